@@ -2,26 +2,43 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"reflect"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/go-historical-data/internal/controller"
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/features"
+	"github.com/go-historical-data/internal/fetcher"
+	"github.com/go-historical-data/internal/ingest"
 	"github.com/go-historical-data/internal/middleware"
-	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/notify"
 	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/internal/resultcache"
 	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/internal/share"
+	"github.com/go-historical-data/internal/storage"
+	"github.com/go-historical-data/pkg/binder"
 	"github.com/go-historical-data/pkg/config"
 	"github.com/go-historical-data/pkg/database"
 	applogger "github.com/go-historical-data/pkg/logger"
+	"github.com/go-historical-data/pkg/server"
 	"github.com/go-historical-data/pkg/tracing"
 	"github.com/go-historical-data/pkg/validator"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
-	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -63,90 +80,800 @@ func main() {
 			Msg("Tracing initialized successfully")
 	}
 
-	// Connect to MySQL
+	// Connect to MySQL, or open the embedded SQLite database, waiting up to
+	// database.connect.max_wait for it to come up; SIGTERM stops the wait
 	dbLogLevel := database.GetLogLevel(cfg.Logging.Level)
-	db, err := database.NewMySQLConnection(cfg.Database, dbLogLevel)
+	connectCtx, stopConnect := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	db, err := database.ConnectWithRetry(connectCtx, cfg.Database, dbLogLevel, func(attempt int, delay time.Duration, err error) {
+		log.Warn().Err(err).Int("attempt", attempt).Dur("retry_in", delay).Msg("Database not reachable yet")
+	})
+	stopConnect()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to database")
 	}
-	log.Info().Msg("Connected to MySQL database")
+	sqlite := database.IsSQLite(db)
+	if sqlite {
+		log.Warn().Str("path", cfg.Database.SQLitePath).Msg("Using the embedded SQLite database; partitions, rollups, tick bars and symbol merges need MySQL")
+	} else {
+		log.Info().Msg("Connected to MySQL database")
+	}
+
+	// Log statements slower than the threshold with their SQL, not their values
+	if cfg.Database.SlowQueryThreshold > 0 {
+		threshold := time.Duration(cfg.Database.SlowQueryThreshold) * time.Millisecond
+		explainer, err := newExplainer(db, cfg.Database.Explain, log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create the query explainer")
+		}
+		if err := database.RegisterSlowQueryLog(db, threshold, func(ctx context.Context, q database.SlowQuery) {
+			middleware.RecordSlowQuery(q.Operation)
+			event := log.Warn().
+				Err(q.Err).
+				Str("operation", q.Operation).
+				Str("table", q.Table).
+				Str("sql", q.SQL).
+				Dur("duration", q.Duration).
+				Int64("rows", q.Rows)
+			if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+				event = event.Str("trace_id", span.TraceID().String())
+			}
+			event.Msg("Slow database query")
+			if explainer != nil {
+				explainer.Explain(ctx, q)
+			}
+		}); err != nil {
+			log.Fatal().Err(err).Msg("Failed to register the slow query log")
+		}
+	}
+
+	if sqlite {
+		// The migrations are MySQL only; SQLite gets the schema of the models
+		if err := repository.CreateSchema(db); err != nil {
+			log.Fatal().Err(err).Msg("Database schema creation failed")
+		}
+	} else {
+		// Verify the schema version, applying pending migrations first if enabled
+		if schemaErr := ensureSchema(cfg.Database, log); schemaErr != nil {
+			log.Fatal().Err(schemaErr).Msg("Database schema check failed")
+		}
+
+		// Warn about indexes the queries rely on but the schema lacks, e.g. after
+		// manual schema changes; the service still starts
+		missing, err := database.MissingIndexes(context.Background(), db, repository.RequiredIndexes)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to check database indexes")
+		}
+		for _, idx := range missing {
+			log.Warn().Str("index", idx.String()).Str("needed_for", idx.Reason).Msg("Database index is missing")
+		}
+	}
 
-	// Auto-migrate database schema
-	if migrateErr := db.AutoMigrate(&model.HistoricalData{}); migrateErr != nil {
-		log.Fatal().Err(migrateErr).Msg("Failed to migrate database schema")
+	// Open and verify connections before the first requests; the idle ones are
+	// pinged periodically by a worker, dropping those the server closed
+	poolWarmer, err := database.NewPoolWarmer(db, cfg.Database.Pool, func(alive, dropped int, err error) {
+		if dropped > 0 {
+			log.Warn().Err(err).Int("alive", alive).Int("dropped", dropped).Msg("Database connections failed the ping")
+		}
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create the connection pool warmer")
+	}
+	if cfg.Database.Pool.WarmConnections > 0 {
+		alive, _, _ := poolWarmer.Warm(context.Background())
+		log.Info().Int("connections", alive).Msg("Database connection pool warmed up")
 	}
-	log.Info().Msg("Database schema migrated successfully")
 
 	// Initialize validator
 	v := validator.New()
 
+	// Database circuit breaker and retry policy shared by all repositories
+	dbResilience := database.NewResilience(cfg.Database.Resilience, func(state string) {
+		middleware.RecordDBBreakerState(state)
+		log.Warn().Str("state", state).Msg("Database circuit breaker state changed")
+	}, middleware.RecordDBRetry)
+
 	// Initialize repository
-	historicalRepo := repository.NewHistoricalRepository(db)
+	writeSession := database.NewWriteSession(cfg.Ingestion.Session)
+	historicalRepo := repository.NewHistoricalRepository(db, dbResilience, writeSession, time.Duration(cfg.Database.CountCacheTTL)*time.Second)
+	stagingRepo := repository.NewStagingRepository(db, dbResilience, writeSession)
+	usageRepo := repository.NewUsageRepository(db, dbResilience)
+	auditRepo := repository.NewAuditRepository(db, dbResilience)
+	uploadJobRepo := repository.NewUploadJobRepository(db, dbResilience)
+	backfillRepo := repository.NewBackfillRepository(db, dbResilience)
+	sourceRepo := repository.NewSourceRepository(db, dbResilience)
+	symbolRepo := repository.NewSymbolRepository(db, dbResilience)
+	corporateActionRepo := repository.NewCorporateActionRepository(db, dbResilience)
+	alertRepo := repository.NewAlertRepository(db, dbResilience)
+	watchlistRepo := repository.NewWatchlistRepository(db, dbResilience)
+	partitionRepo := repository.NewPartitionRepository(db, dbResilience)
+	dataLockRepo := repository.NewDataLockRepository(db, dbResilience)
+	rollupRepo := repository.NewRollupRepository(db, dbResilience)
+	symbolSummaryRepo := repository.NewSymbolSummaryRepository(db, dbResilience)
+	symbolVersionRepo := repository.NewSymbolVersionRepository(db, dbResilience)
+	quoteRepo := repository.NewQuoteRepository(db, dbResilience)
+	tickRepo := repository.NewTickRepository(db, dbResilience)
+	timeSeriesRepo := repository.NewTimeSeriesRepository(db, dbResilience)
+	fundamentalRepo := repository.NewFundamentalRepository(db, dbResilience)
+	earningsRepo := repository.NewEarningsRepository(db, dbResilience)
+	exportJobRepo := repository.NewExportJobRepository(db, dbResilience)
+	pullRepo := repository.NewPullRepository(db, dbResilience)
+	overviewRepo := repository.NewOverviewRepository(db, dbResilience)
+
+	// Initialize market data providers used by backfills
+	providers := fetcher.NewRegistry()
+	if cfg.Fetcher.StooqURL != "" {
+		providers.Register(fetcher.NewStooqProvider(cfg.Fetcher.StooqURL, time.Duration(cfg.Fetcher.Timeout)*time.Second))
+	}
+	if cfg.Fetcher.FileDir != "" {
+		providers.Register(fetcher.NewFileProvider(cfg.Fetcher.FileDir))
+	}
+	if cfg.Fetcher.FREDAPIKey != "" {
+		providers.Register(fetcher.NewFREDProvider(cfg.Fetcher.FREDURL, cfg.Fetcher.FREDAPIKey, time.Duration(cfg.Fetcher.Timeout)*time.Second))
+	}
+	for _, exchange := range cfg.Fetcher.Crypto {
+		pairs := make(map[string]string, len(exchange.Symbols))
+		for _, s := range exchange.Symbols {
+			pairs[s.Symbol] = s.Pair
+		}
+		provider, err := fetcher.NewCryptoProvider(exchange.Exchange, fetcher.CryptoOptions{
+			BaseURL:           exchange.BaseURL,
+			Interval:          exchange.Interval,
+			RequestsPerSecond: exchange.RequestsPerSecond,
+			Pairs:             pairs,
+			Timeout:           time.Duration(cfg.Fetcher.Timeout) * time.Second,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Str("exchange", exchange.Exchange).Msg("Invalid crypto provider")
+		}
+		providers.Register(provider)
+	}
+
+	// Initialize ingestion transforms; custom transforms are registered here too
+	transforms := ingest.NewRegistry(ingest.Builtins()...)
+	for _, t := range cfg.Ingestion.Transforms {
+		if _, err := transforms.Chain(t.Apply); err != nil {
+			log.Fatal().Err(err).Str("source", t.Source).Msg("Invalid ingestion transforms")
+		}
+	}
+
+	// Initialize alert and operator notification channels; email needs an SMTP server
+	notifiers := notify.NewRegistry(notify.NewWebhookNotifier(time.Duration(cfg.Alerts.WebhookTimeout) * time.Second))
+	if cfg.Alerts.SMTP.Host != "" {
+		notifiers.Register(notify.NewEmailNotifier(cfg.Alerts.SMTP))
+	}
+	if cfg.Notifications.SlackWebhookURL != "" {
+		notifiers.Register(notify.NewSlackNotifier(time.Duration(cfg.Notifications.Timeout) * time.Second))
+	}
+
+	// Initialize export artifact storage; local downloads are served by the API itself
+	var exportStore storage.Store
+	var localExports *storage.LocalStore
+	if cfg.Exports.Enabled {
+		switch cfg.Exports.Storage {
+		case "s3":
+			exportStore, err = storage.NewS3Store(cfg.Exports.S3, time.Duration(cfg.Fetcher.Timeout)*time.Second)
+		default:
+			localExports, err = storage.NewLocalStore(cfg.Exports.LocalDir, cfg.Exports.PublicURL, cfg.Exports.SigningKey)
+			exportStore = localExports
+		}
+		if err != nil {
+			log.Fatal().Err(err).Str("storage", cfg.Exports.Storage).Msg("Invalid export storage")
+		}
+	}
+
+	// Share links answer a data query on behalf of the API key that minted them
+	var shareSigner *share.Signer
+	if cfg.Shares.Enabled {
+		if shareSigner, err = share.NewSigner(cfg.Shares.PublicURL, cfg.Shares.SigningKey); err != nil {
+			log.Fatal().Err(err).Msg("Invalid share link configuration")
+		}
+	}
+
+	// Client addresses are read through the trusted load balancers; admin,
+	// upload and API routes may each be restricted to an allowlist
+	trustedProxies, err := middleware.ParsePrefixes(cfg.Security.TrustedProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid trusted proxies")
+	}
+	ipAllowlists := make(map[string][]netip.Prefix, len(cfg.Security.IPAllowlists))
+	for group, cidrs := range cfg.Security.IPAllowlists {
+		if ipAllowlists[group], err = middleware.ParsePrefixes(cidrs); err != nil {
+			log.Fatal().Err(err).Str("group", group).Msg("Invalid IP allowlist")
+		}
+	}
+
+	// Feature flags come from the configuration, overridden by the remote provider
+	flags := features.New(cfg.Features)
+
+	// Initialize domain event bus
+	eventBus := events.NewBus()
+	events.Subscribe(eventBus, func(_ context.Context, e events.UploadCompleted) error {
+		log.Info().
+			Uint64("job_id", e.JobID).
+			Str("status", e.Status).
+			Int("total_rows", e.TotalRows).
+			Int("success_count", e.SuccessCount).
+			Int("failed_count", e.FailedCount).
+			Strs("symbols", e.Symbols).
+			Dur("duration", e.Duration).
+			Msg("CSV upload completed")
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, e events.BackfillCompleted) error {
+		log.Info().
+			Uint64("backfill_id", e.BackfillID).
+			Str("provider", e.Provider).
+			Str("status", e.Status).
+			Int("completed_chunks", e.CompletedChunks).
+			Int("failed_chunks", e.FailedChunks).
+			Int64("rows_ingested", e.RowsIngested).
+			Dur("duration", e.Duration).
+			Msg("Backfill completed")
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, _ events.BarsIngested) error {
+		middleware.InvalidateResponseCache()
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, _ events.SeriesIngested) error {
+		middleware.InvalidateResponseCache()
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, _ events.EarningsChanged) error {
+		middleware.InvalidateResponseCache()
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, _ events.SymbolUpdated) error {
+		middleware.InvalidateResponseCache()
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, _ events.CorporateActionChanged) error {
+		middleware.InvalidateResponseCache()
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, e events.SymbolDelisted) error {
+		log.Info().
+			Str("symbol", e.Symbol).
+			Str("successor", e.Successor).
+			Time("delisted_at", e.DelistedAt).
+			Msg("Symbol delisted")
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, e events.SymbolRenamed) error {
+		log.Info().
+			Str("from", e.From).
+			Str("to", e.To).
+			Int64("moved_rows", e.MovedRows).
+			Int64("discarded_rows", e.DiscardedRows).
+			Msg("Symbol renamed")
+		middleware.InvalidateResponseCache()
+		return nil
+	})
 
 	// Initialize service
-	historicalService := service.NewHistoricalService(historicalRepo)
+	adjustmentService := service.NewAdjustmentService(corporateActionRepo, historicalRepo)
+	currencyConverter := service.NewCurrencyConverter(historicalRepo, symbolRepo)
+	symbolResolver := service.NewSymbolResolver(symbolRepo)
+	historicalService := service.NewHistoricalService(historicalRepo, stagingRepo, uploadJobRepo, sourceRepo, dataLockRepo, earningsRepo, symbolRepo, currencyConverter, adjustmentService, symbolResolver, transforms, eventBus, cfg.Ingestion)
+	usageService := service.NewUsageService(usageRepo, cfg.Usage)
+	auditService := service.NewAuditService(auditRepo)
+	uploadJobService := service.NewUploadJobService(uploadJobRepo)
+	sourceService := service.NewSourceService(sourceRepo)
+	symbolService := service.NewSymbolService(symbolRepo, symbolSummaryRepo, eventBus)
+	corporateActionService := service.NewCorporateActionService(corporateActionRepo, eventBus)
+	fundamentalService := service.NewFundamentalService(fundamentalRepo, historicalRepo, sourceRepo, symbolResolver, cfg.Ingestion)
+	earningsService := service.NewEarningsService(earningsRepo, symbolResolver, eventBus, cfg.Ingestion.BatchSize)
+	// Analytics results are cached under the data versions of their symbols
+	var analyticsCache *resultcache.Cache
+	if cfg.Analytics.Cache.Enabled {
+		var store resultcache.Store
+		if cfg.Analytics.Cache.Backend == "redis" {
+			store = resultcache.NewRedisStore(cfg.Analytics.Cache.Redis)
+		} else {
+			store = resultcache.NewMemoryStore(cfg.Analytics.Cache.MaxEntries, cfg.Analytics.Cache.MaxBytes)
+		}
+		analyticsCache = resultcache.New(store, symbolVersionRepo, time.Duration(cfg.Analytics.Cache.TTL)*time.Second)
+	}
+	analyticsService := service.NewAnalyticsService(historicalRepo, rollupRepo, adjustmentService, currencyConverter, symbolResolver, fundamentalService, analyticsCache, cfg.Analytics)
+	backfillService := service.NewBackfillService(backfillRepo, historicalRepo, sourceRepo, dataLockRepo, providers, transforms, cfg.Ingestion, eventBus, flags, cfg.Backfill)
+	watchlistService := service.NewWatchlistService(watchlistRepo, historicalRepo)
+	alertService := service.NewAlertService(alertRepo, historicalRepo, notifiers)
+	partitionService := service.NewPartitionService(partitionRepo, cfg.Database.Partitioning)
+	dataLockService := service.NewDataLockService(dataLockRepo)
+	rollupService := service.NewRollupService(rollupRepo, historicalRepo, eventBus)
+	symbolSummaryService := service.NewSymbolSummaryService(symbolSummaryRepo, symbolResolver)
+	dataVersionService := service.NewDataVersionService(symbolVersionRepo, symbolResolver)
+	quoteService := service.NewQuoteService(quoteRepo, sourceRepo, symbolResolver, cfg.Ingestion)
+	tickService := service.NewTickService(tickRepo, symbolRepo, symbolResolver, cfg.Ticks)
+	exportService := service.NewExportService(exportJobRepo, historicalRepo, symbolRepo, symbolResolver, exportStore, flags, cfg.Exports)
+	overviewService := service.NewOverviewService(overviewRepo, uploadJobRepo, pullRepo, dbResilience, cfg.Cache.Enabled)
+	notificationService := service.NewNotificationService(symbolSummaryRepo, notifiers, cfg.Notifications)
+	pullService := service.NewPullService(pullRepo, uploadJobRepo, historicalService, cfg.Pull)
+	timeSeriesService := service.NewTimeSeriesService(timeSeriesRepo, sourceRepo, eventBus, cfg.Ingestion)
+	// Data versions are bumped once each write has committed
+	events.Subscribe(eventBus, func(ctx context.Context, e events.BarsIngested) error {
+		return dataVersionService.Bump(ctx, e.Symbols...)
+	})
+	events.Subscribe(eventBus, func(ctx context.Context, e events.SymbolRenamed) error {
+		return dataVersionService.Bump(ctx, e.From, e.To)
+	})
+	events.Subscribe(eventBus, func(ctx context.Context, e events.CorporateActionChanged) error {
+		return dataVersionService.Bump(ctx, e.Symbol)
+	})
+	events.Subscribe(eventBus, func(ctx context.Context, e events.SymbolUpdated) error {
+		return dataVersionService.Bump(ctx, e.Symbol)
+	})
+	events.Subscribe(eventBus, func(_ context.Context, e events.BarsIngested) error {
+		symbolSummaryService.Enqueue(e.Symbols...)
+		return nil
+	})
+	events.Subscribe(eventBus, func(_ context.Context, e events.SymbolRenamed) error {
+		symbolSummaryService.Enqueue(e.From, e.To)
+		return nil
+	})
+	if cfg.Notifications.Enabled {
+		events.Subscribe(eventBus, func(_ context.Context, e events.UploadCompleted) error {
+			notificationService.UploadCompleted(e)
+			return nil
+		})
+		events.Subscribe(eventBus, func(_ context.Context, e events.BackfillCompleted) error {
+			notificationService.BackfillCompleted(e)
+			return nil
+		})
+	}
+	if cfg.Alerts.Enabled {
+		events.Subscribe(eventBus, func(_ context.Context, e events.BarsIngested) error {
+			alertService.Enqueue(e)
+			return nil
+		})
+	}
+	if cfg.Analytics.Rollups.Enabled {
+		events.Subscribe(eventBus, func(_ context.Context, e events.BarsIngested) error {
+			rollupService.Enqueue(e)
+			return nil
+		})
+		events.Subscribe(eventBus, func(ctx context.Context, e events.SymbolRenamed) error {
+			_, err := rollupService.Rebuild(ctx, &request.RebuildRollupsRequest{Symbols: []string{e.From, e.To}})
+			return err
+		})
+		events.Subscribe(eventBus, func(_ context.Context, _ events.RollupsRefreshed) error {
+			middleware.InvalidateResponseCache()
+			return nil
+		})
+	}
+
+	// Background workers share a context cancelled on shutdown. Under prefork,
+	// the workers that only one instance may run stay in the master process;
+	// those fed by the requests a process serves run in every child.
+	singleton := !fiber.IsChild()
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		usageService.Run(workerCtx)
+	}()
+	if cfg.Database.Pool.PingInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			poolWarmer.Run(workerCtx)
+		}()
+	}
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		flags.Run(workerCtx)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		symbolSummaryService.Run(workerCtx)
+	}()
+	if cfg.Backfill.Enabled && singleton {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			backfillService.Run(workerCtx)
+		}()
+	}
+	if cfg.Exports.Enabled && singleton {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			exportService.Run(workerCtx)
+		}()
+	}
+	if cfg.Pull.Enabled && singleton {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			pullService.Run(workerCtx)
+		}()
+	}
+	if cfg.Notifications.Enabled {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			notificationService.Run(workerCtx)
+		}()
+	}
+	if cfg.Alerts.Enabled {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			alertService.Run(workerCtx)
+		}()
+	}
+	if cfg.Database.Partitioning.Enabled && singleton && !sqlite {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			partitionService.Run(workerCtx)
+		}()
+	}
+	if cfg.Analytics.Rollups.Enabled {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			rollupService.Run(workerCtx)
+		}()
+	}
 
 	// Initialize controllers
-	healthController := controller.NewHealthController()
-	historicalController := controller.NewHistoricalController(historicalService, v)
+	healthController := controller.NewHealthController(db, dbResilience)
+	historicalController := controller.NewHistoricalController(historicalService, usageService, v, controller.NewHistoricalMapper(controller.APIVersion1))
+	historicalControllerV2 := controller.NewHistoricalController(historicalService, usageService, v, controller.NewHistoricalMapper(controller.APIVersion2))
+	usageController := controller.NewUsageController(usageService, v)
+	auditController := controller.NewAuditController(auditService, v)
+	uploadJobController := controller.NewUploadJobController(uploadJobService, v)
+	backfillController := controller.NewBackfillController(backfillService, v)
+	exportController := controller.NewExportController(exportService, localExports, v)
+	shareController := controller.NewShareController(shareSigner, v, cfg.Shares)
+	pullController := controller.NewPullController(pullService, v)
+	overviewController := controller.NewOverviewController(overviewService, v)
+	featureController := controller.NewFeatureController(flags)
+	sourceController := controller.NewSourceController(sourceService, v)
+	quoteController := controller.NewQuoteController(quoteService, usageService, v)
+	tickController := controller.NewTickController(tickService, v)
+	timeSeriesController := controller.NewTimeSeriesController(timeSeriesService, usageService, v)
+	fundamentalController := controller.NewFundamentalController(fundamentalService, usageService, v)
+	earningsController := controller.NewEarningsController(earningsService, v)
+	symbolController := controller.NewSymbolController(symbolService, v)
+	corporateActionController := controller.NewCorporateActionController(corporateActionService, v)
+	analyticsController := controller.NewAnalyticsController(analyticsService, v)
+	alertController := controller.NewAlertController(alertService, v)
+	watchlistController := controller.NewWatchlistController(watchlistService, v)
+	partitionController := controller.NewPartitionController(partitionService)
+	dataLockController := controller.NewDataLockController(dataLockService, v)
+	rollupController := controller.NewRollupController(rollupService, v)
+	catalogController := controller.NewCatalogController(symbolSummaryService, v)
+
+	// Time fields of every fiber parser accept dates, epoch seconds and relative times
+	binder.RegisterTimeParser()
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
-		ErrorHandler:          middleware.ErrorHandler(),
+		ErrorHandler:          middleware.ErrorHandler(cfg.API.Errors),
 		DisableStartupMessage: true,
 		AppName:               cfg.App.Name,
-		ReadTimeout:           time.Duration(cfg.API.RequestTimeout) * time.Second,
-		BodyLimit:             (2 * 1024) * 1024 * 1024, // 2GB - Add this line
+		ReadTimeout:           time.Duration(cfg.Server.ReadTimeout) * time.Second,
+		WriteTimeout:          time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		IdleTimeout:           time.Duration(cfg.Server.IdleTimeout) * time.Second,
+		DisableKeepalive:      cfg.Server.DisableKeepalive,
+		Concurrency:           cfg.Server.Concurrency,
+		Prefork:               cfg.Server.Prefork,
+		BodyLimit:             int(cfg.API.BodyLimits.Max()),
+		// Stream large bodies so per-route limits apply before the body is buffered
+		// and multipart uploads spill to disk instead of memory
+		StreamRequestBody: true,
+		// X-Forwarded-Proto and X-Forwarded-Host are only believed from the
+		// trusted proxies. The client address is resolved by middleware.RealIP
+		// rather than ProxyHeader, which would take the leftmost, client-written
+		// X-Forwarded-For entry.
+		EnableTrustedProxyCheck: len(cfg.Security.TrustedProxies) > 0,
+		TrustedProxies:          cfg.Security.TrustedProxies,
 	})
 
 	// Global middleware
 	app.Use(middleware.Recover())
 	app.Use(middleware.RequestID())
+	// Real client addresses for the rate limiter, logs, traces and allowlists
+	app.Use(middleware.RealIP(trustedProxies))
 
 	// Tracing middleware (must be before logger to capture trace context)
 	if cfg.Tracing.Enabled {
 		app.Use(middleware.Tracing())
 	}
 
+	// After tracing, which replaces the request context
+	app.Use(middleware.Language())
 	app.Use(middleware.Logger(log))
-	app.Use(middleware.CORS(cfg.CORS))
-	app.Use(compress.New(compress.Config{
-		Level: compress.LevelBestSpeed,
-	}))
+	if cfg.Security.HeadersEnabled {
+		app.Use(middleware.SecurityHeaders(cfg.Security))
+	}
+	// CORS and rate limiting are swapped on configuration reload
+	corsMiddleware := middleware.NewReloadable(middleware.CORS(cfg.CORS))
+	app.Use(corsMiddleware.Handler())
+	if cfg.API.Compression.Enabled {
+		app.Use(middleware.Compress(cfg.API.Compression))
+	}
+	// After compression, so response bodies are logged before they are compressed
+	if cfg.Logging.Payloads.Enabled {
+		app.Use(middleware.PayloadLogger(cfg.Logging.Payloads, cfg.Auth))
+	}
+
+	if cfg.API.StrictQuery {
+		app.Use(middleware.StrictQuery())
+	}
 
 	// Rate limiting
-	if cfg.API.RateLimit > 0 {
-		app.Use(middleware.RateLimiter(cfg.API.RateLimit))
+	rateLimiter := middleware.NewReloadable(rateLimit(cfg.API.RateLimit))
+	app.Use(rateLimiter.Handler())
+
+	// Upload routes accept large bodies and run long; every other route gets
+	// the default body limit and timeout
+	uploadRoutes := []string{
+		fiber.MethodPost + " /api/v1/data",
+		fiber.MethodPost + " /api/v2/data",
+		fiber.MethodPost + " /api/v1/quotes",
+		fiber.MethodPost + " /api/v1/ticks",
+		fiber.MethodPost + " /api/v1/series",
+		fiber.MethodPost + " /api/v1/fundamentals",
 	}
+	uploadBodyLimits := make(map[string]int64, len(uploadRoutes))
+	uploadTimeouts := make(map[string]time.Duration, len(uploadRoutes))
+	for _, route := range uploadRoutes {
+		uploadBodyLimits[route] = cfg.API.BodyLimits.Upload
+		uploadTimeouts[route] = time.Duration(cfg.API.Timeouts.Upload) * time.Second
+	}
+	// Synthetic data is ingested like an upload, without a body
+	uploadTimeouts[fiber.MethodPost+" /admin/data/generate"] = time.Duration(cfg.API.Timeouts.Upload) * time.Second
+	// Before the body limit, so pushes from outside the allowlist are not read
+	app.Use(middleware.IPAllowlist(ipAllowlists["upload"], uploadRoutes))
+	app.Use(middleware.BodyLimit(cfg.API.BodyLimits.Default, uploadBodyLimits))
+	app.Use(middleware.Timeout(time.Duration(cfg.API.Timeouts.Default)*time.Second, uploadTimeouts))
 
 	// Health check routes (before metrics middleware to avoid tracking internal endpoints)
 	app.Get("/health", healthController.Check)
+	app.Get("/health/ready", healthController.Ready)
 
 	// Prometheus metrics endpoint (must be before metrics middleware)
 	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
 
 	// Prometheus metrics middleware (apply after internal endpoints)
-	app.Use(middleware.PrometheusMiddleware())
+	app.Use(middleware.PrometheusMiddleware(cfg.Metrics))
+
+	// Artifacts of local export storage; signed links stand in for API keys
+	if localExports != nil {
+		app.Get(storage.LocalDownloadPath+"*", exportController.Download)
+	}
+	// Data queries of share links; the signed link stands in for the API key that minted it
+	if cfg.Shares.Enabled {
+		app.Get(share.DataPath, middleware.SharedLink(cfg.Auth, shareSigner), middleware.Metering(usageService),
+			cached(cfg.Cache, "data_list"), historicalController.GetData)
+	}
+
+	// Symbol-scoped reads carry the data version of their symbols and an ETag
+	versioned := middleware.DataVersion(dataVersionService.Versions)
 
 	// API routes
-	apiV1 := app.Group("/api/v1")
+	api := app.Group("/api", middleware.IPAllowlist(ipAllowlists["api"], nil), middleware.APIKeyAuth(cfg.Auth), middleware.SignedIngestion(cfg.Ingestion.Signing, uploadRoutes),
+		middleware.Metering(usageService), middleware.Audit(auditService))
+	apiV1 := api.Group("/v1")
+	if cfg.API.Versioning.V1Deprecated {
+		deprecatedAt, _ := time.Parse("2006-01-02", cfg.API.Versioning.V1DeprecationDate)
+		sunset, _ := time.Parse("2006-01-02", cfg.API.Versioning.V1SunsetDate)
+		apiV1.Use(middleware.Deprecation(deprecatedAt, sunset, "/api/v1", "/api/v2"))
+	}
 	{
 		// Historical data endpoints
 		apiV1.Post("/data", historicalController.UploadCSV)
-		apiV1.Get("/data", historicalController.GetData)
-		apiV1.Get("/data/:id", historicalController.GetDataByID)
+		apiV1.Get("/data", versioned, cached(cfg.Cache, "data_list"), historicalController.GetData)
+		apiV1.Get("/data/:id", cached(cfg.Cache, "data_by_id"), historicalController.GetDataByID)
+		apiV1.Post("/data/lookup", historicalController.LookupData)
+		apiV1.Post("/data/asof", historicalController.AsOfBatch)
+		apiV1.Get("/data/:symbol/asof", versioned, cached(cfg.Cache, "data_asof"), historicalController.AsOf)
+
+		// Bid/ask quote endpoints
+		apiV1.Post("/quotes", quoteController.UploadQuotes)
+		apiV1.Get("/quotes", quoteController.GetQuotes)
+
+		// Generic time series endpoints
+		apiV1.Post("/series", timeSeriesController.UploadSeries)
+		apiV1.Get("/series", cached(cfg.Cache, "series"), timeSeriesController.GetSeries)
+
+		// Fundamentals endpoints
+		apiV1.Post("/fundamentals", fundamentalController.UploadFundamentals)
+
+		// Earnings calendar endpoints
+		apiV1.Get("/earnings", earningsController.GetEarnings)
+		apiV1.Post("/earnings", middleware.RequireRole(middleware.RoleAdmin), earningsController.UploadEarnings)
+
+		// Tick endpoints
+		if cfg.Ticks.Enabled {
+			apiV1.Post("/ticks", tickController.IngestTicks)
+			apiV1.Get("/ticks", tickController.GetTicks)
+			apiV1.Get("/data/:symbol/bars", cachedIntervals(cfg.Cache, "tick_bars", cfg.Ticks.CachedIntervals), tickController.GetBars)
+		}
+
+		// Analytics endpoints
+		apiV1.Get("/compare", versioned, cached(cfg.Cache, "compare"), analyticsController.Compare)
+		apiV1.Get("/analytics/correlation", versioned, cached(cfg.Cache, "analytics"), analyticsController.Correlation)
+		apiV1.Get("/data/:symbol/returns", versioned, cached(cfg.Cache, "analytics"), analyticsController.Returns)
+		apiV1.Get("/data/:symbol/returns/distribution", versioned, cached(cfg.Cache, "analytics"), analyticsController.Distribution)
+		apiV1.Get("/data/:symbol/chart", versioned, cached(cfg.Cache, "analytics"), analyticsController.Chart)
+		apiV1.Get("/data/:symbol/aggregates", versioned, cached(cfg.Cache, "analytics"), analyticsController.Aggregates)
+		apiV1.Post("/backtests", analyticsController.Backtest)
+
+		// Cross-sectional endpoints, after the /data/:symbol/... routes so a
+		// symbol named DATE keeps reaching them
+		apiV1.Get("/data/date/:date", cached(cfg.Cache, "data_list"), historicalController.GetDataOnDate)
+		apiV1.Get("/data/date/:date/movers", cached(cfg.Cache, "data_list"), historicalController.GetMovers)
+
+		// Upload job status endpoints
+		apiV1.Get("/uploads", uploadJobController.GetUploadJobs)
+		apiV1.Get("/uploads/:id", uploadJobController.GetUploadJob)
+
+		// Symbol metadata endpoints
+		apiV1.Get("/symbols", symbolController.GetSymbols)
+		apiV1.Get("/symbols/:symbol", symbolController.GetSymbol)
+		apiV1.Get("/symbols/:symbol/summary", versioned, catalogController.GetSummary)
+		apiV1.Get("/symbols/:symbol/fundamentals", fundamentalController.GetFundamentals)
+		apiV1.Put("/symbols/:symbol", middleware.RequireRole(middleware.RoleAdmin), symbolController.UpsertSymbol)
+		apiV1.Get("/symbols/:symbol/actions", corporateActionController.GetActions)
+		apiV1.Post("/symbols/:symbol/actions", middleware.RequireRole(middleware.RoleAdmin), corporateActionController.CreateAction)
+		apiV1.Delete("/symbols/:symbol/actions/:id", middleware.RequireRole(middleware.RoleAdmin), corporateActionController.DeleteAction)
+
+		// Catalog endpoints
+		apiV1.Get("/catalog", catalogController.GetCatalog)
+
+		// Data provenance endpoints
+		apiV1.Get("/sources", sourceController.GetSources)
+		apiV1.Get("/sources/:id", sourceController.GetSource)
+
+		// Watchlist endpoints
+		apiV1.Post("/watchlists", watchlistController.CreateWatchlist)
+		apiV1.Get("/watchlists", watchlistController.GetWatchlists)
+		apiV1.Get("/watchlists/:id", watchlistController.GetWatchlist)
+		apiV1.Put("/watchlists/:id", watchlistController.UpdateWatchlist)
+		apiV1.Delete("/watchlists/:id", watchlistController.DeleteWatchlist)
+		apiV1.Get("/watchlists/:id/data", watchlistController.GetWatchlistData)
+
+		// Alert rule endpoints
+		if cfg.Alerts.Enabled {
+			apiV1.Post("/alerts", alertController.CreateRule)
+			apiV1.Get("/alerts", alertController.GetRules)
+			apiV1.Get("/alerts/:id", alertController.GetRule)
+			apiV1.Put("/alerts/:id", alertController.UpdateRule)
+			apiV1.Delete("/alerts/:id", alertController.DeleteRule)
+			apiV1.Get("/alerts/:id/events", alertController.GetEvents)
+		}
+
+		// Bulk export endpoints
+		if cfg.Exports.Enabled {
+			apiV1.Post("/exports", exportController.CreateExport)
+			apiV1.Get("/exports", exportController.GetExports)
+			apiV1.Get("/exports/:id", exportController.GetExport)
+		}
+
+		// Share link endpoints
+		if cfg.Shares.Enabled {
+			apiV1.Post("/shares", shareController.CreateShare)
+		}
+
+		// Usage metering endpoints
+		apiV1.Get("/usage", usageController.GetUsage)
+
+		// Backfill orchestration endpoints (admin only)
+		if cfg.Backfill.Enabled {
+			adminOnly := middleware.RequireRole(middleware.RoleAdmin)
+			apiV1.Post("/backfills", adminOnly, backfillController.CreateBackfill)
+			apiV1.Get("/backfills", adminOnly, backfillController.GetBackfills)
+			apiV1.Get("/backfills/:id", adminOnly, backfillController.GetBackfill)
+			apiV1.Get("/backfills/:id/chunks", adminOnly, backfillController.GetBackfillChunks)
+		}
+	}
+
+	apiV2 := api.Group("/v2")
+	{
+		// Historical data endpoints
+		apiV2.Post("/data", historicalControllerV2.UploadCSV)
+		apiV2.Get("/data", versioned, cached(cfg.Cache, "data_list"), historicalControllerV2.GetData)
+		apiV2.Get("/data/:id", cached(cfg.Cache, "data_by_id"), historicalControllerV2.GetDataByID)
+		apiV2.Get("/data/date/:date", cached(cfg.Cache, "data_list"), historicalControllerV2.GetDataOnDate)
+	}
+
+	// Admin routes
+	admin := app.Group("/admin", middleware.IPAllowlist(ipAllowlists["admin"], nil), middleware.APIKeyAuth(cfg.Auth),
+		middleware.RequireRole(middleware.RoleAdmin), middleware.Audit(auditService))
+	{
+		admin.Get("/overview", overviewController.GetOverview)
+		admin.Get("/overview/uploads", overviewController.GetRecentUploads)
+		admin.Get("/overview/queues", overviewController.GetQueues)
+		admin.Get("/overview/freshness", overviewController.GetFreshness)
+		admin.Get("/overview/errors", overviewController.GetErrorRates)
+		admin.Get("/overview/cache", overviewController.GetCacheStats)
+		admin.Get("/overview/database", overviewController.GetDatabaseStats)
+		admin.Get("/features", featureController.GetFeatures)
+		admin.Get("/audit-logs", auditController.GetAuditLogs)
+		admin.Get("/partitions", partitionController.GetPartitions)
+		admin.Post("/partitions/maintain", partitionController.MaintainPartitions)
+		admin.Post("/symbols/rename", symbolController.RenameSymbol)
+		admin.Post("/data/generate", historicalController.GenerateData)
+		admin.Get("/locks", dataLockController.GetLocks)
+		admin.Post("/locks", dataLockController.CreateLock)
+		admin.Delete("/locks/:id", dataLockController.DeleteLock)
+		if cfg.Analytics.Rollups.Enabled {
+			admin.Post("/rollups/rebuild", rollupController.RebuildRollups)
+		}
+		if cfg.Pull.Enabled {
+			admin.Get("/pull-sources", pullController.GetSources)
+			admin.Get("/pull-sources/:name/files", pullController.GetFiles)
+			admin.Post("/pull-sources/:name/poll", pullController.Poll)
+		}
+	}
+
+	// With HTTP/2, net/http serves the TLS connections and hands every
+	// request to the app
+	var tlsCfg *tls.Config
+	var h2Server *http.Server
+	if cfg.TLS.Enabled {
+		tlsCfg, err = server.NewTLSConfig(cfg.TLS)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to configure TLS")
+		}
+		if cfg.Server.HTTP2.Enabled {
+			h2Server, err = server.NewHTTP2Server(app, cfg.Server, tlsCfg, cfg.API.BodyLimits.Max())
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to configure HTTP/2")
+			}
+			tlsCfg = h2Server.TLSConfig
+		}
 	}
 
 	// Start server in a goroutine
 	go func() {
 		addr := fmt.Sprintf(":%d", cfg.App.Port)
-		log.Info().
-			Str("address", addr).
-			Str("env", cfg.App.Env).
-			Msg("Server starting")
+		if !fiber.IsChild() {
+			log.Info().
+				Str("address", addr).
+				Str("env", cfg.App.Env).
+				Bool("tls", cfg.TLS.Enabled).
+				Bool("http2", h2Server != nil).
+				Bool("prefork", cfg.Server.Prefork).
+				Msg("Server starting")
+		}
+
+		if tlsCfg == nil {
+			if listenErr := app.Listen(addr); listenErr != nil {
+				log.Fatal().Err(listenErr).Msg("Failed to start server")
+			}
+			return
+		}
 
-		if listenErr := app.Listen(addr); listenErr != nil {
-			log.Fatal().Err(listenErr).Msg("Failed to start server")
+		ln, listenErr := tls.Listen("tcp", addr, tlsCfg)
+		if listenErr != nil {
+			log.Fatal().Err(listenErr).Msg("Failed to start TLS listener")
+		}
+		if h2Server != nil {
+			if serveErr := h2Server.Serve(ln); !errors.Is(serveErr, http.ErrServerClosed) {
+				log.Fatal().Err(serveErr).Msg("Failed to start server")
+			}
+			return
+		}
+		if serveErr := app.Listener(ln); serveErr != nil {
+			log.Fatal().Err(serveErr).Msg("Failed to start server")
+		}
+	}()
+
+	// Reload the settings that are safe to change at runtime on SIGHUP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		current := cfg
+		for range hup {
+			current = reloadConfig(current, flags, corsMiddleware, rateLimiter, log)
 		}
 	}()
 
@@ -161,10 +888,18 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.API.ShutdownTimeout)*time.Second)
 	defer cancel()
 
-	if shutdownErr := app.ShutdownWithContext(ctx); shutdownErr != nil {
+	shutdown := app.ShutdownWithContext
+	if h2Server != nil {
+		shutdown = h2Server.Shutdown
+	}
+	if shutdownErr := shutdown(ctx); shutdownErr != nil {
 		log.Error().Err(shutdownErr).Msg("Server forced to shutdown")
 	}
 
+	// Stop background workers and wait for their final flush
+	stopWorkers()
+	workers.Wait()
+
 	// Close database connections
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -186,3 +921,157 @@ func main() {
 
 	log.Info().Msg("Server exited gracefully")
 }
+
+// reloadConfig reads the configuration file again and applies the settings
+// that are safe to change at runtime: the log level, the rate limit, CORS and
+// feature flags. Changes to other sections, the database connection among
+// them, are logged and wait for a restart. It returns the configuration in effect.
+func reloadConfig(current *config.Config, flags *features.Flags, cors, rateLimiter *middleware.Reloadable, log *applogger.Logger) *config.Config {
+	next, err := config.Reload()
+	middleware.RecordConfigReload(err)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reload configuration, keeping the current one")
+		return current
+	}
+
+	applied := *current
+	var changed []string
+	if next.Logging.Level != current.Logging.Level {
+		applogger.SetLevel(next.Logging.Level)
+		applied.Logging.Level = next.Logging.Level
+		changed = append(changed, "logging.level")
+	}
+	if next.API.RateLimit != current.API.RateLimit {
+		rateLimiter.Swap(rateLimit(next.API.RateLimit))
+		applied.API.RateLimit = next.API.RateLimit
+		changed = append(changed, "api.rate_limit")
+	}
+	if !reflect.DeepEqual(next.CORS, current.CORS) {
+		cors.Swap(middleware.CORS(next.CORS))
+		applied.CORS = next.CORS
+		changed = append(changed, "cors")
+	}
+	if names := flags.SetStatic(next.Features.Flags); len(names) > 0 {
+		log.Info().Strs("flags", names).Msg("Feature flags changed")
+		changed = append(changed, "features.flags")
+	}
+	applied.Features.Flags = next.Features.Flags
+
+	log.Info().Strs("changed", changed).Msg("Configuration reloaded")
+	if pending := changedSections(&applied, next); len(pending) > 0 {
+		log.Warn().Strs("sections", pending).Msg("Configuration changes need a restart to apply")
+	}
+	return &applied
+}
+
+// changedSections returns the top-level configuration sections that differ
+func changedSections(a, b *config.Config) []string {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	var sections []string
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			sections = append(sections, va.Type().Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	return sections
+}
+
+// rateLimit returns the rate limiter of a limit per minute, or a pass-through
+// handler when the limit is 0
+func rateLimit(maxRequests int) fiber.Handler {
+	if maxRequests <= 0 {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	return middleware.RateLimiter(maxRequests)
+}
+
+// cached returns the response cache middleware for the named route, or a
+// pass-through handler when caching is disabled
+func cached(cfg config.CacheConfig, route string) fiber.Handler {
+	if !cfg.Enabled {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	return middleware.ResponseCache(route, cfg.TTL(route), cfg.MaxBytes)
+}
+
+// cachedIntervals caches the responses of a bars route only for the listed
+// intervals, so popular timeframes are served from memory without every
+// requested interval filling the cache
+func cachedIntervals(cfg config.CacheConfig, route string, intervals []string) fiber.Handler {
+	store := cached(cfg, route)
+	popular := make(map[time.Duration]bool, len(intervals))
+	for _, interval := range intervals {
+		if d, err := time.ParseDuration(interval); err == nil {
+			popular[d] = true
+		}
+	}
+	return func(c *fiber.Ctx) error {
+		// 60s and 1m share the cached interval but not the cache key
+		interval, err := time.ParseDuration(c.Query("interval"))
+		if err != nil || !popular[interval] {
+			return c.Next()
+		}
+		return store(c)
+	}
+}
+
+// ensureSchema refuses to start against a dirty or out-of-date schema. Pending
+// migrations are applied first when database.auto_migrate is enabled.
+func ensureSchema(cfg config.DatabaseConfig, log *applogger.Logger) error {
+	migrator, err := database.NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if cfg.AutoMigrate {
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("schema version %d is dirty; fix the failed migration and run `migrate force`", status.Current)
+	}
+	if status.Pending() {
+		return fmt.Errorf("schema version %d is behind latest %d; run `migrate up`", status.Current, status.Latest)
+	}
+
+	log.Info().Uint("schema_version", status.Current).Msg("Database schema is up to date")
+	return nil
+}
+
+// newExplainer creates the explainer of slow selects, nil unless
+// database.explain is enabled. Plans are logged at debug level, or as a
+// warning when they read a table in full.
+func newExplainer(db *gorm.DB, cfg config.ExplainConfig, log *applogger.Logger) (*database.Explainer, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	return database.NewExplainer(db, cfg.SampleRate, func(ctx context.Context, q database.SlowQuery, plan database.Plan, err error) {
+		if err != nil {
+			log.Warn().Err(err).Str("table", q.Table).Str("sql", q.SQL).Msg("Failed to explain slow query")
+			return
+		}
+		event := log.Debug()
+		for _, table := range plan.FullScans {
+			middleware.RecordFullScan(table)
+		}
+		if len(plan.FullScans) > 0 {
+			event = log.Warn().Strs("full_scans", plan.FullScans)
+		}
+		event = event.
+			Str("table", q.Table).
+			Str("sql", q.SQL).
+			Dur("duration", q.Duration).
+			Interface("plan", plan.Rows)
+		if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+			event = event.Str("trace_id", span.TraceID().String())
+		}
+		event.Msg("Slow query plan")
+	})
+}
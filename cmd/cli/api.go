@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/export"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/config"
+	apiresponse "github.com/go-historical-data/pkg/response"
+)
+
+// apiBackend runs operations against the HTTP API
+type apiBackend struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// envelope is the standard API response wrapper
+type envelope struct {
+	Success bool                     `json:"success"`
+	Data    json.RawMessage          `json:"data"`
+	Error   *apiresponse.ErrorDetail `json:"error"`
+}
+
+// newAPIBackend creates an API client; timeouts are governed by the command context
+func newAPIBackend(baseURL, apiKey string) *apiBackend {
+	return &apiBackend{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{},
+	}
+}
+
+// Ingest streams a CSV file to POST /api/v1/data. Overrides require an admin key.
+func (b *apiBackend) Ingest(ctx context.Context, path string, overrides config.IngestionConfig) (*response.CSVUploadResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Stream the multipart body so large files are never held in memory
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filepath.Base(path)))
+		header.Set("Content-Type", export.ContentTypeCSV)
+		part, err := mw.CreatePart(header)
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	params := url.Values{}
+	if overrides.CaptureAttributes {
+		params.Set("capture_attributes", "true")
+	}
+	if overrides.Staged {
+		params.Set("staged", "true")
+	}
+	if overrides.BatchSize > 0 {
+		params.Set("batch_size", strconv.Itoa(overrides.BatchSize))
+	}
+	if overrides.MaxParallelBatches > 0 {
+		params.Set("max_parallel_batches", strconv.Itoa(overrides.MaxParallelBatches))
+	}
+	if overrides.MaxFileSize > 0 {
+		params.Set("max_file_size", strconv.FormatInt(overrides.MaxFileSize, 10))
+	}
+	if overrides.MaxErrors > 0 {
+		params.Set("max_errors", strconv.Itoa(overrides.MaxErrors))
+	}
+	if overrides.MaxErrorRate > 0 {
+		params.Set("max_error_rate", strconv.FormatFloat(overrides.MaxErrorRate, 'f', -1, 64))
+	}
+	if overrides.UnknownSymbols != "" {
+		params.Set("unknown_symbols", overrides.UnknownSymbols)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/api/v1/data?"+params.Encode(), pr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var result response.CSVUploadResponse
+	if err := b.do(req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Generate ingests synthetic bars via POST /admin/data/generate, which needs
+// an admin key
+func (b *apiBackend) Generate(ctx context.Context, r *request.GenerateDataRequest) (*response.CSVUploadResponse, error) {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/admin/data/generate", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var result response.CSVUploadResponse
+	if err := b.do(req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Query retrieves a page of historical data via GET /api/v1/data
+func (b *apiBackend) Query(ctx context.Context, r *request.GetDataRequest) (*response.PaginatedHistoricalDataResponse, error) {
+	params := url.Values{}
+	if r.Symbol != "" {
+		params.Set("symbol", r.Symbol)
+	}
+	if !r.StartDate.IsZero() {
+		params.Set("start_date", r.StartDate.Format(time.RFC3339))
+	}
+	if !r.EndDate.IsZero() {
+		params.Set("end_date", r.EndDate.Format(time.RFC3339))
+	}
+	if r.Page > 0 {
+		params.Set("page", strconv.Itoa(r.Page))
+	}
+	if r.Limit > 0 {
+		params.Set("limit", strconv.Itoa(r.Limit))
+	}
+	if r.Fields != "" {
+		params.Set("fields", r.Fields)
+	}
+	if r.Sort != "" {
+		params.Set("sort", r.Sort)
+	}
+	if r.SortDir != "" {
+		params.Set("sort_dir", r.SortDir)
+	}
+	if r.Source != "" {
+		params.Set("source", r.Source)
+	}
+	if r.Tag != "" {
+		params.Set("tag", r.Tag)
+	}
+	if r.ConvertTo != "" {
+		params.Set("convert_to", r.ConvertTo)
+	}
+	if r.Adjustment != "" {
+		params.Set("adjustment", r.Adjustment)
+	}
+
+	var result response.PaginatedHistoricalDataResponse
+	if err := b.get(ctx, "/api/v1/data?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+	result.Fields, _ = r.GetFields()
+	return &result, nil
+}
+
+// UploadJob retrieves an upload job via GET /api/v1/uploads/:id
+func (b *apiBackend) UploadJob(ctx context.Context, id uint64) (*model.UploadJob, error) {
+	var job model.UploadJob
+	if err := b.get(ctx, fmt.Sprintf("/api/v1/uploads/%d", id), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UploadJobs lists upload jobs via GET /api/v1/uploads
+func (b *apiBackend) UploadJobs(ctx context.Context, r *request.GetUploadJobsRequest) (*response.PaginatedUploadJobResponse, error) {
+	params := url.Values{}
+	if r.Status != "" {
+		params.Set("status", r.Status)
+	}
+	if r.Page > 0 {
+		params.Set("page", strconv.Itoa(r.Page))
+	}
+	if r.Limit > 0 {
+		params.Set("limit", strconv.Itoa(r.Limit))
+	}
+
+	var result response.PaginatedUploadJobResponse
+	if err := b.get(ctx, "/api/v1/uploads?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Close releases idle connections
+func (b *apiBackend) Close() error {
+	b.client.CloseIdleConnections()
+	return nil
+}
+
+// get performs a GET request and decodes the response data into out
+func (b *apiBackend) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	return b.do(req, out)
+}
+
+// do sends the request and unwraps the response envelope into out
+func (b *apiBackend) do(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	if b.apiKey != "" {
+		req.Header.Set("X-API-Key", b.apiKey)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("unexpected response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if !env.Success || resp.StatusCode >= http.StatusBadRequest {
+		if env.Error != nil {
+			if env.Error.Details != nil {
+				details, _ := json.Marshal(env.Error.Details)
+				return fmt.Errorf("%s: %s: %s (HTTP %d)", env.Error.Code, env.Error.Message, details, resp.StatusCode)
+			}
+			return fmt.Errorf("%s: %s (HTTP %d)", env.Error.Code, env.Error.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("request failed (HTTP %d)", resp.StatusCode)
+	}
+
+	return json.Unmarshal(env.Data, out)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/config"
+)
+
+// backend executes CLI operations either via the HTTP API or directly
+// against the database
+type backend interface {
+	Ingest(ctx context.Context, path string, overrides config.IngestionConfig) (*response.CSVUploadResponse, error)
+	Generate(ctx context.Context, req *request.GenerateDataRequest) (*response.CSVUploadResponse, error)
+	Query(ctx context.Context, req *request.GetDataRequest) (*response.PaginatedHistoricalDataResponse, error)
+	UploadJob(ctx context.Context, id uint64) (*model.UploadJob, error)
+	UploadJobs(ctx context.Context, req *request.GetUploadJobsRequest) (*response.PaginatedUploadJobResponse, error)
+	Close() error
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/export"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// exportPageSize is the page size used when walking a range for export
+const exportPageSize = 1000
+
+// newIngestCommand builds `cli ingest FILE...`
+func newIngestCommand(opts *options) *cobra.Command {
+	var overrides config.IngestionConfig
+
+	cmd := &cobra.Command{
+		Use:   "ingest FILE...",
+		Short: "Upload one or more CSV files",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd, opts, func(ctx context.Context, b backend) error {
+				return ingestFiles(ctx, cmd.OutOrStdout(), b, args, overrides)
+			})
+		},
+	}
+
+	registerIngestionFlags(cmd, &overrides)
+	return cmd
+}
+
+// newBackfillCommand builds `cli backfill --symbols ... --dir ...`
+func newBackfillCommand(opts *options) *cobra.Command {
+	var symbols []string
+	var symbolsFile, dir, pattern string
+	var overrides config.IngestionConfig
+
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Ingest the history files of a list of symbols",
+		Long: `Ingest one CSV file per symbol from a directory. The file name is derived
+from --pattern, where {symbol} is replaced by the symbol (default "{symbol}.csv").
+Symbols without a file are reported and skipped.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if symbolsFile != "" {
+				fromFile, err := readSymbols(symbolsFile)
+				if err != nil {
+					return err
+				}
+				symbols = append(symbols, fromFile...)
+			}
+			if len(symbols) == 0 {
+				return fmt.Errorf("no symbols given; use --symbols or --symbols-file")
+			}
+
+			var files []string
+			for _, symbol := range symbols {
+				symbol = strings.ToUpper(strings.TrimSpace(symbol))
+				if symbol == "" {
+					continue
+				}
+				path := filepath.Join(dir, strings.ReplaceAll(pattern, "{symbol}", symbol))
+				if _, err := os.Stat(path); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: skipped: %v\n", symbol, err)
+					continue
+				}
+				files = append(files, path)
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no files found for the given symbols")
+			}
+
+			return run(cmd, opts, func(ctx context.Context, b backend) error {
+				return ingestFiles(ctx, cmd.OutOrStdout(), b, files, overrides)
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&symbols, "symbols", nil, "comma-separated symbols")
+	cmd.Flags().StringVar(&symbolsFile, "symbols-file", "", "file with one symbol per line")
+	cmd.Flags().StringVar(&dir, "dir", ".", "directory containing the symbol files")
+	cmd.Flags().StringVar(&pattern, "pattern", "{symbol}.csv", "file name pattern")
+	registerIngestionFlags(cmd, &overrides)
+
+	return cmd
+}
+
+// newGenerateCommand builds `cli generate`
+func newGenerateCommand(opts *options) *cobra.Command {
+	var req request.GenerateDataRequest
+	var start, end string
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Ingest random-walk bars for demos and local development",
+		Long: `Generate daily OHLCV bars following a random walk for every weekday of the
+range and ingest them like an upload. Name the symbols with --symbols, or
+generate --count symbols named SYN0001 onwards. The same --seed generates the
+same bars. Needs an admin key unless --direct.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var err error
+			if req.StartDate, err = parseDate(start); err != nil {
+				return err
+			}
+			if req.EndDate, err = parseDate(end); err != nil {
+				return err
+			}
+			req.Normalize()
+			if err := req.Validate(); err != nil {
+				return err
+			}
+
+			return run(cmd, opts, func(ctx context.Context, b backend) error {
+				result, err := b.Generate(ctx, &req)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "job %d: %d rows, %d stored, %d failed\n",
+					result.JobID, result.TotalRows, result.SuccessCount, result.FailedCount)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&req.Symbols, "symbols", nil, "comma-separated symbols to generate")
+	cmd.Flags().IntVar(&req.Count, "count", 0, "number of SYN symbols to generate when --symbols is not given (default 10)")
+	cmd.Flags().StringVar(&start, "start", "", "first date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&end, "end", "", "last date (YYYY-MM-DD)")
+	cmd.Flags().Int64Var(&req.Seed, "seed", 1, "random seed; the same seed generates the same bars")
+	cmd.Flags().Float64Var(&req.Drift, "drift", 0, "daily drift of the returns (default 0.0003)")
+	cmd.Flags().Float64Var(&req.Volatility, "volatility", 0, "daily volatility of the returns (default 0.02)")
+	_ = cmd.MarkFlagRequired("start")
+	_ = cmd.MarkFlagRequired("end")
+
+	return cmd
+}
+
+// newQueryCommand builds `cli query`
+func newQueryCommand(opts *options) *cobra.Command {
+	var q queryFlags
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Print a page of historical data",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req, err := q.request()
+			if err != nil {
+				return err
+			}
+			if output != "table" && output != "json" && output != "csv" {
+				return fmt.Errorf("invalid --output %q: expected table, json or csv", output)
+			}
+
+			return run(cmd, opts, func(ctx context.Context, b backend) error {
+				result, err := b.Query(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				out := cmd.OutOrStdout()
+				switch output {
+				case "json":
+					return printJSON(out, result)
+				case "csv":
+					w := export.NewCSVWriter(out, result.Fields)
+					if err := w.WriteHeader(); err != nil {
+						return err
+					}
+					return w.WriteAll(result.Data)
+				}
+
+				if err := printBars(out, result.Data); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "page %d/%d, %d total rows\n",
+					result.Pagination.Page, result.Pagination.TotalPages, result.Pagination.TotalItems)
+				return nil
+			})
+		},
+	}
+
+	q.register(cmd)
+	cmd.Flags().IntVar(&q.page, "page", 1, "page number")
+	cmd.Flags().IntVar(&q.limit, "limit", 100, "rows per page (max 1000)")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "output format: table, json or csv")
+
+	return cmd
+}
+
+// newExportCommand builds `cli export`
+func newExportCommand(opts *options) *cobra.Command {
+	var q queryFlags
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a full range of historical data as CSV",
+		Long: `Walk every page of the selected range and write it as CSV. The default
+columns match the upload layout so exports can be re-ingested as-is.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req, err := q.request()
+			if err != nil {
+				return err
+			}
+			req.Limit = exportPageSize
+
+			return run(cmd, opts, func(ctx context.Context, b backend) error {
+				out := cmd.OutOrStdout()
+				if file != "" && file != "-" {
+					f, err := os.Create(file)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					out = f
+				}
+
+				columns, _ := req.GetFields()
+				w := export.NewCSVWriter(out, columns)
+				if err := w.WriteHeader(); err != nil {
+					return err
+				}
+
+				var written int64
+				for page := 1; ; page++ {
+					req.Page = page
+					result, err := b.Query(ctx, req)
+					if err != nil {
+						return err
+					}
+					if err := w.WriteAll(result.Data); err != nil {
+						return err
+					}
+					written += int64(len(result.Data))
+					if page >= result.Pagination.TotalPages {
+						break
+					}
+				}
+
+				fmt.Fprintf(cmd.ErrOrStderr(), "exported %d rows\n", written)
+				return nil
+			})
+		},
+	}
+
+	q.register(cmd)
+	cmd.Flags().StringVarP(&file, "file", "f", "-", "output file (- for stdout)")
+
+	return cmd
+}
+
+// newUploadsCommand builds `cli uploads status|list`
+func newUploadsCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "uploads",
+		Short: "Inspect upload jobs",
+	}
+
+	status := &cobra.Command{
+		Use:   "status ID",
+		Short: "Print the status of an upload job",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid upload job ID %q", args[0])
+			}
+			return run(cmd, opts, func(ctx context.Context, b backend) error {
+				job, err := b.UploadJob(ctx, id)
+				if err != nil {
+					return err
+				}
+				return printJobs(cmd.OutOrStdout(), []model.UploadJob{*job})
+			})
+		},
+	}
+
+	var req request.GetUploadJobsRequest
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List recent upload jobs",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return run(cmd, opts, func(ctx context.Context, b backend) error {
+				result, err := b.UploadJobs(ctx, &req)
+				if err != nil {
+					return err
+				}
+				return printJobs(cmd.OutOrStdout(), result.Data)
+			})
+		},
+	}
+	list.Flags().StringVar(&req.Status, "status", "", "filter by status: processing, completed or failed")
+	list.Flags().IntVar(&req.Page, "page", 1, "page number")
+	list.Flags().IntVar(&req.Limit, "limit", 20, "jobs per page (max 1000)")
+
+	cmd.AddCommand(status, list)
+	return cmd
+}
+
+// queryFlags holds the range selection flags shared by query and export
+type queryFlags struct {
+	symbol     string
+	start      string
+	end        string
+	fields     string
+	sort       string
+	sortDir    string
+	source     string
+	tag        string
+	convertTo  string
+	adjustment string
+	page       int
+	limit      int
+}
+
+// register adds the range selection flags to cmd
+func (q *queryFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&q.symbol, "symbol", "s", "", "symbol to select")
+	cmd.Flags().StringVar(&q.start, "start", "", "start date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&q.end, "end", "", "end date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&q.fields, "fields", "", "comma-separated fields to return")
+	cmd.Flags().StringVar(&q.sort, "sort", "date", "sort column: date, symbol, volume or close")
+	cmd.Flags().StringVar(&q.sortDir, "sort-dir", "asc", "sort direction: asc or desc")
+	cmd.Flags().StringVar(&q.source, "source", "", "only rows last written by this upload filename or provider")
+	cmd.Flags().StringVar(&q.tag, "tag", "", "only symbols carrying this tag")
+	cmd.Flags().StringVar(&q.convertTo, "convert-to", "", "convert prices to this currency (ISO 4217 code)")
+	cmd.Flags().StringVar(&q.adjustment, "adjustment", "", "adjust for corporate actions: splits, dividends or all")
+}
+
+// request converts the flags into a validated GetDataRequest
+func (q *queryFlags) request() (*request.GetDataRequest, error) {
+	start, err := parseDate(q.start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseDate(q.end)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &request.GetDataRequest{
+		Symbol:     strings.ToUpper(q.symbol),
+		StartDate:  start,
+		EndDate:    end,
+		Page:       q.page,
+		Limit:      q.limit,
+		Fields:     q.fields,
+		Sort:       q.sort,
+		SortDir:    q.sortDir,
+		Source:     q.source,
+		Tag:        q.tag,
+		ConvertTo:  strings.ToUpper(q.convertTo),
+		Adjustment: q.adjustment,
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// registerIngestionFlags adds the ingestion tuning overrides to cmd
+func registerIngestionFlags(cmd *cobra.Command, overrides *config.IngestionConfig) {
+	cmd.Flags().IntVar(&overrides.BatchSize, "batch-size", 0, "rows per insert batch (admin only via the API)")
+	cmd.Flags().IntVar(&overrides.MaxParallelBatches, "max-parallel-batches", 0, "batches inserted concurrently (admin only via the API)")
+	cmd.Flags().Int64Var(&overrides.MaxFileSize, "max-file-size", 0, "maximum file size in bytes (admin only via the API)")
+	cmd.Flags().IntVar(&overrides.MaxErrors, "max-errors", 0, "failed rows before an upload aborts (admin only via the API)")
+	cmd.Flags().Float64Var(&overrides.MaxErrorRate, "max-error-rate", 0, "percent of failed rows before an upload aborts (admin only via the API)")
+	cmd.Flags().StringVar(&overrides.UnknownSymbols, "unknown-symbols", "", "register, reject or allow symbols without metadata or bars (admin only via the API)")
+	cmd.Flags().BoolVar(&overrides.CaptureAttributes, "capture-attributes", false, "keep unmapped columns as bar attributes")
+	cmd.Flags().BoolVar(&overrides.Staged, "staged", false, "validate the rows in the staging table with SQL and merge them in one statement")
+}
+
+// ingestFiles uploads files sequentially, printing one result line per file.
+// All files are attempted; an error is returned if any of them failed.
+func ingestFiles(ctx context.Context, out io.Writer, b backend, files []string, overrides config.IngestionConfig) error {
+	var failed int
+	for _, path := range files {
+		result, err := b.Ingest(ctx, path, overrides)
+		if err != nil {
+			failed++
+			fmt.Fprintf(out, "%s: error: %v\n", path, err)
+			continue
+		}
+
+		fmt.Fprintf(out, "%s: job %d: %d rows, %d stored, %d failed\n",
+			path, result.JobID, result.TotalRows, result.SuccessCount, result.FailedCount)
+		if len(result.NewSymbols) > 0 {
+			fmt.Fprintf(out, "  new symbols: %s\n", strings.Join(result.NewSymbols, ", "))
+		}
+		for _, e := range result.Errors {
+			fmt.Fprintf(out, "  %s\n", e)
+		}
+		if result.OmittedErrors > 0 {
+			fmt.Fprintf(out, "  ... and %d more errors\n", result.OmittedErrors)
+		}
+		if result.FailedCount > 0 {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files had errors", failed, len(files))
+	}
+	return nil
+}
+
+// readSymbols reads one symbol per line, ignoring blank lines and # comments
+func readSymbols(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var symbols []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		symbols = append(symbols, line)
+	}
+	return symbols, scanner.Err()
+}
+
+// printJSON writes v as indented JSON
+func printJSON(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printBars writes historical data records as an aligned table
+func printBars(out io.Writer, data []response.HistoricalDataResponse) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SYMBOL\tDATE\tOPEN\tHIGH\tLOW\tCLOSE\tVOLUME")
+	for _, d := range data {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n", d.Symbol, d.Date, d.Open.StringFixed(4), d.High.StringFixed(4), d.Low.StringFixed(4), d.Close.StringFixed(4), d.Volume)
+	}
+	return tw.Flush()
+}
+
+// printJobs writes upload jobs as an aligned table
+func printJobs(out io.Writer, jobs []model.UploadJob) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tFILE\tROWS\tSTORED\tFAILED\tSTARTED\tFINISHED\tMESSAGE")
+	for _, j := range jobs {
+		finished := "-"
+		if j.FinishedAt != nil {
+			finished = j.FinishedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d\t%d\t%s\t%s\t%s\n",
+			j.ID, j.Status, j.Filename, j.TotalRows, j.SuccessCount, j.FailedCount,
+			j.StartedAt.Format("2006-01-02 15:04:05"), finished, j.Message)
+	}
+	return tw.Flush()
+}
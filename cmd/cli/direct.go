@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/ingest"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/database"
+	"github.com/go-historical-data/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// cliAPIKey is recorded as the uploader of jobs created in --direct mode
+const cliAPIKey = "cli"
+
+// directBackend runs operations in-process against the database
+type directBackend struct {
+	db         *gorm.DB
+	tenant     string
+	validator  *validator.Validator
+	historical service.HistoricalService
+	uploadJobs service.UploadJobService
+}
+
+// newDirectBackend connects to the database configured for the API
+func newDirectBackend(tenant string) (*directBackend, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if cfg.Database.Driver == "sqlite" && cfg.Database.SQLitePath == database.SQLiteMemory {
+		return nil, fmt.Errorf("--direct needs a database.sqlite_path file: an in-memory database lives inside the API process")
+	}
+	db, err := database.NewConnection(cfg.Database, database.GetLogLevel("error"))
+	if err != nil {
+		return nil, err
+	}
+	if database.IsSQLite(db) {
+		if err := repository.CreateSchema(db); err != nil {
+			return nil, err
+		}
+	}
+
+	res := database.NewResilience(cfg.Database.Resilience, nil, nil)
+	session := database.NewWriteSession(cfg.Ingestion.Session)
+	historicalRepo := repository.NewHistoricalRepository(db, res, session, time.Duration(cfg.Database.CountCacheTTL)*time.Second)
+	uploadJobRepo := repository.NewUploadJobRepository(db, res)
+	symbolRepo := repository.NewSymbolRepository(db, res)
+	converter := service.NewCurrencyConverter(historicalRepo, symbolRepo)
+	adjuster := service.NewAdjustmentService(repository.NewCorporateActionRepository(db, res), historicalRepo)
+	resolver := service.NewSymbolResolver(symbolRepo)
+
+	// Bars written directly still bump the data versions the API tags
+	// responses and caches analytics with
+	bus := events.NewBus()
+	versions := service.NewDataVersionService(repository.NewSymbolVersionRepository(db, res), resolver)
+	events.Subscribe(bus, func(ctx context.Context, e events.BarsIngested) error {
+		return versions.Bump(ctx, e.Symbols...)
+	})
+
+	return &directBackend{
+		db:         db,
+		tenant:     tenant,
+		validator:  validator.New(),
+		historical: service.NewHistoricalService(historicalRepo, repository.NewStagingRepository(db, res, session), uploadJobRepo, repository.NewSourceRepository(db, res), repository.NewDataLockRepository(db, res), repository.NewEarningsRepository(db, res), symbolRepo, converter, adjuster, resolver, ingest.NewRegistry(ingest.Builtins()...), bus, cfg.Ingestion),
+		uploadJobs: service.NewUploadJobService(uploadJobRepo),
+	}, nil
+}
+
+// Ingest uploads a CSV file through the ingestion service
+func (b *directBackend) Ingest(ctx context.Context, path string, overrides config.IngestionConfig) (*response.CSVUploadResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.historical.UploadCSV(ctx, f, service.UploadInfo{
+		Filename:  filepath.Base(path),
+		FileSize:  info.Size(),
+		Tenant:    b.tenant,
+		APIKey:    cliAPIKey,
+		Overrides: overrides,
+
+		CaptureAttributes: overrides.CaptureAttributes,
+		Staged:            overrides.Staged,
+	})
+}
+
+// Generate ingests synthetic bars through the ingestion service
+func (b *directBackend) Generate(ctx context.Context, req *request.GenerateDataRequest) (*response.CSVUploadResponse, error) {
+	if err := b.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	return b.historical.GenerateData(ctx, req, service.UploadInfo{
+		Tenant: b.tenant,
+		APIKey: cliAPIKey,
+	})
+}
+
+// Query retrieves a page of historical data
+func (b *directBackend) Query(ctx context.Context, req *request.GetDataRequest) (*response.PaginatedHistoricalDataResponse, error) {
+	if err := b.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	return b.historical.GetHistoricalData(ctx, req)
+}
+
+// UploadJob retrieves an upload job by ID
+func (b *directBackend) UploadJob(ctx context.Context, id uint64) (*model.UploadJob, error) {
+	job, err := b.uploadJobs.GetUploadJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("upload job %d not found", id)
+	}
+	return job, nil
+}
+
+// UploadJobs lists upload jobs of the configured tenant
+func (b *directBackend) UploadJobs(ctx context.Context, req *request.GetUploadJobsRequest) (*response.PaginatedUploadJobResponse, error) {
+	if err := b.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	return b.uploadJobs.GetUploadJobs(ctx, b.tenant, req)
+}
+
+// Close closes the database connection
+func (b *directBackend) Close() error {
+	sqlDB, err := b.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/spf13/cobra"
+)
+
+// options holds the global flags shared by all commands
+type options struct {
+	apiURL  string
+	apiKey  string
+	direct  bool
+	tenant  string
+	timeout time.Duration
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand builds the command tree
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:   "cli",
+		Short: "Ingest, query and export historical data",
+		Long: `Command line client for the Historical Data API.
+
+Commands talk to the HTTP API by default (--api-url, --api-key). With --direct
+they run against the database using the API's configuration (APP_ENV,
+config/config.<env>.yaml, DB_* overrides), bypassing authentication, quotas
+and the audit log.`,
+		SilenceUsage: true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.apiURL, "api-url", getEnv("HISTORICAL_API_URL", "http://localhost:8080"), "base URL of the API (env HISTORICAL_API_URL)")
+	flags.StringVar(&opts.apiKey, "api-key", os.Getenv("HISTORICAL_API_KEY"), "API key sent as X-API-Key (env HISTORICAL_API_KEY)")
+	flags.BoolVar(&opts.direct, "direct", false, "run against the database instead of the API")
+	flags.StringVar(&opts.tenant, "tenant", middleware.DefaultTenant, "tenant recorded on uploads in --direct mode")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Minute, "overall command timeout")
+
+	root.AddCommand(
+		newIngestCommand(opts),
+		newBackfillCommand(opts),
+		newGenerateCommand(opts),
+		newQueryCommand(opts),
+		newExportCommand(opts),
+		newUploadsCommand(opts),
+	)
+
+	return root
+}
+
+// run opens the selected backend and executes fn with a context bounded by --timeout
+func run(cmd *cobra.Command, opts *options, fn func(ctx context.Context, b backend) error) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+	defer cancel()
+
+	var b backend
+	if opts.direct {
+		direct, err := newDirectBackend(opts.tenant)
+		if err != nil {
+			return err
+		}
+		b = direct
+	} else {
+		b = newAPIBackend(opts.apiURL, opts.apiKey)
+	}
+	defer b.Close()
+
+	return fn(ctx, b)
+}
+
+// getEnv gets environment variable with default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// parseDate accepts YYYY-MM-DD or RFC 3339 timestamps
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD or RFC 3339", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-historical-data/internal/bench"
+	"github.com/spf13/cobra"
+)
+
+// benchmark is an in-process benchmark of an ingest stage over a fixed set of
+// rows per operation, shared with the go test benchmarks of the stage
+type benchmark struct {
+	name string
+	run  func(b *testing.B)
+}
+
+// newBenchCommand builds `loadgen bench`
+func newBenchCommand() *cobra.Command {
+	var data dataFlags
+	var benchtime time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Run the in-process benchmarks of the ingest stages",
+		Long: `Benchmark the ingest stages that run without a database on the synthetic
+bars of --symbols, --start and --end: generating the CSV, decoding it with the
+upload parser and applying the built-in transforms. Each stage processes every
+row once per operation; rows/s compares runs with different settings. These
+are the benchmarks go test -bench runs on a fixed data set.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			gen, err := data.options(0, data.symbols)
+			if err != nil {
+				return err
+			}
+
+			fixture, err := bench.NewFixture(gen)
+			if err != nil {
+				return err
+			}
+			rows, size := fixture.Rows(), len(fixture.CSV)
+
+			benchmarks := []benchmark{
+				{name: "generate", run: fixture.Generate},
+				{name: "decode", run: fixture.Decode},
+				{name: "transform", run: fixture.Transform},
+			}
+
+			// testing.Benchmark runs for the -test.benchtime flag
+			testing.Init()
+			if err := flag.Set("test.benchtime", benchtime.String()); err != nil {
+				return err
+			}
+
+			out := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', tabwriter.AlignRight)
+			fmt.Fprintf(out, "stage\toperations\tms/op\trows/s\tMB/s\tallocs/op\tbytes/op\t\n")
+			for _, stage := range benchmarks {
+				result := testing.Benchmark(stage.run)
+				if result.N == 0 {
+					return fmt.Errorf("%s benchmark failed", stage.name)
+				}
+				perOp := time.Duration(result.NsPerOp())
+				mbPerSec := "-"
+				if result.Bytes > 0 {
+					mbPerSec = fmt.Sprintf("%.1f", float64(result.Bytes)*float64(result.N)/1e6/result.T.Seconds())
+				}
+				fmt.Fprintf(out, "%s\t%d\t%.2f\t%.0f\t%s\t%d\t%d\t\n", stage.name, result.N,
+					float64(perOp)/float64(time.Millisecond), float64(rows)/perOp.Seconds(), mbPerSec,
+					result.AllocsPerOp(), result.AllocedBytesPerOp())
+			}
+			fmt.Fprintf(out, "\n%d rows, %d bytes per operation\n", rows, size)
+			return out.Flush()
+		},
+	}
+
+	data.register(cmd, 10)
+	cmd.Flags().DurationVar(&benchtime, "benchtime", time.Second, "minimum run time of each benchmark")
+	return cmd
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/export"
+	"github.com/go-historical-data/internal/synthetic"
+	apiresponse "github.com/go-historical-data/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+// envelope is the standard API response wrapper
+type envelope struct {
+	Success bool                     `json:"success"`
+	Data    json.RawMessage          `json:"data"`
+	Error   *apiresponse.ErrorDetail `json:"error"`
+}
+
+// uploadResult is the outcome of one synthetic upload
+type uploadResult struct {
+	name     string
+	rows     int64
+	bytes    int64
+	elapsed  time.Duration
+	response *response.CSVUploadResponse
+	err      error
+}
+
+// newIngestCommand builds `loadgen ingest`
+func newIngestCommand(opts *options) *cobra.Command {
+	var data dataFlags
+	var uploads, concurrency, batchSize, parallelBatches int
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "ingest",
+		Short: "Upload synthetic CSVs and report the ingest throughput",
+		Long: `Split the synthetic symbols between --uploads CSVs, generated while they are
+streamed to POST /api/v1/data, with --concurrency uploads in flight. The
+throughput counts the rows stored by the target between the first request
+and the last response. --batch-size and --max-parallel-batches need an admin key.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if uploads <= 0 || uploads > data.symbols {
+				return fmt.Errorf("--uploads must be between 1 and --symbols (%d)", data.symbols)
+			}
+			if concurrency <= 0 {
+				return fmt.Errorf("--concurrency must be positive")
+			}
+			params := url.Values{}
+			if batchSize > 0 {
+				params.Set("batch_size", strconv.Itoa(batchSize))
+			}
+			if parallelBatches > 0 {
+				params.Set("max_parallel_batches", strconv.Itoa(parallelBatches))
+			}
+
+			files := make([]synthetic.Options, 0, uploads)
+			for i := 0; i < uploads; i++ {
+				first, last := i*data.symbols/uploads, (i+1)*data.symbols/uploads
+				gen, err := data.options(first, last-first)
+				if err != nil {
+					return err
+				}
+				files = append(files, gen)
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+			client := &http.Client{}
+			out := cmd.OutOrStdout()
+
+			results := make(chan uploadResult)
+			sem := make(chan struct{}, concurrency)
+			var wg sync.WaitGroup
+			started := time.Now()
+			for i, gen := range files {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+					results <- upload(ctx, client, opts, fmt.Sprintf("loadgen-%03d.csv", i+1), gen, params)
+				}()
+			}
+			go func() {
+				wg.Wait()
+				close(results)
+			}()
+
+			var rows, stored, failed, bytes int64
+			var errs int
+			var durations latencies
+			for result := range results {
+				rows += result.rows
+				bytes += result.bytes
+				durations = append(durations, result.elapsed)
+				if result.err != nil {
+					errs++
+					fmt.Fprintf(out, "%s: error after %s: %v\n", result.name, round(result.elapsed), result.err)
+					continue
+				}
+				stored += int64(result.response.SuccessCount)
+				failed += int64(result.response.FailedCount)
+				fmt.Fprintf(out, "%s: job %d: %d rows, %d stored, %d failed in %s\n", result.name,
+					result.response.JobID, result.rows, result.response.SuccessCount, result.response.FailedCount, round(result.elapsed))
+			}
+			elapsed := time.Since(started)
+
+			fmt.Fprintf(out, "\n%d uploads (%d failed), %d rows sent, %d stored, %d rejected in %s\n",
+				len(files), errs, rows, stored, failed, round(elapsed))
+			fmt.Fprintf(out, "throughput: %.0f rows/s stored, %.1f MB/s sent\n",
+				float64(stored)/elapsed.Seconds(), float64(bytes)/1e6/elapsed.Seconds())
+			durations.print(out, "upload latency")
+
+			if errs > 0 || failed > 0 {
+				return fmt.Errorf("%d uploads failed and %d rows were rejected", errs, failed)
+			}
+			return nil
+		},
+	}
+
+	data.register(cmd, 100)
+	cmd.Flags().IntVar(&uploads, "uploads", 10, "number of CSVs the symbols are split between")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "uploads in flight at once")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 0, "rows per insert batch (admin only)")
+	cmd.Flags().IntVar(&parallelBatches, "max-parallel-batches", 0, "batches inserted concurrently per upload (admin only)")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "overall run timeout")
+	return cmd
+}
+
+// upload streams the CSV of gen to POST /api/v1/data as it is generated
+func upload(ctx context.Context, client *http.Client, opts *options, name string, gen synthetic.Options, params url.Values) uploadResult {
+	result := uploadResult{name: name}
+	started := time.Now()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, name))
+		header.Set("Content-Type", export.ContentTypeCSV)
+		part, err := mw.CreatePart(header)
+		if err == nil {
+			counter := &countingWriter{w: part}
+			result.rows, err = synthetic.WriteCSV(counter, synthetic.NewGenerator(gen))
+			result.bytes = counter.n
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	var uploaded response.CSVUploadResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(opts.apiURL, "/")+"/api/v1/data?"+params.Encode(), pr)
+	if err == nil {
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		err = do(client, opts, req, &uploaded)
+	}
+	// Unblocks the generator when the target answered before reading the
+	// whole body
+	pr.CloseWithError(io.ErrClosedPipe)
+	<-written
+
+	result.elapsed = time.Since(started)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.response = &uploaded
+	return result
+}
+
+// do sends the request and unwraps the response envelope into out
+func do(client *http.Client, opts *options, req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	if opts.apiKey != "" {
+		req.Header.Set("X-API-Key", opts.apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("unexpected response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if !env.Success || resp.StatusCode >= http.StatusBadRequest {
+		if env.Error != nil {
+			return fmt.Errorf("%s: %s (HTTP %d)", env.Error.Code, env.Error.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("request failed (HTTP %d)", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(env.Data, out)
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
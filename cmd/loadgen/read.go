@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newReadCommand builds `loadgen read`
+func newReadCommand(opts *options) *cobra.Command {
+	var data dataFlags
+	var concurrency, windowDays, limit int
+	var duration time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "read",
+		Short: "Query random ranges and report the read latency percentiles",
+		Long: `Issue GET /api/v1/data for a random synthetic symbol and a random window of
+--window-days within --start and --end, from --concurrency clients for
+--duration. Use the --symbols, --start and --end of the ingest run, so every
+query finds bars.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			gen, err := data.options(0, data.symbols)
+			if err != nil {
+				return err
+			}
+			if concurrency <= 0 || windowDays <= 0 {
+				return fmt.Errorf("--concurrency and --window-days must be positive")
+			}
+			days := int(gen.End.Sub(gen.Start).Hours()/24) + 1
+			windowDays = min(windowDays, days)
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), duration)
+			defer cancel()
+			client := &http.Client{}
+			baseURL := strings.TrimRight(opts.apiURL, "/") + "/api/v1/data?"
+
+			var mu sync.Mutex
+			var durations latencies
+			var errs int
+			var lastErr error
+			var wg sync.WaitGroup
+			started := time.Now()
+			for i := 0; i < concurrency; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					rng := rand.New(rand.NewSource(data.seed + int64(i)))
+					for ctx.Err() == nil {
+						from := gen.Start.AddDate(0, 0, rng.Intn(days-windowDays+1))
+						params := url.Values{}
+						params.Set("symbol", gen.Symbols[rng.Intn(len(gen.Symbols))])
+						params.Set("start_date", from.Format(time.RFC3339))
+						params.Set("end_date", from.AddDate(0, 0, windowDays-1).Format(time.RFC3339))
+						params.Set("limit", strconv.Itoa(limit))
+
+						req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+params.Encode(), nil)
+						if err != nil {
+							return
+						}
+						sent := time.Now()
+						err = do(client, opts, req, nil)
+						elapsed := time.Since(sent)
+						if ctx.Err() != nil {
+							// Cut off by the end of the run
+							return
+						}
+
+						mu.Lock()
+						durations = append(durations, elapsed)
+						if err != nil {
+							errs++
+							lastErr = err
+						}
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			elapsed := time.Since(started)
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%d requests (%d failed) in %s: %.1f req/s\n",
+				len(durations), errs, round(elapsed), float64(len(durations))/elapsed.Seconds())
+			durations.print(out, "read latency")
+			if errs > 0 {
+				return fmt.Errorf("%d of %d requests failed, last: %w", errs, len(durations), lastErr)
+			}
+			return nil
+		},
+	}
+
+	data.register(cmd, 100)
+	cmd.Flags().IntVar(&concurrency, "concurrency", 8, "clients querying at once")
+	cmd.Flags().IntVar(&windowDays, "window-days", 90, "calendar days covered by each query")
+	cmd.Flags().IntVar(&limit, "limit", 100, "rows per page (max 1000)")
+	cmd.Flags().DurationVar(&duration, "duration", 30*time.Second, "length of the run")
+	return cmd
+}
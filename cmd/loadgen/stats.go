@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+// latencies collects the durations of requests
+type latencies []time.Duration
+
+// percentile returns the duration under which q (0-1] of the requests completed
+func (l latencies) percentile(q float64) time.Duration {
+	if len(l) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(l)
+	slices.Sort(sorted)
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[max(i, 0)]
+}
+
+// print writes the percentiles of the latencies on one line
+func (l latencies) print(out io.Writer, label string) {
+	fmt.Fprintf(out, "%s: p50 %s, p90 %s, p95 %s, p99 %s, max %s\n", label,
+		round(l.percentile(0.50)), round(l.percentile(0.90)), round(l.percentile(0.95)),
+		round(l.percentile(0.99)), round(l.percentile(1)))
+}
+
+// round keeps durations readable
+func round(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/database"
+)
+
+const usage = `Usage: migrate <command> [args]
+
+Commands:
+  up            Apply all pending migrations
+  down [N]      Roll back N migrations (default 1)
+  status        Print the current and latest schema versions
+  force V       Set the schema version to V without running migrations (clears dirty state)
+
+Configuration is loaded like the API (APP_ENV, config/config.<env>.yaml, DB_* overrides).
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	migrator, err := database.NewMigrator(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize migrator: %v\n", err)
+		os.Exit(1)
+	}
+	defer migrator.Close()
+
+	if err := run(migrator, flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		migrator.Close()
+		os.Exit(1)
+	}
+}
+
+// run executes a single migrate command
+func run(migrator *database.Migrator, command string, args []string) error {
+	switch command {
+	case "up":
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+		return printStatus(migrator)
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step count %q", args[0])
+			}
+			steps = n
+		}
+		if err := migrator.Down(steps); err != nil {
+			return err
+		}
+		return printStatus(migrator)
+
+	case "status":
+		return printStatus(migrator)
+
+	case "force":
+		if len(args) < 1 {
+			return fmt.Errorf("force requires a version")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+		if err := migrator.Force(version); err != nil {
+			return err
+		}
+		return printStatus(migrator)
+
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+}
+
+// printStatus prints the schema version summary
+func printStatus(migrator *database.Migrator) error {
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	state := "up to date"
+	switch {
+	case status.Dirty:
+		state = "dirty"
+	case status.Pending():
+		state = fmt.Sprintf("%d pending", status.Latest-status.Current)
+	}
+
+	fmt.Printf("current: %d\nlatest:  %d\nstate:   %s\n", status.Current, status.Latest, state)
+	return nil
+}
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    request_id VARCHAR(64) NOT NULL,
+    tenant VARCHAR(64) NOT NULL,
+    api_key VARCHAR(64) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    client_ip VARCHAR(64),
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path VARCHAR(1024) NOT NULL,
+    symbols TEXT,
+    resource_ids TEXT,
+    status_code INT NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_request_id (request_id),
+    INDEX idx_audit_tenant_created (tenant, created_at),
+    INDEX idx_audit_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
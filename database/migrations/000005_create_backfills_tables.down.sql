@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS backfill_chunks;
+DROP TABLE IF EXISTS backfills;
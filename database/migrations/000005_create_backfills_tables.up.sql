@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS backfills (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL,
+    api_key VARCHAR(64) NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    symbols MEDIUMTEXT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    total_chunks INT NOT NULL DEFAULT 0,
+    completed_chunks INT NOT NULL DEFAULT 0,
+    failed_chunks INT NOT NULL DEFAULT 0,
+    rows_ingested BIGINT NOT NULL DEFAULT 0,
+    started_at DATETIME(3) NULL,
+    finished_at DATETIME(3) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_backfills_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS backfill_chunks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    backfill_id BIGINT UNSIGNED NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    rows_fetched INT NOT NULL DEFAULT 0,
+    rows_ingested INT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at DATETIME(3) NULL,
+    finished_at DATETIME(3) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_backfill_chunks_backfill_status (backfill_id, status),
+    CONSTRAINT fk_backfill_chunks_backfill FOREIGN KEY (backfill_id) REFERENCES backfills (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
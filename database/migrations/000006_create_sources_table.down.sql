@@ -0,0 +1,7 @@
+ALTER TABLE backfills DROP COLUMN source_id;
+
+ALTER TABLE historical_data
+    DROP INDEX idx_source_id,
+    DROP COLUMN source_id;
+
+DROP TABLE IF EXISTS sources;
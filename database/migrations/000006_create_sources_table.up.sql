@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS sources (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    tenant VARCHAR(64) NOT NULL,
+    upload_job_id BIGINT UNSIGNED NULL,
+    backfill_id BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_sources_name (name),
+    INDEX idx_sources_kind_created (kind, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE historical_data
+    ADD COLUMN source_id BIGINT UNSIGNED NULL AFTER volume,
+    ADD INDEX idx_source_id (source_id);
+
+ALTER TABLE backfills
+    ADD COLUMN source_id BIGINT UNSIGNED NULL AFTER provider;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS corporate_actions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    ex_date DATE NOT NULL,
+    ratio DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    amount DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_corporate_actions_symbol_type_date (symbol, type, ex_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL,
+    api_key VARCHAR(64) NOT NULL,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    symbol VARCHAR(20) NOT NULL,
+    condition_type VARCHAR(20) NOT NULL,
+    threshold DECIMAL(20, 8) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    target VARCHAR(512) NOT NULL,
+    enabled TINYINT(1) NOT NULL DEFAULT 1,
+    last_triggered_at DATETIME(3) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_alert_rules_symbol_enabled (symbol, enabled),
+    INDEX idx_alert_rules_tenant (tenant)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS alert_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    rule_id BIGINT UNSIGNED NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    date DATE NOT NULL,
+    previous_close DECIMAL(20, 8) NOT NULL,
+    close DECIMAL(20, 8) NOT NULL,
+    value DECIMAL(20, 8) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error TEXT,
+    delivered_at DATETIME(3) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_alert_events_rule_date (rule_id, date),
+    CONSTRAINT fk_alert_events_rule FOREIGN KEY (rule_id) REFERENCES alert_rules (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
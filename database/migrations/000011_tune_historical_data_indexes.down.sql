@@ -0,0 +1,4 @@
+ALTER TABLE historical_data
+    DROP INDEX idx_symbol_date_close_volume,
+    ADD INDEX idx_symbol (symbol),
+    ADD INDEX idx_symbol_date (symbol, date);
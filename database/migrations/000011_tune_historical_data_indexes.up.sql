@@ -0,0 +1,41 @@
+-- Bar upserts (ON CONFLICT (symbol, date)) need a unique index on
+-- (symbol, date). Schemas created by the old AutoMigrate only had a plain
+-- idx_symbol_date and may hold duplicate bars, so the latest row of each
+-- (symbol, date) is kept before the unique index is added where missing.
+DELETE older FROM historical_data older
+    JOIN historical_data newer
+      ON newer.symbol = older.symbol AND newer.date = older.date AND newer.id > older.id;
+
+SET @stmt = IF(
+    (SELECT COUNT(*) FROM information_schema.STATISTICS
+      WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'historical_data' AND INDEX_NAME = 'unique_symbol_date') = 0,
+    'ALTER TABLE historical_data ADD UNIQUE KEY unique_symbol_date (symbol, date)',
+    'DO 0');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- unique_symbol_date already serves symbol and symbol+date lookups, so the
+-- redundant idx_symbol and idx_symbol_date are dropped where present
+SET @stmt = IF(
+    (SELECT COUNT(*) FROM information_schema.STATISTICS
+      WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'historical_data' AND INDEX_NAME = 'idx_symbol') > 0,
+    'ALTER TABLE historical_data DROP INDEX idx_symbol',
+    'DO 0');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @stmt = IF(
+    (SELECT COUNT(*) FROM information_schema.STATISTICS
+      WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'historical_data' AND INDEX_NAME = 'idx_symbol_date') > 0,
+    'ALTER TABLE historical_data DROP INDEX idx_symbol_date',
+    'DO 0');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- Covers the common date/close/volume projections of a symbol (sparse
+-- fields, returns, charts, backtests) without reading the table rows
+ALTER TABLE historical_data
+    ADD INDEX idx_symbol_date_close_volume (symbol, date, close, volume);
@@ -0,0 +1,5 @@
+ALTER TABLE historical_data REMOVE PARTITIONING;
+
+ALTER TABLE historical_data
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (id);
@@ -0,0 +1,33 @@
+-- Monthly RANGE partitioning on date. MySQL requires every unique key to
+-- contain the partitioning column, so the primary key becomes (id, date);
+-- unique_symbol_date already contains it.
+ALTER TABLE historical_data
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (id, date);
+
+-- One partition per month from the oldest stored bar through three months
+-- ahead, p_before for older dates and p_future for anything later. The
+-- partition maintenance job keeps creating months ahead of time and drops
+-- expired ones (database.partitioning).
+SET SESSION group_concat_max_len = 1048576;
+SET @first = (SELECT DATE_FORMAT(COALESCE(MIN(date), CURRENT_DATE), '%Y-%m-01') FROM historical_data);
+SET @partitions = (
+    WITH RECURSIVE months (m) AS (
+        SELECT CAST(@first AS DATE)
+        UNION ALL
+        SELECT m + INTERVAL 1 MONTH FROM months
+        WHERE m < CAST(DATE_FORMAT(CURRENT_DATE, '%Y-%m-01') AS DATE) + INTERVAL 3 MONTH
+    )
+    SELECT GROUP_CONCAT(
+        CONCAT('PARTITION p', DATE_FORMAT(m, '%Y%m'), ' VALUES LESS THAN (''', m + INTERVAL 1 MONTH, ''')')
+        ORDER BY m SEPARATOR ', ')
+    FROM months
+);
+SET @stmt = CONCAT(
+    'ALTER TABLE historical_data PARTITION BY RANGE COLUMNS (date) (',
+    'PARTITION p_before VALUES LESS THAN (''', @first, '''), ',
+    @partitions, ', ',
+    'PARTITION p_future VALUES LESS THAN (MAXVALUE))');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
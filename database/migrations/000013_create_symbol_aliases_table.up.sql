@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS symbol_aliases (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    alias VARCHAR(20) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_symbol_aliases_alias (alias),
+    INDEX idx_symbol_aliases_symbol (symbol)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS historical_rollups (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    period VARCHAR(8) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    open DECIMAL(20, 8) NOT NULL,
+    high DECIMAL(20, 8) NOT NULL,
+    low DECIMAL(20, 8) NOT NULL,
+    close DECIMAL(20, 8) NOT NULL,
+    volume BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    bar_count INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_historical_rollups_symbol_period_start (symbol, period, period_start)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
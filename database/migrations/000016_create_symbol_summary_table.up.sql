@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS symbol_summary (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    row_count BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    first_date DATE NOT NULL,
+    last_date DATE NOT NULL,
+    last_close DECIMAL(20, 8) NOT NULL,
+    last_ingested_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_symbol_summary_symbol (symbol),
+    INDEX idx_symbol_summary_last_date (last_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Summarize the data already stored
+INSERT INTO symbol_summary (symbol, row_count, first_date, last_date, last_close)
+SELECT s.symbol, s.row_count, s.first_date, s.last_date, h.close
+FROM (
+    SELECT symbol, COUNT(*) AS row_count, MIN(date) AS first_date, MAX(date) AS last_date
+    FROM historical_data
+    GROUP BY symbol
+) s
+JOIN historical_data h ON h.symbol = s.symbol AND h.date = s.last_date;
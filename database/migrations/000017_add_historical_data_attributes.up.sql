@@ -0,0 +1,3 @@
+-- Vendor columns without a dedicated field (adjclose, openinterest, ...),
+-- captured from uploads on request
+ALTER TABLE historical_data ADD COLUMN attributes JSON NULL AFTER source_id;
@@ -0,0 +1,3 @@
+ALTER TABLE historical_data
+    DROP COLUMN open_interest,
+    DROP COLUMN number_of_trades;
@@ -0,0 +1,4 @@
+-- Futures and options fields, NULL where the source doesn't report them
+ALTER TABLE historical_data
+    ADD COLUMN open_interest BIGINT UNSIGNED NULL AFTER volume,
+    ADD COLUMN number_of_trades BIGINT UNSIGNED NULL AFTER open_interest;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS quotes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    date DATE NOT NULL,
+    bid DECIMAL(20, 8) NOT NULL,
+    ask DECIMAL(20, 8) NOT NULL,
+    source_id BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_quotes_symbol_date (symbol, date),
+    INDEX idx_quotes_date (date),
+    INDEX idx_quotes_source_id (source_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,11 @@
+-- Trades at microsecond precision. Append-only: no unique key, as several
+-- trades may share a timestamp, and no bookkeeping columns.
+CREATE TABLE IF NOT EXISTS ticks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    ts DATETIME(6) NOT NULL,
+    price DECIMAL(20, 8) NOT NULL,
+    size DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    side VARCHAR(4) NOT NULL DEFAULT '',
+    INDEX idx_ticks_symbol_ts (symbol, ts)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
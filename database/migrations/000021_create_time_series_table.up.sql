@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS time_series (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    series_id VARCHAR(64) NOT NULL,
+    ts DATETIME(6) NOT NULL,
+    value DECIMAL(30, 10) NOT NULL,
+    fields JSON NULL,
+    source_id BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_time_series_series_ts (series_id, ts),
+    INDEX idx_time_series_source_id (source_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
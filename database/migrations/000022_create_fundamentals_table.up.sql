@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS fundamentals (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    period VARCHAR(6) NOT NULL,
+    period_end DATE NOT NULL,
+    eps DECIMAL(20, 8) NULL,
+    revenue DECIMAL(24, 4) NULL,
+    net_income DECIMAL(24, 4) NULL,
+    shares_outstanding BIGINT UNSIGNED NULL,
+    source_id BIGINT UNSIGNED NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_fundamentals_symbol_period (symbol, period),
+    INDEX idx_fundamentals_symbol_period_end (symbol, period_end),
+    INDEX idx_fundamentals_source_id (source_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS earnings_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    period VARCHAR(6) NOT NULL,
+    report_date DATE NOT NULL,
+    eps_estimate DECIMAL(20, 8) NULL,
+    eps_actual DECIMAL(20, 8) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_earnings_events_symbol_period (symbol, period),
+    INDEX idx_earnings_events_symbol_report_date (symbol, report_date),
+    INDEX idx_earnings_events_report_date (report_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
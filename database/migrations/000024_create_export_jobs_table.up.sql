@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL,
+    api_key VARCHAR(64) NOT NULL,
+    symbols MEDIUMTEXT NOT NULL,
+    start_date DATE NULL,
+    end_date DATE NULL,
+    format VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    artifact_key VARCHAR(255) NULL,
+    error TEXT,
+    started_at DATETIME(3) NULL,
+    finished_at DATETIME(3) NULL,
+    expires_at DATETIME(3) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_export_jobs_tenant_created (tenant, created_at),
+    INDEX idx_export_jobs_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
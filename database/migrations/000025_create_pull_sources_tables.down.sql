@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pull_files;
+DROP TABLE IF EXISTS pull_source_states;
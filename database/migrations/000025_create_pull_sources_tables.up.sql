@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS pull_source_states (
+    name VARCHAR(64) PRIMARY KEY,
+    last_poll_at DATETIME(3) NULL,
+    last_success_at DATETIME(3) NULL,
+    last_error TEXT,
+    files_ingested BIGINT NOT NULL DEFAULT 0,
+    files_failed BIGINT NOT NULL DEFAULT 0,
+    rows_ingested BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS pull_files (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    source VARCHAR(64) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    modified_at DATETIME(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    upload_job_id BIGINT UNSIGNED NULL,
+    row_count INT NOT NULL DEFAULT 0,
+    error TEXT,
+    archived_to VARCHAR(512) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_pull_files_source_file (source, filename, size, modified_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+ALTER TABLE upload_jobs
+    DROP COLUMN resumes,
+    DROP COLUMN checkpoint_line;
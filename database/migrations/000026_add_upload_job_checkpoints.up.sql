@@ -0,0 +1,3 @@
+ALTER TABLE upload_jobs
+    ADD COLUMN checkpoint_line INT NOT NULL DEFAULT 0 AFTER processed_bytes,
+    ADD COLUMN resumes INT NOT NULL DEFAULT 0 AFTER checkpoint_line;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS symbol_tags (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    tag VARCHAR(32) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_symbol_tags_tag_symbol (tag, symbol),
+    INDEX idx_symbol_tags_symbol (symbol)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE ticks DROP COLUMN session;
@@ -0,0 +1,3 @@
+-- Trading session of each tick (pre, regular, post), empty outside the
+-- sessions or when the symbol's exchange has no calendar
+ALTER TABLE ticks ADD COLUMN session VARCHAR(8) NOT NULL DEFAULT '' AFTER side;
@@ -0,0 +1 @@
+ALTER TABLE symbol_summary DROP COLUMN high_52w, DROP COLUMN low_52w;
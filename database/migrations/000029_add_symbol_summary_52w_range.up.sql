@@ -0,0 +1,16 @@
+-- 52-week high and low of each symbol, over the bars of the 52 weeks up to
+-- its latest bar
+ALTER TABLE symbol_summary
+    ADD COLUMN high_52w DECIMAL(20, 8) NOT NULL DEFAULT 0 AFTER last_close,
+    ADD COLUMN low_52w DECIMAL(20, 8) NOT NULL DEFAULT 0 AFTER high_52w;
+
+-- Summarize the data already stored
+UPDATE symbol_summary s
+JOIN (
+    SELECT h.symbol, MAX(h.high) AS high_52w, MIN(h.low) AS low_52w
+    FROM historical_data h
+    JOIN symbol_summary r ON r.symbol = h.symbol
+    WHERE h.date > DATE_SUB(r.last_date, INTERVAL 52 WEEK) AND h.date <= r.last_date
+    GROUP BY h.symbol
+) w ON w.symbol = s.symbol
+SET s.high_52w = w.high_52w, s.low_52w = w.low_52w;
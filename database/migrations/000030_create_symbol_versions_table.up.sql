@@ -0,0 +1,9 @@
+-- Data version of each symbol, bumped by every write touching its bars,
+-- corporate actions or metadata. Symbols without a row are at version 0.
+CREATE TABLE IF NOT EXISTS symbol_versions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    version BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_symbol_versions_symbol (symbol)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS historical_data_staging;
@@ -0,0 +1,21 @@
+-- Rows of staged uploads, validated with set-based checks before a single
+-- INSERT ... SELECT merges them into historical_data. error_code is set on
+-- the rejected rows; every row of a job is deleted once it is merged.
+CREATE TABLE IF NOT EXISTS historical_data_staging (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    job_id BIGINT UNSIGNED NOT NULL,
+    line INT NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    date DATE NOT NULL,
+    open DECIMAL(20, 8) NOT NULL,
+    high DECIMAL(20, 8) NOT NULL,
+    low DECIMAL(20, 8) NOT NULL,
+    close DECIMAL(20, 8) NOT NULL,
+    volume BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    open_interest BIGINT UNSIGNED NULL,
+    number_of_trades BIGINT UNSIGNED NULL,
+    source_id BIGINT UNSIGNED NULL,
+    attributes JSON NULL,
+    error_code VARCHAR(32) NULL,
+    INDEX idx_staging_job_symbol_date (job_id, symbol, date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
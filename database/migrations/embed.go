@@ -0,0 +1,12 @@
+// Package migrations embeds the versioned SQL schema migrations so binaries can
+// apply and verify them without shipping the files separately.
+package migrations
+
+import (
+	"embed"
+)
+
+// FS contains the versioned up/down SQL migration files
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,130 @@
+package analytics
+
+import (
+	"math"
+)
+
+// SMA returns the simple moving average of values over window; elements
+// before the first full window are NaN
+func SMA(values []float64, window int) []float64 {
+	sma := make([]float64, len(values))
+	for i := range values {
+		if window < 1 || i+1 < window {
+			sma[i] = math.NaN()
+			continue
+		}
+		var sum float64
+		for j := i + 1 - window; j <= i; j++ {
+			sum += values[j]
+		}
+		sma[i] = sum / float64(window)
+	}
+	return sma
+}
+
+// Signals of a strategy
+const (
+	SignalNone = 0
+	SignalBuy  = 1
+	SignalSell = -1
+)
+
+// SMACrossoverSignals returns a buy signal on the bars where the fast SMA
+// crosses above the slow SMA and a sell signal where it crosses below
+func SMACrossoverSignals(closes []float64, fastWindow, slowWindow int) []int {
+	fast := SMA(closes, fastWindow)
+	slow := SMA(closes, slowWindow)
+
+	signals := make([]int, len(closes))
+	for i := 1; i < len(closes); i++ {
+		if math.IsNaN(fast[i-1]) || math.IsNaN(slow[i-1]) || math.IsNaN(fast[i]) || math.IsNaN(slow[i]) {
+			continue
+		}
+		switch {
+		case fast[i-1] <= slow[i-1] && fast[i] > slow[i]:
+			signals[i] = SignalBuy
+		case fast[i-1] >= slow[i-1] && fast[i] < slow[i]:
+			signals[i] = SignalSell
+		}
+	}
+	return signals
+}
+
+// Trade is a round trip of a backtest. A trade still open at the end of the
+// backtest is valued at the last close.
+type Trade struct {
+	EntryIndex int
+	ExitIndex  int
+	EntryPrice float64
+	ExitPrice  float64
+	Quantity   float64
+	PnL        float64 // net of commissions
+	Return     float64 // fraction of the capital committed at entry
+	Open       bool
+}
+
+// BacktestResult holds the trades of a backtest and the equity at every bar
+type BacktestResult struct {
+	Trades       []Trade
+	Equity       []float64 // one value per bar from the first traded bar
+	BarsInMarket int       // bars ending with an open position
+	FinalValue   float64
+}
+
+// Backtest runs a long-only strategy over closes from index from on, starting
+// with capital in cash. Orders fill at the close of the signal bar, investing
+// all the cash on a buy and selling the whole position on a sell; commission
+// is a fraction of the traded value charged on both sides. Bars before from
+// only warm up the signals.
+func Backtest(closes []float64, signals []int, from int, capital, commission float64) BacktestResult {
+	result := BacktestResult{
+		Trades:     make([]Trade, 0),
+		Equity:     make([]float64, 0, len(closes)),
+		FinalValue: capital,
+	}
+
+	cash, quantity := capital, 0.0
+	var open *Trade
+	var committed float64
+	for i := from; i < len(closes); i++ {
+		price := closes[i]
+		switch {
+		case signals[i] == SignalBuy && open == nil && price > 0:
+			committed = cash
+			quantity = cash * (1 - commission) / price
+			cash = 0
+			open = &Trade{EntryIndex: i, EntryPrice: price, Quantity: quantity}
+		case signals[i] == SignalSell && open != nil:
+			cash = quantity * price * (1 - commission)
+			result.Trades = append(result.Trades, closeTrade(open, i, price, cash, committed, false))
+			open, quantity = nil, 0
+		}
+
+		if open != nil {
+			result.BarsInMarket++
+		}
+		result.Equity = append(result.Equity, cash+quantity*price)
+	}
+
+	if open != nil {
+		last := len(closes) - 1
+		result.Trades = append(result.Trades, closeTrade(open, last, closes[last], quantity*closes[last], committed, true))
+	}
+	if n := len(result.Equity); n > 0 {
+		result.FinalValue = result.Equity[n-1]
+	}
+	return result
+}
+
+// closeTrade completes a trade exiting at price with proceeds in cash
+func closeTrade(trade *Trade, index int, price, proceeds, committed float64, open bool) Trade {
+	t := *trade
+	t.ExitIndex = index
+	t.ExitPrice = price
+	t.PnL = proceeds - committed
+	if committed > 0 {
+		t.Return = t.PnL / committed
+	}
+	t.Open = open
+	return t
+}
@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"math"
+)
+
+// Bar is the subset of a daily bar used to derive indicators
+type Bar struct {
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// Derived holds the indicators derived for one bar. Windowed values are nil
+// until enough preceding bars are available.
+type Derived struct {
+	TypicalPrice float64
+	VWAP         *float64 // volume-weighted typical price over the VWAP window
+	TrueRange    float64
+	ATR          *float64 // simple average of the true range over the ATR window
+}
+
+// TypicalPrice returns (high + low + close) / 3
+func TypicalPrice(high, low, close float64) float64 {
+	return (high + low + close) / 3
+}
+
+// TrueRange returns the greatest of high - low, |high - previous close| and
+// |low - previous close|; high - low for the first bar of a series
+func TrueRange(bar, prev Bar, hasPrev bool) float64 {
+	tr := bar.High - bar.Low
+	if hasPrev {
+		tr = math.Max(tr, math.Max(math.Abs(bar.High-prev.Close), math.Abs(bar.Low-prev.Close)))
+	}
+	return tr
+}
+
+// Derive computes the indicators of every bar of a series in ascending date
+// order. Averages are simple moving windows, so a bar's values only depend on
+// the bars of its windows and are identical whatever range was requested.
+func Derive(bars []Bar, vwapWindow, atrWindow int) []Derived {
+	derived := make([]Derived, len(bars))
+	for i := range bars {
+		d := &derived[i]
+		d.TypicalPrice = TypicalPrice(bars[i].High, bars[i].Low, bars[i].Close)
+		if i > 0 {
+			d.TrueRange = TrueRange(bars[i], bars[i-1], true)
+		} else {
+			d.TrueRange = TrueRange(bars[i], Bar{}, false)
+		}
+	}
+
+	// Windows are summed from scratch rather than rolled, so rounding doesn't
+	// depend on how many bars precede them
+	for i := range derived {
+		if i+1 >= vwapWindow {
+			var priceVolume, volume float64
+			for j := i + 1 - vwapWindow; j <= i; j++ {
+				priceVolume += derived[j].TypicalPrice * bars[j].Volume
+				volume += bars[j].Volume
+			}
+			if volume > 0 {
+				vwap := priceVolume / volume
+				derived[i].VWAP = &vwap
+			}
+		}
+
+		// The first bar has no previous close, so the ATR starts from the second
+		if i >= atrWindow {
+			var trueRange float64
+			for j := i + 1 - atrWindow; j <= i; j++ {
+				trueRange += derived[j].TrueRange
+			}
+			atr := trueRange / float64(atrWindow)
+			derived[i].ATR = &atr
+		}
+	}
+	return derived
+}
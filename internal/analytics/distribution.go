@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"math"
+	"slices"
+)
+
+// DistributionPercentiles are the percentiles reported with a distribution
+var DistributionPercentiles = []float64{1, 5, 25, 50, 75, 95, 99}
+
+// Bucket is one bin of a histogram over [Lower, Upper); the last bin also
+// holds its upper bound
+type Bucket struct {
+	Lower float64
+	Upper float64
+	Count int
+}
+
+// Percentile returns the p-th percentile (0 to 100) of sorted values,
+// interpolating linearly between the closest ranks; sorted must not be empty
+func Percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// Histogram bins values into count equal-width buckets spanning their range.
+// Constant values fall in a single bucket.
+func Histogram(values []float64, count int) []Bucket {
+	if len(values) == 0 || count < 1 {
+		return nil
+	}
+	low, high := slices.Min(values), slices.Max(values)
+	if low == high {
+		return []Bucket{{Lower: low, Upper: high, Count: len(values)}}
+	}
+
+	width := (high - low) / float64(count)
+	buckets := make([]Bucket, count)
+	for i := range buckets {
+		buckets[i].Lower = low + float64(i)*width
+		buckets[i].Upper = low + float64(i+1)*width
+	}
+	buckets[count-1].Upper = high
+	for _, v := range values {
+		i := min(int((v-low)/width), count-1)
+		buckets[i].Count++
+	}
+	return buckets
+}
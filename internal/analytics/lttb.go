@@ -0,0 +1,61 @@
+package analytics
+
+// LTTB downsamples a series to at most threshold points with the
+// Largest-Triangle-Three-Buckets algorithm and returns the indices of the kept
+// points in ascending order. The first and last points are always kept; every
+// bucket in between contributes the point forming the largest triangle with the
+// previously kept point and the average of the next bucket, which preserves the
+// visual shape of the series. xs must be ascending.
+func LTTB(xs, ys []float64, threshold int) []int {
+	n := len(xs)
+	if threshold >= n || threshold < 3 {
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	indices := make([]int, 0, threshold)
+	indices = append(indices, 0)
+
+	// The points between the first and the last are split into threshold-2 buckets
+	bucketSize := float64(n-2) / float64(threshold-2)
+	a := 0
+	for b := 0; b < threshold-2; b++ {
+		start := int(float64(b)*bucketSize) + 1
+		end := int(float64(b+1)*bucketSize) + 1
+
+		// Average of the next bucket, or the last point for the last bucket
+		nextStart, nextEnd := end, int(float64(b+2)*bucketSize)+1
+		if nextEnd > n-1 {
+			nextEnd = n - 1
+		}
+		if nextStart >= nextEnd {
+			nextStart, nextEnd = n-1, n
+		}
+		var avgX, avgY float64
+		for i := nextStart; i < nextEnd; i++ {
+			avgX += xs[i]
+			avgY += ys[i]
+		}
+		avgX /= float64(nextEnd - nextStart)
+		avgY /= float64(nextEnd - nextStart)
+
+		maxArea, selected := -1.0, start
+		for i := start; i < end; i++ {
+			// Twice the triangle area; only the comparison matters
+			area := (xs[a]-avgX)*(ys[i]-ys[a]) - (xs[a]-xs[i])*(avgY-ys[a])
+			if area < 0 {
+				area = -area
+			}
+			if area > maxArea {
+				maxArea, selected = area, i
+			}
+		}
+		indices = append(indices, selected)
+		a = selected
+	}
+
+	return append(indices, n-1)
+}
@@ -0,0 +1,161 @@
+// Package analytics implements the statistics served by the analytics
+// endpoints. Computations work on plain float series and accumulate point by
+// point so callers can feed rows as they are read.
+package analytics
+
+import (
+	"math"
+	"time"
+)
+
+// Return periods
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+// PeriodsPerYear returns the number of periods used to annualize statistics
+func PeriodsPerYear(period string) float64 {
+	switch period {
+	case PeriodWeekly:
+		return 52
+	case PeriodMonthly:
+		return 12
+	default:
+		return 252
+	}
+}
+
+// Returns converts a price series into simple period returns. The result has
+// one element less than values; a return following a non-positive price is 0.
+func Returns(values []float64) []float64 {
+	if len(values) < 2 {
+		return nil
+	}
+	returns := make([]float64, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		if values[i-1] > 0 {
+			returns[i-1] = values[i]/values[i-1] - 1
+		}
+	}
+	return returns
+}
+
+// Rebase scales a series so that its first value equals base
+func Rebase(values []float64, base float64) []float64 {
+	rebased := make([]float64, len(values))
+	if len(values) == 0 || values[0] == 0 {
+		return rebased
+	}
+	for i, v := range values {
+		rebased[i] = v * base / values[0]
+	}
+	return rebased
+}
+
+// PeriodStart returns the first day of the ISO week (Monday) or calendar month
+// holding t; daily periods start on t itself
+func PeriodStart(t time.Time, period string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch period {
+	case PeriodWeekly:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case PeriodMonthly:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// NextPeriodStart returns the first day of the period following the one starting at start
+func NextPeriodStart(start time.Time, period string) time.Time {
+	switch period {
+	case PeriodWeekly:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Resample keeps the first value of the series followed by the last value of
+// every ISO week or calendar month, so the first return covers the partial
+// first period; daily series are returned unchanged. dates must be ascending.
+func Resample(dates []time.Time, values []float64, period string) ([]time.Time, []float64) {
+	if period != PeriodWeekly && period != PeriodMonthly {
+		return dates, values
+	}
+
+	key := func(t time.Time) int {
+		if period == PeriodWeekly {
+			year, week := t.ISOWeek()
+			return year*100 + week
+		}
+		return t.Year()*100 + int(t.Month())
+	}
+
+	outDates := make([]time.Time, 0)
+	outValues := make([]float64, 0)
+	for i := range dates {
+		if n := len(outDates); n > 1 && key(outDates[n-1]) == key(dates[i]) {
+			outDates[n-1] = dates[i]
+			outValues[n-1] = values[i]
+			continue
+		}
+		outDates = append(outDates, dates[i])
+		outValues = append(outValues, values[i])
+	}
+	return outDates, outValues
+}
+
+// Drawdown describes the largest peak-to-trough decline of a series
+type Drawdown struct {
+	Depth       float64 // fraction of the peak lost, 0 when the series never declined
+	PeakIndex   int
+	TroughIndex int
+}
+
+// DrawdownTracker finds the maximum drawdown of a series fed one value at a time
+type DrawdownTracker struct {
+	n         int
+	peak      float64
+	peakIndex int
+	max       Drawdown
+}
+
+// Add adds the next value of the series
+func (d *DrawdownTracker) Add(value float64) {
+	if d.n == 0 || value > d.peak {
+		d.peak = value
+		d.peakIndex = d.n
+	} else if d.peak > 0 {
+		if depth := 1 - value/d.peak; depth > d.max.Depth {
+			d.max = Drawdown{Depth: depth, PeakIndex: d.peakIndex, TroughIndex: d.n}
+		}
+	}
+	d.n++
+}
+
+// Max returns the maximum drawdown seen so far
+func (d *DrawdownTracker) Max() Drawdown {
+	return d.max
+}
+
+// Sharpe returns the annualized Sharpe ratio of period returns given an annual
+// risk-free rate (as a fraction); ok is false with fewer than two returns or
+// zero volatility
+func Sharpe(returns []float64, riskFreeRate, periodsPerYear float64) (float64, bool) {
+	periodRiskFree := math.Pow(1+riskFreeRate, 1/periodsPerYear) - 1
+
+	var stats Stats
+	for _, r := range returns {
+		stats.Add(r - periodRiskFree)
+	}
+	stddev, ok := stats.StdDev()
+	if !ok || stddev == 0 {
+		return 0, false
+	}
+	return stats.Mean() / stddev * math.Sqrt(periodsPerYear), true
+}
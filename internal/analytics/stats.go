@@ -0,0 +1,165 @@
+package analytics
+
+import (
+	"math"
+)
+
+// Stats accumulates the mean and variance of a series with Welford's online algorithm
+type Stats struct {
+	n    int
+	mean float64
+	m2   float64
+}
+
+// Add adds one observation
+func (s *Stats) Add(x float64) {
+	s.n++
+	d := x - s.mean
+	s.mean += d / float64(s.n)
+	s.m2 += d * (x - s.mean)
+}
+
+// Count returns the number of observations
+func (s *Stats) Count() int {
+	return s.n
+}
+
+// Mean returns the mean of the observations
+func (s *Stats) Mean() float64 {
+	return s.mean
+}
+
+// StdDev returns the sample standard deviation; ok is false with fewer than two observations
+func (s *Stats) StdDev() (float64, bool) {
+	if s.n < 2 {
+		return 0, false
+	}
+	return math.Sqrt(s.m2 / float64(s.n-1)), true
+}
+
+// PairStats accumulates the means, variances and covariance of paired
+// observations with Welford's online algorithm, so pairs can be added one at
+// a time without keeping the series in memory
+type PairStats struct {
+	n     int
+	meanX float64
+	meanY float64
+	m2X   float64
+	m2Y   float64
+	coXY  float64
+}
+
+// Add adds one (x, y) observation
+func (p *PairStats) Add(x, y float64) {
+	p.n++
+	n := float64(p.n)
+	dx := x - p.meanX
+	p.meanX += dx / n
+	dy := y - p.meanY
+	p.meanY += dy / n
+	p.m2X += dx * (x - p.meanX)
+	p.m2Y += dy * (y - p.meanY)
+	p.coXY += dx * (y - p.meanY)
+}
+
+// Count returns the number of observations
+func (p *PairStats) Count() int {
+	return p.n
+}
+
+// Covariance returns the sample covariance of x and y; ok is false with fewer
+// than two observations
+func (p *PairStats) Covariance() (float64, bool) {
+	if p.n < 2 {
+		return 0, false
+	}
+	return p.coXY / float64(p.n-1), true
+}
+
+// Correlation returns the Pearson correlation of x and y; ok is false with
+// fewer than two observations or when either series is constant
+func (p *PairStats) Correlation() (float64, bool) {
+	if p.n < 2 || p.m2X == 0 || p.m2Y == 0 {
+		return 0, false
+	}
+	corr := p.coXY / math.Sqrt(p.m2X*p.m2Y)
+	// Clamp rounding noise
+	return math.Max(-1, math.Min(1, corr)), true
+}
+
+// Beta returns the sensitivity of x to y, cov(x, y) / var(y), treating y as the
+// benchmark; ok is false with fewer than two observations or a constant y
+func (p *PairStats) Beta() (float64, bool) {
+	if p.n < 2 || p.m2Y == 0 {
+		return 0, false
+	}
+	return p.coXY / p.m2Y, true
+}
+
+// CorrelationAccumulator accumulates the pairwise statistics of several series
+// observed together, one period at a time
+type CorrelationAccumulator struct {
+	size  int
+	pairs []PairStats // upper triangle including the diagonal, row by row
+}
+
+// NewCorrelationAccumulator creates an accumulator for size series
+func NewCorrelationAccumulator(size int) *CorrelationAccumulator {
+	return &CorrelationAccumulator{
+		size:  size,
+		pairs: make([]PairStats, size*(size+1)/2),
+	}
+}
+
+// Add adds the observations of one period, one value per series
+func (a *CorrelationAccumulator) Add(values []float64) {
+	k := 0
+	for i := 0; i < a.size; i++ {
+		for j := i; j < a.size; j++ {
+			a.pairs[k].Add(values[i], values[j])
+			k++
+		}
+	}
+}
+
+// Matrix returns the pairwise Pearson correlations; undefined entries
+// (constant or too short series) are nil
+func (a *CorrelationAccumulator) Matrix() [][]*float64 {
+	matrix := make([][]*float64, a.size)
+	for i := range matrix {
+		matrix[i] = make([]*float64, a.size)
+	}
+
+	k := 0
+	for i := 0; i < a.size; i++ {
+		for j := i; j < a.size; j++ {
+			if corr, ok := a.pairs[k].Correlation(); ok {
+				if i == j {
+					corr = 1
+				}
+				matrix[i][j] = &corr
+				matrix[j][i] = &corr
+			}
+			k++
+		}
+	}
+	return matrix
+}
+
+// CorrelationMatrix returns the pairwise Pearson correlations of equally long
+// series. Undefined entries (constant or too short series) are nil.
+func CorrelationMatrix(series [][]float64) [][]*float64 {
+	acc := NewCorrelationAccumulator(len(series))
+	if len(series) == 0 {
+		return acc.Matrix()
+	}
+
+	values := make([]float64, len(series))
+	for k := range series[0] {
+		for i := range series {
+			values[i] = series[i][k]
+		}
+		acc.Add(values)
+	}
+	return acc.Matrix()
+}
@@ -0,0 +1,107 @@
+// Package bench holds the benchmarks of the ingest stages that run without a
+// database. The BenchmarkXxx functions next to each stage run them on
+// DefaultOptions for `go test -bench`, and `loadgen bench` runs them on the
+// bars of its flags.
+package bench
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-historical-data/internal/ingest"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/synthetic"
+	"github.com/go-historical-data/pkg/csvparser"
+)
+
+// Fixture is the synthetic data every operation of a benchmark processes
+type Fixture struct {
+	Options synthetic.Options
+	CSV     []byte                 // upload CSV of the bars
+	Bars    []model.HistoricalData // the bars, in generation order
+}
+
+// DefaultOptions selects the bars of the go test benchmarks: 10 symbols over
+// a year, about 2,600 rows. They never change, so results compare across
+// commits.
+func DefaultOptions() synthetic.Options {
+	return synthetic.Options{
+		Symbols: synthetic.Symbols(10),
+		Start:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		Seed:    1,
+	}
+}
+
+// NewFixture generates the CSV and bars selected by opts
+func NewFixture(opts synthetic.Options) (*Fixture, error) {
+	var file bytes.Buffer
+	if _, err := synthetic.WriteCSV(&file, synthetic.NewGenerator(opts)); err != nil {
+		return nil, err
+	}
+
+	g := synthetic.NewGenerator(opts)
+	bars := make([]model.HistoricalData, 0, g.Rows())
+	for {
+		bar, ok := g.Next()
+		if !ok {
+			break
+		}
+		bars = append(bars, bar)
+	}
+	return &Fixture{Options: opts, CSV: file.Bytes(), Bars: bars}, nil
+}
+
+// Rows returns the number of rows each operation processes
+func (f *Fixture) Rows() int {
+	return len(f.Bars)
+}
+
+// Generate benchmarks writing the CSV of the fixture's bars
+func (f *Fixture) Generate(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(f.CSV)))
+	for i := 0; i < b.N; i++ {
+		if _, err := synthetic.WriteCSV(io.Discard, synthetic.NewGenerator(f.Options)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Decode benchmarks parsing the fixture's CSV with the upload parser
+func (f *Fixture) Decode(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(f.CSV)))
+	for i := 0; i < b.N; i++ {
+		parser := csvparser.NewParser(bytes.NewReader(f.CSV))
+		if err := parser.ParseHeader(); err != nil {
+			b.Fatal(err)
+		}
+		for {
+			if _, err := parser.ParseRow(); err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// Transform benchmarks applying the built-in transforms to the fixture's bars
+func (f *Fixture) Transform(b *testing.B) {
+	b.ReportAllocs()
+	chain := ingest.Chain(ingest.Builtins())
+	bars := make([]model.HistoricalData, len(f.Bars))
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(bars, f.Bars)
+		b.StartTimer()
+		for j := range bars {
+			if err := chain.Apply(&bars[j]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
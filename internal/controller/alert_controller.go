@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AlertController handles alert rule endpoints
+type AlertController struct {
+	service   service.AlertService
+	validator *validator.Validator
+}
+
+// NewAlertController creates a new alert controller instance
+func NewAlertController(service service.AlertService, validator *validator.Validator) *AlertController {
+	return &AlertController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// CreateRule handles POST /api/v1/alerts - Register an alert rule
+func (h *AlertController) CreateRule(c *fiber.Ctx) error {
+	var req request.AlertRuleRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	rule, err := h.service.CreateRule(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{rule.Symbol})
+	middleware.SetAuditResourceIDs(c, rule.ID)
+
+	return response.Created(c, rule)
+}
+
+// GetRules handles GET /api/v1/alerts - List the caller's alert rules
+func (h *AlertController) GetRules(c *fiber.Ctx) error {
+	var req request.GetAlertRulesRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetRules(c.UserContext(), middleware.GetTenant(c), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetRule handles GET /api/v1/alerts/:id - Retrieve an alert rule
+func (h *AlertController) GetRule(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	rule, err := h.service.GetRule(c.UserContext(), middleware.GetTenant(c), id)
+	if err != nil {
+		return err
+	}
+
+	if rule == nil {
+		return apperror.NotFound("Alert rule not found")
+	}
+
+	return response.Success(c, rule)
+}
+
+// UpdateRule handles PUT /api/v1/alerts/:id - Replace the settings of an alert rule
+func (h *AlertController) UpdateRule(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	var req request.AlertRuleRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	rule, err := h.service.UpdateRule(c.UserContext(), middleware.GetTenant(c), id, &req)
+	if err != nil {
+		return err
+	}
+
+	if rule == nil {
+		return apperror.NotFound("Alert rule not found")
+	}
+
+	middleware.SetAuditSymbols(c, []string{rule.Symbol})
+	middleware.SetAuditResourceIDs(c, rule.ID)
+
+	return response.Success(c, rule)
+}
+
+// DeleteRule handles DELETE /api/v1/alerts/:id - Delete an alert rule and its history
+func (h *AlertController) DeleteRule(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	rule, err := h.service.DeleteRule(c.UserContext(), middleware.GetTenant(c), id)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return apperror.NotFound("Alert rule not found")
+	}
+
+	middleware.SetAuditSymbols(c, []string{rule.Symbol})
+	middleware.SetAuditResourceIDs(c, rule.ID)
+
+	return response.NoContent(c)
+}
+
+// GetEvents handles GET /api/v1/alerts/:id/events - List the times an alert rule fired
+func (h *AlertController) GetEvents(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	var req request.GetAlertEventsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetEvents(c.UserContext(), middleware.GetTenant(c), id, &req)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return apperror.NotFound("Alert rule not found")
+	}
+
+	return response.Success(c, result)
+}
@@ -0,0 +1,254 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AnalyticsController handles endpoints computing statistics from stored series
+type AnalyticsController struct {
+	service   service.AnalyticsService
+	validator *validator.Validator
+}
+
+// NewAnalyticsController creates a new analytics controller instance
+func NewAnalyticsController(service service.AnalyticsService, validator *validator.Validator) *AnalyticsController {
+	return &AnalyticsController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// Compare handles GET /api/v1/compare - Align several symbols and summarize them
+func (h *AnalyticsController) Compare(c *fiber.Ctx) error {
+	var req request.CompareRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.Compare(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Dates)*len(result.Symbols))
+
+	return response.Success(c, result)
+}
+
+// Correlation handles GET /api/v1/analytics/correlation - Return correlation
+// matrix and benchmark betas over a trailing window
+func (h *AnalyticsController) Correlation(c *fiber.Ctx) error {
+	var req request.CorrelationRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.Correlation(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, (result.Observations+1)*len(result.Symbols))
+
+	return response.Success(c, result)
+}
+
+// Returns handles GET /api/v1/data/:symbol/returns - Return period returns,
+// cumulative return, max drawdown and Sharpe ratio of a symbol
+func (h *AnalyticsController) Returns(c *fiber.Ctx) error {
+	symbol := strings.ToUpper(c.Params("symbol"))
+	if err := h.validator.ValidateVar("symbol", symbol, "required,ticker"); err != nil {
+		return err
+	}
+
+	var req request.ReturnsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{symbol})
+
+	// Call service
+	result, err := h.service.Returns(c.UserContext(), symbol, &req)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return apperror.NotFound("No data found for symbol")
+	}
+
+	middleware.SetRowsRead(c, len(result.Returns)+1)
+
+	return response.Success(c, result)
+}
+
+// Distribution handles GET /api/v1/data/:symbol/returns/distribution - Return
+// the histogram and percentiles of a symbol's period returns
+func (h *AnalyticsController) Distribution(c *fiber.Ctx) error {
+	symbol := strings.ToUpper(c.Params("symbol"))
+	if err := h.validator.ValidateVar("symbol", symbol, "required,ticker"); err != nil {
+		return err
+	}
+
+	var req request.DistributionRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{symbol})
+
+	// Call service
+	result, err := h.service.Distribution(c.UserContext(), symbol, &req)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return apperror.NotFound("Not enough data found for symbol")
+	}
+
+	middleware.SetRowsRead(c, result.Observations+1)
+
+	return response.Success(c, result)
+}
+
+// Chart handles GET /api/v1/data/:symbol/chart - Return a symbol's metric
+// downsampled for charting
+func (h *AnalyticsController) Chart(c *fiber.Ctx) error {
+	symbol := strings.ToUpper(c.Params("symbol"))
+	if err := h.validator.ValidateVar("symbol", symbol, "required,ticker"); err != nil {
+		return err
+	}
+
+	var req request.ChartRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{symbol})
+
+	// Call service
+	result, err := h.service.Chart(c.UserContext(), symbol, &req)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return apperror.NotFound("No data found for symbol")
+	}
+
+	middleware.SetRowsRead(c, result.TotalPoints)
+
+	return response.Success(c, result)
+}
+
+// Aggregates handles GET /api/v1/data/:symbol/aggregates - Return a symbol's
+// weekly or monthly OHLCV bars
+func (h *AnalyticsController) Aggregates(c *fiber.Ctx) error {
+	symbol := strings.ToUpper(c.Params("symbol"))
+	if err := h.validator.ValidateVar("symbol", symbol, "required,ticker"); err != nil {
+		return err
+	}
+
+	var req request.AggregatesRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{symbol})
+
+	// Call service
+	result, err := h.service.Aggregates(c.UserContext(), symbol, &req)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return apperror.NotFound("No data found for symbol")
+	}
+
+	middleware.SetRowsRead(c, len(result.Bars))
+
+	return response.Success(c, result)
+}
+
+// Backtest handles POST /api/v1/backtests - Run a strategy against a symbol's
+// stored data and return its trades, equity curve and summary
+func (h *AnalyticsController) Backtest(c *fiber.Ctx) error {
+	var req request.BacktestRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{strings.ToUpper(req.Symbol)})
+
+	// Call service
+	result, err := h.service.Backtest(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return apperror.NotFound("No data found for symbol")
+	}
+
+	middleware.SetRowsRead(c, len(result.EquityCurve)+result.Parameters.SlowWindow)
+
+	return response.Success(c, result)
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditController handles audit log endpoints
+type AuditController struct {
+	service   service.AuditService
+	validator *validator.Validator
+}
+
+// NewAuditController creates a new audit controller instance
+func NewAuditController(service service.AuditService, validator *validator.Validator) *AuditController {
+	return &AuditController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetAuditLogs handles GET /admin/audit-logs - Query the audit log
+func (h *AuditController) GetAuditLogs(c *fiber.Ctx) error {
+	var req request.GetAuditLogsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Validate time range
+	if err := req.Validate(); err != nil {
+		return apperror.BadRequest(err.Error(), nil)
+	}
+
+	// Call service
+	result, err := h.service.GetAuditLogs(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
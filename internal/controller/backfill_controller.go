@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BackfillController handles backfill orchestration endpoints
+type BackfillController struct {
+	service   service.BackfillService
+	validator *validator.Validator
+}
+
+// NewBackfillController creates a new backfill controller instance
+func NewBackfillController(service service.BackfillService, validator *validator.Validator) *BackfillController {
+	return &BackfillController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// CreateBackfill handles POST /api/v1/backfills - Queue a backfill for a list of symbols
+func (h *BackfillController) CreateBackfill(c *fiber.Ctx) error {
+	var req request.CreateBackfillRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	backfill, err := h.service.CreateBackfill(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, req.Symbols)
+	middleware.SetAuditResourceIDs(c, backfill.ID)
+
+	return response.Created(c, backfill)
+}
+
+// GetBackfills handles GET /api/v1/backfills - List backfills
+func (h *BackfillController) GetBackfills(c *fiber.Ctx) error {
+	var req request.GetBackfillsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetBackfills(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetBackfill handles GET /api/v1/backfills/:id - Retrieve a backfill and its chunk counts
+func (h *BackfillController) GetBackfill(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	result, err := h.service.GetBackfill(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return apperror.NotFound("Backfill not found")
+	}
+
+	return response.Success(c, result)
+}
+
+// GetBackfillChunks handles GET /api/v1/backfills/:id/chunks - List per-chunk status
+func (h *BackfillController) GetBackfillChunks(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	var req request.GetBackfillChunksRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetBackfillChunks(c.UserContext(), id, &req)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return apperror.NotFound("Backfill not found")
+	}
+
+	return response.Success(c, result)
+}
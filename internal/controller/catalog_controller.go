@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CatalogController handles the symbols catalog and latest quote endpoints,
+// served from the per-symbol summaries
+type CatalogController struct {
+	service   service.SymbolSummaryService
+	validator *validator.Validator
+}
+
+// NewCatalogController creates a new catalog controller instance
+func NewCatalogController(service service.SymbolSummaryService, validator *validator.Validator) *CatalogController {
+	return &CatalogController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetCatalog handles GET /api/v1/catalog - List the coverage of every symbol with bars
+func (h *CatalogController) GetCatalog(c *fiber.Ctx) error {
+	var req request.GetCatalogRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetCatalog(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetSummary handles GET /api/v1/symbols/:symbol/summary - Retrieve the latest
+// close and the coverage of a symbol
+func (h *CatalogController) GetSummary(c *fiber.Ctx) error {
+	summary, err := h.service.GetSummary(c.UserContext(), c.Params("symbol"))
+	if err != nil {
+		return err
+	}
+	if summary == nil {
+		return apperror.NotFound("Symbol has no data")
+	}
+
+	return response.Success(c, summary)
+}
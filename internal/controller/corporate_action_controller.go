@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CorporateActionController handles split and dividend endpoints
+type CorporateActionController struct {
+	service   service.CorporateActionService
+	validator *validator.Validator
+}
+
+// NewCorporateActionController creates a new corporate action controller instance
+func NewCorporateActionController(service service.CorporateActionService, validator *validator.Validator) *CorporateActionController {
+	return &CorporateActionController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetActions handles GET /api/v1/symbols/:symbol/actions - List the corporate actions of a symbol
+func (h *CorporateActionController) GetActions(c *fiber.Ctx) error {
+	actions, err := h.service.GetActions(c.UserContext(), c.Params("symbol"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, actions)
+}
+
+// CreateAction handles POST /api/v1/symbols/:symbol/actions - Record a split or dividend
+func (h *CorporateActionController) CreateAction(c *fiber.Ctx) error {
+	var req request.CreateCorporateActionRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	symbol := c.Params("symbol")
+	if err := h.validator.ValidateVar("symbol", symbol, "required,ticker"); err != nil {
+		return err
+	}
+
+	// Call service
+	action, err := h.service.CreateAction(c.UserContext(), symbol, &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{action.Symbol})
+	middleware.SetAuditResourceIDs(c, action.ID)
+
+	return response.Created(c, action)
+}
+
+// DeleteAction handles DELETE /api/v1/symbols/:symbol/actions/:id - Remove a corporate action
+func (h *CorporateActionController) DeleteAction(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	action, err := h.service.DeleteAction(c.UserContext(), c.Params("symbol"), id)
+	if err != nil {
+		return err
+	}
+	if action == nil {
+		return apperror.NotFound("Corporate action not found")
+	}
+
+	middleware.SetAuditSymbols(c, []string{action.Symbol})
+	middleware.SetAuditResourceIDs(c, action.ID)
+
+	return response.NoContent(c)
+}
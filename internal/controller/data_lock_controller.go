@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DataLockController handles data lock endpoints
+type DataLockController struct {
+	service   service.DataLockService
+	validator *validator.Validator
+}
+
+// NewDataLockController creates a new data lock controller instance
+func NewDataLockController(service service.DataLockService, validator *validator.Validator) *DataLockController {
+	return &DataLockController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetLocks handles GET /admin/locks - List frozen symbol and date ranges
+func (h *DataLockController) GetLocks(c *fiber.Ctx) error {
+	var req request.GetDataLocksRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetLocks(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// CreateLock handles POST /admin/locks - Freeze the bars of a symbol between two dates
+func (h *DataLockController) CreateLock(c *fiber.Ctx) error {
+	var req request.CreateDataLockRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	lock, err := h.service.CreateLock(c.UserContext(), middleware.GetAPIKeyName(c), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{lock.Symbol})
+	middleware.SetAuditResourceIDs(c, lock.ID)
+
+	return response.Created(c, lock)
+}
+
+// DeleteLock handles DELETE /admin/locks/:id - Unfreeze a range
+func (h *DataLockController) DeleteLock(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	lock, err := h.service.DeleteLock(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return apperror.NotFound("Data lock not found")
+	}
+
+	middleware.SetAuditSymbols(c, []string{lock.Symbol})
+	middleware.SetAuditResourceIDs(c, lock.ID)
+
+	return response.NoContent(c)
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EarningsController handles earnings calendar endpoints
+type EarningsController struct {
+	service   service.EarningsService
+	validator *validator.Validator
+}
+
+// NewEarningsController creates a new earnings controller instance
+func NewEarningsController(service service.EarningsService, validator *validator.Validator) *EarningsController {
+	return &EarningsController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetEarnings handles GET /api/v1/earnings - List earnings events by report date
+func (h *EarningsController) GetEarnings(c *fiber.Ctx) error {
+	var req request.GetEarningsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetEarnings(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Data))
+
+	return response.Success(c, result)
+}
+
+// UploadEarnings handles POST /api/v1/earnings - Record earnings events (admin)
+func (h *EarningsController) UploadEarnings(c *fiber.Ctx) error {
+	var req request.UploadEarningsRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.Upload(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, result.Symbols)
+
+	return response.Success(c, result)
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"errors"
+	"path"
+	"strconv"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/internal/storage"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportController handles bulk export endpoints
+type ExportController struct {
+	service   service.ExportService
+	local     *storage.LocalStore
+	validator *validator.Validator
+}
+
+// NewExportController creates a new export controller instance. local serves
+// the download links of local storage and is nil with other stores.
+func NewExportController(service service.ExportService, local *storage.LocalStore, validator *validator.Validator) *ExportController {
+	return &ExportController{
+		service:   service,
+		local:     local,
+		validator: validator,
+	}
+}
+
+// CreateExport handles POST /api/v1/exports - Queue an export of a list of symbols
+func (h *ExportController) CreateExport(c *fiber.Ctx) error {
+	var req request.CreateExportRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	job, err := h.service.CreateExport(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, req.Symbols)
+	middleware.SetAuditResourceIDs(c, job.ID)
+
+	return response.Created(c, job)
+}
+
+// GetExports handles GET /api/v1/exports - List export jobs of the calling
+// tenant. Admins may inspect another tenant via ?tenant=.
+func (h *ExportController) GetExports(c *fiber.Ctx) error {
+	var req request.GetExportsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req, "tenant"); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	tenant := middleware.GetTenant(c)
+	if other := c.Query("tenant"); other != "" && other != tenant {
+		if !middleware.IsAdmin(c) {
+			return apperror.Forbidden("Only admins can view other tenants' exports")
+		}
+		tenant = other
+	}
+
+	// Call service
+	result, err := h.service.GetExports(c.UserContext(), tenant, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetExport handles GET /api/v1/exports/:id - Retrieve the status of an
+// export with its download link once completed
+func (h *ExportController) GetExport(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	job, err := h.service.GetExport(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	// Exports of other tenants are reported as missing to non-admins
+	if job == nil || (job.Tenant != middleware.GetTenant(c) && !middleware.IsAdmin(c)) {
+		return apperror.NotFound("Export not found")
+	}
+
+	return response.Success(c, job)
+}
+
+// Download handles GET /downloads/* - Serve an artifact of local storage. The
+// signed link is the credential, so the route sits outside API key auth.
+func (h *ExportController) Download(c *fiber.Ctx) error {
+	key := c.Params("*")
+	file, err := h.local.Open(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidSignature) {
+			return apperror.Forbidden("Download link is invalid or expired")
+		}
+		return apperror.NotFound("Export not found")
+	}
+
+	return c.Download(file, path.Base(key))
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/features"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeatureController handles feature flag endpoints
+type FeatureController struct {
+	flags *features.Flags
+}
+
+// NewFeatureController creates a new feature controller instance
+func NewFeatureController(flags *features.Flags) *FeatureController {
+	return &FeatureController{
+		flags: flags,
+	}
+}
+
+// GetFeatures handles GET /admin/features - Current value of every flag set in
+// the configuration or by the remote provider
+func (h *FeatureController) GetFeatures(c *fiber.Ctx) error {
+	return response.Success(c, h.flags.All())
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"io"
+	"strings"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/csvparser"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FundamentalController handles fundamentals endpoints
+type FundamentalController struct {
+	service   service.FundamentalService
+	usage     service.UsageService
+	validator *validator.Validator
+}
+
+// NewFundamentalController creates a new fundamental controller instance
+func NewFundamentalController(service service.FundamentalService, usage service.UsageService, validator *validator.Validator) *FundamentalController {
+	return &FundamentalController{
+		service:   service,
+		usage:     usage,
+		validator: validator,
+	}
+}
+
+// UploadFundamentals handles POST /api/v1/fundamentals - Upload fundamentals
+// as a JSON body (application/json) or a CSV file (multipart/form-data)
+func (h *FundamentalController) UploadFundamentals(c *fiber.Ctx) error {
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	if strings.EqualFold(strings.TrimSpace(mediaType), fiber.MIMEApplicationJSON) {
+		return h.uploadJSON(c)
+	}
+
+	// Parse multipart form
+	file, err := c.FormFile("file")
+	if err != nil {
+		return apperror.BadRequest("No file uploaded", err.Error())
+	}
+
+	// Validate file type
+	contentType := file.Header.Get("Content-Type")
+	if contentType != "text/csv" && contentType != "application/vnd.ms-excel" && contentType != "application/csv" {
+		// Also check file extension as a fallback
+		if len(file.Filename) < 4 || file.Filename[len(file.Filename)-4:] != ".csv" {
+			return apperror.BadRequest("Invalid file type", "Only CSV files are allowed")
+		}
+	}
+
+	uploadInfo := service.UploadInfo{
+		Filename: file.Filename,
+		FileSize: file.Size,
+		Tenant:   middleware.GetTenant(c),
+		APIKey:   middleware.GetAPIKeyName(c),
+	}
+
+	// Validate file size
+	if err := h.service.ValidateUpload(uploadInfo); err != nil {
+		return apperror.PayloadTooLarge(err.Error())
+	}
+
+	// Open file
+	fileReader, err := file.Open()
+	if err != nil {
+		return apperror.Internal("Failed to read file")
+	}
+	defer fileReader.Close()
+
+	// Reject uploads that would exceed the tenant's monthly row quota
+	rows, err := csvparser.CountRows(fileReader)
+	if err != nil {
+		return apperror.BadRequest("Failed to read file", err.Error())
+	}
+	if _, err := fileReader.Seek(0, io.SeekStart); err != nil {
+		return apperror.Internal("Failed to read file")
+	}
+	if err := h.usage.CheckIngestQuota(c.UserContext(), middleware.GetTenant(c), rows); err != nil {
+		return err
+	}
+
+	// Process CSV file
+	result, err := h.service.UploadCSV(c.UserContext(), fileReader, uploadInfo)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsIngested(c, result.SuccessCount)
+	middleware.SetAuditSymbols(c, result.Symbols)
+	middleware.SetAuditResourceIDs(c, result.SourceID)
+
+	return response.Success(c, result)
+}
+
+// uploadJSON stores the fundamentals of a JSON body
+func (h *FundamentalController) uploadJSON(c *fiber.Ctx) error {
+	var req request.UploadFundamentalsRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Reject uploads that would exceed the tenant's monthly row quota
+	if err := h.usage.CheckIngestQuota(c.UserContext(), middleware.GetTenant(c), int64(len(req.Fundamentals))); err != nil {
+		return err
+	}
+
+	// Call service
+	// A JSON body has no filename to record its source under
+	result, err := h.service.Upload(c.UserContext(), &req, service.UploadInfo{
+		Filename: "fundamentals.json",
+		Tenant:   middleware.GetTenant(c),
+		APIKey:   middleware.GetAPIKeyName(c),
+	})
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsIngested(c, result.SuccessCount)
+	middleware.SetAuditSymbols(c, result.Symbols)
+	middleware.SetAuditResourceIDs(c, result.SourceID)
+
+	return response.Success(c, result)
+}
+
+// GetFundamentals handles GET /api/v1/symbols/:symbol/fundamentals - Retrieve
+// the latest reported periods of a symbol with its P/E and market cap
+func (h *FundamentalController) GetFundamentals(c *fiber.Ctx) error {
+	var req request.GetFundamentalsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetFundamentals(c.UserContext(), c.Params("symbol"), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Periods))
+	middleware.SetAuditSymbols(c, []string{result.Symbol})
+
+	return response.Success(c, result)
+}
@@ -1,16 +1,27 @@
 package controller
 
 import (
+	"context"
+	"time"
+
+	"github.com/go-historical-data/pkg/database"
 	"github.com/go-historical-data/pkg/response"
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
 // HealthController handles health check endpoints
-type HealthController struct{}
+type HealthController struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
 
 // NewHealthController creates a new health controller instance
-func NewHealthController() *HealthController {
-	return &HealthController{}
+func NewHealthController(db *gorm.DB, res *database.Resilience) *HealthController {
+	return &HealthController{
+		db:  db,
+		res: res,
+	}
 }
 
 // HealthCheckResponse represents the health check response
@@ -20,6 +31,12 @@ type HealthCheckResponse struct {
 	Version string `json:"version"`
 }
 
+// ReadinessResponse represents the readiness check response
+type ReadinessResponse struct {
+	Status     string            `json:"status"`
+	Components map[string]string `json:"components"`
+}
+
 // Check handles GET /health endpoint
 func (h *HealthController) Check(c *fiber.Ctx) error {
 	return response.Success(c, HealthCheckResponse{
@@ -28,3 +45,38 @@ func (h *HealthController) Check(c *fiber.Ctx) error {
 		Version: "1.0.0",
 	})
 }
+
+// Ready handles GET /health/ready endpoint. It reports not ready (503) when the
+// database cannot be pinged or the database circuit breaker is open.
+func (h *HealthController) Ready(c *fiber.Ctx) error {
+	result := ReadinessResponse{
+		Status: "ready",
+		Components: map[string]string{
+			"database":        "up",
+			"circuit_breaker": h.res.State(),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 2*time.Second)
+	defer cancel()
+
+	sqlDB, err := h.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		result.Components["database"] = "down"
+		result.Status = "not_ready"
+	}
+	if result.Components["circuit_breaker"] == "open" {
+		result.Status = "not_ready"
+	}
+
+	if result.Status != "ready" {
+		return response.Respond(c, fiber.StatusServiceUnavailable, response.SuccessResponse{
+			Success: false,
+			Data:    result,
+		})
+	}
+	return response.Success(c, result)
+}
@@ -1,12 +1,24 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-historical-data/internal/dto/request"
+	dto "github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/export"
 	"github.com/go-historical-data/internal/middleware"
 	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/internal/synthetic"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/csvparser"
 	"github.com/go-historical-data/pkg/response"
 	"github.com/go-historical-data/pkg/validator"
 	"github.com/gofiber/fiber/v2"
@@ -15,14 +27,19 @@ import (
 // HistoricalController handles historical data endpoints
 type HistoricalController struct {
 	service   service.HistoricalService
+	usage     service.UsageService
 	validator *validator.Validator
+	mapper    HistoricalMapper
 }
 
-// NewHistoricalController creates a new historical controller instance
-func NewHistoricalController(service service.HistoricalService, validator *validator.Validator) *HistoricalController {
+// NewHistoricalController creates a new historical controller instance serving
+// the response shape of the given mapper's API version
+func NewHistoricalController(service service.HistoricalService, usage service.UsageService, validator *validator.Validator, mapper HistoricalMapper) *HistoricalController {
 	return &HistoricalController{
 		service:   service,
+		usage:     usage,
 		validator: validator,
+		mapper:    mapper,
 	}
 }
 
@@ -31,30 +48,53 @@ func (h *HistoricalController) GetData(c *fiber.Ctx) error {
 	var req request.GetDataRequest
 
 	// Parse query parameters
-	if err := c.QueryParser(&req); err != nil {
-		return response.BadRequest(c, "Invalid query parameters", err.Error())
+	if err := binder.Query(c, &req, "format"); err != nil {
+		return err
 	}
 
 	// Validate request
 	if err := h.validator.Validate(&req); err != nil {
-		if validationErr, ok := err.(*validator.ValidationError); ok {
-			return response.ValidationError(c, "Validation failed", validationErr.GetErrors())
-		}
-		return response.BadRequest(c, "Validation failed", err.Error())
+		return err
 	}
 
 	// Validate date range
 	if err := req.Validate(); err != nil {
-		return response.BadRequest(c, err.Error(), nil)
+		return apperror.BadRequest(err.Error(), nil)
 	}
 
 	// Call service
 	result, err := h.service.GetHistoricalData(c.UserContext(), &req)
 	if err != nil {
-		return response.InternalServerError(c, err.Error())
+		return err
 	}
 
-	return response.Success(c, result)
+	middleware.SetRowsRead(c, len(result.Data))
+
+	if wantsCSV(c) {
+		c.Set("X-Page", strconv.Itoa(result.Pagination.Page))
+		if result.Pagination.HasNext != nil {
+			c.Set("X-Has-Next", strconv.FormatBool(*result.Pagination.HasNext))
+		} else {
+			c.Set("X-Total-Count", strconv.FormatInt(result.Pagination.TotalItems, 10))
+			c.Set("X-Total-Pages", strconv.Itoa(result.Pagination.TotalPages))
+		}
+		columns := result.Fields
+		if req.WantsDerived() {
+			if len(columns) == 0 {
+				columns = export.DefaultCSVColumns
+			}
+			columns = append(slices.Clone(columns), export.DerivedCSVColumns...)
+		}
+		if req.WantsEarnings() {
+			if len(columns) == 0 {
+				columns = export.DefaultCSVColumns
+			}
+			columns = append(slices.Clone(columns), export.EarningsCSVColumns...)
+		}
+		return sendCSV(c, result.Data, columns, "historical_data.csv")
+	}
+
+	return response.Success(c, h.mapper.MapList(result))
 }
 
 // GetDataByID handles GET /api/v1/data/:id - Retrieve historical data by ID
@@ -63,28 +103,224 @@ func (h *HistoricalController) GetDataByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseUint(idParam, 10, 64)
 	if err != nil {
-		return response.BadRequest(c, "Invalid ID parameter", err.Error())
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	var req request.GetDataByIDRequest
+	if err := binder.Query(c, &req, "format"); err != nil {
+		return err
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		return err
 	}
 
 	// Call service
-	result, err := h.service.GetHistoricalDataByID(c.UserContext(), id)
+	result, err := h.service.GetHistoricalDataByID(c.UserContext(), id, &req)
 	if err != nil {
-		return response.InternalServerError(c, err.Error())
+		return err
 	}
 
 	if result == nil {
-		return response.NotFound(c, "Historical data not found")
+		return apperror.NotFound("Historical data not found")
+	}
+
+	middleware.SetRowsRead(c, 1)
+
+	if wantsCSV(c) {
+		return sendCSV(c, []dto.HistoricalDataResponse{*result}, nil, fmt.Sprintf("historical_data_%d.csv", id))
+	}
+
+	return response.Success(c, h.mapper.MapOne(result))
+}
+
+// LookupData handles POST /api/v1/data/lookup - Retrieve the bars of a list
+// of (symbol, date) pairs in one round trip
+func (h *HistoricalController) LookupData(c *fiber.Ctx) error {
+	var req request.LookupDataRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.LookupData(c.UserContext(), &req)
+	if err != nil {
+		return err
 	}
 
+	middleware.SetRowsRead(c, len(result.Found))
+
+	return response.Success(c, result)
+}
+
+// AsOf handles GET /api/v1/data/:symbol/asof - Retrieve the last bar of a
+// symbol on or before a date
+func (h *HistoricalController) AsOf(c *fiber.Ctx) error {
+	symbol := strings.ToUpper(c.Params("symbol"))
+	if err := h.validator.ValidateVar("symbol", symbol, "required,ticker"); err != nil {
+		return err
+	}
+
+	var req request.AsOfRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{symbol})
+
+	// Call service
+	result, err := h.service.AsOf(c.UserContext(), symbol, &req)
+	if err != nil {
+		return err
+	}
+	if result.Bar == nil {
+		return apperror.NotFound("No data found for symbol on or before date")
+	}
+
+	middleware.SetRowsRead(c, 1)
+
+	return response.Success(c, result)
+}
+
+// AsOfBatch handles POST /api/v1/data/asof - Retrieve the as-of bars of a
+// list of (symbol, date) pairs in one round trip
+func (h *HistoricalController) AsOfBatch(c *fiber.Ctx) error {
+	var req request.AsOfBatchRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.AsOfBatch(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	rows := 0
+	for _, r := range result.Results {
+		if r.Bar != nil {
+			rows++
+		}
+	}
+	middleware.SetRowsRead(c, rows)
+
+	return response.Success(c, result)
+}
+
+// GetDataOnDate handles GET /api/v1/data/date/:date - Retrieve the bars of
+// every symbol on a date
+func (h *HistoricalController) GetDataOnDate(c *fiber.Ctx) error {
+	date, err := time.Parse("2006-01-02", c.Params("date"))
+	if err != nil {
+		return apperror.BadRequest("Invalid date parameter, expected YYYY-MM-DD", nil)
+	}
+
+	var req request.DataOnDateRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req, "format"); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetDataOnDate(c.UserContext(), date, &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Data))
+
+	if wantsCSV(c) {
+		c.Set("X-Page", strconv.Itoa(result.Pagination.Page))
+		c.Set("X-Total-Count", strconv.FormatInt(result.Pagination.TotalItems, 10))
+		c.Set("X-Total-Pages", strconv.Itoa(result.Pagination.TotalPages))
+		return sendCSV(c, result.Data, nil, fmt.Sprintf("historical_data_%s.csv", date.Format("2006-01-02")))
+	}
+
+	return response.Success(c, h.mapper.MapList(result))
+}
+
+// GetMovers handles GET /api/v1/data/date/:date/movers - Retrieve the
+// symbols with the largest change from their previous close on a date
+func (h *HistoricalController) GetMovers(c *fiber.Ctx) error {
+	date, err := time.Parse("2006-01-02", c.Params("date"))
+	if err != nil {
+		return apperror.BadRequest("Invalid date parameter, expected YYYY-MM-DD", nil)
+	}
+
+	var req request.MoversRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetMovers(c.UserContext(), date, &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Gainers)+len(result.Losers))
+
 	return response.Success(c, result)
 }
 
 // UploadCSV handles POST /api/v1/data - Upload CSV file
 func (h *HistoricalController) UploadCSV(c *fiber.Ctx) error {
+	var req request.UploadCSVRequest
+
+	// Parse ingestion tuning overrides
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	if req.HasOverrides() && !middleware.IsAdmin(c) {
+		return apperror.Forbidden("Only admins can override ingestion settings")
+	}
+	if req.OverrideLocks && !middleware.IsAdmin(c) {
+		return apperror.Forbidden("Only admins can override data locks")
+	}
+
 	// Parse multipart form
 	file, err := c.FormFile("file")
 	if err != nil {
-		return response.BadRequest(c, "No file uploaded", err.Error())
+		return apperror.BadRequest("No file uploaded", err.Error())
 	}
 
 	// Validate file type
@@ -92,34 +328,113 @@ func (h *HistoricalController) UploadCSV(c *fiber.Ctx) error {
 	if contentType != "text/csv" && contentType != "application/vnd.ms-excel" && contentType != "application/csv" {
 		// Also check file extension as a fallback
 		if len(file.Filename) < 4 || file.Filename[len(file.Filename)-4:] != ".csv" {
-			return response.BadRequest(c, "Invalid file type", "Only CSV files are allowed")
+			return apperror.BadRequest("Invalid file type", "Only CSV files are allowed")
 		}
 	}
 
-	// Validate file size (max 50MB)
-	// const maxFileSize = 50 * 1024 * 1024 // 50MB
-	// if file.Size > maxFileSize {
-	// 	return response.BadRequest(c, "File too large", "Maximum file size is 50MB")
-	// }
+	uploadInfo := service.UploadInfo{
+		Filename: file.Filename,
+		FileSize: file.Size,
+		Tenant:   middleware.GetTenant(c),
+		APIKey:   middleware.GetAPIKeyName(c),
+
+		OverrideLocks:     req.OverrideLocks,
+		Transforms:        req.GetTransforms(),
+		CaptureAttributes: req.CaptureAttributes,
+		Staged:            req.Staged,
+		Overrides: config.IngestionConfig{
+			BatchSize:          req.BatchSize,
+			MaxParallelBatches: req.MaxParallelBatches,
+			MaxFileSize:        req.MaxFileSize,
+			MaxErrors:          req.MaxErrors,
+			MaxErrorRate:       req.MaxErrorRate,
+			UnknownSymbols:     req.UnknownSymbols,
+		},
+	}
+
+	// Validate file size and transforms
+	if err := h.service.ValidateUpload(uploadInfo); err != nil {
+		return apperror.PayloadTooLarge(err.Error())
+	}
 
 	// Open file
 	fileReader, err := file.Open()
 	if err != nil {
-		return response.InternalServerError(c, "Failed to read file")
+		return apperror.Internal("Failed to read file")
 	}
 	defer fileReader.Close()
 
+	// Reject uploads that would exceed the tenant's monthly row quota
+	rows, err := csvparser.CountRows(fileReader)
+	if err != nil {
+		return apperror.BadRequest("Failed to read file", err.Error())
+	}
+	if _, err := fileReader.Seek(0, io.SeekStart); err != nil {
+		return apperror.Internal("Failed to read file")
+	}
+	if err := h.usage.CheckIngestQuota(c.UserContext(), middleware.GetTenant(c), rows); err != nil {
+		return err
+	}
+
 	// Track CSV upload duration
 	startTime := time.Now()
 
 	// Process CSV file
-	result, err := h.service.UploadCSV(c.UserContext(), fileReader, file.Size)
+	result, err := h.service.UploadCSV(c.UserContext(), fileReader, uploadInfo)
+	return uploadResponse(c, result, err, time.Since(startTime))
+}
+
+// GenerateData handles POST /admin/data/generate, ingesting random-walk bars
+// for demos, load tests and local development
+func (h *HistoricalController) GenerateData(c *fiber.Ctx) error {
+	var req request.GenerateDataRequest
 
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return apperror.BadRequest("Validation failed", err.Error())
+	}
+
+	// Generated rows count towards the monthly row quota like uploaded ones
+	rows := synthetic.NewGenerator(service.SyntheticOptions(&req)).Rows()
+	if err := h.usage.CheckIngestQuota(c.UserContext(), middleware.GetTenant(c), rows); err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	result, err := h.service.GenerateData(c.UserContext(), &req, service.UploadInfo{
+		Tenant: middleware.GetTenant(c),
+		APIKey: middleware.GetAPIKeyName(c),
+	})
+	return uploadResponse(c, result, err, time.Since(startTime))
+}
+
+// uploadResponse records the metrics and audit details of an ingested file
+// and answers with its result, or returns the reason it was refused
+func uploadResponse(c *fiber.Ctx, result *dto.CSVUploadResponse, err error, duration time.Duration) error {
 	// Record metrics
-	duration := time.Since(startTime)
 	if err != nil {
-		middleware.RecordCSVMetrics(0, 0, duration, "error")
-		return response.InternalServerError(c, err.Error())
+		var malformedErr *service.MalformedFileError
+		var lockedErr *service.LockedDataError
+		switch {
+		case errors.As(err, &malformedErr):
+			middleware.RecordCSVMetrics(malformedErr.SuccessCount, malformedErr.FailedCount, duration, "aborted")
+			middleware.SetRowsIngested(c, malformedErr.SuccessCount)
+			middleware.SetAuditResourceIDs(c, malformedErr.JobID)
+		case errors.As(err, &lockedErr):
+			middleware.RecordCSVMetrics(0, 0, duration, "locked")
+		default:
+			middleware.RecordCSVMetrics(0, 0, duration, "error")
+		}
+		return err
 	}
 
 	// Determine upload status based on errors
@@ -133,6 +448,36 @@ func (h *HistoricalController) UploadCSV(c *fiber.Ctx) error {
 	}
 
 	middleware.RecordCSVMetrics(result.SuccessCount, result.FailedCount, duration, uploadStatus)
+	middleware.SetRowsIngested(c, result.SuccessCount)
+	middleware.SetAuditSymbols(c, result.Symbols)
+	middleware.SetAuditResourceIDs(c, result.JobID)
 
 	return response.Success(c, result)
 }
+
+// wantsCSV reports whether the client negotiated a CSV response, either via
+// ?format=csv or an Accept header preferring text/csv. The format parameter wins.
+func wantsCSV(c *fiber.Ctx) bool {
+	switch strings.ToLower(c.Query("format")) {
+	case "csv":
+		return true
+	case "json":
+		return false
+	}
+	return c.Accepts(fiber.MIMEApplicationJSON, export.ContentTypeCSV) == export.ContentTypeCSV
+}
+
+// sendCSV streams records into the response body as a CSV attachment
+func sendCSV(c *fiber.Ctx, data []dto.HistoricalDataResponse, columns []string, filename string) error {
+	c.Set(fiber.HeaderContentType, export.ContentTypeCSV+"; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	w := export.NewCSVWriter(c.Response().BodyWriter(), columns)
+	if err := w.WriteHeader(); err != nil {
+		return apperror.Internal("Failed to write CSV response")
+	}
+	if err := w.WriteAll(data); err != nil {
+		return apperror.Internal("Failed to write CSV response")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	dto "github.com/go-historical-data/internal/dto/response"
+)
+
+// API versions served by the controllers
+const (
+	APIVersion1 = "v1"
+	APIVersion2 = "v2"
+)
+
+// HistoricalMapper maps service-level DTOs to a versioned response shape
+type HistoricalMapper interface {
+	// Version returns the API version the mapper produces
+	Version() string
+	// MapList maps a paginated result
+	MapList(result *dto.PaginatedHistoricalDataResponse) interface{}
+	// MapOne maps a single record
+	MapOne(result *dto.HistoricalDataResponse) interface{}
+}
+
+// NewHistoricalMapper returns the mapper for the given API version, defaulting to v1
+func NewHistoricalMapper(version string) HistoricalMapper {
+	if version == APIVersion2 {
+		return v2Mapper{}
+	}
+	return v1Mapper{}
+}
+
+// v1Mapper returns service DTOs unchanged (the original v1 response shape)
+type v1Mapper struct{}
+
+// Version implements HistoricalMapper
+func (v1Mapper) Version() string { return APIVersion1 }
+
+// MapList implements HistoricalMapper
+func (v1Mapper) MapList(result *dto.PaginatedHistoricalDataResponse) interface{} {
+	return result
+}
+
+// MapOne implements HistoricalMapper
+func (v1Mapper) MapOne(result *dto.HistoricalDataResponse) interface{} {
+	return result
+}
+
+// v2Mapper groups prices under "ohlc" and replaces "pagination" with "meta"
+type v2Mapper struct{}
+
+// Version implements HistoricalMapper
+func (v2Mapper) Version() string { return APIVersion2 }
+
+// MapList implements HistoricalMapper
+func (m v2Mapper) MapList(result *dto.PaginatedHistoricalDataResponse) interface{} {
+	var data interface{}
+	if len(result.Fields) > 0 {
+		// Sparse responses stay flat, only carrying the requested fields
+		sparse := make([]map[string]interface{}, len(result.Data))
+		for i := range result.Data {
+			sparse[i] = result.Data[i].Project(result.Fields)
+		}
+		data = sparse
+	} else {
+		items := make([]dto.HistoricalDataV2Response, len(result.Data))
+		for i := range result.Data {
+			items[i] = m.toV2(&result.Data[i])
+		}
+		data = items
+	}
+
+	meta := dto.PageMetaV2{
+		Page:            result.Pagination.Page,
+		Limit:           result.Pagination.Limit,
+		Total:           result.Pagination.TotalItems,
+		TotalPages:      result.Pagination.TotalPages,
+		HasNext:         result.Pagination.Page < result.Pagination.TotalPages,
+		Count:           result.Pagination.Count,
+		EntitledThrough: result.EntitledThrough,
+	}
+	if result.Pagination.HasNext != nil {
+		meta.HasNext = *result.Pagination.HasNext
+	}
+
+	return &dto.PaginatedHistoricalDataV2Response{
+		Data: data,
+		Meta: meta,
+	}
+}
+
+// MapOne implements HistoricalMapper
+func (m v2Mapper) MapOne(result *dto.HistoricalDataResponse) interface{} {
+	item := m.toV2(result)
+	return &item
+}
+
+// toV2 converts a single record to the v2 shape
+func (v2Mapper) toV2(data *dto.HistoricalDataResponse) dto.HistoricalDataV2Response {
+	var ohlc *dto.OHLC
+	if !data.NullFilled() {
+		ohlc = &dto.OHLC{
+			Open:  data.Open,
+			High:  data.High,
+			Low:   data.Low,
+			Close: data.Close,
+		}
+	}
+	return dto.HistoricalDataV2Response{
+		ID:             data.ID,
+		Symbol:         data.Symbol,
+		Date:           data.Date,
+		OHLC:           ohlc,
+		Volume:         data.Volume,
+		OpenInterest:   data.OpenInterest,
+		NumberOfTrades: data.NumberOfTrades,
+		SourceID:       data.SourceID,
+		Currency:       data.Currency,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
+		Derived:        data.Derived,
+		Attributes:     data.Attributes,
+		Earnings:       data.Earnings,
+		Filled:         data.Filled,
+	}
+}
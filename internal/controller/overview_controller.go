@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// OverviewController handles the operational overview endpoints of the admin dashboard
+type OverviewController struct {
+	service   service.OverviewService
+	validator *validator.Validator
+}
+
+// NewOverviewController creates a new overview controller instance
+func NewOverviewController(service service.OverviewService, validator *validator.Validator) *OverviewController {
+	return &OverviewController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetOverview handles GET /admin/overview - Every section of the overview in one response
+func (h *OverviewController) GetOverview(c *fiber.Ctx) error {
+	var req request.GetOverviewRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetOverview(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetRecentUploads handles GET /admin/overview/uploads - Latest upload jobs of every tenant
+func (h *OverviewController) GetRecentUploads(c *fiber.Ctx) error {
+	var req request.GetOverviewRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetRecentUploads(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetQueues handles GET /admin/overview/queues - Unfinished jobs of every queue by status
+func (h *OverviewController) GetQueues(c *fiber.Ctx) error {
+	result, err := h.service.GetQueues(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetFreshness handles GET /admin/overview/freshness - Latest ingest per source
+func (h *OverviewController) GetFreshness(c *fiber.Ctx) error {
+	result, err := h.service.GetFreshness(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetErrorRates handles GET /admin/overview/errors - Ingestion and HTTP error rates
+func (h *OverviewController) GetErrorRates(c *fiber.Ctx) error {
+	result, err := h.service.GetErrorRates(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetCacheStats handles GET /admin/overview/cache - Response cache hit ratios
+func (h *OverviewController) GetCacheStats(c *fiber.Ctx) error {
+	return response.Success(c, h.service.GetCacheStats())
+}
+
+// GetDatabaseStats handles GET /admin/overview/database - Connection pool and circuit breaker
+func (h *OverviewController) GetDatabaseStats(c *fiber.Ctx) error {
+	result, err := h.service.GetDatabaseStats()
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
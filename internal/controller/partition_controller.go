@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PartitionController handles partition maintenance endpoints
+type PartitionController struct {
+	service service.PartitionService
+}
+
+// NewPartitionController creates a new partition controller instance
+func NewPartitionController(service service.PartitionService) *PartitionController {
+	return &PartitionController{
+		service: service,
+	}
+}
+
+// GetPartitions handles GET /admin/partitions - List the partitions of the
+// historical data table and check range pruning
+func (h *PartitionController) GetPartitions(c *fiber.Ctx) error {
+	result, err := h.service.GetPartitions(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// MaintainPartitions handles POST /admin/partitions/maintain - Create future
+// partitions and drop expired ones now
+func (h *PartitionController) MaintainPartitions(c *fiber.Ctx) error {
+	result, err := h.service.Maintain(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PullController handles pull source admin endpoints
+type PullController struct {
+	service   service.PullService
+	validator *validator.Validator
+}
+
+// NewPullController creates a new pull controller instance
+func NewPullController(service service.PullService, validator *validator.Validator) *PullController {
+	return &PullController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetSources handles GET /admin/pull-sources - List the configured pull
+// sources with their last poll, last error and counters
+func (h *PullController) GetSources(c *fiber.Ctx) error {
+	sources, err := h.service.GetSources(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, sources)
+}
+
+// GetFiles handles GET /admin/pull-sources/:name/files - List the files
+// pulled from a source, newest first
+func (h *PullController) GetFiles(c *fiber.Ctx) error {
+	var req request.GetPullFilesRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetFiles(c.UserContext(), c.Params("name"), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// Poll handles POST /admin/pull-sources/:name/poll - Poll a source now
+// instead of waiting for its interval
+func (h *PullController) Poll(c *fiber.Ctx) error {
+	name := c.Params("name")
+	if err := h.service.Poll(name); err != nil {
+		return err
+	}
+
+	return response.Success(c, fiber.Map{"source": name, "message": "Poll scheduled"})
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"io"
+	"strings"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/csvparser"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// QuoteController handles bid/ask quote endpoints
+type QuoteController struct {
+	service   service.QuoteService
+	usage     service.UsageService
+	validator *validator.Validator
+}
+
+// NewQuoteController creates a new quote controller instance
+func NewQuoteController(service service.QuoteService, usage service.UsageService, validator *validator.Validator) *QuoteController {
+	return &QuoteController{
+		service:   service,
+		usage:     usage,
+		validator: validator,
+	}
+}
+
+// GetQuotes handles GET /api/v1/quotes - Retrieve bid/ask quotes with their mid and spread
+func (h *QuoteController) GetQuotes(c *fiber.Ctx) error {
+	var req request.GetQuotesRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+	req.Symbol = strings.ToUpper(req.Symbol)
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetQuotes(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Data))
+
+	return response.Success(c, result)
+}
+
+// UploadQuotes handles POST /api/v1/quotes - Upload a symbol,date,bid,ask CSV file (multipart/form-data)
+func (h *QuoteController) UploadQuotes(c *fiber.Ctx) error {
+	// Parse multipart form
+	file, err := c.FormFile("file")
+	if err != nil {
+		return apperror.BadRequest("No file uploaded", err.Error())
+	}
+
+	// Validate file type
+	contentType := file.Header.Get("Content-Type")
+	if contentType != "text/csv" && contentType != "application/vnd.ms-excel" && contentType != "application/csv" {
+		// Also check file extension as a fallback
+		if len(file.Filename) < 4 || file.Filename[len(file.Filename)-4:] != ".csv" {
+			return apperror.BadRequest("Invalid file type", "Only CSV files are allowed")
+		}
+	}
+
+	uploadInfo := service.UploadInfo{
+		Filename: file.Filename,
+		FileSize: file.Size,
+		Tenant:   middleware.GetTenant(c),
+		APIKey:   middleware.GetAPIKeyName(c),
+	}
+
+	// Validate file size
+	if err := h.service.ValidateUpload(uploadInfo); err != nil {
+		return apperror.PayloadTooLarge(err.Error())
+	}
+
+	// Open file
+	fileReader, err := file.Open()
+	if err != nil {
+		return apperror.Internal("Failed to read file")
+	}
+	defer fileReader.Close()
+
+	// Reject uploads that would exceed the tenant's monthly row quota
+	rows, err := csvparser.CountRows(fileReader)
+	if err != nil {
+		return apperror.BadRequest("Failed to read file", err.Error())
+	}
+	if _, err := fileReader.Seek(0, io.SeekStart); err != nil {
+		return apperror.Internal("Failed to read file")
+	}
+	if err := h.usage.CheckIngestQuota(c.UserContext(), middleware.GetTenant(c), rows); err != nil {
+		return err
+	}
+
+	// Process CSV file
+	result, err := h.service.UploadCSV(c.UserContext(), fileReader, uploadInfo)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsIngested(c, result.SuccessCount)
+	middleware.SetAuditSymbols(c, result.Symbols)
+	middleware.SetAuditResourceIDs(c, result.SourceID)
+
+	return response.Success(c, result)
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RollupController handles weekly and monthly rollup maintenance endpoints
+type RollupController struct {
+	service   service.RollupService
+	validator *validator.Validator
+}
+
+// NewRollupController creates a new rollup controller instance
+func NewRollupController(service service.RollupService, validator *validator.Validator) *RollupController {
+	return &RollupController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RebuildRollups handles POST /admin/rollups/rebuild - Recompute the rollups of
+// symbols from their daily bars in the background
+func (h *RollupController) RebuildRollups(c *fiber.Ctx) error {
+	var req request.RebuildRollupsRequest
+
+	// Parse request body; an empty body rebuilds every symbol
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.BadRequest("Invalid request body", err.Error())
+		}
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.Rebuild(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, result.Symbols)
+
+	return response.Success(c, result)
+}
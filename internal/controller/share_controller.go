@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	dto "github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/share"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ShareController handles share link endpoints
+type ShareController struct {
+	signer    *share.Signer
+	validator *validator.Validator
+	cfg       config.SharesConfig
+}
+
+// NewShareController creates a new share controller instance
+func NewShareController(signer *share.Signer, validator *validator.Validator, cfg config.SharesConfig) *ShareController {
+	return &ShareController{
+		signer:    signer,
+		validator: validator,
+		cfg:       cfg,
+	}
+}
+
+// CreateShare handles POST /api/v1/shares - Mint a time-limited link answering
+// a data query without an API key
+func (h *ShareController) CreateShare(c *fiber.Ctx) error {
+	var req request.CreateShareRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return apperror.BadRequest(err.Error(), nil)
+	}
+
+	ttl := h.cfg.DefaultTTL
+	if req.TTL > 0 {
+		ttl = req.TTL
+	}
+	if ttl > h.cfg.MaxTTL {
+		return apperror.BadRequest(fmt.Sprintf("ttl must not exceed %d seconds", h.cfg.MaxTTL), nil)
+	}
+
+	// The parameters of GET /api/v1/data the link answers
+	query := url.Values{
+		"symbol": {req.Symbol},
+		"format": {req.Format},
+	}
+	if !req.StartDate.IsZero() {
+		query.Set("start_date", req.StartDate.Format(time.RFC3339))
+	}
+	if !req.EndDate.IsZero() {
+		query.Set("end_date", req.EndDate.Format(time.RFC3339))
+	}
+	if req.Fields != "" {
+		query.Set("fields", req.Fields)
+	}
+	if req.Limit > 0 {
+		query.Set("limit", strconv.Itoa(req.Limit))
+	}
+
+	expires := time.Now().Add(time.Duration(ttl) * time.Second).UTC().Truncate(time.Second)
+	return response.Created(c, dto.ShareResponse{
+		URL:       h.signer.URL(query, middleware.GetAPIKeyName(c), expires),
+		ExpiresAt: expires,
+	})
+}
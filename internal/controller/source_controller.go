@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SourceController handles data provenance endpoints. Historical rows are shared
+// across tenants, so every caller can resolve the source_id of a row.
+type SourceController struct {
+	service   service.SourceService
+	validator *validator.Validator
+}
+
+// NewSourceController creates a new source controller instance
+func NewSourceController(service service.SourceService, validator *validator.Validator) *SourceController {
+	return &SourceController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetSources handles GET /api/v1/sources - List data sources, newest first
+func (h *SourceController) GetSources(c *fiber.Ctx) error {
+	var req request.GetSourcesRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetSources(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetSource handles GET /api/v1/sources/:id - Retrieve a data source by ID
+func (h *SourceController) GetSource(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	source, err := h.service.GetSource(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return apperror.NotFound("Source not found")
+	}
+
+	return response.Success(c, source)
+}
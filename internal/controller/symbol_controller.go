@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SymbolController handles symbol metadata endpoints
+type SymbolController struct {
+	service   service.SymbolService
+	validator *validator.Validator
+}
+
+// NewSymbolController creates a new symbol controller instance
+func NewSymbolController(service service.SymbolService, validator *validator.Validator) *SymbolController {
+	return &SymbolController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetSymbols handles GET /api/v1/symbols - List symbol metadata
+func (h *SymbolController) GetSymbols(c *fiber.Ctx) error {
+	var req request.GetSymbolsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetSymbols(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetSymbol handles GET /api/v1/symbols/:symbol - Retrieve the metadata of a symbol
+func (h *SymbolController) GetSymbol(c *fiber.Ctx) error {
+	symbol, err := h.service.GetSymbol(c.UserContext(), c.Params("symbol"))
+	if err != nil {
+		return err
+	}
+	if symbol == nil {
+		return apperror.NotFound("Symbol not found")
+	}
+
+	return response.Success(c, symbol)
+}
+
+// UpsertSymbol handles PUT /api/v1/symbols/:symbol - Create or replace the metadata of a symbol
+func (h *SymbolController) UpsertSymbol(c *fiber.Ctx) error {
+	var req request.UpsertSymbolRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	symbol := c.Params("symbol")
+	if err := h.validator.ValidateVar("symbol", symbol, "required,ticker"); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.UpsertSymbol(c.UserContext(), symbol, &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{result.Symbol})
+	middleware.SetAuditResourceIDs(c, result.ID)
+
+	return response.Success(c, result)
+}
+
+// RenameSymbol handles POST /admin/symbols/rename - Move every row of a symbol
+// to a new symbol, keeping the old one as a former name
+func (h *SymbolController) RenameSymbol(c *fiber.Ctx) error {
+	var req request.RenameSymbolRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.RenameSymbol(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{result.From, result.To})
+
+	return response.Success(c, result)
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/tickcodec"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TickController handles tick ingestion and query endpoints
+type TickController struct {
+	service   service.TickService
+	validator *validator.Validator
+}
+
+// NewTickController creates a new tick controller instance
+func NewTickController(service service.TickService, validator *validator.Validator) *TickController {
+	return &TickController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// IngestTicks handles POST /api/v1/ticks - Stream ticks as NDJSON
+// (application/x-ndjson) or fixed binary records (application/octet-stream)
+func (h *TickController) IngestTicks(c *fiber.Ctx) error {
+	// The body is decoded while it streams in rather than buffered whole
+	var body io.Reader = c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	var decoder tickcodec.Decoder
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case tickcodec.ContentTypeNDJSON, "application/ndjson":
+		decoder = tickcodec.NewNDJSONDecoder(body)
+	case tickcodec.ContentTypeBinary:
+		decoder = tickcodec.NewBinaryDecoder(body)
+	default:
+		return apperror.UnsupportedMediaType("Content-Type must be " + tickcodec.ContentTypeNDJSON + " or " + tickcodec.ContentTypeBinary)
+	}
+
+	// Call service
+	result, err := h.service.Ingest(c.UserContext(), decoder)
+	if err != nil {
+		return apperror.BadRequest("Failed to read ticks", err.Error())
+	}
+
+	middleware.SetRowsIngested(c, result.SuccessCount)
+	middleware.SetAuditSymbols(c, result.Symbols)
+
+	return response.Success(c, result)
+}
+
+// GetTicks handles GET /api/v1/ticks - List the ticks of a symbol in a time
+// range, paged with next_cursor
+func (h *TickController) GetTicks(c *fiber.Ctx) error {
+	var req request.GetTicksRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+	req.Symbol = strings.ToUpper(req.Symbol)
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetTicks(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Data))
+	middleware.SetAuditSymbols(c, []string{req.Symbol})
+
+	return response.Success(c, result)
+}
+
+// GetBars handles GET /api/v1/data/:symbol/bars - Build OHLCV bars of any
+// whole-second interval from the stored ticks of a symbol at query time
+func (h *TickController) GetBars(c *fiber.Ctx) error {
+	symbol := strings.ToUpper(c.Params("symbol"))
+	if err := h.validator.ValidateVar("symbol", symbol, "required,ticker"); err != nil {
+		return err
+	}
+
+	var req request.TickBarsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, []string{symbol})
+
+	// Call service
+	result, err := h.service.GetBars(c.UserContext(), symbol, &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Bars))
+
+	return response.Success(c, result)
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/export"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/csvparser"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TimeSeriesController handles generic time series endpoints
+type TimeSeriesController struct {
+	service   service.TimeSeriesService
+	usage     service.UsageService
+	validator *validator.Validator
+}
+
+// NewTimeSeriesController creates a new time series controller instance
+func NewTimeSeriesController(service service.TimeSeriesService, usage service.UsageService, validator *validator.Validator) *TimeSeriesController {
+	return &TimeSeriesController{
+		service:   service,
+		usage:     usage,
+		validator: validator,
+	}
+}
+
+// GetSeries handles GET /api/v1/series - Retrieve the observations of a series
+// as JSON or, negotiated like GET /api/v1/data, as CSV
+func (h *TimeSeriesController) GetSeries(c *fiber.Ctx) error {
+	var req request.GetTimeSeriesRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req, "format"); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetSeries(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsRead(c, len(result.Data))
+	middleware.SetAuditSymbols(c, []string{req.SeriesID})
+
+	if wantsCSV(c) {
+		c.Set(fiber.HeaderContentType, export.ContentTypeCSV+"; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "time_series.csv"))
+		if err := export.WriteSeriesCSV(c.Response().BodyWriter(), result.Data); err != nil {
+			return apperror.Internal("Failed to write CSV response")
+		}
+		return nil
+	}
+
+	return response.Success(c, result)
+}
+
+// UploadSeries handles POST /api/v1/series - Upload a series_id,timestamp,value
+// CSV file (multipart/form-data); other columns are stored as fields
+func (h *TimeSeriesController) UploadSeries(c *fiber.Ctx) error {
+	// Parse multipart form
+	file, err := c.FormFile("file")
+	if err != nil {
+		return apperror.BadRequest("No file uploaded", err.Error())
+	}
+
+	// Validate file type
+	contentType := file.Header.Get("Content-Type")
+	if contentType != "text/csv" && contentType != "application/vnd.ms-excel" && contentType != "application/csv" {
+		// Also check file extension as a fallback
+		if len(file.Filename) < 4 || file.Filename[len(file.Filename)-4:] != ".csv" {
+			return apperror.BadRequest("Invalid file type", "Only CSV files are allowed")
+		}
+	}
+
+	uploadInfo := service.UploadInfo{
+		Filename: file.Filename,
+		FileSize: file.Size,
+		Tenant:   middleware.GetTenant(c),
+		APIKey:   middleware.GetAPIKeyName(c),
+	}
+
+	// Validate file size
+	if err := h.service.ValidateUpload(uploadInfo); err != nil {
+		return apperror.PayloadTooLarge(err.Error())
+	}
+
+	// Open file
+	fileReader, err := file.Open()
+	if err != nil {
+		return apperror.Internal("Failed to read file")
+	}
+	defer fileReader.Close()
+
+	// Reject uploads that would exceed the tenant's monthly row quota
+	rows, err := csvparser.CountRows(fileReader)
+	if err != nil {
+		return apperror.BadRequest("Failed to read file", err.Error())
+	}
+	if _, err := fileReader.Seek(0, io.SeekStart); err != nil {
+		return apperror.Internal("Failed to read file")
+	}
+	if err := h.usage.CheckIngestQuota(c.UserContext(), middleware.GetTenant(c), rows); err != nil {
+		return err
+	}
+
+	// Process CSV file
+	result, err := h.service.UploadCSV(c.UserContext(), fileReader, uploadInfo)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetRowsIngested(c, result.SuccessCount)
+	middleware.SetAuditSymbols(c, result.Series)
+	middleware.SetAuditResourceIDs(c, result.SourceID)
+
+	return response.Success(c, result)
+}
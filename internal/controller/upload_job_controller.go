@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UploadJobController handles upload job status endpoints
+type UploadJobController struct {
+	service   service.UploadJobService
+	validator *validator.Validator
+}
+
+// NewUploadJobController creates a new upload job controller instance
+func NewUploadJobController(service service.UploadJobService, validator *validator.Validator) *UploadJobController {
+	return &UploadJobController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetUploadJobs handles GET /api/v1/uploads - List upload jobs of the calling
+// tenant. Admins may inspect another tenant via ?tenant=.
+func (h *UploadJobController) GetUploadJobs(c *fiber.Ctx) error {
+	var req request.GetUploadJobsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req, "tenant"); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	tenant := middleware.GetTenant(c)
+	if other := c.Query("tenant"); other != "" && other != tenant {
+		if !middleware.IsAdmin(c) {
+			return apperror.Forbidden("Only admins can view other tenants' uploads")
+		}
+		tenant = other
+	}
+
+	// Call service
+	result, err := h.service.GetUploadJobs(c.UserContext(), tenant, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetUploadJob handles GET /api/v1/uploads/:id - Retrieve the status of an upload
+func (h *UploadJobController) GetUploadJob(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	job, err := h.service.GetUploadJob(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	// Jobs of other tenants are reported as missing to non-admins
+	if job == nil || (job.Tenant != middleware.GetTenant(c) && !middleware.IsAdmin(c)) {
+		return apperror.NotFound("Upload job not found")
+	}
+
+	return response.Success(c, job)
+}
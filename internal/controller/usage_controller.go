@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UsageController handles usage metering endpoints
+type UsageController struct {
+	service   service.UsageService
+	validator *validator.Validator
+}
+
+// NewUsageController creates a new usage controller instance
+func NewUsageController(service service.UsageService, validator *validator.Validator) *UsageController {
+	return &UsageController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// GetUsage handles GET /api/v1/usage - Retrieve usage of the calling tenant.
+// Admins may inspect another tenant via ?tenant=.
+func (h *UsageController) GetUsage(c *fiber.Ctx) error {
+	var req request.GetUsageRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req, "tenant"); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Validate date range
+	if err := req.Validate(); err != nil {
+		return apperror.BadRequest(err.Error(), nil)
+	}
+
+	tenant := middleware.GetTenant(c)
+	if other := c.Query("tenant"); other != "" && other != tenant {
+		if !middleware.IsAdmin(c) {
+			return apperror.Forbidden("Only admins can view other tenants' usage")
+		}
+		tenant = other
+	}
+
+	// Call service
+	result, err := h.service.GetUsage(c.UserContext(), tenant, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
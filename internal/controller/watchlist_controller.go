@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/service"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/go-historical-data/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WatchlistController handles watchlist endpoints
+type WatchlistController struct {
+	service   service.WatchlistService
+	validator *validator.Validator
+}
+
+// NewWatchlistController creates a new watchlist controller instance
+func NewWatchlistController(service service.WatchlistService, validator *validator.Validator) *WatchlistController {
+	return &WatchlistController{
+		service:   service,
+		validator: validator,
+	}
+}
+
+// CreateWatchlist handles POST /api/v1/watchlists - Create a watchlist
+func (h *WatchlistController) CreateWatchlist(c *fiber.Ctx) error {
+	var req request.WatchlistRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.CreateWatchlist(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), &req)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetAuditSymbols(c, result.Symbols)
+	middleware.SetAuditResourceIDs(c, result.ID)
+
+	return response.Created(c, result)
+}
+
+// GetWatchlists handles GET /api/v1/watchlists - List the caller's watchlists
+func (h *WatchlistController) GetWatchlists(c *fiber.Ctx) error {
+	var req request.GetWatchlistsRequest
+
+	// Parse query parameters
+	if err := binder.Query(c, &req); err != nil {
+		return err
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.GetWatchlists(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, result)
+}
+
+// GetWatchlist handles GET /api/v1/watchlists/:id - Retrieve a watchlist
+func (h *WatchlistController) GetWatchlist(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	result, err := h.service.GetWatchlist(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), id)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return apperror.NotFound("Watchlist not found")
+	}
+
+	return response.Success(c, result)
+}
+
+// UpdateWatchlist handles PUT /api/v1/watchlists/:id - Replace the name and symbols of a watchlist
+func (h *WatchlistController) UpdateWatchlist(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	var req request.WatchlistRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.BadRequest("Invalid request body", err.Error())
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		return err
+	}
+
+	// Call service
+	result, err := h.service.UpdateWatchlist(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), id, &req)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return apperror.NotFound("Watchlist not found")
+	}
+
+	middleware.SetAuditSymbols(c, result.Symbols)
+	middleware.SetAuditResourceIDs(c, result.ID)
+
+	return response.Success(c, result)
+}
+
+// DeleteWatchlist handles DELETE /api/v1/watchlists/:id - Delete a watchlist
+func (h *WatchlistController) DeleteWatchlist(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	result, err := h.service.DeleteWatchlist(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), id)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return apperror.NotFound("Watchlist not found")
+	}
+
+	middleware.SetAuditResourceIDs(c, result.ID)
+
+	return response.NoContent(c)
+}
+
+// GetWatchlistData handles GET /api/v1/watchlists/:id/data - Latest bar and
+// day-over-day change of every symbol of a watchlist
+func (h *WatchlistController) GetWatchlistData(c *fiber.Ctx) error {
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("Invalid ID parameter", err.Error())
+	}
+
+	// Call service
+	result, err := h.service.GetWatchlistData(c.UserContext(), middleware.GetTenant(c), middleware.GetAPIKeyName(c), id)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return apperror.NotFound("Watchlist not found")
+	}
+
+	middleware.SetRowsRead(c, len(result.Symbols))
+
+	return response.Success(c, result)
+}
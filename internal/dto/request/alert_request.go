@@ -0,0 +1,104 @@
+package request
+
+import (
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/go-historical-data/pkg/netguard"
+)
+
+// AlertRuleRequest represents the body of an alert rule creation or replacement
+type AlertRuleRequest struct {
+	Name      string  `json:"name" validate:"omitempty,max=100"`
+	Symbol    string  `json:"symbol" validate:"required,ticker"`
+	Condition string  `json:"condition" validate:"required,oneof=cross_above cross_below change_above change_below"`
+	Threshold float64 `json:"threshold"` // price level, or percent for change conditions
+	Channel   string  `json:"channel" validate:"required,oneof=webhook email"`
+	Target    string  `json:"target" validate:"required,max=512"` // webhook URL or email address
+	Enabled   *bool   `json:"enabled"`                            // defaults to true
+}
+
+// Normalize upper-cases the symbol and trims the name and target
+func (r *AlertRuleRequest) Normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Symbol = strings.ToUpper(strings.TrimSpace(r.Symbol))
+	r.Target = strings.TrimSpace(r.Target)
+}
+
+// Validate checks the threshold and that the target suits the channel
+func (r *AlertRuleRequest) Validate() error {
+	if (r.Condition == "cross_above" || r.Condition == "cross_below") && r.Threshold <= 0 {
+		return &ValidationError{Field: "threshold", Message: "threshold must be a positive price level"}
+	}
+
+	switch r.Channel {
+	case "webhook":
+		u, err := url.Parse(r.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &ValidationError{Field: "target", Message: "target must be an http or https URL"}
+		}
+		// Names resolving to internal addresses are refused when delivering
+		host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+		if addr, err := netip.ParseAddr(host); (err == nil && !netguard.IsPublicAddr(addr)) || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return &ValidationError{Field: "target", Message: "target must be a public address"}
+		}
+	case "email":
+		if _, err := mail.ParseAddress(r.Target); err != nil {
+			return &ValidationError{Field: "target", Message: "target must be an email address"}
+		}
+	}
+	return nil
+}
+
+// IsEnabled reports whether the rule should be enabled, true when unspecified
+func (r *AlertRuleRequest) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// GetAlertRulesRequest represents query parameters for listing alert rules
+type GetAlertRulesRequest struct {
+	Symbol  string `query:"symbol" validate:"omitempty,ticker"`
+	Enabled string `query:"enabled" validate:"omitempty,oneof=true false"`
+	Page    int    `query:"page" validate:"omitempty,min=1"`
+	Limit   int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetAlertRulesRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.Symbol = strings.ToUpper(r.Symbol)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetAlertRulesRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// GetAlertEventsRequest represents query parameters for listing the history of an alert rule
+type GetAlertEventsRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending delivered failed"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetAlertEventsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetAlertEventsRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
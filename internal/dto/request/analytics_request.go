@@ -0,0 +1,292 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/pkg/validator"
+)
+
+// MaxCompareSymbols is the maximum number of symbols of one comparison
+const MaxCompareSymbols = 20
+
+// CompareRequest represents query parameters for comparing several symbols
+type CompareRequest struct {
+	Symbols    string    `query:"symbols" validate:"required,max=500"` // comma-separated
+	StartDate  time.Time `query:"start_date" validate:"omitempty"`
+	EndDate    time.Time `query:"end_date" validate:"omitempty"`
+	Metric     string    `query:"metric" validate:"omitempty,oneof=open high low close volume"`
+	Rebase     float64   `query:"rebase" validate:"omitempty,gt=0"`
+	Adjustment string    `query:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	ConvertTo  string    `query:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+	Include    string    `query:"include" validate:"omitempty,oneof=fundamentals"`
+}
+
+// SetDefaults sets the default metric and normalizes the currency code and dates
+func (r *CompareRequest) SetDefaults() {
+	if r.Metric == "" {
+		r.Metric = "close"
+	}
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// Validate validates the date range and the symbol list
+func (r *CompareRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	if _, err := r.GetSymbols(); err != nil {
+		return err
+	}
+	// Fundamentals are reported in the listing currency
+	if r.Include == "fundamentals" && r.ConvertTo != "" {
+		return &ValidationError{Field: "include", Message: "fundamentals cannot be included with convert_to"}
+	}
+	return nil
+}
+
+// GetSymbols parses the comma-separated symbols into an upper-cased,
+// deduplicated list of 2 to MaxCompareSymbols symbols
+func (r *CompareRequest) GetSymbols() ([]string, error) {
+	return parseSymbolList(r.Symbols, 2, MaxCompareSymbols)
+}
+
+// MaxCorrelationSymbols is the maximum number of symbols of one correlation matrix
+const MaxCorrelationSymbols = 50
+
+// CorrelationRequest represents query parameters for the correlation matrix
+type CorrelationRequest struct {
+	Symbols    string    `query:"symbols" validate:"required,max=1100"` // comma-separated
+	Window     int       `query:"window" validate:"omitempty,min=2,max=2520"`
+	EndDate    time.Time `query:"end_date" validate:"omitempty"`
+	Benchmark  string    `query:"benchmark" validate:"omitempty,ticker"`
+	Adjustment string    `query:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	ConvertTo  string    `query:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+}
+
+// SetDefaults sets the default window of one trading year and normalizes codes
+func (r *CorrelationRequest) SetDefaults() {
+	if r.Window == 0 {
+		r.Window = 252
+	}
+	r.Benchmark = strings.ToUpper(strings.TrimSpace(r.Benchmark))
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// GetSymbols parses the comma-separated symbols into an upper-cased,
+// deduplicated list of 2 to MaxCorrelationSymbols symbols
+func (r *CorrelationRequest) GetSymbols() ([]string, error) {
+	return parseSymbolList(r.Symbols, 2, MaxCorrelationSymbols)
+}
+
+// parseSymbolList parses a comma-separated symbol list, keeping the first
+// occurrence of each symbol, and checks its length
+func parseSymbolList(value string, minCount, maxCount int) ([]string, error) {
+	seen := make(map[string]bool)
+	symbols := make([]string, 0)
+	for _, s := range strings.Split(value, ",") {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		if !validator.IsTicker(s) {
+			return nil, &ValidationError{Field: "symbols", Message: fmt.Sprintf("symbol '%s' is not a valid ticker", s)}
+		}
+		seen[s] = true
+		symbols = append(symbols, s)
+	}
+	if len(symbols) < minCount || len(symbols) > maxCount {
+		return nil, &ValidationError{
+			Field:   "symbols",
+			Message: fmt.Sprintf("between %d and %d distinct symbols are required", minCount, maxCount),
+		}
+	}
+	return symbols, nil
+}
+
+// ReturnsRequest represents query parameters for the return statistics of one symbol
+type ReturnsRequest struct {
+	StartDate time.Time `query:"start_date" validate:"omitempty"`
+	EndDate   time.Time `query:"end_date" validate:"omitempty"`
+	Period    string    `query:"period" validate:"omitempty,interval"`
+	// RiskFreeRate is the annual risk-free rate in percent; the configured rate when omitted
+	RiskFreeRate *float64 `query:"risk_free_rate" validate:"omitempty,gte=-100,lte=100"`
+	Adjustment   string   `query:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	ConvertTo    string   `query:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+}
+
+// SetDefaults sets the default daily period and normalizes the currency code and dates
+func (r *ReturnsRequest) SetDefaults() {
+	if r.Period == "" {
+		r.Period = "daily"
+	}
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// Validate validates the date range
+func (r *ReturnsRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// DistributionRequest represents query parameters for the return
+// distribution of one symbol
+type DistributionRequest struct {
+	StartDate  time.Time `query:"start_date" validate:"omitempty"`
+	EndDate    time.Time `query:"end_date" validate:"omitempty"`
+	Period     string    `query:"period" validate:"omitempty,interval"`
+	Buckets    int       `query:"buckets" validate:"omitempty,min=1,max=1000"` // histogram bins
+	Adjustment string    `query:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	ConvertTo  string    `query:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+}
+
+// DefaultDistributionBuckets is the number of histogram bins when omitted
+const DefaultDistributionBuckets = 20
+
+// SetDefaults sets the default daily period and bucket count and normalizes
+// the currency code and dates
+func (r *DistributionRequest) SetDefaults() {
+	if r.Period == "" {
+		r.Period = "daily"
+	}
+	if r.Buckets == 0 {
+		r.Buckets = DefaultDistributionBuckets
+	}
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// Validate validates the date range
+func (r *DistributionRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// ChartRequest represents query parameters for a chart series of one symbol
+type ChartRequest struct {
+	StartDate  time.Time `query:"start_date" validate:"omitempty"`
+	EndDate    time.Time `query:"end_date" validate:"omitempty"`
+	Points     int       `query:"points" validate:"omitempty,min=3,max=10000"` // downsample to at most this many points
+	Metric     string    `query:"metric" validate:"omitempty,oneof=open high low close volume"`
+	Adjustment string    `query:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	ConvertTo  string    `query:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+}
+
+// SetDefaults sets the default metric and normalizes the currency code and dates
+func (r *ChartRequest) SetDefaults() {
+	if r.Metric == "" {
+		r.Metric = "close"
+	}
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// Validate validates the date range
+func (r *ChartRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// AggregatesRequest represents query parameters for the weekly or monthly bars of one symbol
+type AggregatesRequest struct {
+	Interval   string    `query:"interval" validate:"required,rollup_interval"`
+	StartDate  time.Time `query:"start_date" validate:"omitempty"`
+	EndDate    time.Time `query:"end_date" validate:"omitempty"`
+	Adjustment string    `query:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	ConvertTo  string    `query:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+}
+
+// SetDefaults normalizes the currency code and dates
+func (r *AggregatesRequest) SetDefaults() {
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// Validate validates the date range
+func (r *AggregatesRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// RebuildRollupsRequest represents the body of a rollup rebuild
+type RebuildRollupsRequest struct {
+	Symbols []string `json:"symbols" validate:"omitempty,max=1000,dive,required,ticker"` // every symbol when empty
+}
+
+// Normalize upper-cases the symbols
+func (r *RebuildRollupsRequest) Normalize() {
+	for i := range r.Symbols {
+		r.Symbols[i] = strings.ToUpper(strings.TrimSpace(r.Symbols[i]))
+	}
+}
+
+// Backtest strategies
+const (
+	StrategySMACrossover = "sma_crossover"
+)
+
+// BacktestRequest represents the body of a backtest request
+type BacktestRequest struct {
+	Symbol     string    `json:"symbol" validate:"required,ticker"`
+	StartDate  time.Time `json:"start_date" validate:"required"`
+	EndDate    time.Time `json:"end_date" validate:"required"`
+	Strategy   string    `json:"strategy" validate:"omitempty,oneof=sma_crossover"`
+	FastWindow int       `json:"fast_window" validate:"omitempty,min=1,max=500"`
+	SlowWindow int       `json:"slow_window" validate:"omitempty,min=2,max=500"`
+	// InitialCapital is the cash the backtest starts with, in the series currency
+	InitialCapital float64 `json:"initial_capital" validate:"omitempty,gt=0"`
+	// CommissionBps is charged on the traded value of every fill, in basis points
+	CommissionBps float64 `json:"commission_bps" validate:"omitempty,gte=0,lte=1000"`
+	// RiskFreeRate is the annual risk-free rate in percent; the configured rate when omitted
+	RiskFreeRate *float64 `json:"risk_free_rate" validate:"omitempty,gte=-100,lte=100"`
+	Adjustment   string   `json:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	ConvertTo    string   `json:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+}
+
+// SetDefaults sets the default strategy parameters and normalizes codes
+func (r *BacktestRequest) SetDefaults() {
+	r.Symbol = strings.ToUpper(strings.TrimSpace(r.Symbol))
+	if r.Strategy == "" {
+		r.Strategy = StrategySMACrossover
+	}
+	if r.FastWindow == 0 {
+		r.FastWindow = 20
+	}
+	if r.SlowWindow == 0 {
+		r.SlowWindow = 50
+	}
+	if r.InitialCapital == 0 {
+		r.InitialCapital = 10000
+	}
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+}
+
+// Validate validates the date range and the strategy parameters
+func (r *BacktestRequest) Validate() error {
+	if r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	if r.FastWindow >= r.SlowWindow {
+		return &ValidationError{Field: "fast_window", Message: "fast_window must be less than slow_window"}
+	}
+	return nil
+}
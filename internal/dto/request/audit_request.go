@@ -0,0 +1,48 @@
+package request
+
+import (
+	"strings"
+	"time"
+)
+
+// GetAuditLogsRequest represents query parameters for querying the audit log
+type GetAuditLogsRequest struct {
+	Tenant     string    `query:"tenant" validate:"omitempty,max=64"`
+	APIKey     string    `query:"api_key" validate:"omitempty,max=64"`
+	Method     string    `query:"method" validate:"omitempty,oneof=POST PUT PATCH DELETE"`
+	Outcome    string    `query:"outcome" validate:"omitempty,oneof=success failure"`
+	RequestID  string    `query:"request_id" validate:"omitempty,max=64"`
+	Symbol     string    `query:"symbol" validate:"omitempty,ticker"`
+	ResourceID string    `query:"resource_id" validate:"omitempty,numeric"`
+	StartTime  time.Time `query:"start_time" validate:"omitempty"`
+	EndTime    time.Time `query:"end_time" validate:"omitempty"`
+	Page       int       `query:"page" validate:"omitempty,min=1"`
+	Limit      int       `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetAuditLogsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.Symbol = strings.ToUpper(r.Symbol)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetAuditLogsRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// Validate validates the time range
+func (r *GetAuditLogsRequest) Validate() error {
+	if !r.StartTime.IsZero() && !r.EndTime.IsZero() && r.StartTime.After(r.EndTime) {
+		return &ValidationError{
+			Field:   "time_range",
+			Message: "start_time must be before or equal to end_time",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package request
+
+import (
+	"strings"
+	"time"
+)
+
+// CreateBackfillRequest represents the body of a backfill request
+type CreateBackfillRequest struct {
+	Symbols   []string  `json:"symbols" validate:"required,min=1,dive,required,ticker"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required"`
+	Provider  string    `json:"provider" validate:"required,max=32"`
+	ChunkDays int       `json:"chunk_days" validate:"omitempty,min=1,max=3660"`
+}
+
+// Normalize upper-cases and de-duplicates symbols and truncates dates to UTC days
+func (r *CreateBackfillRequest) Normalize() {
+	seen := make(map[string]bool, len(r.Symbols))
+	symbols := make([]string, 0, len(r.Symbols))
+	for _, s := range r.Symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		symbols = append(symbols, s)
+	}
+	r.Symbols = symbols
+	r.Provider = strings.ToLower(strings.TrimSpace(r.Provider))
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// Validate validates the date range
+func (r *CreateBackfillRequest) Validate() error {
+	if r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// GetBackfillsRequest represents query parameters for listing backfills
+type GetBackfillsRequest struct {
+	Tenant   string `query:"tenant" validate:"omitempty,max=64"`
+	Provider string `query:"provider" validate:"omitempty,max=32"`
+	Status   string `query:"status" validate:"omitempty,oneof=pending running completed partial failed"`
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetBackfillsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetBackfillsRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// GetBackfillChunksRequest represents query parameters for listing backfill chunks
+type GetBackfillChunksRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending running completed failed"`
+	Symbol string `query:"symbol" validate:"omitempty,ticker"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetBackfillChunksRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.Symbol = strings.ToUpper(r.Symbol)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetBackfillChunksRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// truncateToDay drops the time of day, keeping the calendar date as written
+// in the value's own offset: 2024-03-01T00:00:00+09:00 is March 1st, not the
+// February 29th it is in UTC. Dates are held as UTC midnight of that day.
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
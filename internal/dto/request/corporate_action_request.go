@@ -0,0 +1,29 @@
+package request
+
+import (
+	"time"
+)
+
+// CreateCorporateActionRequest represents the body of a corporate action
+type CreateCorporateActionRequest struct {
+	Type   string    `json:"type" validate:"required,oneof=split dividend"`
+	ExDate time.Time `json:"ex_date" validate:"required"`
+	Ratio  float64   `json:"ratio" validate:"omitempty,gt=0"`
+	Amount float64   `json:"amount" validate:"omitempty,gt=0"`
+}
+
+// Normalize truncates the ex-date to its UTC day
+func (r *CreateCorporateActionRequest) Normalize() {
+	r.ExDate = truncateToDay(r.ExDate)
+}
+
+// Validate checks that each action type carries its value
+func (r *CreateCorporateActionRequest) Validate() error {
+	if r.Type == "split" && r.Ratio <= 0 {
+		return &ValidationError{Field: "ratio", Message: "ratio is required for splits"}
+	}
+	if r.Type == "dividend" && r.Amount <= 0 {
+		return &ValidationError{Field: "amount", Message: "amount is required for dividends"}
+	}
+	return nil
+}
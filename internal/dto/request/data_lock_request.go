@@ -0,0 +1,55 @@
+package request
+
+import (
+	"strings"
+	"time"
+)
+
+// CreateDataLockRequest represents the body of a data lock
+type CreateDataLockRequest struct {
+	Symbol    string    `json:"symbol" validate:"required,ticker"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required"`
+	Reason    string    `json:"reason" validate:"omitempty,max=255"`
+}
+
+// Normalize upper-cases the symbol and truncates the dates to their UTC day
+func (r *CreateDataLockRequest) Normalize() {
+	r.Symbol = strings.ToUpper(strings.TrimSpace(r.Symbol))
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+	r.Reason = strings.TrimSpace(r.Reason)
+}
+
+// Validate checks the date range
+func (r *CreateDataLockRequest) Validate() error {
+	if r.EndDate.Before(r.StartDate) {
+		return &ValidationError{Field: "end_date", Message: "end_date must be on or after start_date"}
+	}
+	return nil
+}
+
+// GetDataLocksRequest represents query parameters for listing data locks
+type GetDataLocksRequest struct {
+	Symbol string    `query:"symbol" validate:"omitempty,ticker"`
+	Date   time.Time `query:"date" validate:"omitempty"` // locks covering the date
+	Page   int       `query:"page" validate:"omitempty,min=1"`
+	Limit  int       `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetDataLocksRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.Symbol = strings.ToUpper(r.Symbol)
+	r.Date = truncateToDay(r.Date)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetDataLocksRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
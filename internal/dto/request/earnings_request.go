@@ -0,0 +1,55 @@
+package request
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EarningsEventInput represents the report of one symbol and fiscal period
+type EarningsEventInput struct {
+	Symbol      string              `json:"symbol" validate:"required,ticker"`
+	Period      string              `json:"period" validate:"required,max=8"` // 2024Q1, 2024FY, FY2024...
+	ReportDate  time.Time           `json:"report_date" validate:"required"`
+	EPSEstimate decimal.NullDecimal `json:"eps_estimate"`
+	EPSActual   decimal.NullDecimal `json:"eps_actual"`
+}
+
+// UploadEarningsRequest represents the body of an earnings calendar upload
+type UploadEarningsRequest struct {
+	Events []EarningsEventInput `json:"events" validate:"required,min=1,max=1000,dive"`
+}
+
+// GetEarningsRequest represents query parameters for the earnings calendar
+type GetEarningsRequest struct {
+	Symbol    string    `query:"symbol" validate:"omitempty,ticker"`
+	StartDate time.Time `query:"start_date" validate:"omitempty"`
+	EndDate   time.Time `query:"end_date" validate:"omitempty"`
+	Page      int       `query:"page" validate:"omitempty,min=1"`
+	Limit     int       `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination and reduces the dates to calendar dates
+func (r *GetEarningsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetEarningsRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// Validate validates the date range
+func (r *GetEarningsRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package request
+
+import (
+	"strings"
+	"time"
+)
+
+// CreateExportRequest represents the body of an export request. Without dates
+// the full history of the symbols is exported.
+type CreateExportRequest struct {
+	Symbols   []string  `json:"symbols" validate:"omitempty,dive,required,ticker"`
+	Tag       string    `json:"tag" validate:"omitempty,max=32"` // adds the symbols carrying the tag
+	StartDate time.Time `json:"start_date" validate:"omitempty"`
+	EndDate   time.Time `json:"end_date" validate:"omitempty"`
+	Format    string    `json:"format" validate:"omitempty,oneof=csv parquet"`
+}
+
+// Normalize upper-cases and de-duplicates symbols, lower-cases the tag,
+// truncates dates to UTC days and defaults the format to csv
+func (r *CreateExportRequest) Normalize() {
+	seen := make(map[string]bool, len(r.Symbols))
+	symbols := make([]string, 0, len(r.Symbols))
+	for _, s := range r.Symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		symbols = append(symbols, s)
+	}
+	r.Symbols = symbols
+	r.Tag = NormalizeTag(r.Tag)
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+	if r.Format == "" {
+		r.Format = "csv"
+	}
+}
+
+// Validate requires symbols or a tag and validates the date range
+func (r *CreateExportRequest) Validate() error {
+	if len(r.Symbols) == 0 && r.Tag == "" {
+		return &ValidationError{Field: "symbols", Message: "symbols or tag is required"}
+	}
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// GetExportsRequest represents query parameters for listing export jobs
+type GetExportsRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending running completed failed expired"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetExportsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetExportsRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
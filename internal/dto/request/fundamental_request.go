@@ -0,0 +1,39 @@
+package request
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxFundamentalsPerRequest is the maximum number of periods of one JSON upload
+const MaxFundamentalsPerRequest = 1000
+
+// FundamentalInput represents the figures of one symbol and fiscal period
+type FundamentalInput struct {
+	Symbol            string              `json:"symbol" validate:"required,ticker"`
+	Period            string              `json:"period" validate:"required,max=8"` // 2024Q1, 2024FY, FY2024...
+	PeriodEnd         time.Time           `json:"period_end" validate:"required"`
+	EPS               decimal.NullDecimal `json:"eps"`
+	Revenue           decimal.NullDecimal `json:"revenue"`
+	NetIncome         decimal.NullDecimal `json:"net_income"`
+	SharesOutstanding *uint64             `json:"shares_outstanding"`
+}
+
+// UploadFundamentalsRequest represents the JSON body of a fundamentals upload
+type UploadFundamentalsRequest struct {
+	Fundamentals []FundamentalInput `json:"fundamentals" validate:"required,min=1,max=1000,dive"`
+}
+
+// GetFundamentalsRequest represents query parameters for the fundamentals of a symbol
+type GetFundamentalsRequest struct {
+	PeriodType string `query:"period_type" validate:"omitempty,oneof=quarter annual"`
+	Limit      int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// SetDefaults sets the default number of periods
+func (r *GetFundamentalsRequest) SetDefaults() {
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+}
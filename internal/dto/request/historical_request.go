@@ -1,19 +1,67 @@
 package request
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/go-historical-data/pkg/apperror"
 )
 
+// SelectableFields lists the response fields that can be requested via the fields parameter
+var SelectableFields = []string{
+	"id", "symbol", "date", "open", "high", "low", "close", "volume", "open_interest", "number_of_trades",
+	"source_id", "created_at", "updated_at",
+}
+
+// Includable lists the optional sections that can be requested via the include parameter
+var Includable = []string{"derived", "attributes"}
+
 // GetDataRequest represents query parameters for retrieving historical data
 type GetDataRequest struct {
-	Symbol    string    `query:"symbol" validate:"omitempty,min=1,max=20"`
-	StartDate time.Time `query:"start_date" validate:"omitempty"`
-	EndDate   time.Time `query:"end_date" validate:"omitempty"`
-	Page      int       `query:"page" validate:"omitempty,min=1"`
-	Limit     int       `query:"limit" validate:"omitempty,min=1,max=1000"`
+	Symbol     string    `query:"symbol" validate:"omitempty,ticker"`
+	StartDate  time.Time `query:"start_date" validate:"omitempty"`
+	EndDate    time.Time `query:"end_date" validate:"omitempty"`
+	Page       int       `query:"page" validate:"omitempty,min=1"`
+	Limit      int       `query:"limit" validate:"omitempty,min=1,max=1000"`
+	Fields     string    `query:"fields" validate:"omitempty,max=200"`
+	Sort       string    `query:"sort" validate:"omitempty,oneof=date symbol volume close"`
+	SortDir    string    `query:"sort_dir" validate:"omitempty,oneof=asc desc ASC DESC"`
+	Format     string    `query:"format" validate:"omitempty,oneof=json csv"`
+	Source     string    `query:"source" validate:"omitempty,max=255"` // upload filename or provider name
+	SourceID   uint64    `query:"source_id" validate:"omitempty"`
+	Tag        string    `query:"tag" validate:"omitempty,max=32"`             // symbols carrying the tag
+	ConvertTo  string    `query:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+	Adjustment string    `query:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	Include    string    `query:"include" validate:"omitempty,max=100"`           // comma-separated, see Includable
+	VWAPWindow int       `query:"vwap_window" validate:"omitempty,min=1,max=250"` // bars, with include=derived
+	ATRWindow  int       `query:"atr_window" validate:"omitempty,min=1,max=250"`  // bars, with include=derived
+	// IncludeTotal=false skips counting the matching rows; pagination then only reports has_next
+	IncludeTotal *bool  `query:"include_total"`
+	Count        string `query:"count" validate:"omitempty,oneof=exact approximate"`
+	TZ           string `query:"tz" validate:"omitempty,timezone"` // IANA zone created_at and updated_at are rendered in
+	Annotate     string `query:"annotate" validate:"omitempty,oneof=earnings"`
+	// Bars without open interest or number of trades never match these bounds
+	MinOpenInterest   uint64 `query:"min_open_interest"`
+	MaxOpenInterest   uint64 `query:"max_open_interest"`
+	MinNumberOfTrades uint64 `query:"min_number_of_trades"`
+	MaxNumberOfTrades uint64 `query:"max_number_of_trades"`
+	// Fill returns one entry per day of the range, filling dates without a bar
+	Fill     string `query:"fill" validate:"omitempty,oneof=none forward zero null"`
+	FillDays string `query:"fill_days" validate:"omitempty,oneof=weekdays calendar"`
 }
 
-// SetDefaults sets default values for pagination
+// MaxFillDays bounds the date range of a filled query
+const MaxFillDays = 3660
+
+// Default windows of the derived fields
+const (
+	DefaultVWAPWindow = 20
+	DefaultATRWindow  = 14
+)
+
+// SetDefaults sets default values for pagination and reduces the dates to calendar dates
 func (r *GetDataRequest) SetDefaults() {
 	if r.Page == 0 {
 		r.Page = 1
@@ -21,6 +69,66 @@ func (r *GetDataRequest) SetDefaults() {
 	if r.Limit == 0 {
 		r.Limit = 100
 	}
+	if r.Sort == "" {
+		r.Sort = "date"
+	}
+	if r.SortDir == "" {
+		r.SortDir = "desc"
+	}
+	r.SortDir = strings.ToLower(r.SortDir)
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+	r.Tag = NormalizeTag(r.Tag)
+	if r.Fill == "none" {
+		r.Fill = ""
+	}
+	if r.FillDays == "" {
+		r.FillDays = "weekdays"
+	}
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+	if r.VWAPWindow == 0 {
+		r.VWAPWindow = DefaultVWAPWindow
+	}
+	if r.ATRWindow == 0 {
+		r.ATRWindow = DefaultATRWindow
+	}
+}
+
+// CountMode returns how the total of matching rows is computed: "none" when
+// include_total=false, otherwise the requested count (exact by default)
+func (r *GetDataRequest) CountMode() string {
+	if r.IncludeTotal != nil && !*r.IncludeTotal {
+		return "none"
+	}
+	if r.Count == "" {
+		return "exact"
+	}
+	return r.Count
+}
+
+// WantsDerived reports whether the derived fields were requested
+func (r *GetDataRequest) WantsDerived() bool {
+	return includes(r.Include, "derived")
+}
+
+// WantsAttributes reports whether the bar attributes were requested
+func (r *GetDataRequest) WantsAttributes() bool {
+	return includes(r.Include, "attributes")
+}
+
+// WantsEarnings reports whether bars on earnings report dates are annotated
+func (r *GetDataRequest) WantsEarnings() bool {
+	return r.Annotate == "earnings"
+}
+
+// WantsFill reports whether dates without a bar are filled
+func (r *GetDataRequest) WantsFill() bool {
+	return r.Fill != "" && r.Fill != "none"
+}
+
+// Location returns the zone timestamps are rendered in, UTC by default
+func (r *GetDataRequest) Location() *time.Location {
+	return loadLocation(r.TZ)
 }
 
 // GetOffset calculates the offset for pagination
@@ -28,14 +136,210 @@ func (r *GetDataRequest) GetOffset() int {
 	return (r.Page - 1) * r.Limit
 }
 
-// Validate validates the date range
+// Validate validates the date range and the requested fields
 func (r *GetDataRequest) Validate() error {
 	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
 		return ErrInvalidDateRange
 	}
+	if _, err := r.GetFields(); err != nil {
+		return err
+	}
+	if r.MaxOpenInterest != 0 && r.MinOpenInterest > r.MaxOpenInterest {
+		return &ValidationError{Field: "min_open_interest", Message: "min_open_interest must not exceed max_open_interest"}
+	}
+	if r.MaxNumberOfTrades != 0 && r.MinNumberOfTrades > r.MaxNumberOfTrades {
+		return &ValidationError{Field: "min_number_of_trades", Message: "min_number_of_trades must not exceed max_number_of_trades"}
+	}
+	for _, section := range strings.Split(r.Include, ",") {
+		section = strings.ToLower(strings.TrimSpace(section))
+		if section != "" && !slices.Contains(Includable, section) {
+			return &ValidationError{
+				Field:   "include",
+				Message: fmt.Sprintf("unknown include '%s', allowed values: %s", section, strings.Join(Includable, ", ")),
+			}
+		}
+	}
+	if r.WantsFill() {
+		if r.Symbol == "" || r.StartDate.IsZero() || r.EndDate.IsZero() {
+			return &ValidationError{Field: "fill", Message: "fill requires symbol, start_date and end_date"}
+		}
+		if r.Sort != "" && r.Sort != "date" {
+			return &ValidationError{Field: "fill", Message: "fill requires sort=date"}
+		}
+		if truncateToDay(r.EndDate).Sub(truncateToDay(r.StartDate)) >= MaxFillDays*24*time.Hour {
+			return &ValidationError{Field: "fill", Message: fmt.Sprintf("fill covers at most %d days", MaxFillDays)}
+		}
+	}
 	return nil
 }
 
+// includes reports whether the comma-separated include parameter names section
+func includes(include, section string) bool {
+	for _, s := range strings.Split(include, ",") {
+		if strings.EqualFold(strings.TrimSpace(s), section) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFields parses the comma-separated fields parameter into a deduplicated list.
+// An empty result means all fields were requested.
+func (r *GetDataRequest) GetFields() ([]string, error) {
+	if strings.TrimSpace(r.Fields) == "" {
+		return nil, nil
+	}
+
+	allowed := make(map[string]bool, len(SelectableFields))
+	for _, f := range SelectableFields {
+		allowed[f] = true
+	}
+
+	seen := make(map[string]bool)
+	fields := make([]string, 0)
+	for _, f := range strings.Split(r.Fields, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" || seen[f] {
+			continue
+		}
+		if !allowed[f] {
+			return nil, &ValidationError{
+				Field:   "fields",
+				Message: fmt.Sprintf("unknown field '%s', allowed fields: %s", f, strings.Join(SelectableFields, ", ")),
+			}
+		}
+		seen[f] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// GetDataByIDRequest represents query parameters for retrieving a single record
+type GetDataByIDRequest struct {
+	ConvertTo  string `query:"convert_to" validate:"omitempty,len=3,alpha"` // ISO 4217 currency code
+	Adjustment string `query:"adjustment" validate:"omitempty,oneof=splits dividends all"`
+	TZ         string `query:"tz" validate:"omitempty,timezone"` // IANA zone created_at and updated_at are rendered in
+	Include    string `query:"include" validate:"omitempty,oneof=attributes"`
+}
+
+// WantsAttributes reports whether the bar attributes were requested
+func (r *GetDataByIDRequest) WantsAttributes() bool {
+	return r.Include == "attributes"
+}
+
+// Normalize upper-cases the currency code
+func (r *GetDataByIDRequest) Normalize() {
+	r.ConvertTo = strings.ToUpper(r.ConvertTo)
+}
+
+// Location returns the zone timestamps are rendered in, UTC by default
+func (r *GetDataByIDRequest) Location() *time.Location {
+	return loadLocation(r.TZ)
+}
+
+// LookupDataRequest represents the body of a batch lookup of bars by their
+// (symbol, date) pairs
+type LookupDataRequest struct {
+	Pairs []SymbolDate `json:"pairs" validate:"required,min=1,max=10000,dive"`
+}
+
+// SymbolDate identifies a bar by symbol and calendar date
+type SymbolDate struct {
+	Symbol string `json:"symbol" validate:"required,ticker"`
+	Date   string `json:"date" validate:"required,isodate"` // YYYY-MM-DD, as bars are returned
+}
+
+// Normalize upper-cases the symbols and drops repeated pairs, keeping the
+// first occurrence
+func (r *LookupDataRequest) Normalize() {
+	seen := make(map[SymbolDate]bool, len(r.Pairs))
+	pairs := make([]SymbolDate, 0, len(r.Pairs))
+	for _, pair := range r.Pairs {
+		pair.Symbol = strings.ToUpper(strings.TrimSpace(pair.Symbol))
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+		pairs = append(pairs, pair)
+	}
+	r.Pairs = pairs
+}
+
+// AsOfRequest represents the query parameters of an as-of lookup
+type AsOfRequest struct {
+	Date string `query:"date" validate:"required,isodate"` // YYYY-MM-DD
+}
+
+// AsOfBatchRequest represents the body of a batch as-of lookup
+type AsOfBatchRequest struct {
+	Pairs []SymbolDate `json:"pairs" validate:"required,min=1,max=10000,dive"`
+}
+
+// Normalize upper-cases the symbols; repeated pairs are kept so results
+// line up with the request
+func (r *AsOfBatchRequest) Normalize() {
+	for i := range r.Pairs {
+		r.Pairs[i].Symbol = strings.ToUpper(strings.TrimSpace(r.Pairs[i].Symbol))
+	}
+}
+
+// DataOnDateRequest represents query parameters for the bars of every symbol
+// on one date
+type DataOnDateRequest struct {
+	Tag      string `query:"tag" validate:"omitempty,max=32"`      // symbols carrying the tag
+	Exchange string `query:"exchange" validate:"omitempty,max=32"` // symbols listed on the exchange
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination and normalizes the tag
+func (r *DataOnDateRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.Tag = NormalizeTag(r.Tag)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *DataOnDateRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// MoversRequest represents query parameters for the top movers on one date
+type MoversRequest struct {
+	Tag       string `query:"tag" validate:"omitempty,max=32"`      // symbols carrying the tag
+	Exchange  string `query:"exchange" validate:"omitempty,max=32"` // symbols listed on the exchange
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Direction string `query:"direction" validate:"omitempty,oneof=gainers losers both"`
+}
+
+// SetDefaults sets the default limit and direction and normalizes the tag
+func (r *MoversRequest) SetDefaults() {
+	if r.Limit == 0 {
+		r.Limit = 10
+	}
+	if r.Direction == "" {
+		r.Direction = "both"
+	}
+	r.Tag = NormalizeTag(r.Tag)
+}
+
+// loadLocation returns the named zone, falling back to UTC for an empty or
+// unknown name (the validator rejects unknown names beforehand)
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 // ErrInvalidDateRange is returned when start_date is after end_date
 var ErrInvalidDateRange = &ValidationError{
 	Field:   "date_range",
@@ -51,3 +355,92 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return e.Message
 }
+
+// AppError answers the error with 400
+func (e *ValidationError) AppError() *apperror.Error {
+	return apperror.BadRequest(e.Message, nil)
+}
+
+// UploadCSVRequest represents the admin-only ingestion tuning overrides of a CSV upload
+type UploadCSVRequest struct {
+	BatchSize          int     `query:"batch_size" validate:"omitempty,min=1,max=10000"`
+	MaxParallelBatches int     `query:"max_parallel_batches" validate:"omitempty,min=1,max=32"`
+	MaxFileSize        int64   `query:"max_file_size" validate:"omitempty,min=1"`
+	MaxErrors          int     `query:"max_errors" validate:"omitempty,min=1"`
+	MaxErrorRate       float64 `query:"max_error_rate" validate:"omitempty,gt=0,max=100"`
+	UnknownSymbols     string  `query:"unknown_symbols" validate:"omitempty,oneof=register reject allow"`
+	OverrideLocks      bool    `query:"override_locks"`                          // admins only: upload into frozen ranges
+	Transforms         string  `query:"transforms" validate:"omitempty,max=200"` // comma-separated, applied in order
+	CaptureAttributes  bool    `query:"capture_attributes"`                      // keep unmapped columns as bar attributes
+	Staged             bool    `query:"staged"`                                  // validate the rows in the staging table and merge them in one statement
+}
+
+// GetTransforms splits the comma-separated transforms parameter
+func (r *UploadCSVRequest) GetTransforms() []string {
+	var transforms []string
+	for _, t := range strings.Split(r.Transforms, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			transforms = append(transforms, t)
+		}
+	}
+	return transforms
+}
+
+// HasOverrides reports whether any tuning override is set
+func (r *UploadCSVRequest) HasOverrides() bool {
+	return r.BatchSize > 0 || r.MaxParallelBatches > 0 || r.MaxFileSize > 0 || r.MaxErrors > 0 || r.MaxErrorRate > 0 || r.UnknownSymbols != ""
+}
+
+// maxGenerateYears bounds the range of a synthetic data generation
+const maxGenerateYears = 100
+
+// GenerateDataRequest represents the body of a synthetic data generation.
+// Symbols names the generated symbols; without them, Count symbols are
+// named SYN0001 onwards.
+type GenerateDataRequest struct {
+	Symbols    []string  `json:"symbols" validate:"omitempty,max=1000,dive,required,ticker"`
+	Count      int       `json:"count" validate:"omitempty,min=1,max=1000"`
+	StartDate  time.Time `json:"start_date" validate:"required"`
+	EndDate    time.Time `json:"end_date" validate:"required"`
+	Seed       int64     `json:"seed"`                                          // the same seed generates the same bars
+	Drift      float64   `json:"drift" validate:"omitempty,min=-0.05,max=0.05"` // daily, 0 = 0.0003
+	Volatility float64   `json:"volatility" validate:"omitempty,min=0,max=0.5"` // daily, 0 = 0.02
+}
+
+// Normalize upper-cases and de-duplicates symbols, truncates dates to UTC days
+// and defaults to 10 generated symbol names
+func (r *GenerateDataRequest) Normalize() {
+	seen := make(map[string]bool, len(r.Symbols))
+	symbols := make([]string, 0, len(r.Symbols))
+	for _, s := range r.Symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		symbols = append(symbols, s)
+	}
+	r.Symbols = symbols
+	if len(r.Symbols) == 0 && r.Count == 0 {
+		r.Count = 10
+	}
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// Validate validates the date range and the choice of symbols
+func (r *GenerateDataRequest) Validate() error {
+	if len(r.Symbols) > 0 && r.Count > 0 {
+		return &ValidationError{Field: "count", Message: "give either symbols or count"}
+	}
+	if r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	if r.EndDate.After(time.Now().UTC()) {
+		return &ValidationError{Field: "end_date", Message: "end_date cannot be in the future"}
+	}
+	if r.EndDate.After(r.StartDate.AddDate(maxGenerateYears, 0, 0)) {
+		return &ValidationError{Field: "end_date", Message: fmt.Sprintf("the range cannot exceed %d years", maxGenerateYears)}
+	}
+	return nil
+}
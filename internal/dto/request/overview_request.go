@@ -0,0 +1,13 @@
+package request
+
+// GetOverviewRequest represents query parameters of the operational overview
+type GetOverviewRequest struct {
+	Limit int `query:"limit" validate:"omitempty,min=1,max=100"` // recent uploads listed
+}
+
+// SetDefaults sets the default number of recent uploads
+func (r *GetOverviewRequest) SetDefaults() {
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+}
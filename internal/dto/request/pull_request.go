@@ -0,0 +1,24 @@
+package request
+
+// GetPullFilesRequest represents query parameters for listing the files
+// pulled from a source
+type GetPullFilesRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=ingested failed"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetPullFilesRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetPullFilesRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
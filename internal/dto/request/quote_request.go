@@ -0,0 +1,39 @@
+package request
+
+import (
+	"time"
+)
+
+// GetQuotesRequest represents query parameters for retrieving bid/ask quotes
+type GetQuotesRequest struct {
+	Symbol    string    `query:"symbol" validate:"omitempty,ticker"`
+	StartDate time.Time `query:"start_date" validate:"omitempty"`
+	EndDate   time.Time `query:"end_date" validate:"omitempty"`
+	Page      int       `query:"page" validate:"omitempty,min=1"`
+	Limit     int       `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination and reduces the dates to calendar dates
+func (r *GetQuotesRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetQuotesRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// Validate validates the date range
+func (r *GetQuotesRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package request
+
+import (
+	"strings"
+	"time"
+)
+
+// CreateShareRequest represents the data query a share link answers
+type CreateShareRequest struct {
+	Symbol    string    `json:"symbol" validate:"required,ticker"`
+	StartDate time.Time `json:"start_date" validate:"omitempty"`
+	EndDate   time.Time `json:"end_date" validate:"omitempty"`
+	Format    string    `json:"format" validate:"omitempty,oneof=json csv"`
+	Fields    string    `json:"fields" validate:"omitempty,max=200"`
+	Limit     int       `json:"limit" validate:"omitempty,min=1,max=1000"` // rows per page of the shared query
+	TTL       int       `json:"ttl" validate:"omitempty,min=1"`            // seconds the link stays valid
+}
+
+// Normalize upper-cases the symbol, truncates dates to UTC days and defaults
+// the format to csv
+func (r *CreateShareRequest) Normalize() {
+	r.Symbol = strings.ToUpper(strings.TrimSpace(r.Symbol))
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+	if r.Format == "" {
+		r.Format = "csv"
+	}
+}
+
+// Validate validates the date range
+func (r *CreateShareRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
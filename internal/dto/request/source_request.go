@@ -0,0 +1,25 @@
+package request
+
+// GetSourcesRequest represents query parameters for listing data sources
+type GetSourcesRequest struct {
+	Kind   string `query:"kind" validate:"omitempty,oneof=upload backfill"`
+	Name   string `query:"name" validate:"omitempty,max=255"`
+	Tenant string `query:"tenant" validate:"omitempty,max=64"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetSourcesRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetSourcesRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
@@ -0,0 +1,150 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UpsertSymbolRequest represents the body of a symbol metadata update
+type UpsertSymbolRequest struct {
+	Name     string `json:"name" validate:"omitempty,max=255"`
+	Exchange string `json:"exchange" validate:"omitempty,max=32"`
+	Currency string `json:"currency" validate:"required,len=3,alpha"`
+	// Tags replaces the tags of the symbol; omitted keeps them, [] clears them
+	Tags *[]string `json:"tags" validate:"omitempty,max=50,dive,required,max=32"`
+}
+
+// Normalize upper-cases the currency and exchange codes and lower-cases and
+// de-duplicates the tags
+func (r *UpsertSymbolRequest) Normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Exchange = strings.ToUpper(strings.TrimSpace(r.Exchange))
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	if r.Tags != nil {
+		tags := NormalizeTags(*r.Tags)
+		r.Tags = &tags
+	}
+}
+
+// Validate checks the characters of the tags
+func (r *UpsertSymbolRequest) Validate() error {
+	if r.Tags == nil {
+		return nil
+	}
+	for _, tag := range *r.Tags {
+		if !ValidTag(tag) {
+			return &ValidationError{Field: "tags", Message: fmt.Sprintf("invalid tag %q: use letters, digits, '-', '_' and '.'", tag)}
+		}
+	}
+	return nil
+}
+
+// NormalizeTags lower-cases, trims and de-duplicates tags, dropping empty
+// ones and keeping their order
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// NormalizeTag lower-cases and trims a tag, as tags are stored
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// ValidTag reports whether a normalized tag only uses lower-case letters,
+// digits, '-', '_' and '.'
+func ValidTag(tag string) bool {
+	if tag == "" || len(tag) > 32 {
+		return false
+	}
+	for _, r := range tag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// GetSymbolsRequest represents query parameters for listing symbols
+type GetSymbolsRequest struct {
+	Currency string `query:"currency" validate:"omitempty,len=3,alpha"`
+	Exchange string `query:"exchange" validate:"omitempty,max=32"`
+	Tag      string `query:"tag" validate:"omitempty,max=32"`
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetSymbolsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.Currency = strings.ToUpper(r.Currency)
+	r.Exchange = strings.ToUpper(r.Exchange)
+	r.Tag = NormalizeTag(r.Tag)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetSymbolsRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// RenameSymbolRequest represents the body of a symbol rename
+type RenameSymbolRequest struct {
+	From       string `json:"from" validate:"required,ticker"`
+	To         string `json:"to" validate:"required,ticker"`
+	OnConflict string `json:"on_conflict" validate:"omitempty,oneof=fail keep_existing overwrite"`
+}
+
+// Normalize upper-cases the symbols and defaults the collision strategy to fail
+func (r *RenameSymbolRequest) Normalize() {
+	r.From = strings.ToUpper(strings.TrimSpace(r.From))
+	r.To = strings.ToUpper(strings.TrimSpace(r.To))
+	if r.OnConflict == "" {
+		r.OnConflict = "fail"
+	}
+}
+
+// Validate performs the checks that need more than one field
+func (r *RenameSymbolRequest) Validate() error {
+	if r.From == r.To {
+		return &ValidationError{Field: "to", Message: "to must differ from from"}
+	}
+	return nil
+}
+
+// GetCatalogRequest represents query parameters for listing the symbols catalog
+type GetCatalogRequest struct {
+	StaleDays int    `query:"stale_days" validate:"omitempty,min=1"` // symbols without a bar in that many days
+	Tag       string `query:"tag" validate:"omitempty,max=32"`
+	Page      int    `query:"page" validate:"omitempty,min=1"`
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetCatalogRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	r.Tag = NormalizeTag(r.Tag)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetCatalogRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
@@ -0,0 +1,136 @@
+package request
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTimeRange is returned when from is not before to
+var ErrInvalidTimeRange = &ValidationError{
+	Field:   "time_range",
+	Message: "from must be before to",
+}
+
+// GetTicksRequest represents query parameters for listing the ticks of a symbol
+type GetTicksRequest struct {
+	Symbol  string    `query:"symbol" validate:"required,ticker"`
+	From    time.Time `query:"from" validate:"required"` // inclusive, RFC 3339
+	To      time.Time `query:"to" validate:"required"`   // exclusive, RFC 3339
+	Limit   int       `query:"limit" validate:"omitempty,min=1,max=10000"`
+	Cursor  string    `query:"cursor" validate:"omitempty,max=64"`  // next_cursor of the previous page
+	Session string    `query:"session" validate:"omitempty,max=32"` // comma-separated, see TickSessions
+}
+
+// TickSessions lists the trading sessions ticks can be filtered by
+var TickSessions = []string{"pre", "regular", "post"}
+
+// SetDefaults sets the default page size
+func (r *GetTicksRequest) SetDefaults() {
+	if r.Limit == 0 {
+		r.Limit = 1000
+	}
+}
+
+// Validate validates the time range and the sessions
+func (r *GetTicksRequest) Validate() error {
+	if !r.From.Before(r.To) {
+		return ErrInvalidTimeRange
+	}
+	_, err := parseSessions(r.Session)
+	return err
+}
+
+// Sessions returns the requested sessions, empty for all ticks
+func (r *GetTicksRequest) Sessions() []string {
+	sessions, _ := parseSessions(r.Session)
+	return sessions
+}
+
+// Bounds of the interval of bars built from ticks
+const (
+	MinTickBarInterval = time.Second
+	MaxTickBarInterval = 24 * time.Hour
+)
+
+// DefaultTickBars is the number of intervals covered when from is omitted
+const DefaultTickBars = 100
+
+// TickBarsRequest represents query parameters for bars built from ticks
+type TickBarsRequest struct {
+	Interval string    `query:"interval" validate:"required,max=16"` // Go duration, e.g. 15s, 1m, 4h
+	From     time.Time `query:"from" validate:"omitempty"`           // inclusive, RFC 3339
+	To       time.Time `query:"to" validate:"omitempty"`             // exclusive, RFC 3339
+	TZ       string    `query:"tz" validate:"omitempty,timezone"`    // IANA zone bars are aligned to and rendered in, e.g. America/New_York
+	Session  string    `query:"session" validate:"omitempty,max=32"` // comma-separated, see TickSessions
+}
+
+// Location returns the zone bars are aligned to, UTC by default
+func (r *TickBarsRequest) Location() *time.Location {
+	return loadLocation(r.TZ)
+}
+
+// SetDefaults ends the range now and starts it DefaultTickBars intervals
+// before its end when omitted
+func (r *TickBarsRequest) SetDefaults(now time.Time) {
+	if r.To.IsZero() {
+		r.To = now
+	}
+	if r.From.IsZero() {
+		if interval, err := r.GetInterval(); err == nil {
+			r.From = r.To.Add(-DefaultTickBars * interval)
+		}
+	}
+}
+
+// GetInterval parses the interval, a whole number of seconds between
+// MinTickBarInterval and MaxTickBarInterval
+func (r *TickBarsRequest) GetInterval() (time.Duration, error) {
+	interval, err := time.ParseDuration(r.Interval)
+	if err != nil || interval < MinTickBarInterval || interval > MaxTickBarInterval || interval%time.Second != 0 {
+		return 0, &ValidationError{
+			Field:   "interval",
+			Message: fmt.Sprintf("interval must be a whole number of seconds between %s and %s, e.g. 15s, 1m or 4h", MinTickBarInterval, MaxTickBarInterval),
+		}
+	}
+	return interval, nil
+}
+
+// Validate validates the time range, the interval and the sessions
+func (r *TickBarsRequest) Validate() error {
+	if !r.From.Before(r.To) {
+		return ErrInvalidTimeRange
+	}
+	if _, err := r.GetInterval(); err != nil {
+		return err
+	}
+	_, err := parseSessions(r.Session)
+	return err
+}
+
+// Sessions returns the sessions whose ticks make up the bars, empty for all
+// ticks
+func (r *TickBarsRequest) Sessions() []string {
+	sessions, _ := parseSessions(r.Session)
+	return sessions
+}
+
+// parseSessions parses a comma-separated list of TickSessions
+func parseSessions(value string) ([]string, error) {
+	var sessions []string
+	for _, session := range strings.Split(value, ",") {
+		session = strings.ToLower(strings.TrimSpace(session))
+		if session == "" || slices.Contains(sessions, session) {
+			continue
+		}
+		if !slices.Contains(TickSessions, session) {
+			return nil, &ValidationError{
+				Field:   "session",
+				Message: fmt.Sprintf("unknown session '%s', allowed values: %s", session, strings.Join(TickSessions, ", ")),
+			}
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,51 @@
+package request
+
+import (
+	"strings"
+	"time"
+)
+
+// ErrInvalidSeriesRange is returned when start is after end
+var ErrInvalidSeriesRange = &ValidationError{
+	Field:   "time_range",
+	Message: "start must be before or equal to end",
+}
+
+// GetTimeSeriesRequest represents query parameters for retrieving the
+// observations of a generic time series
+type GetTimeSeriesRequest struct {
+	SeriesID string    `query:"series_id" validate:"required,min=1,max=64"`
+	Start    time.Time `query:"start" validate:"omitempty"` // inclusive, RFC 3339
+	End      time.Time `query:"end" validate:"omitempty"`   // inclusive, RFC 3339
+	SortDir  string    `query:"sort_dir" validate:"omitempty,oneof=asc desc ASC DESC"`
+	Page     int       `query:"page" validate:"omitempty,min=1"`
+	Limit    int       `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination and sorting
+func (r *GetTimeSeriesRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+	if r.SortDir == "" {
+		r.SortDir = "desc"
+	}
+	r.SortDir = strings.ToLower(r.SortDir)
+	r.SeriesID = strings.ToUpper(r.SeriesID)
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetTimeSeriesRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+// Validate validates the time range
+func (r *GetTimeSeriesRequest) Validate() error {
+	if !r.Start.IsZero() && !r.End.IsZero() && r.Start.After(r.End) {
+		return ErrInvalidSeriesRange
+	}
+	return nil
+}
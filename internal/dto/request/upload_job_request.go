@@ -0,0 +1,23 @@
+package request
+
+// GetUploadJobsRequest represents query parameters for listing upload jobs
+type GetUploadJobsRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=processing completed failed"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetUploadJobsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetUploadJobsRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
@@ -0,0 +1,32 @@
+package request
+
+import (
+	"time"
+)
+
+// GetUsageRequest represents query parameters for retrieving tenant usage
+type GetUsageRequest struct {
+	StartDate time.Time `query:"start_date" validate:"omitempty"`
+	EndDate   time.Time `query:"end_date" validate:"omitempty"`
+}
+
+// SetDefaults defaults the range to the current calendar month (UTC)
+func (r *GetUsageRequest) SetDefaults(now time.Time) {
+	now = now.UTC()
+	r.StartDate = truncateToDay(r.StartDate)
+	r.EndDate = truncateToDay(r.EndDate)
+	if r.StartDate.IsZero() {
+		r.StartDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if r.EndDate.IsZero() {
+		r.EndDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Validate validates the date range
+func (r *GetUsageRequest) Validate() error {
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.StartDate.After(r.EndDate) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
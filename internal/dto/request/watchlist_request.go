@@ -0,0 +1,63 @@
+package request
+
+import (
+	"strings"
+)
+
+// MaxWatchlistSymbols is the maximum number of symbols of one watchlist
+const MaxWatchlistSymbols = 200
+
+// WatchlistRequest represents the body of a watchlist creation or replacement
+type WatchlistRequest struct {
+	Name    string   `json:"name" validate:"required,max=100"`
+	Symbols []string `json:"symbols" validate:"required,min=1,max=200,dive,required,ticker"`
+}
+
+// Normalize trims the name and upper-cases and de-duplicates symbols, keeping their order
+func (r *WatchlistRequest) Normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+
+	seen := make(map[string]bool, len(r.Symbols))
+	symbols := make([]string, 0, len(r.Symbols))
+	for _, s := range r.Symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		symbols = append(symbols, s)
+	}
+	r.Symbols = symbols
+}
+
+// Validate checks that a name and at least one symbol remain after normalization
+func (r *WatchlistRequest) Validate() error {
+	if r.Name == "" {
+		return &ValidationError{Field: "name", Message: "name must not be blank"}
+	}
+	if len(r.Symbols) == 0 {
+		return &ValidationError{Field: "symbols", Message: "at least one symbol is required"}
+	}
+	return nil
+}
+
+// GetWatchlistsRequest represents query parameters for listing watchlists
+type GetWatchlistsRequest struct {
+	Page  int `query:"page" validate:"omitempty,min=1"`
+	Limit int `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// SetDefaults sets default values for pagination
+func (r *GetWatchlistsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+}
+
+// GetOffset calculates the offset for pagination
+func (r *GetWatchlistsRequest) GetOffset() int {
+	return (r.Page - 1) * r.Limit
+}
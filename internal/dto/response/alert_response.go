@@ -0,0 +1,17 @@
+package response
+
+import (
+	"github.com/go-historical-data/internal/model"
+)
+
+// PaginatedAlertRuleResponse represents paginated alert rules
+type PaginatedAlertRuleResponse struct {
+	Data       []model.AlertRule `json:"data"`
+	Pagination PaginationMeta    `json:"pagination"`
+}
+
+// PaginatedAlertEventResponse represents the paginated history of an alert rule
+type PaginatedAlertEventResponse struct {
+	Data       []model.AlertEvent `json:"data"`
+	Pagination PaginationMeta     `json:"pagination"`
+}
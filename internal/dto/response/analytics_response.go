@@ -0,0 +1,199 @@
+package response
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// CompareResponse represents several symbols aligned on their common dates
+type CompareResponse struct {
+	Metric  string               `json:"metric"`
+	Rebase  float64              `json:"rebase,omitempty"`
+	Symbols []string             `json:"symbols"`
+	Dates   []string             `json:"dates"`  // Format: YYYY-MM-DD
+	Series  map[string][]float64 `json:"series"` // one value per date, rebased when requested
+	Stats   CompareStats         `json:"stats"`
+}
+
+// CompareStats summarizes a comparison
+type CompareStats struct {
+	// Correlation holds the pairwise correlation of period returns; null when undefined
+	Correlation map[string]map[string]*float64 `json:"correlation"`
+	Performance map[string]SymbolPerformance   `json:"performance"`
+	// Fundamentals holds the latest fundamentals of each symbol valued at the
+	// close of the last compared date; only with include=fundamentals
+	Fundamentals map[string]*FundamentalsSnapshot `json:"fundamentals,omitempty"`
+}
+
+// SymbolPerformance describes the change of a symbol's metric over the compared dates
+type SymbolPerformance struct {
+	First     float64 `json:"first"`
+	Last      float64 `json:"last"`
+	ReturnPct float64 `json:"return_pct"`
+	// RelativePct is the return minus the return of the first requested symbol
+	RelativePct float64 `json:"relative_pct"`
+}
+
+// CorrelationResponse represents the return correlations of several symbols
+// over a trailing window
+type CorrelationResponse struct {
+	Symbols      []string `json:"symbols"`
+	Benchmark    string   `json:"benchmark,omitempty"`
+	Window       int      `json:"window"`
+	Observations int      `json:"observations"` // returns on dates common to every symbol
+	StartDate    string   `json:"start_date,omitempty"`
+	EndDate      string   `json:"end_date,omitempty"`
+	// Correlation holds the pairwise correlation of daily returns; null when undefined
+	Correlation map[string]map[string]*float64 `json:"correlation"`
+	// Beta holds each symbol's beta versus the benchmark; null when undefined
+	Beta map[string]*float64 `json:"beta,omitempty"`
+}
+
+// ReturnsResponse represents the return statistics of one symbol
+type ReturnsResponse struct {
+	Symbol              string         `json:"symbol"`
+	Period              string         `json:"period"`
+	StartDate           string         `json:"start_date,omitempty"`
+	EndDate             string         `json:"end_date,omitempty"`
+	Returns             []PeriodReturn `json:"returns"`
+	CumulativeReturnPct float64        `json:"cumulative_return_pct"`
+	MaxDrawdown         MaxDrawdown    `json:"max_drawdown"`
+	// Sharpe is the annualized Sharpe ratio of the period returns; null when undefined
+	Sharpe       *float64 `json:"sharpe"`
+	RiskFreeRate float64  `json:"risk_free_rate"` // annual percent
+}
+
+// PeriodReturn represents the return of the period ending on a date
+type PeriodReturn struct {
+	Date      string  `json:"date"` // Format: YYYY-MM-DD
+	ReturnPct float64 `json:"return_pct"`
+}
+
+// DistributionResponse represents the distribution of the period returns of
+// one symbol
+type DistributionResponse struct {
+	Symbol       string                  `json:"symbol"`
+	Period       string                  `json:"period"`
+	StartDate    string                  `json:"start_date,omitempty"`
+	EndDate      string                  `json:"end_date,omitempty"`
+	Observations int                     `json:"observations"` // number of returns
+	MeanPct      float64                 `json:"mean_pct"`
+	StdDevPct    *float64                `json:"std_dev_pct"` // null with fewer than two returns
+	MinPct       float64                 `json:"min_pct"`
+	MaxPct       float64                 `json:"max_pct"`
+	Percentiles  []ReturnPercentile      `json:"percentiles"`
+	Histogram    []ReturnHistogramBucket `json:"histogram"`
+}
+
+// ReturnPercentile represents one percentile of the returns
+type ReturnPercentile struct {
+	Percentile float64 `json:"percentile"`
+	ReturnPct  float64 `json:"return_pct"`
+}
+
+// ReturnHistogramBucket represents the number of returns in
+// [lower_pct, upper_pct); the last bucket includes its upper bound
+type ReturnHistogramBucket struct {
+	LowerPct float64 `json:"lower_pct"`
+	UpperPct float64 `json:"upper_pct"`
+	Count    int     `json:"count"`
+}
+
+// AggregatesResponse represents the weekly or monthly bars of one symbol
+type AggregatesResponse struct {
+	Symbol   string         `json:"symbol"`
+	Interval string         `json:"interval"`
+	Source   string         `json:"source"` // rollup or daily, the bars the aggregates were read from
+	Bars     []AggregateBar `json:"bars"`
+}
+
+// AggregateBar represents the OHLCV bar of one period
+type AggregateBar struct {
+	PeriodStart string          `json:"period_start"` // Format: YYYY-MM-DD
+	PeriodEnd   string          `json:"period_end"`   // date of the last daily bar of the period
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	Close       decimal.Decimal `json:"close"`
+	Volume      uint64          `json:"volume"`
+	BarCount    int             `json:"bar_count"`
+}
+
+// RebuildRollupsResponse lists the symbols queued for a rollup rebuild
+type RebuildRollupsResponse struct {
+	Symbols []string `json:"symbols"`
+	Queued  int      `json:"queued"`
+}
+
+// MaxDrawdown represents the largest peak-to-trough decline of the closes
+type MaxDrawdown struct {
+	DepthPct   float64 `json:"depth_pct"`
+	PeakDate   string  `json:"peak_date,omitempty"`
+	TroughDate string  `json:"trough_date,omitempty"`
+}
+
+// ChartResponse represents a symbol's metric, downsampled when requested
+type ChartResponse struct {
+	Symbol      string       `json:"symbol"`
+	Metric      string       `json:"metric"`
+	TotalPoints int          `json:"total_points"` // points of the full series
+	Points      []ChartPoint `json:"points"`
+}
+
+// ChartPoint represents one point of a chart series
+type ChartPoint struct {
+	Date  string  `json:"date"` // Format: YYYY-MM-DD
+	Value float64 `json:"value"`
+}
+
+// BacktestResponse represents the outcome of a strategy run against stored data
+type BacktestResponse struct {
+	Symbol         string             `json:"symbol"`
+	Strategy       string             `json:"strategy"`
+	Parameters     BacktestParameters `json:"parameters"`
+	StartDate      string             `json:"start_date"`
+	EndDate        string             `json:"end_date"`
+	InitialCapital float64            `json:"initial_capital"`
+	CommissionBps  float64            `json:"commission_bps"`
+	Trades         []BacktestTrade    `json:"trades"`
+	EquityCurve    []EquityPoint      `json:"equity_curve"`
+	Summary        BacktestSummary    `json:"summary"`
+}
+
+// BacktestParameters represents the parameters of the SMA crossover strategy
+type BacktestParameters struct {
+	FastWindow int `json:"fast_window"`
+	SlowWindow int `json:"slow_window"`
+}
+
+// BacktestTrade represents a round trip; an open trade is valued at the last close
+type BacktestTrade struct {
+	EntryDate  string  `json:"entry_date"` // Format: YYYY-MM-DD
+	EntryPrice float64 `json:"entry_price"`
+	ExitDate   string  `json:"exit_date"` // Format: YYYY-MM-DD
+	ExitPrice  float64 `json:"exit_price"`
+	Quantity   float64 `json:"quantity"`
+	PnL        float64 `json:"pnl"` // net of commissions
+	ReturnPct  float64 `json:"return_pct"`
+	Open       bool    `json:"open"`
+}
+
+// EquityPoint represents the value of the portfolio at a close
+type EquityPoint struct {
+	Date   string  `json:"date"` // Format: YYYY-MM-DD
+	Equity float64 `json:"equity"`
+}
+
+// BacktestSummary summarizes a backtest
+type BacktestSummary struct {
+	FinalEquity         float64 `json:"final_equity"`
+	TotalReturnPct      float64 `json:"total_return_pct"`
+	BuyAndHoldReturnPct float64 `json:"buy_and_hold_return_pct"`
+	MaxDrawdownPct      float64 `json:"max_drawdown_pct"`
+	// Sharpe is the annualized Sharpe ratio of the daily equity returns; null when undefined
+	Sharpe        *float64 `json:"sharpe"`
+	Trades        int      `json:"trades"`
+	WinningTrades int      `json:"winning_trades"`
+	// WinRatePct is the share of closed trades with a profit; null without closed trades
+	WinRatePct  *float64 `json:"win_rate_pct"`
+	ExposurePct float64  `json:"exposure_pct"` // share of bars holding a position
+}
@@ -0,0 +1,11 @@
+package response
+
+import (
+	"github.com/go-historical-data/internal/model"
+)
+
+// PaginatedAuditLogResponse represents paginated audit log entries
+type PaginatedAuditLogResponse struct {
+	Data       []model.AuditLog `json:"data"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
@@ -0,0 +1,23 @@
+package response
+
+import (
+	"github.com/go-historical-data/internal/model"
+)
+
+// BackfillResponse represents a backfill with its chunk counts per status
+type BackfillResponse struct {
+	model.Backfill
+	Chunks map[string]int64 `json:"chunks"`
+}
+
+// PaginatedBackfillResponse represents paginated backfills
+type PaginatedBackfillResponse struct {
+	Data       []model.Backfill `json:"data"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
+
+// PaginatedBackfillChunkResponse represents paginated backfill chunks
+type PaginatedBackfillChunkResponse struct {
+	Data       []model.BackfillChunk `json:"data"`
+	Pagination PaginationMeta        `json:"pagination"`
+}
@@ -0,0 +1,11 @@
+package response
+
+import (
+	"github.com/go-historical-data/internal/model"
+)
+
+// PaginatedDataLockResponse represents paginated data locks
+type PaginatedDataLockResponse struct {
+	Data       []model.DataLock `json:"data"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
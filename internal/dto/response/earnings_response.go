@@ -0,0 +1,39 @@
+package response
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// EarningsEventResponse represents a single earnings report with its surprise
+type EarningsEventResponse struct {
+	ID          uint64              `json:"id"`
+	Symbol      string              `json:"symbol"`
+	Period      string              `json:"period"`
+	ReportDate  string              `json:"report_date"` // Format: YYYY-MM-DD
+	EPSEstimate decimal.NullDecimal `json:"eps_estimate"`
+	EPSActual   decimal.NullDecimal `json:"eps_actual"`
+	// SurprisePct is the actual EPS above the estimate in percent of the
+	// estimate's magnitude; null until both are known
+	SurprisePct *decimal.Decimal `json:"surprise_pct"`
+}
+
+// PaginatedEarningsResponse represents a page of the earnings calendar
+type PaginatedEarningsResponse struct {
+	Data       []EarningsEventResponse `json:"data"`
+	Pagination PaginationMeta          `json:"pagination"`
+}
+
+// EarningsUploadResponse represents the result of an earnings calendar upload
+type EarningsUploadResponse struct {
+	Count   int      `json:"count"`
+	Symbols []string `json:"symbols"`
+	Message string   `json:"message"`
+}
+
+// EarningsAnnotation marks a bar that falls on an earnings report date
+type EarningsAnnotation struct {
+	Period      string              `json:"period"`
+	EPSEstimate decimal.NullDecimal `json:"eps_estimate"`
+	EPSActual   decimal.NullDecimal `json:"eps_actual"`
+	SurprisePct *decimal.Decimal    `json:"surprise_pct"`
+}
@@ -0,0 +1,21 @@
+package response
+
+import (
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+)
+
+// ExportJobResponse represents an export job. A completed export carries a
+// signed download link valid until DownloadExpiresAt.
+type ExportJobResponse struct {
+	model.ExportJob
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
+
+// PaginatedExportJobResponse represents paginated export jobs
+type PaginatedExportJobResponse struct {
+	Data       []model.ExportJob `json:"data"`
+	Pagination PaginationMeta    `json:"pagination"`
+}
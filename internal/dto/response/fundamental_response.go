@@ -0,0 +1,48 @@
+package response
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// FundamentalResponse represents the reported figures of one fiscal period
+type FundamentalResponse struct {
+	Period            string              `json:"period"`
+	PeriodEnd         string              `json:"period_end"` // Format: YYYY-MM-DD
+	EPS               decimal.NullDecimal `json:"eps"`
+	Revenue           decimal.NullDecimal `json:"revenue"`
+	NetIncome         decimal.NullDecimal `json:"net_income"`
+	SharesOutstanding *uint64             `json:"shares_outstanding"`
+	SourceID          *uint64             `json:"source_id"`
+}
+
+// FundamentalsSnapshot joins the latest reported figures of a symbol with a
+// close to value it
+type FundamentalsSnapshot struct {
+	Period            string           `json:"period"`     // latest period reported
+	PeriodEnd         string           `json:"period_end"` // Format: YYYY-MM-DD
+	EPSTTM            *decimal.Decimal `json:"eps_ttm"`    // sum of the last four quarters, else the last fiscal year
+	SharesOutstanding *uint64          `json:"shares_outstanding"`
+	Price             decimal.Decimal  `json:"price"`
+	PriceDate         string           `json:"price_date"` // Format: YYYY-MM-DD
+	PE                *decimal.Decimal `json:"pe"`         // null unless the trailing EPS is positive
+	MarketCap         *decimal.Decimal `json:"market_cap"` // null without shares outstanding
+}
+
+// FundamentalsResponse represents the fundamentals of a symbol
+type FundamentalsResponse struct {
+	Symbol  string                `json:"symbol"`
+	Latest  *FundamentalsSnapshot `json:"latest"` // null without fundamentals or bars
+	Periods []FundamentalResponse `json:"periods"`
+}
+
+// FundamentalUploadResponse represents the result of a fundamentals upload
+type FundamentalUploadResponse struct {
+	SourceID      uint64        `json:"source_id"`
+	TotalRows     int           `json:"total_rows"`
+	SuccessCount  int           `json:"success_count"`
+	FailedCount   int           `json:"failed_count"`
+	Symbols       []string      `json:"symbols,omitempty"`
+	Errors        []CSVRowError `json:"errors,omitempty"`
+	OmittedErrors int           `json:"omitted_errors,omitempty"` // errors beyond the ones listed
+	Message       string        `json:"message"`
+}
@@ -1,27 +1,253 @@
 package response
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // HistoricalDataResponse represents a single historical data record in the response
 type HistoricalDataResponse struct {
-	ID        uint64    `json:"id"`
-	Symbol    string    `json:"symbol"`
-	Date      string    `json:"date"` // Format: YYYY-MM-DD
-	Open      float64   `json:"open"`
-	High      float64   `json:"high"`
-	Low       float64   `json:"low"`
-	Close     float64   `json:"close"`
-	Volume    uint64    `json:"volume"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             uint64          `json:"id"`
+	Symbol         string          `json:"symbol"`
+	Date           string          `json:"date"` // Format: YYYY-MM-DD
+	Open           decimal.Decimal `json:"open"`
+	High           decimal.Decimal `json:"high"`
+	Low            decimal.Decimal `json:"low"`
+	Close          decimal.Decimal `json:"close"`
+	Volume         uint64          `json:"volume"`
+	OpenInterest   *uint64         `json:"open_interest,omitempty"`
+	NumberOfTrades *uint64         `json:"number_of_trades,omitempty"`
+	SourceID       *uint64         `json:"source_id"`
+	Currency       string          `json:"currency,omitempty"` // set when prices were converted with convert_to
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	// Derived is set when derived fields were requested with include=derived
+	Derived *DerivedFields `json:"derived,omitempty"`
+	// Attributes holds the captured vendor columns, set with include=attributes
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Earnings is set with annotate=earnings on bars of a report date
+	Earnings *EarningsAnnotation `json:"earnings,omitempty"`
+	// Filled is set to the fill policy on entries of dates without a bar
+	Filled string `json:"filled,omitempty"`
+}
+
+// Fill policies of the entries of dates without a bar
+const (
+	FillForward = "forward" // prices of the previous close
+	FillZero    = "zero"    // zero prices
+	FillNull    = "null"    // null prices
+)
+
+// NullFilledResponse is the JSON form of an entry filled with null prices
+type NullFilledResponse struct {
+	Symbol   string           `json:"symbol"`
+	Date     string           `json:"date"`
+	Open     *decimal.Decimal `json:"open"`
+	High     *decimal.Decimal `json:"high"`
+	Low      *decimal.Decimal `json:"low"`
+	Close    *decimal.Decimal `json:"close"`
+	Volume   uint64           `json:"volume"`
+	Currency string           `json:"currency,omitempty"`
+	Filled   string           `json:"filled"`
+}
+
+// NullFilled reports whether the entry stands for a date without a bar
+// and null prices
+func (r *HistoricalDataResponse) NullFilled() bool {
+	return r.Filled == FillNull
+}
+
+// DerivedFields holds indicators computed from a bar and the bars preceding it.
+// Windowed values are null until the window is full.
+type DerivedFields struct {
+	TypicalPrice float64  `json:"typical_price"`
+	VWAP         *float64 `json:"vwap"`
+	TrueRange    float64  `json:"true_range"`
+	ATR          *float64 `json:"atr"`
+}
+
+// InLocation renders the timestamps in loc. The date is a calendar date and
+// stays the same in every zone.
+func (r *HistoricalDataResponse) InLocation(loc *time.Location) {
+	r.CreatedAt = r.CreatedAt.In(loc)
+	r.UpdatedAt = r.UpdatedAt.In(loc)
+}
+
+// Project returns a sparse representation containing only the requested fields
+func (r *HistoricalDataResponse) Project(fields []string) map[string]interface{} {
+	projected := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		switch f {
+		case "id":
+			projected[f] = r.ID
+		case "symbol":
+			projected[f] = r.Symbol
+		case "date":
+			projected[f] = r.Date
+		case "open":
+			projected[f] = r.Open
+		case "high":
+			projected[f] = r.High
+		case "low":
+			projected[f] = r.Low
+		case "close":
+			projected[f] = r.Close
+		case "volume":
+			projected[f] = r.Volume
+		case "open_interest":
+			projected[f] = r.OpenInterest
+		case "number_of_trades":
+			projected[f] = r.NumberOfTrades
+		case "source_id":
+			projected[f] = r.SourceID
+		case "created_at":
+			projected[f] = r.CreatedAt
+		case "updated_at":
+			projected[f] = r.UpdatedAt
+		}
+	}
+	if r.NullFilled() {
+		for _, f := range []string{"open", "high", "low", "close"} {
+			if _, ok := projected[f]; ok {
+				projected[f] = nil
+			}
+		}
+	}
+	if r.Filled != "" {
+		projected["filled"] = r.Filled
+	}
+	if r.Currency != "" {
+		projected["currency"] = r.Currency
+	}
+	if r.Derived != nil {
+		projected["derived"] = r.Derived
+	}
+	if len(r.Attributes) > 0 {
+		projected["attributes"] = r.Attributes
+	}
+	if r.Earnings != nil {
+		projected["earnings"] = r.Earnings
+	}
+	return projected
+}
+
+// LookupDataResponse is the result of a batch lookup: the bars found and
+// the pairs without one, both in request order
+type LookupDataResponse struct {
+	Found   []HistoricalDataResponse `json:"found"`
+	Missing []MissingBar             `json:"missing"`
+	// EntitledThrough is the latest bar date a delayed API key may read
+	// (YYYY-MM-DD); later pairs are reported missing
+	EntitledThrough string `json:"entitled_through,omitempty"`
+}
+
+// MissingBar is a looked up (symbol, date) pair without a bar
+type MissingBar struct {
+	Symbol string `json:"symbol"`
+	Date   string `json:"date"` // Format: YYYY-MM-DD
+}
+
+// AsOfResponse is the last bar of a symbol on or before a date
+type AsOfResponse struct {
+	Symbol string `json:"symbol"`
+	AsOf   string `json:"as_of"` // requested date, YYYY-MM-DD
+	// Bar is null when the symbol has no bar on or before the date
+	Bar *HistoricalDataResponse `json:"bar"`
+	// StaleDays is the number of calendar days between the bar and the
+	// requested date, 0 when the date itself has a bar
+	StaleDays int `json:"stale_days"`
+}
+
+// AsOfBatchResponse lists the as-of lookups in request order
+type AsOfBatchResponse struct {
+	Results []AsOfResponse `json:"results"`
+	// EntitledThrough is the latest bar date a delayed API key may read
+	// (YYYY-MM-DD); later dates resolve to the bar on or before it
+	EntitledThrough string `json:"entitled_through,omitempty"`
+}
+
+// MoversResponse lists the symbols with the largest change from their
+// previous close on a date
+type MoversResponse struct {
+	Date    string  `json:"date"`    // Format: YYYY-MM-DD
+	Gainers []Mover `json:"gainers"` // null when not requested
+	Losers  []Mover `json:"losers"`
+	// EntitledThrough is the latest bar date a delayed API key may read (YYYY-MM-DD)
+	EntitledThrough string `json:"entitled_through,omitempty"`
+}
+
+// Mover represents the change of a symbol's close from its previous bar
+type Mover struct {
+	Symbol        string          `json:"symbol"`
+	Close         decimal.Decimal `json:"close"`
+	PreviousClose decimal.Decimal `json:"previous_close"`
+	ChangePct     float64         `json:"change_pct"`
 }
 
 // PaginatedHistoricalDataResponse represents paginated historical data
 type PaginatedHistoricalDataResponse struct {
 	Data       []HistoricalDataResponse `json:"data"`
 	Pagination PaginationMeta           `json:"pagination"`
+	// EntitledThrough is the latest bar date a delayed API key may read (YYYY-MM-DD)
+	EntitledThrough string `json:"entitled_through,omitempty"`
+
+	// Fields restricts the serialized records to a subset of fields (sparse response)
+	Fields []string `json:"-"`
+}
+
+// MarshalJSON serializes only the selected fields when a sparse response was
+// requested, and null prices on entries filled with them
+func (p PaginatedHistoricalDataResponse) MarshalJSON() ([]byte, error) {
+	type paginated PaginatedHistoricalDataResponse
+	if len(p.Fields) == 0 {
+		if !slices.ContainsFunc(p.Data, func(d HistoricalDataResponse) bool { return d.NullFilled() }) {
+			return json.Marshal(paginated(p))
+		}
+
+		data := make([]interface{}, len(p.Data))
+		for i := range p.Data {
+			if !p.Data[i].NullFilled() {
+				data[i] = &p.Data[i]
+				continue
+			}
+			data[i] = NullFilledResponse{
+				Symbol:   p.Data[i].Symbol,
+				Date:     p.Data[i].Date,
+				Volume:   p.Data[i].Volume,
+				Currency: p.Data[i].Currency,
+				Filled:   p.Data[i].Filled,
+			}
+		}
+		return json.Marshal(struct {
+			Data            []interface{}  `json:"data"`
+			Pagination      PaginationMeta `json:"pagination"`
+			EntitledThrough string         `json:"entitled_through,omitempty"`
+		}{
+			Data:            data,
+			Pagination:      p.Pagination,
+			EntitledThrough: p.EntitledThrough,
+		})
+	}
+
+	sparse := make([]map[string]interface{}, len(p.Data))
+	for i := range p.Data {
+		sparse[i] = p.Data[i].Project(p.Fields)
+	}
+
+	return json.Marshal(struct {
+		Data            []map[string]interface{} `json:"data"`
+		Pagination      PaginationMeta           `json:"pagination"`
+		EntitledThrough string                   `json:"entitled_through,omitempty"`
+	}{
+		Data:            sparse,
+		Pagination:      p.Pagination,
+		EntitledThrough: p.EntitledThrough,
+	})
 }
 
 // PaginationMeta contains pagination metadata
@@ -30,14 +256,90 @@ type PaginationMeta struct {
 	Limit      int   `json:"limit"`
 	TotalItems int64 `json:"total_items"`
 	TotalPages int   `json:"total_pages"`
+	// Count is "approximate" for estimated totals and "skipped" when no total
+	// was computed (total_items and total_pages are then 0); omitted when exact
+	Count   string `json:"count,omitempty"`
+	HasNext *bool  `json:"has_next,omitempty"` // set when the count was skipped
 }
 
 // CSVUploadResponse represents the response for CSV file upload
 type CSVUploadResponse struct {
-	TotalRows      int      `json:"total_rows"`
-	SuccessCount   int      `json:"success_count"`
-	FailedCount    int      `json:"failed_count"`
-	ProcessedBytes int64    `json:"processed_bytes"`
-	Errors         []string `json:"errors,omitempty"`
-	Message        string   `json:"message"`
+	JobID          uint64        `json:"job_id"`
+	TotalRows      int           `json:"total_rows"`
+	SuccessCount   int           `json:"success_count"`
+	FailedCount    int           `json:"failed_count"`
+	ProcessedBytes int64         `json:"processed_bytes"`
+	Symbols        []string      `json:"symbols,omitempty"`
+	NewSymbols     []string      `json:"new_symbols,omitempty"` // stored symbols without metadata or bars before the upload
+	Errors         []CSVRowError `json:"errors,omitempty"`
+	OmittedErrors  int           `json:"omitted_errors,omitempty"` // errors beyond the ones listed
+	Message        string        `json:"message"`
+}
+
+// CSVRowError locates a rejected row, and the cell when one is at fault, so
+// clients can highlight it. Code is stable; Message is for humans.
+type CSVRowError struct {
+	Line     int    `json:"line,omitempty"` // 0 when the error is not tied to a line, e.g. a failed batch
+	Field    string `json:"field,omitempty"`
+	RawValue string `json:"raw_value,omitempty"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// String renders the error on one line
+func (e CSVRowError) String() string {
+	switch {
+	case e.Line == 0:
+		return e.Message
+	case e.Field == "":
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	default:
+		return fmt.Sprintf("line %d, %s '%s': %s", e.Line, e.Field, e.RawValue, e.Message)
+	}
+}
+
+// HistoricalDataV2Response represents a single historical data record in the v2 response shape
+type HistoricalDataV2Response struct {
+	ID             uint64    `json:"id"`
+	Symbol         string    `json:"symbol"`
+	Date           string    `json:"date"` // Format: YYYY-MM-DD
+	OHLC           *OHLC     `json:"ohlc"` // null on entries filled with null prices
+	Volume         uint64    `json:"volume"`
+	OpenInterest   *uint64   `json:"open_interest,omitempty"`
+	NumberOfTrades *uint64   `json:"number_of_trades,omitempty"`
+	SourceID       *uint64   `json:"source_id"`
+	Currency       string    `json:"currency,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	Derived    *DerivedFields      `json:"derived,omitempty"`
+	Attributes map[string]string   `json:"attributes,omitempty"`
+	Earnings   *EarningsAnnotation `json:"earnings,omitempty"`
+	Filled     string              `json:"filled,omitempty"`
+}
+
+// OHLC groups open, high, low and close prices
+type OHLC struct {
+	Open  decimal.Decimal `json:"open"`
+	High  decimal.Decimal `json:"high"`
+	Low   decimal.Decimal `json:"low"`
+	Close decimal.Decimal `json:"close"`
+}
+
+// PaginatedHistoricalDataV2Response represents paginated historical data in the v2 response shape
+type PaginatedHistoricalDataV2Response struct {
+	Data interface{} `json:"data"`
+	Meta PageMetaV2  `json:"meta"`
+}
+
+// PageMetaV2 contains v2 pagination metadata
+type PageMetaV2 struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	HasNext    bool   `json:"has_next"`
+	Count      string `json:"count,omitempty"` // "approximate" or "skipped"; omitted when exact
+	// EntitledThrough is the latest bar date a delayed API key may read (YYYY-MM-DD)
+	EntitledThrough string `json:"entitled_through,omitempty"`
 }
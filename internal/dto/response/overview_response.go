@@ -0,0 +1,98 @@
+package response
+
+import (
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+)
+
+// OverviewResponse gathers every section of the operational overview
+type OverviewResponse struct {
+	GeneratedAt   time.Time          `json:"generated_at"`
+	RecentUploads []model.UploadJob  `json:"recent_uploads"`
+	Queues        QueueDepthResponse `json:"queues"`
+	Freshness     []SourceFreshness  `json:"freshness"`
+	ErrorRates    ErrorRatesResponse `json:"error_rates"`
+	Cache         CacheStatsResponse `json:"cache"`
+	Database      DBPoolResponse     `json:"database"`
+}
+
+// QueueDepthResponse counts the unfinished jobs of every queue by status
+type QueueDepthResponse struct {
+	Uploads        map[string]int64 `json:"uploads"`
+	Backfills      map[string]int64 `json:"backfills"`
+	BackfillChunks map[string]int64 `json:"backfill_chunks"` // chunks of unfinished backfills
+	Exports        map[string]int64 `json:"exports"`
+}
+
+// SourceFreshness reports when a source last ingested data. Backfill sources
+// are providers, upload sources are tenants and pull sources are the
+// configured SFTP/FTP drops.
+type SourceFreshness struct {
+	Kind         string     `json:"kind"`
+	Name         string     `json:"name"`
+	LastIngestAt *time.Time `json:"last_ingest_at"`
+	AgeSeconds   *int64     `json:"age_seconds"`
+	Ingests      int64      `json:"ingests"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+// ErrorRatesResponse reports failures of ingestion over a recent window and
+// of HTTP requests since the process started
+type ErrorRatesResponse struct {
+	WindowHours int           `json:"window_hours"`
+	Uploads     JobErrorRate  `json:"uploads"`
+	Backfills   JobErrorRate  `json:"backfills"`
+	HTTP        HTTPErrorRate `json:"http"`
+}
+
+// JobErrorRate reports the failed jobs and the failed rows or chunks of a
+// kind of job. Rates are 0 without jobs.
+type JobErrorRate struct {
+	Jobs        int64   `json:"jobs"`
+	FailedJobs  int64   `json:"failed_jobs"`
+	JobRate     float64 `json:"job_failure_rate"`
+	Items       int64   `json:"items"` // rows of uploads, chunks of backfills
+	FailedItems int64   `json:"failed_items"`
+	ItemRate    float64 `json:"item_failure_rate"`
+}
+
+// HTTPErrorRate reports the responses served since the process started by
+// status class
+type HTTPErrorRate struct {
+	Requests        uint64  `json:"requests"`
+	ClientErrors    uint64  `json:"client_errors"` // 4xx responses
+	ServerErrors    uint64  `json:"server_errors"` // 5xx responses
+	ServerErrorRate float64 `json:"server_error_rate"`
+}
+
+// CacheStatsResponse reports response cache lookups since the process started
+type CacheStatsResponse struct {
+	Enabled  bool              `json:"enabled"`
+	Hits     uint64            `json:"hits"`
+	Misses   uint64            `json:"misses"`
+	HitRatio float64           `json:"hit_ratio"`
+	Routes   []CacheRouteStats `json:"routes"`
+}
+
+// CacheRouteStats reports the response cache lookups of a cached route
+type CacheRouteStats struct {
+	Route    string  `json:"route"`
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// DBPoolResponse reports the database connection pool and circuit breaker
+type DBPoolResponse struct {
+	CircuitBreaker     string `json:"circuit_breaker"`
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDurationMs     int64  `json:"wait_duration_ms"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
@@ -0,0 +1,31 @@
+package response
+
+// PartitionsResponse represents the partitions of the historical data table
+type PartitionsResponse struct {
+	Partitioned bool                `json:"partitioned"`
+	Partitions  []PartitionResponse `json:"partitions"`
+	Pruning     *PruningCheck       `json:"pruning,omitempty"`
+}
+
+// PartitionResponse represents one RANGE partition
+type PartitionResponse struct {
+	Name          string `json:"name"`
+	LessThan      string `json:"less_than"`      // Format: YYYY-MM-DD, or MAXVALUE
+	EstimatedRows int64  `json:"estimated_rows"` // from table statistics
+}
+
+// PruningCheck shows the partitions read by a query over the current month
+type PruningCheck struct {
+	StartDate  string   `json:"start_date"`
+	EndDate    string   `json:"end_date"` // exclusive
+	Expected   []string `json:"expected"`
+	Partitions []string `json:"partitions"`
+	Pruned     bool     `json:"pruned"`
+}
+
+// PartitionMaintenanceResponse represents the outcome of a maintenance run
+type PartitionMaintenanceResponse struct {
+	Created   []string `json:"created"`
+	Dropped   []string `json:"dropped"`
+	Truncated []string `json:"truncated"` // catch-all partition emptied by the retention
+}
@@ -0,0 +1,26 @@
+package response
+
+import (
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+)
+
+// PullSourceStatus represents a configured pull source with its polling state
+type PullSourceStatus struct {
+	model.PullSourceState
+	Protocol   string     `json:"protocol"`
+	Host       string     `json:"host"`
+	Directory  string     `json:"directory"`
+	Pattern    string     `json:"pattern"`
+	ArchiveDir string     `json:"archive_dir,omitempty"`
+	Interval   int        `json:"interval"`
+	Tenant     string     `json:"tenant,omitempty"`
+	NextPollAt *time.Time `json:"next_poll_at,omitempty"`
+}
+
+// PaginatedPullFileResponse represents paginated pulled files
+type PaginatedPullFileResponse struct {
+	Data       []model.PullFile `json:"data"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
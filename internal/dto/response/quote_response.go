@@ -0,0 +1,35 @@
+package response
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// QuoteResponse represents a single bid/ask quote with its derived mid and spread
+type QuoteResponse struct {
+	ID       uint64          `json:"id"`
+	Symbol   string          `json:"symbol"`
+	Date     string          `json:"date"` // Format: YYYY-MM-DD
+	Bid      decimal.Decimal `json:"bid"`
+	Ask      decimal.Decimal `json:"ask"`
+	Mid      decimal.Decimal `json:"mid"`
+	Spread   decimal.Decimal `json:"spread"`
+	SourceID *uint64         `json:"source_id"`
+}
+
+// PaginatedQuoteResponse represents paginated quotes
+type PaginatedQuoteResponse struct {
+	Data       []QuoteResponse `json:"data"`
+	Pagination PaginationMeta  `json:"pagination"`
+}
+
+// QuoteUploadResponse represents the result of a quotes CSV upload
+type QuoteUploadResponse struct {
+	SourceID      uint64        `json:"source_id"`
+	TotalRows     int           `json:"total_rows"`
+	SuccessCount  int           `json:"success_count"`
+	FailedCount   int           `json:"failed_count"`
+	Symbols       []string      `json:"symbols,omitempty"`
+	Errors        []CSVRowError `json:"errors,omitempty"`
+	OmittedErrors int           `json:"omitted_errors,omitempty"` // errors beyond the ones listed
+	Message       string        `json:"message"`
+}
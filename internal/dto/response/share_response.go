@@ -0,0 +1,9 @@
+package response
+
+import "time"
+
+// ShareResponse represents a share link of a data query
+type ShareResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
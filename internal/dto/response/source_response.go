@@ -0,0 +1,11 @@
+package response
+
+import (
+	"github.com/go-historical-data/internal/model"
+)
+
+// PaginatedSourceResponse represents paginated data sources
+type PaginatedSourceResponse struct {
+	Data       []model.Source `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
@@ -0,0 +1,31 @@
+package response
+
+import (
+	"github.com/go-historical-data/internal/model"
+)
+
+// PaginatedSymbolResponse represents paginated symbol metadata
+type PaginatedSymbolResponse struct {
+	Data       []model.Symbol `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+// PaginatedCatalogResponse represents a paginated page of the symbols catalog
+type PaginatedCatalogResponse struct {
+	Data       []model.SymbolSummary `json:"data"`
+	Pagination PaginationMeta        `json:"pagination"`
+}
+
+// RenameSymbolResponse reports the outcome of a symbol rename
+type RenameSymbolResponse struct {
+	From            string   `json:"from"`
+	To              string   `json:"to"`
+	OnConflict      string   `json:"on_conflict"`
+	Collisions      int64    `json:"collisions"`
+	MovedRows       int64    `json:"moved_rows"`
+	DiscardedRows   int64    `json:"discarded_rows"`
+	MovedActions    int64    `json:"moved_actions"`
+	MovedAlertRules int64    `json:"moved_alert_rules"`
+	MetadataMoved   bool     `json:"metadata_moved"`
+	FormerNames     []string `json:"former_names"`
+}
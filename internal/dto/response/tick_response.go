@@ -0,0 +1,49 @@
+package response
+
+import (
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// TickListResponse represents a page of ticks in time order
+type TickListResponse struct {
+	Data []model.Tick `json:"data"`
+	// NextCursor resumes the listing after the last tick; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// TickIngestResponse represents the result of a tick ingestion request
+type TickIngestResponse struct {
+	TotalRecords  int           `json:"total_records"`
+	SuccessCount  int           `json:"success_count"`
+	FailedCount   int           `json:"failed_count"`
+	Symbols       []string      `json:"symbols,omitempty"`
+	Errors        []CSVRowError `json:"errors,omitempty"` // line is the NDJSON line or binary record number
+	OmittedErrors int           `json:"omitted_errors,omitempty"`
+	Message       string        `json:"message"`
+}
+
+// TickBar represents the OHLCV bar of the ticks of one interval
+type TickBar struct {
+	Start  time.Time       `json:"start"`
+	Open   decimal.Decimal `json:"open"`
+	High   decimal.Decimal `json:"high"`
+	Low    decimal.Decimal `json:"low"`
+	Close  decimal.Decimal `json:"close"`
+	Volume decimal.Decimal `json:"volume"`
+	Trades int64           `json:"trades"`
+}
+
+// TickBarsResponse represents the bars of a symbol built from its ticks.
+// Intervals without ticks have no bar. Times are rendered in the requested
+// zone, UTC by default.
+type TickBarsResponse struct {
+	Symbol   string    `json:"symbol"`
+	Interval string    `json:"interval"`
+	TZ       string    `json:"tz,omitempty"` // IANA zone the bars are aligned to
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Bars     []TickBar `json:"bars"`
+}
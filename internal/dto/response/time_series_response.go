@@ -0,0 +1,35 @@
+package response
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TimeSeriesResponse represents a single observation of a generic time series
+type TimeSeriesResponse struct {
+	ID        uint64            `json:"id"`
+	SeriesID  string            `json:"series_id"`
+	Timestamp time.Time         `json:"timestamp"`
+	Value     decimal.Decimal   `json:"value"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	SourceID  *uint64           `json:"source_id"`
+}
+
+// PaginatedTimeSeriesResponse represents paginated time series observations
+type PaginatedTimeSeriesResponse struct {
+	Data       []TimeSeriesResponse `json:"data"`
+	Pagination PaginationMeta       `json:"pagination"`
+}
+
+// TimeSeriesUploadResponse represents the result of a time series CSV upload
+type TimeSeriesUploadResponse struct {
+	SourceID      uint64        `json:"source_id"`
+	TotalRows     int           `json:"total_rows"`
+	SuccessCount  int           `json:"success_count"`
+	FailedCount   int           `json:"failed_count"`
+	Series        []string      `json:"series,omitempty"`
+	Errors        []CSVRowError `json:"errors,omitempty"`
+	OmittedErrors int           `json:"omitted_errors,omitempty"` // errors beyond the ones listed
+	Message       string        `json:"message"`
+}
@@ -0,0 +1,11 @@
+package response
+
+import (
+	"github.com/go-historical-data/internal/model"
+)
+
+// PaginatedUploadJobResponse represents paginated upload jobs
+type PaginatedUploadJobResponse struct {
+	Data       []model.UploadJob `json:"data"`
+	Pagination PaginationMeta    `json:"pagination"`
+}
@@ -0,0 +1,33 @@
+package response
+
+// UsageResponse represents metered usage for a tenant
+type UsageResponse struct {
+	Tenant    string       `json:"tenant"`
+	StartDate string       `json:"start_date"` // Format: YYYY-MM-DD
+	EndDate   string       `json:"end_date"`   // Format: YYYY-MM-DD
+	Totals    UsageTotals  `json:"totals"`
+	Daily     []UsageDaily `json:"daily"`
+	Quota     UsageQuota   `json:"quota"`
+}
+
+// UsageTotals contains aggregated usage counters
+type UsageTotals struct {
+	RowsIngested int64 `json:"rows_ingested"`
+	RowsRead     int64 `json:"rows_read"`
+	BytesIn      int64 `json:"bytes_in"`
+	BytesOut     int64 `json:"bytes_out"`
+}
+
+// UsageDaily contains usage counters for a single day and API key
+type UsageDaily struct {
+	Day    string `json:"day"` // Format: YYYY-MM-DD
+	APIKey string `json:"api_key"`
+	UsageTotals
+}
+
+// UsageQuota describes the monthly ingestion quota of the tenant
+type UsageQuota struct {
+	MonthlyRowQuota     int64 `json:"monthly_row_quota"` // 0 means unlimited
+	MonthlyRowsIngested int64 `json:"monthly_rows_ingested"`
+	Remaining           int64 `json:"remaining,omitempty"`
+}
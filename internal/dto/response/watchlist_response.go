@@ -0,0 +1,40 @@
+package response
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WatchlistResponse represents a watchlist
+type WatchlistResponse struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	Symbols   []string  `json:"symbols"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PaginatedWatchlistResponse represents paginated watchlists
+type PaginatedWatchlistResponse struct {
+	Data       []WatchlistResponse `json:"data"`
+	Pagination PaginationMeta      `json:"pagination"`
+}
+
+// WatchlistDataResponse represents the latest bar of every symbol of a watchlist
+type WatchlistDataResponse struct {
+	ID      uint64           `json:"id"`
+	Name    string           `json:"name"`
+	Symbols []WatchlistQuote `json:"symbols"`
+}
+
+// WatchlistQuote represents the latest bar of a symbol and its change from the previous bar
+type WatchlistQuote struct {
+	Symbol string                  `json:"symbol"`
+	Latest *HistoricalDataResponse `json:"latest"` // null when the symbol has no data
+	// Previous close and the change to the latest close; null without a previous bar
+	PreviousDate  string           `json:"previous_date,omitempty"`
+	PreviousClose *decimal.Decimal `json:"previous_close"`
+	Change        *decimal.Decimal `json:"change"`
+	ChangePct     *float64         `json:"change_pct"`
+}
@@ -0,0 +1,24 @@
+// Package entitlement carries the entitlement window of delayed API keys in
+// the request context. The auth middleware sets it; repositories, services and
+// caches read it to restrict and key what a request may see.
+package entitlement
+
+import (
+	"context"
+	"time"
+)
+
+type contextKey struct{}
+
+// With returns a context restricting the bars read under it to those dated
+// through the given date
+func With(ctx context.Context, through time.Time) context.Context {
+	return context.WithValue(ctx, contextKey{}, through)
+}
+
+// Through returns the latest bar date readable under ctx, false when the
+// caller is entitled to every bar. Repositories apply it to their queries.
+func Through(ctx context.Context) (time.Time, bool) {
+	through, ok := ctx.Value(contextKey{}).(time.Time)
+	return through, ok
+}
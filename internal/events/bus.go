@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// Handler handles a published event
+type Handler func(ctx context.Context, event Event) error
+
+// Bus dispatches domain events to registered subscribers
+type Bus interface {
+	// Subscribe registers a handler for the given event name
+	Subscribe(name string, handler Handler)
+	// Publish synchronously delivers the event to every subscriber of its name.
+	// Handler errors and panics are logged and never propagate to the publisher.
+	Publish(ctx context.Context, event Event)
+}
+
+// bus implements Bus interface
+type bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus creates a new in-process event bus
+func NewBus() Bus {
+	return &bus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers a handler for the given event name
+func (b *bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish delivers the event to every subscriber of its name
+func (b *bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers[event.Name()]
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := b.dispatch(ctx, h, event); err != nil {
+			logger.GetGlobalLogger().Error().
+				Err(err).
+				Str("event", event.Name()).
+				Msg("Event handler failed")
+		}
+	}
+}
+
+// dispatch invokes a single handler, converting panics into errors
+func (b *bus) dispatch(ctx context.Context, h Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+	}()
+	return h(ctx, event)
+}
+
+// Subscribe registers a typed handler for events of type T
+func Subscribe[T Event](b Bus, handler func(ctx context.Context, event T) error) {
+	var zero T
+	b.Subscribe(zero.Name(), func(ctx context.Context, event Event) error {
+		typed, ok := event.(T)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T for %s", event, zero.Name())
+		}
+		return handler(ctx, typed)
+	})
+}
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"time"
+)
+
+// Event names
+const (
+	NameBarsIngested           = "bars.ingested"
+	NameUploadCompleted        = "upload.completed"
+	NameSymbolDelisted         = "symbol.delisted"
+	NameBackfillCompleted      = "backfill.completed"
+	NameSymbolUpdated          = "symbol.updated"
+	NameCorporateActionChanged = "corporate_action.changed"
+	NameSymbolRenamed          = "symbol.renamed"
+	NameRollupsRefreshed       = "rollups.refreshed"
+	NameSeriesIngested         = "series.ingested"
+	NameEarningsChanged        = "earnings.changed"
+)
+
+// Event is implemented by every domain event published on the bus
+type Event interface {
+	// Name returns the stable event name used for subscriber routing
+	Name() string
+}
+
+// BarsIngested is published after a batch of bars has been persisted
+type BarsIngested struct {
+	Symbols    []string  `json:"symbols"`
+	Count      int       `json:"count"`
+	StartDate  time.Time `json:"start_date"`
+	EndDate    time.Time `json:"end_date"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Name implements Event
+func (BarsIngested) Name() string { return NameBarsIngested }
+
+// UploadCompleted is published when a CSV upload has finished processing,
+// including uploads that failed once their job was recorded
+type UploadCompleted struct {
+	JobID          uint64        `json:"job_id"`
+	Tenant         string        `json:"tenant"`
+	Filename       string        `json:"filename"`
+	Status         string        `json:"status"`
+	Message        string        `json:"message"`
+	TotalRows      int           `json:"total_rows"`
+	SuccessCount   int           `json:"success_count"`
+	FailedCount    int           `json:"failed_count"`
+	ProcessedBytes int64         `json:"processed_bytes"`
+	Symbols        []string      `json:"symbols"`
+	Duration       time.Duration `json:"duration"`
+	OccurredAt     time.Time     `json:"occurred_at"`
+}
+
+// Name implements Event
+func (UploadCompleted) Name() string { return NameUploadCompleted }
+
+// SymbolDelisted is published when a symbol stops trading and is retired,
+// such as the old symbol of a rename or merge, which only lives on as a former
+// name of its successor
+type SymbolDelisted struct {
+	Symbol     string    `json:"symbol"`
+	Successor  string    `json:"successor,omitempty"` // symbol the rows moved to
+	DelistedAt time.Time `json:"delisted_at"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Name implements Event
+func (SymbolDelisted) Name() string { return NameSymbolDelisted }
+
+// BackfillCompleted is published when every chunk of a backfill has finished
+type BackfillCompleted struct {
+	BackfillID      uint64        `json:"backfill_id"`
+	Provider        string        `json:"provider"`
+	Status          string        `json:"status"`
+	CompletedChunks int           `json:"completed_chunks"`
+	FailedChunks    int           `json:"failed_chunks"`
+	RowsIngested    int64         `json:"rows_ingested"`
+	Duration        time.Duration `json:"duration"`
+	OccurredAt      time.Time     `json:"occurred_at"`
+}
+
+// Name implements Event
+func (BackfillCompleted) Name() string { return NameBackfillCompleted }
+
+// SymbolUpdated is published when the metadata of a symbol is created or changed
+type SymbolUpdated struct {
+	Symbol     string    `json:"symbol"`
+	Currency   string    `json:"currency"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Name implements Event
+func (SymbolUpdated) Name() string { return NameSymbolUpdated }
+
+// CorporateActionChanged is published when a split or dividend is recorded or removed
+type CorporateActionChanged struct {
+	Symbol     string    `json:"symbol"`
+	Type       string    `json:"type"`
+	ExDate     time.Time `json:"ex_date"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Name implements Event
+func (CorporateActionChanged) Name() string { return NameCorporateActionChanged }
+
+// SymbolRenamed is published when the rows of a symbol are moved to a new symbol
+type SymbolRenamed struct {
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	MovedRows     int64     `json:"moved_rows"`
+	DiscardedRows int64     `json:"discarded_rows"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// Name implements Event
+func (SymbolRenamed) Name() string { return NameSymbolRenamed }
+
+// RollupsRefreshed is published when the weekly and monthly rollups of a symbol
+// have been recomputed after an ingest
+type RollupsRefreshed struct {
+	Symbol     string    `json:"symbol"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Name implements Event
+func (RollupsRefreshed) Name() string { return NameRollupsRefreshed }
+
+// SeriesIngested is published after observations of generic time series have
+// been persisted
+type SeriesIngested struct {
+	Series     []string  `json:"series"`
+	Count      int       `json:"count"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Name implements Event
+func (SeriesIngested) Name() string { return NameSeriesIngested }
+
+// EarningsChanged is published when earnings events have been recorded
+type EarningsChanged struct {
+	Symbols    []string  `json:"symbols"`
+	Count      int       `json:"count"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Name implements Event
+func (EarningsChanged) Name() string { return NameEarningsChanged }
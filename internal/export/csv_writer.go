@@ -0,0 +1,167 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/response"
+)
+
+// ContentTypeCSV is the MIME type of CSV exports
+const ContentTypeCSV = "text/csv"
+
+// DefaultCSVColumns mirrors the upload CSV layout so exports can be re-ingested as-is
+var DefaultCSVColumns = []string{"symbol", "date", "open", "high", "low", "close", "volume"}
+
+// DerivedCSVColumns are appended to the columns when derived fields were requested
+var DerivedCSVColumns = []string{"typical_price", "vwap", "true_range", "atr"}
+
+// EarningsCSVColumns are appended to the columns when bars were annotated with earnings
+var EarningsCSVColumns = []string{"earnings_period", "eps_estimate", "eps_actual"}
+
+// CSVWriter streams historical data records as CSV
+type CSVWriter struct {
+	writer  *csv.Writer
+	columns []string
+	record  []string
+}
+
+// NewCSVWriter creates a CSV writer emitting the given columns; empty columns
+// fall back to DefaultCSVColumns
+func NewCSVWriter(w io.Writer, columns []string) *CSVWriter {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	return &CSVWriter{
+		writer:  csv.NewWriter(w),
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}
+}
+
+// WriteHeader writes the header row
+func (w *CSVWriter) WriteHeader() error {
+	return w.writer.Write(w.columns)
+}
+
+// Write writes a single record
+func (w *CSVWriter) Write(data *response.HistoricalDataResponse) error {
+	for i, col := range w.columns {
+		w.record[i] = formatColumn(data, col)
+	}
+	return w.writer.Write(w.record)
+}
+
+// WriteAll writes all records and flushes the underlying writer
+func (w *CSVWriter) WriteAll(data []response.HistoricalDataResponse) error {
+	for i := range data {
+		if err := w.Write(&data[i]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush flushes buffered data to the underlying writer
+func (w *CSVWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// formatColumn renders a single column value; the prices of entries filled
+// with null prices are empty
+func formatColumn(data *response.HistoricalDataResponse, column string) string {
+	switch column {
+	case "open", "high", "low", "close":
+		if data.NullFilled() {
+			return ""
+		}
+	}
+	switch column {
+	case "id":
+		return strconv.FormatUint(data.ID, 10)
+	case "symbol":
+		return data.Symbol
+	case "date":
+		return data.Date
+	case "open":
+		return data.Open.String()
+	case "high":
+		return data.High.String()
+	case "low":
+		return data.Low.String()
+	case "close":
+		return data.Close.String()
+	case "volume":
+		return strconv.FormatUint(data.Volume, 10)
+	case "open_interest":
+		return formatOptionalUint(data.OpenInterest)
+	case "number_of_trades":
+		return formatOptionalUint(data.NumberOfTrades)
+	case "source_id":
+		return formatOptionalUint(data.SourceID)
+	case "created_at":
+		return data.CreatedAt.Format(time.RFC3339)
+	case "updated_at":
+		return data.UpdatedAt.Format(time.RFC3339)
+	case "typical_price", "vwap", "true_range", "atr":
+		return formatDerived(data.Derived, column)
+	case "earnings_period", "eps_estimate", "eps_actual":
+		return formatEarnings(data.Earnings, column)
+	default:
+		return ""
+	}
+}
+
+// formatOptionalUint renders an optional integer, empty when unset
+func formatOptionalUint(value *uint64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatUint(*value, 10)
+}
+
+// formatDerived renders a derived column value, empty when undefined
+func formatDerived(derived *response.DerivedFields, column string) string {
+	if derived == nil {
+		return ""
+	}
+	var value *float64
+	switch column {
+	case "typical_price":
+		value = &derived.TypicalPrice
+	case "vwap":
+		value = derived.VWAP
+	case "true_range":
+		value = &derived.TrueRange
+	case "atr":
+		value = derived.ATR
+	}
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// formatEarnings renders an earnings column value, empty on other days or
+// when the figure is unknown
+func formatEarnings(earnings *response.EarningsAnnotation, column string) string {
+	if earnings == nil {
+		return ""
+	}
+	switch column {
+	case "earnings_period":
+		return earnings.Period
+	case "eps_estimate":
+		if earnings.EPSEstimate.Valid {
+			return earnings.EPSEstimate.Decimal.String()
+		}
+	case "eps_actual":
+		if earnings.EPSActual.Valid {
+			return earnings.EPSActual.Decimal.String()
+		}
+	}
+	return ""
+}
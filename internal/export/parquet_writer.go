@@ -0,0 +1,406 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/shopspring/decimal"
+)
+
+// ContentTypeParquet is the MIME type of Parquet exports
+const ContentTypeParquet = "application/vnd.apache.parquet"
+
+// parquetRowGroupSize is the number of rows buffered per row group
+const parquetRowGroupSize = 100_000
+
+// Prices are written as DECIMAL(20,8), matching the price columns; 9 bytes
+// hold any 20 digit unscaled value
+const (
+	parquetPricePrecision = 20
+	parquetPriceScale     = 8
+	parquetPriceBytes     = 9
+)
+
+// Parquet enum values of the format's thrift definitions
+const (
+	parquetTypeInt32       = 1
+	parquetTypeInt64       = 2
+	parquetTypeByteArray   = 6
+	parquetTypeFixedLenBA  = 7
+	parquetRequired        = 0
+	parquetConvertedUTF8   = 0
+	parquetConvertedDec    = 5
+	parquetConvertedDate   = 6
+	parquetConvertedUint64 = 14
+	parquetEncodingPlain   = 0
+	parquetCodecGzip       = 2
+	parquetDataPage        = 0
+	parquetEncodingRLE     = 3
+)
+
+// parquetColumn describes a column of the DefaultCSVColumns layout
+type parquetColumn struct {
+	name          string
+	physicalType  int32
+	typeLength    int32
+	convertedType int32
+	precision     int32
+	scale         int32
+}
+
+// parquetColumns mirrors DefaultCSVColumns
+var parquetColumns = []parquetColumn{
+	{name: "symbol", physicalType: parquetTypeByteArray, convertedType: parquetConvertedUTF8},
+	{name: "date", physicalType: parquetTypeInt32, convertedType: parquetConvertedDate},
+	{name: "open", physicalType: parquetTypeFixedLenBA, typeLength: parquetPriceBytes, convertedType: parquetConvertedDec, precision: parquetPricePrecision, scale: parquetPriceScale},
+	{name: "high", physicalType: parquetTypeFixedLenBA, typeLength: parquetPriceBytes, convertedType: parquetConvertedDec, precision: parquetPricePrecision, scale: parquetPriceScale},
+	{name: "low", physicalType: parquetTypeFixedLenBA, typeLength: parquetPriceBytes, convertedType: parquetConvertedDec, precision: parquetPricePrecision, scale: parquetPriceScale},
+	{name: "close", physicalType: parquetTypeFixedLenBA, typeLength: parquetPriceBytes, convertedType: parquetConvertedDec, precision: parquetPricePrecision, scale: parquetPriceScale},
+	{name: "volume", physicalType: parquetTypeInt64, convertedType: parquetConvertedUint64},
+}
+
+// parquetChunk records where a column chunk was written
+type parquetChunk struct {
+	offset           int64
+	compressedSize   int64
+	uncompressedSize int64
+	numValues        int64
+}
+
+// parquetRowGroup records the column chunks of a written row group
+type parquetRowGroup struct {
+	numRows   int64
+	totalSize int64
+	chunks    []parquetChunk
+}
+
+// ParquetWriter writes historical data records as a Parquet file with the
+// DefaultCSVColumns, all required: prices as DECIMAL(20,8), the date as DATE
+// and the volume as an unsigned 64-bit integer. Rows are buffered into row
+// groups of parquetRowGroupSize and each column chunk is a single
+// gzip-compressed, plain-encoded data page.
+type ParquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []bytes.Buffer
+	rows      int64
+	total     int64
+	rowGroups []parquetRowGroup
+	started   bool
+}
+
+// NewParquetWriter creates a Parquet writer; the file is complete once Close
+// returns
+func NewParquetWriter(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{
+		w:       w,
+		columns: make([]bytes.Buffer, len(parquetColumns)),
+	}
+}
+
+// Write buffers a single record, writing a row group once enough rows are buffered
+func (p *ParquetWriter) Write(data *response.HistoricalDataResponse) error {
+	date, err := time.Parse("2006-01-02", data.Date)
+	if err != nil {
+		return fmt.Errorf("invalid date %q: %w", data.Date, err)
+	}
+
+	binary.Write(&p.columns[0], binary.LittleEndian, uint32(len(data.Symbol)))
+	p.columns[0].WriteString(data.Symbol)
+	binary.Write(&p.columns[1], binary.LittleEndian, int32(date.Unix()/86400))
+	for i, price := range []decimal.Decimal{data.Open, data.High, data.Low, data.Close} {
+		if err := writeParquetDecimal(&p.columns[2+i], price); err != nil {
+			return err
+		}
+	}
+	binary.Write(&p.columns[6], binary.LittleEndian, data.Volume)
+
+	p.rows++
+	if p.rows >= parquetRowGroupSize {
+		return p.flushRowGroup()
+	}
+	return nil
+}
+
+// Close writes the buffered rows and the file footer
+func (p *ParquetWriter) Close() error {
+	if err := p.flushRowGroup(); err != nil {
+		return err
+	}
+	if err := p.writeMagic(); err != nil {
+		return err
+	}
+
+	footer := p.fileMetaData()
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	if err := p.write(length[:]); err != nil {
+		return err
+	}
+	return p.write([]byte("PAR1"))
+}
+
+// flushRowGroup writes the buffered rows as a row group, one page per column
+func (p *ParquetWriter) flushRowGroup() error {
+	if p.rows == 0 {
+		return nil
+	}
+	if err := p.writeMagic(); err != nil {
+		return err
+	}
+
+	group := parquetRowGroup{numRows: p.rows, chunks: make([]parquetChunk, len(parquetColumns))}
+	for i := range parquetColumns {
+		raw := p.columns[i].Bytes()
+
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err := zw.Write(raw); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		header := &thriftWriter{}
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(raw)))
+		header.i32(3, int32(compressed.Len()))
+		header.beginStruct(5)
+		header.i32(1, int32(p.rows))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.endStruct()
+		header.stop()
+
+		group.chunks[i] = parquetChunk{
+			offset:           p.offset,
+			compressedSize:   int64(header.buf.Len() + compressed.Len()),
+			uncompressedSize: int64(header.buf.Len() + len(raw)),
+			numValues:        p.rows,
+		}
+		group.totalSize += group.chunks[i].uncompressedSize
+
+		if err := p.write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if err := p.write(compressed.Bytes()); err != nil {
+			return err
+		}
+		p.columns[i].Reset()
+	}
+
+	p.rowGroups = append(p.rowGroups, group)
+	p.total += p.rows
+	p.rows = 0
+	return nil
+}
+
+// fileMetaData encodes the footer describing the schema and row groups
+func (p *ParquetWriter) fileMetaData() []byte {
+	meta := &thriftWriter{}
+	meta.i32(1, 1)
+
+	meta.beginList(2, len(parquetColumns)+1)
+	meta.beginElement()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(parquetColumns)))
+	meta.endElement()
+	for _, col := range parquetColumns {
+		meta.beginElement()
+		meta.i32(1, col.physicalType)
+		if col.typeLength > 0 {
+			meta.i32(2, col.typeLength)
+		}
+		meta.i32(3, parquetRequired)
+		meta.binary(4, col.name)
+		meta.i32(6, col.convertedType)
+		if col.convertedType == parquetConvertedDec {
+			meta.i32(7, col.scale)
+			meta.i32(8, col.precision)
+		}
+		meta.endElement()
+	}
+
+	meta.i64(3, p.total)
+
+	meta.beginList(4, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		meta.beginElement()
+		meta.beginList(1, len(group.chunks))
+		for i, chunk := range group.chunks {
+			meta.beginElement()
+			meta.i64(2, chunk.offset)
+			meta.beginStruct(3)
+			meta.i32(1, parquetColumns[i].physicalType)
+			meta.i32List(2, []int32{parquetEncodingPlain, parquetEncodingRLE})
+			meta.binaryList(3, []string{parquetColumns[i].name})
+			meta.i32(4, parquetCodecGzip)
+			meta.i64(5, chunk.numValues)
+			meta.i64(6, chunk.uncompressedSize)
+			meta.i64(7, chunk.compressedSize)
+			meta.i64(9, chunk.offset)
+			meta.endStruct()
+			meta.endElement()
+		}
+		meta.i64(2, group.totalSize)
+		meta.i64(3, group.numRows)
+		meta.endElement()
+	}
+
+	meta.binary(6, "go-historical-data")
+	meta.stop()
+	return meta.buf.Bytes()
+}
+
+// writeMagic writes the leading magic number before the first row group or footer
+func (p *ParquetWriter) writeMagic() error {
+	if p.started {
+		return nil
+	}
+	p.started = true
+	return p.write([]byte("PAR1"))
+}
+
+// write writes b and advances the file offset
+func (p *ParquetWriter) write(b []byte) error {
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	return err
+}
+
+// writeParquetDecimal appends a price as a big-endian two's complement
+// unscaled value of parquetPriceBytes bytes
+func writeParquetDecimal(buf *bytes.Buffer, price decimal.Decimal) error {
+	value := price.Shift(parquetPriceScale).BigInt()
+	if value.Sign() < 0 {
+		// Two's complement over the fixed width
+		value.Add(value, new(big.Int).Lsh(big.NewInt(1), parquetPriceBytes*8))
+	}
+	if value.BitLen() > parquetPriceBytes*8 {
+		return fmt.Errorf("price %s exceeds DECIMAL(%d,%d)", price, parquetPricePrecision, parquetPriceScale)
+	}
+	var out [parquetPriceBytes]byte
+	value.FillBytes(out[:])
+	buf.Write(out[:])
+	return nil
+}
+
+// thriftWriter encodes structs with the Thrift compact protocol used by
+// Parquet metadata. Nested structs and list elements keep their own last
+// field ID.
+type thriftWriter struct {
+	buf     bytes.Buffer
+	lastID  int16
+	parents []int16
+}
+
+// Compact protocol type IDs
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(uint64(zigzag(int64(id))))
+	}
+	t.lastID = id
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	t.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(zigzag(v))
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+func (t *thriftWriter) listHeader(id int16, size int, elem byte) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elem)
+		return
+	}
+	t.buf.WriteByte(0xF0 | elem)
+	t.varint(uint64(size))
+}
+
+func (t *thriftWriter) i32List(id int16, values []int32) {
+	t.listHeader(id, len(values), thriftI32)
+	for _, v := range values {
+		t.varint(zigzag(int64(v)))
+	}
+}
+
+func (t *thriftWriter) binaryList(id int16, values []string) {
+	t.listHeader(id, len(values), thriftBinary)
+	for _, v := range values {
+		t.varint(uint64(len(v)))
+		t.buf.WriteString(v)
+	}
+}
+
+// beginList starts a list of size structs; each is written between
+// beginElement and endElement
+func (t *thriftWriter) beginList(id int16, size int) {
+	t.listHeader(id, size, thriftStruct)
+}
+
+func (t *thriftWriter) beginElement() {
+	t.parents = append(t.parents, t.lastID)
+	t.lastID = 0
+}
+
+func (t *thriftWriter) endElement() {
+	t.stop()
+	t.lastID = t.parents[len(t.parents)-1]
+	t.parents = t.parents[:len(t.parents)-1]
+}
+
+// beginStruct starts a struct field, closed by endStruct
+func (t *thriftWriter) beginStruct(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginElement()
+}
+
+func (t *thriftWriter) endStruct() {
+	t.endElement()
+}
+
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/response"
+)
+
+// SeriesCSVColumns mirrors the time series upload CSV layout so exports can be
+// re-ingested as-is; field columns follow
+var SeriesCSVColumns = []string{"series_id", "timestamp", "value"}
+
+// WriteSeriesCSV writes time series observations as CSV with a column per
+// field name found in data, sorted after the SeriesCSVColumns, and flushes
+func WriteSeriesCSV(w io.Writer, data []response.TimeSeriesResponse) error {
+	var fields []string
+	for i := range data {
+		for name := range data[i].Fields {
+			if !slices.Contains(fields, name) {
+				fields = append(fields, name)
+			}
+		}
+	}
+	slices.Sort(fields)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append(slices.Clone(SeriesCSVColumns), fields...)); err != nil {
+		return err
+	}
+
+	record := make([]string, len(SeriesCSVColumns)+len(fields))
+	for i := range data {
+		record[0] = data[i].SeriesID
+		record[1] = data[i].Timestamp.Format(time.RFC3339Nano)
+		record[2] = data[i].Value.String()
+		for j, name := range fields {
+			record[len(SeriesCSVColumns)+j] = data[i].Fields[name]
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
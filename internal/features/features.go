@@ -0,0 +1,180 @@
+package features
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// Flags checked by the services. Unset flags take the default of the check.
+const (
+	// ParquetExports allows exports in the parquet format
+	ParquetExports = "parquet_exports"
+	// providerPrefix followed by a provider name gates backfills from that
+	// provider, e.g. provider_binance
+	providerPrefix = "provider_"
+)
+
+// Provider returns the flag gating backfills from a provider
+func Provider(name string) string {
+	return providerPrefix + name
+}
+
+// Flags resolves feature flags from the configuration, overridden by the
+// remote provider when one is configured. Flags is safe for concurrent use.
+type Flags struct {
+	mu     sync.RWMutex
+	static map[string]bool // configuration and FEATURE_* environment variables
+	remote map[string]bool // last document fetched from the remote provider
+
+	remoteURL string
+	interval  time.Duration
+	client    *http.Client
+}
+
+// New creates the flags of a configuration
+func New(cfg config.FeaturesConfig) *Flags {
+	return &Flags{
+		static:    normalize(cfg.Flags),
+		remote:    map[string]bool{},
+		remoteURL: cfg.RemoteURL,
+		interval:  time.Duration(max(cfg.RefreshInterval, 5)) * time.Second,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Enabled reports whether a flag is on, fallback when it is set nowhere
+func (f *Flags) Enabled(name string, fallback bool) bool {
+	name = strings.ToLower(name)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if value, ok := f.remote[name]; ok {
+		return value
+	}
+	if value, ok := f.static[name]; ok {
+		return value
+	}
+	return fallback
+}
+
+// All returns the value of every flag set in the configuration or by the remote provider
+func (f *Flags) All() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	all := make(map[string]bool, len(f.static)+len(f.remote))
+	for name, value := range f.static {
+		all[name] = value
+	}
+	for name, value := range f.remote {
+		all[name] = value
+	}
+	return all
+}
+
+// SetStatic replaces the flags of the configuration on reload and returns the
+// names of the flags added, removed or changed
+func (f *Flags) SetStatic(flags map[string]bool) []string {
+	flags = normalize(flags)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	changed := changedFlags(f.static, flags)
+	f.static = flags
+	return changed
+}
+
+// Run polls the remote provider until ctx is cancelled. It returns at once
+// without a remote provider. A failed poll keeps the flags of the last one.
+func (f *Flags) Run(ctx context.Context) {
+	if f.remoteURL == "" {
+		return
+	}
+	log := logger.GetGlobalLogger()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Msg("Failed to refresh feature flags")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh fetches the flags of the remote provider, a JSON object of booleans
+func (f *Flags) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.remoteURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "go-historical-data")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("feature flag request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("feature flag provider responded with status %d", resp.StatusCode)
+	}
+
+	var flags map[string]bool
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&flags); err != nil {
+		return fmt.Errorf("invalid feature flag document: %w", err)
+	}
+	flags = normalize(flags)
+
+	f.mu.Lock()
+	changed := changedFlags(f.remote, flags)
+	f.remote = flags
+	f.mu.Unlock()
+
+	if len(changed) > 0 {
+		logger.GetGlobalLogger().Info().Strs("flags", changed).Msg("Feature flags changed")
+	}
+	return nil
+}
+
+// normalize lower-cases flag names, as viper does with configuration keys
+func normalize(flags map[string]bool) map[string]bool {
+	normalized := make(map[string]bool, len(flags))
+	for name, value := range flags {
+		normalized[strings.ToLower(name)] = value
+	}
+	return normalized
+}
+
+// changedFlags returns the sorted names of flags added, removed or changed
+func changedFlags(before, after map[string]bool) []string {
+	var changed []string
+	for name, value := range after {
+		if old, ok := before[name]; !ok || old != value {
+			changed = append(changed, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
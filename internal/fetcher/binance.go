@@ -0,0 +1,143 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// BinanceProviderName is the name of the Binance provider
+const BinanceProviderName = "binance"
+
+// binanceKlineLimit is the most klines Binance returns per request
+const binanceKlineLimit = 1000
+
+// binanceIntervals are the Binance kline intervals that divide a day
+var binanceIntervals = map[string]time.Duration{
+	"1m": time.Minute, "3m": 3 * time.Minute, "5m": 5 * time.Minute, "15m": 15 * time.Minute, "30m": 30 * time.Minute,
+	"1h": time.Hour, "2h": 2 * time.Hour, "4h": 4 * time.Hour, "6h": 6 * time.Hour, "8h": 8 * time.Hour, "12h": 12 * time.Hour,
+	"1d": 24 * time.Hour,
+}
+
+// binanceProvider pulls klines from Binance's public /api/v3/klines endpoint
+type binanceProvider struct {
+	opts     CryptoOptions
+	interval time.Duration
+	client   *http.Client
+	pacer    *pacer
+}
+
+// NewBinanceProvider creates a provider for the Binance klines endpoint. Only
+// the symbols of opts.Pairs can be fetched; klines are rolled up into daily bars.
+func NewBinanceProvider(opts CryptoOptions) (Provider, error) {
+	if opts.Interval == "" {
+		opts.Interval = "1d"
+	}
+	interval, ok := binanceIntervals[opts.Interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported binance interval %q", opts.Interval)
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	return &binanceProvider{
+		opts:     opts,
+		interval: interval,
+		client:   &http.Client{Timeout: opts.Timeout},
+		pacer:    newPacer(opts.RequestsPerSecond),
+	}, nil
+}
+
+// Name returns the provider name
+func (p *binanceProvider) Name() string {
+	return BinanceProviderName
+}
+
+// Fetch pages through the klines of symbol between start and end
+func (p *binanceProvider) Fetch(ctx context.Context, symbol string, start, end time.Time) ([]model.HistoricalData, error) {
+	pair, err := p.opts.pair(BinanceProviderName, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	from := start.UTC().Truncate(24 * time.Hour)
+	until := end.UTC().Truncate(24 * time.Hour).Add(24*time.Hour - time.Millisecond)
+
+	var klines []kline
+	for from.Before(until) {
+		params := url.Values{}
+		params.Set("symbol", pair)
+		params.Set("interval", p.opts.Interval)
+		params.Set("startTime", strconv.FormatInt(from.UnixMilli(), 10))
+		params.Set("endTime", strconv.FormatInt(until.UnixMilli(), 10))
+		params.Set("limit", strconv.Itoa(binanceKlineLimit))
+
+		var page []kline
+		err := getJSON(ctx, p.client, p.pacer, BinanceProviderName, p.opts.BaseURL+"/api/v3/klines?"+params.Encode(), func(resp *http.Response) (decodeErr error) {
+			page, decodeErr = parseBinanceKlines(resp)
+			return decodeErr
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		klines = append(klines, page...)
+		if len(page) < binanceKlineLimit {
+			break
+		}
+		from = page[len(page)-1].OpenTime.Add(p.interval)
+	}
+
+	return rollupDaily(symbol, klines, start, end), nil
+}
+
+// parseBinanceKlines parses [openTime, open, high, low, close, volume,
+// closeTime, quoteVolume, trades, ...] arrays; prices and volumes are strings
+func parseBinanceKlines(resp *http.Response) ([]kline, error) {
+	var rows [][]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, err
+	}
+
+	klines := make([]kline, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 9 {
+			return nil, fmt.Errorf("unexpected kline with %d fields", len(row))
+		}
+
+		var openTime int64
+		if err := json.Unmarshal(row[0], &openTime); err != nil {
+			return nil, fmt.Errorf("invalid kline open time %s: %w", row[0], err)
+		}
+
+		var values [6]decimal.Decimal // open, high, low, close, volume, quote volume
+		for i, field := range [6]json.RawMessage{row[1], row[2], row[3], row[4], row[5], row[7]} {
+			if err := json.Unmarshal(field, &values[i]); err != nil {
+				return nil, fmt.Errorf("invalid kline value %s: %w", field, err)
+			}
+		}
+
+		var trades uint64
+		if err := json.Unmarshal(row[8], &trades); err != nil {
+			return nil, fmt.Errorf("invalid kline trade count %s: %w", row[8], err)
+		}
+
+		klines = append(klines, kline{
+			OpenTime:    time.UnixMilli(openTime).UTC(),
+			Open:        values[0],
+			High:        values[1],
+			Low:         values[2],
+			Close:       values[3],
+			Volume:      values[4],
+			QuoteVolume: &values[5],
+			Trades:      &trades,
+		})
+	}
+	return klines, nil
+}
@@ -0,0 +1,148 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// CoinbaseProviderName is the name of the Coinbase provider
+const CoinbaseProviderName = "coinbase"
+
+// coinbaseCandleLimit is the most candles Coinbase returns per request
+const coinbaseCandleLimit = 300
+
+// coinbaseIntervals are the Coinbase candle granularities
+var coinbaseIntervals = map[string]time.Duration{
+	"1m": time.Minute, "5m": 5 * time.Minute, "15m": 15 * time.Minute,
+	"1h": time.Hour, "6h": 6 * time.Hour, "1d": 24 * time.Hour,
+}
+
+// coinbaseProvider pulls candles from the Coinbase Exchange public
+// /products/<pair>/candles endpoint
+type coinbaseProvider struct {
+	opts     CryptoOptions
+	interval time.Duration
+	client   *http.Client
+	pacer    *pacer
+}
+
+// NewCoinbaseProvider creates a provider for the Coinbase candles endpoint.
+// Only the symbols of opts.Pairs can be fetched; candles are rolled up into
+// daily bars.
+func NewCoinbaseProvider(opts CryptoOptions) (Provider, error) {
+	if opts.Interval == "" {
+		opts.Interval = "1d"
+	}
+	interval, ok := coinbaseIntervals[opts.Interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported coinbase interval %q", opts.Interval)
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	return &coinbaseProvider{
+		opts:     opts,
+		interval: interval,
+		client:   &http.Client{Timeout: opts.Timeout},
+		pacer:    newPacer(opts.RequestsPerSecond),
+	}, nil
+}
+
+// Name returns the provider name
+func (p *coinbaseProvider) Name() string {
+	return CoinbaseProviderName
+}
+
+// Fetch requests the candles of symbol between start and end in windows of
+// coinbaseCandleLimit candles
+func (p *coinbaseProvider) Fetch(ctx context.Context, symbol string, start, end time.Time) ([]model.HistoricalData, error) {
+	pair, err := p.opts.pair(CoinbaseProviderName, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	from := start.UTC().Truncate(24 * time.Hour)
+	until := end.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	window := coinbaseCandleLimit * p.interval
+
+	var klines []kline
+	for from.Before(until) {
+		// Both bounds are inclusive, so a window ends one candle early
+		to := from.Add(window - p.interval)
+		if last := until.Add(-p.interval); to.After(last) {
+			to = last
+		}
+
+		params := url.Values{}
+		params.Set("granularity", strconv.Itoa(int(p.interval/time.Second)))
+		params.Set("start", from.Format(time.RFC3339))
+		params.Set("end", to.Format(time.RFC3339))
+
+		var page []kline
+		err := getJSON(ctx, p.client, p.pacer, CoinbaseProviderName, p.opts.BaseURL+"/products/"+url.PathEscape(pair)+"/candles?"+params.Encode(), func(resp *http.Response) (decodeErr error) {
+			page, decodeErr = parseCoinbaseCandles(resp)
+			return decodeErr
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		klines = append(klines, page...)
+		from = to.Add(p.interval)
+	}
+
+	// Candles come newest first within each window
+	sort.Slice(klines, func(i, j int) bool {
+		return klines[i].OpenTime.Before(klines[j].OpenTime)
+	})
+	return rollupDaily(symbol, klines, start, end), nil
+}
+
+// parseCoinbaseCandles parses [time, low, high, open, close, volume] arrays of
+// numbers, time in Unix seconds
+func parseCoinbaseCandles(resp *http.Response) ([]kline, error) {
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+
+	var rows [][]json.Number
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, err
+	}
+
+	klines := make([]kline, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 6 {
+			return nil, fmt.Errorf("unexpected candle with %d fields", len(row))
+		}
+
+		openTime, err := row[0].Int64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid candle time %s: %w", row[0], err)
+		}
+
+		var values [5]decimal.Decimal // low, high, open, close, volume
+		for i := range values {
+			if values[i], err = decimal.NewFromString(row[i+1].String()); err != nil {
+				return nil, fmt.Errorf("invalid candle value %s: %w", row[i+1], err)
+			}
+		}
+
+		klines = append(klines, kline{
+			OpenTime: time.Unix(openTime, 0).UTC(),
+			Low:      values[0],
+			High:     values[1],
+			Open:     values[2],
+			Close:    values[3],
+			Volume:   values[4],
+		})
+	}
+	return klines, nil
+}
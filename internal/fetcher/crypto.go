@@ -0,0 +1,221 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// CryptoOptions configures an exchange kline provider
+type CryptoOptions struct {
+	BaseURL           string
+	Interval          string            // kline interval rolled up into daily bars, e.g. 1h or 1d
+	RequestsPerSecond float64           // pace of requests to the exchange; 0 means unpaced
+	Pairs             map[string]string // upper-case stored symbol -> exchange pair, e.g. BTCUSD -> BTCUSDT
+	Timeout           time.Duration
+}
+
+// NewCryptoProvider creates the kline provider of the named exchange, binance
+// or coinbase
+func NewCryptoProvider(exchange string, opts CryptoOptions) (Provider, error) {
+	pairs := make(map[string]string, len(opts.Pairs))
+	for symbol, pair := range opts.Pairs {
+		pairs[strings.ToUpper(symbol)] = pair
+	}
+	opts.Pairs = pairs
+
+	switch strings.ToLower(exchange) {
+	case BinanceProviderName:
+		return NewBinanceProvider(opts)
+	case CoinbaseProviderName:
+		return NewCoinbaseProvider(opts)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, exchange)
+}
+
+// pair returns the exchange pair of symbol; symbols outside the configured
+// list are refused rather than guessed
+func (o CryptoOptions) pair(exchange, symbol string) (string, error) {
+	pair, ok := o.Pairs[strings.ToUpper(symbol)]
+	if !ok {
+		return "", fmt.Errorf("symbol %q is not configured for %s", symbol, exchange)
+	}
+	return pair, nil
+}
+
+// kline is one exchange candle
+type kline struct {
+	OpenTime    time.Time
+	Open        decimal.Decimal
+	High        decimal.Decimal
+	Low         decimal.Decimal
+	Close       decimal.Decimal
+	Volume      decimal.Decimal // base asset volume
+	QuoteVolume *decimal.Decimal
+	Trades      *uint64
+}
+
+// pacer spaces requests to an exchange and pauses them all when the exchange
+// asks to back off
+type pacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newPacer creates a pacer allowing perSecond requests per second
+func newPacer(perSecond float64) *pacer {
+	p := &pacer{}
+	if perSecond > 0 {
+		p.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return p
+}
+
+// wait blocks until the next request may be sent
+func (p *pacer) wait(ctx context.Context) error {
+	p.mu.Lock()
+	now := time.Now()
+	at := now
+	if p.next.After(now) {
+		at = p.next
+	}
+	p.next = at.Add(p.interval)
+	p.mu.Unlock()
+
+	if delay := at.Sub(now); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// backoff holds every request for d
+func (p *pacer) backoff(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if until := time.Now().Add(d); until.After(p.next) {
+		p.next = until
+	}
+}
+
+// maxRateLimitRetries is how often a request answered with HTTP 429 is retried
+const maxRateLimitRetries = 3
+
+// getJSON paces and sends a GET request, retrying after the delay the
+// exchange asks for when rate limited, and hands the body of a 200 to decode
+func getJSON(ctx context.Context, client *http.Client, p *pacer, exchange, rawURL string, decode func(*http.Response) error) error {
+	for attempt := 0; ; attempt++ {
+		if err := p.wait(ctx); err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("%s request failed: %w", exchange, err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			resp.Body.Close()
+			p.backoff(retryAfter(resp, time.Second<<attempt))
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("%s returned HTTP %d", exchange, resp.StatusCode)
+		}
+
+		err = decode(resp)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s response: %w", exchange, err)
+		}
+		return nil
+	}
+}
+
+// retryAfter returns the delay of the Retry-After header in seconds, or fallback
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return fallback
+}
+
+// rollupDaily merges klines, oldest first, into one bar per UTC day within
+// [start, end]. The volume is truncated to whole units; the exact base and
+// quote volumes are kept as attributes.
+func rollupDaily(symbol string, klines []kline, start, end time.Time) []model.HistoricalData {
+	var bars []model.HistoricalData
+	var volume, quoteVolume decimal.Decimal
+	var hasQuoteVolume bool
+
+	closeBar := func() {
+		if len(bars) == 0 {
+			return
+		}
+		bar := &bars[len(bars)-1]
+		bar.Volume = uint64(volume.IntPart())
+		bar.Attributes = model.Attributes{"volume": volume.String()}
+		if hasQuoteVolume {
+			bar.Attributes["quote_volume"] = quoteVolume.String()
+		}
+	}
+
+	for _, k := range klines {
+		day := k.OpenTime.UTC().Truncate(24 * time.Hour)
+		if !inRange(day, start, end) {
+			continue
+		}
+
+		if len(bars) == 0 || !bars[len(bars)-1].Date.Equal(day) {
+			closeBar()
+			bars = append(bars, model.HistoricalData{
+				Symbol: strings.ToUpper(symbol),
+				Date:   day,
+				Open:   k.Open,
+				High:   k.High,
+				Low:    k.Low,
+			})
+			volume, quoteVolume, hasQuoteVolume = decimal.Zero, decimal.Zero, false
+		}
+
+		bar := &bars[len(bars)-1]
+		bar.High = decimal.Max(bar.High, k.High)
+		bar.Low = decimal.Min(bar.Low, k.Low)
+		bar.Close = k.Close
+		volume = volume.Add(k.Volume)
+		if k.QuoteVolume != nil {
+			quoteVolume = quoteVolume.Add(*k.QuoteVolume)
+			hasQuoteVolume = true
+		}
+		if k.Trades != nil {
+			trades := *k.Trades
+			if bar.NumberOfTrades != nil {
+				trades += *bar.NumberOfTrades
+			}
+			bar.NumberOfTrades = &trades
+		}
+	}
+	closeBar()
+
+	return bars
+}
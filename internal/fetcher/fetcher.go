@@ -0,0 +1,73 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+)
+
+// ErrUnknownProvider is returned when no provider is registered under a name
+var ErrUnknownProvider = errors.New("unknown provider")
+
+// Provider fetches daily bars for a symbol from an external data source
+type Provider interface {
+	// Name is the identifier clients use to select the provider
+	Name() string
+	// Fetch returns the bars of symbol between start and end (inclusive)
+	Fetch(ctx context.Context, symbol string, start, end time.Time) ([]model.HistoricalData, error)
+}
+
+// Registry holds the configured providers by name
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+// NewRegistry creates a registry with the given providers
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider)}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds a provider, replacing any provider with the same name
+func (r *Registry) Register(p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.Name()] = p
+}
+
+// Get returns the provider registered under name
+func (r *Registry) Get(name string) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// Names returns the sorted names of the registered providers
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// inRange reports whether date falls within [start, end] at day granularity
+func inRange(date, start, end time.Time) bool {
+	return !date.Before(start) && !date.After(end)
+}
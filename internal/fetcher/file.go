@@ -0,0 +1,85 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/csvparser"
+)
+
+// FileProviderName is the name of the local file provider
+const FileProviderName = "file"
+
+// fileProvider reads bars from <dir>/<SYMBOL>.csv files in the upload CSV layout
+type fileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a provider reading per-symbol CSV files from dir
+func NewFileProvider(dir string) Provider {
+	return &fileProvider{dir: dir}
+}
+
+// Name returns the provider name
+func (p *fileProvider) Name() string {
+	return FileProviderName
+}
+
+// Fetch reads the bars of symbol between start and end. A missing file yields no bars.
+func (p *fileProvider) Fetch(ctx context.Context, symbol string, start, end time.Time) ([]model.HistoricalData, error) {
+	symbol = strings.ToUpper(symbol)
+	if strings.ContainsAny(symbol, `/\`) || strings.Contains(symbol, "..") {
+		return nil, fmt.Errorf("invalid symbol %q", symbol)
+	}
+
+	f, err := os.Open(filepath.Join(p.dir, symbol+".csv"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	parser := csvparser.NewParser(f)
+	if err := parser.ParseHeader(); err != nil {
+		return nil, fmt.Errorf("invalid CSV header in %s: %w", f.Name(), err)
+	}
+
+	var bars []model.HistoricalData
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		row, err := parser.ParseRow()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row.Symbol != symbol || !inRange(row.Date, start, end) {
+			continue
+		}
+
+		bars = append(bars, model.HistoricalData{
+			Symbol: row.Symbol,
+			Date:   row.Date,
+			Open:   row.Open,
+			High:   row.High,
+			Low:    row.Low,
+			Close:  row.Close,
+			Volume: row.Volume,
+		})
+	}
+
+	return bars, nil
+}
@@ -0,0 +1,115 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// FREDProviderName is the name of the FRED provider
+const FREDProviderName = "fred"
+
+// fredMissingValue marks an observation FRED has no value for
+const fredMissingValue = "."
+
+// fredProvider downloads economic series observations from the FRED API
+type fredProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewFREDProvider creates a provider for the FRED series observations
+// endpoint. The symbol is the FRED series ID (CPIAUCSL, DGS10, UNRATE...).
+func NewFREDProvider(baseURL, apiKey string, timeout time.Duration) Provider {
+	return &fredProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the provider name
+func (p *fredProvider) Name() string {
+	return FREDProviderName
+}
+
+// Fetch downloads the observations of a series between start and end as
+// single-value bars: open, high, low and close all hold the value and the
+// volume is zero
+func (p *fredProvider) Fetch(ctx context.Context, symbol string, start, end time.Time) ([]model.HistoricalData, error) {
+	params := url.Values{}
+	params.Set("series_id", strings.ToUpper(symbol))
+	params.Set("api_key", p.apiKey)
+	params.Set("file_type", "json")
+	params.Set("observation_start", start.Format("2006-01-02"))
+	params.Set("observation_end", end.Format("2006-01-02"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/fred/series/observations?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// The URL carries the API key; report the request without it
+		return nil, fmt.Errorf("fred request failed: %w", errors.Unwrap(err))
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Observations []struct {
+			Date  string `json:"date"`
+			Value string `json:"value"`
+		} `json:"observations"`
+		ErrorMessage string `json:"error_message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to read fred response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Unknown series are answered with HTTP 400 and an error message
+		if body.ErrorMessage != "" {
+			return nil, fmt.Errorf("fred returned HTTP %d: %s", resp.StatusCode, body.ErrorMessage)
+		}
+		return nil, fmt.Errorf("fred returned HTTP %d", resp.StatusCode)
+	}
+
+	bars := make([]model.HistoricalData, 0, len(body.Observations))
+	for _, obs := range body.Observations {
+		if obs.Value == fredMissingValue {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", obs.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fred date %q: %w", obs.Date, err)
+		}
+		if !inRange(date, start, end) {
+			continue
+		}
+		value, err := decimal.NewFromString(obs.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fred value %q on %s: %w", obs.Value, obs.Date, err)
+		}
+
+		bars = append(bars, model.HistoricalData{
+			Symbol: strings.ToUpper(symbol),
+			Date:   date,
+			Open:   value,
+			High:   value,
+			Low:    value,
+			Close:  value,
+		})
+	}
+
+	return bars, nil
+}
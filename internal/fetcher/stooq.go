@@ -0,0 +1,133 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// StooqProviderName is the name of the Stooq provider
+const StooqProviderName = "stooq"
+
+// stooqProvider downloads daily bars from Stooq's CSV endpoint
+type stooqProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewStooqProvider creates a provider for the Stooq CSV download endpoint.
+// Symbols without an exchange suffix are treated as US listings (AAPL -> aapl.us).
+func NewStooqProvider(baseURL string, timeout time.Duration) Provider {
+	return &stooqProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the provider name
+func (p *stooqProvider) Name() string {
+	return StooqProviderName
+}
+
+// Fetch downloads the bars of symbol between start and end
+func (p *stooqProvider) Fetch(ctx context.Context, symbol string, start, end time.Time) ([]model.HistoricalData, error) {
+	ticker := strings.ToLower(symbol)
+	if !strings.Contains(ticker, ".") {
+		ticker += ".us"
+	}
+
+	params := url.Values{}
+	params.Set("s", ticker)
+	params.Set("d1", start.Format("20060102"))
+	params.Set("d2", end.Format("20060102"))
+	params.Set("i", "d")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/q/d/l/?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("stooq request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("stooq returned HTTP %d", resp.StatusCode)
+	}
+
+	return parseStooqCSV(resp.Body, symbol, start, end)
+}
+
+// parseStooqCSV parses "Date,Open,High,Low,Close,Volume" rows. Stooq answers
+// unknown symbols and empty ranges with a plain "No data" body.
+func parseStooqCSV(r io.Reader, symbol string, start, end time.Time) ([]model.HistoricalData, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stooq response: %w", err)
+	}
+	if len(header) < 6 || !strings.EqualFold(header[0], "date") {
+		if strings.HasPrefix(strings.ToLower(strings.Join(header, ",")), "no data") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unexpected stooq response header %q", strings.Join(header, ","))
+	}
+
+	var bars []model.HistoricalData
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stooq response: %w", err)
+		}
+		if len(record) < 6 {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid stooq date %q: %w", record[0], err)
+		}
+		if !inRange(date, start, end) {
+			continue
+		}
+
+		var prices [4]decimal.Decimal
+		for i := range prices {
+			if prices[i], err = decimal.NewFromString(record[i+1]); err != nil {
+				return nil, fmt.Errorf("invalid stooq price %q on %s: %w", record[i+1], record[0], err)
+			}
+		}
+		volume, _ := strconv.ParseFloat(record[5], 64)
+
+		bars = append(bars, model.HistoricalData{
+			Symbol: strings.ToUpper(symbol),
+			Date:   date,
+			Open:   prices[0],
+			High:   prices[1],
+			Low:    prices[2],
+			Close:  prices[3],
+			Volume: uint64(max(volume, 0)),
+		})
+	}
+
+	return bars, nil
+}
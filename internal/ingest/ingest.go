@@ -0,0 +1,107 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-historical-data/internal/model"
+)
+
+// ErrUnknownTransform is returned when no transform is registered under a name
+var ErrUnknownTransform = errors.New("unknown transform")
+
+// Transform rewrites a validated bar before it is stored, e.g. to normalize
+// vendor symbols or rescale prices
+type Transform interface {
+	// Name is the identifier uploads and the configuration use to select the transform
+	Name() string
+	// Apply rewrites bar in place; an error rejects the bar
+	Apply(bar *model.HistoricalData) error
+}
+
+// Chain applies transforms in order
+type Chain []Transform
+
+// Apply runs every transform of the chain on bar, stopping at the first error
+func (c Chain) Apply(bar *model.HistoricalData) error {
+	for _, t := range c {
+		if err := t.Apply(bar); err != nil {
+			return fmt.Errorf("%s: %w", t.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the transforms of the chain, in order
+func (c Chain) Names() []string {
+	names := make([]string, len(c))
+	for i, t := range c {
+		names[i] = t.Name()
+	}
+	return names
+}
+
+// Registry holds the available transforms by name
+type Registry struct {
+	mu         sync.RWMutex
+	transforms map[string]Transform
+}
+
+// NewRegistry creates a registry with the given transforms
+func NewRegistry(transforms ...Transform) *Registry {
+	r := &Registry{transforms: make(map[string]Transform)}
+	for _, t := range transforms {
+		r.Register(t)
+	}
+	return r
+}
+
+// Register adds a transform, replacing any transform with the same name
+func (r *Registry) Register(t Transform) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transforms[t.Name()] = t
+}
+
+// Get returns the transform registered under name
+func (r *Registry) Get(name string) (Transform, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.transforms[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTransform, name)
+	}
+	return t, nil
+}
+
+// Chain resolves the named transforms into a chain applied in the given order
+func (r *Registry) Chain(names []string) (Chain, error) {
+	chain := make(Chain, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		t, err := r.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, t)
+	}
+	return chain, nil
+}
+
+// Names returns the sorted names of the registered transforms
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.transforms))
+	for name := range r.transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,71 @@
+package ingest
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// Names of the built-in transforms
+const (
+	StripSuffixName   = "strip_suffix"
+	PenceToPoundsName = "pence_to_pounds"
+)
+
+// Builtins returns the transforms every registry starts with
+func Builtins() []Transform {
+	return []Transform{
+		NewStripSuffixTransform(),
+		NewPenceToPoundsTransform(),
+	}
+}
+
+// stripSuffixTransform drops the exchange suffix vendors append to symbols
+type stripSuffixTransform struct{}
+
+// NewStripSuffixTransform creates a transform turning "AAPL.US" into "AAPL"
+func NewStripSuffixTransform() Transform {
+	return stripSuffixTransform{}
+}
+
+// Name implements Transform
+func (stripSuffixTransform) Name() string { return StripSuffixName }
+
+// Apply removes everything from the last dot of the symbol
+func (stripSuffixTransform) Apply(bar *model.HistoricalData) error {
+	i := strings.LastIndexByte(bar.Symbol, '.')
+	if i < 0 {
+		return nil
+	}
+	if i == 0 {
+		return errors.New("symbol is only a suffix")
+	}
+	bar.Symbol = bar.Symbol[:i]
+	return nil
+}
+
+// pence is the number of pence in a pound
+var pence = decimal.NewFromInt(100)
+
+// penceToPoundsTransform rescales prices quoted in pence, as London Stock
+// Exchange vendors often quote them, to pounds
+type penceToPoundsTransform struct{}
+
+// NewPenceToPoundsTransform creates a transform dividing prices by 100
+func NewPenceToPoundsTransform() Transform {
+	return penceToPoundsTransform{}
+}
+
+// Name implements Transform
+func (penceToPoundsTransform) Name() string { return PenceToPoundsName }
+
+// Apply divides the open, high, low and close by 100; volume is unchanged
+func (penceToPoundsTransform) Apply(bar *model.HistoricalData) error {
+	bar.Open = bar.Open.Div(pence).Round(model.PriceScale)
+	bar.High = bar.High.Div(pence).Round(model.PriceScale)
+	bar.Low = bar.Low.Div(pence).Round(model.PriceScale)
+	bar.Close = bar.Close.Div(pence).Round(model.PriceScale)
+	return nil
+}
@@ -0,0 +1,17 @@
+package ingest_test
+
+import (
+	"testing"
+
+	"github.com/go-historical-data/internal/bench"
+)
+
+// BenchmarkBuiltins measures applying the built-in transforms to every bar
+func BenchmarkBuiltins(b *testing.B) {
+	fixture, err := bench.NewFixture(bench.DefaultOptions())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	fixture.Transform(b)
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Audit outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditRecorder persists audit entries
+type AuditRecorder interface {
+	Record(ctx context.Context, entry *model.AuditLog) error
+}
+
+// Audit creates a middleware that records every mutating request (POST, PUT,
+// PATCH, DELETE) with the caller identity, route, affected symbols/IDs and outcome.
+// Controllers report affected resources via SetAuditSymbols and SetAuditResourceIDs.
+func Audit(recorder AuditRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+
+		start := time.Now()
+		err := c.Next()
+
+		// The error handler runs after middleware; derive the status it will send
+		status := responseStatus(c, err)
+
+		entry := &model.AuditLog{
+			RequestID:  GetRequestID(c),
+			Tenant:     GetTenant(c),
+			APIKey:     GetAPIKeyName(c),
+			Role:       GetRole(c),
+			ClientIP:   GetClientIP(c),
+			Method:     c.Method(),
+			Route:      c.Route().Path,
+			Path:       c.Path(),
+			StatusCode: status,
+			Outcome:    AuditOutcomeSuccess,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if symbols, ok := c.Locals("audit_symbols").([]string); ok {
+			entry.Symbols = strings.Join(symbols, ",")
+		}
+		if ids, ok := c.Locals("audit_resource_ids").([]string); ok {
+			entry.ResourceIDs = strings.Join(ids, ",")
+		}
+		if status >= fiber.StatusBadRequest {
+			entry.Outcome = AuditOutcomeFailure
+			if err != nil {
+				entry.Error = err.Error()
+			}
+		}
+
+		log := GetLogger(c)
+		log.Info().
+			Bool("audit", true).
+			Str("tenant", entry.Tenant).
+			Str("api_key", entry.APIKey).
+			Str("method", entry.Method).
+			Str("route", entry.Route).
+			Str("symbols", entry.Symbols).
+			Str("resource_ids", entry.ResourceIDs).
+			Int("status", entry.StatusCode).
+			Str("outcome", entry.Outcome).
+			Msg("Audit")
+
+		// Persist on a detached context so client disconnects don't drop audit entries
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if recordErr := recorder.Record(ctx, entry); recordErr != nil {
+			log.Error().Err(recordErr).Msg("Failed to persist audit entry")
+		}
+
+		return err
+	}
+}
+
+// SetAuditSymbols reports the symbols affected by a mutating request
+func SetAuditSymbols(c *fiber.Ctx, symbols []string) {
+	c.Locals("audit_symbols", symbols)
+}
+
+// SetAuditResourceIDs reports the record IDs affected by a mutating request
+func SetAuditResourceIDs(c *fiber.Ctx, ids ...uint64) {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatUint(id, 10)
+	}
+	c.Locals("audit_resource_ids", values)
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/go-historical-data/pkg/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Roles assigned to API keys
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// DefaultTenant is the tenant assigned to anonymous requests when auth is disabled
+const DefaultTenant = "default"
+
+// anonymousKeyName identifies requests made without an API key
+const anonymousKeyName = "anonymous"
+
+// APIKeyAuth creates a middleware that identifies the caller by API key and stores
+// the tenant, key name and role in the request locals, and the entitlement window
+// of delayed keys in the request context. When auth is disabled every request is
+// treated as the anonymous caller of the default tenant.
+func APIKeyAuth(cfg config.AuthConfig) fiber.Handler {
+	header := cfg.Header
+	if header == "" {
+		header = "X-API-Key"
+	}
+	anonymousRole := cfg.AnonymousRole
+	if anonymousRole == "" {
+		anonymousRole = RoleUser
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !cfg.Enabled {
+			setIdentity(c, DefaultTenant, anonymousKeyName, anonymousRole)
+			return c.Next()
+		}
+
+		key := c.Get(header)
+		if key == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "Missing API key")
+		}
+
+		for _, k := range cfg.APIKeys {
+			if subtle.ConstantTimeCompare([]byte(k.Key), []byte(key)) == 1 {
+				tenant := k.Tenant
+				if tenant == "" {
+					tenant = DefaultTenant
+				}
+				role := k.Role
+				if role == "" {
+					role = RoleUser
+				}
+				setIdentity(c, tenant, k.Name, role)
+				setEntitlement(c, k.DelayDays)
+				return c.Next()
+			}
+		}
+
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid API key")
+	}
+}
+
+// RequireRole creates a middleware that rejects callers without the given role
+func RequireRole(role string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if GetRole(c) != role {
+			return fiber.NewError(fiber.StatusForbidden, "Insufficient permissions")
+		}
+		return c.Next()
+	}
+}
+
+// setIdentity stores the caller identity in the request locals
+func setIdentity(c *fiber.Ctx, tenant, keyName, role string) {
+	c.Locals("tenant", tenant)
+	c.Locals("api_key", keyName)
+	c.Locals("role", role)
+}
+
+// GetTenant retrieves the caller tenant from context
+func GetTenant(c *fiber.Ctx) string {
+	if tenant, ok := c.Locals("tenant").(string); ok {
+		return tenant
+	}
+	return DefaultTenant
+}
+
+// GetAPIKeyName retrieves the caller API key name from context
+func GetAPIKeyName(c *fiber.Ctx) string {
+	if name, ok := c.Locals("api_key").(string); ok {
+		return name
+	}
+	return anonymousKeyName
+}
+
+// GetRole retrieves the caller role from context
+func GetRole(c *fiber.Ctx) string {
+	if role, ok := c.Locals("role").(string); ok {
+		return role
+	}
+	return ""
+}
+
+// IsAdmin reports whether the caller has the admin role
+func IsAdmin(c *fiber.Ctx) bool {
+	return GetRole(c) == RoleAdmin
+}
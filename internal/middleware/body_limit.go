@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit creates a middleware enforcing request body size limits per route.
+// routeLimits is keyed by "METHOD /path" and overrides defaultLimit for the
+// requests the router sends to that route (ignoring case and a trailing slash),
+// so only designated routes (e.g. uploads) accept large bodies. Requests are
+// rejected from the Content-Length header before the body is read; chunked
+// bodies, whose length is unknown, are read up to the limit and no further.
+func BodyLimit(defaultLimit int64, routeLimits map[string]int64) fiber.Handler {
+	routeLimits = routeMap(routeLimits)
+
+	return func(c *fiber.Ctx) error {
+		limit := defaultLimit
+		if routeLimit, ok := routeLimits[routeKey(c)]; ok {
+			limit = routeLimit
+		}
+		if limit <= 0 {
+			return c.Next()
+		}
+
+		size := int64(c.Request().Header.ContentLength())
+		if size < 0 {
+			// Chunked transfer: the length is only known once the body is read
+			spilled, chunkedSize, err := readChunkedBody(c, limit)
+			if err != nil {
+				c.Context().SetConnectionClose()
+				return fiber.NewError(fiber.StatusBadRequest, "Failed to read request body")
+			}
+			if spilled != nil {
+				defer spilled.Close()
+			}
+			if chunkedSize > limit {
+				// The rest of the body is left unread, so the connection cannot be reused
+				c.Context().SetConnectionClose()
+			}
+			size = chunkedSize
+		}
+
+		if size > limit {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, "Request body too large")
+		}
+
+		return c.Next()
+	}
+}
+
+// readChunkedBody reads a streamed body of unknown length, stopping one byte
+// past limit, and returns its size. A body within the limit replaces the stream
+// for the handlers, kept in memory up to chunkedMemoryLimit and spilled to a
+// temporary file beyond, which the caller closes once the request is handled;
+// the rest of a larger one is never read.
+func readChunkedBody(c *fiber.Ctx, limit int64) (*tempBody, int64, error) {
+	stream := c.Context().RequestBodyStream()
+	if stream == nil {
+		return nil, int64(len(c.Body())), nil
+	}
+	stream = io.LimitReader(stream, limit+1)
+
+	body, err := io.ReadAll(io.LimitReader(stream, chunkedMemoryLimit+1))
+	if err != nil {
+		return nil, 0, err
+	}
+	if int64(len(body)) <= chunkedMemoryLimit {
+		if int64(len(body)) <= limit {
+			c.Request().SetBodyRaw(body)
+			c.Request().Header.SetContentLength(len(body))
+		}
+		return nil, int64(len(body)), nil
+	}
+
+	file, err := os.CreateTemp("", "chunked-body-*")
+	if err != nil {
+		return nil, 0, err
+	}
+	spilled := &tempBody{File: file}
+	size, err := io.Copy(file, io.MultiReader(bytes.NewReader(body), stream))
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil || size > limit {
+		spilled.Close()
+		return nil, size, err
+	}
+	c.Request().SetBodyStream(spilled, int(size))
+	return spilled, size, nil
+}
+
+// chunkedMemoryLimit is the size of chunked bodies kept in memory
+const chunkedMemoryLimit = 4 << 20
+
+// tempBody is a request body spilled to a temporary file, removed on Close
+type tempBody struct {
+	*os.File
+}
+
+// Close closes and removes the file; closing it twice is harmless
+func (b *tempBody) Close() error {
+	err := b.File.Close()
+	_ = os.Remove(b.Name())
+	return err
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-historical-data/internal/entitlement"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cache"
+)
+
+// cacheGeneration is part of every cache key; bumping it invalidates all cached responses
+var cacheGeneration atomic.Uint64
+
+// ResponseCache creates an in-process response cache middleware for GET routes.
+// Responses are keyed by path and normalized query parameters, and only successful
+// responses are stored. Cache-Control headers are emitted on both hits and misses,
+// and lookups are counted per route. The body depends on the negotiated format
+// and the caller's entitlement window, so shared caches must not store them:
+// responses are private to the client and vary on Accept.
+func ResponseCache(route string, ttl time.Duration, maxBytes uint) fiber.Handler {
+	maxAge := "private, max-age=" + strconv.Itoa(int(ttl.Seconds()))
+
+	store := cache.New(cache.Config{
+		Expiration:   ttl,
+		CacheHeader:  "X-Cache",
+		CacheControl: true,
+		MaxBytes:     maxBytes,
+		Methods:      []string{fiber.MethodGet},
+		KeyGenerator: func(c *fiber.Ctx) string {
+			// Accept participates in the key because read endpoints negotiate JSON vs
+			// CSV, the entitlement window because delayed keys read fewer bars
+			through, _ := entitlement.Through(c.UserContext())
+			return strconv.FormatUint(cacheGeneration.Load(), 10) + ":" + c.Path() + "?" + normalizeQuery(c) +
+				"|" + c.Get(fiber.HeaderAccept) + "|" + through.Format("2006-01-02")
+		},
+		// Never cache error responses
+		Next: func(c *fiber.Ctx) bool {
+			return c.Response().StatusCode() != fiber.StatusOK
+		},
+	})
+
+	return func(c *fiber.Ctx) error {
+		// Clients explicitly opting out of caching always reach the handler
+		if strings.Contains(c.Get(fiber.HeaderCacheControl), "no-store") {
+			c.Set(fiber.HeaderCacheControl, "no-store")
+			return c.Next()
+		}
+
+		if err := store(c); err != nil {
+			return err
+		}
+		if result := string(c.Response().Header.Peek("X-Cache")); result == "hit" || result == "miss" {
+			RecordResponseCache(route, result)
+			// A replayed body carries the IDs of the request that cached it
+			if result == "hit" {
+				response.Restamp(c)
+			}
+		}
+
+		if c.Response().StatusCode() == fiber.StatusOK {
+			// Hits carry the remaining age, set public by the cache middleware
+			cacheControl := string(c.Response().Header.Peek(fiber.HeaderCacheControl))
+			if remaining, ok := strings.CutPrefix(cacheControl, "public"); ok {
+				c.Set(fiber.HeaderCacheControl, "private"+remaining)
+			} else if cacheControl == "" {
+				c.Set(fiber.HeaderCacheControl, maxAge)
+			}
+			c.Vary(fiber.HeaderAccept)
+		} else {
+			c.Set(fiber.HeaderCacheControl, "no-store")
+		}
+		return nil
+	}
+}
+
+// InvalidateResponseCache discards every cached response by rotating the key generation
+func InvalidateResponseCache() {
+	cacheGeneration.Add(1)
+}
+
+// timeParams are the query parameters bound to time fields, whose values
+// binder.ParseTime may read relative to now
+var timeParams = map[string]bool{
+	"date": true, "start": true, "end": true, "from": true, "to": true,
+	"start_date": true, "end_date": true, "start_time": true, "end_time": true,
+}
+
+// normalizeQuery returns the query string with lowercase keys sorted alphabetically,
+// values sorted within each key, and empty values dropped. Time parameters are
+// resolved to the absolute time they stand for, so ?start_date=-1w keys a
+// different window every day.
+func normalizeQuery(c *fiber.Ctx) string {
+	now := time.Now()
+	values := make(url.Values)
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		k := strings.ToLower(strings.TrimSpace(string(key)))
+		v := strings.TrimSpace(string(value))
+		if k == "" || v == "" {
+			return
+		}
+		if timeParams[k] {
+			if t, err := binder.ParseTime(v, now); err == nil {
+				v = t.Format(time.RFC3339)
+			}
+		}
+		values[k] = append(values[k], v)
+	})
+
+	for k := range values {
+		sort.Strings(values[k])
+	}
+
+	// url.Values.Encode sorts by key
+	return values.Encode()
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParsePrefixes parses a list of CIDRs; a bare address stands for itself alone
+func ParsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", cidr, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// RealIP creates a middleware resolving the client address of each request
+// once, through the trusted proxies, for the middleware after it (see
+// GetClientIP). It must run before the rate limiter, logger and tracing.
+func RealIP(trusted []netip.Prefix) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("client_ip", ClientIP(c, trusted))
+		return c.Next()
+	}
+}
+
+// GetClientIP retrieves the client address resolved by RealIP, or the peer
+// address when it did not run
+func GetClientIP(c *fiber.Ctx) string {
+	if addr, ok := c.Locals("client_ip").(netip.Addr); ok && addr.IsValid() {
+		return addr.String()
+	}
+	return c.IP()
+}
+
+// clientAddr retrieves the client address resolved by RealIP, or the peer
+// address when it did not run
+func clientAddr(c *fiber.Ctx) netip.Addr {
+	if addr, ok := c.Locals("client_ip").(netip.Addr); ok {
+		return addr
+	}
+	peer, _ := netip.AddrFromSlice(c.Context().RemoteIP())
+	return peer.Unmap()
+}
+
+// ClientIP returns the address of the client of a request. Behind trusted
+// proxies it is the rightmost X-Forwarded-For entry that is not itself a
+// trusted proxy: entries left of it were written by the client and cannot be
+// believed. Without trusted proxies, or from any other peer, it is the peer.
+func ClientIP(c *fiber.Ctx, trusted []netip.Prefix) netip.Addr {
+	peer, _ := netip.AddrFromSlice(c.Context().RemoteIP())
+	peer = peer.Unmap()
+	if !contains(trusted, peer) {
+		return peer
+	}
+
+	forwarded := strings.Split(c.Get(fiber.HeaderXForwardedFor), ",")
+	client := peer
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap()
+		if !contains(trusted, client) {
+			break
+		}
+	}
+	return client
+}
+
+// contains reports whether addr is within any of the prefixes
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
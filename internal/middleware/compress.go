@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+	"github.com/valyala/fasthttp"
+)
+
+// encoder is a pooled compressor of one content encoding
+type encoder struct {
+	pool sync.Pool
+}
+
+// resettableWriter is the compressor API shared by every encoding
+type resettableWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// newEncoders returns the encoders of every supported encoding at level
+// (fastest, default or best)
+func newEncoders(level string) map[string]*encoder {
+	gzipLevel := map[string]int{"fastest": gzip.BestSpeed, "default": gzip.DefaultCompression, "best": gzip.BestCompression}[level]
+	brotliLevel := map[string]int{"fastest": 1, "default": 4, "best": brotli.BestCompression}[level]
+	zstdLevel := map[string]zstd.EncoderLevel{"fastest": zstd.SpeedFastest, "default": zstd.SpeedDefault, "best": zstd.SpeedBestCompression}[level]
+
+	return map[string]*encoder{
+		"zstd": {pool: sync.Pool{New: func() any {
+			w, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstdLevel), zstd.WithEncoderConcurrency(1))
+			return w
+		}}},
+		"br": {pool: sync.Pool{New: func() any {
+			return brotli.NewWriterLevel(nil, brotliLevel)
+		}}},
+		"gzip": {pool: sync.Pool{New: func() any {
+			w, _ := gzip.NewWriterLevel(nil, gzipLevel)
+			return w
+		}}},
+		"deflate": {pool: sync.Pool{New: func() any {
+			w, _ := zlib.NewWriterLevel(nil, gzipLevel)
+			return w
+		}}},
+	}
+}
+
+// compress writes the compressed body read from r to w
+func (e *encoder) compress(w io.Writer, r io.Reader, flush bool) error {
+	zw := e.pool.Get().(resettableWriter)
+	defer e.pool.Put(zw)
+	zw.Reset(w)
+
+	var err error
+	if flush {
+		// Streamed bodies are flushed as they are produced, so clients see
+		// rows as soon as they are queried
+		_, err = io.Copy(&flushingWriter{w: zw}, r)
+	} else {
+		_, err = io.Copy(zw, r)
+	}
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// flushingWriter flushes the compressor after every write
+type flushingWriter struct {
+	w resettableWriter
+}
+
+// Write implements io.Writer
+func (f *flushingWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, f.w.Flush()
+}
+
+// Compress creates a middleware compressing response bodies with the encoding
+// the client prefers among cfg.Encodings: zstd, brotli, gzip or deflate. Ties
+// between the q-values of Accept-Encoding go to the earliest configured
+// encoding. Bodies that are small, already encoded or of a content type in
+// cfg.SkipContentTypes (e.g. gzipped CSV and Parquet exports) are sent as is.
+func Compress(cfg config.CompressionConfig) fiber.Handler {
+	encoders := newEncoders(cfg.Level)
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		resp := c.Response()
+		if c.Method() == fiber.MethodHead || !compressible(resp, cfg) {
+			return nil
+		}
+		resp.Header.Add(fiber.HeaderVary, fiber.HeaderAcceptEncoding)
+
+		encoding := negotiateEncoding(c.Get(fiber.HeaderAcceptEncoding), cfg.Encodings)
+		if encoding == "" {
+			return nil
+		}
+		enc := encoders[encoding]
+
+		if resp.IsBodyStream() {
+			body := resp.BodyStream()
+			resp.SetBodyStream(fasthttp.NewStreamReader(func(w *bufio.Writer) {
+				_ = enc.compress(&bufioFlusher{w: w}, body, true)
+				if closer, ok := body.(io.Closer); ok {
+					closer.Close()
+				}
+			}), -1)
+		} else {
+			body := resp.Body()
+			if len(body) < cfg.MinBytes {
+				return nil
+			}
+			var buf bytes.Buffer
+			if err := enc.compress(&buf, bytes.NewReader(body), false); err != nil {
+				return err
+			}
+			resp.SetBodyRaw(buf.Bytes())
+		}
+		resp.Header.Set(fiber.HeaderContentEncoding, encoding)
+		return nil
+	}
+}
+
+// bufioFlusher flushes the buffered writer of a body stream after every write,
+// passing the flushes of the compressor through to the client
+type bufioFlusher struct {
+	w *bufio.Writer
+}
+
+// Write implements io.Writer
+func (b *bufioFlusher) Write(p []byte) (int, error) {
+	n, err := b.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, b.w.Flush()
+}
+
+// compressible reports whether a response has a body worth compressing
+func compressible(resp *fasthttp.Response, cfg config.CompressionConfig) bool {
+	status := resp.StatusCode()
+	if status < fiber.StatusOK || status == fiber.StatusNoContent || status == fiber.StatusNotModified ||
+		status == fiber.StatusPartialContent {
+		return false
+	}
+	if len(resp.Header.ContentEncoding()) > 0 {
+		return false
+	}
+	if !resp.IsBodyStream() && len(resp.Body()) == 0 {
+		return false
+	}
+
+	mediaType, _, _ := strings.Cut(string(resp.Header.ContentType()), ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, skip := range cfg.SkipContentTypes {
+		if mediaType == skip || (strings.HasSuffix(skip, "/") && strings.HasPrefix(mediaType, skip)) {
+			return false
+		}
+	}
+	return true
+}
+
+// negotiateEncoding picks the encoding of the highest q-value in an
+// Accept-Encoding header among the supported ones, in their order on ties.
+// "" means the body is sent as is.
+func negotiateEncoding(header string, supported []string) string {
+	if header == "" {
+		return ""
+	}
+
+	accepted := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if name == "*" {
+			wildcard = q
+		} else {
+			accepted[name] = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range supported {
+		q, ok := accepted[encoding]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
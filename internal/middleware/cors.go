@@ -15,6 +15,6 @@ func CORS(cfg config.CORSConfig) fiber.Handler {
 		AllowMethods:     strings.Join(cfg.AllowedMethods, ","),
 		AllowHeaders:     strings.Join(cfg.AllowedHeaders, ","),
 		AllowCredentials: true,
-		ExposeHeaders:    "X-Request-ID",
+		ExposeHeaders:    "X-Request-ID,X-Cache,X-Data-Version,ETag,Deprecation,Sunset,Link",
 	})
 }
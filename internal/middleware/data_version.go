@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-historical-data/internal/entitlement"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderDataVersion carries the data version of each symbol of a response
+const HeaderDataVersion = "X-Data-Version"
+
+// DataVersionLookup returns the data version of each symbol
+type DataVersionLookup func(ctx context.Context, symbols []string) (map[string]uint64, error)
+
+// DataVersion creates a middleware tagging the responses of a symbol-scoped
+// GET route with the data version of its symbols (X-Data-Version: AAPL=3,MSFT=1)
+// and a weak ETag derived from the versions, path, query, Accept header and
+// entitlement window. A request whose If-None-Match matches is answered 304
+// without reaching the handler. The versions are read before the handler
+// runs, so a write landing meanwhile bumps them past the ETag sent and the
+// next request refetches. Requests naming no symbol pass through untagged.
+func DataVersion(lookup DataVersionLookup) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		symbols := requestSymbols(c)
+		if len(symbols) == 0 {
+			return c.Next()
+		}
+		versions, err := lookup(c.UserContext(), symbols)
+		if err != nil {
+			// Serve the response untagged rather than fail the request
+			return c.Next()
+		}
+
+		var header strings.Builder
+		for i, symbol := range symbols {
+			if i > 0 {
+				header.WriteString(",")
+			}
+			header.WriteString(symbol)
+			header.WriteString("=")
+			header.WriteString(strconv.FormatUint(versions[symbol], 10))
+		}
+
+		through, _ := entitlement.Through(c.UserContext())
+		digest := sha256.Sum256([]byte(header.String() + "|" + c.Path() + "?" + normalizeQuery(c) +
+			"|" + c.Get(fiber.HeaderAccept) + "|" + through.Format("2006-01-02")))
+		etag := `W/"` + hex.EncodeToString(digest[:16]) + `"`
+
+		c.Set(HeaderDataVersion, header.String())
+		c.Set(fiber.HeaderETag, etag)
+		if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+
+		err = c.Next()
+		if err != nil || c.Response().StatusCode() != fiber.StatusOK {
+			c.Response().Header.Del(fiber.HeaderETag)
+		}
+		return err
+	}
+}
+
+// requestSymbols collects the upper-cased symbols named by the :symbol route
+// parameter and the symbol, symbols and benchmark query parameters, sorted
+func requestSymbols(c *fiber.Ctx) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(values ...string) {
+		for _, value := range values {
+			symbol := strings.ToUpper(strings.TrimSpace(value))
+			if symbol != "" && !seen[symbol] {
+				seen[symbol] = true
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	add(c.Params("symbol"), c.Query("symbol"), c.Query("benchmark"))
+	add(strings.Split(c.Query("symbols"), ",")...)
+	sort.Strings(symbols)
+	return symbols
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing
+// weakly as RFC 9110 requires for If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deprecation creates a middleware that marks responses of a deprecated API version
+// with Deprecation (RFC 9745), Sunset (RFC 8594) and successor-version Link headers.
+// Zero dates are omitted; successorPrefix replaces oldPrefix in the Link target,
+// which is only sent when the app registers the successor of the matched route.
+func Deprecation(deprecatedAt, sunset time.Time, oldPrefix, successorPrefix string) fiber.Handler {
+	deprecation := "true"
+	if !deprecatedAt.IsZero() {
+		deprecation = fmt.Sprintf("@%d", deprecatedAt.Unix())
+	}
+
+	var sunsetHeader string
+	if !sunset.IsZero() {
+		sunsetHeader = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	// Routes of the successor version, read once every route is registered
+	var (
+		successorsOnce sync.Once
+		successors     map[string]bool
+	)
+
+	return func(c *fiber.Ctx) error {
+		c.Set("Deprecation", deprecation)
+		if sunsetHeader != "" {
+			c.Set("Sunset", sunsetHeader)
+		}
+		err := c.Next()
+
+		if successorPrefix != "" {
+			successorsOnce.Do(func() {
+				successors = make(map[string]bool)
+				for _, route := range c.App().GetRoutes(true) {
+					if strings.HasPrefix(route.Path, successorPrefix+"/") {
+						successors[route.Method+" "+route.Path] = true
+					}
+				}
+			})
+			// Once the request is routed, c.Route() is the route that handled it
+			route := strings.Replace(c.Route().Path, oldPrefix, successorPrefix, 1)
+			if successors[c.Method()+" "+route] {
+				successor := strings.Replace(c.Path(), oldPrefix, successorPrefix, 1)
+				c.Append(fiber.HeaderLink, fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/go-historical-data/internal/entitlement"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderEntitledThrough reports the latest bar date a delayed API key may read
+const HeaderEntitledThrough = "X-Entitled-Through"
+
+// setEntitlement restricts a request to bars dated delayDays before today
+// (UTC) and older (see entitlement.Through), e.g. 1 for T-1 data, and reports the window in a header.
+// A delay of 0 leaves the request unrestricted.
+func setEntitlement(c *fiber.Ctx, delayDays int) {
+	if delayDays <= 0 {
+		return
+	}
+	now := time.Now().UTC()
+	through := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -delayDays)
+
+	c.SetUserContext(entitlement.With(c.UserContext(), through))
+	c.Set(HeaderEntitledThrough, through.Format("2006-01-02"))
+}
@@ -2,57 +2,137 @@ package middleware
 
 import (
 	"errors"
+	"strings"
+	"unicode"
 
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/database"
+	"github.com/go-historical-data/pkg/i18n"
 	"github.com/go-historical-data/pkg/response"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
-// ErrorHandler is a global error handler middleware
-func ErrorHandler() fiber.ErrorHandler {
+// ErrorHandler is a global error handler middleware. App errors are answered
+// with their status, code, message and details; fiber errors and typed
+// database errors are translated, and any other error is a 500. Errors are
+// sent in the error envelope, or as RFC 7807 problem documents when the
+// format is problem or the request accepts application/problem+json.
+func ErrorHandler(cfg config.ErrorsConfig) fiber.ErrorHandler {
 	return func(c *fiber.Ctx, err error) error {
 		// Get logger from context
 		log := GetLogger(c)
 
-		// Default to 500 Internal Server Error
-		code := fiber.StatusInternalServerError
-		message := "Internal Server Error"
+		appErr := toAppError(err, i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage)))
 
-		// Check if it's a Fiber error
-		var fiberErr *fiber.Error
-		if errors.As(err, &fiberErr) {
-			code = fiberErr.Code
-			message = fiberErr.Message
+		// Log error, client errors as warnings
+		event := log.Warn()
+		if appErr.Status >= fiber.StatusInternalServerError {
+			event = log.Error()
 		}
-
-		// Log error
-		log.Error().
+		if appErr.Err != nil {
+			event = event.AnErr("cause", appErr.Err)
+		}
+		event.
 			Err(err).
-			Int("status", code).
+			Int("status", appErr.Status).
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Msg("Request error")
 
-		// Send error response based on status code
-		switch code {
-		case fiber.StatusBadRequest:
-			return response.BadRequest(c, message, nil)
-		case fiber.StatusUnauthorized:
-			return response.Unauthorized(c, message)
-		case fiber.StatusForbidden:
-			return response.Forbidden(c, message)
-		case fiber.StatusNotFound:
-			return response.NotFound(c, message)
-		case fiber.StatusTooManyRequests:
-			return response.TooManyRequests(c, message)
-		default:
-			return response.InternalServerError(c, message)
+		if cfg.Format == "problem" || response.AcceptsProblem(c) {
+			problem := response.NewProblem(cfg.ProblemTypeBase, appErr.Status, appErr.Code, appErr.Message, appErr.Details)
+			problem.Instance = c.OriginalURL()
+			problem.RequestID = GetRequestID(c)
+			problem.TraceID, _ = c.Locals("trace_id").(string)
+			return response.ProblemError(c, problem)
 		}
+		return response.Error(c, appErr.Status, appErr.Code, appErr.Message, appErr.Details)
+	}
+}
+
+// toAppError translates an error returned by a handler into the error
+// answered for it, with translated messages in lang
+func toAppError(err error, lang string) *apperror.Error {
+	if appErr, ok := apperror.FromLocalized(err, lang); ok {
+		return appErr
 	}
+
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &fiberErr):
+		return fromFiberError(fiberErr)
+	case errors.Is(err, database.ErrDuplicate):
+		return apperror.Conflict("Resource already exists", nil)
+	case errors.Is(err, database.ErrConstraint):
+		return apperror.Conflict("Request conflicts with stored data", nil)
+	case errors.Is(err, database.ErrUnavailable):
+		return apperror.Unavailable("Database is temporarily unavailable, retry later")
+	}
+	return apperror.Internal("Internal Server Error")
+}
+
+// fromFiberError keeps the status and message of fiber errors. Statuses with
+// an error code of their own are answered with it, other client errors with a
+// code named after the status (e.g. METHOD_NOT_ALLOWED), and server errors
+// as a 500.
+func fromFiberError(err *fiber.Error) *apperror.Error {
+	switch err.Code {
+	case fiber.StatusBadRequest:
+		return apperror.BadRequest(err.Message, nil)
+	case fiber.StatusUnauthorized:
+		return apperror.Unauthorized(err.Message)
+	case fiber.StatusForbidden:
+		return apperror.Forbidden(err.Message)
+	case fiber.StatusNotFound:
+		return apperror.NotFound(err.Message)
+	case fiber.StatusConflict:
+		return apperror.Conflict(err.Message, nil)
+	case fiber.StatusUnprocessableEntity:
+		return apperror.Validation(err.Message, nil)
+	case fiber.StatusRequestEntityTooLarge:
+		return apperror.PayloadTooLarge(err.Message)
+	case fiber.StatusUnsupportedMediaType:
+		return apperror.UnsupportedMediaType(err.Message)
+	case fiber.StatusTooManyRequests:
+		return apperror.TooManyRequests(err.Message)
+	case fiber.StatusServiceUnavailable:
+		return apperror.Unavailable(err.Message)
+	case fiber.StatusRequestTimeout, fiber.StatusGatewayTimeout:
+		return apperror.Timeout(err.Message)
+	}
+	if err.Code < fiber.StatusBadRequest || err.Code >= fiber.StatusInternalServerError {
+		return apperror.Internal(err.Message)
+	}
+	return apperror.New(err.Code, statusErrCode(err.Code), err.Message)
+}
+
+// statusErrCode names the error code of a client error status after its
+// reason phrase, e.g. METHOD_NOT_ALLOWED for 405
+func statusErrCode(status int) string {
+	reason := utils.StatusMessage(status)
+	if reason == "" {
+		return response.ErrCodeBadRequest
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, reason)
+}
+
+// errorStatus returns the status the error handler answers err with, for
+// middleware running before it
+func errorStatus(err error) int {
+	return toAppError(err, i18n.Default).Status
 }
 
-// Recover middleware recovers from panics
+// Recover middleware recovers from panics, answering them through the error
+// handler as a 500
 func Recover() fiber.Handler {
-	return func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
 				log := GetLogger(c)
@@ -62,11 +142,7 @@ func Recover() fiber.Handler {
 					Str("path", c.Path()).
 					Msg("Panic recovered")
 
-				// Send error response, ignore any error from the response itself
-				// as we're already in a panic recovery situation
-				if err := response.InternalServerError(c, "Internal Server Error"); err != nil {
-					log.Error().Err(err).Msg("Failed to send panic response")
-				}
+				err = apperror.Internal("Internal Server Error")
 			}
 		}()
 
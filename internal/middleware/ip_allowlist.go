@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/netip"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IPAllowlist creates a middleware rejecting with 403 the requests of clients
+// outside the allowed prefixes. The client address is the one resolved by
+// RealIP through the trusted proxies. routes, keyed by "METHOD /path" and matched
+// like the router, ignoring case and a trailing slash, restricts the check to
+// those routes; nil checks every request reaching the middleware.
+// An empty allowlist admits every client.
+func IPAllowlist(allowed []netip.Prefix, routes []string) fiber.Handler {
+	var restricted map[string]bool
+	if routes != nil {
+		restricted = routeSet(routes)
+	}
+
+	return func(c *fiber.Ctx) error {
+		if len(allowed) == 0 || (restricted != nil && !restricted[routeKey(c)]) {
+			return c.Next()
+		}
+
+		client := clientAddr(c)
+		if !contains(allowed, client) {
+			GetLogger(c).Warn().
+				Str("client_ip", client.String()).
+				Str("method", c.Method()).
+				Str("path", c.Path()).
+				Msg("Rejected request from an address outside the allowlist")
+			return fiber.NewError(fiber.StatusForbidden, "Access denied from this address")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/go-historical-data/pkg/i18n"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Language creates a middleware negotiating the language of user-facing
+// messages from Accept-Language and carrying it in the request context, so
+// the parsers of uploads report rejected rows in it
+func Language() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(i18n.WithLanguage(c.UserContext(), i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage))))
+		return c.Next()
+	}
+}
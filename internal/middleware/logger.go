@@ -33,7 +33,7 @@ func Logger(log *logger.Logger) fiber.Handler {
 		reqLogger.Info().
 			Str("method", c.Method()).
 			Str("path", c.Path()).
-			Str("ip", c.IP()).
+			Str("ip", GetClientIP(c)).
 			Str("user_agent", c.Get("User-Agent")).
 			Msg("Incoming request")
 
@@ -52,7 +52,7 @@ func Logger(log *logger.Logger) fiber.Handler {
 		logEvent.
 			Str("method", c.Method()).
 			Str("path", c.Path()).
-			Int("status", c.Response().StatusCode()).
+			Int("status", responseStatus(c, err)).
 			Dur("duration_ms", duration).
 			Int("size", len(c.Response().Body())).
 			Msg("Request completed")
@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/go-historical-data/pkg/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// redacted replaces the values of sensitive headers, query parameters and JSON fields
+const redacted = "[REDACTED]"
+
+// alwaysRedacted are the headers masked whatever the configuration
+var alwaysRedacted = []string{"authorization", "proxy-authorization", "cookie", "set-cookie"}
+
+// PayloadLogger creates a middleware that logs the headers and bodies of
+// requests and responses at debug level, for debugging environments. Bodies
+// are truncated to cfg.MaxBytes. The API key header, credentials, the
+// configured fields and the configured API keys themselves are masked.
+// Nothing is read or logged while the debug level is off.
+func PayloadLogger(cfg config.PayloadLoggingConfig, auth config.AuthConfig) fiber.Handler {
+	maxBytes := cfg.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 4096
+	}
+
+	fields := make(map[string]bool, len(cfg.RedactFields)+len(alwaysRedacted)+1)
+	for _, name := range alwaysRedacted {
+		fields[name] = true
+	}
+	if auth.Header != "" {
+		fields[strings.ToLower(auth.Header)] = true
+	} else {
+		fields["x-api-key"] = true
+	}
+	for _, name := range cfg.RedactFields {
+		fields[strings.ToLower(name)] = true
+	}
+
+	// Keys sent where no field name gives them away, e.g. in a CSV row or a URL path
+	secrets := make([][]byte, 0, len(auth.APIKeys))
+	for _, key := range auth.APIKeys {
+		if key.Key != "" {
+			secrets = append(secrets, []byte(key.Key))
+		}
+	}
+
+	p := &payloadRedactor{fields: fields, secrets: secrets, maxBytes: maxBytes}
+
+	return func(c *fiber.Ctx) error {
+		log := GetLogger(c)
+		if !log.Debug().Enabled() {
+			return c.Next()
+		}
+
+		log.Debug().
+			Str("method", c.Method()).
+			Str("path", p.text([]byte(c.Path()))).
+			Interface("query", p.query(c)).
+			Interface("headers", p.requestHeaders(c)).
+			Str("body", p.requestBody(c)).
+			Msg("Request payload")
+
+		err := c.Next()
+
+		// A returned error is answered by the error handler after this logs
+		log.Debug().
+			Err(err).
+			Str("method", c.Method()).
+			Str("path", p.text([]byte(c.Path()))).
+			Int("status", responseStatus(c, err)).
+			Interface("headers", p.responseHeaders(c)).
+			Str("body", p.body(c.Response().Body(), string(c.Response().Header.ContentType()))).
+			Msg("Response payload")
+
+		return err
+	}
+}
+
+// payloadRedactor masks and truncates the payloads logged by PayloadLogger
+type payloadRedactor struct {
+	fields   map[string]bool // lower-cased header, query parameter and JSON field names
+	secrets  [][]byte
+	maxBytes int
+}
+
+// requestBody returns the request body to log. Multipart uploads and bodies
+// larger than the cap are not read, so streamed uploads stay streamed.
+func (p *payloadRedactor) requestBody(c *fiber.Ctx) string {
+	contentType := c.Get(fiber.HeaderContentType)
+	length := c.Request().Header.ContentLength()
+	switch {
+	case length == 0:
+		return ""
+	case strings.HasPrefix(contentType, fiber.MIMEMultipartForm):
+		return "[multipart body omitted]"
+	case length < 0 || length > p.maxBytes:
+		return "[" + strconv.Itoa(length) + " bytes omitted]"
+	}
+	return p.body(c.Body(), contentType)
+}
+
+// body masks the sensitive fields of a JSON body and truncates it
+func (p *payloadRedactor) body(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) || strings.HasSuffix(strings.Split(contentType, ";")[0], "+json") {
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err == nil {
+			if masked, err := json.Marshal(p.mask(doc)); err == nil {
+				body = masked
+			}
+		}
+	}
+	return p.text(body)
+}
+
+// mask replaces the values of sensitive fields at any depth of a JSON document
+func (p *payloadRedactor) mask(doc interface{}) interface{} {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for name, value := range v {
+			if p.fields[strings.ToLower(name)] {
+				v[name] = redacted
+			} else {
+				v[name] = p.mask(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = p.mask(value)
+		}
+	}
+	return doc
+}
+
+// text masks the configured API keys and truncates to the size cap
+// before truncating, so no key is cut in half
+func (p *payloadRedactor) text(b []byte) string {
+	for _, secret := range p.secrets {
+		if bytes.Contains(b, secret) {
+			b = bytes.ReplaceAll(b, secret, []byte(redacted))
+		}
+	}
+	if len(b) > p.maxBytes {
+		return string(b[:p.maxBytes]) + "...[truncated]"
+	}
+	return string(b)
+}
+
+// query returns the query parameters with sensitive values masked
+func (p *payloadRedactor) query(c *fiber.Ctx) map[string]string {
+	query := make(map[string]string)
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		query[string(key)] = p.value(string(key), value)
+	})
+	return query
+}
+
+// requestHeaders returns the request headers with sensitive values masked
+func (p *payloadRedactor) requestHeaders(c *fiber.Ctx) map[string]string {
+	headers := make(map[string]string)
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		headers[string(key)] = p.value(string(key), value)
+	})
+	return headers
+}
+
+// responseHeaders returns the response headers with sensitive values masked
+func (p *payloadRedactor) responseHeaders(c *fiber.Ctx) map[string]string {
+	headers := make(map[string]string)
+	c.Response().Header.VisitAll(func(key, value []byte) {
+		headers[string(key)] = p.value(string(key), value)
+	})
+	return headers
+}
+
+// value masks the value of a sensitive header or query parameter
+func (p *payloadRedactor) value(name string, value []byte) string {
+	if p.fields[strings.ToLower(name)] {
+		return redacted
+	}
+	return p.text(value)
+}
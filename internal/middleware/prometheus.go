@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/go-historical-data/pkg/config"
 	"github.com/gofiber/fiber/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -79,7 +81,7 @@ var (
 			Name: "csv_uploads_total",
 			Help: "Total number of CSV uploads",
 		},
-		[]string{"status"}, // success, partial, error
+		[]string{"status"}, // success, partial, error, aborted
 	)
 
 	// Database metrics
@@ -99,10 +101,162 @@ var (
 		},
 		[]string{"operation"},
 	)
+
+	// Backfill metrics
+	backfillChunksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "backfill_chunks_total",
+			Help: "Total number of finished backfill chunks",
+		},
+		[]string{"provider", "status"}, // completed or failed
+	)
+
+	backfillRowsIngested = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "backfill_rows_ingested_total",
+			Help: "Total number of rows ingested by backfills",
+		},
+		[]string{"provider"},
+	)
+
+	backfillChunkDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "backfill_chunk_duration_seconds",
+			Help:    "Backfill chunk processing duration in seconds, including retries",
+			Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300}, // 100ms to 5min
+		},
+		[]string{"provider"},
+	)
+
+	dbRowsAffected = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_rows_affected_total",
+			Help: "Total number of rows affected by database writes, as reported by the server",
+		},
+		[]string{"operation", "table"},
+	)
+
+	dbBatchRows = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_batch_rows",
+			Help:    "Rows per batch write",
+			Buckets: []float64{1, 10, 50, 100, 500, 1000, 2500, 5000, 10000, 50000},
+		},
+		[]string{"operation", "table"},
+	)
+
+	dbUpsertRows = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_upsert_rows_total",
+			Help: "Total number of upserted rows by outcome",
+		},
+		[]string{"table", "outcome"}, // inserted, updated, or upserted where the database doesn't tell them apart
+	)
+
+	dbRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_retries_total",
+			Help: "Total number of database calls retried after a transient error",
+		},
+		[]string{"reason"}, // deadlock, lock_wait_timeout, bad_connection
+	)
+
+	dbCircuitBreakerState = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "db_circuit_breaker_state",
+			Help: "Database circuit breaker state (0 = closed, 1 = half-open, 2 = open)",
+		},
+	)
+
+	coalescedReadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coalesced_reads_total",
+			Help: "Total number of reads answered by an execution shared with identical concurrent reads",
+		},
+		[]string{"query"},
+	)
+
+	symbolLastBarTimestamp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "symbol_last_bar_timestamp_seconds",
+			Help: "Date of the latest stored bar per symbol as a Unix timestamp",
+		},
+		[]string{"symbol"},
+	)
+
+	// Response cache lookups
+	responseCacheRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "response_cache_requests_total",
+			Help: "Total number of response cache lookups by route and result (hit or miss)",
+		},
+		[]string{"route", "result"},
+	)
+
+	// Analytics result cache lookups
+	analyticsCacheRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "analytics_cache_requests_total",
+			Help: "Total number of analytics result cache lookups by endpoint and result (hit or miss)",
+		},
+		[]string{"endpoint", "result"},
+	)
+
+	// Signed pushes rejected by SignedIngestion
+	signatureFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ingest_signature_failures_total",
+			Help: "Total number of pushes rejected for a missing, expired, replayed or mismatched signature",
+		},
+		[]string{"source", "reason"},
+	)
+
+	// Statements slower than the slow query threshold
+	dbSlowQueriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_slow_queries_total",
+			Help: "Total number of database statements slower than the slow query threshold",
+		},
+		[]string{"operation"}, // select, insert, update, delete, raw
+	)
+
+	dbFullScansTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_full_scans_total",
+			Help: "Total number of full table scans found in the plans of sampled slow queries",
+		},
+		[]string{"table"},
+	)
+
+	// Configuration reloads
+	configReloadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "config_reloads_total",
+			Help: "Total number of configuration reloads by result (success or error)",
+		},
+		[]string{"result"},
+	)
+
+	configLastReloadTimestamp = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "config_last_reload_success_timestamp_seconds",
+			Help: "Unix time of the last successful configuration reload",
+		},
+	)
 )
 
-// PrometheusMiddleware creates a middleware that collects Prometheus metrics
-func PrometheusMiddleware() fiber.Handler {
+// Path labels of requests outside the labelled routes
+const (
+	pathLabelAll   = "*"     // per-path labels are disabled
+	pathLabelOther = "other" // no labelled route matched, e.g. 404 scans
+)
+
+// PrometheusMiddleware creates a middleware that collects Prometheus metrics.
+// Requests are labelled by route template, never by raw path, so unmatched
+// requests cannot grow the label set.
+func PrometheusMiddleware(cfg config.MetricsConfig) fiber.Handler {
+	labels := &pathLabeler{cfg: cfg}
+
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
@@ -110,26 +264,26 @@ func PrometheusMiddleware() fiber.Handler {
 		httpActiveConnections.Inc()
 		defer httpActiveConnections.Dec()
 
-		// Record request size
-		requestSize := len(c.Body())
-		path := c.Route().Path
-		if path == "" {
-			path = c.Path()
-		}
-		httpRequestSize.WithLabelValues(c.Method(), path).Observe(float64(requestSize))
+		// Record request size from the header so streamed bodies aren't buffered
+		requestSize := max(c.Request().Header.ContentLength(), 0)
 
 		// Continue to next handler
 		err := c.Next()
 
-		// Record metrics after request completion
+		// Record metrics after request completion, once the route is known
 		duration := time.Since(start).Seconds()
-		status := strconv.Itoa(c.Response().StatusCode())
+		statusCode := responseStatus(c, err)
+		status := strconv.Itoa(statusCode)
+		path := labels.label(c)
+
+		httpRequestSize.WithLabelValues(c.Method(), path).Observe(float64(requestSize))
 
 		// Record request duration
 		httpRequestDuration.WithLabelValues(c.Method(), path, status).Observe(duration)
 
 		// Increment request counter
 		httpRequestsTotal.WithLabelValues(c.Method(), path, status).Inc()
+		recordRequestStats(statusCode)
 
 		// Record response size
 		responseSize := len(c.Response().Body())
@@ -139,6 +293,51 @@ func PrometheusMiddleware() fiber.Handler {
 	}
 }
 
+// responseStatus returns the status of a response, the one the error handler
+// will answer with when the handler returned an error, e.g. 404 for no route
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	return errorStatus(err)
+}
+
+// pathLabeler maps requests to the path label of the HTTP metrics
+type pathLabeler struct {
+	cfg    config.MetricsConfig
+	once   sync.Once
+	routes map[string]bool // "METHOD /template" of the labelled routes
+}
+
+// label returns the route template of the handler that served the request
+// when it is labelled. A request matching only middleware has the template of
+// the middleware, hence the check against the registered routes.
+func (l *pathLabeler) label(c *fiber.Ctx) string {
+	if !l.cfg.PathLabels {
+		return pathLabelAll
+	}
+
+	// Routes are registered before the first request is served
+	l.once.Do(func() {
+		allowed := make(map[string]bool, len(l.cfg.Paths))
+		for _, path := range l.cfg.Paths {
+			allowed[path] = true
+		}
+		l.routes = make(map[string]bool)
+		for _, route := range c.App().GetRoutes(true) {
+			if len(allowed) == 0 || allowed[route.Path] {
+				l.routes[route.Method+" "+route.Path] = true
+			}
+		}
+	})
+
+	route := c.Route()
+	if l.routes[route.Method+" "+route.Path] {
+		return route.Path
+	}
+	return pathLabelOther
+}
+
 // RecordCSVMetrics records metrics for CSV upload operations
 func RecordCSVMetrics(successCount, errorCount int, duration time.Duration, uploadStatus string) {
 	csvRowsProcessed.WithLabelValues("success").Add(float64(successCount))
@@ -147,6 +346,13 @@ func RecordCSVMetrics(successCount, errorCount int, duration time.Duration, uplo
 	csvUploadsTotal.WithLabelValues(uploadStatus).Inc()
 }
 
+// RecordBackfillChunkMetrics records metrics for a finished backfill chunk
+func RecordBackfillChunkMetrics(provider, status string, rowsIngested int, duration time.Duration) {
+	backfillChunksTotal.WithLabelValues(provider, status).Inc()
+	backfillRowsIngested.WithLabelValues(provider).Add(float64(rowsIngested))
+	backfillChunkDuration.WithLabelValues(provider).Observe(duration.Seconds())
+}
+
 // RecordDBMetrics records metrics for database operations
 func RecordDBMetrics(operation string, duration time.Duration, err error) {
 	dbQueryDuration.WithLabelValues(operation).Observe(duration.Seconds())
@@ -154,3 +360,86 @@ func RecordDBMetrics(operation string, duration time.Duration, err error) {
 		dbErrorsTotal.WithLabelValues(operation).Inc()
 	}
 }
+
+// RecordDBRowsAffected records the rows a database write affected
+func RecordDBRowsAffected(operation, table string, rows int64) {
+	dbRowsAffected.WithLabelValues(operation, table).Add(float64(rows))
+}
+
+// RecordDBBatch records the rows of a batch write
+func RecordDBBatch(operation, table string, rows int) {
+	dbBatchRows.WithLabelValues(operation, table).Observe(float64(rows))
+}
+
+// RecordDBUpsert records upserted rows with their outcome
+func RecordDBUpsert(table, outcome string, rows int64) {
+	dbUpsertRows.WithLabelValues(table, outcome).Add(float64(rows))
+}
+
+// RecordSlowQuery records a statement slower than the slow query threshold
+func RecordSlowQuery(operation string) {
+	dbSlowQueriesTotal.WithLabelValues(operation).Inc()
+}
+
+// RecordFullScan records a table read in full by an explained slow query
+func RecordFullScan(table string) {
+	dbFullScansTotal.WithLabelValues(table).Inc()
+}
+
+// RecordCoalescedRead records a read answered by a shared execution
+func RecordCoalescedRead(query string) {
+	coalescedReadsTotal.WithLabelValues(query).Inc()
+}
+
+// RecordSymbolFreshness records the date of the latest stored bar of a symbol
+func RecordSymbolFreshness(symbol string, lastDate time.Time) {
+	symbolLastBarTimestamp.WithLabelValues(symbol).Set(float64(lastDate.Unix()))
+}
+
+// ClearSymbolFreshness drops the freshness series of a symbol left without bars
+func ClearSymbolFreshness(symbol string) {
+	symbolLastBarTimestamp.DeleteLabelValues(symbol)
+}
+
+// RecordResponseCache records a response cache hit or miss of a route
+func RecordResponseCache(route, result string) {
+	responseCacheRequests.WithLabelValues(route, result).Inc()
+	recordCacheStats(route, result)
+}
+
+// RecordAnalyticsCache records an analytics result cache hit or miss of an endpoint
+func RecordAnalyticsCache(endpoint, result string) {
+	analyticsCacheRequests.WithLabelValues(endpoint, result).Inc()
+}
+
+// RecordDBRetry records a database call retried after a transient error
+func RecordDBRetry(reason string) {
+	dbRetriesTotal.WithLabelValues(reason).Inc()
+}
+
+// RecordDBBreakerState records the database circuit breaker state
+func RecordDBBreakerState(state string) {
+	switch state {
+	case "open":
+		dbCircuitBreakerState.Set(2)
+	case "half-open":
+		dbCircuitBreakerState.Set(1)
+	default:
+		dbCircuitBreakerState.Set(0)
+	}
+}
+
+// RecordSignatureFailure records a push rejected for its signature
+func RecordSignatureFailure(source, reason string) {
+	signatureFailuresTotal.WithLabelValues(source, reason).Inc()
+}
+
+// RecordConfigReload records the outcome of a configuration reload
+func RecordConfigReload(err error) {
+	if err != nil {
+		configReloadsTotal.WithLabelValues("error").Inc()
+		return
+	}
+	configReloadsTotal.WithLabelValues("success").Inc()
+	configLastReloadTimestamp.SetToCurrentTime()
+}
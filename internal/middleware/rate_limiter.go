@@ -13,7 +13,7 @@ func RateLimiter(maxRequests int) fiber.Handler {
 		Max:        maxRequests,
 		Expiration: 1 * time.Minute,
 		KeyGenerator: func(c *fiber.Ctx) string {
-			return c.IP()
+			return GetClientIP(c)
 		},
 		LimitReached: func(c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusTooManyRequests, "Rate limit exceeded")
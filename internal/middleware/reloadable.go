@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Reloadable is a middleware whose handler can be replaced at runtime, for
+// settings applied on configuration reload
+type Reloadable struct {
+	handler atomic.Pointer[fiber.Handler]
+}
+
+// NewReloadable creates a reloadable middleware running handler
+func NewReloadable(handler fiber.Handler) *Reloadable {
+	r := &Reloadable{}
+	r.Swap(handler)
+	return r
+}
+
+// Swap replaces the handler; requests in flight finish with the previous one
+func (r *Reloadable) Swap(handler fiber.Handler) {
+	r.handler.Store(&handler)
+}
+
+// Handler returns the middleware running the current handler
+func (r *Reloadable) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return (*r.handler.Load())(c)
+	}
+}
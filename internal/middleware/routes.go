@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// routeKey returns the "METHOD /path" key of a request, normalised the way the
+// router matches it. Without StrictRouting and CaseSensitive, "/API/v1/Data/"
+// reaches the "/api/v1/data" handler, so per-route policies must match it too.
+func routeKey(c *fiber.Ctx) string {
+	return c.Method() + " " + normalizePath(c.Path())
+}
+
+// normalizePath lower-cases a path and trims its trailing slashes
+func normalizePath(path string) string {
+	path = strings.ToLower(path)
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	return path
+}
+
+// routeSet builds a set of "METHOD /path" keys normalised like routeKey
+func routeSet(routes []string) map[string]bool {
+	set := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		set[normalizeRoute(route)] = true
+	}
+	return set
+}
+
+// routeMap re-keys a map of "METHOD /path" keys normalised like routeKey
+func routeMap[V any](routes map[string]V) map[string]V {
+	normalised := make(map[string]V, len(routes))
+	for route, value := range routes {
+		normalised[normalizeRoute(route)] = value
+	}
+	return normalised
+}
+
+// normalizeRoute normalises the path of a "METHOD /path" key
+func normalizeRoute(route string) string {
+	method, path, ok := strings.Cut(route, " ")
+	if !ok {
+		return route
+	}
+	return strings.ToUpper(method) + " " + normalizePath(path)
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/go-historical-data/pkg/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/helmet"
+)
+
+// SecurityHeaders creates a middleware that sets security-related response headers
+// (HSTS on HTTPS requests, X-Content-Type-Options, X-Frame-Options, CSP, etc.)
+func SecurityHeaders(cfg config.SecurityConfig) fiber.Handler {
+	return helmet.New(helmet.Config{
+		XSSProtection:             "0",
+		ContentTypeNosniff:        "nosniff",
+		XFrameOptions:             "DENY",
+		HSTSMaxAge:                cfg.HSTSMaxAge,
+		HSTSPreloadEnabled:        cfg.HSTSPreload,
+		ContentSecurityPolicy:     cfg.ContentSecurityPolicy,
+		ReferrerPolicy:            "no-referrer",
+		CrossOriginOpenerPolicy:   "same-origin",
+		CrossOriginResourcePolicy: "same-origin",
+		OriginAgentCluster:        "?1",
+		XDNSPrefetchControl:       "off",
+		XDownloadOptions:          "noopen",
+		XPermittedCrossDomain:     "none",
+	})
+}
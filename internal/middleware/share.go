@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/url"
+
+	"github.com/go-historical-data/internal/share"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SharedLink creates a middleware admitting the holders of a share link in
+// place of an API key. The request acts as the issuing key, with its tenant
+// and entitlement window but the user role, so revoking the key revokes its
+// links. Tampered, expired and revoked links are rejected with 403.
+func SharedLink(cfg config.AuthConfig, signer *share.Signer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		query := url.Values{}
+		c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+			query.Add(string(key), string(value))
+		})
+
+		issuer, err := signer.Verify(query)
+		if err != nil {
+			return apperror.Forbidden("Share link is invalid or expired")
+		}
+		binder.Allow(c, share.LinkParams...)
+
+		if !cfg.Enabled {
+			setIdentity(c, DefaultTenant, issuer, RoleUser)
+			return c.Next()
+		}
+		for _, k := range cfg.APIKeys {
+			if k.Name == issuer {
+				tenant := k.Tenant
+				if tenant == "" {
+					tenant = DefaultTenant
+				}
+				setIdentity(c, tenant, k.Name, RoleUser)
+				setEntitlement(c, k.DelayDays)
+				return c.Next()
+			}
+		}
+		return apperror.Forbidden("Share link is invalid or expired")
+	}
+}
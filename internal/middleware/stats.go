@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// RequestStats counts the HTTP requests served since the process started
+type RequestStats struct {
+	Total        uint64
+	ClientErrors uint64 // 4xx responses
+	ServerErrors uint64 // 5xx responses
+}
+
+// CacheStats counts the response cache lookups of a route since the process started
+type CacheStats struct {
+	Route  string
+	Hits   uint64
+	Misses uint64
+}
+
+// requestCounters back RequestStats; Prometheus keeps the labelled series
+var requestCounters struct {
+	total, clientErrors, serverErrors atomic.Uint64
+}
+
+// cacheCounters holds the hit and miss counters of each cached route
+var cacheCounters sync.Map // route -> *[2]atomic.Uint64
+
+// recordRequestStats counts a served request by status class
+func recordRequestStats(status int) {
+	requestCounters.total.Add(1)
+	switch {
+	case status >= 500:
+		requestCounters.serverErrors.Add(1)
+	case status >= 400:
+		requestCounters.clientErrors.Add(1)
+	}
+}
+
+// recordCacheStats counts a cache hit or miss of a route
+func recordCacheStats(route, result string) {
+	counters, _ := cacheCounters.LoadOrStore(route, new([2]atomic.Uint64))
+	if result == "hit" {
+		counters.(*[2]atomic.Uint64)[0].Add(1)
+	} else {
+		counters.(*[2]atomic.Uint64)[1].Add(1)
+	}
+}
+
+// GetRequestStats returns the requests served since the process started
+func GetRequestStats() RequestStats {
+	return RequestStats{
+		Total:        requestCounters.total.Load(),
+		ClientErrors: requestCounters.clientErrors.Load(),
+		ServerErrors: requestCounters.serverErrors.Load(),
+	}
+}
+
+// GetCacheStats returns the response cache lookups of every cached route
+// since the process started, by route
+func GetCacheStats() []CacheStats {
+	var stats []CacheStats
+	cacheCounters.Range(func(key, value interface{}) bool {
+		counters := value.(*[2]atomic.Uint64)
+		stats = append(stats, CacheStats{
+			Route:  key.(string),
+			Hits:   counters[0].Load(),
+			Misses: counters[1].Load(),
+		})
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Route < stats[j].Route })
+	return stats
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/go-historical-data/pkg/binder"
+	"github.com/gofiber/fiber/v2"
+)
+
+// StrictQuery creates a middleware making the handlers reject query
+// parameters their request does not read and malformed times with 400,
+// so a typo such as ?symol=AAPL is not silently ignored
+func StrictQuery() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		binder.Strict(c)
+		return c.Next()
+	}
+}
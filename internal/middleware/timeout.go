@@ -1,15 +1,45 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"time"
 
+	"github.com/go-historical-data/pkg/apperror"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/timeout"
 )
 
-// Timeout creates a timeout middleware
-func Timeout(duration int) fiber.Handler {
-	return timeout.NewWithContext(func(c *fiber.Ctx) error {
-		return c.Next()
-	}, time.Duration(duration)*time.Second)
+// Timeout creates a middleware putting a deadline on the request context per
+// route. routeTimeouts is keyed by "METHOD /path" and overrides defaultTimeout
+// for the requests the router sends to that route (ignoring case and a trailing
+// slash), so uploads can run longer than reads. Handlers pass
+// c.UserContext() down to the repositories, so queries still running at the
+// deadline are cancelled; a request failing past its deadline answers 504.
+func Timeout(defaultTimeout time.Duration, routeTimeouts map[string]time.Duration) fiber.Handler {
+	routeTimeouts = routeMap(routeTimeouts)
+
+	return func(c *fiber.Ctx) error {
+		timeout := defaultTimeout
+		if routeTimeout, ok := routeTimeouts[routeKey(c)]; ok {
+			timeout = routeTimeout
+		}
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+		// A service call failing past the deadline comes back as an error
+		if err != nil || c.Response().StatusCode() >= fiber.StatusInternalServerError {
+			GetLogger(c).Warn().Err(err).Dur("timeout", timeout).Str("path", c.Path()).Msg("Request timed out")
+			return apperror.Timeout("Request timed out")
+		}
+		return nil
+	}
 }
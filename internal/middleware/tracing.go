@@ -32,7 +32,7 @@ func Tracing() fiber.Handler {
 				attribute.String("http.scheme", c.Protocol()),
 				attribute.String("http.host", c.Hostname()),
 				attribute.String("http.user_agent", c.Get("User-Agent")),
-				attribute.String("http.client_ip", c.IP()),
+				attribute.String("http.client_ip", GetClientIP(c)),
 			),
 		)
 		defer span.End()
@@ -49,7 +49,7 @@ func Tracing() fiber.Handler {
 		err := c.Next()
 
 		// Record response status
-		statusCode := c.Response().StatusCode()
+		statusCode := responseStatus(c, err)
 		span.SetAttributes(
 			attribute.Int("http.status_code", statusCode),
 			attribute.Int64("http.response_size", int64(len(c.Response().Body()))),
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// UsageRecorder accumulates per-tenant usage
+type UsageRecorder interface {
+	RecordUsage(tenant, apiKey string, rowsRead, rowsIngested, bytesIn, bytesOut int64)
+}
+
+// Metering creates a middleware that records rows read, rows ingested and bytes
+// transferred for the calling tenant and API key. Controllers report row counts
+// via SetRowsRead and SetRowsIngested.
+func Metering(recorder UsageRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		rowsRead, _ := c.Locals("usage_rows_read").(int64)
+		rowsIngested, _ := c.Locals("usage_rows_ingested").(int64)
+
+		recorder.RecordUsage(
+			GetTenant(c),
+			GetAPIKeyName(c),
+			rowsRead,
+			rowsIngested,
+			int64(max(c.Request().Header.ContentLength(), 0)),
+			int64(len(c.Response().Body())),
+		)
+
+		return err
+	}
+}
+
+// SetRowsRead reports the number of rows returned to the caller
+func SetRowsRead(c *fiber.Ctx, rows int) {
+	c.Locals("usage_rows_read", int64(rows))
+}
+
+// SetRowsIngested reports the number of rows ingested on behalf of the caller
+func SetRowsIngested(c *fiber.Ctx, rows int) {
+	c.Locals("usage_rows_ingested", int64(rows))
+}
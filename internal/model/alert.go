@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+)
+
+// Alert rule conditions, evaluated on each new daily bar against the previous one
+const (
+	AlertConditionCrossAbove  = "cross_above"  // close crosses above the threshold level
+	AlertConditionCrossBelow  = "cross_below"  // close crosses below the threshold level
+	AlertConditionChangeAbove = "change_above" // day-over-day change in percent reaches the threshold or more
+	AlertConditionChangeBelow = "change_below" // day-over-day change in percent reaches the threshold or less
+)
+
+// Alert delivery channels
+const (
+	AlertChannelWebhook = "webhook"
+	AlertChannelEmail   = "email"
+)
+
+// Alert event delivery statuses
+const (
+	AlertEventStatusPending   = "pending"
+	AlertEventStatusDelivered = "delivered"
+	AlertEventStatusFailed    = "failed"
+)
+
+// AlertRule is a condition on a symbol's closes that notifies a webhook or an
+// email address when new data meets it
+type AlertRule struct {
+	ID              uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Tenant          string     `gorm:"type:varchar(64);not null;index:idx_alert_rules_tenant" json:"tenant"`
+	APIKey          string     `gorm:"column:api_key;type:varchar(64);not null" json:"api_key"`
+	Name            string     `gorm:"type:varchar(100);not null;default:''" json:"name"`
+	Symbol          string     `gorm:"type:varchar(20);not null;index:idx_alert_rules_symbol_enabled" json:"symbol"`
+	Condition       string     `gorm:"column:condition_type;type:varchar(20);not null" json:"condition"`
+	Threshold       float64    `gorm:"type:decimal(20,8);not null" json:"threshold"` // price level, or percent for change conditions
+	Channel         string     `gorm:"type:varchar(16);not null" json:"channel"`
+	Target          string     `gorm:"type:varchar(512);not null" json:"target"` // webhook URL or email address
+	Enabled         bool       `gorm:"not null;index:idx_alert_rules_symbol_enabled" json:"enabled"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// AlertEvent records a rule firing on a bar and the outcome of its delivery
+type AlertEvent struct {
+	ID            uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	RuleID        uint64     `gorm:"not null;uniqueIndex:unique_alert_events_rule_date" json:"rule_id"`
+	Symbol        string     `gorm:"type:varchar(20);not null" json:"symbol"`
+	Date          time.Time  `gorm:"type:date;not null;uniqueIndex:unique_alert_events_rule_date" json:"date"`
+	PreviousClose float64    `gorm:"type:decimal(20,8);not null" json:"previous_close"`
+	Close         float64    `gorm:"type:decimal(20,8);not null" json:"close"`
+	Value         float64    `gorm:"type:decimal(20,8);not null" json:"value"` // close, or change in percent for change conditions
+	Status        string     `gorm:"type:varchar(16);not null" json:"status"`
+	Error         string     `gorm:"type:text" json:"error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (AlertEvent) TableName() string {
+	return "alert_events"
+}
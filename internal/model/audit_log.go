@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+// AuditLog represents a record of a mutating API call
+type AuditLog struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	RequestID   string    `gorm:"type:varchar(64);not null;index:idx_audit_request_id" json:"request_id"`
+	Tenant      string    `gorm:"type:varchar(64);not null;index:idx_audit_tenant_created" json:"tenant"`
+	APIKey      string    `gorm:"column:api_key;type:varchar(64);not null" json:"api_key"`
+	Role        string    `gorm:"type:varchar(20);not null" json:"role"`
+	ClientIP    string    `gorm:"type:varchar(64)" json:"client_ip"`
+	Method      string    `gorm:"type:varchar(10);not null" json:"method"`
+	Route       string    `gorm:"type:varchar(255);not null" json:"route"`
+	Path        string    `gorm:"type:varchar(1024);not null" json:"path"`
+	Symbols     string    `gorm:"type:text" json:"symbols"`      // comma-separated affected symbols
+	ResourceIDs string    `gorm:"type:text" json:"resource_ids"` // comma-separated affected record IDs
+	StatusCode  int       `gorm:"not null" json:"status_code"`
+	Outcome     string    `gorm:"type:varchar(20);not null" json:"outcome"` // success or failure
+	Error       string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs  int64     `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index:idx_audit_tenant_created;index:idx_audit_created" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
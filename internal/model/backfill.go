@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+)
+
+// Backfill and chunk statuses
+const (
+	BackfillStatusPending   = "pending"
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusPartial   = "partial" // finished with some failed chunks (backfills only)
+	BackfillStatusFailed    = "failed"
+)
+
+// Backfill represents a request to load history for a list of symbols from a provider
+type Backfill struct {
+	ID              uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Tenant          string     `gorm:"type:varchar(64);not null" json:"tenant"`
+	APIKey          string     `gorm:"column:api_key;type:varchar(64);not null" json:"api_key"`
+	Provider        string     `gorm:"type:varchar(32);not null" json:"provider"`
+	SourceID        *uint64    `json:"source_id,omitempty"`
+	Symbols         string     `gorm:"type:mediumtext;not null" json:"symbols"` // comma-separated
+	StartDate       time.Time  `gorm:"type:date;not null" json:"start_date"`
+	EndDate         time.Time  `gorm:"type:date;not null" json:"end_date"`
+	Status          string     `gorm:"type:varchar(20);not null;index:idx_backfills_status" json:"status"`
+	TotalChunks     int        `gorm:"not null;default:0" json:"total_chunks"`
+	CompletedChunks int        `gorm:"not null;default:0" json:"completed_chunks"`
+	FailedChunks    int        `gorm:"not null;default:0" json:"failed_chunks"`
+	RowsIngested    int64      `gorm:"not null;default:0" json:"rows_ingested"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Backfill) TableName() string {
+	return "backfills"
+}
+
+// BackfillChunk is the unit of backfill work: one symbol over one date window
+type BackfillChunk struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	BackfillID   uint64     `gorm:"not null;index:idx_backfill_chunks_backfill_status" json:"backfill_id"`
+	Symbol       string     `gorm:"type:varchar(20);not null" json:"symbol"`
+	StartDate    time.Time  `gorm:"type:date;not null" json:"start_date"`
+	EndDate      time.Time  `gorm:"type:date;not null" json:"end_date"`
+	Status       string     `gorm:"type:varchar(20);not null;index:idx_backfill_chunks_backfill_status" json:"status"`
+	Attempts     int        `gorm:"not null;default:0" json:"attempts"`
+	RowsFetched  int        `gorm:"not null;default:0" json:"rows_fetched"`
+	RowsIngested int        `gorm:"not null;default:0" json:"rows_ingested"`
+	Error        string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (BackfillChunk) TableName() string {
+	return "backfill_chunks"
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// Corporate action types
+const (
+	CorporateActionSplit    = "split"
+	CorporateActionDividend = "dividend"
+)
+
+// CorporateAction is a split or cash dividend that takes effect on its ex-date
+type CorporateAction struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol    string    `gorm:"type:varchar(20);not null;uniqueIndex:unique_corporate_actions_symbol_type_date" json:"symbol"`
+	Type      string    `gorm:"type:varchar(16);not null;uniqueIndex:unique_corporate_actions_symbol_type_date" json:"type"`
+	ExDate    time.Time `gorm:"type:date;not null;uniqueIndex:unique_corporate_actions_symbol_type_date" json:"ex_date"`
+	Ratio     float64   `gorm:"type:decimal(20,8);not null;default:0" json:"ratio,omitempty"`  // splits: new shares per old share, e.g. 4 for a 4-for-1
+	Amount    float64   `gorm:"type:decimal(20,8);not null;default:0" json:"amount,omitempty"` // dividends: cash per share in the symbol's currency
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (CorporateAction) TableName() string {
+	return "corporate_actions"
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+// DataLock freezes the bars of a symbol between two dates, inclusive, so
+// uploads touching them are rejected unless an admin overrides the lock
+type DataLock struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol    string    `gorm:"type:varchar(20);not null;index:idx_data_locks_symbol_dates" json:"symbol"`
+	StartDate time.Time `gorm:"type:date;not null;index:idx_data_locks_symbol_dates" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date;not null;index:idx_data_locks_symbol_dates" json:"end_date"`
+	Reason    string    `gorm:"type:varchar(255);not null;default:''" json:"reason,omitempty"`
+	CreatedBy string    `gorm:"type:varchar(100);not null;default:''" json:"created_by,omitempty"` // API key name of the admin
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (DataLock) TableName() string {
+	return "data_locks"
+}
+
+// Covers reports whether the lock covers a bar of symbol on date
+func (l *DataLock) Covers(symbol string, date time.Time) bool {
+	return l.Symbol == symbol && !date.Before(l.StartDate) && !date.After(l.EndDate)
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EarningsEvent is the report of a symbol's results for one fiscal period: the
+// expected EPS ahead of the report date and the actual EPS once reported
+type EarningsEvent struct {
+	ID          uint64              `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol      string              `gorm:"type:varchar(20);not null;uniqueIndex:unique_earnings_events_symbol_period;index:idx_earnings_events_symbol_report_date" json:"symbol"`
+	Period      string              `gorm:"type:varchar(6);not null;uniqueIndex:unique_earnings_events_symbol_period" json:"period"` // fiscal period reported, 2024Q1 or 2024FY
+	ReportDate  time.Time           `gorm:"type:date;not null;index:idx_earnings_events_symbol_report_date;index:idx_earnings_events_report_date" json:"report_date"`
+	EPSEstimate decimal.NullDecimal `gorm:"column:eps_estimate;type:decimal(20,8)" json:"eps_estimate"` // consensus estimate, null when unknown
+	EPSActual   decimal.NullDecimal `gorm:"column:eps_actual;type:decimal(20,8)" json:"eps_actual"`     // null until reported
+	CreatedAt   time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (EarningsEvent) TableName() string {
+	return "earnings_events"
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+)
+
+// Export job statuses
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+	ExportStatusExpired   = "expired" // the artifact was removed after the retention period
+)
+
+// Export artifact formats
+const (
+	ExportFormatCSV     = "csv" // gzip-compressed CSV
+	ExportFormatParquet = "parquet"
+)
+
+// ExportJob represents a bulk export of historical data to a downloadable artifact
+type ExportJob struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Tenant      string     `gorm:"type:varchar(64);not null;index:idx_export_jobs_tenant_created" json:"tenant"`
+	APIKey      string     `gorm:"column:api_key;type:varchar(64);not null" json:"api_key"`
+	Symbols     string     `gorm:"type:mediumtext;not null" json:"symbols"` // comma-separated
+	StartDate   *time.Time `gorm:"type:date" json:"start_date,omitempty"`
+	EndDate     *time.Time `gorm:"type:date" json:"end_date,omitempty"`
+	Format      string     `gorm:"type:varchar(10);not null" json:"format"`
+	Status      string     `gorm:"type:varchar(20);not null;index:idx_export_jobs_status" json:"status"`
+	RowCount    int64      `gorm:"not null;default:0" json:"row_count"`
+	SizeBytes   int64      `gorm:"not null;default:0" json:"size_bytes"`
+	ArtifactKey string     `gorm:"type:varchar(255)" json:"-"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // when the artifact is removed
+	CreatedAt   time.Time  `gorm:"autoCreateTime;index:idx_export_jobs_tenant_created" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Fundamental holds the reported figures of a symbol for one fiscal period.
+// Figures the source didn't report are null.
+type Fundamental struct {
+	ID                uint64              `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol            string              `gorm:"type:varchar(20);not null;uniqueIndex:unique_fundamentals_symbol_period;index:idx_fundamentals_symbol_period_end" json:"symbol"`
+	Period            string              `gorm:"type:varchar(6);not null;uniqueIndex:unique_fundamentals_symbol_period" json:"period"` // 2024Q1 for a quarter, 2024FY for a fiscal year
+	PeriodEnd         time.Time           `gorm:"type:date;not null;index:idx_fundamentals_symbol_period_end" json:"period_end"`        // last day of the period, held as UTC midnight
+	EPS               decimal.NullDecimal `gorm:"column:eps;type:decimal(20,8)" json:"eps"`                                             // diluted earnings per share
+	Revenue           decimal.NullDecimal `gorm:"type:decimal(24,4)" json:"revenue"`
+	NetIncome         decimal.NullDecimal `gorm:"type:decimal(24,4)" json:"net_income"`
+	SharesOutstanding *uint64             `gorm:"type:bigint unsigned" json:"shares_outstanding"`
+	SourceID          *uint64             `gorm:"index:idx_fundamentals_source_id" json:"source_id"`
+	CreatedAt         time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Fundamental) TableName() string {
+	return "fundamentals"
+}
+
+// IsAnnual reports whether the figures cover a whole fiscal year
+func (f *Fundamental) IsAnnual() bool {
+	return strings.HasSuffix(f.Period, "FY")
+}
@@ -1,24 +1,79 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+// PriceScale is the number of decimal places prices are stored with
+const PriceScale = 8
+
 // HistoricalData represents OHLC historical data entity
 type HistoricalData struct {
-	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	Symbol    string    `gorm:"type:varchar(20);not null;index:idx_symbol_date" json:"symbol"`
-	Date      time.Time `gorm:"type:date;not null;index:idx_symbol_date" json:"date"`
-	Open      float64   `gorm:"type:decimal(20,8);not null" json:"open"`
-	High      float64   `gorm:"type:decimal(20,8);not null" json:"high"`
-	Low       float64   `gorm:"type:decimal(20,8);not null" json:"low"`
-	Close     float64   `gorm:"type:decimal(20,8);not null" json:"close"`
-	Volume    uint64    `gorm:"type:bigint unsigned;not null;default:0" json:"volume"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID             uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol         string          `gorm:"type:varchar(20);not null;uniqueIndex:unique_symbol_date;index:idx_symbol_date_close_volume" json:"symbol"`
+	Date           time.Time       `gorm:"type:date;not null;uniqueIndex:unique_symbol_date;index:idx_symbol_date_close_volume;index:idx_date" json:"date"` // calendar date of the bar, held as UTC midnight
+	Open           decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"open"`
+	High           decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"high"`
+	Low            decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"low"`
+	Close          decimal.Decimal `gorm:"type:decimal(20,8);not null;index:idx_symbol_date_close_volume" json:"close"`
+	Volume         uint64          `gorm:"type:bigint unsigned;not null;default:0;index:idx_symbol_date_close_volume" json:"volume"`
+	OpenInterest   *uint64         `gorm:"type:bigint unsigned" json:"open_interest,omitempty"`    // nil where the source doesn't report it
+	NumberOfTrades *uint64         `gorm:"type:bigint unsigned" json:"number_of_trades,omitempty"` // nil where the source doesn't report it
+	SourceID       *uint64         `gorm:"index:idx_source_id" json:"source_id"`                   // see Source; nil for rows loaded before provenance tracking
+	Attributes     Attributes      `gorm:"type:json" json:"attributes,omitempty"`                  // vendor columns without a dedicated field (adjclose, ...); nil unless captured
+	CreatedAt      time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // TableName specifies the table name for GORM
 func (HistoricalData) TableName() string {
 	return "historical_data"
 }
+
+// ScalePrices multiplies the open, high, low and close by factor, rounding to
+// the stored scale. Used for split, dividend and currency adjustments.
+func (d *HistoricalData) ScalePrices(factor float64) {
+	f := decimal.NewFromFloat(factor)
+	d.Open = d.Open.Mul(f).Round(PriceScale)
+	d.High = d.High.Mul(f).Round(PriceScale)
+	d.Low = d.Low.Mul(f).Round(PriceScale)
+	d.Close = d.Close.Mul(f).Round(PriceScale)
+}
+
+// Attributes are extra values of a bar keyed by their lower-cased column name,
+// stored as a JSON object
+type Attributes map[string]string
+
+// Value implements driver.Valuer; empty attributes are stored as NULL
+func (a Attributes) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(map[string]string(a))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (a *Attributes) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported attributes type %T", value)
+	}
+	return json.Unmarshal(b, (*map[string]string)(a))
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HistoricalRollup aggregates the daily bars of a symbol over an ISO week or a
+// calendar month. It is maintained from historical_data as bars are ingested.
+type HistoricalRollup struct {
+	ID          uint64          `gorm:"primaryKey;autoIncrement" json:"-"`
+	Symbol      string          `gorm:"type:varchar(20);not null;uniqueIndex:unique_historical_rollups_symbol_period_start" json:"symbol"`
+	Period      string          `gorm:"type:varchar(8);not null;uniqueIndex:unique_historical_rollups_symbol_period_start" json:"period"` // weekly or monthly
+	PeriodStart time.Time       `gorm:"type:date;not null;uniqueIndex:unique_historical_rollups_symbol_period_start" json:"period_start"`
+	PeriodEnd   time.Time       `gorm:"type:date;not null" json:"period_end"` // date of the last bar of the period
+	Open        decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"open"`
+	High        decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"high"`
+	Low         decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"low"`
+	Close       decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"close"`
+	Volume      uint64          `gorm:"type:bigint unsigned;not null;default:0" json:"volume"`
+	BarCount    int             `gorm:"not null;default:0" json:"bar_count"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (HistoricalRollup) TableName() string {
+	return "historical_rollups"
+}
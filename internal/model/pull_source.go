@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+// Pulled file statuses
+const (
+	PullFileStatusIngested = "ingested"
+	PullFileStatusFailed   = "failed"
+)
+
+// PullSourceState tracks the polling of a configured pull source
+type PullSourceState struct {
+	Name          string     `gorm:"primaryKey;type:varchar(64)" json:"name"`
+	LastPollAt    *time.Time `json:"last_poll_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"` // last poll that reached the server and listed the directory
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	FilesIngested int64      `gorm:"not null;default:0" json:"files_ingested"`
+	FilesFailed   int64      `gorm:"not null;default:0" json:"files_failed"`
+	RowsIngested  int64      `gorm:"not null;default:0" json:"rows_ingested"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (PullSourceState) TableName() string {
+	return "pull_source_states"
+}
+
+// PullFile records a file fetched from a pull source. A file is identified by
+// its name, size and modification time, so a replaced file is fetched again.
+type PullFile struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Source      string    `gorm:"type:varchar(64);not null;uniqueIndex:unique_pull_files_source_file" json:"source"`
+	Filename    string    `gorm:"type:varchar(255);not null;uniqueIndex:unique_pull_files_source_file" json:"filename"`
+	Size        int64     `gorm:"not null;uniqueIndex:unique_pull_files_source_file" json:"size"`
+	ModifiedAt  time.Time `gorm:"not null;uniqueIndex:unique_pull_files_source_file" json:"modified_at"`
+	Status      string    `gorm:"type:varchar(20);not null" json:"status"`
+	UploadJobID *uint64   `json:"upload_job_id,omitempty"`
+	RowCount    int       `gorm:"not null;default:0" json:"row_count"` // rows stored
+	Error       string    `gorm:"type:text" json:"error,omitempty"`
+	ArchivedTo  string    `gorm:"type:varchar(512)" json:"archived_to,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (PullFile) TableName() string {
+	return "pull_files"
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote is the bid and ask of a symbol at the end of a day, for datasets that
+// are quote based rather than OHLC (e.g. FX)
+type Quote struct {
+	ID        uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol    string          `gorm:"type:varchar(20);not null;uniqueIndex:unique_quotes_symbol_date" json:"symbol"`
+	Date      time.Time       `gorm:"type:date;not null;uniqueIndex:unique_quotes_symbol_date;index:idx_quotes_date" json:"date"` // calendar date, held as UTC midnight
+	Bid       decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"bid"`
+	Ask       decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"ask"`
+	SourceID  *uint64         `gorm:"index:idx_quotes_source_id" json:"source_id"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Quote) TableName() string {
+	return "quotes"
+}
+
+// Mid returns the midpoint of the bid and ask
+func (q *Quote) Mid() decimal.Decimal {
+	return q.Bid.Add(q.Ask).Div(decimal.NewFromInt(2)).Round(PriceScale)
+}
+
+// Spread returns the ask minus the bid
+func (q *Quote) Spread() decimal.Decimal {
+	return q.Ask.Sub(q.Bid)
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// Source kinds
+const (
+	SourceKindUpload   = "upload"
+	SourceKindBackfill = "backfill"
+)
+
+// Source records where a set of historical rows came from: one row per upload
+// or backfill. HistoricalData.SourceID points at the source of its latest write.
+type Source struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Kind        string    `gorm:"type:varchar(20);not null;index:idx_sources_kind_created" json:"kind"`
+	Name        string    `gorm:"type:varchar(255);not null;index:idx_sources_name" json:"name"` // upload filename or provider name
+	Tenant      string    `gorm:"type:varchar(64);not null" json:"tenant"`
+	UploadJobID *uint64   `json:"upload_job_id,omitempty"`
+	BackfillID  *uint64   `json:"backfill_id,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index:idx_sources_kind_created" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Source) TableName() string {
+	return "sources"
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StagedBar is a row of a staged upload waiting in the staging table. The
+// validation pass sets ErrorCode on the rows it rejects; the others are merged
+// into historical_data in one statement, and every row of the job is then
+// removed.
+type StagedBar struct {
+	ID             uint64          `gorm:"primaryKey;autoIncrement" json:"-"`
+	JobID          uint64          `gorm:"not null;index:idx_staging_job_symbol_date" json:"job_id"` // upload job the row belongs to
+	Line           int             `gorm:"not null" json:"line"`                                     // CSV line of the row
+	Symbol         string          `gorm:"type:varchar(20);not null;index:idx_staging_job_symbol_date" json:"symbol"`
+	Date           time.Time       `gorm:"type:date;not null;index:idx_staging_job_symbol_date" json:"date"`
+	Open           decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"open"`
+	High           decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"high"`
+	Low            decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"low"`
+	Close          decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"close"`
+	Volume         uint64          `gorm:"type:bigint unsigned;not null;default:0" json:"volume"`
+	OpenInterest   *uint64         `gorm:"type:bigint unsigned" json:"open_interest,omitempty"`
+	NumberOfTrades *uint64         `gorm:"type:bigint unsigned" json:"number_of_trades,omitempty"`
+	SourceID       *uint64         `json:"source_id"`
+	Attributes     Attributes      `gorm:"type:json" json:"attributes,omitempty"`
+	ErrorCode      *string         `gorm:"type:varchar(32)" json:"error_code,omitempty"` // nil until the validation pass rejects the row
+}
+
+// TableName specifies the table name for GORM
+func (StagedBar) TableName() string {
+	return "historical_data_staging"
+}
+
+// NewStagedBar stages a bar read from line of an upload job
+func NewStagedBar(jobID uint64, line int, bar *HistoricalData) StagedBar {
+	return StagedBar{
+		JobID:          jobID,
+		Line:           line,
+		Symbol:         bar.Symbol,
+		Date:           bar.Date,
+		Open:           bar.Open,
+		High:           bar.High,
+		Low:            bar.Low,
+		Close:          bar.Close,
+		Volume:         bar.Volume,
+		OpenInterest:   bar.OpenInterest,
+		NumberOfTrades: bar.NumberOfTrades,
+		SourceID:       bar.SourceID,
+		Attributes:     bar.Attributes,
+	}
+}
+
+// Bar returns the bar the staged row would be merged as
+func (b *StagedBar) Bar() HistoricalData {
+	return HistoricalData{
+		Symbol:         b.Symbol,
+		Date:           b.Date,
+		Open:           b.Open,
+		High:           b.High,
+		Low:            b.Low,
+		Close:          b.Close,
+		Volume:         b.Volume,
+		OpenInterest:   b.OpenInterest,
+		NumberOfTrades: b.NumberOfTrades,
+		SourceID:       b.SourceID,
+		Attributes:     b.Attributes,
+	}
+}
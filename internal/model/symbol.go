@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// Symbol holds the reference metadata of a traded symbol
+type Symbol struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol    string    `gorm:"type:varchar(20);not null;uniqueIndex:unique_symbols_symbol" json:"symbol"`
+	Name      string    `gorm:"type:varchar(255);not null;default:''" json:"name"`
+	Exchange  string    `gorm:"type:varchar(32);not null;default:''" json:"exchange"`
+	Currency  string    `gorm:"type:char(3);not null;index:idx_symbols_currency" json:"currency"` // ISO 4217 code prices are quoted in
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	FormerNames []string       `gorm:"-" json:"former_names,omitempty"` // see SymbolAlias
+	Tags        []string       `gorm:"-" json:"tags,omitempty"`         // see SymbolTag
+	Summary     *SymbolSummary `gorm:"-" json:"summary,omitempty"`      // coverage of the stored bars
+}
+
+// TableName specifies the table name for GORM
+func (Symbol) TableName() string {
+	return "symbols"
+}
+
+// SymbolAlias maps a former name of a renamed symbol to its current symbol
+type SymbolAlias struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Alias     string    `gorm:"type:varchar(20);not null;uniqueIndex:unique_symbol_aliases_alias" json:"alias"`
+	Symbol    string    `gorm:"type:varchar(20);not null;index:idx_symbol_aliases_symbol" json:"symbol"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (SymbolAlias) TableName() string {
+	return "symbol_aliases"
+}
+
+// SymbolTag attaches a free-form label such as "sp500" or "delisted" to a
+// symbol, so queries can select a group of symbols by tag
+type SymbolTag struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Tag       string    `gorm:"type:varchar(32);not null;uniqueIndex:unique_symbol_tags_tag_symbol,priority:1" json:"tag"`
+	Symbol    string    `gorm:"type:varchar(20);not null;uniqueIndex:unique_symbol_tags_tag_symbol,priority:2;index:idx_symbol_tags_symbol" json:"symbol"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (SymbolTag) TableName() string {
+	return "symbol_tags"
+}
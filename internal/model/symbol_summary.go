@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FiftyTwoWeekDays is the span before a symbol's latest bar covered by its
+// 52-week high and low
+const FiftyTwoWeekDays = 52 * 7
+
+// SymbolSummary caches the coverage and latest close of a symbol's bars. It is
+// refreshed as bars are written so reads never scan historical_data.
+type SymbolSummary struct {
+	ID               uint64          `gorm:"primaryKey;autoIncrement" json:"-"`
+	Symbol           string          `gorm:"type:varchar(20);not null;uniqueIndex:unique_symbol_summary_symbol" json:"symbol"`
+	RowCount         int64           `gorm:"type:bigint unsigned;not null;default:0" json:"row_count"`
+	FirstDate        time.Time       `gorm:"type:date;not null" json:"first_date"`
+	LastDate         time.Time       `gorm:"type:date;not null;index:idx_symbol_summary_last_date" json:"last_date"`
+	LastClose        decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"last_close"`                         // close of the bar on LastDate
+	High52W          decimal.Decimal `gorm:"column:high_52w;type:decimal(20,8);not null;default:0" json:"high_52w"` // highest high of the 52 weeks up to LastDate
+	Low52W           decimal.Decimal `gorm:"column:low_52w;type:decimal(20,8);not null;default:0" json:"low_52w"`   // lowest low of the 52 weeks up to LastDate
+	RangePosition52W *float64        `gorm:"-" json:"range_position_52w"`                                           // LastClose within [Low52W, High52W], 0 at the low and 1 at the high
+	LastIngestedAt   time.Time       `json:"last_ingested_at"`                                                      // when bars of the symbol were last written
+}
+
+// TableName specifies the table name for GORM
+func (SymbolSummary) TableName() string {
+	return "symbol_summary"
+}
+
+// SetRangePosition derives RangePosition52W from the stored close and range,
+// leaving it nil when the range is flat
+func (s *SymbolSummary) SetRangePosition() {
+	s.RangePosition52W = nil
+	width := s.High52W.Sub(s.Low52W)
+	if !width.IsPositive() {
+		return
+	}
+	position, _ := s.LastClose.Sub(s.Low52W).Div(width).Round(4).Float64()
+	s.RangePosition52W = &position
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// SymbolVersion counts the writes touching a symbol's bars, or what derived
+// results depend on such as its corporate actions and currency. It only ever
+// increases, so a result tagged with the version it was computed at is current
+// as long as the version is.
+type SymbolVersion struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"-"`
+	Symbol    string    `gorm:"type:varchar(20);not null;uniqueIndex:unique_symbol_versions_symbol" json:"symbol"`
+	Version   uint64    `gorm:"type:bigint unsigned;not null;default:0" json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (SymbolVersion) TableName() string {
+	return "symbol_versions"
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Tick is a single trade. Ticks are append-only and carry no bookkeeping
+// columns to keep inserts cheap.
+type Tick struct {
+	ID        uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol    string          `gorm:"type:varchar(20);not null;index:idx_ticks_symbol_ts" json:"symbol"`
+	Timestamp time.Time       `gorm:"column:ts;type:datetime(6);not null;index:idx_ticks_symbol_ts" json:"ts"` // UTC, microsecond precision
+	Price     decimal.Decimal `gorm:"type:decimal(20,8);not null" json:"price"`
+	Size      decimal.Decimal `gorm:"type:decimal(20,8);not null;default:0" json:"size"`
+	Side      string          `gorm:"type:varchar(4);not null;default:''" json:"side,omitempty"`    // buy, sell or empty when unknown
+	Session   string          `gorm:"type:varchar(8);not null;default:''" json:"session,omitempty"` // pre, regular, post or empty outside them
+}
+
+// TableName specifies the table name for GORM
+func (Tick) TableName() string {
+	return "ticks"
+}
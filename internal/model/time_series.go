@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TimeSeries is one observation of a generic series with a single value
+// rather than OHLC bars: indicators, fundamentals, macro figures
+type TimeSeries struct {
+	ID        uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	SeriesID  string          `gorm:"type:varchar(64);not null;uniqueIndex:unique_time_series_series_ts" json:"series_id"`
+	Timestamp time.Time       `gorm:"column:ts;type:datetime(6);not null;uniqueIndex:unique_time_series_series_ts" json:"timestamp"` // UTC; calendar dates are held as midnight
+	Value     decimal.Decimal `gorm:"type:decimal(30,10);not null" json:"value"`
+	Fields    Attributes      `gorm:"type:json" json:"fields,omitempty"` // extra values of the observation by name, e.g. unit or revision
+	SourceID  *uint64         `gorm:"index:idx_time_series_source_id" json:"source_id"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (TimeSeries) TableName() string {
+	return "time_series"
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// Upload job statuses
+const (
+	UploadStatusProcessing = "processing"
+	UploadStatusCompleted  = "completed"
+	UploadStatusFailed     = "failed"
+)
+
+// UploadJob represents the processing record of a CSV upload
+type UploadJob struct {
+	ID             uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Tenant         string     `gorm:"type:varchar(64);not null;index:idx_upload_jobs_tenant_created" json:"tenant"`
+	APIKey         string     `gorm:"column:api_key;type:varchar(64);not null" json:"api_key"`
+	Filename       string     `gorm:"type:varchar(255);not null" json:"filename"`
+	FileSize       int64      `gorm:"not null;default:0" json:"file_size"`
+	Status         string     `gorm:"type:varchar(20);not null;index:idx_upload_jobs_status" json:"status"`
+	TotalRows      int        `gorm:"not null;default:0" json:"total_rows"`
+	SuccessCount   int        `gorm:"not null;default:0" json:"success_count"`
+	FailedCount    int        `gorm:"not null;default:0" json:"failed_count"`
+	ProcessedBytes int64      `gorm:"not null;default:0" json:"processed_bytes"` // bytes of the file stored, the checkpoint of a resumable upload
+	CheckpointLine int        `gorm:"not null;default:0" json:"checkpoint_line"` // CSV records read up to processed_bytes, the header included
+	Resumes        int        `gorm:"not null;default:0" json:"resumes"`         // times the upload was resumed from its checkpoint
+	Symbols        string     `gorm:"type:text" json:"symbols"`                  // comma-separated
+	Message        string     `gorm:"type:text" json:"message"`
+	StartedAt      time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime;index:idx_upload_jobs_tenant_created" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (UploadJob) TableName() string {
+	return "upload_jobs"
+}
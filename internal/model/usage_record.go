@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// UsageRecord represents daily usage counters for a tenant and API key
+type UsageRecord struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Tenant       string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_usage_tenant_key_day" json:"tenant"`
+	APIKey       string    `gorm:"column:api_key;type:varchar(64);not null;uniqueIndex:idx_usage_tenant_key_day" json:"api_key"`
+	Day          time.Time `gorm:"type:date;not null;uniqueIndex:idx_usage_tenant_key_day" json:"day"`
+	RowsIngested int64     `gorm:"not null;default:0" json:"rows_ingested"`
+	RowsRead     int64     `gorm:"not null;default:0" json:"rows_read"`
+	BytesIn      int64     `gorm:"not null;default:0" json:"bytes_in"`
+	BytesOut     int64     `gorm:"not null;default:0" json:"bytes_out"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (UsageRecord) TableName() string {
+	return "usage_records"
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Watchlist is a named list of symbols owned by an API key
+type Watchlist struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Tenant    string    `gorm:"type:varchar(64);not null;index:idx_watchlists_owner" json:"tenant"`
+	APIKey    string    `gorm:"column:api_key;type:varchar(64);not null;index:idx_watchlists_owner" json:"api_key"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	Symbols   string    `gorm:"type:text;not null" json:"symbols"` // comma-separated, in list order
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Watchlist) TableName() string {
+	return "watchlists"
+}
+
+// SymbolList returns the symbols of the watchlist in list order
+func (w *Watchlist) SymbolList() []string {
+	if w.Symbols == "" {
+		return []string{}
+	}
+	return strings.Split(w.Symbols, ",")
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+)
+
+// EmailChannel is the channel of the email notifier
+const EmailChannel = "email"
+
+// emailNotifier sends plain-text emails through an SMTP relay
+type emailNotifier struct {
+	cfg config.SMTPConfig
+}
+
+// NewEmailNotifier creates a notifier sending emails through the configured
+// SMTP server, authenticating when a username is set
+func NewEmailNotifier(cfg config.SMTPConfig) Notifier {
+	return &emailNotifier{cfg: cfg}
+}
+
+// Channel returns the notifier channel
+func (n *emailNotifier) Channel() string {
+	return EmailChannel
+}
+
+// Notify sends the subject and text of msg to the target address
+func (n *emailNotifier) Notify(ctx context.Context, target string, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", target)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Text, "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	if err := smtp.SendMail(addr, auth, n.cfg.From, []string{target}, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownChannel is returned when no notifier is registered for a channel
+var ErrUnknownChannel = errors.New("unknown notification channel")
+
+// Message is a notification rendered for every channel
+type Message struct {
+	Subject string      // email subject
+	Text    string      // plain-text email body
+	Payload interface{} // JSON body of webhooks
+}
+
+// Notifier delivers messages over one channel
+type Notifier interface {
+	// Channel is the identifier rules use to select the notifier
+	Channel() string
+	// Notify delivers msg to target, a URL or address depending on the channel
+	Notify(ctx context.Context, target string, msg Message) error
+}
+
+// Registry holds the configured notifiers by channel
+type Registry struct {
+	mu        sync.RWMutex
+	notifiers map[string]Notifier
+}
+
+// NewRegistry creates a registry with the given notifiers
+func NewRegistry(notifiers ...Notifier) *Registry {
+	r := &Registry{notifiers: make(map[string]Notifier)}
+	for _, n := range notifiers {
+		r.Register(n)
+	}
+	return r
+}
+
+// Register adds a notifier, replacing any notifier of the same channel
+func (r *Registry) Register(n Notifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifiers[n.Channel()] = n
+}
+
+// Get returns the notifier registered for channel
+func (r *Registry) Get(channel string) (Notifier, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n, ok := r.notifiers[channel]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownChannel, channel)
+	}
+	return n, nil
+}
+
+// Channels returns the sorted channels of the registered notifiers
+func (r *Registry) Channels() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	channels := make([]string, 0, len(r.notifiers))
+	for channel := range r.notifiers {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SlackChannel is the channel of the Slack notifier
+const SlackChannel = "slack"
+
+// slackNotifier posts the subject and text of messages to Slack incoming webhooks
+type slackNotifier struct {
+	client *http.Client
+}
+
+// NewSlackNotifier creates a notifier posting to Slack incoming webhook URLs
+func NewSlackNotifier(timeout time.Duration) Notifier {
+	return &slackNotifier{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Channel returns the notifier channel
+func (n *slackNotifier) Channel() string {
+	return SlackChannel
+}
+
+// Notify posts the message to the target webhook URL with the subject in bold
+func (n *slackNotifier) Notify(ctx context.Context, target string, msg Message) error {
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", msg.Subject, msg.Text),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-historical-data")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("slack responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
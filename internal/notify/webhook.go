@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/go-historical-data/pkg/netguard"
+)
+
+// WebhookChannel is the channel of the webhook notifier
+const WebhookChannel = "webhook"
+
+// ErrForbiddenTarget is returned when a webhook target resolves to a loopback,
+// private, link-local or otherwise non-public address
+var ErrForbiddenTarget = errors.New("webhook target address is not allowed")
+
+// webhookNotifier posts the message payload as JSON
+type webhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting JSON payloads to webhook URLs.
+// Webhook targets are chosen by API clients, so connections are only made to
+// public addresses: the check runs on the address dialled, after resolution,
+// so a name re-resolving to an internal address is refused too. Redirects are
+// not followed and no proxy is used.
+func NewWebhookNotifier(timeout time.Duration) Notifier {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublicOnly}
+	return &webhookNotifier{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// dialPublicOnly refuses connections to non-public addresses
+func dialPublicOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !netguard.IsPublicAddr(addrPort.Addr()) {
+		return ErrForbiddenTarget
+	}
+	return nil
+}
+
+// Channel returns the notifier channel
+func (n *webhookNotifier) Channel() string {
+	return WebhookChannel
+}
+
+// Notify posts the payload to the target URL; any non-2xx status is an error
+func (n *webhookNotifier) Notify(ctx context.Context, target string, msg Message) error {
+	body, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-historical-data")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+)
+
+// ftpClient speaks plain FTP in passive mode and lists directories with MLSD
+// (RFC 3659). Credentials and files travel unencrypted.
+type ftpClient struct {
+	conn    net.Conn
+	text    *textproto.Conn
+	host    string
+	timeout time.Duration
+}
+
+// dialFTP connects, logs in and switches to binary transfers
+func dialFTP(ctx context.Context, cfg config.PullSourceConfig, timeout time.Duration) (*ftpClient, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = 21
+	}
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", net.JoinHostPort(cfg.Host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ftpClient{conn: conn, text: textproto.NewConn(conn), host: cfg.Host, timeout: timeout}
+	if err := c.login(cfg.Username, cfg.Password()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// login reads the greeting and authenticates
+func (c *ftpClient) login(username, password string) error {
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	_, _, err := c.text.ReadResponse(220)
+	c.conn.SetDeadline(time.Time{})
+	if err != nil {
+		return fmt.Errorf("ftp greeting failed: %w", err)
+	}
+
+	code, _, err := c.cmd(0, "USER %s", username)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 230:
+	case 331:
+		if _, _, err := c.cmd(230, "PASS %s", password); err != nil {
+			return fmt.Errorf("ftp login failed: %w", err)
+		}
+	default:
+		return fmt.Errorf("ftp login failed with code %d", code)
+	}
+
+	_, _, err = c.cmd(200, "TYPE I")
+	return err
+}
+
+// List reads a directory with MLSD
+func (c *ftpClient) List(dir string) ([]FileInfo, error) {
+	data, err := c.transfer("MLSD %s", dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileInfo
+	scanner := bufio.NewScanner(data)
+	for scanner.Scan() {
+		if info, ok := parseMLSDLine(scanner.Text()); ok {
+			files = append(files, info)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		data.Close()
+		return nil, err
+	}
+	if err := data.Close(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Open retrieves a file
+func (c *ftpClient) Open(path string) (io.ReadCloser, error) {
+	return c.transfer("RETR %s", path)
+}
+
+// Rename moves a file
+func (c *ftpClient) Rename(from, to string) error {
+	if _, _, err := c.cmd(350, "RNFR %s", from); err != nil {
+		return err
+	}
+	_, _, err := c.cmd(250, "RNTO %s", to)
+	return err
+}
+
+// Mkdir creates a directory, checking a failure against the path since
+// servers report existing directories as a generic failure
+func (c *ftpClient) Mkdir(dir string) error {
+	_, _, mkdirErr := c.cmd(257, "MKD %s", dir)
+	if mkdirErr == nil {
+		return nil
+	}
+	_, facts, err := c.cmd(250, "MLST %s", dir)
+	if err != nil || !strings.Contains(strings.ToLower(facts), "type=dir;") {
+		return mkdirErr
+	}
+	return nil
+}
+
+// Close logs out and closes the connection
+func (c *ftpClient) Close() error {
+	c.cmd(221, "QUIT")
+	return c.conn.Close()
+}
+
+// cmd sends a command and reads its reply; an expected code of 0 accepts any
+func (c *ftpClient) cmd(expect int, format string, args ...interface{}) (int, string, error) {
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	id, err := c.text.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+	c.text.StartResponse(id)
+	defer c.text.EndResponse(id)
+
+	return c.text.ReadResponse(expect)
+}
+
+// transfer opens a passive data connection and starts a command sending data
+// over it. Closing the returned reader reads the transfer's final reply.
+func (c *ftpClient) transfer(format string, args ...interface{}) (io.ReadCloser, error) {
+	addr, err := c.passive()
+	if err != nil {
+		return nil, err
+	}
+	data, err := net.DialTimeout("tcp", addr, c.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("ftp data connection failed: %w", err)
+	}
+
+	if _, _, err := c.cmd(1, format, args...); err != nil {
+		data.Close()
+		return nil, err
+	}
+	return &ftpData{client: c, conn: data}, nil
+}
+
+// passive asks for a data port with EPSV, falling back to PASV. The data
+// connection goes to the control host, whatever address PASV announces.
+func (c *ftpClient) passive() (string, error) {
+	if _, message, err := c.cmd(229, "EPSV"); err == nil {
+		start, end := strings.Index(message, "(|||"), strings.LastIndex(message, "|)")
+		if start >= 0 && end > start+4 {
+			if port, err := strconv.Atoi(message[start+4 : end]); err == nil {
+				return net.JoinHostPort(c.host, strconv.Itoa(port)), nil
+			}
+		}
+	}
+
+	_, message, err := c.cmd(227, "PASV")
+	if err != nil {
+		return "", err
+	}
+	start, end := strings.Index(message, "("), strings.Index(message, ")")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("malformed PASV reply %q", message)
+	}
+	fields := strings.Split(message[start+1:end], ",")
+	if len(fields) != 6 {
+		return "", fmt.Errorf("malformed PASV reply %q", message)
+	}
+	hi, err1 := strconv.Atoi(strings.TrimSpace(fields[4]))
+	lo, err2 := strconv.Atoi(strings.TrimSpace(fields[5]))
+	if err1 != nil || err2 != nil {
+		return "", fmt.Errorf("malformed PASV reply %q", message)
+	}
+	return net.JoinHostPort(c.host, strconv.Itoa(hi<<8|lo)), nil
+}
+
+// ftpData reads a data connection, bounding every read by the timeout
+type ftpData struct {
+	client *ftpClient
+	conn   net.Conn
+}
+
+func (d *ftpData) Read(p []byte) (int, error) {
+	d.conn.SetReadDeadline(time.Now().Add(d.client.timeout))
+	return d.conn.Read(p)
+}
+
+// Close closes the data connection and reads the transfer's final reply
+func (d *ftpData) Close() error {
+	d.conn.Close()
+
+	c := d.client
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	defer c.conn.SetDeadline(time.Time{})
+	_, _, err := c.text.ReadResponse(2)
+	return err
+}
+
+// parseMLSDLine parses "type=file;size=123;modify=20240102030405; name",
+// skipping the directory itself and its parent
+func parseMLSDLine(line string) (FileInfo, bool) {
+	facts, name, ok := strings.Cut(line, " ")
+	if !ok || name == "" {
+		return FileInfo{}, false
+	}
+
+	info := FileInfo{Name: name}
+	for _, fact := range strings.Split(facts, ";") {
+		key, value, _ := strings.Cut(fact, "=")
+		switch strings.ToLower(key) {
+		case "type":
+			switch strings.ToLower(value) {
+			case "file":
+			case "dir":
+				info.IsDir = true
+			default: // cdir, pdir and links
+				return FileInfo{}, false
+			}
+		case "size":
+			info.Size, _ = strconv.ParseInt(value, 10, 64)
+		case "modify":
+			if len(value) >= 14 {
+				info.ModTime, _ = time.Parse("20060102150405", value[:14])
+			}
+		}
+	}
+	return info, true
+}
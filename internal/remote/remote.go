@@ -0,0 +1,56 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+)
+
+// Protocols of pull sources
+const (
+	ProtocolSFTP = "sftp"
+	ProtocolFTP  = "ftp"
+)
+
+// ErrUnknownProtocol is returned for a pull source of an unsupported protocol
+var ErrUnknownProtocol = errors.New("unknown pull protocol")
+
+// FileInfo describes a file of a remote directory
+type FileInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+}
+
+// Client is a connection to the file server of a pull source. A client is not
+// safe for concurrent use.
+type Client interface {
+	// List returns the entries of a directory
+	List(dir string) ([]FileInfo, error)
+	// Open streams the content of a file; the reader must be closed before the
+	// next call
+	Open(path string) (io.ReadCloser, error)
+	// Rename moves a file, which must not exist at the new path
+	Rename(from, to string) error
+	// Mkdir creates a directory; an existing directory is not an error
+	Mkdir(dir string) error
+	// Close ends the session
+	Close() error
+}
+
+// Dial connects and logs in to the server of a pull source. Every network
+// operation of the returned client is bounded by timeout.
+func Dial(ctx context.Context, cfg config.PullSourceConfig, timeout time.Duration) (Client, error) {
+	switch cfg.Protocol {
+	case ProtocolSFTP:
+		return dialSFTP(ctx, cfg, timeout)
+	case ProtocolFTP:
+		return dialFTP(ctx, cfg, timeout)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownProtocol, cfg.Protocol)
+}
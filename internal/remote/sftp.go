@@ -0,0 +1,486 @@
+package remote
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTP version 3 packet types, the version OpenSSH speaks
+const (
+	sftpInit     = 1
+	sftpVersion  = 2
+	sftpOpen     = 3
+	sftpClose    = 4
+	sftpRead     = 5
+	sftpOpendir  = 11
+	sftpReaddir  = 12
+	sftpMkdir    = 14
+	sftpStat     = 17
+	sftpRename   = 18
+	sftpStatus   = 101
+	sftpHandle   = 102
+	sftpData     = 103
+	sftpName     = 104
+	sftpAttrs    = 105
+	sftpProtocol = 3
+)
+
+// SFTP status codes and attribute flags
+const (
+	sftpStatusOK  = 0
+	sftpStatusEOF = 1
+
+	sftpAttrSize        = 0x00000001
+	sftpAttrUIDGID      = 0x00000002
+	sftpAttrPermissions = 0x00000004
+	sftpAttrACModTime   = 0x00000008
+	sftpAttrExtended    = 0x80000000
+
+	sftpOpenRead = 0x00000001
+)
+
+// sftpReadSize is the chunk requested per read; servers cap reads at 32 KiB or more
+const sftpReadSize = 32 << 10
+
+// sftpMaxPacket bounds the packets accepted from the server
+const sftpMaxPacket = 256 << 10
+
+// sftpStatusError is a failure reported by the server
+type sftpStatusError struct {
+	Code    uint32
+	Message string
+}
+
+func (e *sftpStatusError) Error() string {
+	return fmt.Sprintf("sftp status %d: %s", e.Code, e.Message)
+}
+
+// sftpClient speaks SFTP version 3 over the subsystem of an SSH session.
+// Requests are sent one at a time.
+type sftpClient struct {
+	conn    net.Conn
+	ssh     *ssh.Client
+	session *ssh.Session
+	w       io.Writer
+	r       io.Reader
+	timeout time.Duration
+	nextID  uint32
+}
+
+// dialSFTP connects with the configured key or password, accepting only the
+// configured host key
+func dialSFTP(ctx context.Context, cfg config.PullSourceConfig, timeout time.Duration) (*sftpClient, error) {
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid host key of pull source %s: %w", cfg.Name, err)
+	}
+
+	var auth []ssh.AuthMethod
+	if cfg.PrivateKeyFile != "" {
+		pem, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if password := cfg.Password(); password != "" {
+		auth = append(auth, ssh.Password(password))
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 22
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            cfg.Username,
+		Auth:            auth,
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         timeout,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+
+	c := &sftpClient{conn: conn, ssh: client, timeout: timeout}
+	if err := c.start(); err != nil {
+		c.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return c, nil
+}
+
+// start opens the sftp subsystem and negotiates the protocol version
+func (c *sftpClient) start() error {
+	session, err := c.ssh.NewSession()
+	if err != nil {
+		return err
+	}
+	c.session = session
+	if c.w, err = session.StdinPipe(); err != nil {
+		return err
+	}
+	if c.r, err = session.StdoutPipe(); err != nil {
+		return err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return fmt.Errorf("failed to start sftp subsystem: %w", err)
+	}
+
+	if err := c.send(sftpInit, binary.BigEndian.AppendUint32(nil, sftpProtocol)); err != nil {
+		return err
+	}
+	typ, data, err := c.recv()
+	if err != nil {
+		return err
+	}
+	if typ != sftpVersion || len(data) < 4 {
+		return fmt.Errorf("unexpected sftp packet %d during version negotiation", typ)
+	}
+	if version := binary.BigEndian.Uint32(data); version < sftpProtocol {
+		return fmt.Errorf("sftp server speaks version %d, version %d is required", version, sftpProtocol)
+	}
+	return nil
+}
+
+// List reads the entries of a directory, leaving out . and ..
+func (c *sftpClient) List(dir string) ([]FileInfo, error) {
+	handle, err := c.handle(sftpOpendir, appendString(nil, dir))
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeHandle(handle)
+
+	var files []FileInfo
+	for {
+		typ, data, err := c.request(sftpReaddir, appendString(nil, handle))
+		if err != nil {
+			return nil, err
+		}
+		if typ == sftpStatus {
+			if err := statusError(data); err != nil {
+				if isEOF(err) {
+					return files, nil
+				}
+				return nil, err
+			}
+			return files, nil
+		}
+		if typ != sftpName {
+			return nil, fmt.Errorf("unexpected sftp packet %d listing %s", typ, dir)
+		}
+
+		r := &sftpReader{b: data}
+		count := r.uint32()
+		for i := uint32(0); i < count && r.err == nil; i++ {
+			name := r.string()
+			r.string() // long name
+			info := r.attrs()
+			if name == "." || name == ".." {
+				continue
+			}
+			info.Name = name
+			files = append(files, info)
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("malformed sftp listing of %s: %w", dir, r.err)
+		}
+	}
+}
+
+// Open opens a file for reading
+func (c *sftpClient) Open(path string) (io.ReadCloser, error) {
+	payload := appendString(nil, path)
+	payload = binary.BigEndian.AppendUint32(payload, sftpOpenRead)
+	payload = binary.BigEndian.AppendUint32(payload, 0) // no attributes
+	handle, err := c.handle(sftpOpen, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &sftpFile{client: c, handle: handle}, nil
+}
+
+// Rename moves a file
+func (c *sftpClient) Rename(from, to string) error {
+	typ, data, err := c.request(sftpRename, appendString(appendString(nil, from), to))
+	if err != nil {
+		return err
+	}
+	return expectStatus(typ, data)
+}
+
+// Mkdir creates a directory; servers report an existing directory as a
+// generic failure, so a failure is checked against the path
+func (c *sftpClient) Mkdir(dir string) error {
+	typ, data, err := c.request(sftpMkdir, binary.BigEndian.AppendUint32(appendString(nil, dir), 0))
+	if err != nil {
+		return err
+	}
+	mkdirErr := expectStatus(typ, data)
+	if mkdirErr == nil {
+		return nil
+	}
+
+	typ, data, err = c.request(sftpStat, appendString(nil, dir))
+	if err != nil || typ != sftpAttrs {
+		return mkdirErr
+	}
+	if info := (&sftpReader{b: data}).attrs(); !info.IsDir {
+		return mkdirErr
+	}
+	return nil
+}
+
+// Close ends the session and the connection
+func (c *sftpClient) Close() error {
+	if c.session != nil {
+		c.session.Close()
+	}
+	return c.ssh.Close()
+}
+
+// handle sends a request answered with a handle
+func (c *sftpClient) handle(typ byte, payload []byte) (string, error) {
+	replyType, data, err := c.request(typ, payload)
+	if err != nil {
+		return "", err
+	}
+	if replyType == sftpStatus {
+		if err := statusError(data); err != nil {
+			return "", err
+		}
+	}
+	if replyType != sftpHandle {
+		return "", fmt.Errorf("unexpected sftp packet %d, expected a handle", replyType)
+	}
+	r := &sftpReader{b: data}
+	handle := r.string()
+	return handle, r.err
+}
+
+// closeHandle releases a file or directory handle
+func (c *sftpClient) closeHandle(handle string) error {
+	typ, data, err := c.request(sftpClose, appendString(nil, handle))
+	if err != nil {
+		return err
+	}
+	return expectStatus(typ, data)
+}
+
+// request sends a request and returns the type and payload of its reply,
+// past the request ID. The connection deadline bounds the round trip.
+func (c *sftpClient) request(typ byte, payload []byte) (byte, []byte, error) {
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	c.nextID++
+	id := c.nextID
+	if err := c.send(typ, append(binary.BigEndian.AppendUint32(nil, id), payload...)); err != nil {
+		return 0, nil, err
+	}
+	replyType, data, err := c.recv()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 4 || binary.BigEndian.Uint32(data) != id {
+		return 0, nil, fmt.Errorf("sftp reply does not match request %d", id)
+	}
+	return replyType, data[4:], nil
+}
+
+// send writes a packet: its length, type and payload
+func (c *sftpClient) send(typ byte, payload []byte) error {
+	packet := binary.BigEndian.AppendUint32(make([]byte, 0, 5+len(payload)), uint32(len(payload)+1))
+	packet = append(packet, typ)
+	packet = append(packet, payload...)
+	_, err := c.w.Write(packet)
+	return err
+}
+
+// recv reads a packet
+func (c *sftpClient) recv() (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return 0, nil, fmt.Errorf("failed to read sftp packet: %w", err)
+	}
+	length := binary.BigEndian.Uint32(header[:4])
+	if length < 1 || length > sftpMaxPacket {
+		return 0, nil, fmt.Errorf("sftp packet of %d bytes exceeds the limit", length)
+	}
+	data := make([]byte, length-1)
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return 0, nil, fmt.Errorf("failed to read sftp packet: %w", err)
+	}
+	return header[4], data, nil
+}
+
+// sftpFile reads a remote file sequentially
+type sftpFile struct {
+	client *sftpClient
+	handle string
+	offset uint64
+	eof    bool
+}
+
+// Read requests the next chunk of the file
+func (f *sftpFile) Read(p []byte) (int, error) {
+	if f.eof {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	payload := appendString(nil, f.handle)
+	payload = binary.BigEndian.AppendUint64(payload, f.offset)
+	payload = binary.BigEndian.AppendUint32(payload, uint32(min(len(p), sftpReadSize)))
+	typ, data, err := f.client.request(sftpRead, payload)
+	if err != nil {
+		return 0, err
+	}
+	switch typ {
+	case sftpStatus:
+		err := statusError(data)
+		if err == nil || isEOF(err) {
+			f.eof = true
+			return 0, io.EOF
+		}
+		return 0, err
+	case sftpData:
+		r := &sftpReader{b: data}
+		chunk := r.string()
+		if r.err != nil {
+			return 0, r.err
+		}
+		n := copy(p, chunk)
+		f.offset += uint64(n)
+		return n, nil
+	}
+	return 0, fmt.Errorf("unexpected sftp packet %d reading a file", typ)
+}
+
+// Close releases the file handle
+func (f *sftpFile) Close() error {
+	return f.client.closeHandle(f.handle)
+}
+
+// sftpReader decodes the fields of a reply, recording the first short read
+type sftpReader struct {
+	b   []byte
+	err error
+}
+
+func (r *sftpReader) uint32() uint32 {
+	if len(r.b) < 4 {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v
+}
+
+func (r *sftpReader) uint64() uint64 {
+	if len(r.b) < 8 {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	v := binary.BigEndian.Uint64(r.b)
+	r.b = r.b[8:]
+	return v
+}
+
+func (r *sftpReader) string() string {
+	n := r.uint32()
+	if r.err != nil || uint32(len(r.b)) < n {
+		r.err = io.ErrUnexpectedEOF
+		return ""
+	}
+	s := string(r.b[:n])
+	r.b = r.b[n:]
+	return s
+}
+
+// attrs decodes file attributes, keeping the size, modification time and type
+func (r *sftpReader) attrs() FileInfo {
+	var info FileInfo
+	flags := r.uint32()
+	if flags&sftpAttrSize != 0 {
+		info.Size = int64(r.uint64())
+	}
+	if flags&sftpAttrUIDGID != 0 {
+		r.uint32()
+		r.uint32()
+	}
+	if flags&sftpAttrPermissions != 0 {
+		info.IsDir = r.uint32()&0o170000 == 0o040000
+	}
+	if flags&sftpAttrACModTime != 0 {
+		r.uint32() // access time
+		info.ModTime = time.Unix(int64(r.uint32()), 0).UTC()
+	}
+	if flags&sftpAttrExtended != 0 {
+		for n := r.uint32(); n > 0 && r.err == nil; n-- {
+			r.string()
+			r.string()
+		}
+	}
+	return info
+}
+
+// appendString appends a length-prefixed string
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// statusError returns the failure of a status reply, nil for success
+func statusError(data []byte) error {
+	r := &sftpReader{b: data}
+	code := r.uint32()
+	message := r.string()
+	if r.err != nil {
+		return fmt.Errorf("malformed sftp status: %w", r.err)
+	}
+	if code == sftpStatusOK {
+		return nil
+	}
+	return &sftpStatusError{Code: code, Message: message}
+}
+
+// expectStatus returns the failure of a reply that must be a status
+func expectStatus(typ byte, data []byte) error {
+	if typ != sftpStatus {
+		return fmt.Errorf("unexpected sftp packet %d, expected a status", typ)
+	}
+	return statusError(data)
+}
+
+// isEOF reports whether err is the end-of-file status
+func isEOF(err error) bool {
+	var statusErr *sftpStatusError
+	return errors.As(err, &statusErr) && statusErr.Code == sftpStatusEOF
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AlertRepository defines the interface for alert rule and event persistence
+type AlertRepository interface {
+	CreateRule(ctx context.Context, rule *model.AlertRule) error
+	UpdateRule(ctx context.Context, rule *model.AlertRule) error
+	MarkRuleTriggered(ctx context.Context, id uint64, at time.Time) error
+	DeleteRule(ctx context.Context, id uint64) error
+	FindRuleByID(ctx context.Context, id uint64) (*model.AlertRule, error)
+	FindRules(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.AlertRule, int64, error)
+	FindEnabledRulesBySymbol(ctx context.Context, symbol string) ([]model.AlertRule, error)
+	CreateEvent(ctx context.Context, event *model.AlertEvent) (bool, error)
+	UpdateEvent(ctx context.Context, event *model.AlertEvent) error
+	FindEvents(ctx context.Context, ruleID uint64, filters map[string]interface{}, limit, offset int) ([]model.AlertEvent, int64, error)
+}
+
+// alertRepository implements AlertRepository interface
+type alertRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewAlertRepository creates a new alert repository instance
+func NewAlertRepository(db *gorm.DB, res *database.Resilience) AlertRepository {
+	return &alertRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// CreateRule stores a new alert rule
+func (r *alertRepository) CreateRule(ctx context.Context, rule *model.AlertRule) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		rule.ID = 0
+		return r.db.WithContext(ctx).Create(rule).Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+	return nil
+}
+
+// UpdateRule saves an existing alert rule
+func (r *alertRepository) UpdateRule(ctx context.Context, rule *model.AlertRule) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(rule).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update alert rule: %w", err)
+	}
+	return nil
+}
+
+// MarkRuleTriggered stamps the last time a rule fired without touching its settings
+func (r *alertRepository) MarkRuleTriggered(ctx context.Context, id uint64, at time.Time) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.AlertRule{}).Where("id = ?", id).Update("last_triggered_at", at).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update alert rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule deletes an alert rule and, through the foreign key, its events
+func (r *alertRepository) DeleteRule(ctx context.Context, id uint64) error {
+	start := time.Now()
+	var rowsAffected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Delete(&model.AlertRule{}, id)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("delete", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+	if rowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindRuleByID retrieves an alert rule by ID, returning nil when not found
+func (r *alertRepository) FindRuleByID(ctx context.Context, id uint64) (*model.AlertRule, error) {
+	start := time.Now()
+	var rule model.AlertRule
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&rule, id).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find alert rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// FindRules retrieves alert rules matching the filters, newest first
+func (r *alertRepository) FindRules(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.AlertRule, int64, error) {
+	var rules []model.AlertRule
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.AlertRule{})
+		for _, column := range []string{"tenant", "symbol"} {
+			if value, ok := filters[column].(string); ok && value != "" {
+				query = query.Where(column+" = ?", value)
+			}
+		}
+		if enabled, ok := filters["enabled"].(bool); ok {
+			query = query.Where("enabled = ?", enabled)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count alert rules: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&rules).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find alert rules: %w", err)
+	}
+
+	return rules, total, nil
+}
+
+// FindEnabledRulesBySymbol retrieves the enabled alert rules of a symbol
+func (r *alertRepository) FindEnabledRulesBySymbol(ctx context.Context, symbol string) ([]model.AlertRule, error) {
+	start := time.Now()
+	var rules []model.AlertRule
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("symbol = ? AND enabled = ?", symbol, true).Order("id ASC").Find(&rules).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateEvent stores an alert event unless the rule already fired on the same
+// date, and reports whether the event was created
+func (r *alertRepository) CreateEvent(ctx context.Context, event *model.AlertEvent) (bool, error) {
+	start := time.Now()
+	var rowsAffected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		event.ID = 0
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to create alert event: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// UpdateEvent saves an existing alert event
+func (r *alertRepository) UpdateEvent(ctx context.Context, event *model.AlertEvent) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(event).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update alert event: %w", err)
+	}
+	return nil
+}
+
+// FindEvents retrieves the events of an alert rule matching the filters, newest first
+func (r *alertRepository) FindEvents(ctx context.Context, ruleID uint64, filters map[string]interface{}, limit, offset int) ([]model.AlertEvent, int64, error) {
+	var events []model.AlertEvent
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.AlertEvent{}).Where("rule_id = ?", ruleID)
+		if status, ok := filters["status"].(string); ok && status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count alert events: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("date DESC, id DESC").Find(&events).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find alert events: %w", err)
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// AuditRepository defines the interface for audit log persistence
+type AuditRepository interface {
+	Create(ctx context.Context, entry *model.AuditLog) error
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.AuditLog, int64, error)
+}
+
+// auditRepository implements AuditRepository interface
+type auditRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewAuditRepository creates a new audit repository instance
+func NewAuditRepository(db *gorm.DB, res *database.Resilience) AuditRepository {
+	return &auditRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Create stores a new audit entry
+func (r *auditRepository) Create(ctx context.Context, entry *model.AuditLog) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Create(entry).Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+	return nil
+}
+
+// FindAll retrieves audit entries matching the filters, newest first
+func (r *auditRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.AuditLog, int64, error) {
+	var entries []model.AuditLog
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		return r.applyFilters(r.db.WithContext(ctx).Model(&model.AuditLog{}), filters)
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&entries).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find audit logs: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// applyFilters applies filters to the query
+func (r *auditRepository) applyFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	for _, column := range []string{"tenant", "api_key", "method", "outcome", "request_id"} {
+		if value, ok := filters[column].(string); ok && value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+	if symbol, ok := filters["symbol"].(string); ok && symbol != "" {
+		query = query.Where("FIND_IN_SET(?, symbols) > 0", symbol)
+	}
+	if resourceID, ok := filters["resource_id"].(string); ok && resourceID != "" {
+		query = query.Where("FIND_IN_SET(?, resource_ids) > 0", resourceID)
+	}
+	if startTime, ok := filters["start_time"].(time.Time); ok && !startTime.IsZero() {
+		query = query.Where("created_at >= ?", startTime)
+	}
+	if endTime, ok := filters["end_time"].(time.Time); ok && !endTime.IsZero() {
+		query = query.Where("created_at <= ?", endTime)
+	}
+	return query
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// BackfillRepository defines the interface for backfill and chunk persistence
+type BackfillRepository interface {
+	Create(ctx context.Context, backfill *model.Backfill, chunks []model.BackfillChunk) error
+	Update(ctx context.Context, backfill *model.Backfill) error
+	FindByID(ctx context.Context, id uint64) (*model.Backfill, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Backfill, int64, error)
+	FindNextUnfinished(ctx context.Context) (*model.Backfill, error)
+	ResetRunningChunks(ctx context.Context) error
+	FindChunks(ctx context.Context, backfillID uint64, filters map[string]interface{}, limit, offset int) ([]model.BackfillChunk, int64, error)
+	FindPendingChunks(ctx context.Context, backfillID uint64) ([]model.BackfillChunk, error)
+	CountChunksByStatus(ctx context.Context, backfillID uint64) (map[string]int64, error)
+	UpdateChunk(ctx context.Context, chunk *model.BackfillChunk) error
+	FinishChunk(ctx context.Context, chunk *model.BackfillChunk) error
+}
+
+// backfillRepository implements BackfillRepository interface
+type backfillRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewBackfillRepository creates a new backfill repository instance
+func NewBackfillRepository(db *gorm.DB, res *database.Resilience) BackfillRepository {
+	return &backfillRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Create stores a backfill together with its chunks in one transaction
+func (r *backfillRepository) Create(ctx context.Context, backfill *model.Backfill, chunks []model.BackfillChunk) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			backfill.ID = 0
+			if err := tx.Create(backfill).Error; err != nil {
+				return err
+			}
+			for i := range chunks {
+				chunks[i].ID = 0
+				chunks[i].BackfillID = backfill.ID
+			}
+			return tx.CreateInBatches(chunks, 1000).Error
+		})
+	})
+	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create backfill: %w", err)
+	}
+	return nil
+}
+
+// Update saves an existing backfill
+func (r *backfillRepository) Update(ctx context.Context, backfill *model.Backfill) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(backfill).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update backfill: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a backfill by ID, returning nil when not found
+func (r *backfillRepository) FindByID(ctx context.Context, id uint64) (*model.Backfill, error) {
+	start := time.Now()
+	var backfill model.Backfill
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&backfill, id).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find backfill: %w", err)
+	}
+	return &backfill, nil
+}
+
+// FindAll retrieves backfills matching the filters, newest first
+func (r *backfillRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Backfill, int64, error) {
+	var backfills []model.Backfill
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.Backfill{})
+		for _, column := range []string{"tenant", "provider", "status"} {
+			if value, ok := filters[column].(string); ok && value != "" {
+				query = query.Where(column+" = ?", value)
+			}
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count backfills: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&backfills).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find backfills: %w", err)
+	}
+
+	return backfills, total, nil
+}
+
+// FindNextUnfinished retrieves the oldest pending or running backfill, returning
+// nil when there is none. Running backfills are returned so interrupted work resumes.
+func (r *backfillRepository) FindNextUnfinished(ctx context.Context) (*model.Backfill, error) {
+	start := time.Now()
+	var backfills []model.Backfill
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Where("status IN ?", []string{model.BackfillStatusPending, model.BackfillStatusRunning}).
+			Order("id ASC").
+			Limit(1).
+			Find(&backfills).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unfinished backfill: %w", err)
+	}
+	if len(backfills) == 0 {
+		return nil, nil
+	}
+	return &backfills[0], nil
+}
+
+// ResetRunningChunks returns chunks left running by an interrupted process to pending
+func (r *backfillRepository) ResetRunningChunks(ctx context.Context) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.BackfillChunk{}).
+			Where("status = ?", model.BackfillStatusRunning).
+			Update("status", model.BackfillStatusPending).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to reset running backfill chunks: %w", err)
+	}
+	return nil
+}
+
+// FindChunks retrieves the chunks of a backfill matching the filters
+func (r *backfillRepository) FindChunks(ctx context.Context, backfillID uint64, filters map[string]interface{}, limit, offset int) ([]model.BackfillChunk, int64, error) {
+	var chunks []model.BackfillChunk
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.BackfillChunk{}).Where("backfill_id = ?", backfillID)
+		for _, column := range []string{"status", "symbol"} {
+			if value, ok := filters[column].(string); ok && value != "" {
+				query = query.Where(column+" = ?", value)
+			}
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count backfill chunks: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("id ASC").Find(&chunks).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find backfill chunks: %w", err)
+	}
+
+	return chunks, total, nil
+}
+
+// FindPendingChunks retrieves all pending chunks of a backfill in creation order
+func (r *backfillRepository) FindPendingChunks(ctx context.Context, backfillID uint64) ([]model.BackfillChunk, error) {
+	start := time.Now()
+	var chunks []model.BackfillChunk
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Where("backfill_id = ? AND status = ?", backfillID, model.BackfillStatusPending).
+			Order("id ASC").
+			Find(&chunks).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending backfill chunks: %w", err)
+	}
+	return chunks, nil
+}
+
+// CountChunksByStatus counts the chunks of a backfill per status
+func (r *backfillRepository) CountChunksByStatus(ctx context.Context, backfillID uint64) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.BackfillChunk{}).
+			Select("status, COUNT(*) AS count").
+			Where("backfill_id = ?", backfillID).
+			Group("status").
+			Scan(&rows).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count backfill chunks: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// UpdateChunk saves an existing chunk
+func (r *backfillRepository) UpdateChunk(ctx context.Context, chunk *model.BackfillChunk) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(chunk).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update backfill chunk: %w", err)
+	}
+	return nil
+}
+
+// FinishChunk saves a completed or failed chunk and adds its outcome to the
+// backfill's progress counters in one transaction
+func (r *backfillRepository) FinishChunk(ctx context.Context, chunk *model.BackfillChunk) error {
+	var completed, failed int
+	switch chunk.Status {
+	case model.BackfillStatusCompleted:
+		completed = 1
+	case model.BackfillStatusFailed:
+		failed = 1
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(chunk).Error; err != nil {
+				return err
+			}
+			return tx.Model(&model.Backfill{}).Where("id = ?", chunk.BackfillID).Updates(map[string]interface{}{
+				"completed_chunks": gorm.Expr("completed_chunks + ?", completed),
+				"failed_chunks":    gorm.Expr("failed_chunks + ?", failed),
+				"rows_ingested":    gorm.Expr("rows_ingested + ?", chunk.RowsIngested),
+			}).Error
+		})
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to finish backfill chunk: %w", err)
+	}
+	return nil
+}
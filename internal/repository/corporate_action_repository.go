@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CorporateActionRepository defines the interface for corporate action persistence
+type CorporateActionRepository interface {
+	Upsert(ctx context.Context, action *model.CorporateAction) error
+	FindByID(ctx context.Context, id uint64) (*model.CorporateAction, error)
+	FindBySymbols(ctx context.Context, symbols []string) ([]model.CorporateAction, error)
+	Delete(ctx context.Context, id uint64) error
+}
+
+// corporateActionRepository implements CorporateActionRepository interface
+type corporateActionRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewCorporateActionRepository creates a new corporate action repository instance
+func NewCorporateActionRepository(db *gorm.DB, res *database.Resilience) CorporateActionRepository {
+	return &corporateActionRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Upsert creates the action or replaces the ratio and amount of the action of
+// the same symbol, type and ex-date
+func (r *corporateActionRepository) Upsert(ctx context.Context, action *model.CorporateAction) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}, {Name: "type"}, {Name: "ex_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"ratio", "amount", "updated_at"}),
+		}).Create(action).Error
+		if err != nil {
+			return err
+		}
+		// The insert ID is not reported back on update, reload the stored row
+		return r.db.WithContext(ctx).
+			Where("symbol = ? AND type = ? AND ex_date = ?", action.Symbol, action.Type, action.ExDate).
+			First(action).Error
+	})
+	middleware.RecordDBMetrics("upsert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert corporate action: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a corporate action by ID, returning nil when not found
+func (r *corporateActionRepository) FindByID(ctx context.Context, id uint64) (*model.CorporateAction, error) {
+	start := time.Now()
+	var action model.CorporateAction
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&action, id).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find corporate action: %w", err)
+	}
+	return &action, nil
+}
+
+// FindBySymbols retrieves the corporate actions of the given symbols ordered by ex-date
+func (r *corporateActionRepository) FindBySymbols(ctx context.Context, symbols []string) ([]model.CorporateAction, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	start := time.Now()
+	var actions []model.CorporateAction
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("symbol IN ?", symbols).Order("ex_date ASC, id ASC").Find(&actions).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find corporate actions: %w", err)
+	}
+	return actions, nil
+}
+
+// Delete deletes a corporate action by ID
+func (r *corporateActionRepository) Delete(ctx context.Context, id uint64) error {
+	start := time.Now()
+	var rowsAffected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Delete(&model.CorporateAction{}, id)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("delete", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete corporate action: %w", err)
+	}
+	if rowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
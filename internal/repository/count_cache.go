@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedCounts bounds the number of filter signatures kept by a countCache
+const maxCachedCounts = 10000
+
+// countCache keeps exact counts per filter signature for a short TTL, so
+// paging through a broad query doesn't re-count the same rows on every page
+type countCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cachedCount
+}
+
+// cachedCount is a count and the time it stops being served
+type cachedCount struct {
+	total   int64
+	expires time.Time
+}
+
+// newCountCache creates a count cache; a non-positive ttl disables it
+func newCountCache(ttl time.Duration) *countCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &countCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedCount),
+	}
+}
+
+// get returns the count cached for key, if any
+func (c *countCache) get(key string) (int64, bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return 0, false
+	}
+	return entry.total, true
+}
+
+// set caches a count for key, dropping expired entries once the cache is full
+func (c *countCache) set(key string, total int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= maxCachedCounts {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCachedCounts {
+			return
+		}
+	}
+	c.entries[key] = cachedCount{total: total, expires: now.Add(c.ttl)}
+}
+
+// filterSignature builds a cache key identifying a set of filters
+func filterSignature(filters map[string]interface{}) string {
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		value := filters[k]
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC().Format(time.RFC3339Nano)
+		}
+		fmt.Fprintf(&b, "%s=%v;", k, value)
+	}
+	return b.String()
+}
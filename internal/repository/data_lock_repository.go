@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// DataLockRepository defines the interface for data lock persistence
+type DataLockRepository interface {
+	Create(ctx context.Context, lock *model.DataLock) error
+	Delete(ctx context.Context, id uint64) error
+	FindByID(ctx context.Context, id uint64) (*model.DataLock, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.DataLock, int64, error)
+	FindAllLocks(ctx context.Context) ([]model.DataLock, error)
+}
+
+// dataLockRepository implements DataLockRepository interface
+type dataLockRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewDataLockRepository creates a new data lock repository instance
+func NewDataLockRepository(db *gorm.DB, res *database.Resilience) DataLockRepository {
+	return &dataLockRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Create stores a new data lock
+func (r *dataLockRepository) Create(ctx context.Context, lock *model.DataLock) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		lock.ID = 0
+		return r.db.WithContext(ctx).Create(lock).Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create data lock: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a data lock
+func (r *dataLockRepository) Delete(ctx context.Context, id uint64) error {
+	start := time.Now()
+	var rowsAffected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Delete(&model.DataLock{}, id)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("delete", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete data lock: %w", err)
+	}
+	if rowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves a data lock by ID, returning nil when not found
+func (r *dataLockRepository) FindByID(ctx context.Context, id uint64) (*model.DataLock, error) {
+	start := time.Now()
+	var lock model.DataLock
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&lock, id).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find data lock: %w", err)
+	}
+	return &lock, nil
+}
+
+// FindAll retrieves data locks matching the filters, ordered by symbol and start date
+func (r *dataLockRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.DataLock, int64, error) {
+	var locks []model.DataLock
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.DataLock{})
+		if symbol, ok := filters["symbol"].(string); ok && symbol != "" {
+			query = query.Where("symbol = ?", symbol)
+		}
+		// Locks overlapping the date
+		if date, ok := filters["date"].(time.Time); ok && !date.IsZero() {
+			query = query.Where("start_date <= ? AND end_date >= ?", date, date)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count data locks: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("symbol ASC, start_date ASC, id ASC").Find(&locks).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find data locks: %w", err)
+	}
+
+	return locks, total, nil
+}
+
+// FindAllLocks retrieves every data lock, for checking an upload against them
+func (r *dataLockRepository) FindAllLocks(ctx context.Context) ([]model.DataLock, error) {
+	start := time.Now()
+	var locks []model.DataLock
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Order("symbol ASC, start_date ASC").Find(&locks).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data locks: %w", err)
+	}
+	return locks, nil
+}
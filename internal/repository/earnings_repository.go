@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EarningsRepository defines the interface for earnings event persistence
+type EarningsRepository interface {
+	BulkUpsert(ctx context.Context, events []model.EarningsEvent, batchSize int) error
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.EarningsEvent, int64, error)
+	FindBySymbols(ctx context.Context, symbols []string, start, end time.Time) ([]model.EarningsEvent, error)
+}
+
+// earningsRepository implements EarningsRepository interface
+type earningsRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewEarningsRepository creates a new earnings repository instance
+func NewEarningsRepository(db *gorm.DB, res *database.Resilience) EarningsRepository {
+	return &earningsRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// BulkUpsert stores earnings events in batches. An event replaces the report
+// date of the same symbol and period, which moves when a report is
+// rescheduled, and keeps the stored estimate and actual when it lacks them.
+func (r *earningsRepository) BulkUpsert(ctx context.Context, events []model.EarningsEvent, batchSize int) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	updates := clause.AssignmentColumns([]string{"report_date", "updated_at"})
+	for _, column := range []string{"eps_estimate", "eps_actual"} {
+		updates = append(updates, clause.Assignment{
+			Column: clause.Column{Name: column},
+			Value:  gorm.Expr(fmt.Sprintf("COALESCE(%s, %s)", database.Inserted(r.db, column), column)),
+		})
+	}
+
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}, {Name: "period"}},
+			DoUpdates: updates,
+		}).CreateInBatches(events, batchSize)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert earnings events: %w", err)
+	}
+	recordBatch(r.db, "bulk_insert", "earnings_events", len(events), affected, true)
+	return nil
+}
+
+// FindAll retrieves earnings events matching the filters in calendar order
+func (r *earningsRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.EarningsEvent, int64, error) {
+	var events []model.EarningsEvent
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.EarningsEvent{})
+		if symbol, ok := filters["symbol"].(string); ok && symbol != "" {
+			query = query.Where("symbol = ?", symbol)
+		}
+		if startDate, ok := filters["start_date"].(time.Time); ok && !startDate.IsZero() {
+			query = query.Where("report_date >= ?", startDate)
+		}
+		if endDate, ok := filters["end_date"].(time.Time); ok && !endDate.IsZero() {
+			query = query.Where("report_date <= ?", endDate)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count earnings events: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("report_date ASC, symbol ASC").Find(&events).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find earnings events: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// FindBySymbols retrieves the earnings events of the given symbols reported
+// between start and end, both included
+func (r *earningsRepository) FindBySymbols(ctx context.Context, symbols []string, start, end time.Time) ([]model.EarningsEvent, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	var events []model.EarningsEvent
+	begin := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Where("symbol IN ? AND report_date BETWEEN ? AND ?", symbols, start, end).
+			Order("symbol ASC, report_date ASC").
+			Find(&events).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(begin), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find earnings events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-historical-data/internal/entitlement"
+	"gorm.io/gorm"
+)
+
+// Delayed API keys are entitled to the bars, quotes and ticks dated some days
+// before today and older (see entitlement.Through). The window is applied
+// here, in query construction, so every read path honours it.
+
+// entitledFilters adds the entitlement window of the caller of ctx to the
+// filters, so it restricts the query and keys the count cache
+func entitledFilters(ctx context.Context, filters map[string]interface{}) map[string]interface{} {
+	through, ok := entitlement.Through(ctx)
+	if !ok {
+		return filters
+	}
+	windowed := make(map[string]interface{}, len(filters)+1)
+	for k, v := range filters {
+		windowed[k] = v
+	}
+	windowed["entitled_through"] = through
+	return windowed
+}
+
+// entitled restricts a query to the bars within the entitlement window of the
+// caller of ctx
+func entitled(ctx context.Context, query *gorm.DB) *gorm.DB {
+	if through, ok := entitlement.Through(ctx); ok {
+		return query.Where("date <= ?", through)
+	}
+	return query
+}
+
+// entitledTo caps the end of a tick range to the end of the last day within
+// the entitlement window of the caller of ctx
+func entitledTo(ctx context.Context, to time.Time) time.Time {
+	if through, ok := entitlement.Through(ctx); ok {
+		if end := through.AddDate(0, 0, 1); to.After(end) {
+			return end
+		}
+	}
+	return to
+}
@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/go-historical-data/pkg/database"
+
+// Typed errors of repository calls, matched with errors.Is through the wraps
+// of the services. Every call through the resilience wrapper is classified.
+var (
+	// ErrDuplicate is a write conflicting with a stored unique key
+	ErrDuplicate = database.ErrDuplicate
+	// ErrConstraint is a write violating a constraint of the schema
+	ErrConstraint = database.ErrConstraint
+	// ErrUnavailable is a call the database could not serve; retrying later may succeed
+	ErrUnavailable = database.ErrUnavailable
+)
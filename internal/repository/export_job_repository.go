@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ExportJobRepository defines the interface for export job persistence
+type ExportJobRepository interface {
+	Create(ctx context.Context, job *model.ExportJob) error
+	Update(ctx context.Context, job *model.ExportJob) error
+	FindByID(ctx context.Context, id uint64) (*model.ExportJob, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.ExportJob, int64, error)
+	FindNextUnfinished(ctx context.Context) (*model.ExportJob, error)
+	FindExpired(ctx context.Context, now time.Time) ([]model.ExportJob, error)
+}
+
+// exportJobRepository implements ExportJobRepository interface
+type exportJobRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewExportJobRepository creates a new export job repository instance
+func NewExportJobRepository(db *gorm.DB, res *database.Resilience) ExportJobRepository {
+	return &exportJobRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Create stores a new export job
+func (r *exportJobRepository) Create(ctx context.Context, job *model.ExportJob) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		job.ID = 0
+		return r.db.WithContext(ctx).Create(job).Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create export job: %w", err)
+	}
+	return nil
+}
+
+// Update saves an existing export job
+func (r *exportJobRepository) Update(ctx context.Context, job *model.ExportJob) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(job).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update export job: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves an export job by ID, returning nil when not found
+func (r *exportJobRepository) FindByID(ctx context.Context, id uint64) (*model.ExportJob, error) {
+	start := time.Now()
+	var job model.ExportJob
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&job, id).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find export job: %w", err)
+	}
+	return &job, nil
+}
+
+// FindAll retrieves export jobs matching the filters, newest first
+func (r *exportJobRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.ExportJob, int64, error) {
+	var jobs []model.ExportJob
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.ExportJob{})
+		for _, column := range []string{"tenant", "status"} {
+			if value, ok := filters[column].(string); ok && value != "" {
+				query = query.Where(column+" = ?", value)
+			}
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count export jobs: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&jobs).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find export jobs: %w", err)
+	}
+
+	return jobs, total, nil
+}
+
+// FindNextUnfinished retrieves the oldest pending or running export job,
+// returning nil when there is none. Running jobs are returned so an export
+// interrupted by a restart is produced again.
+func (r *exportJobRepository) FindNextUnfinished(ctx context.Context) (*model.ExportJob, error) {
+	start := time.Now()
+	var jobs []model.ExportJob
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Where("status IN ?", []string{model.ExportStatusPending, model.ExportStatusRunning}).
+			Order("id ASC").
+			Limit(1).
+			Find(&jobs).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unfinished export job: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// FindExpired retrieves completed export jobs whose artifact expired at now
+func (r *exportJobRepository) FindExpired(ctx context.Context, now time.Time) ([]model.ExportJob, error) {
+	start := time.Now()
+	var jobs []model.ExportJob
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Where("status = ? AND expires_at <= ?", model.ExportStatusCompleted, now).
+			Order("id ASC").
+			Find(&jobs).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired export jobs: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FundamentalRepository defines the interface for fundamentals persistence
+type FundamentalRepository interface {
+	BulkUpsert(ctx context.Context, fundamentals []model.Fundamental, batchSize int) error
+	FindBySymbol(ctx context.Context, symbol string, filters map[string]interface{}, limit int) ([]model.Fundamental, error)
+}
+
+// fundamentalRepository implements FundamentalRepository interface
+type fundamentalRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewFundamentalRepository creates a new fundamental repository instance
+func NewFundamentalRepository(db *gorm.DB, res *database.Resilience) FundamentalRepository {
+	return &fundamentalRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// BulkUpsert stores fundamentals in batches, replacing the figures of the same
+// symbol and period. Stored figures are kept when the new row lacks them.
+func (r *fundamentalRepository) BulkUpsert(ctx context.Context, fundamentals []model.Fundamental, batchSize int) error {
+	if len(fundamentals) == 0 {
+		return nil
+	}
+
+	updates := clause.AssignmentColumns([]string{"period_end", "source_id", "updated_at"})
+	for _, column := range []string{"eps", "revenue", "net_income", "shares_outstanding"} {
+		updates = append(updates, clause.Assignment{
+			Column: clause.Column{Name: column},
+			Value:  gorm.Expr(fmt.Sprintf("COALESCE(%s, %s)", database.Inserted(r.db, column), column)),
+		})
+	}
+
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}, {Name: "period"}},
+			DoUpdates: updates,
+		}).CreateInBatches(fundamentals, batchSize)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert fundamentals: %w", err)
+	}
+	recordBatch(r.db, "bulk_insert", "fundamentals", len(fundamentals), affected, true)
+	return nil
+}
+
+// FindBySymbol retrieves up to limit periods of a symbol, latest period end
+// first. Filters: period_type (quarter or annual) and as_of, the latest
+// period end included.
+func (r *fundamentalRepository) FindBySymbol(ctx context.Context, symbol string, filters map[string]interface{}, limit int) ([]model.Fundamental, error) {
+	var fundamentals []model.Fundamental
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		query := r.db.WithContext(ctx).Where("symbol = ?", symbol)
+		switch filters["period_type"] {
+		case "quarter":
+			query = query.Where("period NOT LIKE ?", "%FY")
+		case "annual":
+			query = query.Where("period LIKE ?", "%FY")
+		}
+		if asOf, ok := filters["as_of"].(time.Time); ok && !asOf.IsZero() {
+			query = query.Where("period_end <= ?", asOf)
+		}
+		return query.Order("period_end DESC, period ASC").Limit(limit).Find(&fundamentals).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fundamentals: %w", err)
+	}
+	return fundamentals, nil
+}
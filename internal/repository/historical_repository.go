@@ -2,11 +2,18 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"iter"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/go-historical-data/internal/entitlement"
 	"github.com/go-historical-data/internal/middleware"
 	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"github.com/shopspring/decimal"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -18,30 +25,104 @@ import (
 type HistoricalRepository interface {
 	Create(ctx context.Context, data *model.HistoricalData) error
 	BulkCreate(ctx context.Context, data []model.HistoricalData, batchSize int) error
+	BulkCreateWithSymbols(ctx context.Context, data []model.HistoricalData, batchSize int, symbols []model.Symbol) error
 	FindBySymbol(ctx context.Context, symbol string, startDate, endDate time.Time) ([]model.HistoricalData, error)
-	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.HistoricalData, int64, error)
+	FindBySymbolStream(ctx context.Context, symbol string, startDate, endDate time.Time) iter.Seq2[model.HistoricalData, error]
+	FindLatestBySymbols(ctx context.Context, symbols []string, count int) ([]model.HistoricalData, error)
+	FindByKeys(ctx context.Context, keys []BarKey) ([]model.HistoricalData, error)
+	FindAsOf(ctx context.Context, keys []BarKey) ([]*model.HistoricalData, error)
+	FindMovers(ctx context.Context, date time.Time, filters map[string]interface{}, gainers bool, limit int) ([]Mover, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int, opts QueryOptions) ([]model.HistoricalData, int64, error)
 	FindByID(ctx context.Context, id uint64) (*model.HistoricalData, error)
 	Update(ctx context.Context, data *model.HistoricalData) error
 	Delete(ctx context.Context, id uint64) error
 	Count(ctx context.Context, filters map[string]interface{}) (int64, error)
+	ListSymbols(ctx context.Context) ([]string, error)
+	GetDateBounds(ctx context.Context, symbol string) (*DateBounds, error)
+	CountBySymbol(ctx context.Context, symbols []string) (map[string]int64, error)
+}
+
+// DateBounds describes the dates covered by a symbol's bars
+type DateBounds struct {
+	FirstDate time.Time
+	LastDate  time.Time
+	Count     int64
+}
+
+// BarKey identifies a bar by its natural key
+type BarKey struct {
+	Symbol string
+	Date   time.Time
+}
+
+// barKeyChunkSize is the number of keys per IN-tuple query of FindByKeys,
+// keeping statements well under the placeholder limits
+const barKeyChunkSize = 500
+
+// asOfChunkSize is the number of keys per UNION ALL query of FindAsOf,
+// within SQLite's limit of 500 compound SELECT terms
+const asOfChunkSize = 250
+
+// QueryOptions holds optional query shaping parameters for list queries
+type QueryOptions struct {
+	// Fields restricts the selected columns; empty selects all columns
+	Fields []string
+	// SortBy is the primary sort column (date, symbol, volume, close); defaults to date
+	SortBy string
+	// SortDir is the sort direction (asc or desc); defaults to desc
+	SortDir string
+	// Count selects how FindAll computes the total; defaults to CountExact
+	Count string
+}
+
+// Count modes of FindAll
+const (
+	// CountExact runs COUNT(*), reusing a cached count for the same filters when enabled
+	CountExact = "exact"
+	// CountApproximate estimates the total from table statistics (unfiltered
+	// queries) or the optimizer's row estimate (filtered queries)
+	CountApproximate = "approximate"
+	// CountNone skips the count. One row past the page is read instead, and the
+	// total returned is the offset plus the rows found, so it exceeds
+	// offset+limit exactly when a next page exists.
+	CountNone = "none"
+)
+
+// sortableColumns lists the columns allowed in ORDER BY clauses
+var sortableColumns = map[string]bool{
+	"date":   true,
+	"symbol": true,
+	"volume": true,
+	"close":  true,
 }
 
 // historicalRepository implements HistoricalRepository interface
 type historicalRepository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	res     *database.Resilience
+	session database.WriteSession
+	counts  *countCache
 }
 
-// NewHistoricalRepository creates a new historical repository instance
-func NewHistoricalRepository(db *gorm.DB) HistoricalRepository {
+// NewHistoricalRepository creates a new historical repository instance. Calls go
+// through res (circuit breaker and transient-error retries); a nil res disables it.
+// Bulk writes run in transactions of session. Exact counts of FindAll are
+// reused for countCacheTTL; zero disables caching.
+func NewHistoricalRepository(db *gorm.DB, res *database.Resilience, session database.WriteSession, countCacheTTL time.Duration) HistoricalRepository {
 	return &historicalRepository{
-		db: db,
+		db:      db,
+		res:     res,
+		session: session,
+		counts:  newCountCache(countCacheTTL),
 	}
 }
 
 // Create creates a new historical data record
 func (r *historicalRepository) Create(ctx context.Context, data *model.HistoricalData) error {
 	start := time.Now()
-	err := r.db.WithContext(ctx).Create(data).Error
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Create(data).Error
+	})
 	middleware.RecordDBMetrics("insert", time.Since(start), err)
 
 	if err != nil {
@@ -68,14 +149,19 @@ func (r *historicalRepository) BulkCreate(ctx context.Context, data []model.Hist
 	// Track database operation time
 	start := time.Now()
 
-	// Use batch insert with conflict handling (upsert)
-	// If duplicate symbol+date exists, update the record
-	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "symbol"}, {Name: "date"}},
-		DoUpdates: clause.AssignmentColumns([]string{
-			"open", "high", "low", "close", "volume", "updated_at",
-		}),
-	}).CreateInBatches(data, batchSize).Error
+	// Use batch insert with conflict handling (upsert), in key order
+	sorted := sortedBars(data)
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		// A retry inserts fresh copies rather than rows carrying the IDs of
+		// a rolled back attempt
+		bars := slices.Clone(sorted)
+		return r.session.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+			result := tx.Clauses(r.barUpsert()).CreateInBatches(bars, batchSize)
+			affected = result.RowsAffected
+			return result.Error
+		})
+	})
 
 	// Record metrics
 	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
@@ -85,36 +171,340 @@ func (r *historicalRepository) BulkCreate(ctx context.Context, data []model.Hist
 		span.SetStatus(codes.Error, "bulk insert failed")
 		return fmt.Errorf("failed to bulk create historical data: %w", err)
 	}
+	recordBatch(r.db, "bulk_insert", "historical_data", len(data), affected, true)
+
+	span.SetStatus(codes.Ok, "bulk insert successful")
+	return nil
+}
+
+// BulkCreateWithSymbols upserts bars like BulkCreate and registers the
+// metadata of symbols in the same transaction, so neither is stored without
+// the other. Symbols already registered keep their metadata.
+func (r *historicalRepository) BulkCreateWithSymbols(ctx context.Context, data []model.HistoricalData, batchSize int, symbols []model.Symbol) error {
+	if len(symbols) == 0 {
+		return r.BulkCreate(ctx, data, batchSize)
+	}
+
+	tracer := otel.Tracer("historical-repository")
+	ctx, span := tracer.Start(ctx, "HistoricalRepository.BulkCreateWithSymbols")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("record_count", len(data)),
+		attribute.Int("batch_size", batchSize),
+		attribute.Int("symbol_count", len(symbols)),
+	)
+
+	sorted := sortedBars(data)
+	sortedSymbols := slices.SortedFunc(slices.Values(symbols), func(a, b model.Symbol) int {
+		return strings.Compare(a.Symbol, b.Symbol)
+	})
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		// A retry inserts fresh copies rather than rows carrying the IDs of
+		// a rolled back attempt
+		stubs := slices.Clone(sortedSymbols)
+		bars := slices.Clone(sorted)
+		return r.session.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&stubs).Error; err != nil {
+				return err
+			}
+			result := tx.Clauses(r.barUpsert()).CreateInBatches(bars, batchSize)
+			affected = result.RowsAffected
+			return result.Error
+		})
+	})
+	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "bulk insert failed")
+		return fmt.Errorf("failed to bulk create historical data with symbols: %w", err)
+	}
+	recordBatch(r.db, "bulk_insert", "historical_data", len(data), affected, true)
 
 	span.SetStatus(codes.Ok, "bulk insert successful")
 	return nil
 }
 
+// sortedBars returns a copy of bars ordered by symbol and date. Upserts in
+// key order take their row and gap locks in the same order, so concurrent
+// uploads touching the same symbols wait for each other instead of
+// deadlocking; a deadlock left over is retried by the resilience policy.
+func sortedBars(bars []model.HistoricalData) []model.HistoricalData {
+	return slices.SortedStableFunc(slices.Values(bars), func(a, b model.HistoricalData) int {
+		if c := strings.Compare(a.Symbol, b.Symbol); c != 0 {
+			return c
+		}
+		return a.Date.Compare(b.Date)
+	})
+}
+
+// barUpsert updates the stored bar when a duplicate symbol+date is inserted.
+// Open interest, number of trades and attributes are only replaced when the
+// new row carries them, so re-loading a bar from a source without those
+// columns keeps the stored ones.
+func (r *historicalRepository) barUpsert() clause.OnConflict {
+	updates := clause.AssignmentColumns([]string{
+		"open", "high", "low", "close", "volume", "source_id", "updated_at",
+	})
+	for _, column := range []string{"open_interest", "number_of_trades", "attributes"} {
+		updates = append(updates, clause.Assignment{
+			Column: clause.Column{Name: column},
+			Value:  gorm.Expr(fmt.Sprintf("COALESCE(%s, %s)", database.Inserted(r.db, column), column)),
+		})
+	}
+	return clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "date"}},
+		DoUpdates: updates,
+	}
+}
+
 // FindBySymbol retrieves historical data for a specific symbol within a date range
 func (r *historicalRepository) FindBySymbol(ctx context.Context, symbol string, startDate, endDate time.Time) ([]model.HistoricalData, error) {
 	start := time.Now()
 	var data []model.HistoricalData
-	query := r.db.WithContext(ctx).Where("symbol = ?", symbol)
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		query := entitled(ctx, r.db.WithContext(ctx).Where("symbol = ?", symbol))
 
-	if !startDate.IsZero() {
-		query = query.Where("date >= ?", startDate)
+		if !startDate.IsZero() {
+			query = query.Where("date >= ?", startDate)
+		}
+		if !endDate.IsZero() {
+			query = query.Where("date <= ?", endDate)
+		}
+
+		return query.Order("date ASC").Find(&data).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find historical data by symbol: %w", err)
 	}
-	if !endDate.IsZero() {
-		query = query.Where("date <= ?", endDate)
+
+	return data, nil
+}
+
+// FindBySymbolStream iterates over the historical data of a symbol within a
+// date range in ascending date order, reading rows from the database as the
+// caller consumes them instead of materializing the range. The iteration holds
+// a connection until it ends; stopping early releases it. A failure is yielded
+// once as the last element, and only opening the query goes through the
+// circuit breaker and retries.
+func (r *historicalRepository) FindBySymbolStream(ctx context.Context, symbol string, startDate, endDate time.Time) iter.Seq2[model.HistoricalData, error] {
+	return func(yield func(model.HistoricalData, error) bool) {
+		start := time.Now()
+		var rows *sql.Rows
+		err := r.res.Do(ctx, func(ctx context.Context) error {
+			query := entitled(ctx, r.db.WithContext(ctx).Model(&model.HistoricalData{}).Where("symbol = ?", symbol))
+			if !startDate.IsZero() {
+				query = query.Where("date >= ?", startDate)
+			}
+			if !endDate.IsZero() {
+				query = query.Where("date <= ?", endDate)
+			}
+
+			var err error
+			rows, err = query.Order("date ASC").Rows()
+			return err
+		})
+		if err != nil {
+			middleware.RecordDBMetrics("select", time.Since(start), err)
+			yield(model.HistoricalData{}, fmt.Errorf("failed to stream historical data by symbol: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var row model.HistoricalData
+			if err = r.db.ScanRows(rows, &row); err != nil {
+				break
+			}
+			if !yield(row, nil) {
+				middleware.RecordDBMetrics("select", time.Since(start), nil)
+				return
+			}
+		}
+		if err == nil {
+			err = rows.Err()
+		}
+		middleware.RecordDBMetrics("select", time.Since(start), err)
+		if err != nil {
+			yield(model.HistoricalData{}, fmt.Errorf("failed to stream historical data by symbol: %w", err))
+		}
 	}
+}
 
-	err := query.Order("date ASC").Find(&data).Error
+// FindLatestBySymbols retrieves the latest count bars of each symbol, ordered
+// by symbol and then newest date first
+func (r *historicalRepository) FindLatestBySymbols(ctx context.Context, symbols []string, count int) ([]model.HistoricalData, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	start := time.Now()
+	var data []model.HistoricalData
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		ranked := entitled(ctx, r.db.WithContext(ctx).Model(&model.HistoricalData{}).
+			Select("historical_data.*, ROW_NUMBER() OVER (PARTITION BY symbol ORDER BY date DESC) AS row_rank").
+			Where("symbol IN ?", symbols))
+		return r.db.WithContext(ctx).Table("(?) AS ranked", ranked).
+			Where("row_rank <= ?", count).
+			Order("symbol ASC, date DESC").
+			Find(&data).Error
+	})
 	middleware.RecordDBMetrics("select", time.Since(start), err)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to find historical data by symbol: %w", err)
+		return nil, fmt.Errorf("failed to find latest historical data: %w", err)
 	}
 
 	return data, nil
 }
 
+// FindByKeys retrieves the bars of the given (symbol, date) keys with one
+// IN-tuple query per chunk of barKeyChunkSize keys, each served by
+// unique_symbol_date. Keys without a bar are absent from the result.
+func (r *historicalRepository) FindByKeys(ctx context.Context, keys []BarKey) ([]model.HistoricalData, error) {
+	tracer := otel.Tracer("historical-repository")
+	ctx, span := tracer.Start(ctx, "HistoricalRepository.FindByKeys")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("key_count", len(keys)))
+
+	data := make([]model.HistoricalData, 0, len(keys))
+	for chunk := range slices.Chunk(keys, barKeyChunkSize) {
+		tuples := make([][]interface{}, len(chunk))
+		for i, key := range chunk {
+			tuples[i] = []interface{}{key.Symbol, key.Date}
+		}
+
+		var rows []model.HistoricalData
+		start := time.Now()
+		err := r.res.Do(ctx, func(ctx context.Context) error {
+			return entitled(ctx, r.db.WithContext(ctx)).Where("(symbol, date) IN ?", tuples).Find(&rows).Error
+		})
+		middleware.RecordDBMetrics("select", time.Since(start), err)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "select query failed")
+			return nil, fmt.Errorf("failed to find historical data by keys: %w", err)
+		}
+		data = append(data, rows...)
+	}
+
+	span.SetAttributes(attribute.Int("returned_count", len(data)))
+	return data, nil
+}
+
+// FindAsOf retrieves for each key the last bar of its symbol on or before its
+// date, nil when there is none, aligned with keys. Each key is a backward
+// seek on unique_symbol_date; a chunk of asOfChunkSize keys is one UNION ALL
+// query.
+func (r *historicalRepository) FindAsOf(ctx context.Context, keys []BarKey) ([]*model.HistoricalData, error) {
+	tracer := otel.Tracer("historical-repository")
+	ctx, span := tracer.Start(ctx, "HistoricalRepository.FindAsOf")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("key_count", len(keys)))
+
+	through, windowed := entitlement.Through(ctx)
+	bars := make([]*model.HistoricalData, len(keys))
+	table := model.HistoricalData{}.TableName()
+	for offset := 0; offset < len(keys); offset += asOfChunkSize {
+		chunk := keys[offset:min(offset+asOfChunkSize, len(keys))]
+
+		terms := make([]string, len(chunk))
+		args := make([]interface{}, 0, 3*len(chunk))
+		for i, key := range chunk {
+			date := key.Date
+			if windowed && date.After(through) {
+				date = through
+			}
+			terms[i] = fmt.Sprintf("SELECT ? AS lookup, t.* FROM (SELECT * FROM %s WHERE symbol = ? AND date <= ? ORDER BY date DESC LIMIT 1) t", table)
+			args = append(args, offset+i, key.Symbol, date)
+		}
+
+		var rows []struct {
+			Lookup int
+			model.HistoricalData
+		}
+		start := time.Now()
+		err := r.res.Do(ctx, func(ctx context.Context) error {
+			return r.db.WithContext(ctx).Raw(strings.Join(terms, " UNION ALL "), args...).Scan(&rows).Error
+		})
+		middleware.RecordDBMetrics("select", time.Since(start), err)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "select query failed")
+			return nil, fmt.Errorf("failed to find historical data as of dates: %w", err)
+		}
+		for i := range rows {
+			bar := rows[i].HistoricalData
+			bars[rows[i].Lookup] = &bar
+		}
+	}
+
+	return bars, nil
+}
+
+// Mover is the close of a symbol on a date and the close of its previous bar
+type Mover struct {
+	Symbol        string
+	Close         decimal.Decimal
+	PreviousClose decimal.Decimal
+}
+
+// FindMovers retrieves up to limit symbols with a bar on date, within the
+// filters, by the largest relative change from their previous close: rising
+// ones when gainers, falling ones otherwise. The previous close is one
+// backward seek on unique_symbol_date per bar of the date.
+func (r *historicalRepository) FindMovers(ctx context.Context, date time.Time, filters map[string]interface{}, gainers bool, limit int) ([]Mover, error) {
+	tracer := otel.Tracer("historical-repository")
+	ctx, span := tracer.Start(ctx, "HistoricalRepository.FindMovers")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("date", date.Format("2006-01-02")),
+		attribute.Bool("gainers", gainers),
+		attribute.Int("limit", limit),
+	)
+
+	filters = entitledFilters(ctx, filters)
+	table := model.HistoricalData{}.TableName()
+	direction, order := "close > previous_close", "close / previous_close DESC, symbol ASC"
+	if !gainers {
+		direction, order = "close < previous_close", "close / previous_close ASC, symbol ASC"
+	}
+
+	var movers []Mover
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		day := r.applyFilters(r.db.WithContext(ctx).Model(&model.HistoricalData{}), filters).
+			Where(table+".date = ?", date).
+			Select(fmt.Sprintf(`%[1]s.symbol, %[1]s.close,
+				(SELECT p.close FROM %[1]s p WHERE p.symbol = %[1]s.symbol AND p.date < %[1]s.date ORDER BY p.date DESC LIMIT 1) AS previous_close`, table))
+		return r.db.WithContext(ctx).Table("(?) day", day).
+			Where("previous_close > 0 AND " + direction).
+			Order(order).
+			Limit(limit).
+			Scan(&movers).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "select query failed")
+		return nil, fmt.Errorf("failed to find movers: %w", err)
+	}
+	return movers, nil
+}
+
 // FindAll retrieves all historical data with optional filters and pagination
-func (r *historicalRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.HistoricalData, int64, error) {
+func (r *historicalRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int, opts QueryOptions) ([]model.HistoricalData, int64, error) {
 	tracer := otel.Tracer("historical-repository")
 	ctx, span := tracer.Start(ctx, "HistoricalRepository.FindAll")
 	defer span.End()
@@ -124,28 +514,57 @@ func (r *historicalRepository) FindAll(ctx context.Context, filters map[string]i
 		attribute.Int("offset", offset),
 	)
 
+	filters = entitledFilters(ctx, filters)
 	var data []model.HistoricalData
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&model.HistoricalData{})
-
-	// Apply filters
-	query = r.applyFilters(query, filters)
+	newQuery := func(ctx context.Context) *gorm.DB {
+		return r.applyFilters(r.db.WithContext(ctx).Model(&model.HistoricalData{}), filters)
+	}
 
 	// Count total records
-	start := time.Now()
-	countErr := query.Count(&total).Error
-	middleware.RecordDBMetrics("select", time.Since(start), countErr)
-
+	var countErr error
+	switch opts.Count {
+	case CountNone:
+	case CountApproximate:
+		total, countErr = r.estimateCount(ctx, filters)
+	default:
+		total, countErr = r.exactCount(ctx, filters)
+	}
 	if countErr != nil {
 		span.RecordError(countErr)
 		span.SetStatus(codes.Error, "count query failed")
 		return nil, 0, fmt.Errorf("failed to count historical data: %w", countErr)
 	}
 
+	span.SetAttributes(
+		attribute.String("count", opts.Count),
+		attribute.String("sort_by", opts.SortBy),
+		attribute.String("sort_dir", opts.SortDir),
+	)
+
+	// Without a count, one more row tells whether a next page exists
+	fetch := limit
+	if opts.Count == CountNone {
+		fetch = limit + 1
+	}
+
+	if len(opts.Fields) > 0 {
+		span.SetAttributes(attribute.StringSlice("fields", opts.Fields))
+	}
+
 	// Apply pagination and fetch data
-	start = time.Now()
-	findErr := query.Limit(limit).Offset(offset).Order("date DESC").Find(&data).Error
+	start := time.Now()
+	findErr := r.res.Do(ctx, func(ctx context.Context) error {
+		query := newQuery(ctx)
+
+		// Project down to the requested columns
+		if len(opts.Fields) > 0 {
+			query = query.Select(opts.Fields)
+		}
+
+		return query.Limit(fetch).Offset(offset).Order(r.buildOrder(filters, opts)).Find(&data).Error
+	})
 	middleware.RecordDBMetrics("select", time.Since(start), findErr)
 
 	if findErr != nil {
@@ -154,6 +573,13 @@ func (r *historicalRepository) FindAll(ctx context.Context, filters map[string]i
 		return nil, 0, fmt.Errorf("failed to find all historical data: %w", findErr)
 	}
 
+	if opts.Count == CountNone {
+		total = int64(offset + len(data))
+		if len(data) > limit {
+			data = data[:limit]
+		}
+	}
+
 	span.SetAttributes(
 		attribute.Int64("total_count", total),
 		attribute.Int("returned_count", len(data)),
@@ -172,7 +598,9 @@ func (r *historicalRepository) FindByID(ctx context.Context, id uint64) (*model.
 
 	start := time.Now()
 	var data model.HistoricalData
-	err := r.db.WithContext(ctx).First(&data, id).Error
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return entitled(ctx, r.db.WithContext(ctx)).First(&data, id).Error
+	})
 	middleware.RecordDBMetrics("select", time.Since(start), err)
 
 	if err != nil {
@@ -191,7 +619,10 @@ func (r *historicalRepository) FindByID(ctx context.Context, id uint64) (*model.
 
 // Update updates an existing historical data record
 func (r *historicalRepository) Update(ctx context.Context, data *model.HistoricalData) error {
-	if err := r.db.WithContext(ctx).Save(data).Error; err != nil {
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(data).Error
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update historical data: %w", err)
 	}
 	return nil
@@ -199,11 +630,16 @@ func (r *historicalRepository) Update(ctx context.Context, data *model.Historica
 
 // Delete deletes a historical data record by ID
 func (r *historicalRepository) Delete(ctx context.Context, id uint64) error {
-	result := r.db.WithContext(ctx).Delete(&model.HistoricalData{}, id)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete historical data: %w", result.Error)
+	var rowsAffected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Delete(&model.HistoricalData{}, id)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete historical data: %w", err)
 	}
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return gorm.ErrRecordNotFound
 	}
 	return nil
@@ -211,17 +647,220 @@ func (r *historicalRepository) Delete(ctx context.Context, id uint64) error {
 
 // Count returns the total count of records matching the filters
 func (r *historicalRepository) Count(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	filters = entitledFilters(ctx, filters)
 	var count int64
-	query := r.db.WithContext(ctx).Model(&model.HistoricalData{})
-	query = r.applyFilters(query, filters)
-
-	if err := query.Count(&count).Error; err != nil {
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		query := r.db.WithContext(ctx).Model(&model.HistoricalData{})
+		return r.applyFilters(query, filters).Count(&count).Error
+	})
+	if err != nil {
 		return 0, fmt.Errorf("failed to count historical data: %w", err)
 	}
 
 	return count, nil
 }
 
+// exactCount counts the rows matching the filters, serving a cached count for
+// the same filters when the count cache is enabled
+func (r *historicalRepository) exactCount(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	key := filterSignature(filters)
+	if total, ok := r.counts.get(key); ok {
+		return total, nil
+	}
+
+	var total int64
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.applyFilters(r.db.WithContext(ctx).Model(&model.HistoricalData{}), filters).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return 0, err
+	}
+
+	r.counts.set(key, total)
+	return total, nil
+}
+
+// estimateCount estimates the rows matching the filters without counting
+// them: from the table statistics of information_schema when nothing is
+// filtered, otherwise from the optimizer's estimate for the filtered query.
+// SQLite keeps no such estimates, so the rows are counted there.
+func (r *historicalRepository) estimateCount(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	if database.IsSQLite(r.db) {
+		return r.exactCount(ctx, filters)
+	}
+
+	table := model.HistoricalData{}.TableName()
+	var total int64
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		if !r.hasFilters(filters) {
+			return r.db.WithContext(ctx).
+				Raw("SELECT COALESCE(TABLE_ROWS, 0) FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", table).
+				Scan(&total).Error
+		}
+
+		// Build the filtered query without running it, then ask for its plan
+		stmt := r.applyFilters(r.db.Session(&gorm.Session{DryRun: true}).Model(&model.HistoricalData{}), filters).
+			Select("id").Find(&[]model.HistoricalData{}).Statement
+		var plan []struct {
+			Table    *string
+			Rows     *float64
+			Filtered *float64
+		}
+		if err := r.db.WithContext(ctx).Raw("EXPLAIN "+stmt.SQL.String(), stmt.Vars...).Scan(&plan).Error; err != nil {
+			return err
+		}
+		for _, step := range plan {
+			if step.Table == nil || *step.Table != table || step.Rows == nil {
+				continue
+			}
+			total = int64(*step.Rows)
+			if step.Filtered != nil {
+				total = int64(*step.Rows * *step.Filtered / 100)
+			}
+			return nil
+		}
+		return nil
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	return total, err
+}
+
+// hasFilters reports whether applyFilters would restrict the query
+func (r *historicalRepository) hasFilters(filters map[string]interface{}) bool {
+	for _, value := range filters {
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				return true
+			}
+		case time.Time:
+			if !v.IsZero() {
+				return true
+			}
+		case uint64:
+			if v != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ListSymbols returns every symbol with at least one bar, in ascending order.
+// DISTINCT on the leading column of unique_symbol_date is answered with a loose
+// index scan, reading one index entry per symbol rather than every row.
+func (r *historicalRepository) ListSymbols(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	var symbols []string
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.HistoricalData{}).
+			Distinct("symbol").
+			Order("symbol ASC").
+			Pluck("symbol", &symbols).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+// GetDateBounds returns the first and last dates and the number of bars of a
+// symbol, or nil when it has none. The aggregates are read from
+// unique_symbol_date without touching the table rows.
+func (r *historicalRepository) GetDateBounds(ctx context.Context, symbol string) (*DateBounds, error) {
+	start := time.Now()
+	var row struct {
+		FirstDate *time.Time
+		LastDate  *time.Time
+		Count     int64
+	}
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return entitled(ctx, r.db.WithContext(ctx).Model(&model.HistoricalData{})).
+			Select("MIN(date) AS first_date, MAX(date) AS last_date, COUNT(*) AS count").
+			Where("symbol = ?", symbol).
+			Scan(&row).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get date bounds: %w", err)
+	}
+	if row.Count == 0 || row.FirstDate == nil || row.LastDate == nil {
+		return nil, nil
+	}
+	return &DateBounds{
+		FirstDate: *row.FirstDate,
+		LastDate:  *row.LastDate,
+		Count:     row.Count,
+	}, nil
+}
+
+// CountBySymbol returns the number of bars of each of the given symbols, or of
+// every symbol when none are given. Symbols without bars are absent from the
+// result. The grouped count is served by unique_symbol_date.
+func (r *historicalRepository) CountBySymbol(ctx context.Context, symbols []string) (map[string]int64, error) {
+	start := time.Now()
+	var rows []struct {
+		Symbol string
+		Count  int64
+	}
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		query := entitled(ctx, r.db.WithContext(ctx).Model(&model.HistoricalData{})).
+			Select("symbol, COUNT(*) AS count")
+		if len(symbols) > 0 {
+			query = query.Where("symbol IN ?", symbols)
+		}
+		return query.Group("symbol").Scan(&rows).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count historical data by symbol: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Symbol] = row.Count
+	}
+	return counts, nil
+}
+
+// buildOrder builds an ORDER BY clause that lines up with the (symbol, date) index
+// where possible and always ends in a unique tie-breaker for stable pagination
+func (r *historicalRepository) buildOrder(filters map[string]interface{}, opts QueryOptions) string {
+	sortBy := opts.SortBy
+	if !sortableColumns[sortBy] {
+		sortBy = "date"
+	}
+	dir := "DESC"
+	if opts.SortDir == "asc" {
+		dir = "ASC"
+	}
+
+	symbol, _ := filters["symbol"].(string)
+
+	switch sortBy {
+	case "symbol":
+		// Walks unique_symbol_date in order
+		return fmt.Sprintf("symbol %s, date %s", dir, dir)
+	case "date":
+		if symbol != "" {
+			// Symbol is fixed by the filter, so unique_symbol_date already yields date order
+			return fmt.Sprintf("date %s", dir)
+		}
+		// Keep rows of the same day grouped by symbol, served by idx_date
+		return fmt.Sprintf("date %s, symbol ASC", dir)
+	default:
+		// Non-indexed columns fall back to a filesort; tie-break on the natural key
+		return fmt.Sprintf("%s %s, date DESC, symbol ASC", sortBy, dir)
+	}
+}
+
 // applyFilters applies filters to the query
 func (r *historicalRepository) applyFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
 	if symbol, ok := filters["symbol"].(string); ok && symbol != "" {
@@ -233,5 +872,33 @@ func (r *historicalRepository) applyFilters(query *gorm.DB, filters map[string]i
 	if endDate, ok := filters["end_date"].(time.Time); ok && !endDate.IsZero() {
 		query = query.Where("date <= ?", endDate)
 	}
+	if sourceID, ok := filters["source_id"].(uint64); ok && sourceID != 0 {
+		query = query.Where("source_id = ?", sourceID)
+	}
+	if minOI, ok := filters["min_open_interest"].(uint64); ok && minOI != 0 {
+		query = query.Where("open_interest >= ?", minOI)
+	}
+	if maxOI, ok := filters["max_open_interest"].(uint64); ok && maxOI != 0 {
+		query = query.Where("open_interest <= ?", maxOI)
+	}
+	if minTrades, ok := filters["min_number_of_trades"].(uint64); ok && minTrades != 0 {
+		query = query.Where("number_of_trades >= ?", minTrades)
+	}
+	if maxTrades, ok := filters["max_number_of_trades"].(uint64); ok && maxTrades != 0 {
+		query = query.Where("number_of_trades <= ?", maxTrades)
+	}
+	if through, ok := filters["entitled_through"].(time.Time); ok {
+		query = query.Where("date <= ?", through)
+	}
+	if source, ok := filters["source"].(string); ok && source != "" {
+		// Matches every upload of a filename or every backfill of a provider
+		query = query.Where("source_id IN (?)", r.db.Model(&model.Source{}).Select("id").Where("name = ?", source))
+	}
+	if tag, ok := filters["tag"].(string); ok && tag != "" {
+		query = taggedWith(query, model.HistoricalData{}.TableName(), tag)
+	}
+	if exchange, ok := filters["exchange"].(string); ok && exchange != "" {
+		query = query.Where("symbol IN (?)", r.db.Model(&model.Symbol{}).Select("symbol").Where("exchange = ?", exchange))
+	}
 	return query
 }
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/go-historical-data/pkg/database"
+)
+
+// RequiredIndexes lists the indexes the repositories' queries rely on. The
+// unique indexes back ON CONFLICT upserts: without them MySQL inserts
+// duplicates instead of updating.
+var RequiredIndexes = []database.IndexSpec{
+	{Table: "historical_data", Name: "unique_symbol_date", Columns: []string{"symbol", "date"}, Unique: true, Reason: "bar upserts"},
+	{Table: "historical_data", Name: "idx_symbol_date_close_volume", Columns: []string{"symbol", "date", "close", "volume"}, Reason: "covering reads of date, close and volume"},
+	{Table: "historical_data", Name: "idx_date", Columns: []string{"date"}, Reason: "date-ordered listing across symbols"},
+	{Table: "historical_data", Name: "idx_source_id", Columns: []string{"source_id"}, Reason: "source filters"},
+	{Table: "historical_data_staging", Name: "idx_staging_job_symbol_date", Columns: []string{"job_id", "symbol", "date"}, Reason: "validation and merge of staged uploads"},
+	{Table: "usage_records", Name: "idx_usage_tenant_key_day", Columns: []string{"tenant", "api_key", "day"}, Unique: true, Reason: "usage upserts"},
+	{Table: "symbols", Name: "unique_symbols_symbol", Columns: []string{"symbol"}, Unique: true, Reason: "symbol upserts"},
+	{Table: "corporate_actions", Name: "unique_corporate_actions_symbol_type_date", Columns: []string{"symbol", "type", "ex_date"}, Unique: true, Reason: "corporate action upserts"},
+	{Table: "historical_rollups", Name: "unique_historical_rollups_symbol_period_start", Columns: []string{"symbol", "period", "period_start"}, Unique: true, Reason: "rollup upserts"},
+	{Table: "symbol_summary", Name: "unique_symbol_summary_symbol", Columns: []string{"symbol"}, Unique: true, Reason: "summary lookups by symbol"},
+	{Table: "symbol_versions", Name: "unique_symbol_versions_symbol", Columns: []string{"symbol"}, Unique: true, Reason: "version bumps"},
+	{Table: "alert_events", Name: "unique_alert_events_rule_date", Columns: []string{"rule_id", "date"}, Unique: true, Reason: "alert event deduplication"},
+	{Table: "quotes", Name: "unique_quotes_symbol_date", Columns: []string{"symbol", "date"}, Unique: true, Reason: "quote upserts"},
+	{Table: "ticks", Name: "idx_ticks_symbol_ts", Columns: []string{"symbol", "ts"}, Reason: "tick range scans and bar aggregation"},
+	{Table: "time_series", Name: "unique_time_series_series_ts", Columns: []string{"series_id", "ts"}, Unique: true, Reason: "series upserts and range reads"},
+	{Table: "fundamentals", Name: "unique_fundamentals_symbol_period", Columns: []string{"symbol", "period"}, Unique: true, Reason: "fundamentals upserts"},
+	{Table: "fundamentals", Name: "idx_fundamentals_symbol_period_end", Columns: []string{"symbol", "period_end"}, Reason: "latest fundamentals of a symbol"},
+	{Table: "earnings_events", Name: "unique_earnings_events_symbol_period", Columns: []string{"symbol", "period"}, Unique: true, Reason: "earnings event upserts"},
+	{Table: "earnings_events", Name: "idx_earnings_events_symbol_report_date", Columns: []string{"symbol", "report_date"}, Reason: "earnings annotations of bars"},
+	{Table: "earnings_events", Name: "idx_earnings_events_report_date", Columns: []string{"report_date"}, Reason: "earnings calendar across symbols"},
+	{Table: "symbol_tags", Name: "unique_symbol_tags_tag_symbol", Columns: []string{"tag", "symbol"}, Unique: true, Reason: "tag filters"},
+	{Table: "pull_files", Name: "unique_pull_files_source_file", Columns: []string{"source", "filename", "size", "modified_at"}, Unique: true, Reason: "skipping files already pulled"},
+}
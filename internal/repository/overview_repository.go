@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// JobStats aggregates the jobs created since a time and the rows or chunks they processed
+type JobStats struct {
+	Jobs        int64
+	FailedJobs  int64
+	Items       int64 // rows of uploads, chunks of backfills
+	FailedItems int64
+}
+
+// SourceFreshness is the latest ingest of a backfill provider or an uploading tenant
+type SourceFreshness struct {
+	Kind         string
+	Name         string
+	LastIngestAt time.Time
+	Ingests      int64
+}
+
+// OverviewRepository defines the interface for the aggregates of the operational overview
+type OverviewRepository interface {
+	CountQueues(ctx context.Context) (map[string]map[string]int64, error)
+	UploadStats(ctx context.Context, since time.Time) (*JobStats, error)
+	BackfillStats(ctx context.Context, since time.Time) (*JobStats, error)
+	FindSourceFreshness(ctx context.Context) ([]SourceFreshness, error)
+	PoolStats() (sql.DBStats, error)
+}
+
+// overviewRepository implements OverviewRepository interface
+type overviewRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewOverviewRepository creates a new overview repository instance
+func NewOverviewRepository(db *gorm.DB, res *database.Resilience) OverviewRepository {
+	return &overviewRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// CountQueues counts the unfinished jobs of every queue by status: uploads
+// being processed, pending and running backfills with their chunks, and
+// pending and running exports
+func (r *overviewRepository) CountQueues(ctx context.Context) (map[string]map[string]int64, error) {
+	type statusCount struct {
+		Status string
+		Count  int64
+	}
+	active := []string{model.BackfillStatusPending, model.BackfillStatusRunning}
+
+	queues := map[string]func(ctx context.Context) *gorm.DB{
+		"uploads": func(ctx context.Context) *gorm.DB {
+			return r.db.WithContext(ctx).Model(&model.UploadJob{}).Where("status = ?", model.UploadStatusProcessing)
+		},
+		"backfills": func(ctx context.Context) *gorm.DB {
+			return r.db.WithContext(ctx).Model(&model.Backfill{}).Where("status IN ?", active)
+		},
+		"backfill_chunks": func(ctx context.Context) *gorm.DB {
+			return r.db.WithContext(ctx).Model(&model.BackfillChunk{}).
+				Where("backfill_id IN (?)", r.db.Model(&model.Backfill{}).Select("id").Where("status IN ?", active))
+		},
+		"exports": func(ctx context.Context) *gorm.DB {
+			return r.db.WithContext(ctx).Model(&model.ExportJob{}).
+				Where("status IN ?", []string{model.ExportStatusPending, model.ExportStatusRunning})
+		},
+	}
+
+	result := make(map[string]map[string]int64, len(queues))
+	for queue, newQuery := range queues {
+		var counts []statusCount
+		start := time.Now()
+		err := r.res.Do(ctx, func(ctx context.Context) error {
+			return newQuery(ctx).Select("status, COUNT(*) AS count").Group("status").Scan(&counts).Error
+		})
+		middleware.RecordDBMetrics("select", time.Since(start), err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s queue: %w", queue, err)
+		}
+
+		result[queue] = make(map[string]int64, len(counts))
+		for _, c := range counts {
+			result[queue][c.Status] = c.Count
+		}
+	}
+	return result, nil
+}
+
+// UploadStats aggregates the upload jobs created since a time and their rows
+func (r *overviewRepository) UploadStats(ctx context.Context, since time.Time) (*JobStats, error) {
+	var stats JobStats
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.UploadJob{}).
+			Select("COUNT(*) AS jobs, COALESCE(SUM(status = ?), 0) AS failed_jobs, "+
+				"COALESCE(SUM(total_rows), 0) AS items, COALESCE(SUM(failed_count), 0) AS failed_items", model.UploadStatusFailed).
+			Where("created_at >= ?", since).
+			Scan(&stats).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate upload jobs: %w", err)
+	}
+	return &stats, nil
+}
+
+// BackfillStats aggregates the backfills finished since a time and their chunks
+func (r *overviewRepository) BackfillStats(ctx context.Context, since time.Time) (*JobStats, error) {
+	var stats JobStats
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.Backfill{}).
+			Select("COUNT(*) AS jobs, COALESCE(SUM(status = ?), 0) AS failed_jobs, "+
+				"COALESCE(SUM(completed_chunks + failed_chunks), 0) AS items, COALESCE(SUM(failed_chunks), 0) AS failed_items", model.BackfillStatusFailed).
+			Where("finished_at >= ?", since).
+			Scan(&stats).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate backfills: %w", err)
+	}
+	return &stats, nil
+}
+
+// FindSourceFreshness retrieves the latest ingest of every backfill provider
+// and of every tenant's uploads
+func (r *overviewRepository) FindSourceFreshness(ctx context.Context) ([]SourceFreshness, error) {
+	var freshness []SourceFreshness
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		freshness = freshness[:0]
+		var backfills, uploads []SourceFreshness
+		if err := r.db.WithContext(ctx).Model(&model.Source{}).
+			Select("kind, name, MAX(created_at) AS last_ingest_at, COUNT(*) AS ingests").
+			Where("kind = ?", model.SourceKindBackfill).
+			Group("kind, name").
+			Order("name ASC").
+			Scan(&backfills).Error; err != nil {
+			return err
+		}
+		if err := r.db.WithContext(ctx).Model(&model.Source{}).
+			Select("kind, tenant AS name, MAX(created_at) AS last_ingest_at, COUNT(*) AS ingests").
+			Where("kind = ?", model.SourceKindUpload).
+			Group("kind, tenant").
+			Order("tenant ASC").
+			Scan(&uploads).Error; err != nil {
+			return err
+		}
+		freshness = append(append(freshness, backfills...), uploads...)
+		return nil
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find source freshness: %w", err)
+	}
+	return freshness, nil
+}
+
+// PoolStats returns the statistics of the database connection pool
+func (r *overviewRepository) PoolStats() (sql.DBStats, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Partition describes a RANGE partition of the historical data table
+type Partition struct {
+	Name string
+	// LessThan is the exclusive upper bound of the partition's dates; nil for MAXVALUE
+	LessThan      *time.Time
+	EstimatedRows int64
+}
+
+// PartitionRepository defines the interface for managing the partitions of the
+// historical data table
+type PartitionRepository interface {
+	ListPartitions(ctx context.Context) ([]Partition, error)
+	AddMonthlyPartitions(ctx context.Context, months []time.Time, maxValuePartition string) error
+	ExpirePartitions(ctx context.Context, names []string, lessThan time.Time) error
+	ExplainPartitions(ctx context.Context, start, end time.Time) ([]string, error)
+}
+
+// partitionRepository implements PartitionRepository interface
+type partitionRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewPartitionRepository creates a new partition repository instance
+func NewPartitionRepository(db *gorm.DB, res *database.Resilience) PartitionRepository {
+	return &partitionRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// MonthlyPartitionName returns the name of the partition holding the dates of
+// the month starting at month
+func MonthlyPartitionName(month time.Time) string {
+	return "p" + month.Format("200601")
+}
+
+// ListPartitions returns the partitions of the historical data table in
+// ascending order, or none when the table is not partitioned
+func (r *partitionRepository) ListPartitions(ctx context.Context) ([]Partition, error) {
+	start := time.Now()
+	var rows []struct {
+		PartitionName        string
+		PartitionDescription string
+		TableRows            int64
+	}
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Raw(`SELECT PARTITION_NAME AS partition_name, PARTITION_DESCRIPTION AS partition_description, TABLE_ROWS AS table_rows
+			FROM information_schema.PARTITIONS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL
+			ORDER BY PARTITION_ORDINAL_POSITION`, model.HistoricalData{}.TableName()).Scan(&rows).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	partitions := make([]Partition, len(rows))
+	for i, row := range rows {
+		partitions[i] = Partition{Name: row.PartitionName, EstimatedRows: row.TableRows}
+		if row.PartitionDescription == "MAXVALUE" {
+			continue
+		}
+		bound, err := time.Parse("2006-01-02", strings.Trim(row.PartitionDescription, "'"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bound %q of partition %s: %w", row.PartitionDescription, row.PartitionName, err)
+		}
+		partitions[i].LessThan = &bound
+	}
+	return partitions, nil
+}
+
+// AddMonthlyPartitions creates one partition per month starting at each of
+// months (ascending). When the table ends with a MAXVALUE partition, it is
+// split so that later dates keep landing in it.
+func (r *partitionRepository) AddMonthlyPartitions(ctx context.Context, months []time.Time, maxValuePartition string) error {
+	if len(months) == 0 {
+		return nil
+	}
+
+	definitions := make([]string, 0, len(months)+1)
+	for _, month := range months {
+		definitions = append(definitions, fmt.Sprintf("PARTITION `%s` VALUES LESS THAN ('%s')",
+			MonthlyPartitionName(month), month.AddDate(0, 1, 0).Format("2006-01-02")))
+	}
+
+	var stmt string
+	if maxValuePartition != "" {
+		definitions = append(definitions, fmt.Sprintf("PARTITION `%s` VALUES LESS THAN (MAXVALUE)", maxValuePartition))
+		stmt = fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION `%s` INTO (%s)",
+			model.HistoricalData{}.TableName(), maxValuePartition, strings.Join(definitions, ", "))
+	} else {
+		stmt = fmt.Sprintf("ALTER TABLE %s ADD PARTITION (%s)",
+			model.HistoricalData{}.TableName(), strings.Join(definitions, ", "))
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Exec(stmt).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("failed to add partitions: %w", err)
+	}
+	return nil
+}
+
+// ExpirePartitions deletes the rows of the lowest partitions (ascending) and
+// merges them into the first one, bounded by lessThan, which stays the
+// catch-all for older dates. The partitions are truncated first, so the merge
+// copies no rows.
+func (r *partitionRepository) ExpirePartitions(ctx context.Context, names []string, lessThan time.Time) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	table := model.HistoricalData{}.TableName()
+	stmts := []string{fmt.Sprintf("ALTER TABLE %s TRUNCATE PARTITION %s", table, strings.Join(quoted, ", "))}
+	if len(names) > 1 {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION %s INTO (PARTITION %s VALUES LESS THAN ('%s'))",
+			table, strings.Join(quoted, ", "), quoted[0], lessThan.Format("2006-01-02")))
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		for _, stmt := range stmts {
+			if err := r.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	middleware.RecordDBMetrics("delete", time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("failed to expire partitions: %w", err)
+	}
+	return nil
+}
+
+// ExplainPartitions returns the partitions the optimizer reads for a date
+// range query [start, end), which shows whether the range is pruned
+func (r *partitionRepository) ExplainPartitions(ctx context.Context, start, end time.Time) ([]string, error) {
+	begin := time.Now()
+	var plan []struct {
+		Partitions *string
+	}
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Raw("EXPLAIN SELECT id FROM "+model.HistoricalData{}.TableName()+" WHERE date >= ? AND date < ?", start, end).
+			Scan(&plan).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(begin), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain range query: %w", err)
+	}
+
+	var partitions []string
+	for _, step := range plan {
+		if step.Partitions != nil && *step.Partitions != "" {
+			partitions = append(partitions, strings.Split(*step.Partitions, ",")...)
+		}
+	}
+	return partitions, nil
+}
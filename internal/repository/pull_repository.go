@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// PullRepository defines the interface for pull source state and pulled file persistence
+type PullRepository interface {
+	FindStates(ctx context.Context) ([]model.PullSourceState, error)
+	SaveState(ctx context.Context, state *model.PullSourceState) error
+	HasFile(ctx context.Context, source, filename string, size int64, modifiedAt time.Time) (bool, error)
+	CreateFile(ctx context.Context, file *model.PullFile) error
+	FindFiles(ctx context.Context, source string, filters map[string]interface{}, limit, offset int) ([]model.PullFile, int64, error)
+}
+
+// pullRepository implements PullRepository interface
+type pullRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewPullRepository creates a new pull repository instance
+func NewPullRepository(db *gorm.DB, res *database.Resilience) PullRepository {
+	return &pullRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// FindStates retrieves the state of every source polled so far
+func (r *pullRepository) FindStates(ctx context.Context) ([]model.PullSourceState, error) {
+	start := time.Now()
+	var states []model.PullSourceState
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Order("name ASC").Find(&states).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pull source states: %w", err)
+	}
+	return states, nil
+}
+
+// SaveState creates or replaces the state of a source
+func (r *pullRepository) SaveState(ctx context.Context, state *model.PullSourceState) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(state).Error
+	})
+	middleware.RecordDBMetrics("upsert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to save pull source state: %w", err)
+	}
+	return nil
+}
+
+// HasFile reports whether a file of the same name, size and modification
+// time was already pulled from the source
+func (r *pullRepository) HasFile(ctx context.Context, source, filename string, size int64, modifiedAt time.Time) (bool, error) {
+	start := time.Now()
+	var count int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.PullFile{}).
+			Where("source = ? AND filename = ? AND size = ? AND modified_at = ?", source, filename, size, modifiedAt).
+			Count(&count).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to find pulled file: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CreateFile records a pulled file
+func (r *pullRepository) CreateFile(ctx context.Context, file *model.PullFile) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		file.ID = 0
+		return r.db.WithContext(ctx).Create(file).Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to record pulled file: %w", err)
+	}
+	return nil
+}
+
+// FindFiles retrieves the files pulled from a source matching the filters, newest first
+func (r *pullRepository) FindFiles(ctx context.Context, source string, filters map[string]interface{}, limit, offset int) ([]model.PullFile, int64, error) {
+	var files []model.PullFile
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.PullFile{}).Where("source = ?", source)
+		if status, ok := filters["status"].(string); ok && status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pulled files: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("id DESC").Find(&files).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find pulled files: %w", err)
+	}
+
+	return files, total, nil
+}
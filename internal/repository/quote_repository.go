@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuoteRepository defines the interface for bid/ask quote persistence
+type QuoteRepository interface {
+	BulkUpsert(ctx context.Context, quotes []model.Quote, batchSize int) error
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Quote, int64, error)
+}
+
+// quoteRepository implements QuoteRepository interface
+type quoteRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewQuoteRepository creates a new quote repository instance
+func NewQuoteRepository(db *gorm.DB, res *database.Resilience) QuoteRepository {
+	return &quoteRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// BulkUpsert stores quotes in batches, replacing the quote of the same symbol and date
+func (r *quoteRepository) BulkUpsert(ctx context.Context, quotes []model.Quote, batchSize int) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"bid", "ask", "source_id", "updated_at"}),
+		}).CreateInBatches(quotes, batchSize)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert quotes: %w", err)
+	}
+	recordBatch(r.db, "bulk_insert", "quotes", len(quotes), affected, true)
+	return nil
+}
+
+// FindAll retrieves quotes matching the filters, newest first
+func (r *quoteRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Quote, int64, error) {
+	var quotes []model.Quote
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := entitled(ctx, r.db.WithContext(ctx).Model(&model.Quote{}))
+		if symbol, ok := filters["symbol"].(string); ok && symbol != "" {
+			query = query.Where("symbol = ?", symbol)
+		}
+		if startDate, ok := filters["start_date"].(time.Time); ok && !startDate.IsZero() {
+			query = query.Where("date >= ?", startDate)
+		}
+		if endDate, ok := filters["end_date"].(time.Time); ok && !endDate.IsZero() {
+			query = query.Where("date <= ?", endDate)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count quotes: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("date DESC, symbol ASC").Find(&quotes).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find quotes: %w", err)
+	}
+
+	return quotes, total, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Rollup periods
+const (
+	RollupWeekly  = "weekly"
+	RollupMonthly = "monthly"
+)
+
+// rollupBuckets maps each rollup period to the SQL expression of the first day
+// of the period holding a bar: the Monday of its ISO week or the first of its month
+var rollupBuckets = map[string]string{
+	RollupWeekly:  "DATE_SUB(date, INTERVAL WEEKDAY(date) DAY)",
+	RollupMonthly: "DATE_SUB(date, INTERVAL DAYOFMONTH(date) - 1 DAY)",
+}
+
+// RollupRepository defines the interface for weekly and monthly rollup persistence
+type RollupRepository interface {
+	Refresh(ctx context.Context, symbol, period string, from, until time.Time) (int64, error)
+	FindBySymbol(ctx context.Context, symbol, period string, from, until time.Time) ([]model.HistoricalRollup, error)
+}
+
+// rollupRepository implements RollupRepository interface
+type rollupRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewRollupRepository creates a new rollup repository instance
+func NewRollupRepository(db *gorm.DB, res *database.Resilience) RollupRepository {
+	return &rollupRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Refresh recomputes the rollups of a symbol for the periods starting in
+// [from, until) from its daily bars and returns the number of rollups written.
+// from and until must be period starts; zero values refresh the whole history.
+// Periods left without bars are removed.
+func (r *rollupRepository) Refresh(ctx context.Context, symbol, period string, from, until time.Time) (int64, error) {
+	bucket, ok := rollupBuckets[period]
+	if !ok {
+		return 0, fmt.Errorf("unknown rollup period %q", period)
+	}
+
+	rangeSQL, args := "", []interface{}{}
+	if !from.IsZero() {
+		rangeSQL += " AND date >= ?"
+		args = append(args, from)
+	}
+	if !until.IsZero() {
+		rangeSQL += " AND date < ?"
+		args = append(args, until)
+	}
+
+	// Open and close come from the first and last bar of each period
+	insertSQL := fmt.Sprintf(`INSERT INTO historical_rollups
+			(symbol, period, period_start, period_end, open, high, low, close, volume, bar_count)
+		SELECT symbol, ?, bucket, MAX(date), MAX(first_open), MAX(high), MIN(low), MAX(last_close), SUM(volume), COUNT(*)
+		FROM (
+			SELECT symbol, date, high, low, volume, bucket,
+				FIRST_VALUE(open) OVER w AS first_open,
+				LAST_VALUE(close) OVER w AS last_close
+			FROM (
+				SELECT symbol, date, open, high, low, close, volume, %s AS bucket
+				FROM historical_data
+				WHERE symbol = ?%s
+			) bars
+			WINDOW w AS (PARTITION BY bucket ORDER BY date ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING)
+		) ranked
+		GROUP BY symbol, bucket
+		ON DUPLICATE KEY UPDATE
+			period_end = VALUES(period_end), open = VALUES(open), high = VALUES(high), low = VALUES(low),
+			close = VALUES(close), volume = VALUES(volume), bar_count = VALUES(bar_count)`, bucket, rangeSQL)
+
+	start := time.Now()
+	var written int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			query := tx.Where("symbol = ? AND period = ?", symbol, period)
+			if !from.IsZero() {
+				query = query.Where("period_start >= ?", from)
+			}
+			if !until.IsZero() {
+				query = query.Where("period_start < ?", until)
+			}
+			if err := query.Delete(&model.HistoricalRollup{}).Error; err != nil {
+				return err
+			}
+
+			result := tx.Exec(insertSQL, append([]interface{}{period, symbol}, args...)...)
+			written = result.RowsAffected
+			return result.Error
+		})
+	})
+	middleware.RecordDBMetrics("upsert", time.Since(start), err)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh %s rollups of %s: %w", period, symbol, err)
+	}
+	return written, nil
+}
+
+// FindBySymbol retrieves the rollups of a symbol for the periods starting in
+// [from, until), oldest first. Zero values leave the range open.
+func (r *rollupRepository) FindBySymbol(ctx context.Context, symbol, period string, from, until time.Time) ([]model.HistoricalRollup, error) {
+	start := time.Now()
+	var rollups []model.HistoricalRollup
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		query := r.db.WithContext(ctx).Where("symbol = ? AND period = ?", symbol, period)
+		if !from.IsZero() {
+			query = query.Where("period_start >= ?", from)
+		}
+		if !until.IsZero() {
+			query = query.Where("period_start < ?", until)
+		}
+		return query.Order("period_start ASC").Find(&rollups).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rollups: %w", err)
+	}
+	return rollups, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-historical-data/internal/model"
+	"gorm.io/gorm"
+)
+
+// Models lists the entities of every table, for databases whose schema is
+// created from the models rather than the versioned migrations
+var Models = []interface{}{
+	&model.HistoricalData{},
+	&model.StagedBar{},
+	&model.UsageRecord{},
+	&model.AuditLog{},
+	&model.UploadJob{},
+	&model.Backfill{},
+	&model.BackfillChunk{},
+	&model.Source{},
+	&model.Symbol{},
+	&model.SymbolAlias{},
+	&model.SymbolTag{},
+	&model.CorporateAction{},
+	&model.AlertRule{},
+	&model.AlertEvent{},
+	&model.Watchlist{},
+	&model.DataLock{},
+	&model.HistoricalRollup{},
+	&model.SymbolSummary{},
+	&model.SymbolVersion{},
+	&model.Quote{},
+	&model.Tick{},
+	&model.TimeSeries{},
+	&model.Fundamental{},
+	&model.EarningsEvent{},
+	&model.ExportJob{},
+	&model.PullSourceState{},
+	&model.PullFile{},
+}
+
+// CreateSchema creates the missing tables and indexes of Models on a SQLite
+// database. The migrations are MySQL only, so the schema follows the gorm tags
+// of the models instead; column types the SQLite driver doesn't read back as
+// times (datetime(6)) lose their precision suffix.
+func CreateSchema(db *gorm.DB) error {
+	for _, m := range Models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return fmt.Errorf("failed to parse model %T: %w", m, err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if dataType := string(field.DataType); strings.HasPrefix(strings.ToLower(dataType), "datetime(") {
+				field.DataType = "datetime"
+			}
+		}
+	}
+
+	if err := db.AutoMigrate(Models...); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+	return nil
+}
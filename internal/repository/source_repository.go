@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// SourceRepository defines the interface for data source persistence
+type SourceRepository interface {
+	Create(ctx context.Context, source *model.Source) error
+	FindByID(ctx context.Context, id uint64) (*model.Source, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Source, int64, error)
+}
+
+// sourceRepository implements SourceRepository interface
+type sourceRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewSourceRepository creates a new source repository instance
+func NewSourceRepository(db *gorm.DB, res *database.Resilience) SourceRepository {
+	return &sourceRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Create stores a new source
+func (r *sourceRepository) Create(ctx context.Context, source *model.Source) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Create(source).Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create source: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a source by ID, returning nil when not found
+func (r *sourceRepository) FindByID(ctx context.Context, id uint64) (*model.Source, error) {
+	start := time.Now()
+	var source model.Source
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&source, id).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find source: %w", err)
+	}
+	return &source, nil
+}
+
+// FindAll retrieves sources matching the filters, newest first
+func (r *sourceRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Source, int64, error) {
+	var sources []model.Source
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.Source{})
+		if kind, ok := filters["kind"].(string); ok && kind != "" {
+			query = query.Where("kind = ?", kind)
+		}
+		if name, ok := filters["name"].(string); ok && name != "" {
+			query = query.Where("name = ?", name)
+		}
+		if tenant, ok := filters["tenant"].(string); ok && tenant != "" {
+			query = query.Where("tenant = ?", tenant)
+		}
+		if jobID, ok := filters["upload_job_id"].(uint64); ok && jobID != 0 {
+			query = query.Where("upload_job_id = ?", jobID)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count sources: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&sources).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find sources: %w", err)
+	}
+
+	return sources, total, nil
+}
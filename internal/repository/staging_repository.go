@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StagingRepository defines the interface for the staging table of staged
+// uploads: rows land there unchecked, are validated with set-based statements
+// and merged into historical_data with a single INSERT ... SELECT
+type StagingRepository interface {
+	// Stage inserts rows of an upload job into the staging table
+	Stage(ctx context.Context, rows []model.StagedBar) error
+	// Validate rejects the staged rows of a job failing the checks and returns
+	// the rejected rows of the job per error code
+	Validate(ctx context.Context, jobID uint64, checks StagingChecks) (map[string]int64, error)
+	// FindRejected returns the first rejected rows of a job in line order
+	FindRejected(ctx context.Context, jobID uint64, limit int) ([]model.StagedBar, error)
+	// UnknownSymbols returns the symbols of the accepted rows of a job without
+	// metadata, former name or stored bars
+	UnknownSymbols(ctx context.Context, jobID uint64) ([]string, error)
+	// Summarize returns the accepted rows of a job per symbol
+	Summarize(ctx context.Context, jobID uint64) ([]StagedSymbol, error)
+	// Merge upserts the accepted rows of a job into historical_data and
+	// registers the metadata stubs in the same transaction
+	Merge(ctx context.Context, jobID uint64, stubs []model.Symbol) error
+	// Clear deletes every staged row of a job
+	Clear(ctx context.Context, jobID uint64) error
+}
+
+// StagingChecks are the error codes set on the staged rows failing each
+// check, and the parameters of the checks. The row checks mirror the business
+// rules applied to every other ingestion path.
+type StagingChecks struct {
+	HighBelowLow       string
+	OpenOutOfRange     string
+	CloseOutOfRange    string
+	FutureDate         string
+	TradesExceedVolume string
+	NonPositivePrice   string
+	UnknownSymbol      string // empty accepts unknown symbols
+	Duplicate          string
+
+	LatestDate time.Time // rows dated after it are in the future
+}
+
+// StagedSymbol summarizes the accepted rows of a symbol in a staged upload
+type StagedSymbol struct {
+	Symbol    string
+	Rows      int64
+	FirstDate time.Time
+	LastDate  time.Time
+}
+
+// stagingTable is the table staged rows are read from and updated in
+const stagingTable = "historical_data_staging"
+
+// unknownSymbolSQL matches the staged rows whose symbol has no metadata, is no
+// former name and has no stored bars
+const unknownSymbolSQL = `NOT EXISTS (SELECT 1 FROM symbols s WHERE s.symbol = historical_data_staging.symbol)
+	AND NOT EXISTS (SELECT 1 FROM symbol_aliases a WHERE a.alias = historical_data_staging.symbol)
+	AND NOT EXISTS (SELECT 1 FROM historical_data h WHERE h.symbol = historical_data_staging.symbol)`
+
+// stagingRepository implements StagingRepository interface
+type stagingRepository struct {
+	db      *gorm.DB
+	res     *database.Resilience
+	session database.WriteSession
+}
+
+// NewStagingRepository creates a new staging repository instance whose
+// writes run in transactions of session
+func NewStagingRepository(db *gorm.DB, res *database.Resilience, session database.WriteSession) StagingRepository {
+	return &stagingRepository{
+		db:      db,
+		res:     res,
+		session: session,
+	}
+}
+
+// Stage inserts the rows as they are; nothing is checked until Validate
+func (r *stagingRepository) Stage(ctx context.Context, rows []model.StagedBar) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		// A retry inserts fresh copies rather than rows carrying the IDs of
+		// a rolled back attempt
+		staged := slices.Clone(rows)
+		return r.session.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+			result := tx.CreateInBatches(staged, len(staged))
+			affected = result.RowsAffected
+			return result.Error
+		})
+	})
+	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to stage historical data: %w", err)
+	}
+	recordBatch(r.db, "bulk_insert", stagingTable, len(rows), affected, false)
+	return nil
+}
+
+// Validate runs one UPDATE per check, in the order the business rules are
+// applied to single bars, so each rejected row carries the code of the first
+// check it fails. Duplicates are looked for last among the rows still
+// accepted: every row of a symbol and date but the last one is rejected.
+func (r *stagingRepository) Validate(ctx context.Context, jobID uint64, checks StagingChecks) (map[string]int64, error) {
+	tracer := otel.Tracer("staging-repository")
+	ctx, span := tracer.Start(ctx, "StagingRepository.Validate")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("job_id", int64(jobID)))
+
+	type check struct {
+		code      string
+		condition string
+		args      []interface{}
+	}
+	all := []check{
+		{checks.HighBelowLow, "high < low", nil},
+		{checks.OpenOutOfRange, "open < low OR open > high", nil},
+		{checks.CloseOutOfRange, "close < low OR close > high", nil},
+		{checks.FutureDate, "date > ?", []interface{}{checks.LatestDate}},
+		{checks.TradesExceedVolume, "number_of_trades > volume", nil},
+		{checks.NonPositivePrice, "open <= 0 OR high <= 0 OR low <= 0 OR close <= 0", nil},
+		{checks.UnknownSymbol, unknownSymbolSQL, nil},
+		// The window function keeps the derived table materialized, which
+		// MySQL requires to read the table being updated
+		{checks.Duplicate, `id IN (SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY symbol, date ORDER BY line DESC) AS position
+			FROM historical_data_staging
+			WHERE job_id = ? AND error_code IS NULL
+		) ranked WHERE position > 1)`, []interface{}{jobID}},
+	}
+
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		affected = 0
+		return r.session.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+			for _, c := range all {
+				if c.code == "" {
+					continue
+				}
+				sql := fmt.Sprintf("UPDATE %s SET error_code = ? WHERE job_id = ? AND error_code IS NULL AND (%s)", stagingTable, c.condition)
+				result := tx.Exec(sql, append([]interface{}{c.code, jobID}, c.args...)...)
+				if result.Error != nil {
+					return fmt.Errorf("%s check: %w", c.code, result.Error)
+				}
+				affected += result.RowsAffected
+			}
+			return nil
+		})
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "validation failed")
+		return nil, fmt.Errorf("failed to validate staged rows: %w", err)
+	}
+	middleware.RecordDBRowsAffected("update", stagingTable, affected)
+
+	var counts []struct {
+		ErrorCode string
+		Rows      int64
+	}
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.StagedBar{}).
+			Select("error_code, COUNT(*) AS `rows`").
+			Where("job_id = ? AND error_code IS NOT NULL", jobID).
+			Group("error_code").
+			Scan(&counts).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "validation failed")
+		return nil, fmt.Errorf("failed to count rejected staged rows: %w", err)
+	}
+
+	rejected := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		rejected[c.ErrorCode] = c.Rows
+		span.SetAttributes(attribute.Int64("rejected."+c.ErrorCode, c.Rows))
+	}
+	span.SetStatus(codes.Ok, "validation successful")
+	return rejected, nil
+}
+
+// FindRejected reads the rejected rows of a job by line
+func (r *stagingRepository) FindRejected(ctx context.Context, jobID uint64, limit int) ([]model.StagedBar, error) {
+	var rows []model.StagedBar
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Where("job_id = ? AND error_code IS NOT NULL", jobID).
+			Order("line ASC").
+			Limit(limit).
+			Find(&rows).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rejected staged rows: %w", err)
+	}
+	return rows, nil
+}
+
+// UnknownSymbols reads the distinct unknown symbols of the accepted rows
+func (r *stagingRepository) UnknownSymbols(ctx context.Context, jobID uint64) ([]string, error) {
+	var symbols []string
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.StagedBar{}).
+			Distinct("symbol").
+			Where("job_id = ? AND error_code IS NULL", jobID).
+			Where(unknownSymbolSQL).
+			Order("symbol ASC").
+			Pluck("symbol", &symbols).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unknown staged symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+// Summarize groups the accepted rows of a job by symbol
+func (r *stagingRepository) Summarize(ctx context.Context, jobID uint64) ([]StagedSymbol, error) {
+	var symbols []StagedSymbol
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.StagedBar{}).
+			Select("symbol, COUNT(*) AS `rows`, MIN(date) AS first_date, MAX(date) AS last_date").
+			Where("job_id = ? AND error_code IS NULL", jobID).
+			Group("symbol").
+			Order("symbol ASC").
+			Scan(&symbols).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize staged rows: %w", err)
+	}
+	return symbols, nil
+}
+
+// Merge copies the accepted rows with one INSERT ... SELECT, upserting like
+// HistoricalRepository.BulkCreate: a stored bar of the same symbol and date
+// is replaced, keeping the open interest, number of trades and attributes
+// the staged row lacks. Rows are inserted in key order, as batches are.
+func (r *stagingRepository) Merge(ctx context.Context, jobID uint64, stubs []model.Symbol) error {
+	tracer := otel.Tracer("staging-repository")
+	ctx, span := tracer.Start(ctx, "StagingRepository.Merge")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("job_id", int64(jobID)),
+		attribute.Int("symbol_count", len(stubs)),
+	)
+
+	columns := "symbol, date, open, high, low, close, volume, open_interest, number_of_trades, source_id, attributes"
+	mergeSQL := fmt.Sprintf(`INSERT INTO historical_data (%s, created_at, updated_at)
+		SELECT %s, ?, ?
+		FROM %s
+		WHERE job_id = ? AND error_code IS NULL
+		ORDER BY symbol, date
+		%s`, columns, columns, stagingTable, r.upsertSQL())
+
+	start := time.Now()
+	var accepted, affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		// A retry inserts fresh copies rather than rows carrying the IDs of
+		// a rolled back attempt
+		symbols := slices.Clone(stubs)
+		return r.session.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+			if len(symbols) > 0 {
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&symbols).Error; err != nil {
+					return err
+				}
+			}
+			// The rows merged, which the metrics need to tell inserts from updates
+			if err := tx.Table(stagingTable).Where("job_id = ? AND error_code IS NULL", jobID).Count(&accepted).Error; err != nil {
+				return err
+			}
+			now := time.Now()
+			result := tx.Exec(mergeSQL, now, now, jobID)
+			affected = result.RowsAffected
+			return result.Error
+		})
+	})
+	middleware.RecordDBMetrics("upsert", time.Since(start), err)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "merge failed")
+		return fmt.Errorf("failed to merge staged rows: %w", err)
+	}
+	recordBatch(r.db, "upsert", "historical_data", int(accepted), affected, true)
+	span.SetStatus(codes.Ok, "merge successful")
+	return nil
+}
+
+// upsertSQL returns the conflict clause of the merge in the dialect of the
+// database
+func (r *stagingRepository) upsertSQL() string {
+	assignments := make([]string, 0, 10)
+	for _, column := range []string{"open", "high", "low", "close", "volume", "source_id", "updated_at"} {
+		assignments = append(assignments, fmt.Sprintf("%s = %s", column, database.Inserted(r.db, column)))
+	}
+	for _, column := range []string{"open_interest", "number_of_trades", "attributes"} {
+		assignments = append(assignments, fmt.Sprintf("%s = COALESCE(%s, %s)", column, database.Inserted(r.db, column), column))
+	}
+	if database.IsSQLite(r.db) {
+		return "ON CONFLICT (symbol, date) DO UPDATE SET " + strings.Join(assignments, ", ")
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+}
+
+// Clear deletes the staged rows of a job
+func (r *stagingRepository) Clear(ctx context.Context, jobID uint64) error {
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Where("job_id = ?", jobID).Delete(&model.StagedBar{})
+		affected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("delete", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to clear staged rows: %w", err)
+	}
+	middleware.RecordDBRowsAffected("delete", stagingTable, affected)
+	return nil
+}
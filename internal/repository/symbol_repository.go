@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SymbolRepository defines the interface for symbol metadata persistence
+type SymbolRepository interface {
+	Upsert(ctx context.Context, symbol *model.Symbol) error
+	FindBySymbol(ctx context.Context, symbol string) (*model.Symbol, error)
+	FindBySymbols(ctx context.Context, symbols []string) ([]model.Symbol, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Symbol, int64, error)
+	ResolveAlias(ctx context.Context, alias string) (string, error)
+	ResolveAliases(ctx context.Context, aliases []string) (map[string]string, error)
+	FindAliases(ctx context.Context, symbol string) ([]string, error)
+	SetTags(ctx context.Context, symbol string, tags []string) error
+	FindTags(ctx context.Context, symbols []string) (map[string][]string, error)
+	FindTagged(ctx context.Context, tag string) ([]string, error)
+	Rename(ctx context.Context, from, to, collision string) (*RenameResult, error)
+}
+
+// Collision strategies of Rename, applied to dates with bars under both symbols
+const (
+	RenameCollisionFail         = "fail"          // abort the rename
+	RenameCollisionKeepExisting = "keep_existing" // keep the bar of the new symbol
+	RenameCollisionOverwrite    = "overwrite"     // replace it with the bar of the old symbol
+)
+
+// ErrRenameCollision is returned by Rename with RenameCollisionFail when both
+// symbols have bars on the same dates
+var ErrRenameCollision = errors.New("both symbols have bars on the same dates")
+
+// RenameResult reports what a rename changed
+type RenameResult struct {
+	Collisions       int64 // dates with bars under both symbols
+	MovedRows        int64
+	DiscardedRows    int64 // bars deleted to resolve collisions
+	MovedActions     int64
+	MovedAlertRules  int64
+	MetadataMoved    bool // false when there was none or the new symbol already had its own
+	MetadataExisting bool // the old symbol had metadata
+}
+
+// symbolRepository implements SymbolRepository interface
+type symbolRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewSymbolRepository creates a new symbol repository instance
+func NewSymbolRepository(db *gorm.DB, res *database.Resilience) SymbolRepository {
+	return &symbolRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Upsert creates the symbol or replaces the metadata of an existing one
+func (r *symbolRepository) Upsert(ctx context.Context, symbol *model.Symbol) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "exchange", "currency", "updated_at"}),
+		}).Create(symbol).Error
+		if err != nil {
+			return err
+		}
+		// The insert ID is not reported back on update, reload the stored row
+		return r.db.WithContext(ctx).Where("symbol = ?", symbol.Symbol).First(symbol).Error
+	})
+	middleware.RecordDBMetrics("upsert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert symbol: %w", err)
+	}
+	return nil
+}
+
+// FindBySymbol retrieves the metadata of a symbol, returning nil when not found
+func (r *symbolRepository) FindBySymbol(ctx context.Context, symbol string) (*model.Symbol, error) {
+	start := time.Now()
+	var data model.Symbol
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("symbol = ?", symbol).First(&data).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find symbol: %w", err)
+	}
+	return &data, nil
+}
+
+// FindBySymbols retrieves the metadata of the given symbols; unknown symbols are skipped
+func (r *symbolRepository) FindBySymbols(ctx context.Context, symbols []string) ([]model.Symbol, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	start := time.Now()
+	var data []model.Symbol
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("symbol IN ?", symbols).Find(&data).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbols: %w", err)
+	}
+	return data, nil
+}
+
+// FindAll retrieves symbols matching the filters, ordered by symbol
+func (r *symbolRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Symbol, int64, error) {
+	var symbols []model.Symbol
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.Symbol{})
+		if currency, ok := filters["currency"].(string); ok && currency != "" {
+			query = query.Where("currency = ?", currency)
+		}
+		if exchange, ok := filters["exchange"].(string); ok && exchange != "" {
+			query = query.Where("exchange = ?", exchange)
+		}
+		if tag, ok := filters["tag"].(string); ok && tag != "" {
+			query = taggedWith(query, model.Symbol{}.TableName(), tag)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count symbols: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("symbol ASC").Find(&symbols).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find symbols: %w", err)
+	}
+
+	return symbols, total, nil
+}
+
+// ResolveAlias returns the current symbol of a former name, or "" when alias
+// is not the former name of a renamed symbol
+func (r *symbolRepository) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	start := time.Now()
+	var aliases []model.SymbolAlias
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("alias = ?", alias).Limit(1).Find(&aliases).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve symbol alias: %w", err)
+	}
+	if len(aliases) == 0 {
+		return "", nil
+	}
+	return aliases[0].Symbol, nil
+}
+
+// ResolveAliases returns the current symbol of each of the given names that
+// is a former name; other names are absent from the result
+func (r *symbolRepository) ResolveAliases(ctx context.Context, aliases []string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	for chunk := range slices.Chunk(aliases, 1000) {
+		var rows []model.SymbolAlias
+		start := time.Now()
+		err := r.res.Do(ctx, func(ctx context.Context) error {
+			return r.db.WithContext(ctx).Where("alias IN ?", chunk).Find(&rows).Error
+		})
+		middleware.RecordDBMetrics("select", time.Since(start), err)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve symbol aliases: %w", err)
+		}
+		for _, row := range rows {
+			resolved[row.Alias] = row.Symbol
+		}
+	}
+	return resolved, nil
+}
+
+// FindAliases returns the former names of a symbol in the order they were recorded
+func (r *symbolRepository) FindAliases(ctx context.Context, symbol string) ([]string, error) {
+	start := time.Now()
+	var aliases []string
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.SymbolAlias{}).
+			Where("symbol = ?", symbol).
+			Order("id ASC").
+			Pluck("alias", &aliases).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbol aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// SetTags replaces the tags of a symbol
+func (r *symbolRepository) SetTags(ctx context.Context, symbol string, tags []string) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("symbol = ?", symbol).Delete(&model.SymbolTag{}).Error; err != nil {
+				return err
+			}
+			if len(tags) == 0 {
+				return nil
+			}
+			rows := make([]model.SymbolTag, len(tags))
+			for i, tag := range tags {
+				rows[i] = model.SymbolTag{Tag: tag, Symbol: symbol}
+			}
+			return tx.Create(&rows).Error
+		})
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to set symbol tags: %w", err)
+	}
+	return nil
+}
+
+// FindTags returns the sorted tags of each of the given symbols; symbols
+// without tags are absent from the result
+func (r *symbolRepository) FindTags(ctx context.Context, symbols []string) (map[string][]string, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	start := time.Now()
+	var rows []model.SymbolTag
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("symbol IN ?", symbols).Order("symbol ASC, tag ASC").Find(&rows).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbol tags: %w", err)
+	}
+	tags := make(map[string][]string)
+	for _, row := range rows {
+		tags[row.Symbol] = append(tags[row.Symbol], row.Tag)
+	}
+	return tags, nil
+}
+
+// FindTagged returns the symbols carrying a tag, in ascending order
+func (r *symbolRepository) FindTagged(ctx context.Context, tag string) ([]string, error) {
+	start := time.Now()
+	var symbols []string
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(&model.SymbolTag{}).
+			Where("tag = ?", tag).
+			Order("symbol ASC").
+			Pluck("symbol", &symbols).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tagged symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+// taggedWith restricts a query on table to the symbols carrying tag. The
+// join goes through a derived table exposing only tagged_symbol, so the
+// unqualified columns of the query's own filters and ordering stay unambiguous.
+func taggedWith(query *gorm.DB, table, tag string) *gorm.DB {
+	return query.Joins(fmt.Sprintf(
+		"JOIN (SELECT symbol AS tagged_symbol FROM symbol_tags WHERE tag = ?) tagged ON tagged.tagged_symbol = %s.symbol", table), tag)
+}
+
+// Rename moves the bars, corporate actions, alert rules, data locks, tags and metadata of a
+// symbol to a new symbol in one transaction and records the old symbol as an
+// alias of the new one. Former names of the old symbol follow it to the new
+// one. Dates with bars under both symbols are resolved with the collision
+// strategy; with RenameCollisionFail nothing changes and ErrRenameCollision is
+// returned along with the collision count.
+func (r *symbolRepository) Rename(ctx context.Context, from, to, collision string) (*RenameResult, error) {
+	start := time.Now()
+	var result *RenameResult
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result = &RenameResult{}
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Raw(`SELECT COUNT(*) FROM historical_data o
+				JOIN historical_data n ON n.symbol = ? AND n.date = o.date
+				WHERE o.symbol = ?`, to, from).Scan(&result.Collisions).Error
+			if err != nil {
+				return err
+			}
+
+			if result.Collisions > 0 {
+				var res *gorm.DB
+				switch collision {
+				case RenameCollisionKeepExisting:
+					res = tx.Exec(`DELETE o FROM historical_data o
+						JOIN historical_data n ON n.symbol = ? AND n.date = o.date
+						WHERE o.symbol = ?`, to, from)
+				case RenameCollisionOverwrite:
+					res = tx.Exec(`DELETE n FROM historical_data n
+						JOIN historical_data o ON o.symbol = ? AND o.date = n.date
+						WHERE n.symbol = ?`, from, to)
+				default:
+					return ErrRenameCollision
+				}
+				if res.Error != nil {
+					return res.Error
+				}
+				result.DiscardedRows = res.RowsAffected
+			}
+
+			res := tx.Model(&model.HistoricalData{}).Where("symbol = ?", from).Update("symbol", to)
+			if res.Error != nil {
+				return res.Error
+			}
+			result.MovedRows = res.RowsAffected
+
+			// Actions already recorded for the new symbol win
+			err = tx.Exec(`DELETE o FROM corporate_actions o
+				JOIN corporate_actions n ON n.symbol = ? AND n.type = o.type AND n.ex_date = o.ex_date
+				WHERE o.symbol = ?`, to, from).Error
+			if err != nil {
+				return err
+			}
+			res = tx.Model(&model.CorporateAction{}).Where("symbol = ?", from).Update("symbol", to)
+			if res.Error != nil {
+				return res.Error
+			}
+			result.MovedActions = res.RowsAffected
+
+			res = tx.Model(&model.AlertRule{}).Where("symbol = ?", from).Update("symbol", to)
+			if res.Error != nil {
+				return res.Error
+			}
+			result.MovedAlertRules = res.RowsAffected
+
+			// Frozen ranges stay frozen under the new symbol
+			if err := tx.Model(&model.DataLock{}).Where("symbol = ?", from).Update("symbol", to).Error; err != nil {
+				return err
+			}
+
+			// Tags follow, merged with the ones of the new symbol
+			err = tx.Exec(`DELETE FROM symbol_tags WHERE symbol = ? AND tag IN (
+				SELECT tag FROM (SELECT tag FROM symbol_tags WHERE symbol = ?) existing)`, from, to).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&model.SymbolTag{}).Where("symbol = ?", from).Update("symbol", to).Error; err != nil {
+				return err
+			}
+
+			// Metadata moves unless the new symbol already has its own
+			var metadata []model.Symbol
+			if err := tx.Where("symbol IN ?", []string{from, to}).Find(&metadata).Error; err != nil {
+				return err
+			}
+			hasTo := false
+			for i := range metadata {
+				switch metadata[i].Symbol {
+				case from:
+					result.MetadataExisting = true
+				case to:
+					hasTo = true
+				}
+			}
+			if result.MetadataExisting {
+				if hasTo {
+					err = tx.Where("symbol = ?", from).Delete(&model.Symbol{}).Error
+				} else {
+					err = tx.Model(&model.Symbol{}).Where("symbol = ?", from).Update("symbol", to).Error
+					result.MetadataMoved = true
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			// The new symbol stops being an alias (renaming back), the former
+			// names of the old symbol follow it, and the old symbol becomes one
+			if err := tx.Where("alias = ?", to).Delete(&model.SymbolAlias{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.SymbolAlias{}).Where("symbol = ?", from).Update("symbol", to).Error; err != nil {
+				return err
+			}
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "alias"}},
+				DoUpdates: clause.AssignmentColumns([]string{"symbol"}),
+			}).Create(&model.SymbolAlias{Alias: from, Symbol: to}).Error
+		})
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		if errors.Is(err, ErrRenameCollision) {
+			return result, err
+		}
+		return nil, fmt.Errorf("failed to rename symbol: %w", err)
+	}
+	return result, nil
+}
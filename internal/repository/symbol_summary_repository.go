@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/entitlement"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// SymbolSummaryRepository defines the interface for per-symbol summary persistence
+type SymbolSummaryRepository interface {
+	Refresh(ctx context.Context, symbol string) error
+	FindBySymbol(ctx context.Context, symbol string) (*model.SymbolSummary, error)
+	FindBySymbols(ctx context.Context, symbols []string) ([]model.SymbolSummary, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.SymbolSummary, int64, error)
+}
+
+// symbolSummaryRepository implements SymbolSummaryRepository interface
+type symbolSummaryRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewSymbolSummaryRepository creates a new symbol summary repository instance
+func NewSymbolSummaryRepository(db *gorm.DB, res *database.Resilience) SymbolSummaryRepository {
+	return &symbolSummaryRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Refresh recomputes the summary of a symbol from its bars, which reads only
+// the symbol's range of the (symbol, date) index, then its 52-week range from
+// the last year of that range. A symbol left without bars loses its summary.
+func (r *symbolSummaryRepository) Refresh(ctx context.Context, symbol string) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("symbol = ?", symbol).Delete(&model.SymbolSummary{}).Error; err != nil {
+				return err
+			}
+			inserted := tx.Exec(`INSERT INTO symbol_summary (symbol, row_count, first_date, last_date, last_close, last_ingested_at)
+				SELECT s.symbol, s.row_count, s.first_date, s.last_date, h.close, ?
+				FROM (
+					SELECT symbol, COUNT(*) AS row_count, MIN(date) AS first_date, MAX(date) AS last_date
+					FROM historical_data
+					WHERE symbol = ?
+					GROUP BY symbol
+				) s
+				JOIN historical_data h ON h.symbol = s.symbol AND h.date = s.last_date`, time.Now(), symbol)
+			if inserted.Error != nil || inserted.RowsAffected == 0 {
+				return inserted.Error
+			}
+
+			var summary model.SymbolSummary
+			if err := tx.Where("symbol = ?", symbol).First(&summary).Error; err != nil {
+				return err
+			}
+			ranges, err := fiftyTwoWeekRanges(tx, []string{symbol}, summary.LastDate)
+			if err != nil {
+				return err
+			}
+			return tx.Model(&model.SymbolSummary{}).Where("symbol = ?", symbol).
+				Updates(map[string]interface{}{"high_52w": ranges[symbol].High52W, "low_52w": ranges[symbol].Low52W}).Error
+		})
+	})
+	middleware.RecordDBMetrics("upsert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to refresh summary of %s: %w", symbol, err)
+	}
+	return nil
+}
+
+// FindBySymbol retrieves the summary of a symbol, returning nil when it has no bars
+func (r *symbolSummaryRepository) FindBySymbol(ctx context.Context, symbol string) (*model.SymbolSummary, error) {
+	start := time.Now()
+	var summary model.SymbolSummary
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("symbol = ?", symbol).First(&summary).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find symbol summary: %w", err)
+	}
+
+	entitled, err := r.entitle(ctx, []model.SymbolSummary{summary})
+	if err != nil || len(entitled) == 0 {
+		return nil, err
+	}
+	return &entitled[0], nil
+}
+
+// FindBySymbols retrieves the summaries of the given symbols; symbols without bars are skipped
+func (r *symbolSummaryRepository) FindBySymbols(ctx context.Context, symbols []string) ([]model.SymbolSummary, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	start := time.Now()
+	var summaries []model.SymbolSummary
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("symbol IN ?", symbols).Find(&summaries).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbol summaries: %w", err)
+	}
+	return r.entitle(ctx, summaries)
+}
+
+// FindAll retrieves summaries matching the filters, ordered by symbol
+func (r *symbolSummaryRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.SymbolSummary, int64, error) {
+	var summaries []model.SymbolSummary
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.SymbolSummary{})
+		// Symbols whose latest bar is older than the date
+		if staleBefore, ok := filters["stale_before"].(time.Time); ok && !staleBefore.IsZero() {
+			query = query.Where("last_date < ?", staleBefore)
+		}
+		if tag, ok := filters["tag"].(string); ok && tag != "" {
+			query = taggedWith(query, model.SymbolSummary{}.TableName(), tag)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count symbol summaries: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("symbol ASC").Find(&summaries).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find symbol summaries: %w", err)
+	}
+
+	summaries, err = r.entitle(ctx, summaries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return summaries, total, nil
+}
+
+// entitle restricts the summaries to the entitlement window of the caller of
+// ctx and derives their position within the 52-week range
+func (r *symbolSummaryRepository) entitle(ctx context.Context, summaries []model.SymbolSummary) ([]model.SymbolSummary, error) {
+	summaries, err := r.window(ctx, summaries)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		summaries[i].SetRangePosition()
+	}
+	return summaries, nil
+}
+
+// window recomputes from the bars the summaries whose latest bar is past the
+// entitlement window of the caller of ctx, so delayed keys never see a later
+// close or range. Symbols without a bar in the window are dropped.
+func (r *symbolSummaryRepository) window(ctx context.Context, summaries []model.SymbolSummary) ([]model.SymbolSummary, error) {
+	through, ok := entitlement.Through(ctx)
+	if !ok {
+		return summaries, nil
+	}
+	var symbols []string
+	for _, summary := range summaries {
+		if summary.LastDate.After(through) {
+			symbols = append(symbols, summary.Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return summaries, nil
+	}
+
+	var windowed []model.SymbolSummary
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Raw(`SELECT s.symbol, s.row_count, s.first_date, s.last_date, h.close AS last_close
+			FROM (
+				SELECT symbol, COUNT(*) AS row_count, MIN(date) AS first_date, MAX(date) AS last_date
+				FROM historical_data
+				WHERE symbol IN ? AND date <= ?
+				GROUP BY symbol
+			) s
+			JOIN historical_data h ON h.symbol = s.symbol AND h.date = s.last_date`, symbols, through).
+			Scan(&windowed).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entitled symbol summaries: %w", err)
+	}
+
+	// The 52-week range ends at the latest bar in the window, which differs
+	// only between symbols missing bars on the last dates
+	bySymbol := make(map[string]model.SymbolSummary, len(windowed))
+	byLastDate := make(map[time.Time][]string)
+	for _, summary := range windowed {
+		bySymbol[summary.Symbol] = summary
+		byLastDate[summary.LastDate] = append(byLastDate[summary.LastDate], summary.Symbol)
+	}
+	ranges := make(map[string]model.SymbolSummary, len(windowed))
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		for lastDate, symbols := range byLastDate {
+			found, err := fiftyTwoWeekRanges(r.db.WithContext(ctx), symbols, lastDate)
+			if err != nil {
+				return err
+			}
+			for symbol, summary := range found {
+				ranges[symbol] = summary
+			}
+		}
+		return nil
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entitled 52-week ranges: %w", err)
+	}
+
+	entitled := summaries[:0]
+	for _, summary := range summaries {
+		if summary.LastDate.After(through) {
+			w, ok := bySymbol[summary.Symbol]
+			if !ok {
+				continue
+			}
+			summary.RowCount, summary.FirstDate, summary.LastDate, summary.LastClose = w.RowCount, w.FirstDate, w.LastDate, w.LastClose
+			summary.High52W, summary.Low52W = ranges[summary.Symbol].High52W, ranges[summary.Symbol].Low52W
+		}
+		entitled = append(entitled, summary)
+	}
+	return entitled, nil
+}
+
+// fiftyTwoWeekRanges computes the 52-week high and low of the symbols over
+// their bars of the 52 weeks up to lastDate, keyed by symbol
+func fiftyTwoWeekRanges(db *gorm.DB, symbols []string, lastDate time.Time) (map[string]model.SymbolSummary, error) {
+	var found []model.SymbolSummary
+	err := db.Raw(`SELECT symbol, MAX(high) AS high_52w, MIN(low) AS low_52w
+		FROM historical_data
+		WHERE symbol IN ? AND date > ? AND date <= ?
+		GROUP BY symbol`, symbols, lastDate.AddDate(0, 0, -model.FiftyTwoWeekDays), lastDate).
+		Scan(&found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make(map[string]model.SymbolSummary, len(found))
+	for _, summary := range found {
+		ranges[summary.Symbol] = summary
+	}
+	return ranges, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SymbolVersionRepository defines the interface for per-symbol data version persistence
+type SymbolVersionRepository interface {
+	// Bump increments the version of each symbol, creating it at 1
+	Bump(ctx context.Context, symbols []string) error
+	// FindBySymbols returns the version of each symbol, 0 for symbols never bumped
+	FindBySymbols(ctx context.Context, symbols []string) (map[string]uint64, error)
+}
+
+// symbolVersionRepository implements SymbolVersionRepository interface
+type symbolVersionRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewSymbolVersionRepository creates a new symbol version repository instance
+func NewSymbolVersionRepository(db *gorm.DB, res *database.Resilience) SymbolVersionRepository {
+	return &symbolVersionRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Bump upserts the versions in symbol order, so concurrent bumps of
+// overlapping symbols lock their rows in the same order
+func (r *symbolVersionRepository) Bump(ctx context.Context, symbols []string) error {
+	if len(symbols) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(symbols)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	now := time.Now()
+	versions := make([]model.SymbolVersion, len(sorted))
+	for i, symbol := range sorted {
+		versions[i] = model.SymbolVersion{Symbol: symbol, Version: 1, UpdatedAt: now}
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "symbol"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": gorm.Expr(database.Inserted(r.db, "updated_at")),
+			}),
+		}).Create(&versions).Error
+	})
+	middleware.RecordDBMetrics("upsert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to bump symbol versions: %w", err)
+	}
+	return nil
+}
+
+// FindBySymbols reads the versions by the unique symbol index
+func (r *symbolVersionRepository) FindBySymbols(ctx context.Context, symbols []string) (map[string]uint64, error) {
+	versions := make(map[string]uint64, len(symbols))
+	if len(symbols) == 0 {
+		return versions, nil
+	}
+
+	var found []model.SymbolVersion
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Where("symbol IN ?", symbols).Find(&found).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find symbol versions: %w", err)
+	}
+	for _, symbol := range symbols {
+		versions[symbol] = 0
+	}
+	for _, version := range found {
+		versions[version.Symbol] = version.Version
+	}
+	return versions, nil
+}
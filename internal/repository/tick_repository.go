@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// TickCursor is the position after which a tick listing resumes
+type TickCursor struct {
+	Timestamp time.Time
+	ID        uint64
+}
+
+// TickBar is the OHLCV bar of the ticks of one interval
+type TickBar struct {
+	Start  time.Time
+	Open   decimal.Decimal
+	High   decimal.Decimal
+	Low    decimal.Decimal
+	Close  decimal.Decimal
+	Volume decimal.Decimal
+	Trades int64
+}
+
+// TickRepository defines the interface for tick persistence
+type TickRepository interface {
+	BulkCreate(ctx context.Context, ticks []model.Tick, batchSize int) error
+	FindRange(ctx context.Context, symbol string, from, to time.Time, sessions []string, after *TickCursor, limit int) ([]model.Tick, error)
+	Aggregate(ctx context.Context, symbol string, from, to time.Time, sessions []string, interval time.Duration, loc *time.Location) ([]TickBar, error)
+}
+
+// tickRepository implements TickRepository interface
+type tickRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewTickRepository creates a new tick repository instance
+func NewTickRepository(db *gorm.DB, res *database.Resilience) TickRepository {
+	return &tickRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// BulkCreate appends ticks in batches
+func (r *tickRepository) BulkCreate(ctx context.Context, ticks []model.Tick, batchSize int) error {
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).CreateInBatches(ticks, batchSize)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create ticks: %w", err)
+	}
+	recordBatch(r.db, "bulk_insert", "ticks", len(ticks), affected, false)
+	return nil
+}
+
+// FindRange retrieves up to limit ticks of a symbol in [from, to) in time
+// order, resuming after the cursor when given and restricted to the given
+// sessions when any. The (ts, id) keyset walks idx_ticks_symbol_ts without
+// offsets however deep the listing goes.
+func (r *tickRepository) FindRange(ctx context.Context, symbol string, from, to time.Time, sessions []string, after *TickCursor, limit int) ([]model.Tick, error) {
+	var ticks []model.Tick
+	to = entitledTo(ctx, to)
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		query := r.db.WithContext(ctx).
+			Where("symbol = ? AND ts >= ? AND ts < ?", symbol, from, to)
+		if len(sessions) > 0 {
+			query = query.Where("session IN ?", sessions)
+		}
+		if after != nil {
+			query = query.Where("(ts > ? OR (ts = ? AND id > ?))", after.Timestamp, after.Timestamp, after.ID)
+		}
+		return query.Order("ts ASC, id ASC").Limit(limit).Find(&ticks).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ticks: %w", err)
+	}
+	return ticks, nil
+}
+
+// Aggregate builds OHLCV bars of the ticks of a symbol in [from, to), only
+// those of the given sessions when any, one per interval aligned to midnight
+// in loc, skipping intervals without ticks. The
+// open and close are the first and last price by (ts, id); GROUP_CONCAT only
+// needs to keep its leading entry, so group_concat_max_len never matters.
+//
+// Ticks are bucketed by their wall clock in loc: the UTC offset in effect at
+// each tick comes from a CASE over the zone transitions of the range, so
+// neither the MySQL time zone tables nor the session zone are involved. An
+// interval of up to the DST shift also groups by offset, so the wall clock
+// hour repeated when clocks fall back yields two bars; longer intervals span
+// it, e.g. a daily bar covers the 25 hours of that local day.
+func (r *tickRepository) Aggregate(ctx context.Context, symbol string, from, to time.Time, sessions []string, interval time.Duration, loc *time.Location) ([]TickBar, error) {
+	seconds := int64(interval / time.Second)
+	to = entitledTo(ctx, to)
+
+	offset, args := utcOffsetExpr("ts", from, to, loc)
+	byOffset := interval <= time.Hour && len(args) > 1
+	params := append(append(slices.Clone(args), seconds), args...)
+	params = append(params, symbol, from, to)
+	where := "symbol = ? AND ts >= ? AND ts < ?"
+	if len(sessions) > 0 {
+		where += " AND session IN ?"
+		params = append(params, sessions)
+	}
+	group := "bucket"
+	if byOffset {
+		group = "bucket, " + offset
+		params = append(params, args...)
+	}
+
+	var rows []struct {
+		Bucket    int64
+		UTCOffset int64 `gorm:"column:utc_offset"`
+		Open      decimal.Decimal
+		High      decimal.Decimal
+		Low       decimal.Decimal
+		Close     decimal.Decimal
+		Volume    decimal.Decimal
+		Trades    int64
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Raw(`
+			SELECT FLOOR((UNIX_TIMESTAMP(ts) + `+offset+`) / ?) AS bucket,
+				MIN(`+offset+`) AS utc_offset,
+				CAST(SUBSTRING_INDEX(GROUP_CONCAT(price ORDER BY ts ASC, id ASC), ',', 1) AS DECIMAL(20, 8)) AS open,
+				MAX(price) AS high,
+				MIN(price) AS low,
+				CAST(SUBSTRING_INDEX(GROUP_CONCAT(price ORDER BY ts DESC, id DESC), ',', 1) AS DECIMAL(20, 8)) AS close,
+				SUM(size) AS volume,
+				COUNT(*) AS trades
+			FROM ticks
+			WHERE `+where+`
+			GROUP BY `+group+`
+			ORDER BY MIN(ts)`, params...).
+			Scan(&rows).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate ticks: %w", err)
+	}
+
+	bars := make([]TickBar, len(rows))
+	for i, row := range rows {
+		// The bucket counts intervals of wall clock time in loc
+		start := time.Unix(row.Bucket*seconds-row.UTCOffset, 0)
+		if !byOffset {
+			wall := time.Unix(row.Bucket*seconds, 0).UTC()
+			start = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+		}
+		bars[i] = TickBar{
+			Start:  start.UTC(),
+			Open:   row.Open,
+			High:   row.High,
+			Low:    row.Low,
+			Close:  row.Close,
+			Volume: row.Volume,
+			Trades: row.Trades,
+		}
+	}
+	return bars, nil
+}
+
+// utcOffsetExpr returns a SQL expression of the UTC offset in seconds of loc
+// at the time in column, with its arguments, built from the zone transitions
+// between from and to
+func utcOffsetExpr(column string, from, to time.Time, loc *time.Location) (string, []interface{}) {
+	var expr strings.Builder
+	var args []interface{}
+	t := from
+	for {
+		_, offset := t.In(loc).Zone()
+		_, end := t.In(loc).ZoneBounds()
+		if end.IsZero() || !end.Before(to) {
+			if len(args) == 0 {
+				return "?", []interface{}{offset}
+			}
+			expr.WriteString(" ELSE ? END")
+			return "CASE" + expr.String(), append(args, offset)
+		}
+		expr.WriteString(" WHEN " + column + " < ? THEN ?")
+		args = append(args, end.UTC(), offset)
+		t = end
+	}
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TimeSeriesRepository defines the interface for generic time series persistence
+type TimeSeriesRepository interface {
+	BulkUpsert(ctx context.Context, points []model.TimeSeries, batchSize int) error
+	FindAll(ctx context.Context, filters map[string]interface{}, sortDir string, limit, offset int) ([]model.TimeSeries, int64, error)
+}
+
+// timeSeriesRepository implements TimeSeriesRepository interface
+type timeSeriesRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewTimeSeriesRepository creates a new time series repository instance
+func NewTimeSeriesRepository(db *gorm.DB, res *database.Resilience) TimeSeriesRepository {
+	return &timeSeriesRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// BulkUpsert stores observations in batches, replacing the observation of the
+// same series and timestamp. Stored fields are kept when the new observation
+// has none.
+func (r *timeSeriesRepository) BulkUpsert(ctx context.Context, points []model.TimeSeries, batchSize int) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	var affected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "series_id"}, {Name: "ts"}},
+			DoUpdates: append(clause.AssignmentColumns([]string{"value", "source_id", "updated_at"}),
+				clause.Assignment{Column: clause.Column{Name: "fields"}, Value: gorm.Expr(fmt.Sprintf("COALESCE(%s, fields)", database.Inserted(r.db, "fields")))}),
+		}).CreateInBatches(points, batchSize)
+		affected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("bulk_insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert time series: %w", err)
+	}
+	recordBatch(r.db, "bulk_insert", "time_series", len(points), affected, true)
+	return nil
+}
+
+// FindAll retrieves the observations matching the filters in timestamp order
+func (r *timeSeriesRepository) FindAll(ctx context.Context, filters map[string]interface{}, sortDir string, limit, offset int) ([]model.TimeSeries, int64, error) {
+	var points []model.TimeSeries
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.TimeSeries{})
+		if seriesID, ok := filters["series_id"].(string); ok && seriesID != "" {
+			query = query.Where("series_id = ?", seriesID)
+		}
+		if start, ok := filters["start"].(time.Time); ok && !start.IsZero() {
+			query = query.Where("ts >= ?", start)
+		}
+		if end, ok := filters["end"].(time.Time); ok && !end.IsZero() {
+			query = query.Where("ts <= ?", end)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count time series: %w", err)
+	}
+
+	// The series is fixed by the filter, so unique_time_series_series_ts yields timestamp order
+	dir := "DESC"
+	if sortDir == "asc" {
+		dir = "ASC"
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("ts " + dir).Find(&points).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find time series: %w", err)
+	}
+
+	return points, total, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// UploadJobRepository defines the interface for upload job persistence
+type UploadJobRepository interface {
+	Create(ctx context.Context, job *model.UploadJob) error
+	Update(ctx context.Context, job *model.UploadJob) error
+	SaveCheckpoint(ctx context.Context, job *model.UploadJob) error
+	FindByID(ctx context.Context, id uint64) (*model.UploadJob, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.UploadJob, int64, error)
+}
+
+// uploadJobRepository implements UploadJobRepository interface
+type uploadJobRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewUploadJobRepository creates a new upload job repository instance
+func NewUploadJobRepository(db *gorm.DB, res *database.Resilience) UploadJobRepository {
+	return &uploadJobRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Create stores a new upload job
+func (r *uploadJobRepository) Create(ctx context.Context, job *model.UploadJob) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Create(job).Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create upload job: %w", err)
+	}
+	return nil
+}
+
+// Update saves an existing upload job
+func (r *uploadJobRepository) Update(ctx context.Context, job *model.UploadJob) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(job).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update upload job: %w", err)
+	}
+	return nil
+}
+
+// SaveCheckpoint saves the progress of a processing upload job: its
+// checkpoint, row counts and symbols
+func (r *uploadJobRepository) SaveCheckpoint(ctx context.Context, job *model.UploadJob) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Model(job).
+			Select("processed_bytes", "checkpoint_line", "total_rows", "success_count", "failed_count", "symbols").
+			Updates(job).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to save upload job checkpoint: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves an upload job by ID, returning nil when not found
+func (r *uploadJobRepository) FindByID(ctx context.Context, id uint64) (*model.UploadJob, error) {
+	start := time.Now()
+	var job model.UploadJob
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&job, id).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find upload job: %w", err)
+	}
+	return &job, nil
+}
+
+// FindAll retrieves upload jobs matching the filters, newest first
+func (r *uploadJobRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.UploadJob, int64, error) {
+	var jobs []model.UploadJob
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.UploadJob{})
+		if tenant, ok := filters["tenant"].(string); ok && tenant != "" {
+			query = query.Where("tenant = ?", tenant)
+		}
+		if status, ok := filters["status"].(string); ok && status != "" {
+			query = query.Where("status = ?", status)
+		}
+		if apiKey, ok := filters["api_key"].(string); ok && apiKey != "" {
+			query = query.Where("api_key = ?", apiKey)
+		}
+		if filename, ok := filters["filename"].(string); ok && filename != "" {
+			query = query.Where("filename = ?", filename)
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count upload jobs: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&jobs).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find upload jobs: %w", err)
+	}
+
+	return jobs, total, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UsageRepository defines the interface for usage metering persistence
+type UsageRepository interface {
+	Increment(ctx context.Context, records []model.UsageRecord) error
+	FindByTenant(ctx context.Context, tenant string, startDay, endDay time.Time) ([]model.UsageRecord, error)
+	SumRowsIngested(ctx context.Context, tenant string, startDay, endDay time.Time) (int64, error)
+}
+
+// usageRepository implements UsageRepository interface
+type usageRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewUsageRepository creates a new usage repository instance
+func NewUsageRepository(db *gorm.DB, res *database.Resilience) UsageRepository {
+	return &usageRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Increment adds the given counters to the stored daily records, creating them if needed
+func (r *usageRepository) Increment(ctx context.Context, records []model.UsageRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "tenant"}, {Name: "api_key"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"rows_ingested": gorm.Expr("rows_ingested + " + database.Inserted(r.db, "rows_ingested")),
+				"rows_read":     gorm.Expr("rows_read + " + database.Inserted(r.db, "rows_read")),
+				"bytes_in":      gorm.Expr("bytes_in + " + database.Inserted(r.db, "bytes_in")),
+				"bytes_out":     gorm.Expr("bytes_out + " + database.Inserted(r.db, "bytes_out")),
+				"updated_at":    gorm.Expr(database.Inserted(r.db, "updated_at")),
+			}),
+		}).Create(&records).Error
+	})
+	middleware.RecordDBMetrics("upsert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to increment usage records: %w", err)
+	}
+	return nil
+}
+
+// FindByTenant retrieves daily usage records for a tenant within a day range
+func (r *usageRepository) FindByTenant(ctx context.Context, tenant string, startDay, endDay time.Time) ([]model.UsageRecord, error) {
+	start := time.Now()
+	var records []model.UsageRecord
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Where("tenant = ? AND day >= ? AND day <= ?", tenant, startDay, endDay).
+			Order("day ASC, api_key ASC").
+			Find(&records).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find usage records: %w", err)
+	}
+	return records, nil
+}
+
+// SumRowsIngested returns the total rows ingested by a tenant within a day range
+func (r *usageRepository) SumRowsIngested(ctx context.Context, tenant string, startDay, endDay time.Time) (int64, error) {
+	start := time.Now()
+	var total int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).
+			Model(&model.UsageRecord{}).
+			Select("COALESCE(SUM(rows_ingested), 0)").
+			Where("tenant = ? AND day >= ? AND day <= ?", tenant, startDay, endDay).
+			Scan(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum ingested rows: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// WatchlistRepository defines the interface for watchlist persistence
+type WatchlistRepository interface {
+	Create(ctx context.Context, watchlist *model.Watchlist) error
+	Update(ctx context.Context, watchlist *model.Watchlist) error
+	Delete(ctx context.Context, id uint64) error
+	FindByID(ctx context.Context, id uint64) (*model.Watchlist, error)
+	FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Watchlist, int64, error)
+}
+
+// watchlistRepository implements WatchlistRepository interface
+type watchlistRepository struct {
+	db  *gorm.DB
+	res *database.Resilience
+}
+
+// NewWatchlistRepository creates a new watchlist repository instance
+func NewWatchlistRepository(db *gorm.DB, res *database.Resilience) WatchlistRepository {
+	return &watchlistRepository{
+		db:  db,
+		res: res,
+	}
+}
+
+// Create stores a new watchlist
+func (r *watchlistRepository) Create(ctx context.Context, watchlist *model.Watchlist) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		watchlist.ID = 0
+		return r.db.WithContext(ctx).Create(watchlist).Error
+	})
+	middleware.RecordDBMetrics("insert", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to create watchlist: %w", err)
+	}
+	return nil
+}
+
+// Update saves an existing watchlist
+func (r *watchlistRepository) Update(ctx context.Context, watchlist *model.Watchlist) error {
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Save(watchlist).Error
+	})
+	middleware.RecordDBMetrics("update", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update watchlist: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a watchlist by ID
+func (r *watchlistRepository) Delete(ctx context.Context, id uint64) error {
+	start := time.Now()
+	var rowsAffected int64
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Delete(&model.Watchlist{}, id)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	middleware.RecordDBMetrics("delete", time.Since(start), err)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+	if rowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindByID retrieves a watchlist by ID, returning nil when not found
+func (r *watchlistRepository) FindByID(ctx context.Context, id uint64) (*model.Watchlist, error) {
+	start := time.Now()
+	var watchlist model.Watchlist
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).First(&watchlist, id).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find watchlist: %w", err)
+	}
+	return &watchlist, nil
+}
+
+// FindAll retrieves watchlists matching the filters ordered by name
+func (r *watchlistRepository) FindAll(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]model.Watchlist, int64, error) {
+	var watchlists []model.Watchlist
+	var total int64
+
+	newQuery := func(ctx context.Context) *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.Watchlist{})
+		for _, column := range []string{"tenant", "api_key"} {
+			if value, ok := filters[column].(string); ok && value != "" {
+				query = query.Where(column+" = ?", value)
+			}
+		}
+		return query
+	}
+
+	start := time.Now()
+	err := r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Count(&total).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count watchlists: %w", err)
+	}
+
+	start = time.Now()
+	err = r.res.Do(ctx, func(ctx context.Context) error {
+		return newQuery(ctx).Limit(limit).Offset(offset).Order("name ASC, id ASC").Find(&watchlists).Error
+	})
+	middleware.RecordDBMetrics("select", time.Since(start), err)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find watchlists: %w", err)
+	}
+
+	return watchlists, total, nil
+}
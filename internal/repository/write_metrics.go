@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/pkg/database"
+	"gorm.io/gorm"
+)
+
+// recordBatch records a successful batch write of rows rows to table and the
+// rows it affected. The rows of an upsert are also split into inserted and
+// updated ones.
+func recordBatch(db *gorm.DB, operation, table string, rows int, affected int64, upsert bool) {
+	middleware.RecordDBBatch(operation, table, rows)
+	middleware.RecordDBRowsAffected(operation, table, affected)
+	if !upsert {
+		return
+	}
+
+	// SQLite counts every inserted or updated row once
+	if database.IsSQLite(db) {
+		middleware.RecordDBUpsert(table, "upserted", affected)
+		return
+	}
+	// MySQL counts 1 per inserted row and 2 per updated row. Upserts set
+	// updated_at, so no row is left unchanged, which would count 0.
+	updated := min(max(affected-int64(rows), 0), int64(rows))
+	middleware.RecordDBUpsert(table, "inserted", max(affected-2*updated, 0))
+	middleware.RecordDBUpsert(table, "updated", updated)
+}
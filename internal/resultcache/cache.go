@@ -0,0 +1,117 @@
+// Package resultcache caches computed analytics results in process or in Redis.
+// Results are keyed by endpoint, parameters and the data version of each symbol
+// read, so a write bumping a symbol's version makes every result computed from
+// its former bars unreachable without scanning the cache.
+package resultcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/entitlement"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// Store keeps cached results
+type Store interface {
+	// Get returns the value of key, reporting false when it is missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Versions looks up the data version of symbols, 0 for symbols never written
+type Versions interface {
+	FindBySymbols(ctx context.Context, symbols []string) (map[string]uint64, error)
+}
+
+// Cache serves computed results from a Store. A nil Cache computes every result.
+type Cache struct {
+	store    Store
+	versions Versions
+	ttl      time.Duration
+}
+
+// New creates a cache keeping results in store for ttl, keyed by the versions
+func New(store Store, versions Versions, ttl time.Duration) *Cache {
+	return &Cache{store: store, versions: versions, ttl: ttl}
+}
+
+// Load returns the result of endpoint for the symbols and params from the
+// cache, or computes and caches it. A nil result, such as a symbol without
+// data, is not cached. Cache failures are logged and fall back to compute.
+func Load[T any](ctx context.Context, c *Cache, endpoint string, symbols []string, params interface{}, compute func() (*T, error)) (*T, error) {
+	if c == nil {
+		return compute()
+	}
+	log := logger.GetGlobalLogger()
+
+	key, err := c.key(ctx, endpoint, symbols, params)
+	if err != nil {
+		log.Error().Err(err).Str("endpoint", endpoint).Msg("Failed to build analytics cache key")
+		return compute()
+	}
+
+	cached, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		log.Error().Err(err).Str("endpoint", endpoint).Msg("Failed to read analytics cache")
+	}
+	if ok {
+		var result T
+		if err := json.Unmarshal(cached, &result); err == nil {
+			middleware.RecordAnalyticsCache(endpoint, "hit")
+			return &result, nil
+		}
+	}
+	middleware.RecordAnalyticsCache(endpoint, "miss")
+
+	result, err := compute()
+	if err != nil || result == nil {
+		return result, err
+	}
+	if value, err := json.Marshal(result); err == nil {
+		if err := c.store.Set(ctx, key, value, c.ttl); err != nil {
+			log.Error().Err(err).Str("endpoint", endpoint).Msg("Failed to write analytics cache")
+		}
+	}
+	return result, nil
+}
+
+// key identifies a result by endpoint, the data version of each symbol, the
+// entitlement window of the caller and a digest of the parameters. The
+// versions are read before the result is computed, so a write landing
+// meanwhile bumps them past the key and the result is never served stale.
+func (c *Cache) key(ctx context.Context, endpoint string, symbols []string, params interface{}) (string, error) {
+	versions, err := c.versions.FindBySymbols(ctx, symbols)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(encoded)
+
+	var b strings.Builder
+	b.WriteString(endpoint)
+	for _, symbol := range symbols {
+		b.WriteString("|")
+		b.WriteString(symbol)
+		b.WriteString("@")
+		b.WriteString(strconv.FormatUint(versions[symbol], 10))
+	}
+	// Delayed keys read fewer bars
+	if through, ok := entitlement.Through(ctx); ok {
+		b.WriteString("|through=")
+		b.WriteString(through.Format("2006-01-02"))
+	}
+	b.WriteString("|")
+	b.WriteString(hex.EncodeToString(digest[:]))
+	return b.String(), nil
+}
@@ -0,0 +1,225 @@
+package resultcache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+)
+
+// RedisStore keeps results in Redis, so every instance shares them. It speaks
+// RESP over a small pool of connections; a connection that fails a command is
+// closed rather than returned to the pool.
+type RedisStore struct {
+	cfg     config.RedisConfig
+	timeout time.Duration
+	pool    chan *redisConn
+}
+
+// redisConn is a pooled connection with its buffered reader and writer
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// NewRedisStore creates a store connecting lazily to the configured server
+func NewRedisStore(cfg config.RedisConfig) *RedisStore {
+	poolSize := cfg.PoolSize
+	if poolSize <= 0 {
+		poolSize = 8
+	}
+	timeout := time.Duration(cfg.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	return &RedisStore{
+		cfg:     cfg,
+		timeout: timeout,
+		pool:    make(chan *redisConn, poolSize),
+	}
+}
+
+// Get implements Store
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	replies, err := s.do(ctx, []string{"GET", s.cfg.KeyPrefix + "result:" + key})
+	if err != nil {
+		return nil, false, err
+	}
+	if replies[0] == nil {
+		return nil, false, nil
+	}
+	return replies[0].([]byte), true, nil
+}
+
+// Set implements Store
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := s.do(ctx, []string{"SET", s.cfg.KeyPrefix + "result:" + key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10)})
+	return err
+}
+
+// do sends the commands in one round trip and returns their replies
+func (s *RedisStore) do(ctx context.Context, commands ...[]string) ([]interface{}, error) {
+	c, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	c.conn.SetDeadline(deadline)
+
+	replies, err := c.roundTrip(commands)
+	if err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	s.put(c)
+	return replies, nil
+}
+
+// get takes a pooled connection or dials a new one, authenticating and
+// selecting the database
+func (s *RedisStore) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-s.pool:
+		return c, nil
+	default:
+	}
+
+	conn, err := (&net.Dialer{Timeout: s.timeout}).DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+
+	var setup [][]string
+	if s.cfg.Password != "" {
+		setup = append(setup, []string{"AUTH", s.cfg.Password})
+	}
+	if s.cfg.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(s.cfg.DB)})
+	}
+	if len(setup) > 0 {
+		conn.SetDeadline(time.Now().Add(s.timeout))
+		if _, err := c.roundTrip(setup); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// put returns a healthy connection to the pool, closing it when the pool is full
+func (s *RedisStore) put(c *redisConn) {
+	c.conn.SetDeadline(time.Time{})
+	select {
+	case s.pool <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+// roundTrip writes the commands as RESP arrays of bulk strings and reads one
+// reply per command
+func (c *redisConn) roundTrip(commands [][]string) ([]interface{}, error) {
+	for _, args := range commands {
+		fmt.Fprintf(c.w, "*%d\r\n", len(args))
+		for _, arg := range args {
+			fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+		}
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+
+	replies := make([]interface{}, len(commands))
+	var firstErr error
+	for i := range commands {
+		reply, err := c.readReply()
+		if err != nil {
+			var replyErr redisError
+			if !errors.As(err, &replyErr) {
+				return nil, err
+			}
+			// The remaining replies are still read to keep the connection usable
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		replies[i] = reply
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return replies, nil
+}
+
+// redisError is an error reply of the server
+type redisError string
+
+// Error implements error
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// readReply reads a RESP reply: bulk strings as []byte, integers as int64,
+// arrays as []interface{} and nil bulk strings or arrays as nil
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		n, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed integer %q", payload)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", payload)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		value := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, value); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		return value[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", payload)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply type %q", kind)
+}
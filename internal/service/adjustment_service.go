@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Adjustment modes accepted by the adjustment parameter
+const (
+	AdjustmentSplits    = "splits"
+	AdjustmentDividends = "dividends"
+	AdjustmentAll       = "all"
+)
+
+// dividendLookbackDays bounds the search for the close preceding a dividend's ex-date
+const dividendLookbackDays = 10
+
+// AdjustmentService applies backward split and dividend adjustments to stored series
+type AdjustmentService interface {
+	// Adjust rescales rows in place so that prices and volumes before each
+	// corporate action's ex-date are comparable with the ones after it
+	Adjust(ctx context.Context, rows []model.HistoricalData, mode string) error
+}
+
+// adjustmentFactor is the cumulative factor applied to rows dated before exDate
+type adjustmentFactor struct {
+	exDate time.Time
+	price  float64
+	volume float64
+}
+
+// adjustmentEntry caches the factors of a symbol and mode for one version of its actions
+type adjustmentEntry struct {
+	version string
+	factors []adjustmentFactor // ascending ex-date
+}
+
+// adjustmentService implements AdjustmentService interface
+type adjustmentService struct {
+	actions repository.CorporateActionRepository
+	repo    repository.HistoricalRepository
+
+	mu    sync.Mutex
+	cache map[string]*adjustmentEntry // keyed by symbol and mode
+}
+
+// NewAdjustmentService creates a new adjustment service instance
+func NewAdjustmentService(actions repository.CorporateActionRepository, repo repository.HistoricalRepository) AdjustmentService {
+	return &adjustmentService{
+		actions: actions,
+		repo:    repo,
+		cache:   make(map[string]*adjustmentEntry),
+	}
+}
+
+// Adjust implements AdjustmentService. Splits divide prices and multiply volumes
+// by the split ratio; dividends multiply prices by 1 - amount / previous close.
+func (s *adjustmentService) Adjust(ctx context.Context, rows []model.HistoricalData, mode string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AdjustmentService.Adjust")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("mode", mode),
+		attribute.Int("row_count", len(rows)),
+	)
+
+	symbols := make([]string, 0)
+	seen := make(map[string]bool)
+	for i := range rows {
+		if !seen[rows[i].Symbol] {
+			seen[rows[i].Symbol] = true
+			symbols = append(symbols, rows[i].Symbol)
+		}
+	}
+
+	actions, err := s.actions.FindBySymbols(ctx, symbols)
+	if err != nil {
+		return fmt.Errorf("failed to load corporate actions: %w", err)
+	}
+	bySymbol := make(map[string][]model.CorporateAction)
+	for i := range actions {
+		bySymbol[actions[i].Symbol] = append(bySymbol[actions[i].Symbol], actions[i])
+	}
+
+	factors := make(map[string][]adjustmentFactor, len(bySymbol))
+	for symbol, symbolActions := range bySymbol {
+		if factors[symbol], err = s.factors(ctx, symbol, mode, symbolActions); err != nil {
+			return err
+		}
+	}
+
+	for i := range rows {
+		symbolFactors := factors[rows[i].Symbol]
+		// The first action after the row carries the product of it and all later actions
+		j := sort.Search(len(symbolFactors), func(j int) bool { return symbolFactors[j].exDate.After(rows[i].Date) })
+		if j == len(symbolFactors) {
+			continue
+		}
+		f := symbolFactors[j]
+		rows[i].ScalePrices(f.price)
+		rows[i].Volume = uint64(math.Round(float64(rows[i].Volume) * f.volume))
+	}
+	return nil
+}
+
+// factors returns the cumulative factors of a symbol's actions, reusing the
+// cached result until the symbol's actions change
+func (s *adjustmentService) factors(ctx context.Context, symbol, mode string, actions []model.CorporateAction) ([]adjustmentFactor, error) {
+	key := symbol + "|" + mode
+	version := actionsVersion(actions)
+
+	s.mu.Lock()
+	entry := s.cache[key]
+	s.mu.Unlock()
+	if entry != nil && entry.version == version {
+		return entry.factors, nil
+	}
+
+	// Walk back from the newest action, accumulating the factors
+	factors := make([]adjustmentFactor, 0, len(actions))
+	price, volume := 1.0, 1.0
+	complete := true
+	for i := len(actions) - 1; i >= 0; i-- {
+		action := &actions[i]
+		switch {
+		case action.Type == model.CorporateActionSplit && mode != AdjustmentDividends && action.Ratio > 0:
+			price /= action.Ratio
+			volume *= action.Ratio
+		case action.Type == model.CorporateActionDividend && mode != AdjustmentSplits && action.Amount > 0:
+			prevClose, err := s.previousClose(ctx, symbol, action.ExDate)
+			if err != nil {
+				return nil, err
+			}
+			if prevClose <= action.Amount {
+				// No bar before the ex-date yet; retry once it has been loaded
+				complete = false
+				continue
+			}
+			price *= 1 - action.Amount/prevClose
+		default:
+			continue
+		}
+		factors = append(factors, adjustmentFactor{exDate: action.ExDate, price: price, volume: volume})
+	}
+	slices.Reverse(factors)
+
+	if complete {
+		s.mu.Lock()
+		s.cache[key] = &adjustmentEntry{version: version, factors: factors}
+		s.mu.Unlock()
+	}
+	return factors, nil
+}
+
+// previousClose returns the last close before the ex-date, or 0 when none is stored
+func (s *adjustmentService) previousClose(ctx context.Context, symbol string, exDate time.Time) (float64, error) {
+	bars, err := s.repo.FindBySymbol(ctx, symbol, exDate.AddDate(0, 0, -dividendLookbackDays), exDate.AddDate(0, 0, -1))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load close before dividend: %w", err)
+	}
+	if len(bars) == 0 {
+		return 0, nil
+	}
+	return bars[len(bars)-1].Close.InexactFloat64(), nil
+}
+
+// actionsVersion identifies a set of actions: any insert, update or delete changes it
+func actionsVersion(actions []model.CorporateAction) string {
+	var latestID uint64
+	var latestUpdate time.Time
+	for i := range actions {
+		latestID = max(latestID, actions[i].ID)
+		if actions[i].UpdatedAt.After(latestUpdate) {
+			latestUpdate = actions[i].UpdatedAt
+		}
+	}
+	return fmt.Sprintf("%d:%d:%d", len(actions), latestID, latestUpdate.UnixNano())
+}
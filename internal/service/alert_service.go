@@ -0,0 +1,450 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/notify"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// AlertService defines the interface for alert rules and their evaluation
+type AlertService interface {
+	CreateRule(ctx context.Context, tenant, apiKey string, req *request.AlertRuleRequest) (*model.AlertRule, error)
+	GetRules(ctx context.Context, tenant string, req *request.GetAlertRulesRequest) (*response.PaginatedAlertRuleResponse, error)
+	GetRule(ctx context.Context, tenant string, id uint64) (*model.AlertRule, error)
+	UpdateRule(ctx context.Context, tenant string, id uint64, req *request.AlertRuleRequest) (*model.AlertRule, error)
+	DeleteRule(ctx context.Context, tenant string, id uint64) (*model.AlertRule, error)
+	GetEvents(ctx context.Context, tenant string, id uint64, req *request.GetAlertEventsRequest) (*response.PaginatedAlertEventResponse, error)
+	// Enqueue schedules the evaluation of the rules of newly ingested bars
+	Enqueue(event events.BarsIngested)
+	// Run evaluates enqueued bars until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// alertService implements AlertService interface
+type alertService struct {
+	repo       repository.AlertRepository
+	historical repository.HistoricalRepository
+	notifiers  *notify.Registry
+
+	mu      sync.Mutex
+	pending map[string]alertRange // ingested date range per symbol awaiting evaluation
+	wake    chan struct{}
+}
+
+// alertRange is a date range of ingested bars of one symbol
+type alertRange struct {
+	start, end time.Time
+}
+
+// NewAlertService creates a new alert service instance
+func NewAlertService(repo repository.AlertRepository, historical repository.HistoricalRepository, notifiers *notify.Registry) AlertService {
+	return &alertService{
+		repo:       repo,
+		historical: historical,
+		notifiers:  notifiers,
+		pending:    make(map[string]alertRange),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// CreateRule registers an alert rule for the caller's tenant
+func (s *alertService) CreateRule(ctx context.Context, tenant, apiKey string, req *request.AlertRuleRequest) (*model.AlertRule, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AlertService.CreateRule")
+	defer span.End()
+
+	if err := s.validateRule(req); err != nil {
+		return nil, err
+	}
+
+	rule := &model.AlertRule{
+		Tenant: tenant,
+		APIKey: apiKey,
+	}
+	applyAlertRule(rule, req)
+
+	span.SetAttributes(
+		attribute.String("symbol", rule.Symbol),
+		attribute.String("condition", rule.Condition),
+		attribute.String("channel", rule.Channel),
+	)
+
+	if err := s.repo.CreateRule(ctx, rule); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create alert rule")
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetRules lists the alert rules of the caller's tenant
+func (s *alertService) GetRules(ctx context.Context, tenant string, req *request.GetAlertRulesRequest) (*response.PaginatedAlertRuleResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"tenant": tenant,
+		"symbol": req.Symbol,
+	}
+	if req.Enabled != "" {
+		filters["enabled"] = req.Enabled == "true"
+	}
+
+	rules, total, err := s.repo.FindRules(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rules: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedAlertRuleResponse{
+		Data: rules,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// GetRule retrieves an alert rule of the caller's tenant, returning nil when not found
+func (s *alertService) GetRule(ctx context.Context, tenant string, id uint64) (*model.AlertRule, error) {
+	rule, err := s.repo.FindRuleByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	if rule == nil || rule.Tenant != tenant {
+		return nil, nil
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces the settings of an alert rule, returning nil when not found
+func (s *alertService) UpdateRule(ctx context.Context, tenant string, id uint64, req *request.AlertRuleRequest) (*model.AlertRule, error) {
+	if err := s.validateRule(req); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.GetRule(ctx, tenant, id)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+
+	applyAlertRule(rule, req)
+	if err := s.repo.UpdateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes an alert rule and its history, returning nil when not found
+func (s *alertService) DeleteRule(ctx context.Context, tenant string, id uint64) (*model.AlertRule, error) {
+	rule, err := s.GetRule(ctx, tenant, id)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteRule(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+// GetEvents lists the history of an alert rule, returning nil when the rule does not exist
+func (s *alertService) GetEvents(ctx context.Context, tenant string, id uint64, req *request.GetAlertEventsRequest) (*response.PaginatedAlertEventResponse, error) {
+	req.SetDefaults()
+
+	rule, err := s.GetRule(ctx, tenant, id)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+
+	filters := map[string]interface{}{
+		"status": req.Status,
+	}
+
+	alertEvents, total, err := s.repo.FindEvents(ctx, id, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert events: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedAlertEventResponse{
+		Data: alertEvents,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// validateRule normalizes and validates a rule request, rejecting channels
+// without a configured notifier
+func (s *alertService) validateRule(req *request.AlertRuleRequest) error {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.notifiers.Get(req.Channel); err != nil {
+		return &request.ValidationError{Field: "channel", Message: fmt.Sprintf("%s alerts are not configured", req.Channel)}
+	}
+	return nil
+}
+
+// applyAlertRule copies the request settings onto a rule
+func applyAlertRule(rule *model.AlertRule, req *request.AlertRuleRequest) {
+	rule.Name = req.Name
+	rule.Symbol = req.Symbol
+	rule.Condition = req.Condition
+	rule.Threshold = req.Threshold
+	rule.Channel = req.Channel
+	rule.Target = req.Target
+	rule.Enabled = req.IsEnabled()
+}
+
+// Enqueue merges the ingested range into the pending evaluations and wakes the
+// worker. Ingestion never blocks on evaluation.
+func (s *alertService) Enqueue(event events.BarsIngested) {
+	s.mu.Lock()
+	for _, symbol := range event.Symbols {
+		r, ok := s.pending[symbol]
+		if !ok {
+			r = alertRange{start: event.StartDate, end: event.EndDate}
+		}
+		if event.StartDate.Before(r.start) {
+			r.start = event.StartDate
+		}
+		if event.EndDate.After(r.end) {
+			r.end = event.EndDate
+		}
+		s.pending[symbol] = r
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run evaluates the rules of pending symbols each time new bars are enqueued
+func (s *alertService) Run(ctx context.Context) {
+	log := logger.GetGlobalLogger()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		}
+
+		s.mu.Lock()
+		pending := s.pending
+		s.pending = make(map[string]alertRange)
+		s.mu.Unlock()
+
+		for symbol, r := range pending {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := s.evaluate(ctx, symbol, r); err != nil {
+				log.Error().Err(err).Str("symbol", symbol).Msg("Alert evaluation failed")
+			}
+		}
+	}
+}
+
+// evaluate checks every bar of the range against its previous bar for each
+// enabled rule of the symbol. Bars dated before a rule was created are
+// skipped, so backfilled history does not replay old alerts.
+func (s *alertService) evaluate(ctx context.Context, symbol string, r alertRange) error {
+	rules, err := s.repo.FindEnabledRulesBySymbol(ctx, symbol)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AlertService.evaluate")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("symbol", symbol),
+		attribute.Int("rule_count", len(rules)),
+	)
+
+	// The bar preceding the range provides the previous close of its first bar
+	filters := map[string]interface{}{
+		"symbol":   symbol,
+		"end_date": r.start.AddDate(0, 0, -1),
+	}
+	bars, _, err := s.historical.FindAll(ctx, filters, 1, 0, repository.QueryOptions{SortBy: "date", SortDir: "desc", Count: repository.CountNone})
+	if err != nil {
+		return err
+	}
+	rows, err := s.historical.FindBySymbol(ctx, symbol, r.start, r.end)
+	if err != nil {
+		return err
+	}
+	bars = append(bars, rows...)
+
+	for i := 1; i < len(bars); i++ {
+		prev, cur := &bars[i-1], &bars[i]
+		for j := range rules {
+			rule := &rules[j]
+			created := rule.CreatedAt.UTC()
+			if cur.Date.Before(time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)) {
+				continue
+			}
+			prevClose, close := prev.Close.InexactFloat64(), cur.Close.InexactFloat64()
+			value, fired := evaluateAlertCondition(rule, prevClose, close)
+			if !fired {
+				continue
+			}
+			if err := s.trigger(ctx, rule, cur, prevClose, value); err != nil {
+				span.RecordError(err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// evaluateAlertCondition returns the value the rule compares, the close or the
+// change in percent, and whether the rule fires
+func evaluateAlertCondition(rule *model.AlertRule, prevClose, close float64) (float64, bool) {
+	switch rule.Condition {
+	case model.AlertConditionCrossAbove:
+		return close, prevClose < rule.Threshold && close >= rule.Threshold
+	case model.AlertConditionCrossBelow:
+		return close, prevClose > rule.Threshold && close <= rule.Threshold
+	case model.AlertConditionChangeAbove, model.AlertConditionChangeBelow:
+		if prevClose <= 0 {
+			return 0, false
+		}
+		change := (close/prevClose - 1) * 100
+		if rule.Condition == model.AlertConditionChangeAbove {
+			return change, change >= rule.Threshold
+		}
+		return change, change <= rule.Threshold
+	}
+	return 0, false
+}
+
+// trigger records that the rule fired on a bar and delivers the notification.
+// A rule fires at most once per date, so re-ingesting a bar does not notify twice.
+func (s *alertService) trigger(ctx context.Context, rule *model.AlertRule, bar *model.HistoricalData, prevClose, value float64) error {
+	event := &model.AlertEvent{
+		RuleID:        rule.ID,
+		Symbol:        bar.Symbol,
+		Date:          bar.Date,
+		PreviousClose: prevClose,
+		Close:         bar.Close.InexactFloat64(),
+		Value:         value,
+		Status:        model.AlertEventStatusPending,
+	}
+	created, err := s.repo.CreateEvent(ctx, event)
+	if err != nil || !created {
+		return err
+	}
+
+	notifyErr := s.deliver(ctx, rule, event)
+	now := time.Now()
+	if notifyErr != nil {
+		event.Status = model.AlertEventStatusFailed
+		event.Error = deliveryFailure(rule.Channel, notifyErr)
+		logger.GetGlobalLogger().Warn().
+			Err(notifyErr).
+			Uint64("rule_id", rule.ID).
+			Str("channel", rule.Channel).
+			Msg("Alert delivery failed")
+	} else {
+		event.Status = model.AlertEventStatusDelivered
+		event.DeliveredAt = &now
+	}
+	if err := s.repo.UpdateEvent(ctx, event); err != nil {
+		return err
+	}
+
+	return s.repo.MarkRuleTriggered(ctx, rule.ID, now)
+}
+
+// deliver sends the alert over the rule's channel
+func (s *alertService) deliver(ctx context.Context, rule *model.AlertRule, event *model.AlertEvent) error {
+	notifier, err := s.notifiers.Get(rule.Channel)
+	if err != nil {
+		return err
+	}
+	return notifier.Notify(ctx, rule.Target, newAlertMessage(rule, event))
+}
+
+// deliveryFailure is the reason recorded on a failed event. Events are read back
+// by API clients, so what the target answered is only logged: recording it
+// would let a webhook pointed at an internal service read that service.
+func deliveryFailure(channel string, err error) string {
+	switch {
+	case errors.Is(err, notify.ErrUnknownChannel):
+		return fmt.Sprintf("%s alerts are not configured", channel)
+	case errors.Is(err, notify.ErrForbiddenTarget):
+		return notify.ErrForbiddenTarget.Error()
+	default:
+		return fmt.Sprintf("%s delivery failed", channel)
+	}
+}
+
+// newAlertMessage renders an alert event for every channel
+func newAlertMessage(rule *model.AlertRule, event *model.AlertEvent) notify.Message {
+	date := event.Date.Format("2006-01-02")
+
+	var description string
+	switch rule.Condition {
+	case model.AlertConditionCrossAbove:
+		description = fmt.Sprintf("closed at %g, crossing above %g", event.Close, rule.Threshold)
+	case model.AlertConditionCrossBelow:
+		description = fmt.Sprintf("closed at %g, crossing below %g", event.Close, rule.Threshold)
+	default:
+		description = fmt.Sprintf("closed at %g, a %+.2f%% change from %g", event.Close, event.Value, event.PreviousClose)
+	}
+
+	name := rule.Name
+	if name == "" {
+		name = fmt.Sprintf("%s %s %g", rule.Symbol, rule.Condition, rule.Threshold)
+	}
+
+	return notify.Message{
+		Subject: fmt.Sprintf("Alert: %s %s on %s", rule.Symbol, description, date),
+		Text:    fmt.Sprintf("Alert rule %q (#%d) fired.\n\n%s %s on %s.\n", name, rule.ID, rule.Symbol, description, date),
+		Payload: map[string]interface{}{
+			"rule_id":        rule.ID,
+			"name":           rule.Name,
+			"symbol":         rule.Symbol,
+			"condition":      rule.Condition,
+			"threshold":      rule.Threshold,
+			"date":           date,
+			"previous_close": event.PreviousClose,
+			"close":          event.Close,
+			"value":          event.Value,
+			"triggered_at":   event.CreatedAt,
+		},
+	}
+}
@@ -0,0 +1,951 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-historical-data/internal/analytics"
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/entitlement"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/internal/resultcache"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AnalyticsService defines the interface for statistics computed from stored series
+type AnalyticsService interface {
+	Compare(ctx context.Context, req *request.CompareRequest) (*response.CompareResponse, error)
+	Correlation(ctx context.Context, req *request.CorrelationRequest) (*response.CorrelationResponse, error)
+	Returns(ctx context.Context, symbol string, req *request.ReturnsRequest) (*response.ReturnsResponse, error)
+	Distribution(ctx context.Context, symbol string, req *request.DistributionRequest) (*response.DistributionResponse, error)
+	Chart(ctx context.Context, symbol string, req *request.ChartRequest) (*response.ChartResponse, error)
+	Backtest(ctx context.Context, req *request.BacktestRequest) (*response.BacktestResponse, error)
+	Aggregates(ctx context.Context, symbol string, req *request.AggregatesRequest) (*response.AggregatesResponse, error)
+}
+
+// analyticsService implements AnalyticsService interface
+type analyticsService struct {
+	repo         repository.HistoricalRepository
+	rollups      repository.RollupRepository
+	adjuster     AdjustmentService
+	converter    CurrencyConverter
+	resolver     SymbolResolver
+	fundamentals FundamentalService
+	cache        *resultcache.Cache
+	cfg          config.AnalyticsConfig
+}
+
+// NewAnalyticsService creates a new analytics service instance
+func NewAnalyticsService(repo repository.HistoricalRepository, rollups repository.RollupRepository, adjuster AdjustmentService, converter CurrencyConverter, resolver SymbolResolver, fundamentals FundamentalService, cache *resultcache.Cache, cfg config.AnalyticsConfig) AnalyticsService {
+	return &analyticsService{
+		repo:         repo,
+		rollups:      rollups,
+		adjuster:     adjuster,
+		converter:    converter,
+		resolver:     resolver,
+		fundamentals: fundamentals,
+		cache:        cache,
+		cfg:          cfg,
+	}
+}
+
+// Compare aligns the metric of several symbols on the dates they all have
+// data for, and summarizes their return correlation and relative performance
+func (s *analyticsService) Compare(ctx context.Context, req *request.CompareRequest) (*response.CompareResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Compare")
+	defer span.End()
+
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	symbols, _ := req.GetSymbols()
+	symbols, err := s.resolver.ResolveAll(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.StringSlice("symbols", symbols),
+		attribute.String("metric", req.Metric),
+	)
+
+	cached, err := s.cachedSymbols(ctx, symbols, req.ConvertTo)
+	if err != nil {
+		return nil, err
+	}
+	// Fundamentals are written without an event the cache could be invalidated on
+	cache := s.cache
+	if req.Include == "fundamentals" {
+		cache = nil
+	}
+	return resultcache.Load(ctx, cache, "compare", cached, req, func() (*response.CompareResponse, error) {
+		return s.compare(ctx, symbols, req)
+	})
+}
+
+// compare aligns and summarizes the resolved symbols
+func (s *analyticsService) compare(ctx context.Context, symbols []string, req *request.CompareRequest) (*response.CompareResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	rows := make([][]model.HistoricalData, len(symbols))
+	for i, symbol := range symbols {
+		var err error
+		rows[i], err = s.loadSeries(ctx, symbol, req.StartDate, req.EndDate, req.Adjustment, req.ConvertTo)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to load series")
+			return nil, err
+		}
+		if len(rows[i]) == 0 {
+			return nil, &request.ValidationError{Field: "symbols", Message: fmt.Sprintf("no data for %s in the requested range", symbol)}
+		}
+	}
+
+	aligned, series := alignSeries(rows, req.Metric)
+	returns := make([][]float64, len(symbols))
+	for i := range symbols {
+		returns[i] = analytics.Returns(series[i])
+	}
+
+	result := &response.CompareResponse{
+		Metric:  req.Metric,
+		Rebase:  req.Rebase,
+		Symbols: symbols,
+		Dates:   aligned,
+		Series:  make(map[string][]float64, len(symbols)),
+		Stats: response.CompareStats{
+			Correlation: make(map[string]map[string]*float64, len(symbols)),
+			Performance: make(map[string]response.SymbolPerformance, len(symbols)),
+		},
+	}
+
+	correlation := analytics.CorrelationMatrix(returns)
+	var baseReturn float64
+	for i, symbol := range symbols {
+		result.Series[symbol] = series[i]
+		if req.Rebase > 0 {
+			result.Series[symbol] = analytics.Rebase(series[i], req.Rebase)
+		}
+
+		result.Stats.Correlation[symbol] = make(map[string]*float64, len(symbols))
+		for j, other := range symbols {
+			result.Stats.Correlation[symbol][other] = correlation[i][j]
+		}
+
+		if len(aligned) == 0 {
+			continue
+		}
+		perf := response.SymbolPerformance{
+			First: series[i][0],
+			Last:  series[i][len(aligned)-1],
+		}
+		if perf.First != 0 {
+			perf.ReturnPct = (perf.Last/perf.First - 1) * 100
+		}
+		if i == 0 {
+			baseReturn = perf.ReturnPct
+		}
+		perf.RelativePct = perf.ReturnPct - baseReturn
+		result.Stats.Performance[symbol] = perf
+	}
+
+	if req.Include == "fundamentals" && len(aligned) > 0 {
+		result.Stats.Fundamentals = make(map[string]*response.FundamentalsSnapshot, len(symbols))
+		last := aligned[len(aligned)-1]
+		for i, symbol := range symbols {
+			// The last compared date is among the latest of every symbol
+			for j := len(rows[i]) - 1; j >= 0; j-- {
+				if rows[i][j].Date.Format("2006-01-02") != last {
+					continue
+				}
+				snapshot, err := s.fundamentals.Snapshot(ctx, symbol, rows[i][j].Close, rows[i][j].Date)
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, "failed to load fundamentals")
+					return nil, err
+				}
+				result.Stats.Fundamentals[symbol] = snapshot
+				break
+			}
+		}
+	}
+
+	span.SetAttributes(attribute.Int("aligned_points", len(aligned)))
+	return result, nil
+}
+
+// Correlation computes the pairwise correlation of daily returns over the
+// trailing window ending at the requested date, and each symbol's beta versus
+// the benchmark
+func (s *analyticsService) Correlation(ctx context.Context, req *request.CorrelationRequest) (*response.CorrelationResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Correlation")
+	defer span.End()
+
+	req.SetDefaults()
+	symbols, err := req.GetSymbols()
+	if err != nil {
+		return nil, err
+	}
+	if symbols, err = s.resolver.ResolveAll(ctx, symbols); err != nil {
+		return nil, err
+	}
+	if req.Benchmark, err = s.resolver.Resolve(ctx, req.Benchmark); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.StringSlice("symbols", symbols),
+		attribute.Int("window", req.Window),
+		attribute.String("benchmark", req.Benchmark),
+	)
+
+	// The benchmark is loaded and aligned with the symbols, as the last series
+	loaded := symbols
+	if req.Benchmark != "" {
+		loaded = append(slices.Clone(symbols), req.Benchmark)
+	}
+
+	cached, err := s.cachedSymbols(ctx, loaded, req.ConvertTo)
+	if err != nil {
+		return nil, err
+	}
+	return resultcache.Load(ctx, s.cache, "correlation", cached, req, func() (*response.CorrelationResponse, error) {
+		return s.correlation(ctx, symbols, loaded, req)
+	})
+}
+
+// correlation computes the correlation of the resolved symbols, loaded being
+// the symbols followed by the benchmark, if any
+func (s *analyticsService) correlation(ctx context.Context, symbols, loaded []string, req *request.CorrelationRequest) (*response.CorrelationResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	var err error
+
+	// window returns need window+1 prices on common dates
+	rows := make([][]model.HistoricalData, len(loaded))
+	for i, symbol := range loaded {
+		rows[i], err = s.loadRecent(ctx, symbol, req.EndDate, req.Window+1, req.Adjustment, req.ConvertTo)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to load series")
+			return nil, err
+		}
+		if len(rows[i]) == 0 {
+			return nil, &request.ValidationError{Field: "symbols", Message: fmt.Sprintf("no data for %s in the requested range", symbol)}
+		}
+	}
+
+	aligned, series := alignSeries(rows, "close")
+
+	result := &response.CorrelationResponse{
+		Symbols:     symbols,
+		Benchmark:   req.Benchmark,
+		Window:      req.Window,
+		Correlation: make(map[string]map[string]*float64, len(symbols)),
+	}
+	if len(aligned) > 0 {
+		result.StartDate = aligned[0]
+		result.EndDate = aligned[len(aligned)-1]
+	}
+
+	// Feed the returns period by period
+	corr := analytics.NewCorrelationAccumulator(len(symbols))
+	betas := make([]analytics.PairStats, len(symbols))
+	period := make([]float64, len(loaded))
+	for k := 1; k < len(aligned); k++ {
+		for i := range loaded {
+			period[i] = 0
+			if series[i][k-1] > 0 {
+				period[i] = series[i][k]/series[i][k-1] - 1
+			}
+		}
+		corr.Add(period[:len(symbols)])
+		if req.Benchmark != "" {
+			for i := range symbols {
+				betas[i].Add(period[i], period[len(symbols)])
+			}
+		}
+		result.Observations++
+	}
+
+	matrix := corr.Matrix()
+	for i, symbol := range symbols {
+		result.Correlation[symbol] = make(map[string]*float64, len(symbols))
+		for j, other := range symbols {
+			result.Correlation[symbol][other] = matrix[i][j]
+		}
+	}
+	if req.Benchmark != "" {
+		result.Beta = make(map[string]*float64, len(symbols))
+		for i, symbol := range symbols {
+			if beta, ok := betas[i].Beta(); ok {
+				result.Beta[symbol] = &beta
+			} else {
+				result.Beta[symbol] = nil
+			}
+		}
+	}
+
+	span.SetAttributes(attribute.Int("observations", result.Observations))
+	return result, nil
+}
+
+// Returns computes the period returns of a symbol's closes along with the
+// cumulative return, maximum drawdown and annualized Sharpe ratio. It returns
+// nil when the symbol has no data in the requested range.
+func (s *analyticsService) Returns(ctx context.Context, symbol string, req *request.ReturnsRequest) (*response.ReturnsResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Returns")
+	defer span.End()
+
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	symbol, err := s.resolver.Resolve(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("symbol", symbol),
+		attribute.String("period", req.Period),
+	)
+
+	cached, err := s.cachedSymbols(ctx, []string{symbol}, req.ConvertTo)
+	if err != nil {
+		return nil, err
+	}
+	return resultcache.Load(ctx, s.cache, "returns", cached, req, func() (*response.ReturnsResponse, error) {
+		return s.returns(ctx, symbol, req)
+	})
+}
+
+// returns computes the returns of the resolved symbol
+func (s *analyticsService) returns(ctx context.Context, symbol string, req *request.ReturnsRequest) (*response.ReturnsResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	var dates []time.Time
+	var closes []float64
+	err := s.streamSeries(ctx, symbol, req.StartDate, req.EndDate, req.Adjustment, req.ConvertTo, func(row *model.HistoricalData) {
+		dates = append(dates, row.Date)
+		closes = append(closes, row.Close.InexactFloat64())
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to load series")
+		return nil, err
+	}
+	if len(dates) == 0 {
+		return nil, nil
+	}
+	dates, closes = analytics.Resample(dates, closes, req.Period)
+
+	riskFreeRate := s.cfg.RiskFreeRate
+	if req.RiskFreeRate != nil {
+		riskFreeRate = *req.RiskFreeRate
+	}
+
+	result := &response.ReturnsResponse{
+		Symbol:       symbol,
+		Period:       req.Period,
+		StartDate:    dates[0].Format("2006-01-02"),
+		EndDate:      dates[len(dates)-1].Format("2006-01-02"),
+		Returns:      make([]response.PeriodReturn, 0, len(dates)),
+		RiskFreeRate: riskFreeRate,
+	}
+
+	returns := analytics.Returns(closes)
+	for i, r := range returns {
+		result.Returns = append(result.Returns, response.PeriodReturn{
+			Date:      dates[i+1].Format("2006-01-02"),
+			ReturnPct: r * 100,
+		})
+	}
+	if closes[0] != 0 {
+		result.CumulativeReturnPct = (closes[len(closes)-1]/closes[0] - 1) * 100
+	}
+
+	var drawdown analytics.DrawdownTracker
+	for _, v := range closes {
+		drawdown.Add(v)
+	}
+	if dd := drawdown.Max(); dd.Depth > 0 {
+		result.MaxDrawdown = response.MaxDrawdown{
+			DepthPct:   dd.Depth * 100,
+			PeakDate:   dates[dd.PeakIndex].Format("2006-01-02"),
+			TroughDate: dates[dd.TroughIndex].Format("2006-01-02"),
+		}
+	}
+
+	if sharpe, ok := analytics.Sharpe(returns, riskFreeRate/100, analytics.PeriodsPerYear(req.Period)); ok {
+		result.Sharpe = &sharpe
+	}
+
+	span.SetAttributes(attribute.Int("returns", len(result.Returns)))
+	return result, nil
+}
+
+// Distribution computes the histogram and percentiles of the period returns
+// of a symbol's closes. It returns nil when the symbol has fewer than two
+// closes in the requested range.
+func (s *analyticsService) Distribution(ctx context.Context, symbol string, req *request.DistributionRequest) (*response.DistributionResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Distribution")
+	defer span.End()
+
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	symbol, err := s.resolver.Resolve(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("symbol", symbol),
+		attribute.String("period", req.Period),
+		attribute.Int("buckets", req.Buckets),
+	)
+
+	cached, err := s.cachedSymbols(ctx, []string{symbol}, req.ConvertTo)
+	if err != nil {
+		return nil, err
+	}
+	return resultcache.Load(ctx, s.cache, "distribution", cached, req, func() (*response.DistributionResponse, error) {
+		return s.distribution(ctx, symbol, req)
+	})
+}
+
+// distribution computes the return distribution of the resolved symbol
+func (s *analyticsService) distribution(ctx context.Context, symbol string, req *request.DistributionRequest) (*response.DistributionResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	var dates []time.Time
+	var closes []float64
+	err := s.streamSeries(ctx, symbol, req.StartDate, req.EndDate, req.Adjustment, req.ConvertTo, func(row *model.HistoricalData) {
+		dates = append(dates, row.Date)
+		closes = append(closes, row.Close.InexactFloat64())
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to load series")
+		return nil, err
+	}
+	dates, closes = analytics.Resample(dates, closes, req.Period)
+	if len(closes) < 2 {
+		return nil, nil
+	}
+
+	returns := analytics.Returns(closes)
+	var stats analytics.Stats
+	for i := range returns {
+		returns[i] *= 100
+		stats.Add(returns[i])
+	}
+	sorted := slices.Clone(returns)
+	slices.Sort(sorted)
+
+	result := &response.DistributionResponse{
+		Symbol:       symbol,
+		Period:       req.Period,
+		StartDate:    dates[0].Format("2006-01-02"),
+		EndDate:      dates[len(dates)-1].Format("2006-01-02"),
+		Observations: len(returns),
+		MeanPct:      stats.Mean(),
+		MinPct:       sorted[0],
+		MaxPct:       sorted[len(sorted)-1],
+		Percentiles:  make([]response.ReturnPercentile, len(analytics.DistributionPercentiles)),
+	}
+	if stdDev, ok := stats.StdDev(); ok {
+		result.StdDevPct = &stdDev
+	}
+	for i, p := range analytics.DistributionPercentiles {
+		result.Percentiles[i] = response.ReturnPercentile{Percentile: p, ReturnPct: analytics.Percentile(sorted, p)}
+	}
+	buckets := analytics.Histogram(returns, req.Buckets)
+	result.Histogram = make([]response.ReturnHistogramBucket, len(buckets))
+	for i, b := range buckets {
+		result.Histogram[i] = response.ReturnHistogramBucket{LowerPct: b.Lower, UpperPct: b.Upper, Count: b.Count}
+	}
+
+	span.SetAttributes(attribute.Int("returns", len(returns)))
+	return result, nil
+}
+
+// Chart returns a symbol's metric downsampled to the requested number of
+// points with LTTB, or the full series when no points were requested. It
+// returns nil when the symbol has no data in the requested range.
+func (s *analyticsService) Chart(ctx context.Context, symbol string, req *request.ChartRequest) (*response.ChartResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Chart")
+	defer span.End()
+
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	symbol, err := s.resolver.Resolve(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("symbol", symbol),
+		attribute.String("metric", req.Metric),
+		attribute.Int("points", req.Points),
+	)
+
+	cached, err := s.cachedSymbols(ctx, []string{symbol}, req.ConvertTo)
+	if err != nil {
+		return nil, err
+	}
+	return resultcache.Load(ctx, s.cache, "chart", cached, req, func() (*response.ChartResponse, error) {
+		return s.chart(ctx, symbol, req)
+	})
+}
+
+// chart downsamples the metric of the resolved symbol
+func (s *analyticsService) chart(ctx context.Context, symbol string, req *request.ChartRequest) (*response.ChartResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	// Dates are placed on a day axis so gaps such as weekends keep their width
+	var dates []time.Time
+	var xs, ys []float64
+	err := s.streamSeries(ctx, symbol, req.StartDate, req.EndDate, req.Adjustment, req.ConvertTo, func(row *model.HistoricalData) {
+		dates = append(dates, row.Date)
+		xs = append(xs, float64(row.Date.Unix()/86400))
+		ys = append(ys, metricValue(row, req.Metric))
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to load series")
+		return nil, err
+	}
+	if len(xs) == 0 {
+		return nil, nil
+	}
+
+	threshold := len(xs)
+	if req.Points > 0 {
+		threshold = req.Points
+	}
+	indices := analytics.LTTB(xs, ys, threshold)
+
+	result := &response.ChartResponse{
+		Symbol:      symbol,
+		Metric:      req.Metric,
+		TotalPoints: len(xs),
+		Points:      make([]response.ChartPoint, len(indices)),
+	}
+	for i, idx := range indices {
+		result.Points[i] = response.ChartPoint{
+			Date:  dates[idx].Format("2006-01-02"),
+			Value: ys[idx],
+		}
+	}
+
+	span.SetAttributes(attribute.Int("returned_points", len(result.Points)))
+	return result, nil
+}
+
+// Backtest runs the requested strategy against a symbol's closes over the
+// requested range. The bars preceding the range warm up the moving averages so
+// signals are available from its first bar. It returns nil when the symbol has
+// no data in the requested range.
+func (s *analyticsService) Backtest(ctx context.Context, req *request.BacktestRequest) (*response.BacktestResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Backtest")
+	defer span.End()
+
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	symbol, err := s.resolver.Resolve(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	req.Symbol = symbol
+
+	span.SetAttributes(
+		attribute.String("symbol", req.Symbol),
+		attribute.String("strategy", req.Strategy),
+		attribute.Int("fast_window", req.FastWindow),
+		attribute.Int("slow_window", req.SlowWindow),
+	)
+
+	// A crossover on the first bar of the range compares it with the previous
+	// bar, so slow_window bars are needed before the range
+	filters := map[string]interface{}{
+		"symbol":   req.Symbol,
+		"end_date": req.StartDate.AddDate(0, 0, -1),
+	}
+	warmup, _, err := s.repo.FindAll(ctx, filters, req.SlowWindow, 0, repository.QueryOptions{SortBy: "date", SortDir: "desc", Count: repository.CountNone})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to load series")
+		return nil, fmt.Errorf("failed to load %s: %w", req.Symbol, err)
+	}
+	slices.Reverse(warmup)
+
+	rows, err := s.repo.FindBySymbol(ctx, req.Symbol, req.StartDate, req.EndDate)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to load series")
+		return nil, fmt.Errorf("failed to load %s: %w", req.Symbol, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	rows, err = s.prepare(ctx, req.Symbol, append(warmup, rows...), req.Adjustment, req.ConvertTo)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to load series")
+		return nil, err
+	}
+
+	closes := make([]float64, len(rows))
+	for i := range rows {
+		closes[i] = rows[i].Close.InexactFloat64()
+	}
+	from := len(warmup)
+	signals := analytics.SMACrossoverSignals(closes, req.FastWindow, req.SlowWindow)
+	run := analytics.Backtest(closes, signals, from, req.InitialCapital, req.CommissionBps/10000)
+
+	result := &response.BacktestResponse{
+		Symbol:   req.Symbol,
+		Strategy: req.Strategy,
+		Parameters: response.BacktestParameters{
+			FastWindow: req.FastWindow,
+			SlowWindow: req.SlowWindow,
+		},
+		StartDate:      rows[from].Date.Format("2006-01-02"),
+		EndDate:        rows[len(rows)-1].Date.Format("2006-01-02"),
+		InitialCapital: req.InitialCapital,
+		CommissionBps:  req.CommissionBps,
+		Trades:         make([]response.BacktestTrade, len(run.Trades)),
+		EquityCurve:    make([]response.EquityPoint, len(run.Equity)),
+		Summary: response.BacktestSummary{
+			FinalEquity:    run.FinalValue,
+			TotalReturnPct: (run.FinalValue/req.InitialCapital - 1) * 100,
+			Trades:         len(run.Trades),
+			ExposurePct:    float64(run.BarsInMarket) / float64(len(run.Equity)) * 100,
+		},
+	}
+
+	var closed int
+	for i, trade := range run.Trades {
+		result.Trades[i] = response.BacktestTrade{
+			EntryDate:  rows[trade.EntryIndex].Date.Format("2006-01-02"),
+			EntryPrice: trade.EntryPrice,
+			ExitDate:   rows[trade.ExitIndex].Date.Format("2006-01-02"),
+			ExitPrice:  trade.ExitPrice,
+			Quantity:   trade.Quantity,
+			PnL:        trade.PnL,
+			ReturnPct:  trade.Return * 100,
+			Open:       trade.Open,
+		}
+		if trade.Open {
+			continue
+		}
+		closed++
+		if trade.PnL > 0 {
+			result.Summary.WinningTrades++
+		}
+	}
+	if closed > 0 {
+		winRate := float64(result.Summary.WinningTrades) / float64(closed) * 100
+		result.Summary.WinRatePct = &winRate
+	}
+
+	var drawdown analytics.DrawdownTracker
+	for i, equity := range run.Equity {
+		result.EquityCurve[i] = response.EquityPoint{
+			Date:   rows[from+i].Date.Format("2006-01-02"),
+			Equity: equity,
+		}
+		drawdown.Add(equity)
+	}
+	result.Summary.MaxDrawdownPct = drawdown.Max().Depth * 100
+
+	if first := closes[from]; first > 0 {
+		result.Summary.BuyAndHoldReturnPct = (closes[len(closes)-1]/first - 1) * 100
+	}
+
+	riskFreeRate := s.cfg.RiskFreeRate
+	if req.RiskFreeRate != nil {
+		riskFreeRate = *req.RiskFreeRate
+	}
+	if sharpe, ok := analytics.Sharpe(analytics.Returns(run.Equity), riskFreeRate/100, analytics.PeriodsPerYear(analytics.PeriodDaily)); ok {
+		result.Summary.Sharpe = &sharpe
+	}
+
+	span.SetAttributes(attribute.Int("trades", len(result.Trades)))
+	return result, nil
+}
+
+// alignSeries extracts the metric of every series on the dates all of them
+// have a row for, in ascending date order. rows must be in ascending date order.
+func alignSeries(rows [][]model.HistoricalData, metric string) ([]string, [][]float64) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	values := make([]map[string]float64, len(rows))
+	for i := range rows {
+		values[i] = make(map[string]float64, len(rows[i]))
+		for j := range rows[i] {
+			values[i][rows[i][j].Date.Format("2006-01-02")] = metricValue(&rows[i][j], metric)
+		}
+	}
+
+	aligned := make([]string, 0, len(rows[0]))
+	for j := range rows[0] {
+		date := rows[0][j].Date.Format("2006-01-02")
+		common := true
+		for i := 1; i < len(values); i++ {
+			if _, ok := values[i][date]; !ok {
+				common = false
+				break
+			}
+		}
+		if common {
+			aligned = append(aligned, date)
+		}
+	}
+
+	series := make([][]float64, len(rows))
+	for i := range rows {
+		series[i] = make([]float64, len(aligned))
+		for j, date := range aligned {
+			series[i][j] = values[i][date]
+		}
+	}
+	return aligned, series
+}
+
+// Aggregates returns the weekly or monthly OHLCV bars of a symbol covering the
+// date range. Periods are whole, so the first and last may hold bars outside
+// the range. Raw prices are read from the rollups when they are maintained;
+// adjusted or converted prices are aggregated from the daily bars. It returns
+// nil when the symbol has no data in the range.
+func (s *analyticsService) Aggregates(ctx context.Context, symbol string, req *request.AggregatesRequest) (*response.AggregatesResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Aggregates")
+	defer span.End()
+
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	symbol, err := s.resolver.Resolve(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, until time.Time
+	if !req.StartDate.IsZero() {
+		from = analytics.PeriodStart(req.StartDate, req.Interval)
+	}
+	if !req.EndDate.IsZero() {
+		until = analytics.NextPeriodStart(analytics.PeriodStart(req.EndDate, req.Interval), req.Interval)
+	}
+
+	result := &response.AggregatesResponse{
+		Symbol:   symbol,
+		Interval: req.Interval,
+		Bars:     make([]response.AggregateBar, 0),
+	}
+	// A rollup of the current period holds bars past a delayed key's window
+	_, delayed := entitlement.Through(ctx)
+	if s.cfg.Rollups.Enabled && req.Adjustment == "" && req.ConvertTo == "" && !delayed {
+		result.Source = "rollup"
+		rollups, err := s.rollups.FindBySymbol(ctx, symbol, req.Interval, from, until)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to load rollups")
+			return nil, fmt.Errorf("failed to load %s: %w", symbol, err)
+		}
+		for i := range rollups {
+			result.Bars = append(result.Bars, response.AggregateBar{
+				PeriodStart: rollups[i].PeriodStart.Format("2006-01-02"),
+				PeriodEnd:   rollups[i].PeriodEnd.Format("2006-01-02"),
+				Open:        rollups[i].Open,
+				High:        rollups[i].High,
+				Low:         rollups[i].Low,
+				Close:       rollups[i].Close,
+				Volume:      rollups[i].Volume,
+				BarCount:    rollups[i].BarCount,
+			})
+		}
+	} else {
+		result.Source = "daily"
+		end := until
+		if !end.IsZero() {
+			end = end.AddDate(0, 0, -1)
+		}
+		err = s.streamSeries(ctx, symbol, from, end, req.Adjustment, req.ConvertTo, func(row *model.HistoricalData) {
+			start := analytics.PeriodStart(row.Date, req.Interval).Format("2006-01-02")
+			n := len(result.Bars)
+			if n == 0 || result.Bars[n-1].PeriodStart != start {
+				result.Bars = append(result.Bars, response.AggregateBar{
+					PeriodStart: start,
+					PeriodEnd:   row.Date.Format("2006-01-02"),
+					Open:        row.Open,
+					High:        row.High,
+					Low:         row.Low,
+					Close:       row.Close,
+					Volume:      row.Volume,
+					BarCount:    1,
+				})
+				return
+			}
+			bar := &result.Bars[n-1]
+			bar.PeriodEnd = row.Date.Format("2006-01-02")
+			bar.High = decimal.Max(bar.High, row.High)
+			bar.Low = decimal.Min(bar.Low, row.Low)
+			bar.Close = row.Close
+			bar.Volume += row.Volume
+			bar.BarCount++
+		})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to load series")
+			return nil, err
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("symbol", symbol),
+		attribute.String("interval", req.Interval),
+		attribute.String("source", result.Source),
+		attribute.Int("bars", len(result.Bars)),
+	)
+	if len(result.Bars) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// cachedSymbols returns the symbols whose bars a cached result computed from
+// the symbols depends on: the symbols and, when converted, their FX pairs
+func (s *analyticsService) cachedSymbols(ctx context.Context, symbols []string, convertTo string) ([]string, error) {
+	if s.cache == nil || convertTo == "" {
+		return symbols, nil
+	}
+	pairs, err := s.converter.Pairs(ctx, symbols, convertTo)
+	if err != nil {
+		return nil, err
+	}
+	return append(slices.Clone(symbols), pairs...), nil
+}
+
+// loadSeries loads a symbol's rows in ascending date order with the requested
+// adjustment and currency conversion applied
+func (s *analyticsService) loadSeries(ctx context.Context, symbol string, start, end time.Time, adjustment, convertTo string) ([]model.HistoricalData, error) {
+	rows, err := s.repo.FindBySymbol(ctx, symbol, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", symbol, err)
+	}
+	return s.prepare(ctx, symbol, rows, adjustment, convertTo)
+}
+
+// streamBatchSize is the number of streamed rows adjusted and converted at once
+const streamBatchSize = 5000
+
+// streamSeries feeds a symbol's rows to fn in ascending date order with the
+// requested adjustment and currency conversion applied. Rows are read from a
+// stream and prepared in batches, so memory doesn't grow with the range
+// beyond what fn keeps.
+func (s *analyticsService) streamSeries(ctx context.Context, symbol string, start, end time.Time, adjustment, convertTo string, fn func(row *model.HistoricalData)) error {
+	batch := make([]model.HistoricalData, 0, streamBatchSize)
+	flush := func() error {
+		prepared, err := s.prepare(ctx, symbol, batch, adjustment, convertTo)
+		if err != nil {
+			return err
+		}
+		for i := range prepared {
+			fn(&prepared[i])
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for row, err := range s.repo.FindBySymbolStream(ctx, symbol, start, end) {
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", symbol, err)
+		}
+		batch = append(batch, row)
+		if len(batch) == streamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// loadRecent loads a symbol's last count rows up to end (the latest rows when
+// end is zero) in ascending date order, prepared like loadSeries
+func (s *analyticsService) loadRecent(ctx context.Context, symbol string, end time.Time, count int, adjustment, convertTo string) ([]model.HistoricalData, error) {
+	filters := map[string]interface{}{
+		"symbol":   symbol,
+		"end_date": end,
+	}
+	rows, _, err := s.repo.FindAll(ctx, filters, count, 0, repository.QueryOptions{SortBy: "date", SortDir: "desc", Count: repository.CountNone})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", symbol, err)
+	}
+	slices.Reverse(rows)
+	return s.prepare(ctx, symbol, rows, adjustment, convertTo)
+}
+
+// prepare applies the requested adjustment and currency conversion to rows
+func (s *analyticsService) prepare(ctx context.Context, symbol string, rows []model.HistoricalData, adjustment, convertTo string) ([]model.HistoricalData, error) {
+	if adjustment != "" {
+		if err := s.adjuster.Adjust(ctx, rows, adjustment); err != nil {
+			return nil, fmt.Errorf("failed to adjust %s: %w", symbol, err)
+		}
+	}
+	if convertTo != "" {
+		if err := s.converter.Convert(ctx, rows, convertTo); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// metricValue returns the named field of a bar
+func metricValue(row *model.HistoricalData, metric string) float64 {
+	switch metric {
+	case "open":
+		return row.Open.InexactFloat64()
+	case "high":
+		return row.High.InexactFloat64()
+	case "low":
+		return row.Low.InexactFloat64()
+	case "volume":
+		return float64(row.Volume)
+	default:
+		return row.Close.InexactFloat64()
+	}
+}
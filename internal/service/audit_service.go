@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+)
+
+// AuditService defines the interface for the audit log
+type AuditService interface {
+	Record(ctx context.Context, entry *model.AuditLog) error
+	GetAuditLogs(ctx context.Context, req *request.GetAuditLogsRequest) (*response.PaginatedAuditLogResponse, error)
+}
+
+// auditService implements AuditService interface
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+// NewAuditService creates a new audit service instance
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{
+		repo: repo,
+	}
+}
+
+// Record stores an audit entry
+func (s *auditService) Record(ctx context.Context, entry *model.AuditLog) error {
+	return s.repo.Create(ctx, entry)
+}
+
+// GetAuditLogs retrieves audit entries matching the request filters
+func (s *auditService) GetAuditLogs(ctx context.Context, req *request.GetAuditLogsRequest) (*response.PaginatedAuditLogResponse, error) {
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	filters := map[string]interface{}{
+		"tenant":      req.Tenant,
+		"api_key":     req.APIKey,
+		"method":      req.Method,
+		"outcome":     req.Outcome,
+		"request_id":  req.RequestID,
+		"symbol":      req.Symbol,
+		"resource_id": req.ResourceID,
+		"start_time":  req.StartTime,
+		"end_time":    req.EndTime,
+	}
+
+	entries, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedAuditLogResponse{
+		Data: entries,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
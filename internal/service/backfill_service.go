@@ -0,0 +1,532 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/features"
+	"github.com/go-historical-data/internal/fetcher"
+	"github.com/go-historical-data/internal/ingest"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// BackfillService defines the interface for backfill orchestration
+type BackfillService interface {
+	CreateBackfill(ctx context.Context, tenant, apiKey string, req *request.CreateBackfillRequest) (*model.Backfill, error)
+	GetBackfill(ctx context.Context, id uint64) (*response.BackfillResponse, error)
+	GetBackfills(ctx context.Context, req *request.GetBackfillsRequest) (*response.PaginatedBackfillResponse, error)
+	GetBackfillChunks(ctx context.Context, id uint64, req *request.GetBackfillChunksRequest) (*response.PaginatedBackfillChunkResponse, error)
+	// Run processes pending backfills until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// backfillService implements BackfillService interface
+type backfillService struct {
+	repo       repository.BackfillRepository
+	historical repository.HistoricalRepository
+	sources    repository.SourceRepository
+	locks      repository.DataLockRepository
+	providers  *fetcher.Registry
+	transforms *ingest.Registry
+	ingestion  config.IngestionConfig
+	bus        events.Bus
+	flags      *features.Flags
+	cfg        config.BackfillConfig
+	wake       chan struct{}
+}
+
+// NewBackfillService creates a new backfill service instance
+func NewBackfillService(repo repository.BackfillRepository, historical repository.HistoricalRepository, sources repository.SourceRepository, locks repository.DataLockRepository, providers *fetcher.Registry, transforms *ingest.Registry, ingestion config.IngestionConfig, bus events.Bus, flags *features.Flags, cfg config.BackfillConfig) BackfillService {
+	return &backfillService{
+		repo:       repo,
+		historical: historical,
+		sources:    sources,
+		locks:      locks,
+		providers:  providers,
+		transforms: transforms,
+		ingestion:  ingestion,
+		bus:        bus,
+		flags:      flags,
+		cfg:        cfg,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// CreateBackfill splits the request into per-symbol date windows and queues them
+func (s *backfillService) CreateBackfill(ctx context.Context, tenant, apiKey string, req *request.CreateBackfillRequest) (*model.Backfill, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "BackfillService.CreateBackfill")
+	defer span.End()
+
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.cfg.MaxSymbols > 0 && len(req.Symbols) > s.cfg.MaxSymbols {
+		return nil, &request.ValidationError{
+			Field:   "symbols",
+			Message: fmt.Sprintf("at most %d symbols are allowed per backfill", s.cfg.MaxSymbols),
+		}
+	}
+	if _, err := s.providers.Get(req.Provider); err != nil {
+		return nil, apperror.BadRequest(err.Error(), nil).Wrap(err)
+	}
+	if !s.flags.Enabled(features.Provider(req.Provider), true) {
+		return nil, &request.ValidationError{
+			Field:   "provider",
+			Message: fmt.Sprintf("provider %q is disabled", req.Provider),
+		}
+	}
+
+	chunkDays := req.ChunkDays
+	if chunkDays == 0 {
+		chunkDays = s.cfg.ChunkDays
+	}
+
+	span.SetAttributes(
+		attribute.String("provider", req.Provider),
+		attribute.Int("symbol_count", len(req.Symbols)),
+		attribute.Int("chunk_days", chunkDays),
+	)
+
+	chunks := make([]model.BackfillChunk, 0)
+	for _, symbol := range req.Symbols {
+		for start := req.StartDate; !start.After(req.EndDate); start = start.AddDate(0, 0, chunkDays) {
+			end := start.AddDate(0, 0, chunkDays-1)
+			if end.After(req.EndDate) {
+				end = req.EndDate
+			}
+			chunks = append(chunks, model.BackfillChunk{
+				Symbol:    symbol,
+				StartDate: start,
+				EndDate:   end,
+				Status:    model.BackfillStatusPending,
+			})
+		}
+	}
+
+	backfill := &model.Backfill{
+		Tenant:      tenant,
+		APIKey:      apiKey,
+		Provider:    req.Provider,
+		Symbols:     strings.Join(req.Symbols, ","),
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		Status:      model.BackfillStatusPending,
+		TotalChunks: len(chunks),
+	}
+	if err := s.repo.Create(ctx, backfill, chunks); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create backfill")
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.Int64("backfill_id", int64(backfill.ID)),
+		attribute.Int("total_chunks", len(chunks)),
+	)
+
+	// Wake the worker without blocking; a pending signal already covers this backfill
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return backfill, nil
+}
+
+// GetBackfill retrieves a backfill with its chunk counts, returning nil when not found
+func (s *backfillService) GetBackfill(ctx context.Context, id uint64) (*response.BackfillResponse, error) {
+	backfill, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backfill: %w", err)
+	}
+	if backfill == nil {
+		return nil, nil
+	}
+
+	counts, err := s.repo.CountChunksByStatus(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backfill: %w", err)
+	}
+
+	return &response.BackfillResponse{
+		Backfill: *backfill,
+		Chunks:   counts,
+	}, nil
+}
+
+// GetBackfills lists backfills matching the request filters, newest first
+func (s *backfillService) GetBackfills(ctx context.Context, req *request.GetBackfillsRequest) (*response.PaginatedBackfillResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"tenant":   req.Tenant,
+		"provider": req.Provider,
+		"status":   req.Status,
+	}
+
+	backfills, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backfills: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedBackfillResponse{
+		Data: backfills,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// GetBackfillChunks lists the chunks of a backfill, returning nil when the backfill does not exist
+func (s *backfillService) GetBackfillChunks(ctx context.Context, id uint64, req *request.GetBackfillChunksRequest) (*response.PaginatedBackfillChunkResponse, error) {
+	req.SetDefaults()
+
+	backfill, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backfill chunks: %w", err)
+	}
+	if backfill == nil {
+		return nil, nil
+	}
+
+	filters := map[string]interface{}{
+		"status": req.Status,
+		"symbol": req.Symbol,
+	}
+
+	chunks, total, err := s.repo.FindChunks(ctx, id, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backfill chunks: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedBackfillChunkResponse{
+		Data: chunks,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// Run processes unfinished backfills oldest first, waking on new backfills and
+// every poll interval. Chunks left running by a previous process are retried;
+// only one API instance should run backfills.
+func (s *backfillService) Run(ctx context.Context) {
+	log := logger.GetGlobalLogger()
+
+	if err := s.repo.ResetRunningChunks(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to reset interrupted backfill chunks")
+	}
+
+	ticker := time.NewTicker(time.Duration(max(s.cfg.PollInterval, 1)) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			backfill, err := s.repo.FindNextUnfinished(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to find unfinished backfills")
+				break
+			}
+			if backfill == nil {
+				break
+			}
+			if err := s.process(ctx, backfill); err != nil {
+				if ctx.Err() == nil {
+					log.Error().Err(err).Uint64("backfill_id", backfill.ID).Msg("Backfill processing failed")
+				}
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// process runs the pending chunks of a backfill with bounded concurrency and
+// records the final status once no chunk is left
+func (s *backfillService) process(ctx context.Context, backfill *model.Backfill) error {
+	startTime := time.Now()
+	if backfill.Status == model.BackfillStatusPending {
+		backfill.Status = model.BackfillStatusRunning
+		backfill.StartedAt = &startTime
+		if err := s.repo.Update(ctx, backfill); err != nil {
+			return err
+		}
+	}
+
+	// The source is created on the first run, which also covers backfills queued
+	// before provenance tracking
+	if backfill.SourceID == nil {
+		source := &model.Source{
+			Kind:       model.SourceKindBackfill,
+			Name:       backfill.Provider,
+			Tenant:     backfill.Tenant,
+			BackfillID: &backfill.ID,
+		}
+		if err := s.sources.Create(ctx, source); err != nil {
+			return err
+		}
+		backfill.SourceID = &source.ID
+		if err := s.repo.Update(ctx, backfill); err != nil {
+			return err
+		}
+	}
+
+	chunks, err := s.repo.FindPendingChunks(ctx, backfill.ID)
+	if err != nil {
+		return err
+	}
+
+	// A provider removed from the configuration fails every chunk instead of
+	// leaving the backfill stuck
+	provider, providerErr := s.providers.Get(backfill.Provider)
+
+	sem := make(chan struct{}, max(s.cfg.MaxConcurrency, 1))
+	var wg sync.WaitGroup
+dispatch:
+	for i := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		wg.Add(1)
+		go func(chunk *model.BackfillChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.runChunk(ctx, backfill, provider, providerErr, chunk)
+		}(&chunks[i])
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	counts, err := s.repo.CountChunksByStatus(ctx, backfill.ID)
+	if err != nil {
+		return err
+	}
+	if counts[model.BackfillStatusPending] > 0 || counts[model.BackfillStatusRunning] > 0 {
+		return fmt.Errorf("backfill %d still has unfinished chunks", backfill.ID)
+	}
+
+	// Reload to pick up the counters maintained per chunk
+	current, err := s.repo.FindByID(ctx, backfill.ID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+
+	switch {
+	case current.FailedChunks == 0:
+		current.Status = model.BackfillStatusCompleted
+	case current.CompletedChunks == 0:
+		current.Status = model.BackfillStatusFailed
+	default:
+		current.Status = model.BackfillStatusPartial
+	}
+	finishedAt := time.Now()
+	current.FinishedAt = &finishedAt
+	if err := s.repo.Update(ctx, current); err != nil {
+		return err
+	}
+
+	duration := time.Duration(0)
+	if current.StartedAt != nil {
+		duration = finishedAt.Sub(*current.StartedAt)
+	}
+	s.bus.Publish(ctx, events.BackfillCompleted{
+		BackfillID:      current.ID,
+		Provider:        current.Provider,
+		Status:          current.Status,
+		CompletedChunks: current.CompletedChunks,
+		FailedChunks:    current.FailedChunks,
+		RowsIngested:    current.RowsIngested,
+		Duration:        duration,
+		OccurredAt:      finishedAt,
+	})
+
+	return nil
+}
+
+// runChunk fetches and stores one chunk, retrying failures with exponential
+// backoff up to the configured number of attempts
+func (s *backfillService) runChunk(ctx context.Context, backfill *model.Backfill, provider fetcher.Provider, providerErr error, chunk *model.BackfillChunk) {
+	log := logger.GetGlobalLogger()
+
+	startTime := time.Now()
+	chunk.Status = model.BackfillStatusRunning
+	chunk.StartedAt = &startTime
+	if err := s.repo.UpdateChunk(ctx, chunk); err != nil {
+		log.Error().Err(err).Uint64("chunk_id", chunk.ID).Msg("Failed to start backfill chunk")
+		return
+	}
+
+	err := providerErr
+	if provider != nil {
+		delay := time.Duration(s.cfg.RetryBaseDelayMs) * time.Millisecond
+		for attempt := 1; ; attempt++ {
+			chunk.Attempts++
+			err = s.fetchAndStore(ctx, provider, backfill.SourceID, chunk)
+			if err == nil || ctx.Err() != nil || attempt >= s.cfg.MaxAttempts {
+				break
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+			}
+			delay *= 2
+		}
+	}
+
+	// Interrupted chunks go back to pending and are retried when the backfill resumes
+	if ctx.Err() != nil {
+		chunk.Status = model.BackfillStatusPending
+		chunk.StartedAt = nil
+		saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if updateErr := s.repo.UpdateChunk(saveCtx, chunk); updateErr != nil {
+			log.Error().Err(updateErr).Uint64("chunk_id", chunk.ID).Msg("Failed to requeue backfill chunk")
+		}
+		return
+	}
+
+	finishedAt := time.Now()
+	chunk.FinishedAt = &finishedAt
+	chunk.Status = model.BackfillStatusCompleted
+	if err != nil {
+		chunk.Status = model.BackfillStatusFailed
+		chunk.Error = err.Error()
+	}
+
+	if err := s.repo.FinishChunk(ctx, chunk); err != nil {
+		log.Error().Err(err).Uint64("chunk_id", chunk.ID).Msg("Failed to finish backfill chunk")
+	}
+	middleware.RecordBackfillChunkMetrics(backfill.Provider, chunk.Status, chunk.RowsIngested, finishedAt.Sub(startTime))
+}
+
+// fetchAndStore fetches the chunk's bars, drops invalid and frozen ones and
+// upserts the rest tagged with the backfill's source
+func (s *backfillService) fetchAndStore(ctx context.Context, provider fetcher.Provider, sourceID *uint64, chunk *model.BackfillChunk) error {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "BackfillService.fetchAndStore")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("provider", provider.Name()),
+		attribute.String("symbol", chunk.Symbol),
+		attribute.Int("attempt", chunk.Attempts),
+	)
+
+	bars, err := provider.Fetch(ctx, chunk.Symbol, chunk.StartDate, chunk.EndDate)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "fetch failed")
+		return fmt.Errorf("fetch failed: %w", err)
+	}
+
+	all, err := s.locks.FindAllLocks(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to load data locks")
+		return err
+	}
+	locks := newLockIndex(all)
+
+	// The provider's configured transforms, validated at startup
+	transforms, err := s.transforms.Chain(s.ingestion.TransformsFor(provider.Name()))
+	if err != nil {
+		return err
+	}
+
+	valid := bars[:0]
+	var invalid, frozen int
+	var firstInvalid error
+	for i := range bars {
+		bars[i].Symbol = chunk.Symbol
+		bars[i].SourceID = sourceID
+		err := validateBar(&bars[i])
+		if err == nil {
+			err = transforms.Apply(&bars[i])
+		}
+		if err != nil {
+			invalid++
+			if firstInvalid == nil {
+				firstInvalid = fmt.Errorf("%s: %w", bars[i].Date.Format("2006-01-02"), err)
+			}
+			continue
+		}
+		if locks.find(bars[i].Symbol, bars[i].Date) != nil {
+			frozen++
+			continue
+		}
+		valid = append(valid, bars[i])
+	}
+
+	chunk.RowsFetched = len(bars)
+	chunk.RowsIngested = 0
+	chunk.Error = ""
+	if invalid > 0 {
+		chunk.Error = fmt.Sprintf("%d bars failed validation, first: %v", invalid, firstInvalid)
+	}
+	if frozen > 0 {
+		chunk.Error = strings.TrimPrefix(chunk.Error+fmt.Sprintf("; %d bars skipped in frozen ranges", frozen), "; ")
+	}
+
+	if len(valid) > 0 {
+		if err := s.historical.BulkCreate(ctx, valid, 1000); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "database insert failed")
+			return fmt.Errorf("insert failed: %w", err)
+		}
+		chunk.RowsIngested = len(valid)
+		s.bus.Publish(ctx, newBarsIngestedEvent(valid))
+	}
+
+	span.SetAttributes(
+		attribute.Int("rows_fetched", chunk.RowsFetched),
+		attribute.Int("rows_ingested", chunk.RowsIngested),
+	)
+	return nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+)
+
+// CorporateActionService defines the interface for managing splits and dividends
+type CorporateActionService interface {
+	GetActions(ctx context.Context, symbol string) ([]model.CorporateAction, error)
+	CreateAction(ctx context.Context, symbol string, req *request.CreateCorporateActionRequest) (*model.CorporateAction, error)
+	DeleteAction(ctx context.Context, symbol string, id uint64) (*model.CorporateAction, error)
+}
+
+// corporateActionService implements CorporateActionService interface
+type corporateActionService struct {
+	repo repository.CorporateActionRepository
+	bus  events.Bus
+}
+
+// NewCorporateActionService creates a new corporate action service instance
+func NewCorporateActionService(repo repository.CorporateActionRepository, bus events.Bus) CorporateActionService {
+	return &corporateActionService{
+		repo: repo,
+		bus:  bus,
+	}
+}
+
+// GetActions lists the corporate actions of a symbol ordered by ex-date
+func (s *corporateActionService) GetActions(ctx context.Context, symbol string) ([]model.CorporateAction, error) {
+	actions, err := s.repo.FindBySymbols(ctx, []string{strings.ToUpper(symbol)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get corporate actions: %w", err)
+	}
+	return actions, nil
+}
+
+// CreateAction records a corporate action, replacing the action of the same
+// type on the same ex-date
+func (s *corporateActionService) CreateAction(ctx context.Context, symbol string, req *request.CreateCorporateActionRequest) (*model.CorporateAction, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	action := &model.CorporateAction{
+		Symbol: strings.ToUpper(symbol),
+		Type:   req.Type,
+		ExDate: req.ExDate,
+	}
+	switch req.Type {
+	case model.CorporateActionSplit:
+		action.Ratio = req.Ratio
+	case model.CorporateActionDividend:
+		action.Amount = req.Amount
+	}
+
+	if err := s.repo.Upsert(ctx, action); err != nil {
+		return nil, fmt.Errorf("failed to create corporate action: %w", err)
+	}
+
+	s.publishChanged(ctx, action)
+	return action, nil
+}
+
+// DeleteAction deletes a corporate action of a symbol, returning nil when not found
+func (s *corporateActionService) DeleteAction(ctx context.Context, symbol string, id uint64) (*model.CorporateAction, error) {
+	action, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete corporate action: %w", err)
+	}
+	if action == nil || action.Symbol != strings.ToUpper(symbol) {
+		return nil, nil
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to delete corporate action: %w", err)
+	}
+
+	s.publishChanged(ctx, action)
+	return action, nil
+}
+
+// publishChanged announces that adjusted series of the action's symbol changed
+func (s *corporateActionService) publishChanged(ctx context.Context, action *model.CorporateAction) {
+	s.bus.Publish(ctx, events.CorporateActionChanged{
+		Symbol:     action.Symbol,
+		Type:       action.Type,
+		ExDate:     action.ExDate,
+		OccurredAt: time.Now(),
+	})
+}
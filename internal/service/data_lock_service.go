@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+)
+
+// DataLockService defines the interface for freezing symbol and date ranges
+type DataLockService interface {
+	CreateLock(ctx context.Context, createdBy string, req *request.CreateDataLockRequest) (*model.DataLock, error)
+	GetLocks(ctx context.Context, req *request.GetDataLocksRequest) (*response.PaginatedDataLockResponse, error)
+	DeleteLock(ctx context.Context, id uint64) (*model.DataLock, error)
+}
+
+// dataLockService implements DataLockService interface
+type dataLockService struct {
+	repo repository.DataLockRepository
+}
+
+// NewDataLockService creates a new data lock service instance
+func NewDataLockService(repo repository.DataLockRepository) DataLockService {
+	return &dataLockService{
+		repo: repo,
+	}
+}
+
+// CreateLock freezes the bars of a symbol between two dates
+func (s *dataLockService) CreateLock(ctx context.Context, createdBy string, req *request.CreateDataLockRequest) (*model.DataLock, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	lock := &model.DataLock{
+		Symbol:    req.Symbol,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Reason:    req.Reason,
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.Create(ctx, lock); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// GetLocks lists data locks matching the request filters, ordered by symbol and start date
+func (s *dataLockService) GetLocks(ctx context.Context, req *request.GetDataLocksRequest) (*response.PaginatedDataLockResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"symbol": req.Symbol,
+		"date":   req.Date,
+	}
+
+	locks, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data locks: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedDataLockResponse{
+		Data: locks,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// DeleteLock unfreezes a range, returning nil when the lock does not exist
+func (s *dataLockService) DeleteLock(ctx context.Context, id uint64) (*model.DataLock, error) {
+	lock, err := s.repo.FindByID(ctx, id)
+	if err != nil || lock == nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to delete data lock: %w", err)
+	}
+	return lock, nil
+}
+
+// lockIndex groups data locks by symbol to check bars against them
+type lockIndex map[string][]model.DataLock
+
+// newLockIndex indexes locks by symbol
+func newLockIndex(locks []model.DataLock) lockIndex {
+	index := make(lockIndex)
+	for _, lock := range locks {
+		index[lock.Symbol] = append(index[lock.Symbol], lock)
+	}
+	return index
+}
+
+// find returns the lock covering a bar of symbol on date, or nil
+func (l lockIndex) find(symbol string, date time.Time) *model.DataLock {
+	locks := l[symbol]
+	for i := range locks {
+		if locks[i].Covers(symbol, date) {
+			return &locks[i]
+		}
+	}
+	return nil
+}
+
+// lockedBarError describes a bar rejected because it falls in a frozen range
+func lockedBarError(lock *model.DataLock) string {
+	return fmt.Sprintf("%s is frozen from %s to %s by lock %d",
+		lock.Symbol, lock.StartDate.Format("2006-01-02"), lock.EndDate.Format("2006-01-02"), lock.ID)
+}
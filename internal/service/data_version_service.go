@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-historical-data/internal/repository"
+)
+
+// DataVersionService defines the interface for the per-symbol data versions
+// behind ETags and analytics cache keys
+type DataVersionService interface {
+	// Bump increments the data version of the symbols after a write
+	Bump(ctx context.Context, symbols ...string) error
+	// Versions returns the data version of each symbol under the name it was
+	// requested with; a former name carries the version of the current symbol
+	Versions(ctx context.Context, symbols []string) (map[string]uint64, error)
+}
+
+// dataVersionService implements DataVersionService interface
+type dataVersionService struct {
+	repo     repository.SymbolVersionRepository
+	resolver SymbolResolver
+}
+
+// NewDataVersionService creates a new data version service instance
+func NewDataVersionService(repo repository.SymbolVersionRepository, resolver SymbolResolver) DataVersionService {
+	return &dataVersionService{
+		repo:     repo,
+		resolver: resolver,
+	}
+}
+
+// Bump increments the data version of the symbols
+func (s *dataVersionService) Bump(ctx context.Context, symbols ...string) error {
+	if err := s.repo.Bump(ctx, symbols); err != nil {
+		return fmt.Errorf("failed to bump data versions: %w", err)
+	}
+	return nil
+}
+
+// Versions resolves the symbols in bulk and reads the versions of the current symbols
+func (s *dataVersionService) Versions(ctx context.Context, symbols []string) (map[string]uint64, error) {
+	resolved, err := s.resolver.ResolveMap(ctx, symbols)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data versions: %w", err)
+	}
+	current := make([]string, 0, len(resolved))
+	for _, symbol := range symbols {
+		current = append(current, resolved[symbol])
+	}
+
+	found, err := s.repo.FindBySymbols(ctx, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data versions: %w", err)
+	}
+	versions := make(map[string]uint64, len(symbols))
+	for _, symbol := range symbols {
+		versions[symbol] = found[resolved[symbol]]
+	}
+	return versions, nil
+}
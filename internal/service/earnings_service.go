@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/csvparser"
+	"github.com/shopspring/decimal"
+)
+
+// EarningsService defines the interface for the earnings calendar
+type EarningsService interface {
+	Upload(ctx context.Context, req *request.UploadEarningsRequest) (*response.EarningsUploadResponse, error)
+	GetEarnings(ctx context.Context, req *request.GetEarningsRequest) (*response.PaginatedEarningsResponse, error)
+}
+
+// earningsService implements EarningsService interface
+type earningsService struct {
+	repo      repository.EarningsRepository
+	resolver  SymbolResolver
+	bus       events.Bus
+	batchSize int
+}
+
+// NewEarningsService creates a new earnings service instance. Uploads are
+// stored in batches of batchSize and publish EarningsChanged on bus.
+func NewEarningsService(repo repository.EarningsRepository, resolver SymbolResolver, bus events.Bus, batchSize int) EarningsService {
+	return &earningsService{
+		repo:      repo,
+		resolver:  resolver,
+		bus:       bus,
+		batchSize: max(batchSize, 1),
+	}
+}
+
+// Upload records the events of the body, replacing the report date of the
+// same symbol and period. The body is stored whole or not at all, so a single
+// invalid period rejects it.
+func (s *earningsService) Upload(ctx context.Context, req *request.UploadEarningsRequest) (*response.EarningsUploadResponse, error) {
+	earnings := make([]model.EarningsEvent, len(req.Events))
+	var symbols []string
+	for i, input := range req.Events {
+		period, err := csvparser.NormalizeFiscalPeriod(input.Period)
+		if err != nil {
+			return nil, &request.ValidationError{
+				Field:   "period",
+				Message: fmt.Sprintf("events[%d]: period must be a quarter such as 2024Q1 or a fiscal year such as 2024FY", i),
+			}
+		}
+		symbol := strings.ToUpper(strings.TrimSpace(input.Symbol))
+		if !slices.Contains(symbols, symbol) {
+			symbols = append(symbols, symbol)
+		}
+		y, m, d := input.ReportDate.Date()
+		earnings[i] = model.EarningsEvent{
+			Symbol:      symbol,
+			Period:      period,
+			ReportDate:  time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+			EPSEstimate: input.EPSEstimate,
+			EPSActual:   input.EPSActual,
+		}
+	}
+
+	if err := s.repo.BulkUpsert(ctx, earnings, s.batchSize); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.EarningsChanged{
+		Symbols:    symbols,
+		Count:      len(earnings),
+		OccurredAt: time.Now(),
+	})
+
+	return &response.EarningsUploadResponse{
+		Count:   len(earnings),
+		Symbols: symbols,
+		Message: "Earnings events stored successfully",
+	}, nil
+}
+
+// GetEarnings lists earnings events in calendar order
+func (s *earningsService) GetEarnings(ctx context.Context, req *request.GetEarningsRequest) (*response.PaginatedEarningsResponse, error) {
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	symbol, err := s.resolver.Resolve(ctx, strings.ToUpper(req.Symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	filters := map[string]interface{}{
+		"symbol":     symbol,
+		"start_date": req.StartDate,
+		"end_date":   req.EndDate,
+	}
+
+	earnings, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get earnings events: %w", err)
+	}
+
+	data := make([]response.EarningsEventResponse, len(earnings))
+	for i := range earnings {
+		data[i] = toEarningsEventResponse(&earnings[i])
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedEarningsResponse{
+		Data: data,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// surprisePct returns by how much the actual EPS beat the estimate, in percent
+// of the estimate's magnitude; nil unless both are known and the estimate is
+// not zero
+func surprisePct(estimate, actual decimal.NullDecimal) *decimal.Decimal {
+	if !estimate.Valid || !actual.Valid || estimate.Decimal.IsZero() {
+		return nil
+	}
+	pct := actual.Decimal.Sub(estimate.Decimal).Div(estimate.Decimal.Abs()).Mul(decimal.NewFromInt(100)).Round(2)
+	return &pct
+}
+
+// toEarningsEventResponse converts model to response DTO
+func toEarningsEventResponse(event *model.EarningsEvent) response.EarningsEventResponse {
+	return response.EarningsEventResponse{
+		ID:          event.ID,
+		Symbol:      event.Symbol,
+		Period:      event.Period,
+		ReportDate:  event.ReportDate.Format("2006-01-02"),
+		EPSEstimate: event.EPSEstimate,
+		EPSActual:   event.EPSActual,
+		SurprisePct: surprisePct(event.EPSEstimate, event.EPSActual),
+	}
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/entitlement"
+	"github.com/go-historical-data/internal/export"
+	"github.com/go-historical-data/internal/features"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/internal/storage"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ExportService defines the interface for bulk export jobs
+type ExportService interface {
+	CreateExport(ctx context.Context, tenant, apiKey string, req *request.CreateExportRequest) (*model.ExportJob, error)
+	GetExport(ctx context.Context, id uint64) (*response.ExportJobResponse, error)
+	GetExports(ctx context.Context, tenant string, req *request.GetExportsRequest) (*response.PaginatedExportJobResponse, error)
+	// Run produces pending exports and removes expired artifacts until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// exportService implements ExportService interface
+type exportService struct {
+	repo       repository.ExportJobRepository
+	historical repository.HistoricalRepository
+	symbols    repository.SymbolRepository
+	resolver   SymbolResolver
+	store      storage.Store
+	flags      *features.Flags
+	cfg        config.ExportsConfig
+	wake       chan struct{}
+}
+
+// artifactWriter writes the records of an export artifact
+type artifactWriter interface {
+	Write(data *response.HistoricalDataResponse) error
+}
+
+// NewExportService creates a new export service instance keeping artifacts in store
+func NewExportService(repo repository.ExportJobRepository, historical repository.HistoricalRepository, symbols repository.SymbolRepository, resolver SymbolResolver, store storage.Store, flags *features.Flags, cfg config.ExportsConfig) ExportService {
+	return &exportService{
+		repo:       repo,
+		historical: historical,
+		symbols:    symbols,
+		resolver:   resolver,
+		store:      store,
+		flags:      flags,
+		cfg:        cfg,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// CreateExport queues an export of the requested symbols and of the symbols
+// carrying the requested tag when the export is created
+func (s *exportService) CreateExport(ctx context.Context, tenant, apiKey string, req *request.CreateExportRequest) (*model.ExportJob, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "ExportService.CreateExport")
+	defer span.End()
+
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Tag != "" {
+		tagged, err := s.symbols.FindTagged(ctx, req.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if len(tagged) == 0 {
+			return nil, &request.ValidationError{Field: "tag", Message: fmt.Sprintf("no symbol carries the tag %q", req.Tag)}
+		}
+		for _, symbol := range tagged {
+			if !slices.Contains(req.Symbols, symbol) {
+				req.Symbols = append(req.Symbols, symbol)
+			}
+		}
+	}
+	if s.cfg.MaxSymbols > 0 && len(req.Symbols) > s.cfg.MaxSymbols {
+		return nil, &request.ValidationError{
+			Field:   "symbols",
+			Message: fmt.Sprintf("at most %d symbols are allowed per export", s.cfg.MaxSymbols),
+		}
+	}
+	if req.Format == model.ExportFormatParquet && !s.flags.Enabled(features.ParquetExports, true) {
+		return nil, &request.ValidationError{Field: "format", Message: "parquet exports are disabled"}
+	}
+
+	symbols, err := s.resolver.ResolveAll(ctx, req.Symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("format", req.Format),
+		attribute.Int("symbol_count", len(symbols)),
+	)
+
+	job := &model.ExportJob{
+		Tenant:  tenant,
+		APIKey:  apiKey,
+		Symbols: strings.Join(symbols, ","),
+		Format:  req.Format,
+		Status:  model.ExportStatusPending,
+	}
+	if !req.StartDate.IsZero() {
+		job.StartDate = &req.StartDate
+	}
+	if !req.EndDate.IsZero() {
+		job.EndDate = &req.EndDate
+	}
+	// The worker runs without the caller's context, so a delayed key's window
+	// is recorded as the end of the export
+	if through, ok := entitlement.Through(ctx); ok && (job.EndDate == nil || job.EndDate.After(through)) {
+		job.EndDate = &through
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create export")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int64("export_id", int64(job.ID)))
+
+	// Wake the worker without blocking; a pending signal already covers this export
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// GetExport retrieves an export job, returning nil when not found. Completed
+// exports get a download link valid for url_ttl seconds, or until the artifact
+// expires when that is sooner.
+func (s *exportService) GetExport(ctx context.Context, id uint64) (*response.ExportJobResponse, error) {
+	job, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export: %w", err)
+	}
+	if job == nil {
+		return nil, nil
+	}
+
+	result := &response.ExportJobResponse{ExportJob: *job}
+
+	now := time.Now()
+	if job.Status != model.ExportStatusCompleted || job.ExpiresAt == nil || !job.ExpiresAt.After(now) {
+		return result, nil
+	}
+	expires := now.Add(time.Duration(s.cfg.URLTTL) * time.Second)
+	if job.ExpiresAt.Before(expires) {
+		expires = *job.ExpiresAt
+	}
+	url, err := s.store.URL(job.ArtifactKey, expires)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign download link: %w", err)
+	}
+	result.DownloadURL = url
+	result.DownloadExpiresAt = &expires
+
+	return result, nil
+}
+
+// GetExports lists export jobs of a tenant, newest first. An empty tenant
+// lists jobs of all tenants.
+func (s *exportService) GetExports(ctx context.Context, tenant string, req *request.GetExportsRequest) (*response.PaginatedExportJobResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"tenant": tenant,
+		"status": req.Status,
+	}
+
+	jobs, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exports: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedExportJobResponse{
+		Data: jobs,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// Run produces unfinished exports oldest first, waking on new exports and
+// every poll interval, when expired artifacts are removed too. Exports left
+// running by a previous process are produced again; only one API instance
+// should run exports.
+func (s *exportService) Run(ctx context.Context) {
+	log := logger.GetGlobalLogger()
+
+	ticker := time.NewTicker(time.Duration(max(s.cfg.PollInterval, 1)) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		if err := s.purgeExpired(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("Failed to remove expired export artifacts")
+		}
+
+		for ctx.Err() == nil {
+			job, err := s.repo.FindNextUnfinished(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to find unfinished exports")
+				break
+			}
+			if job == nil {
+				break
+			}
+			if err := s.process(ctx, job); err != nil {
+				if ctx.Err() == nil {
+					log.Error().Err(err).Uint64("export_id", job.ID).Msg("Export processing failed")
+				}
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// process produces and stores the artifact of an export. A failure to build
+// or store the artifact fails the export; only errors recording its state are
+// returned.
+func (s *exportService) process(ctx context.Context, job *model.ExportJob) error {
+	startTime := time.Now()
+	job.Status = model.ExportStatusRunning
+	job.StartedAt = &startTime
+	if err := s.repo.Update(ctx, job); err != nil {
+		return err
+	}
+
+	rows, size, key, err := s.produce(ctx, job)
+	if ctx.Err() != nil {
+		// Left running, so the export is produced again on the next start
+		return ctx.Err()
+	}
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err != nil {
+		job.Status = model.ExportStatusFailed
+		job.Error = err.Error()
+		return s.repo.Update(ctx, job)
+	}
+
+	expiresAt := finishedAt.Add(time.Duration(s.cfg.Retention) * time.Hour)
+	job.Status = model.ExportStatusCompleted
+	job.RowCount = rows
+	job.SizeBytes = size
+	job.ArtifactKey = key
+	job.ExpiresAt = &expiresAt
+	return s.repo.Update(ctx, job)
+}
+
+// produce writes the artifact to a temporary file and stores it, returning the
+// rows written, the artifact size and its key
+func (s *exportService) produce(ctx context.Context, job *model.ExportJob) (int64, int64, string, error) {
+	file, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	rows, err := s.writeArtifact(ctx, job, file)
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, "", err
+	}
+
+	key := fmt.Sprintf("exports/%d.csv.gz", job.ID)
+	contentType := "application/gzip"
+	if job.Format == model.ExportFormatParquet {
+		key = fmt.Sprintf("exports/%d.parquet", job.ID)
+		contentType = export.ContentTypeParquet
+	}
+	if err := s.store.Put(ctx, key, file, size, contentType); err != nil {
+		return 0, 0, "", err
+	}
+	return rows, size, key, nil
+}
+
+// writeArtifact writes the bars of every symbol of the export in the job's
+// format, symbol by symbol in ascending date order
+func (s *exportService) writeArtifact(ctx context.Context, job *model.ExportJob, w io.Writer) (int64, error) {
+	var writer artifactWriter
+	var finish func() error
+	switch job.Format {
+	case model.ExportFormatParquet:
+		pw := export.NewParquetWriter(w)
+		writer, finish = pw, pw.Close
+	default:
+		gz := gzip.NewWriter(w)
+		cw := export.NewCSVWriter(gz, export.DefaultCSVColumns)
+		if err := cw.WriteHeader(); err != nil {
+			return 0, err
+		}
+		writer = cw
+		finish = func() error {
+			if err := cw.Flush(); err != nil {
+				return err
+			}
+			return gz.Close()
+		}
+	}
+
+	var start, end time.Time
+	if job.StartDate != nil {
+		start = *job.StartDate
+	}
+	if job.EndDate != nil {
+		end = *job.EndDate
+	}
+
+	var rows int64
+	for _, symbol := range strings.Split(job.Symbols, ",") {
+		for row, err := range s.historical.FindBySymbolStream(ctx, symbol, start, end) {
+			if err != nil {
+				return 0, err
+			}
+			data := toHistoricalDataResponse(&row)
+			if err := writer.Write(&data); err != nil {
+				return 0, fmt.Errorf("failed to write export: %w", err)
+			}
+			rows++
+		}
+	}
+
+	if err := finish(); err != nil {
+		return 0, fmt.Errorf("failed to write export: %w", err)
+	}
+	return rows, nil
+}
+
+// purgeExpired removes the artifacts of expired exports
+func (s *exportService) purgeExpired(ctx context.Context) error {
+	jobs, err := s.repo.FindExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		if err := s.store.Delete(ctx, jobs[i].ArtifactKey); err != nil {
+			return err
+		}
+		jobs[i].Status = model.ExportStatusExpired
+		if err := s.repo.Update(ctx, &jobs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
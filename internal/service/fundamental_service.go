@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/csvparser"
+	"github.com/go-historical-data/pkg/i18n"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ttmQuarterSpan is the longest span between the period ends of the first and
+// fourth of the quarters summed into a trailing EPS; three quarters apart
+// plus slack for 52/53-week fiscal calendars
+const ttmQuarterSpan = 285 * 24 * time.Hour
+
+// snapshotPeriods is the number of latest periods read to build a snapshot,
+// enough for four quarters between two fiscal years
+const snapshotPeriods = 8
+
+// FundamentalService defines the interface for fundamentals operations
+type FundamentalService interface {
+	ValidateUpload(info UploadInfo) error
+	UploadCSV(ctx context.Context, reader io.Reader, info UploadInfo) (*response.FundamentalUploadResponse, error)
+	Upload(ctx context.Context, req *request.UploadFundamentalsRequest, info UploadInfo) (*response.FundamentalUploadResponse, error)
+	GetFundamentals(ctx context.Context, symbol string, req *request.GetFundamentalsRequest) (*response.FundamentalsResponse, error)
+	Snapshot(ctx context.Context, symbol string, price decimal.Decimal, priceDate time.Time) (*response.FundamentalsSnapshot, error)
+}
+
+// fundamentalService implements FundamentalService interface
+type fundamentalService struct {
+	repo       repository.FundamentalRepository
+	historical repository.HistoricalRepository
+	sources    repository.SourceRepository
+	resolver   SymbolResolver
+	cfg        config.IngestionConfig
+}
+
+// NewFundamentalService creates a new fundamental service instance. Uploads
+// use the configured ingestion batch size and file size limit; snapshots are
+// valued at the latest stored close.
+func NewFundamentalService(repo repository.FundamentalRepository, historical repository.HistoricalRepository, sources repository.SourceRepository, resolver SymbolResolver, cfg config.IngestionConfig) FundamentalService {
+	return &fundamentalService{
+		repo:       repo,
+		historical: historical,
+		sources:    sources,
+		resolver:   resolver,
+		cfg:        cfg,
+	}
+}
+
+// ValidateUpload checks the file size against the configured limit
+func (s *fundamentalService) ValidateUpload(info UploadInfo) error {
+	if s.cfg.MaxFileSize > 0 && info.FileSize > s.cfg.MaxFileSize {
+		return &FileTooLargeError{Size: info.FileSize, Limit: s.cfg.MaxFileSize}
+	}
+	return nil
+}
+
+// UploadCSV stores the periods of a symbol,period,period_end file. Invalid
+// rows are reported and skipped; a period replaces the stored figures of the
+// same symbol and period, keeping those the file leaves empty.
+func (s *fundamentalService) UploadCSV(ctx context.Context, reader io.Reader, info UploadInfo) (*response.FundamentalUploadResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "FundamentalService.UploadCSV")
+	defer span.End()
+
+	parser := csvparser.NewFundamentalsParser(reader)
+	parser.SetLanguage(i18n.FromContext(ctx))
+	if err := parser.ParseHeader(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid CSV header")
+		return nil, &request.ValidationError{Field: "file", Message: fmt.Sprintf("invalid CSV header: %v", err)}
+	}
+
+	source, err := s.createSource(ctx, info)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create source")
+		return nil, err
+	}
+
+	batchSize := max(s.cfg.BatchSize, 1)
+	result := &response.FundamentalUploadResponse{SourceID: source.ID}
+	var rowErrors []response.CSVRowError
+	var symbols []string
+	batch := make([]model.Fundamental, 0, batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.repo.BulkUpsert(ctx, batch, batchSize); err != nil {
+			result.FailedCount += len(batch)
+			rowErrors = append(rowErrors, response.CSVRowError{Code: RowErrorBatchInsert, Message: err.Error()})
+		} else {
+			result.SuccessCount += len(batch)
+			for i := range batch {
+				if !slices.Contains(symbols, batch[i].Symbol) {
+					symbols = append(symbols, batch[i].Symbol)
+				}
+			}
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		row, err := parser.ParseRow()
+		if err == io.EOF {
+			break
+		}
+		result.TotalRows++
+		if err != nil {
+			result.FailedCount++
+			rowErrors = append(rowErrors, parseRowError(err, parser.GetCurrentLine()))
+			continue
+		}
+
+		batch = append(batch, model.Fundamental{
+			Symbol:            row.Symbol,
+			Period:            row.Period,
+			PeriodEnd:         row.PeriodEnd,
+			EPS:               row.EPS,
+			Revenue:           row.Revenue,
+			NetIncome:         row.NetIncome,
+			SharesOutstanding: row.SharesOutstanding,
+			SourceID:          &source.ID,
+		})
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	// Limit the listed errors to avoid huge responses
+	result.OmittedErrors = max(len(rowErrors)-maxReportedErrors, 0)
+	result.Errors = rowErrors[:len(rowErrors)-result.OmittedErrors]
+	result.Symbols = symbols
+
+	result.Message = "CSV file processed successfully"
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("CSV file processed with %d errors", result.FailedCount)
+	}
+
+	span.SetAttributes(
+		attribute.Int("total_rows", result.TotalRows),
+		attribute.Int("success_count", result.SuccessCount),
+		attribute.Int("failed_count", result.FailedCount),
+	)
+	span.SetStatus(codes.Ok, result.Message)
+
+	return result, nil
+}
+
+// Upload stores the periods of a JSON body. Unlike a CSV upload the body is
+// stored whole or not at all, so a single invalid period rejects it.
+func (s *fundamentalService) Upload(ctx context.Context, req *request.UploadFundamentalsRequest, info UploadInfo) (*response.FundamentalUploadResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "FundamentalService.Upload")
+	defer span.End()
+
+	fundamentals := make([]model.Fundamental, len(req.Fundamentals))
+	var symbols []string
+	for i, input := range req.Fundamentals {
+		period, err := csvparser.NormalizeFiscalPeriod(input.Period)
+		if err != nil {
+			return nil, &request.ValidationError{
+				Field:   "period",
+				Message: fmt.Sprintf("fundamentals[%d]: period must be a quarter such as 2024Q1 or a fiscal year such as 2024FY", i),
+			}
+		}
+		symbol := strings.ToUpper(strings.TrimSpace(input.Symbol))
+		if !slices.Contains(symbols, symbol) {
+			symbols = append(symbols, symbol)
+		}
+		y, m, d := input.PeriodEnd.Date()
+		fundamentals[i] = model.Fundamental{
+			Symbol:            symbol,
+			Period:            period,
+			PeriodEnd:         time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+			EPS:               input.EPS,
+			Revenue:           input.Revenue,
+			NetIncome:         input.NetIncome,
+			SharesOutstanding: input.SharesOutstanding,
+		}
+	}
+
+	source, err := s.createSource(ctx, info)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create source")
+		return nil, err
+	}
+	for i := range fundamentals {
+		fundamentals[i].SourceID = &source.ID
+	}
+
+	if err := s.repo.BulkUpsert(ctx, fundamentals, max(s.cfg.BatchSize, 1)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to store fundamentals")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("count", len(fundamentals)))
+	return &response.FundamentalUploadResponse{
+		SourceID:     source.ID,
+		TotalRows:    len(fundamentals),
+		SuccessCount: len(fundamentals),
+		Symbols:      symbols,
+		Message:      "Fundamentals stored successfully",
+	}, nil
+}
+
+// GetFundamentals lists the latest periods of a symbol along with a snapshot
+// valued at its latest stored close
+func (s *fundamentalService) GetFundamentals(ctx context.Context, symbol string, req *request.GetFundamentalsRequest) (*response.FundamentalsResponse, error) {
+	req.SetDefaults()
+
+	symbol, err := s.resolver.Resolve(ctx, strings.ToUpper(symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	filters := map[string]interface{}{"period_type": req.PeriodType}
+	fundamentals, err := s.repo.FindBySymbol(ctx, symbol, filters, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.FundamentalsResponse{
+		Symbol:  symbol,
+		Periods: make([]response.FundamentalResponse, len(fundamentals)),
+	}
+	for i := range fundamentals {
+		result.Periods[i] = toFundamentalResponse(&fundamentals[i])
+	}
+
+	latest, err := s.historical.FindLatestBySymbols(ctx, []string{symbol}, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest close: %w", err)
+	}
+	if len(latest) > 0 {
+		if result.Latest, err = s.Snapshot(ctx, symbol, latest[0].Close, latest[0].Date); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Snapshot values the periods of symbol reported by priceDate at price: the
+// trailing EPS is the sum of the last four quarters when they are all
+// reported, else the EPS of the last fiscal year. It returns nil when the
+// symbol has no fundamentals by then.
+func (s *fundamentalService) Snapshot(ctx context.Context, symbol string, price decimal.Decimal, priceDate time.Time) (*response.FundamentalsSnapshot, error) {
+	filters := map[string]interface{}{"as_of": priceDate}
+	fundamentals, err := s.repo.FindBySymbol(ctx, symbol, filters, snapshotPeriods)
+	if err != nil {
+		return nil, err
+	}
+	if len(fundamentals) == 0 {
+		return nil, nil
+	}
+
+	snapshot := &response.FundamentalsSnapshot{
+		Period:    fundamentals[0].Period,
+		PeriodEnd: fundamentals[0].PeriodEnd.Format("2006-01-02"),
+		Price:     price,
+		PriceDate: priceDate.Format("2006-01-02"),
+	}
+
+	for i := range fundamentals {
+		if fundamentals[i].SharesOutstanding != nil {
+			snapshot.SharesOutstanding = fundamentals[i].SharesOutstanding
+			break
+		}
+	}
+
+	if eps, ok := trailingEPS(fundamentals); ok {
+		snapshot.EPSTTM = &eps
+		if eps.IsPositive() {
+			pe := price.Div(eps).Round(4)
+			snapshot.PE = &pe
+		}
+	}
+	if snapshot.SharesOutstanding != nil {
+		marketCap := price.Mul(decimal.NewFromUint64(*snapshot.SharesOutstanding)).Round(2)
+		snapshot.MarketCap = &marketCap
+	}
+
+	return snapshot, nil
+}
+
+// trailingEPS sums the EPS of the latest four quarters, latest period end
+// first, when they all report one within ttmQuarterSpan; otherwise it falls
+// back to the latest fiscal year reporting one
+func trailingEPS(fundamentals []model.Fundamental) (decimal.Decimal, bool) {
+	var quarters []*model.Fundamental
+	for i := range fundamentals {
+		if !fundamentals[i].IsAnnual() && len(quarters) < 4 {
+			quarters = append(quarters, &fundamentals[i])
+		}
+	}
+	if len(quarters) == 4 && quarters[0].PeriodEnd.Sub(quarters[3].PeriodEnd) <= ttmQuarterSpan {
+		sum := decimal.Zero
+		complete := true
+		for _, q := range quarters {
+			if !q.EPS.Valid {
+				complete = false
+				break
+			}
+			sum = sum.Add(q.EPS.Decimal)
+		}
+		if complete {
+			return sum, true
+		}
+	}
+
+	for i := range fundamentals {
+		if fundamentals[i].IsAnnual() && fundamentals[i].EPS.Valid {
+			return fundamentals[i].EPS.Decimal, true
+		}
+	}
+	return decimal.Decimal{}, false
+}
+
+// createSource records the upload every stored period points back at
+func (s *fundamentalService) createSource(ctx context.Context, info UploadInfo) (*model.Source, error) {
+	source := &model.Source{
+		Kind:   model.SourceKindUpload,
+		Name:   info.Filename,
+		Tenant: info.Tenant,
+	}
+	if err := s.sources.Create(ctx, source); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// toFundamentalResponse converts model to response DTO
+func toFundamentalResponse(f *model.Fundamental) response.FundamentalResponse {
+	return response.FundamentalResponse{
+		Period:            f.Period,
+		PeriodEnd:         f.PeriodEnd.Format("2006-01-02"),
+		EPS:               f.EPS,
+		Revenue:           f.Revenue,
+		NetIncome:         f.NetIncome,
+		SharesOutstanding: f.SharesOutstanding,
+		SourceID:          f.SourceID,
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/apperror"
+)
+
+// fxLookbackDays bounds how far a rate is carried forward over days without
+// a stored FX bar (weekends, holidays)
+const fxLookbackDays = 7
+
+// CurrencyConversionError is returned when rows cannot be converted to the
+// requested currency because symbol metadata or FX rates are missing
+type CurrencyConversionError struct {
+	Message string
+}
+
+func (e *CurrencyConversionError) Error() string {
+	return e.Message
+}
+
+// AppError answers the error with 400
+func (e *CurrencyConversionError) AppError() *apperror.Error {
+	return apperror.BadRequest(e.Message, nil)
+}
+
+// CurrencyConverter converts stored prices between currencies
+type CurrencyConverter interface {
+	// Convert converts the OHLC prices of rows in place to the target currency
+	Convert(ctx context.Context, rows []model.HistoricalData, target string) error
+	// Pairs returns the symbols of the FX pairs converting the symbols to the
+	// target currency may read, whose bars the converted prices depend on
+	Pairs(ctx context.Context, symbols []string, target string) ([]string, error)
+}
+
+// currencyConverter implements CurrencyConverter interface
+type currencyConverter struct {
+	repo    repository.HistoricalRepository
+	symbols repository.SymbolRepository
+}
+
+// NewCurrencyConverter creates a converter reading symbol currencies from
+// symbols and FX rates from repo
+func NewCurrencyConverter(repo repository.HistoricalRepository, symbols repository.SymbolRepository) CurrencyConverter {
+	return &currencyConverter{
+		repo:    repo,
+		symbols: symbols,
+	}
+}
+
+// fxRates holds the daily closes of a currency pair in ascending date order
+type fxRates struct {
+	dates []time.Time
+	rates []float64
+}
+
+// at returns the rate of the given day or the closest earlier day within the lookback
+func (r *fxRates) at(date time.Time) (float64, bool) {
+	i := sort.Search(len(r.dates), func(i int) bool { return r.dates[i].After(date) }) - 1
+	if i < 0 || date.Sub(r.dates[i]) > fxLookbackDays*24*time.Hour {
+		return 0, false
+	}
+	return r.rates[i], true
+}
+
+// Convert implements CurrencyConverter. Each symbol's currency comes from its
+// metadata and rates from the stored series of the <FROM><TO> pair (e.g.
+// EURUSD), or the inverted <TO><FROM> pair when only that one is stored.
+func (s *currencyConverter) Convert(ctx context.Context, rows []model.HistoricalData, target string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	symbols := make([]string, 0)
+	seen := make(map[string]bool)
+	start, end := rows[0].Date, rows[0].Date
+	for i := range rows {
+		if !seen[rows[i].Symbol] {
+			seen[rows[i].Symbol] = true
+			symbols = append(symbols, rows[i].Symbol)
+		}
+		if rows[i].Date.Before(start) {
+			start = rows[i].Date
+		}
+		if rows[i].Date.After(end) {
+			end = rows[i].Date
+		}
+	}
+
+	metadata, err := s.symbols.FindBySymbols(ctx, symbols)
+	if err != nil {
+		return err
+	}
+	currencies := make(map[string]string, len(metadata))
+	for i := range metadata {
+		currencies[metadata[i].Symbol] = metadata[i].Currency
+	}
+	for _, symbol := range symbols {
+		if currencies[symbol] == "" {
+			return &CurrencyConversionError{Message: fmt.Sprintf("currency of symbol %s is unknown", symbol)}
+		}
+	}
+
+	pairs := make(map[string]*fxRates)
+	for i := range rows {
+		from := currencies[rows[i].Symbol]
+		if from == target {
+			continue
+		}
+
+		rates, ok := pairs[from]
+		if !ok {
+			rates, err = s.loadFXRates(ctx, from, target, start.AddDate(0, 0, -fxLookbackDays), end)
+			if err != nil {
+				return err
+			}
+			pairs[from] = rates
+		}
+
+		rate, ok := rates.at(rows[i].Date)
+		if !ok {
+			return &CurrencyConversionError{Message: fmt.Sprintf("no %s/%s rate on or before %s", from, target, rows[i].Date.Format("2006-01-02"))}
+		}
+		rows[i].ScalePrices(rate)
+	}
+	return nil
+}
+
+// Pairs implements CurrencyConverter, returning both the <FROM><TO> pair and
+// the inverted <TO><FROM> pair of each currency. Symbols of unknown currency
+// are skipped, as Convert fails for them.
+func (s *currencyConverter) Pairs(ctx context.Context, symbols []string, target string) ([]string, error) {
+	metadata, err := s.symbols.FindBySymbols(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []string
+	seen := make(map[string]bool)
+	for i := range metadata {
+		from := metadata[i].Currency
+		if from == "" || from == target || seen[from] {
+			continue
+		}
+		seen[from] = true
+		pairs = append(pairs, from+target, target+from)
+	}
+	sort.Strings(pairs)
+	return pairs, nil
+}
+
+// loadFXRates loads the closes converting from into to between start and end
+func (s *currencyConverter) loadFXRates(ctx context.Context, from, to string, start, end time.Time) (*fxRates, error) {
+	bars, err := s.repo.FindBySymbol(ctx, from+to, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	invert := false
+	if len(bars) == 0 {
+		if bars, err = s.repo.FindBySymbol(ctx, to+from, start, end); err != nil {
+			return nil, err
+		}
+		invert = true
+	}
+	if len(bars) == 0 {
+		return nil, &CurrencyConversionError{Message: fmt.Sprintf("no FX rates stored for %s%s or %s%s", from, to, to, from)}
+	}
+
+	rates := &fxRates{
+		dates: make([]time.Time, len(bars)),
+		rates: make([]float64, len(bars)),
+	}
+	for i := range bars {
+		rates.dates[i] = bars[i].Date
+		rates.rates[i] = bars[i].Close.InexactFloat64()
+		if invert {
+			rates.rates[i] = 1 / rates.rates[i]
+		}
+	}
+	return rates, nil
+}
@@ -2,37 +2,248 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-historical-data/internal/analytics"
 	"github.com/go-historical-data/internal/dto/request"
 	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/entitlement"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/ingest"
+	"github.com/go-historical-data/internal/middleware"
 	"github.com/go-historical-data/internal/model"
 	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/internal/synthetic"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/config"
 	"github.com/go-historical-data/pkg/csvparser"
+	"github.com/go-historical-data/pkg/i18n"
+	"github.com/go-historical-data/pkg/logger"
+	"github.com/shopspring/decimal"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 )
 
 // HistoricalService defines the interface for historical data business logic
 type HistoricalService interface {
-	UploadCSV(ctx context.Context, reader io.Reader, fileSize int64) (*response.CSVUploadResponse, error)
+	UploadCSV(ctx context.Context, reader io.Reader, info UploadInfo) (*response.CSVUploadResponse, error)
+	ValidateUpload(info UploadInfo) error
+	GenerateData(ctx context.Context, req *request.GenerateDataRequest, info UploadInfo) (*response.CSVUploadResponse, error)
 	GetHistoricalData(ctx context.Context, req *request.GetDataRequest) (*response.PaginatedHistoricalDataResponse, error)
-	GetHistoricalDataByID(ctx context.Context, id uint64) (*response.HistoricalDataResponse, error)
+	GetHistoricalDataByID(ctx context.Context, id uint64, req *request.GetDataByIDRequest) (*response.HistoricalDataResponse, error)
+	LookupData(ctx context.Context, req *request.LookupDataRequest) (*response.LookupDataResponse, error)
+	AsOf(ctx context.Context, symbol string, req *request.AsOfRequest) (*response.AsOfResponse, error)
+	AsOfBatch(ctx context.Context, req *request.AsOfBatchRequest) (*response.AsOfBatchResponse, error)
+	GetDataOnDate(ctx context.Context, date time.Time, req *request.DataOnDateRequest) (*response.PaginatedHistoricalDataResponse, error)
+	GetMovers(ctx context.Context, date time.Time, req *request.MoversRequest) (*response.MoversResponse, error)
+}
+
+// UploadInfo describes an uploaded file and who uploaded it
+type UploadInfo struct {
+	Filename string
+	FileSize int64
+	Tenant   string
+	APIKey   string
+
+	// OverrideLocks lets an admin upload into frozen symbol and date ranges
+	OverrideLocks bool
+
+	// Transforms names the transforms applied to every row, replacing the
+	// ones configured for the file name
+	Transforms []string
+
+	// CaptureAttributes keeps the unmapped columns of the file as bar
+	// attributes; the configured ingestion.capture_attributes also enables it
+	CaptureAttributes bool
+
+	// Overrides replaces the configured ingestion tuning for this upload; zero
+	// fields keep the configured value
+	Overrides config.IngestionConfig
+
+	// Resumable saves a checkpoint of the upload while its batches are stored,
+	// so that an interrupted upload can be continued with ResumeJobID
+	Resumable bool
+
+	// ResumeJobID continues an interrupted resumable upload of the same file
+	// from its checkpoint. The reader must be an io.ReadSeeker.
+	ResumeJobID uint64
+
+	// Staged lands the rows in the staging table, validates them there with
+	// set-based SQL and merges the valid ones in a single statement. The
+	// configured ingestion.staged and ingestion.staged_min_size also enable it.
+	Staged bool
+}
+
+// maxSampleErrors is the number of row errors reported with an aborted upload
+const maxSampleErrors = 10
+
+// maxReportedErrors is the number of row errors listed in an upload response
+const maxReportedErrors = 100
+
+// Stable codes of rows rejected after parsing; parse failures use the
+// csvparser codes
+const (
+	RowErrorHighBelowLow       = "high_below_low"
+	RowErrorOpenOutOfRange     = "open_out_of_range"
+	RowErrorCloseOutOfRange    = "close_out_of_range"
+	RowErrorFutureDate         = "future_date"
+	RowErrorNonPositivePrice   = "non_positive_price"
+	RowErrorTradesExceedVolume = "trades_exceed_volume"
+	RowErrorLocked             = "locked"
+	RowErrorTransformFailed    = "transform_failed"
+	RowErrorUnknownSymbol      = "unknown_symbol"
+	RowErrorSymbolLookup       = "symbol_lookup_failed"
+	RowErrorBatchInsert        = "batch_insert_failed"
+	RowErrorDuplicate          = "duplicate_row"
+	RowErrorStagingFailed      = "staging_failed"
+)
+
+// Handling of uploaded symbols without metadata, former names or stored bars
+// (ingestion.unknown_symbols)
+const (
+	// UnknownSymbolsRegister stores a metadata stub with the first rows of
+	// the symbol
+	UnknownSymbolsRegister = "register"
+	// UnknownSymbolsReject rejects the rows of the symbol, so typos never
+	// reach the table
+	UnknownSymbolsReject = "reject"
+	// UnknownSymbolsAllow stores the rows without metadata
+	UnknownSymbolsAllow = "allow"
+)
+
+// defaultStubCurrency is the currency of registered stubs when
+// ingestion.stub_currency is not set
+const defaultStubCurrency = "USD"
+
+// BarValidationError reports a bar that breaks a business rule, naming the
+// field at fault
+type BarValidationError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+func (e *BarValidationError) Error() string {
+	return e.Message
+}
+
+// AppError answers the error with 400
+func (e *BarValidationError) AppError() *apperror.Error {
+	return apperror.BadRequest(e.Message, nil)
+}
+
+// MalformedFileError is returned when an upload is aborted because too many
+// rows failed. Batches stored before the abort are kept.
+type MalformedFileError struct {
+	JobID        uint64
+	RowsRead     int
+	SuccessCount int
+	FailedCount  int
+	Reason       string
+	SampleErrors []response.CSVRowError
+}
+
+func (e *MalformedFileError) Error() string {
+	return "file appears malformed: " + e.Reason
+}
+
+// AppError answers the error with 422, the job and row counts and the first
+// row errors
+func (e *MalformedFileError) AppError() *apperror.Error {
+	return apperror.MalformedFile("File appears malformed: "+e.Reason, map[string]interface{}{
+		"job_id":        e.JobID,
+		"rows_read":     e.RowsRead,
+		"success_count": e.SuccessCount,
+		"failed_count":  e.FailedCount,
+		"errors":        e.SampleErrors,
+	})
+}
+
+// LockedDataError is returned when an upload touches frozen symbol and date
+// ranges without overriding the locks. The upload is rejected before any row
+// is stored.
+type LockedDataError struct {
+	LockedRows   int
+	Locks        []model.DataLock
+	SampleErrors []response.CSVRowError
+}
+
+func (e *LockedDataError) Error() string {
+	return fmt.Sprintf("%d rows fall in frozen date ranges", e.LockedRows)
+}
+
+// AppError answers the error with 409 and the locks at fault
+func (e *LockedDataError) AppError() *apperror.Error {
+	return apperror.Conflict("Upload touches frozen data, pass override_locks=true as an admin to replace it", map[string]interface{}{
+		"locked_rows": e.LockedRows,
+		"locks":       e.Locks,
+		"errors":      e.SampleErrors,
+	})
+}
+
+// ErrUploadNotResumable is returned when resuming an upload job that is not
+// an interrupted upload
+var ErrUploadNotResumable = errors.New("upload job cannot be resumed")
+
+// FileTooLargeError is returned when an upload exceeds the maximum file size
+type FileTooLargeError struct {
+	Size  int64
+	Limit int64
+}
+
+func (e *FileTooLargeError) Error() string {
+	return fmt.Sprintf("file size %d bytes exceeds the limit of %d bytes", e.Size, e.Limit)
+}
+
+// AppError answers the error with 413
+func (e *FileTooLargeError) AppError() *apperror.Error {
+	return apperror.PayloadTooLarge(fmt.Sprintf("File size %d bytes exceeds the limit of %d bytes", e.Size, e.Limit))
 }
 
 // historicalService implements HistoricalService interface
 type historicalService struct {
-	repo repository.HistoricalRepository
+	repo       repository.HistoricalRepository
+	staging    repository.StagingRepository
+	jobs       repository.UploadJobRepository
+	sources    repository.SourceRepository
+	locks      repository.DataLockRepository
+	earnings   repository.EarningsRepository
+	symbols    repository.SymbolRepository
+	converter  CurrencyConverter
+	adjuster   AdjustmentService
+	resolver   SymbolResolver
+	transforms *ingest.Registry
+	bus        events.Bus
+	cfg        config.IngestionConfig
+	reads      singleflight.Group // coalesces identical concurrent data queries
 }
 
 // NewHistoricalService creates a new historical service instance
-func NewHistoricalService(repo repository.HistoricalRepository) HistoricalService {
+func NewHistoricalService(repo repository.HistoricalRepository, staging repository.StagingRepository, jobs repository.UploadJobRepository, sources repository.SourceRepository, locks repository.DataLockRepository, earnings repository.EarningsRepository, symbols repository.SymbolRepository, converter CurrencyConverter, adjuster AdjustmentService, resolver SymbolResolver, transforms *ingest.Registry, bus events.Bus, cfg config.IngestionConfig) HistoricalService {
 	return &historicalService{
-		repo: repo,
+		repo:       repo,
+		staging:    staging,
+		jobs:       jobs,
+		sources:    sources,
+		locks:      locks,
+		earnings:   earnings,
+		symbols:    symbols,
+		converter:  converter,
+		adjuster:   adjuster,
+		resolver:   resolver,
+		transforms: transforms,
+		bus:        bus,
+		cfg:        cfg,
 	}
 }
 
@@ -50,6 +261,10 @@ func (s *historicalService) GetHistoricalData(ctx context.Context, req *request.
 		attribute.String("symbol", req.Symbol),
 		attribute.Int("page", req.Page),
 		attribute.Int("limit", req.Limit),
+		attribute.String("sort", req.Sort),
+		attribute.String("sort_dir", req.SortDir),
+		attribute.String("convert_to", req.ConvertTo),
+		attribute.String("adjustment", req.Adjustment),
 	)
 
 	// Validate date range
@@ -59,10 +274,69 @@ func (s *historicalService) GetHistoricalData(ctx context.Context, req *request.
 		return nil, err
 	}
 
+	// Former names of renamed symbols query the current symbol
+	symbol, err := s.resolver.Resolve(ctx, req.Symbol)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "database query failed")
+		return nil, err
+	}
+
+	// Identical concurrent queries, e.g. dashboards refreshing at market open,
+	// share one execution and its read-only result. The execution outlives a
+	// caller that gives up so the others still get their answer, but keeps the
+	// deadline of the request that started it so a stuck query is still cut off.
+	results := s.reads.DoChan(dataQueryKey(ctx, req, symbol), func() (interface{}, error) {
+		sharedCtx, cancel := withoutCancel(ctx)
+		defer cancel()
+		return s.getHistoricalData(sharedCtx, req, symbol)
+	})
+	select {
+	case res := <-results:
+		span.SetAttributes(attribute.Bool("coalesced", res.Shared))
+		if res.Shared {
+			middleware.RecordCoalescedRead("data_list")
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*response.PaginatedHistoricalDataResponse), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// withoutCancel returns a context carrying the values and deadline of ctx but
+// not its cancellation. context.WithoutCancel alone would drop the deadline set
+// by the request timeout.
+func withoutCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return detached, func() {}
+}
+
+// dataQueryKey identifies a normalized data query by everything that shapes
+// its result, the caller's entitlement window included
+func dataQueryKey(ctx context.Context, req *request.GetDataRequest, symbol string) string {
+	key := *req
+	key.Symbol = symbol
+	key.Format = ""
+	key.IncludeTotal = nil
+	key.Count = req.CountMode()
+	through, _ := entitlement.Through(ctx)
+	return fmt.Sprintf("%+v|%s", key, through.Format("2006-01-02"))
+}
+
+// getHistoricalData runs a normalized data query
+func (s *historicalService) getHistoricalData(ctx context.Context, req *request.GetDataRequest, symbol string) (*response.PaginatedHistoricalDataResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
 	// Build filters
 	filters := make(map[string]interface{})
-	if req.Symbol != "" {
-		filters["symbol"] = req.Symbol
+	if symbol != "" {
+		filters["symbol"] = symbol
 	}
 	if !req.StartDate.IsZero() {
 		filters["start_date"] = req.StartDate
@@ -70,24 +344,169 @@ func (s *historicalService) GetHistoricalData(ctx context.Context, req *request.
 	if !req.EndDate.IsZero() {
 		filters["end_date"] = req.EndDate
 	}
+	if req.Source != "" {
+		filters["source"] = req.Source
+	}
+	if req.SourceID != 0 {
+		filters["source_id"] = req.SourceID
+	}
+	if req.Tag != "" {
+		filters["tag"] = req.Tag
+	}
+	if req.MinOpenInterest != 0 {
+		filters["min_open_interest"] = req.MinOpenInterest
+	}
+	if req.MaxOpenInterest != 0 {
+		filters["max_open_interest"] = req.MaxOpenInterest
+	}
+	if req.MinNumberOfTrades != 0 {
+		filters["min_number_of_trades"] = req.MinNumberOfTrades
+	}
+	if req.MaxNumberOfTrades != 0 {
+		filters["max_number_of_trades"] = req.MaxNumberOfTrades
+	}
 
-	// Fetch from database
-	data, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	// Resolve sparse field selection (already validated above)
+	fields, _ := req.GetFields()
+	if len(fields) > 0 {
+		span.SetAttributes(attribute.StringSlice("fields", fields))
+	}
+
+	// Adjustment and conversion need the symbol and date of every row, and
+	// derived fields the prices and volume, even when they are not returned.
+	// Attributes are only read when requested.
+	columns := slices.Clone(fields)
+	if len(columns) > 0 {
+		var required []string
+		if req.Adjustment != "" || req.ConvertTo != "" {
+			required = append(required, "symbol", "date")
+		}
+		if req.WantsDerived() {
+			required = append(required, "symbol", "date", "high", "low", "close", "volume")
+		}
+		if req.WantsEarnings() {
+			required = append(required, "symbol", "date")
+		}
+		if req.WantsAttributes() {
+			required = append(required, "attributes")
+		}
+		if req.WantsFill() {
+			required = append(required, "symbol", "date", "close")
+		}
+		for _, column := range required {
+			if !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+
+	// Fetch from database. A filled query reads every bar of its range in
+	// date order and pages through the filled series instead.
+	limit, offset, opts := req.Limit, req.GetOffset(), repository.QueryOptions{
+		Fields:  columns,
+		SortBy:  req.Sort,
+		SortDir: req.SortDir,
+		Count:   req.CountMode(),
+	}
+	if req.WantsFill() {
+		limit, offset = request.MaxFillDays, 0
+		opts.SortDir, opts.Count = "asc", repository.CountNone
+	}
+	data, total, err := s.repo.FindAll(ctx, filters, limit, offset, opts)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "database query failed")
 		return nil, fmt.Errorf("failed to get historical data: %w", err)
 	}
 
+	// Forward filling starts from the last bar before the range, which is
+	// adjusted and converted along with the others
+	seeded := false
+	if req.Fill == response.FillForward {
+		previous, err := s.repo.FindAsOf(ctx, []repository.BarKey{{Symbol: symbol, Date: req.StartDate.AddDate(0, 0, -1)}})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "database query failed")
+			return nil, fmt.Errorf("failed to get historical data: %w", err)
+		}
+		if previous[0] != nil {
+			data = append([]model.HistoricalData{*previous[0]}, data...)
+			seeded = true
+		}
+	}
+
 	span.SetAttributes(
 		attribute.Int("total_records", int(total)),
 		attribute.Int("returned_records", len(data)),
 	)
 
+	// Adjust in the symbol's own currency, dividends are quoted in it
+	if req.Adjustment != "" {
+		if err := s.adjuster.Adjust(ctx, data, req.Adjustment); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "adjustment failed")
+			return nil, fmt.Errorf("failed to adjust historical data: %w", err)
+		}
+	}
+	if req.ConvertTo != "" {
+		if err := s.converter.Convert(ctx, data, req.ConvertTo); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "currency conversion failed")
+			return nil, err
+		}
+	}
+
 	// Convert to response
+	loc := req.Location()
 	responseData := make([]response.HistoricalDataResponse, len(data))
 	for i := range data {
-		responseData[i] = s.toHistoricalDataResponse(&data[i])
+		responseData[i] = toHistoricalDataResponse(&data[i])
+		responseData[i].Currency = req.ConvertTo
+		responseData[i].InLocation(loc)
+		if req.WantsAttributes() {
+			responseData[i].Attributes = data[i].Attributes
+		}
+	}
+
+	if req.WantsDerived() {
+		derived, err := s.deriveFields(ctx, data, req)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "derived fields failed")
+			return nil, err
+		}
+		for i := range responseData {
+			responseData[i].Derived = derived[i]
+		}
+	}
+
+	if req.WantsEarnings() {
+		if err := s.annotateEarnings(ctx, data, responseData); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "earnings annotation failed")
+			return nil, err
+		}
+	}
+
+	countMode := req.CountMode()
+	if req.WantsFill() {
+		var previous *response.HistoricalDataResponse
+		if seeded {
+			previous, responseData = &responseData[0], responseData[1:]
+		}
+		end := req.EndDate
+		if through, ok := entitlement.Through(ctx); ok && end.After(through) {
+			end = through
+		}
+		series := fillDates(responseData, previous, symbol, req.StartDate, end, req)
+		if req.SortDir == "desc" {
+			slices.Reverse(series)
+		}
+		total = int64(len(series))
+		responseData = series[min(req.GetOffset(), len(series)):min(req.GetOffset()+req.Limit, len(series))]
+		if countMode == repository.CountApproximate {
+			countMode = repository.CountExact
+		}
 	}
 
 	// Calculate pagination metadata
@@ -104,13 +523,59 @@ func (s *historicalService) GetHistoricalData(ctx context.Context, req *request.
 			TotalItems: total,
 			TotalPages: totalPages,
 		},
+		Fields: fields,
+	}
+	if through, ok := entitlement.Through(ctx); ok {
+		result.EntitledThrough = through.Format("2006-01-02")
+	}
+	switch countMode {
+	case repository.CountNone:
+		// The repository read one row past the page instead of counting
+		hasNext := total > int64(req.GetOffset()+req.Limit)
+		result.Pagination.TotalItems = 0
+		result.Pagination.TotalPages = 0
+		result.Pagination.Count = "skipped"
+		result.Pagination.HasNext = &hasNext
+	case repository.CountApproximate:
+		result.Pagination.Count = "approximate"
 	}
 
 	return result, nil
 }
 
+// fillDates returns one entry per day from start to end, weekends excluded
+// unless fill_days=calendar, with the bars in ascending date order and a
+// filled entry for every day without one. Forward filling repeats the close
+// of the previous bar, so days before the first bar are left out.
+func fillDates(bars []response.HistoricalDataResponse, previous *response.HistoricalDataResponse, symbol string, start, end time.Time, req *request.GetDataRequest) []response.HistoricalDataResponse {
+	series := make([]response.HistoricalDataResponse, 0, int(end.Sub(start).Hours()/24)+1)
+	next := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if next < len(bars) && bars[next].Date == date {
+			series = append(series, bars[next])
+			previous = &bars[next]
+			next++
+			continue
+		}
+		if req.FillDays == "weekdays" && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+
+		filled := response.HistoricalDataResponse{Symbol: symbol, Date: date, Currency: req.ConvertTo, Filled: req.Fill}
+		if req.Fill == response.FillForward {
+			if previous == nil {
+				continue
+			}
+			filled.Open, filled.High, filled.Low, filled.Close = previous.Close, previous.Close, previous.Close, previous.Close
+		}
+		series = append(series, filled)
+	}
+	return series
+}
+
 // GetHistoricalDataByID retrieves a single historical data record by ID
-func (s *historicalService) GetHistoricalDataByID(ctx context.Context, id uint64) (*response.HistoricalDataResponse, error) {
+func (s *historicalService) GetHistoricalDataByID(ctx context.Context, id uint64, req *request.GetDataByIDRequest) (*response.HistoricalDataResponse, error) {
 	tracer := otel.Tracer("historical-service")
 	ctx, span := tracer.Start(ctx, "HistoricalService.GetHistoricalDataByID")
 	defer span.End()
@@ -131,102 +596,638 @@ func (s *historicalService) GetHistoricalDataByID(ctx context.Context, id uint64
 
 	span.SetAttributes(attribute.Bool("found", true))
 
+	req.Normalize()
+	rows := []model.HistoricalData{*data}
+	if req.Adjustment != "" {
+		if err := s.adjuster.Adjust(ctx, rows, req.Adjustment); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "adjustment failed")
+			return nil, fmt.Errorf("failed to adjust historical data: %w", err)
+		}
+	}
+	if req.ConvertTo != "" {
+		if err := s.converter.Convert(ctx, rows, req.ConvertTo); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "currency conversion failed")
+			return nil, err
+		}
+	}
+	data = &rows[0]
+
 	// Convert to response
-	result := s.toHistoricalDataResponse(data)
+	result := toHistoricalDataResponse(data)
+	if req.WantsAttributes() {
+		result.Attributes = data.Attributes
+	}
+	result.Currency = req.ConvertTo
+	result.InLocation(req.Location())
 
 	return &result, nil
 }
 
-// UploadCSV processes and stores CSV file data with batch processing
-func (s *historicalService) UploadCSV(ctx context.Context, reader io.Reader, fileSize int64) (*response.CSVUploadResponse, error) {
+// LookupData retrieves the bars of a list of (symbol, date) pairs in one
+// round trip. Former names resolve to the current symbol, whose bar is
+// returned; pairs without a bar are listed as missing.
+func (s *historicalService) LookupData(ctx context.Context, req *request.LookupDataRequest) (*response.LookupDataResponse, error) {
 	tracer := otel.Tracer("historical-service")
-	ctx, span := tracer.Start(ctx, "HistoricalService.UploadCSV")
+	ctx, span := tracer.Start(ctx, "HistoricalService.LookupData")
 	defer span.End()
 
+	req.Normalize()
+	span.SetAttributes(attribute.Int("pair_count", len(req.Pairs)))
+
+	symbols := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, pair := range req.Pairs {
+		if !seen[pair.Symbol] {
+			seen[pair.Symbol] = true
+			symbols = append(symbols, pair.Symbol)
+		}
+	}
+	current, err := s.resolver.ResolveMap(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]repository.BarKey, len(req.Pairs))
+	for i, pair := range req.Pairs {
+		date, err := time.Parse("2006-01-02", pair.Date)
+		if err != nil {
+			return nil, &request.ValidationError{Field: "date", Message: fmt.Sprintf("invalid date %q: expected YYYY-MM-DD", pair.Date)}
+		}
+		keys[i] = repository.BarKey{Symbol: current[pair.Symbol], Date: date}
+	}
+
+	rows, err := s.repo.FindByKeys(ctx, keys)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "database query failed")
+		return nil, fmt.Errorf("failed to look up historical data: %w", err)
+	}
+	byKey := make(map[repository.BarKey]*model.HistoricalData, len(rows))
+	for i := range rows {
+		byKey[repository.BarKey{Symbol: rows[i].Symbol, Date: rows[i].Date.UTC()}] = &rows[i]
+	}
+
+	result := &response.LookupDataResponse{
+		Found:   make([]response.HistoricalDataResponse, 0, len(rows)),
+		Missing: make([]response.MissingBar, 0),
+	}
+	for i, key := range keys {
+		if row, ok := byKey[key]; ok {
+			result.Found = append(result.Found, toHistoricalDataResponse(row))
+			continue
+		}
+		result.Missing = append(result.Missing, response.MissingBar{Symbol: req.Pairs[i].Symbol, Date: req.Pairs[i].Date})
+	}
+	if through, ok := entitlement.Through(ctx); ok {
+		result.EntitledThrough = through.Format("2006-01-02")
+	}
+
 	span.SetAttributes(
-		attribute.Int64("file_size_bytes", fileSize),
+		attribute.Int("found_count", len(result.Found)),
+		attribute.Int("missing_count", len(result.Missing)),
 	)
+	return result, nil
+}
 
-	const batchSize = 1000
+// AsOf retrieves the last bar of a symbol on or before a date, so weekends
+// and holidays resolve to the previous trading day. The result has a nil
+// bar when the symbol has none by that date.
+func (s *historicalService) AsOf(ctx context.Context, symbol string, req *request.AsOfRequest) (*response.AsOfResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "HistoricalService.AsOf")
+	defer span.End()
 
-	parser := csvparser.NewParser(reader)
+	span.SetAttributes(attribute.String("symbol", symbol), attribute.String("date", req.Date))
 
-	// Parse and validate header
-	if err := parser.ParseHeader(); err != nil {
+	results, err := s.asOf(ctx, []request.SymbolDate{{Symbol: symbol, Date: req.Date}})
+	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "invalid CSV header")
-		return nil, fmt.Errorf("invalid CSV header: %w", err)
+		return nil, err
 	}
+	return &results[0], nil
+}
 
-	var totalRows int
-	var successCount int
-	var failedCount int
-	var errors []string
-	batch := make([]model.HistoricalData, 0, batchSize)
+// AsOfBatch retrieves the as-of bars of a list of (symbol, date) pairs in
+// one round trip, in request order
+func (s *historicalService) AsOfBatch(ctx context.Context, req *request.AsOfBatchRequest) (*response.AsOfBatchResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "HistoricalService.AsOfBatch")
+	defer span.End()
+
+	req.Normalize()
+	span.SetAttributes(attribute.Int("pair_count", len(req.Pairs)))
+
+	results, err := s.asOf(ctx, req.Pairs)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result := &response.AsOfBatchResponse{Results: results}
+	if through, ok := entitlement.Through(ctx); ok {
+		result.EntitledThrough = through.Format("2006-01-02")
+	}
+	return result, nil
+}
+
+// asOf looks up the as-of bars of pairs, resolving former names to the
+// current symbol
+func (s *historicalService) asOf(ctx context.Context, pairs []request.SymbolDate) ([]response.AsOfResponse, error) {
+	symbols := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, pair := range pairs {
+		if !seen[pair.Symbol] {
+			seen[pair.Symbol] = true
+			symbols = append(symbols, pair.Symbol)
+		}
+	}
+	current, err := s.resolver.ResolveMap(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
 
-	// Process rows in batches
-	for {
-		row, err := parser.ParseRow()
+	keys := make([]repository.BarKey, len(pairs))
+	for i, pair := range pairs {
+		date, err := time.Parse("2006-01-02", pair.Date)
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			// Collect error but continue processing
-			errors = append(errors, err.Error())
-			failedCount++
+			return nil, &request.ValidationError{Field: "date", Message: fmt.Sprintf("invalid date %q: expected YYYY-MM-DD", pair.Date)}
+		}
+		keys[i] = repository.BarKey{Symbol: current[pair.Symbol], Date: date}
+	}
+
+	bars, err := s.repo.FindAsOf(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up as-of historical data: %w", err)
+	}
+
+	results := make([]response.AsOfResponse, len(pairs))
+	for i, pair := range pairs {
+		results[i] = response.AsOfResponse{Symbol: pair.Symbol, AsOf: pair.Date}
+		if bars[i] == nil {
 			continue
 		}
+		bar := toHistoricalDataResponse(bars[i])
+		results[i].Bar = &bar
+		results[i].StaleDays = int(keys[i].Date.Sub(bars[i].Date.UTC()).Hours() / 24)
+	}
+	return results, nil
+}
+
+// GetDataOnDate retrieves a page of the bars of every symbol on a date, in
+// symbol order, optionally restricted to a tag or an exchange
+func (s *historicalService) GetDataOnDate(ctx context.Context, date time.Time, req *request.DataOnDateRequest) (*response.PaginatedHistoricalDataResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "HistoricalService.GetDataOnDate")
+	defer span.End()
+
+	req.SetDefaults()
+	span.SetAttributes(
+		attribute.String("date", date.Format("2006-01-02")),
+		attribute.Int("page", req.Page),
+		attribute.Int("limit", req.Limit),
+	)
+
+	filters := map[string]interface{}{
+		"start_date": date,
+		"end_date":   date,
+	}
+	if req.Tag != "" {
+		filters["tag"] = req.Tag
+	}
+	if req.Exchange != "" {
+		filters["exchange"] = req.Exchange
+	}
+
+	// A single date walks idx_date and orders its rows by symbol
+	data, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset(), repository.QueryOptions{
+		SortBy:  "date",
+		SortDir: "asc",
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "database query failed")
+		return nil, fmt.Errorf("failed to get historical data on date: %w", err)
+	}
 
-		totalRows++
+	result := &response.PaginatedHistoricalDataResponse{
+		Data: make([]response.HistoricalDataResponse, len(data)),
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+		},
+	}
+	for i := range data {
+		result.Data[i] = toHistoricalDataResponse(&data[i])
+	}
+	if through, ok := entitlement.Through(ctx); ok {
+		result.EntitledThrough = through.Format("2006-01-02")
+	}
+
+	span.SetAttributes(attribute.Int("returned_records", len(data)))
+	return result, nil
+}
+
+// GetMovers retrieves the symbols with the largest rise and fall of their
+// close on a date from their previous bar, optionally restricted to a tag
+// or an exchange
+func (s *historicalService) GetMovers(ctx context.Context, date time.Time, req *request.MoversRequest) (*response.MoversResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "HistoricalService.GetMovers")
+	defer span.End()
+
+	req.SetDefaults()
+	span.SetAttributes(
+		attribute.String("date", date.Format("2006-01-02")),
+		attribute.String("direction", req.Direction),
+		attribute.Int("limit", req.Limit),
+	)
+
+	filters := make(map[string]interface{})
+	if req.Tag != "" {
+		filters["tag"] = req.Tag
+	}
+	if req.Exchange != "" {
+		filters["exchange"] = req.Exchange
+	}
+
+	result := &response.MoversResponse{Date: date.Format("2006-01-02")}
+	for _, gainers := range []bool{true, false} {
+		if (gainers && req.Direction == "losers") || (!gainers && req.Direction == "gainers") {
+			continue
+		}
+		movers, err := s.repo.FindMovers(ctx, date, filters, gainers, req.Limit)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "database query failed")
+			return nil, fmt.Errorf("failed to get movers: %w", err)
+		}
+		list := make([]response.Mover, len(movers))
+		for i, m := range movers {
+			list[i] = response.Mover{
+				Symbol:        m.Symbol,
+				Close:         m.Close,
+				PreviousClose: m.PreviousClose,
+				ChangePct:     m.Close.Div(m.PreviousClose).Sub(decimal.NewFromInt(1)).Mul(decimal.NewFromInt(100)).InexactFloat64(),
+			}
+		}
+		if gainers {
+			result.Gainers = list
+		} else {
+			result.Losers = list
+		}
+	}
+	if through, ok := entitlement.Through(ctx); ok {
+		result.EntitledThrough = through.Format("2006-01-02")
+	}
+	return result, nil
+}
 
-		// Validate business rules
-		if err := s.validateCSVRow(row); err != nil {
-			errors = append(errors, fmt.Sprintf("line %d: %v", parser.GetCurrentLine(), err))
-			failedCount++
+// deriveFields computes the derived fields of every row. Each symbol's series
+// is reloaded over the page's date range together with enough preceding bars
+// to fill the windows, so the values don't depend on sorting, paging or the
+// source filter.
+func (s *historicalService) deriveFields(ctx context.Context, data []model.HistoricalData, req *request.GetDataRequest) ([]*response.DerivedFields, error) {
+	type dateRange struct{ start, end time.Time }
+	ranges := make(map[string]*dateRange)
+	for i := range data {
+		r, ok := ranges[data[i].Symbol]
+		if !ok {
+			ranges[data[i].Symbol] = &dateRange{start: data[i].Date, end: data[i].Date}
 			continue
 		}
+		if data[i].Date.Before(r.start) {
+			r.start = data[i].Date
+		}
+		if data[i].Date.After(r.end) {
+			r.end = data[i].Date
+		}
+	}
+
+	// A bar's VWAP needs vwap_window-1 preceding bars, its ATR atr_window
+	// preceding closes
+	lookback := max(req.VWAPWindow-1, req.ATRWindow)
+
+	derived := make(map[string]*response.DerivedFields, len(data))
+	for symbol, r := range ranges {
+		var series []model.HistoricalData
+		if lookback > 0 {
+			filters := map[string]interface{}{
+				"symbol":   symbol,
+				"end_date": r.start.AddDate(0, 0, -1),
+			}
+			preceding, _, err := s.repo.FindAll(ctx, filters, lookback, 0, repository.QueryOptions{SortBy: "date", SortDir: "desc", Count: repository.CountNone})
+			if err != nil {
+				return nil, fmt.Errorf("failed to load preceding bars of %s: %w", symbol, err)
+			}
+			slices.Reverse(preceding)
+			series = preceding
+		}
+		rows, err := s.repo.FindBySymbol(ctx, symbol, r.start, r.end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load bars of %s: %w", symbol, err)
+		}
+		series = append(series, rows...)
 
-		// Add to batch
-		batch = append(batch, model.HistoricalData{
-			Symbol: row.Symbol,
-			Date:   row.Date,
-			Open:   row.Open,
-			High:   row.High,
-			Low:    row.Low,
-			Close:  row.Close,
-			Volume: row.Volume,
+		// Derive from the same prices that are returned
+		if req.Adjustment != "" {
+			if err := s.adjuster.Adjust(ctx, series, req.Adjustment); err != nil {
+				return nil, fmt.Errorf("failed to adjust historical data: %w", err)
+			}
+		}
+		if req.ConvertTo != "" {
+			if err := s.converter.Convert(ctx, series, req.ConvertTo); err != nil {
+				return nil, err
+			}
+		}
+
+		bars := make([]analytics.Bar, len(series))
+		for i := range series {
+			bars[i] = analytics.Bar{
+				High:   series[i].High.InexactFloat64(),
+				Low:    series[i].Low.InexactFloat64(),
+				Close:  series[i].Close.InexactFloat64(),
+				Volume: float64(series[i].Volume),
+			}
+		}
+		for i, d := range analytics.Derive(bars, req.VWAPWindow, req.ATRWindow) {
+			derived[symbol+"|"+series[i].Date.Format("2006-01-02")] = &response.DerivedFields{
+				TypicalPrice: d.TypicalPrice,
+				VWAP:         d.VWAP,
+				TrueRange:    d.TrueRange,
+				ATR:          d.ATR,
+			}
+		}
+	}
+
+	result := make([]*response.DerivedFields, len(data))
+	for i := range data {
+		result[i] = derived[data[i].Symbol+"|"+data[i].Date.Format("2006-01-02")]
+	}
+	return result, nil
+}
+
+// ValidateUpload checks an upload against the effective maximum file size and
+// its transforms before any of it is read
+func (s *historicalService) ValidateUpload(info UploadInfo) error {
+	settings := s.ingestionSettings(info.Overrides)
+	if settings.MaxFileSize > 0 && info.FileSize > settings.MaxFileSize {
+		return &FileTooLargeError{Size: info.FileSize, Limit: settings.MaxFileSize}
+	}
+	if info.Staged && (info.Resumable || info.ResumeJobID != 0) {
+		return &request.ValidationError{
+			Field:   "staged",
+			Message: "staged uploads are merged in one statement and cannot be resumed",
+		}
+	}
+	if _, err := s.uploadTransforms(info); err != nil {
+		return &request.ValidationError{
+			Field:   "transforms",
+			Message: fmt.Sprintf("%v, available transforms: %s", err, strings.Join(s.transforms.Names(), ", ")),
+		}
+	}
+	return nil
+}
+
+// stagedUpload reports whether an upload is staged: on request, or as
+// configured for every upload or the large ones unless it is resumable
+func (s *historicalService) stagedUpload(info UploadInfo, settings config.IngestionConfig) bool {
+	if info.Staged {
+		return true
+	}
+	if info.Resumable || info.ResumeJobID != 0 {
+		return false
+	}
+	return settings.Staged || (settings.StagedMinSize > 0 && info.FileSize >= settings.StagedMinSize)
+}
+
+// uploadTransforms resolves the transforms of an upload: the ones it names,
+// or else the ones configured for its source name, the file name
+func (s *historicalService) uploadTransforms(info UploadInfo) (ingest.Chain, error) {
+	names := info.Transforms
+	if len(names) == 0 {
+		names = s.cfg.TransformsFor(info.Filename)
+	}
+	return s.transforms.Chain(names)
+}
+
+// UploadCSV processes and stores CSV file data with batch processing
+func (s *historicalService) UploadCSV(ctx context.Context, reader io.Reader, info UploadInfo) (*response.CSVUploadResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "HistoricalService.UploadCSV")
+	defer span.End()
+
+	fileSize := info.FileSize
+	span.SetAttributes(
+		attribute.Int64("file_size_bytes", fileSize),
+		attribute.String("filename", info.Filename),
+	)
+
+	settings := s.ingestionSettings(info.Overrides)
+	span.SetAttributes(
+		attribute.Int("batch_size", settings.BatchSize),
+		attribute.Int("max_parallel_batches", settings.MaxParallelBatches),
+		attribute.Int("max_errors", settings.MaxErrors),
+		attribute.Float64("max_error_rate", settings.MaxErrorRate),
+	)
+
+	if err := s.ValidateUpload(info); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid upload")
+		return nil, err
+	}
+	transforms, _ := s.uploadTransforms(info) // resolved by ValidateUpload
+	staged := s.stagedUpload(info, settings)
+	span.SetAttributes(
+		attribute.StringSlice("transforms", transforms.Names()),
+		attribute.Bool("staged", staged),
+	)
+
+	// Uploads touching frozen ranges are rejected before anything is stored
+	var locks lockIndex
+	if !info.OverrideLocks {
+		all, err := s.locks.FindAllLocks(ctx)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to load data locks")
+			return nil, err
+		}
+		locks = newLockIndex(all)
+	}
+	if seeker, ok := reader.(io.ReadSeeker); ok && len(locks) > 0 {
+		lockedErr, err := findLockedRows(seeker, locks, transforms)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to read file")
+			return nil, err
+		}
+		if lockedErr != nil {
+			span.SetStatus(codes.Error, "upload touches frozen data")
+			return nil, lockedErr
+		}
+	}
+
+	startTime := time.Now()
+
+	// Record the upload job so its status can be queried later, or pick up
+	// the interrupted one
+	var job *model.UploadJob
+	var source *model.Source
+	var err error
+	if info.ResumeJobID != 0 {
+		job, source, err = s.resumeUploadJob(ctx, info)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to resume upload job")
+			return nil, err
+		}
+	} else {
+		job = &model.UploadJob{
+			Tenant:    info.Tenant,
+			APIKey:    info.APIKey,
+			Filename:  info.Filename,
+			FileSize:  fileSize,
+			Status:    model.UploadStatusProcessing,
+			StartedAt: startTime,
+		}
+		if err := s.jobs.Create(ctx, job); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create upload job")
+			return nil, err
+		}
+	}
+	span.SetAttributes(attribute.Int64("job_id", int64(job.ID)))
+
+	// Every row of this upload points back at the file and job it came from
+	if source == nil {
+		source = &model.Source{
+			Kind:        model.SourceKindUpload,
+			Name:        info.Filename,
+			Tenant:      info.Tenant,
+			UploadJobID: &job.ID,
+		}
+		if err := s.sources.Create(ctx, source); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to create source")
+			job.Status = model.UploadStatusFailed
+			job.Message = err.Error()
+			s.finishUploadJob(ctx, job)
+			return nil, err
+		}
+	}
+
+	parser := csvparser.NewParser(reader)
+	parser.SetLanguage(i18n.FromContext(ctx))
+	if info.CaptureAttributes || s.cfg.CaptureAttributes {
+		parser.CaptureUnmapped()
+	}
+
+	// Parse and validate header
+	if err := parser.ParseHeader(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid CSV header")
+		job.Status = model.UploadStatusFailed
+		job.Message = fmt.Sprintf("invalid CSV header: %v", err)
+		s.finishUploadJob(ctx, job)
+		s.bus.Publish(ctx, events.UploadCompleted{
+			JobID:      job.ID,
+			Tenant:     job.Tenant,
+			Filename:   job.Filename,
+			Status:     job.Status,
+			Message:    job.Message,
+			Duration:   time.Since(startTime),
+			OccurredAt: time.Now(),
 		})
+		return nil, &request.ValidationError{Field: "file", Message: job.Message}
+	}
 
-		// Process batch when it reaches the size limit
-		if len(batch) >= batchSize {
-			if err := s.repo.BulkCreate(ctx, batch, batchSize); err != nil {
-				// Log error but continue with next batch
-				errors = append(errors, fmt.Sprintf("batch insert error: %v", err))
-				failedCount += len(batch)
-			} else {
-				successCount += len(batch)
+	pipeline := &uploadPipeline{
+		parser:     parser,
+		transforms: transforms,
+		locks:      locks,
+		sourceID:   &source.ID,
+		settings:   settings,
+		repo:       s.repo,
+		catalog:    s.symbols,
+		resolver:   s.resolver,
+		bus:        s.bus,
+	}
+	if staged {
+		pipeline.staging = s.staging
+		pipeline.jobID = job.ID
+	}
+	if info.Resumable {
+		pipeline.checkpoint = func(ctx context.Context, cp uploadCheckpoint) {
+			progress := model.UploadJob{
+				ID:             job.ID,
+				ProcessedBytes: cp.Offset,
+				CheckpointLine: cp.Line,
+				TotalRows:      cp.TotalRows,
+				SuccessCount:   cp.SuccessCount,
+				FailedCount:    cp.FailedCount,
+				Symbols:        strings.Join(cp.Symbols, ","),
+			}
+			if err := s.jobs.SaveCheckpoint(ctx, &progress); err != nil {
+				logger.GetGlobalLogger().Warn().Err(err).Uint64("job_id", job.ID).Msg("Failed to save upload checkpoint")
 			}
-			batch = batch[:0] // Clear batch
 		}
 	}
 
-	// Process remaining batch
-	if len(batch) > 0 {
-		if err := s.repo.BulkCreate(ctx, batch, batchSize); err != nil {
-			errors = append(errors, fmt.Sprintf("final batch insert error: %v", err))
-			failedCount += len(batch)
-		} else {
-			successCount += len(batch)
+	// A resumed upload skips the rows before its checkpoint
+	if info.ResumeJobID != 0 && job.CheckpointLine > 0 {
+		if err := resumeParser(parser, reader, job); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to resume upload")
+			job.Status = model.UploadStatusFailed
+			job.Message = fmt.Sprintf("failed to resume from the checkpoint: %v", err)
+			s.finishUploadJob(ctx, job)
+			return nil, err
+		}
+		var symbols []string
+		if job.Symbols != "" {
+			symbols = strings.Split(job.Symbols, ",")
 		}
+		pipeline.resume(uploadCheckpoint{
+			Offset:       job.ProcessedBytes,
+			Line:         job.CheckpointLine,
+			TotalRows:    job.TotalRows,
+			SuccessCount: job.SuccessCount,
+			FailedCount:  job.FailedCount,
+			Symbols:      symbols,
+		})
+		span.SetAttributes(attribute.Int64("resumed_at_byte", job.ProcessedBytes))
 	}
 
-	// Limit errors to first 100 to avoid huge responses
-	if len(errors) > 100 {
-		errors = append(errors[:100], fmt.Sprintf("... and %d more errors", len(errors)-100))
+	abortedReason := pipeline.run(ctx)
+	if staged {
+		if abortedReason == "" {
+			abortedReason = pipeline.merge(ctx)
+		}
+		if err := s.staging.Clear(ctx, job.ID); err != nil {
+			logger.GetGlobalLogger().Warn().Err(err).Uint64("job_id", job.ID).Msg("Failed to clear staged rows")
+		}
 	}
+	aborted := abortedReason != ""
+	rowsRead, totalRows := pipeline.rowsRead, pipeline.totalRows
+	successCount, failedCount := pipeline.successCount, pipeline.failedCount
+	rowErrors := pipeline.rowErrors
+
+	// Keep a sample of the first errors to explain an aborted upload
+	sampleErrors := rowErrors[:min(len(rowErrors), maxSampleErrors)]
+
+	// Limit the listed errors to avoid huge responses
+	omittedErrors := max(len(rowErrors)-maxReportedErrors, 0)
+	rowErrors = rowErrors[:len(rowErrors)-omittedErrors]
+	omittedErrors += pipeline.unlisted
 
 	message := "CSV file processed successfully"
-	if failedCount > 0 {
+	switch {
+	case aborted:
+		message = "file appears malformed: " + abortedReason
+	case failedCount > 0:
 		message = fmt.Sprintf("CSV file processed with %d errors", failedCount)
 	}
 
@@ -235,7 +1236,7 @@ func (s *historicalService) UploadCSV(ctx context.Context, reader io.Reader, fil
 		attribute.Int("total_rows", totalRows),
 		attribute.Int("success_count", successCount),
 		attribute.Int("failed_count", failedCount),
-		attribute.Int("error_count", len(errors)),
+		attribute.Int("error_count", len(rowErrors)),
 	)
 
 	if failedCount > 0 {
@@ -244,53 +1245,454 @@ func (s *historicalService) UploadCSV(ctx context.Context, reader io.Reader, fil
 		))
 	}
 
-	span.SetStatus(codes.Ok, message)
+	if !aborted {
+		span.SetStatus(codes.Ok, message)
+	}
+
+	symbols := pipeline.uploadedSymbols()
+	newSymbols := pipeline.newUploadedSymbols()
+	if len(newSymbols) > 0 {
+		logger.GetGlobalLogger().Info().
+			Uint64("job_id", job.ID).
+			Strs("symbols", newSymbols).
+			Str("unknown_symbols", settings.UnknownSymbols).
+			Msg("Upload introduced new symbols")
+	}
+
+	job.Status = model.UploadStatusCompleted
+	if aborted {
+		job.Status = model.UploadStatusFailed
+	}
+	job.TotalRows = totalRows
+	job.SuccessCount = successCount
+	job.FailedCount = failedCount
+	job.ProcessedBytes = fileSize
+	job.Symbols = strings.Join(symbols, ",")
+	job.Message = message
+	s.finishUploadJob(ctx, job)
+
+	s.bus.Publish(ctx, events.UploadCompleted{
+		JobID:          job.ID,
+		Tenant:         job.Tenant,
+		Filename:       job.Filename,
+		Status:         job.Status,
+		Message:        message,
+		TotalRows:      totalRows,
+		SuccessCount:   successCount,
+		FailedCount:    failedCount,
+		ProcessedBytes: fileSize,
+		Symbols:        symbols,
+		Duration:       time.Since(startTime),
+		OccurredAt:     time.Now(),
+	})
+
+	if aborted {
+		span.SetStatus(codes.Error, "upload aborted")
+		return nil, &MalformedFileError{
+			JobID:        job.ID,
+			RowsRead:     rowsRead,
+			SuccessCount: successCount,
+			FailedCount:  failedCount,
+			Reason:       abortedReason,
+			SampleErrors: sampleErrors,
+		}
+	}
 
 	return &response.CSVUploadResponse{
+		JobID:          job.ID,
 		TotalRows:      totalRows,
 		SuccessCount:   successCount,
 		FailedCount:    failedCount,
 		ProcessedBytes: fileSize,
-		Errors:         errors,
+		Symbols:        symbols,
+		NewSymbols:     newSymbols,
+		Errors:         rowErrors,
+		OmittedErrors:  omittedErrors,
 		Message:        message,
 	}, nil
 }
 
-// validateCSVRow validates business rules for CSV row data
-func (s *historicalService) validateCSVRow(row *csvparser.HistoricalDataRow) error {
+// SyntheticOptions returns the generator options of a normalized synthetic
+// data request
+func SyntheticOptions(req *request.GenerateDataRequest) synthetic.Options {
+	symbols := req.Symbols
+	if len(symbols) == 0 {
+		symbols = synthetic.Symbols(req.Count)
+	}
+	return synthetic.Options{
+		Symbols:    symbols,
+		Start:      req.StartDate,
+		End:        req.EndDate,
+		Seed:       req.Seed,
+		Drift:      req.Drift,
+		Volatility: req.Volatility,
+	}
+}
+
+// GenerateData ingests random-walk bars through the upload pipeline, as an
+// upload job of a synthetic-<seed>.csv file unless info names one. The CSV is
+// generated while it is read, so large ranges are never held in memory.
+func (s *historicalService) GenerateData(ctx context.Context, req *request.GenerateDataRequest, info UploadInfo) (*response.CSVUploadResponse, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if info.Filename == "" {
+		info.Filename = fmt.Sprintf("synthetic-%d.csv", req.Seed)
+	}
+
+	pr, pw := io.Pipe()
+	gen := synthetic.NewGenerator(SyntheticOptions(req))
+	go func() {
+		_, err := synthetic.WriteCSV(pw, gen)
+		pw.CloseWithError(err)
+	}()
+	// Unblocks the generator when the upload stops early
+	defer pr.Close()
+
+	return s.UploadCSV(ctx, pr, info)
+}
+
+// resumeUploadJob loads an interrupted resumable upload job of the same file
+// and its source, counting the resume
+func (s *historicalService) resumeUploadJob(ctx context.Context, info UploadInfo) (*model.UploadJob, *model.Source, error) {
+	job, err := s.jobs.FindByID(ctx, info.ResumeJobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job == nil || job.Status != model.UploadStatusProcessing ||
+		job.APIKey != info.APIKey || job.Filename != info.Filename || job.FileSize != info.FileSize {
+		return nil, nil, apperror.Conflict(fmt.Sprintf("Upload job #%d cannot be resumed", info.ResumeJobID), nil).Wrap(ErrUploadNotResumable)
+	}
+
+	job.Resumes++
+	if err := s.jobs.Update(ctx, job); err != nil {
+		return nil, nil, err
+	}
+
+	sources, _, err := s.sources.FindAll(ctx, map[string]interface{}{
+		"kind":          model.SourceKindUpload,
+		"upload_job_id": job.ID,
+	}, 1, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sources) == 0 {
+		return job, nil, nil
+	}
+	return job, &sources[0], nil
+}
+
+// resumeParser moves a parser whose header was read to the checkpoint of a job
+func resumeParser(parser *csvparser.Parser, reader io.Reader, job *model.UploadJob) error {
+	seeker, ok := reader.(io.ReadSeeker)
+	if !ok {
+		return errors.New("the upload cannot be read from its checkpoint")
+	}
+	if _, err := seeker.Seek(job.ProcessedBytes, io.SeekStart); err != nil {
+		return err
+	}
+	parser.ResumeAt(seeker, job.ProcessedBytes, job.CheckpointLine)
+	return nil
+}
+
+// findLockedRows scans an upload for rows in frozen ranges once transformed
+// and rewinds it, returning nil when no row is frozen. Rows that fail to parse
+// or transform are left to the upload itself.
+func findLockedRows(reader io.ReadSeeker, locks lockIndex, transforms ingest.Chain) (*LockedDataError, error) {
+	parser := csvparser.NewParser(reader)
+	var lockedErr *LockedDataError
+	if err := parser.ParseHeader(); err == nil {
+		touched := make(map[uint64]struct{})
+		for {
+			row, err := parser.ParseRow()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				continue
+			}
+			bar := barFromRow(row, nil)
+			if err := transforms.Apply(&bar); err != nil {
+				continue
+			}
+			lock := locks.find(bar.Symbol, bar.Date)
+			if lock == nil {
+				continue
+			}
+			if lockedErr == nil {
+				lockedErr = &LockedDataError{}
+			}
+			lockedErr.LockedRows++
+			if _, ok := touched[lock.ID]; !ok {
+				touched[lock.ID] = struct{}{}
+				lockedErr.Locks = append(lockedErr.Locks, *lock)
+			}
+			if len(lockedErr.SampleErrors) < maxSampleErrors {
+				lockedErr.SampleErrors = append(lockedErr.SampleErrors, lockedRowError(lock, row, parser.GetCurrentLine()))
+			}
+		}
+	}
+
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind file: %w", err)
+	}
+	return lockedErr, nil
+}
+
+// parseRowError describes a row the parser rejected
+func parseRowError(err error, line int) response.CSVRowError {
+	var parseErr *csvparser.ParseError
+	if errors.As(err, &parseErr) {
+		return response.CSVRowError{
+			Line:     parseErr.Line,
+			Field:    parseErr.Field,
+			RawValue: parseErr.Value,
+			Code:     parseErr.Code,
+			Message:  parseErr.Message,
+		}
+	}
+	return response.CSVRowError{Line: line, Code: csvparser.CodeMalformedRow, Message: err.Error()}
+}
+
+// validationRowError describes a parsed row that breaks a business rule
+func validationRowError(err error, row *csvparser.HistoricalDataRow, line int) response.CSVRowError {
+	rowErr := response.CSVRowError{Line: line, Message: err.Error()}
+	var barErr *BarValidationError
+	if errors.As(err, &barErr) {
+		rowErr.Field = barErr.Field
+		rowErr.Code = barErr.Code
+		rowErr.RawValue = csvRowValue(row, barErr.Field)
+	}
+	return rowErr
+}
+
+// lockedRowError describes a row that falls in a frozen range
+func lockedRowError(lock *model.DataLock, row *csvparser.HistoricalDataRow, line int) response.CSVRowError {
+	return response.CSVRowError{
+		Line:     line,
+		Field:    "date",
+		RawValue: row.Date.Format("2006-01-02"),
+		Code:     RowErrorLocked,
+		Message:  lockedBarError(lock),
+	}
+}
+
+// csvRowValue renders the parsed value of a field of a row
+func csvRowValue(row *csvparser.HistoricalDataRow, field string) string {
+	switch field {
+	case "date":
+		return row.Date.Format("2006-01-02")
+	case "open":
+		return row.Open.String()
+	case "high":
+		return row.High.String()
+	case "low":
+		return row.Low.String()
+	case "close":
+		return row.Close.String()
+	case "number_of_trades":
+		if row.NumberOfTrades != nil {
+			return strconv.FormatUint(*row.NumberOfTrades, 10)
+		}
+	}
+	return ""
+}
+
+// ingestionSettings applies the non-zero overrides to the configured ingestion tuning
+func (s *historicalService) ingestionSettings(overrides config.IngestionConfig) config.IngestionConfig {
+	settings := s.cfg
+	if overrides.BatchSize > 0 {
+		settings.BatchSize = overrides.BatchSize
+	}
+	if overrides.MaxParallelBatches > 0 {
+		settings.MaxParallelBatches = overrides.MaxParallelBatches
+	}
+	if overrides.MaxFileSize > 0 {
+		settings.MaxFileSize = overrides.MaxFileSize
+	}
+	if overrides.MaxErrors > 0 {
+		settings.MaxErrors = overrides.MaxErrors
+	}
+	if overrides.MaxErrorRate > 0 {
+		settings.MaxErrorRate = overrides.MaxErrorRate
+	}
+	if overrides.UnknownSymbols != "" {
+		settings.UnknownSymbols = overrides.UnknownSymbols
+	}
+	settings.BatchSize = max(settings.BatchSize, 1)
+	settings.MaxParallelBatches = max(settings.MaxParallelBatches, 1)
+	if settings.UnknownSymbols == "" {
+		settings.UnknownSymbols = UnknownSymbolsRegister
+	}
+	if settings.StubCurrency == "" {
+		settings.StubCurrency = defaultStubCurrency
+	}
+	return settings
+}
+
+// finishUploadJob stamps the job's finish time and persists its final state.
+// A failure here must not fail an upload whose rows are already stored.
+func (s *historicalService) finishUploadJob(ctx context.Context, job *model.UploadJob) {
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err := s.jobs.Update(ctx, job); err != nil {
+		logger.GetGlobalLogger().Error().Err(err).Uint64("job_id", job.ID).Msg("Failed to update upload job")
+	}
+}
+
+// newBarsIngestedEvent summarizes a persisted batch as a BarsIngested event
+func newBarsIngestedEvent(batch []model.HistoricalData) events.BarsIngested {
+	batchSymbols := make(map[string]struct{})
+	event := events.BarsIngested{
+		Count:      len(batch),
+		OccurredAt: time.Now(),
+	}
+
+	for i := range batch {
+		batchSymbols[batch[i].Symbol] = struct{}{}
+		if event.StartDate.IsZero() || batch[i].Date.Before(event.StartDate) {
+			event.StartDate = batch[i].Date
+		}
+		if batch[i].Date.After(event.EndDate) {
+			event.EndDate = batch[i].Date
+		}
+	}
+
+	event.Symbols = make([]string, 0, len(batchSymbols))
+	for symbol := range batchSymbols {
+		event.Symbols = append(event.Symbols, symbol)
+	}
+	sort.Strings(event.Symbols)
+
+	return event
+}
+
+// validateBar validates the business rules shared by every ingestion path.
+// Prices are compared exactly, so a bar is never rejected for rounding.
+func validateBar(bar *model.HistoricalData) error {
 	// Validate OHLC relationships
-	if row.High < row.Low {
-		return fmt.Errorf("high price (%.2f) must be greater than or equal to low price (%.2f)", row.High, row.Low)
+	if bar.High.LessThan(bar.Low) {
+		return &BarValidationError{
+			Field:   "high",
+			Code:    RowErrorHighBelowLow,
+			Message: fmt.Sprintf("high price (%s) must be greater than or equal to low price (%s)", bar.High, bar.Low),
+		}
+	}
+	if bar.Open.LessThan(bar.Low) || bar.Open.GreaterThan(bar.High) {
+		return &BarValidationError{
+			Field:   "open",
+			Code:    RowErrorOpenOutOfRange,
+			Message: fmt.Sprintf("open price (%s) must be between low (%s) and high (%s)", bar.Open, bar.Low, bar.High),
+		}
 	}
-	if row.Open < row.Low || row.Open > row.High {
-		return fmt.Errorf("open price (%.2f) must be between low (%.2f) and high (%.2f)", row.Open, row.Low, row.High)
+	if bar.Close.LessThan(bar.Low) || bar.Close.GreaterThan(bar.High) {
+		return &BarValidationError{
+			Field:   "close",
+			Code:    RowErrorCloseOutOfRange,
+			Message: fmt.Sprintf("close price (%s) must be between low (%s) and high (%s)", bar.Close, bar.Low, bar.High),
+		}
 	}
-	if row.Close < row.Low || row.Close > row.High {
-		return fmt.Errorf("close price (%.2f) must be between low (%.2f) and high (%.2f)", row.Close, row.Low, row.High)
+	// Validate date is not in the future. The bar date is a calendar date, so
+	// compare with the latest date currently in effect anywhere (UTC+14):
+	// a close from Tokyo is not in the future while it is still yesterday in UTC.
+	if bar.Date.After(latestToday(time.Now())) {
+		return &BarValidationError{
+			Field:   "date",
+			Code:    RowErrorFutureDate,
+			Message: fmt.Sprintf("date (%s) cannot be in the future", bar.Date.Format("2006-01-02")),
+		}
 	}
-	// Validate date is not in the future
-	if row.Date.After(time.Now()) {
-		return fmt.Errorf("date (%s) cannot be in the future", row.Date.Format("2006-01-02"))
+	// Every trade moves at least one unit
+	if bar.NumberOfTrades != nil && *bar.NumberOfTrades > bar.Volume {
+		return &BarValidationError{
+			Field:   "number_of_trades",
+			Code:    RowErrorTradesExceedVolume,
+			Message: fmt.Sprintf("number of trades (%d) cannot exceed volume (%d)", *bar.NumberOfTrades, bar.Volume),
+		}
 	}
 	// Validate all prices are positive
-	if row.Open <= 0 || row.High <= 0 || row.Low <= 0 || row.Close <= 0 {
-		return fmt.Errorf("all prices must be positive")
+	for _, price := range []struct {
+		field string
+		value decimal.Decimal
+	}{{"open", bar.Open}, {"high", bar.High}, {"low", bar.Low}, {"close", bar.Close}} {
+		if !price.value.IsPositive() {
+			return &BarValidationError{
+				Field:   price.field,
+				Code:    RowErrorNonPositivePrice,
+				Message: "all prices must be positive",
+			}
+		}
+	}
+	return nil
+}
+
+// latestToday returns the most advanced calendar date in any time zone at now,
+// as UTC midnight
+func latestToday(now time.Time) time.Time {
+	t := now.In(time.FixedZone("UTC+14", 14*60*60))
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// annotateEarnings marks the responses of bars that fall on an earnings
+// report date of their symbol
+func (s *historicalService) annotateEarnings(ctx context.Context, data []model.HistoricalData, responseData []response.HistoricalDataResponse) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var symbols []string
+	first, last := data[0].Date, data[0].Date
+	for i := range data {
+		if !slices.Contains(symbols, data[i].Symbol) {
+			symbols = append(symbols, data[i].Symbol)
+		}
+		if data[i].Date.Before(first) {
+			first = data[i].Date
+		}
+		if data[i].Date.After(last) {
+			last = data[i].Date
+		}
+	}
+
+	events, err := s.earnings.FindBySymbols(ctx, symbols, first, last)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	annotations := make(map[string]*response.EarningsAnnotation, len(events))
+	for i := range events {
+		annotations[events[i].Symbol+"|"+events[i].ReportDate.Format("2006-01-02")] = &response.EarningsAnnotation{
+			Period:      events[i].Period,
+			EPSEstimate: events[i].EPSEstimate,
+			EPSActual:   events[i].EPSActual,
+			SurprisePct: surprisePct(events[i].EPSEstimate, events[i].EPSActual),
+		}
+	}
+	for i := range data {
+		responseData[i].Earnings = annotations[data[i].Symbol+"|"+data[i].Date.Format("2006-01-02")]
 	}
 	return nil
 }
 
 // toHistoricalDataResponse converts model to response DTO
-func (s *historicalService) toHistoricalDataResponse(data *model.HistoricalData) response.HistoricalDataResponse {
+func toHistoricalDataResponse(data *model.HistoricalData) response.HistoricalDataResponse {
 	return response.HistoricalDataResponse{
-		ID:        data.ID,
-		Symbol:    data.Symbol,
-		Date:      data.Date.Format("2006-01-02"),
-		Open:      data.Open,
-		High:      data.High,
-		Low:       data.Low,
-		Close:     data.Close,
-		Volume:    data.Volume,
-		CreatedAt: data.CreatedAt,
-		UpdatedAt: data.UpdatedAt,
+		ID:             data.ID,
+		Symbol:         data.Symbol,
+		Date:           data.Date.Format("2006-01-02"),
+		Open:           data.Open,
+		High:           data.High,
+		Low:            data.Low,
+		Close:          data.Close,
+		Volume:         data.Volume,
+		OpenInterest:   data.OpenInterest,
+		NumberOfTrades: data.NumberOfTrades,
+		SourceID:       data.SourceID,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
 	}
 }
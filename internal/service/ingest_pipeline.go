@@ -0,0 +1,636 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/ingest"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/csvparser"
+)
+
+// uploadPipeline moves the rows of an upload through the ingestion stages:
+// decode (parse a CSV record), map (to a bar), validate (business rules),
+// transform (the configured rewrites), symbol check (unknown symbols) and sink
+// (batched, parallel inserts).
+// A row rejected by a stage is recorded and the others keep flowing until the
+// error budget is spent.
+// A staged upload skips the validate and symbol check stages: its sink lands
+// the rows in the staging table, and merge then runs both checks in SQL over
+// the whole upload before the valid rows reach historical_data.
+type uploadPipeline struct {
+	parser     *csvparser.Parser
+	transforms ingest.Chain
+	locks      lockIndex
+	sourceID   *uint64
+	settings   config.IngestionConfig
+	repo       repository.HistoricalRepository
+	catalog    repository.SymbolRepository
+	resolver   SymbolResolver
+	bus        events.Bus
+
+	// staging, when set, receives the rows of the upload job jobID instead
+	// of historical_data
+	staging repository.StagingRepository
+	jobID   uint64
+
+	// known caches whether each symbol met by the symbol check existed
+	// before the upload, so every symbol is looked up once. Only read and
+	// written by the stage loop.
+	known map[string]bool
+
+	// checkpoint, when set, saves the position before which every row is
+	// stored or rejected, at most every uploadCheckpointInterval
+	checkpoint func(ctx context.Context, cp uploadCheckpoint)
+
+	mu           sync.Mutex // guards the counters below, shared with the sink workers
+	rowsRead     int        // parsed or not
+	totalRows    int
+	successCount int
+	failedCount  int
+	sinkFailed   int // rows of batches that failed to insert, part of failedCount
+	rowErrors    []response.CSVRowError
+	unlisted     int // rows rejected by the staged checks beyond the listed rowErrors
+	staged       int // rows landed in the staging table
+	symbols      map[string]struct{}
+	newSymbols   map[string]struct{} // symbols of the stored rows unknown before the upload
+
+	// Batches finish out of order; the checkpoint only moves past batches
+	// finished in sequence
+	dispatched     int                 // batches handed to the sink
+	committed      int                 // batches finished in sequence
+	finished       map[int]sinkOutcome // batches finished ahead of the sequence
+	baseSuccess    int                 // rows stored before a resume
+	storedInSeq    int                 // rows stored by the batches finished in sequence
+	failedInSeq    int                 // rows of the batches finished in sequence that failed to insert
+	lastCheckpoint time.Time
+
+	saveMu      sync.Mutex // orders checkpoint saves
+	savedOffset int64
+}
+
+// uploadCheckpointInterval is the minimum time between checkpoint saves
+const uploadCheckpointInterval = 5 * time.Second
+
+// uploadCheckpoint is a position of an upload before which every row is
+// stored or rejected, with the counts of those rows
+type uploadCheckpoint struct {
+	Offset       int64 // bytes of the file read
+	Line         int   // CSV records read, the header included
+	TotalRows    int
+	SuccessCount int
+	FailedCount  int
+	Symbols      []string
+}
+
+// sinkBatch is a batch of bars handed to the sink workers, with the position
+// of the upload after its last row
+type sinkBatch struct {
+	seq       int
+	bars      []model.HistoricalData
+	lines     []int // CSV line of each bar, kept for staged uploads
+	offset    int64
+	line      int
+	totalRows int
+	rejected  int // rows rejected before the sink so far
+
+	newSymbols []string // unknown symbols of the bars, registered with them in register mode
+}
+
+// sinkOutcome is the result of a finished batch
+type sinkOutcome struct {
+	batch  *sinkBatch
+	stored int
+	failed int
+}
+
+// resume continues the counts of an upload from its checkpoint
+func (p *uploadPipeline) resume(cp uploadCheckpoint) {
+	p.rowsRead = cp.Line - 1 // the header is not a row
+	p.totalRows = cp.TotalRows
+	p.successCount = cp.SuccessCount
+	p.failedCount = cp.FailedCount
+	p.baseSuccess = cp.SuccessCount
+	p.savedOffset = cp.Offset
+	p.symbols = make(map[string]struct{}, len(cp.Symbols))
+	for _, symbol := range cp.Symbols {
+		p.symbols[symbol] = struct{}{}
+	}
+}
+
+// run drains the parser through the stages, returning why the upload was
+// aborted or "" when every row was read
+func (p *uploadPipeline) run(ctx context.Context) string {
+	if p.symbols == nil {
+		p.symbols = make(map[string]struct{})
+	}
+	p.newSymbols = make(map[string]struct{})
+	p.known = make(map[string]bool)
+	p.finished = make(map[int]sinkOutcome)
+	batches, wait := p.startSink(ctx)
+
+	var abortedReason string
+	batch := make([]model.HistoricalData, 0, p.settings.BatchSize)
+	var lines []int
+	batchNew := make(map[string]struct{})
+	for {
+		// Stop early once the error budget is spent
+		if abortedReason = p.abortReason(); abortedReason != "" {
+			break
+		}
+
+		// Decode
+		row, err := p.parser.ParseRow()
+		if err == io.EOF {
+			break
+		}
+		p.mu.Lock()
+		p.rowsRead++
+		p.mu.Unlock()
+		if err != nil {
+			p.reject(1, parseRowError(err, p.parser.GetCurrentLine()))
+			continue
+		}
+		p.mu.Lock()
+		p.totalRows++
+		p.mu.Unlock()
+
+		// Map
+		bar := barFromRow(row, p.sourceID)
+
+		// Validate
+		if p.staging == nil {
+			if err := validateBar(&bar); err != nil {
+				p.reject(1, validationRowError(err, row, p.parser.GetCurrentLine()))
+				continue
+			}
+		}
+
+		// Transform
+		if err := p.transforms.Apply(&bar); err != nil {
+			p.reject(1, response.CSVRowError{
+				Line:    p.parser.GetCurrentLine(),
+				Code:    RowErrorTransformFailed,
+				Message: err.Error(),
+			})
+			continue
+		}
+
+		// Readers that could not be scanned ahead drop frozen rows one by one
+		if lock := p.locks.find(bar.Symbol, bar.Date); lock != nil {
+			p.reject(1, lockedRowError(lock, row, p.parser.GetCurrentLine()))
+			continue
+		}
+
+		// Staged rows go to the sink unchecked; merge checks their symbols
+		if p.staging != nil {
+			batch = append(batch, bar)
+			lines = append(lines, p.parser.GetCurrentLine())
+			if len(batch) >= p.settings.BatchSize {
+				batches <- p.newStagedBatch(batch, lines)
+				batch = make([]model.HistoricalData, 0, p.settings.BatchSize)
+				lines = nil
+			}
+			continue
+		}
+
+		// Symbol check: unknown symbols are rejected in strict mode, or
+		// stored with the batch carrying their first rows
+		known, err := p.isKnownSymbol(ctx, bar.Symbol)
+		if err != nil {
+			p.reject(1, response.CSVRowError{
+				Line:     p.parser.GetCurrentLine(),
+				Field:    "symbol",
+				RawValue: bar.Symbol,
+				Code:     RowErrorSymbolLookup,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		if !known {
+			if p.settings.UnknownSymbols == UnknownSymbolsReject {
+				p.reject(1, response.CSVRowError{
+					Line:     p.parser.GetCurrentLine(),
+					Field:    "symbol",
+					RawValue: bar.Symbol,
+					Code:     RowErrorUnknownSymbol,
+					Message:  fmt.Sprintf("unknown symbol %s: register its metadata before uploading it", bar.Symbol),
+				})
+				continue
+			}
+			batchNew[bar.Symbol] = struct{}{}
+		}
+
+		// Sink, handing the batch to a worker when it reaches the size limit
+		batch = append(batch, bar)
+		if len(batch) >= p.settings.BatchSize {
+			batches <- p.newSinkBatch(batch, batchNew)
+			batch = make([]model.HistoricalData, 0, p.settings.BatchSize)
+			batchNew = make(map[string]struct{})
+		}
+	}
+
+	// Flush the remaining batch unless the upload was aborted
+	if len(batch) > 0 && abortedReason == "" {
+		if p.staging != nil {
+			batches <- p.newStagedBatch(batch, lines)
+		} else {
+			batches <- p.newSinkBatch(batch, batchNew)
+		}
+	}
+	close(batches)
+	wait()
+
+	return abortedReason
+}
+
+// merge validates the staged rows of the upload with set-based checks, then,
+// unless the rejected rows spend the error budget, registers the stubs of
+// their unknown symbols in register mode and merges the valid rows into
+// historical_data in one statement. It returns why the upload was aborted,
+// or "" once the rows are merged. A failure of the database rejects every
+// staged row, as a failed batch insert does its rows.
+func (p *uploadPipeline) merge(ctx context.Context) string {
+	checks := repository.StagingChecks{
+		HighBelowLow:       RowErrorHighBelowLow,
+		OpenOutOfRange:     RowErrorOpenOutOfRange,
+		CloseOutOfRange:    RowErrorCloseOutOfRange,
+		FutureDate:         RowErrorFutureDate,
+		TradesExceedVolume: RowErrorTradesExceedVolume,
+		NonPositivePrice:   RowErrorNonPositivePrice,
+		Duplicate:          RowErrorDuplicate,
+		LatestDate:         latestToday(time.Now()),
+	}
+	if p.settings.UnknownSymbols == UnknownSymbolsReject {
+		checks.UnknownSymbol = RowErrorUnknownSymbol
+	}
+	rejected, err := p.staging.Validate(ctx, p.jobID, checks)
+	if err != nil {
+		p.rejectStaged(p.staged, err)
+		return ""
+	}
+
+	var failed int
+	for _, rows := range rejected {
+		failed += int(rows)
+	}
+	if failed > 0 {
+		listed, err := p.staging.FindRejected(ctx, p.jobID, maxReportedErrors)
+		if err != nil {
+			p.rejectStaged(p.staged, err)
+			return ""
+		}
+		p.mu.Lock()
+		for i := range listed {
+			p.rowErrors = append(p.rowErrors, stagedRowError(&listed[i]))
+		}
+		p.failedCount += failed
+		p.unlisted = failed - len(listed)
+		p.mu.Unlock()
+	}
+	if reason := p.abortReason(); reason != "" {
+		return reason
+	}
+
+	accepted := p.staged - failed
+	if accepted == 0 {
+		return ""
+	}
+	var unknown []string
+	if checks.UnknownSymbol == "" {
+		if unknown, err = p.staging.UnknownSymbols(ctx, p.jobID); err != nil {
+			p.rejectStaged(accepted, err)
+			return ""
+		}
+	}
+	summary, err := p.staging.Summarize(ctx, p.jobID)
+	if err != nil {
+		p.rejectStaged(accepted, err)
+		return ""
+	}
+
+	var stubs []model.Symbol
+	if p.settings.UnknownSymbols == UnknownSymbolsRegister {
+		stubs = make([]model.Symbol, len(unknown))
+		for i, symbol := range unknown {
+			stubs[i] = model.Symbol{Symbol: symbol, Currency: p.settings.StubCurrency}
+		}
+	}
+	if err := p.staging.Merge(ctx, p.jobID, stubs); err != nil {
+		p.rejectStaged(accepted, err)
+		return ""
+	}
+
+	event := events.BarsIngested{Count: accepted, OccurredAt: time.Now()}
+	p.mu.Lock()
+	p.successCount += accepted
+	for _, s := range summary {
+		p.symbols[s.Symbol] = struct{}{}
+		event.Symbols = append(event.Symbols, s.Symbol)
+		if event.StartDate.IsZero() || s.FirstDate.Before(event.StartDate) {
+			event.StartDate = s.FirstDate
+		}
+		if s.LastDate.After(event.EndDate) {
+			event.EndDate = s.LastDate
+		}
+	}
+	for _, symbol := range unknown {
+		p.newSymbols[symbol] = struct{}{}
+	}
+	p.mu.Unlock()
+	p.bus.Publish(ctx, event)
+	return ""
+}
+
+// rejectStaged records staged rows left unmerged by a database failure
+func (p *uploadPipeline) rejectStaged(rows int, err error) {
+	p.reject(rows, response.CSVRowError{
+		Code:    RowErrorStagingFailed,
+		Message: fmt.Sprintf("staged merge error: %v", err),
+	})
+}
+
+// newSinkBatch wraps bars for the sink with the position of the parser and
+// the unknown symbols among them that no stored batch has carried yet
+func (p *uploadPipeline) newSinkBatch(bars []model.HistoricalData, unknown map[string]struct{}) *sinkBatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	batch := &sinkBatch{
+		seq:       p.dispatched,
+		bars:      bars,
+		offset:    p.parser.Offset(),
+		line:      p.parser.GetCurrentLine(),
+		totalRows: p.totalRows,
+		rejected:  p.failedCount - p.sinkFailed,
+	}
+	for symbol := range unknown {
+		if _, stored := p.newSymbols[symbol]; !stored {
+			batch.newSymbols = append(batch.newSymbols, symbol)
+		}
+	}
+	p.dispatched++
+	return batch
+}
+
+// newStagedBatch wraps bars for the staging table with their CSV lines
+func (p *uploadPipeline) newStagedBatch(bars []model.HistoricalData, lines []int) *sinkBatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	batch := &sinkBatch{seq: p.dispatched, bars: bars, lines: lines}
+	p.dispatched++
+	return batch
+}
+
+// isKnownSymbol reports whether symbol existed before the upload: it has
+// metadata, is a former name or has stored bars
+func (p *uploadPipeline) isKnownSymbol(ctx context.Context, symbol string) (bool, error) {
+	if known, ok := p.known[symbol]; ok {
+		return known, nil
+	}
+
+	metadata, err := p.catalog.FindBySymbol(ctx, symbol)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up symbol %s: %w", symbol, err)
+	}
+	known := metadata != nil
+	if !known {
+		current, err := p.resolver.Resolve(ctx, symbol)
+		if err != nil {
+			return false, err
+		}
+		known = current != symbol
+	}
+	if !known {
+		counts, err := p.repo.CountBySymbol(ctx, []string{symbol})
+		if err != nil {
+			return false, fmt.Errorf("failed to look up symbol %s: %w", symbol, err)
+		}
+		known = counts[symbol] > 0
+	}
+	p.known[symbol] = known
+	return known, nil
+}
+
+// store persists a batch, registering stubs of its unknown symbols in
+// register mode, or stages it
+func (p *uploadPipeline) store(ctx context.Context, batch *sinkBatch) error {
+	if p.staging != nil {
+		rows := make([]model.StagedBar, len(batch.bars))
+		for i := range batch.bars {
+			rows[i] = model.NewStagedBar(p.jobID, batch.lines[i], &batch.bars[i])
+		}
+		return p.staging.Stage(ctx, rows)
+	}
+	if p.settings.UnknownSymbols != UnknownSymbolsRegister || len(batch.newSymbols) == 0 {
+		return p.repo.BulkCreate(ctx, batch.bars, len(batch.bars))
+	}
+	stubs := make([]model.Symbol, len(batch.newSymbols))
+	for i, symbol := range batch.newSymbols {
+		stubs[i] = model.Symbol{Symbol: symbol, Currency: p.settings.StubCurrency}
+	}
+	return p.repo.BulkCreateWithSymbols(ctx, batch.bars, len(batch.bars), stubs)
+}
+
+// startSink starts the workers persisting batches in parallel, bounded by
+// MaxParallelBatches. wait returns once the batches channel is closed and
+// drained.
+func (p *uploadPipeline) startSink(ctx context.Context) (chan<- *sinkBatch, func()) {
+	batches := make(chan *sinkBatch)
+	var workers sync.WaitGroup
+	for i := 0; i < p.settings.MaxParallelBatches; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for batch := range batches {
+				if err := p.store(ctx, batch); err != nil {
+					// Log error but continue with next batch
+					p.reject(len(batch.bars), response.CSVRowError{
+						Code:    RowErrorBatchInsert,
+						Message: fmt.Sprintf("batch insert error: %v", err),
+					})
+					p.mu.Lock()
+					p.sinkFailed += len(batch.bars)
+					p.mu.Unlock()
+					p.finish(ctx, sinkOutcome{batch: batch, failed: len(batch.bars)})
+					continue
+				}
+
+				// Staged rows are counted once merged
+				if p.staging != nil {
+					p.mu.Lock()
+					p.staged += len(batch.bars)
+					p.mu.Unlock()
+					continue
+				}
+
+				event := newBarsIngestedEvent(batch.bars)
+				p.mu.Lock()
+				p.successCount += len(batch.bars)
+				for _, symbol := range event.Symbols {
+					p.symbols[symbol] = struct{}{}
+				}
+				for _, symbol := range batch.newSymbols {
+					p.newSymbols[symbol] = struct{}{}
+				}
+				p.mu.Unlock()
+				p.bus.Publish(ctx, event)
+				p.finish(ctx, sinkOutcome{batch: batch, stored: len(batch.bars)})
+			}
+		}()
+	}
+	return batches, workers.Wait
+}
+
+// finish records a finished batch, moves the checkpoint past the batches
+// finished in sequence and saves it when due
+func (p *uploadPipeline) finish(ctx context.Context, outcome sinkOutcome) {
+	if p.checkpoint == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.finished[outcome.batch.seq] = outcome
+	var cp *uploadCheckpoint
+	for {
+		next, ok := p.finished[p.committed]
+		if !ok {
+			break
+		}
+		delete(p.finished, p.committed)
+		p.committed++
+		p.storedInSeq += next.stored
+		p.failedInSeq += next.failed
+		cp = &uploadCheckpoint{
+			Offset:       next.batch.offset,
+			Line:         next.batch.line,
+			TotalRows:    next.batch.totalRows,
+			SuccessCount: p.baseSuccess + p.storedInSeq,
+			FailedCount:  next.batch.rejected + p.failedInSeq,
+		}
+	}
+	due := cp != nil && time.Since(p.lastCheckpoint) >= uploadCheckpointInterval
+	if due {
+		p.lastCheckpoint = time.Now()
+		cp.Symbols = p.uploadedSymbols()
+	}
+	p.mu.Unlock()
+	if !due {
+		return
+	}
+
+	p.saveMu.Lock()
+	defer p.saveMu.Unlock()
+	if cp.Offset > p.savedOffset {
+		p.checkpoint(ctx, *cp)
+		p.savedOffset = cp.Offset
+	}
+}
+
+// reject records rows that failed a stage
+func (p *uploadPipeline) reject(rows int, rowErr response.CSVRowError) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rowErrors = append(p.rowErrors, rowErr)
+	p.failedCount += rows
+}
+
+// abortReason reports why the upload should stop early, or "" to continue
+func (p *uploadPipeline) abortReason() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.settings.MaxErrors > 0 && p.failedCount > p.settings.MaxErrors {
+		return fmt.Sprintf("%d rows failed, exceeding the limit of %d", p.failedCount, p.settings.MaxErrors)
+	}
+	if p.settings.MaxErrorRate > 0 && p.rowsRead >= p.settings.ErrorRateMinRows && p.rowsRead > 0 {
+		if rate := float64(p.failedCount) * 100 / float64(p.rowsRead); rate > p.settings.MaxErrorRate {
+			return fmt.Sprintf("%.1f%% of the first %d rows failed, exceeding the limit of %.1f%%", rate, p.rowsRead, p.settings.MaxErrorRate)
+		}
+	}
+	return ""
+}
+
+// uploadedSymbols returns the sorted symbols of the stored rows
+func (p *uploadPipeline) uploadedSymbols() []string {
+	symbols := make([]string, 0, len(p.symbols))
+	for symbol := range p.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// newUploadedSymbols returns the sorted symbols of the stored rows that
+// were unknown before the upload
+func (p *uploadPipeline) newUploadedSymbols() []string {
+	symbols := make([]string, 0, len(p.newSymbols))
+	for symbol := range p.newSymbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// stagedRowError describes a staged row rejected by a set-based check. The
+// business rules are checked again on the row to describe it exactly as a
+// row rejected by the validate stage.
+func stagedRowError(row *model.StagedBar) response.CSVRowError {
+	code := ""
+	if row.ErrorCode != nil {
+		code = *row.ErrorCode
+	}
+	rowErr := response.CSVRowError{Line: row.Line, Code: code}
+	switch code {
+	case RowErrorDuplicate:
+		rowErr.Field = "date"
+		rowErr.RawValue = row.Date.Format("2006-01-02")
+		rowErr.Message = fmt.Sprintf("%s on %s is repeated on a later line, which is kept", row.Symbol, rowErr.RawValue)
+	case RowErrorUnknownSymbol:
+		rowErr.Field = "symbol"
+		rowErr.RawValue = row.Symbol
+		rowErr.Message = fmt.Sprintf("unknown symbol %s: register its metadata before uploading it", row.Symbol)
+	default:
+		bar := row.Bar()
+		var barErr *BarValidationError
+		if err := validateBar(&bar); errors.As(err, &barErr) {
+			rowErr.Field = barErr.Field
+			rowErr.Message = barErr.Message
+			rowErr.RawValue = csvRowValue(&csvparser.HistoricalDataRow{
+				Date:           bar.Date,
+				Open:           bar.Open,
+				High:           bar.High,
+				Low:            bar.Low,
+				Close:          bar.Close,
+				NumberOfTrades: bar.NumberOfTrades,
+			}, barErr.Field)
+		} else {
+			rowErr.Message = fmt.Sprintf("rejected by the %s check", code)
+		}
+	}
+	return rowErr
+}
+
+// barFromRow maps a parsed CSV row to the bar stored for it
+func barFromRow(row *csvparser.HistoricalDataRow, sourceID *uint64) model.HistoricalData {
+	return model.HistoricalData{
+		Symbol:         row.Symbol,
+		Date:           row.Date,
+		Open:           row.Open,
+		High:           row.High,
+		Low:            row.Low,
+		Close:          row.Close,
+		Volume:         row.Volume,
+		OpenInterest:   row.OpenInterest,
+		NumberOfTrades: row.NumberOfTrades,
+		SourceID:       sourceID,
+		Attributes:     row.Attributes,
+	}
+}
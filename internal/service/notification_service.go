@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/notify"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// notificationQueueSize bounds the messages awaiting delivery; further
+// messages are dropped while delivery is stuck
+const notificationQueueSize = 100
+
+// NotificationService notifies operators of failed ingestion and stale symbols
+// over email and Slack
+type NotificationService interface {
+	// UploadCompleted queues a notification for a failed upload or one whose
+	// share of failed rows exceeds the threshold
+	UploadCompleted(event events.UploadCompleted)
+	// BackfillCompleted queues a notification for a failed backfill or one whose
+	// share of failed chunks exceeds the threshold
+	BackfillCompleted(event events.BackfillCompleted)
+	// Run delivers queued notifications and checks freshness SLAs until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// notificationService implements NotificationService interface
+type notificationService struct {
+	summaries repository.SymbolSummaryRepository
+	notifiers *notify.Registry
+	cfg       config.NotificationsConfig
+
+	queue chan notify.Message
+	stale map[string]bool // symbols notified as stale, owned by Run
+}
+
+// NewNotificationService creates a new notification service instance
+func NewNotificationService(summaries repository.SymbolSummaryRepository, notifiers *notify.Registry, cfg config.NotificationsConfig) NotificationService {
+	return &notificationService{
+		summaries: summaries,
+		notifiers: notifiers,
+		cfg:       cfg,
+		queue:     make(chan notify.Message, notificationQueueSize),
+		stale:     make(map[string]bool),
+	}
+}
+
+// UploadCompleted queues a notification for an upload that failed or exceeded the error rate
+func (s *notificationService) UploadCompleted(event events.UploadCompleted) {
+	var reason string
+	switch {
+	case event.Status == model.UploadStatusFailed:
+		reason = "failed"
+	case s.exceedsErrorRate(event.FailedCount, event.TotalRows):
+		reason = fmt.Sprintf("exceeded the error rate with %d of %d rows failed", event.FailedCount, event.TotalRows)
+	default:
+		return
+	}
+
+	s.enqueue(notify.Message{
+		Subject: fmt.Sprintf("Upload #%d %s: %s", event.JobID, reason, event.Filename),
+		Text: fmt.Sprintf("Upload #%d of %s for tenant %q %s.\n\n%s\nRows: %d total, %d ingested, %d failed.\n",
+			event.JobID, event.Filename, event.Tenant, reason, event.Message, event.TotalRows, event.SuccessCount, event.FailedCount),
+		Payload: event,
+	})
+}
+
+// BackfillCompleted queues a notification for a backfill that failed or exceeded the error rate
+func (s *notificationService) BackfillCompleted(event events.BackfillCompleted) {
+	chunks := event.CompletedChunks + event.FailedChunks
+
+	var reason string
+	switch {
+	case event.Status == model.BackfillStatusFailed:
+		reason = "failed"
+	case s.exceedsErrorRate(event.FailedChunks, chunks):
+		reason = fmt.Sprintf("exceeded the error rate with %d of %d chunks failed", event.FailedChunks, chunks)
+	default:
+		return
+	}
+
+	s.enqueue(notify.Message{
+		Subject: fmt.Sprintf("Backfill #%d from %s %s", event.BackfillID, event.Provider, reason),
+		Text: fmt.Sprintf("Backfill #%d from %s finished as %s.\n\nChunks: %d completed, %d failed.\nRows ingested: %d.\n",
+			event.BackfillID, event.Provider, event.Status, event.CompletedChunks, event.FailedChunks, event.RowsIngested),
+		Payload: event,
+	})
+}
+
+// Run delivers queued messages one at a time and checks freshness SLAs on
+// every interval
+func (s *notificationService) Run(ctx context.Context) {
+	interval := time.Duration(max(s.cfg.FreshnessInterval, 60)) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-s.queue:
+			s.deliver(ctx, msg)
+		case <-ticker.C:
+			s.checkFreshness(ctx)
+		}
+	}
+}
+
+// checkFreshness notifies once when a watched symbol's latest bar becomes
+// older than its SLA, and again when fresh bars arrive
+func (s *notificationService) checkFreshness(ctx context.Context) {
+	if len(s.cfg.Freshness) == 0 {
+		return
+	}
+
+	symbols := make([]string, len(s.cfg.Freshness))
+	for i, sla := range s.cfg.Freshness {
+		symbols[i] = strings.ToUpper(sla.Symbol)
+	}
+	summaries, err := s.summaries.FindBySymbols(ctx, symbols)
+	if err != nil {
+		logger.GetGlobalLogger().Error().Err(err).Msg("Failed to check data freshness")
+		return
+	}
+	lastDates := make(map[string]time.Time, len(summaries))
+	for _, summary := range summaries {
+		lastDates[summary.Symbol] = summary.LastDate
+	}
+
+	now := time.Now()
+	for i, sla := range s.cfg.Freshness {
+		symbol := symbols[i]
+		maxAge := time.Duration(sla.MaxAge) * time.Hour
+		lastDate, ok := lastDates[symbol]
+		stale := !ok || now.Sub(lastDate) > maxAge
+
+		switch {
+		case stale && !s.stale[symbol]:
+			latest := "no bars"
+			if ok {
+				latest = "latest bar on " + lastDate.Format("2006-01-02")
+			}
+			s.deliver(ctx, notify.Message{
+				Subject: fmt.Sprintf("%s data is stale: %s", symbol, latest),
+				Text:    fmt.Sprintf("%s has %s, breaching its freshness SLA of %d hours.\n", symbol, latest, sla.MaxAge),
+				Payload: map[string]interface{}{
+					"symbol":    symbol,
+					"last_date": lastDates[symbol],
+					"max_age":   sla.MaxAge,
+					"stale":     true,
+				},
+			})
+		case !stale && s.stale[symbol]:
+			s.deliver(ctx, notify.Message{
+				Subject: fmt.Sprintf("%s data is fresh again", symbol),
+				Text:    fmt.Sprintf("%s has a bar on %s, within its freshness SLA of %d hours.\n", symbol, lastDate.Format("2006-01-02"), sla.MaxAge),
+				Payload: map[string]interface{}{
+					"symbol":    symbol,
+					"last_date": lastDate,
+					"max_age":   sla.MaxAge,
+					"stale":     false,
+				},
+			})
+		}
+		s.stale[symbol] = stale
+	}
+}
+
+// deliver sends a message to every configured email recipient and the Slack
+// webhook. Failures are logged; notifications are not retried.
+func (s *notificationService) deliver(ctx context.Context, msg notify.Message) {
+	log := logger.GetGlobalLogger()
+
+	timeout := time.Duration(max(s.cfg.Timeout, 1)) * time.Second
+	send := func(channel, target string) {
+		notifier, err := s.notifiers.Get(channel)
+		if err != nil {
+			log.Warn().Err(err).Str("subject", msg.Subject).Msg("Notification channel is not configured")
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		if err := notifier.Notify(ctx, target, msg); err != nil {
+			log.Error().Err(err).Str("channel", channel).Str("subject", msg.Subject).Msg("Failed to deliver notification")
+		}
+	}
+
+	for _, address := range s.cfg.Email {
+		send(notify.EmailChannel, strings.TrimSpace(address))
+	}
+	if s.cfg.SlackWebhookURL != "" {
+		send(notify.SlackChannel, s.cfg.SlackWebhookURL)
+	}
+}
+
+// enqueue queues a message without blocking the publisher
+func (s *notificationService) enqueue(msg notify.Message) {
+	select {
+	case s.queue <- msg:
+	default:
+		logger.GetGlobalLogger().Warn().Str("subject", msg.Subject).Msg("Notification queue is full, dropping notification")
+	}
+}
+
+// exceedsErrorRate reports whether failed out of total exceeds the threshold
+func (s *notificationService) exceedsErrorRate(failed, total int) bool {
+	return s.cfg.ErrorRateThreshold > 0 && total > 0 && float64(failed)/float64(total) > s.cfg.ErrorRateThreshold
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/database"
+	"golang.org/x/sync/errgroup"
+)
+
+// errorRateWindow is the window ingestion error rates are computed over
+const errorRateWindow = 24 * time.Hour
+
+// OverviewService gathers the operational overview of the admin dashboard
+type OverviewService interface {
+	GetOverview(ctx context.Context, req *request.GetOverviewRequest) (*response.OverviewResponse, error)
+	GetRecentUploads(ctx context.Context, req *request.GetOverviewRequest) ([]model.UploadJob, error)
+	GetQueues(ctx context.Context) (*response.QueueDepthResponse, error)
+	GetFreshness(ctx context.Context) ([]response.SourceFreshness, error)
+	GetErrorRates(ctx context.Context) (*response.ErrorRatesResponse, error)
+	GetCacheStats() *response.CacheStatsResponse
+	GetDatabaseStats() (*response.DBPoolResponse, error)
+}
+
+// overviewService implements OverviewService interface
+type overviewService struct {
+	repo         repository.OverviewRepository
+	uploads      repository.UploadJobRepository
+	pulls        repository.PullRepository
+	res          *database.Resilience
+	cacheEnabled bool
+}
+
+// NewOverviewService creates a new overview service instance
+func NewOverviewService(repo repository.OverviewRepository, uploads repository.UploadJobRepository, pulls repository.PullRepository, res *database.Resilience, cacheEnabled bool) OverviewService {
+	return &overviewService{
+		repo:         repo,
+		uploads:      uploads,
+		pulls:        pulls,
+		res:          res,
+		cacheEnabled: cacheEnabled,
+	}
+}
+
+// GetOverview gathers every section of the overview concurrently
+func (s *overviewService) GetOverview(ctx context.Context, req *request.GetOverviewRequest) (*response.OverviewResponse, error) {
+	result := &response.OverviewResponse{
+		GeneratedAt: time.Now().UTC(),
+		Cache:       *s.GetCacheStats(),
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		uploads, err := s.GetRecentUploads(ctx, req)
+		result.RecentUploads = uploads
+		return err
+	})
+	g.Go(func() error {
+		queues, err := s.GetQueues(ctx)
+		if err == nil {
+			result.Queues = *queues
+		}
+		return err
+	})
+	g.Go(func() error {
+		freshness, err := s.GetFreshness(ctx)
+		result.Freshness = freshness
+		return err
+	})
+	g.Go(func() error {
+		rates, err := s.GetErrorRates(ctx)
+		if err == nil {
+			result.ErrorRates = *rates
+		}
+		return err
+	})
+	g.Go(func() error {
+		db, err := s.GetDatabaseStats()
+		if err == nil {
+			result.Database = *db
+		}
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetRecentUploads retrieves the latest upload jobs of every tenant
+func (s *overviewService) GetRecentUploads(ctx context.Context, req *request.GetOverviewRequest) ([]model.UploadJob, error) {
+	req.SetDefaults()
+
+	jobs, _, err := s.uploads.FindAll(ctx, map[string]interface{}{}, req.Limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent uploads: %w", err)
+	}
+	return jobs, nil
+}
+
+// GetQueues counts the unfinished jobs of every queue by status
+func (s *overviewService) GetQueues(ctx context.Context) (*response.QueueDepthResponse, error) {
+	queues, err := s.repo.CountQueues(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue depth: %w", err)
+	}
+
+	return &response.QueueDepthResponse{
+		Uploads:        queues["uploads"],
+		Backfills:      queues["backfills"],
+		BackfillChunks: queues["backfill_chunks"],
+		Exports:        queues["exports"],
+	}, nil
+}
+
+// GetFreshness reports the latest ingest of every backfill provider, tenant
+// uploading files and pull source
+func (s *overviewService) GetFreshness(ctx context.Context) ([]response.SourceFreshness, error) {
+	sources, err := s.repo.FindSourceFreshness(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source freshness: %w", err)
+	}
+	states, err := s.pulls.FindStates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source freshness: %w", err)
+	}
+
+	now := time.Now()
+	result := make([]response.SourceFreshness, 0, len(sources)+len(states))
+	for _, source := range sources {
+		lastIngestAt := source.LastIngestAt
+		age := int64(now.Sub(lastIngestAt).Seconds())
+		result = append(result, response.SourceFreshness{
+			Kind:         source.Kind,
+			Name:         source.Name,
+			LastIngestAt: &lastIngestAt,
+			AgeSeconds:   &age,
+			Ingests:      source.Ingests,
+		})
+	}
+	for _, state := range states {
+		freshness := response.SourceFreshness{
+			Kind:         "pull",
+			Name:         state.Name,
+			LastIngestAt: state.LastSuccessAt,
+			Ingests:      state.FilesIngested,
+			LastError:    state.LastError,
+		}
+		if state.LastSuccessAt != nil {
+			age := int64(now.Sub(*state.LastSuccessAt).Seconds())
+			freshness.AgeSeconds = &age
+		}
+		result = append(result, freshness)
+	}
+	return result, nil
+}
+
+// GetErrorRates reports the failed uploads and backfills of the last day and
+// the failed HTTP requests since the process started
+func (s *overviewService) GetErrorRates(ctx context.Context) (*response.ErrorRatesResponse, error) {
+	since := time.Now().Add(-errorRateWindow)
+
+	uploads, err := s.repo.UploadStats(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get error rates: %w", err)
+	}
+	backfills, err := s.repo.BackfillStats(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get error rates: %w", err)
+	}
+	requests := middleware.GetRequestStats()
+
+	return &response.ErrorRatesResponse{
+		WindowHours: int(errorRateWindow.Hours()),
+		Uploads:     newJobErrorRate(uploads),
+		Backfills:   newJobErrorRate(backfills),
+		HTTP: response.HTTPErrorRate{
+			Requests:        requests.Total,
+			ClientErrors:    requests.ClientErrors,
+			ServerErrors:    requests.ServerErrors,
+			ServerErrorRate: ratio(requests.ServerErrors, requests.Total),
+		},
+	}, nil
+}
+
+// GetCacheStats reports the response cache lookups since the process started
+func (s *overviewService) GetCacheStats() *response.CacheStatsResponse {
+	result := &response.CacheStatsResponse{
+		Enabled: s.cacheEnabled,
+		Routes:  []response.CacheRouteStats{},
+	}
+	for _, route := range middleware.GetCacheStats() {
+		result.Hits += route.Hits
+		result.Misses += route.Misses
+		result.Routes = append(result.Routes, response.CacheRouteStats{
+			Route:    route.Route,
+			Hits:     route.Hits,
+			Misses:   route.Misses,
+			HitRatio: ratio(route.Hits, route.Hits+route.Misses),
+		})
+	}
+	result.HitRatio = ratio(result.Hits, result.Hits+result.Misses)
+	return result
+}
+
+// GetDatabaseStats reports the connection pool and circuit breaker state
+func (s *overviewService) GetDatabaseStats() (*response.DBPoolResponse, error) {
+	stats, err := s.repo.PoolStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database pool stats: %w", err)
+	}
+
+	return &response.DBPoolResponse{
+		CircuitBreaker:     s.res.State(),
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}, nil
+}
+
+// newJobErrorRate computes the failure rates of aggregated jobs
+func newJobErrorRate(stats *repository.JobStats) response.JobErrorRate {
+	return response.JobErrorRate{
+		Jobs:        stats.Jobs,
+		FailedJobs:  stats.FailedJobs,
+		JobRate:     ratio(uint64(stats.FailedJobs), uint64(stats.Jobs)),
+		Items:       stats.Items,
+		FailedItems: stats.FailedItems,
+		ItemRate:    ratio(uint64(stats.FailedItems), uint64(stats.Items)),
+	}
+}
+
+// ratio returns part / total, 0 when total is 0
+func ratio(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// ErrNotPartitioned is returned when the historical data table has no partitions
+var ErrNotPartitioned = errors.New("historical data table is not partitioned")
+
+// PartitionService defines the interface for maintaining the monthly
+// partitions of the historical data table
+type PartitionService interface {
+	GetPartitions(ctx context.Context) (*response.PartitionsResponse, error)
+	Maintain(ctx context.Context) (*response.PartitionMaintenanceResponse, error)
+	Run(ctx context.Context)
+}
+
+// partitionService implements PartitionService interface
+type partitionService struct {
+	repo repository.PartitionRepository
+	cfg  config.PartitioningConfig
+}
+
+// NewPartitionService creates a new partition service instance
+func NewPartitionService(repo repository.PartitionRepository, cfg config.PartitioningConfig) PartitionService {
+	return &partitionService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// GetPartitions lists the partitions and checks that a query over the current
+// month is pruned to that month's partition
+func (s *partitionService) GetPartitions(ctx context.Context) (*response.PartitionsResponse, error) {
+	partitions, err := s.repo.ListPartitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.PartitionsResponse{
+		Partitioned: len(partitions) > 0,
+		Partitions:  make([]response.PartitionResponse, len(partitions)),
+	}
+	for i, p := range partitions {
+		result.Partitions[i] = response.PartitionResponse{
+			Name:          p.Name,
+			LessThan:      "MAXVALUE",
+			EstimatedRows: p.EstimatedRows,
+		}
+		if p.LessThan != nil {
+			result.Partitions[i].LessThan = p.LessThan.Format("2006-01-02")
+		}
+	}
+	if !result.Partitioned {
+		return result, nil
+	}
+
+	month := s.currentMonth()
+	next := month.AddDate(0, 1, 0)
+	read, err := s.repo.ExplainPartitions(ctx, month, next)
+	if err != nil {
+		return nil, err
+	}
+	expected := []string{repository.MonthlyPartitionName(month)}
+	result.Pruning = &response.PruningCheck{
+		StartDate:  month.Format("2006-01-02"),
+		EndDate:    next.Format("2006-01-02"),
+		Expected:   expected,
+		Partitions: read,
+		Pruned:     len(read) == 1 && read[0] == expected[0],
+	}
+	return result, nil
+}
+
+// Maintain creates the monthly partitions up to future_months ahead of the
+// current month and, with a retention, deletes the rows of the partitions whose
+// dates are all older than retention_months. The lowest of them, the catch-all
+// p_before, is truncated and extended over the others, which are dropped, so
+// bars older than the retention still land in it rather than in a kept month.
+func (s *partitionService) Maintain(ctx context.Context) (*response.PartitionMaintenanceResponse, error) {
+	partitions, err := s.repo.ListPartitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(partitions) == 0 {
+		return nil, apperror.Conflict(ErrNotPartitioned.Error(), nil).Wrap(ErrNotPartitioned)
+	}
+
+	result := &response.PartitionMaintenanceResponse{
+		Created:   make([]string, 0),
+		Dropped:   make([]string, 0),
+		Truncated: make([]string, 0),
+	}
+	month := s.currentMonth()
+
+	// Months from the highest bound through future_months ahead
+	var last time.Time
+	var maxValue string
+	for _, p := range partitions {
+		if p.LessThan == nil {
+			maxValue = p.Name
+		} else if p.LessThan.After(last) {
+			last = *p.LessThan
+		}
+	}
+	if last.IsZero() {
+		last = month
+	}
+	target := month.AddDate(0, s.cfg.FutureMonths+1, 0)
+	var months []time.Time
+	for m := last; m.Before(target); m = m.AddDate(0, 1, 0) {
+		months = append(months, m)
+		result.Created = append(result.Created, repository.MonthlyPartitionName(m))
+	}
+	if err := s.repo.AddMonthlyPartitions(ctx, months, maxValue); err != nil {
+		return nil, err
+	}
+
+	if s.cfg.RetentionMonths > 0 {
+		cutoff := month.AddDate(0, -s.cfg.RetentionMonths, 0)
+		var expired []string
+		var bound time.Time
+		for _, p := range partitions {
+			if p.LessThan != nil && !p.LessThan.After(cutoff) {
+				expired = append(expired, p.Name)
+				bound = *p.LessThan
+			}
+		}
+		if len(expired) > 0 {
+			if err := s.repo.ExpirePartitions(ctx, expired, bound); err != nil {
+				return nil, err
+			}
+			result.Truncated = append(result.Truncated, expired[0])
+			result.Dropped = append(result.Dropped, expired[1:]...)
+		}
+	}
+
+	return result, nil
+}
+
+// Run maintains the partitions on start and then every interval until ctx is
+// cancelled
+func (s *partitionService) Run(ctx context.Context) {
+	interval := time.Duration(s.cfg.Interval) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		log := logger.GetGlobalLogger()
+		result, err := s.Maintain(ctx)
+		switch {
+		case errors.Is(err, ErrNotPartitioned):
+			log.Warn().Msg("Historical data table is not partitioned; skipping partition maintenance")
+		case err != nil:
+			log.Error().Err(err).Msg("Partition maintenance failed")
+		case len(result.Created) > 0 || len(result.Dropped) > 0 || len(result.Truncated) > 0:
+			log.Info().
+				Strs("created", result.Created).
+				Strs("dropped", result.Dropped).
+				Strs("truncated", result.Truncated).
+				Msg("Partitions maintained")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// currentMonth returns the first day of the current month in UTC
+func (s *partitionService) currentMonth() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/remote"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// ErrUnknownPullSource is returned for a pull source that is not configured
+var ErrUnknownPullSource = errors.New("unknown pull source")
+
+// PullService defines the interface for polling SFTP and FTP drops
+type PullService interface {
+	GetSources(ctx context.Context) ([]response.PullSourceStatus, error)
+	GetFiles(ctx context.Context, name string, req *request.GetPullFilesRequest) (*response.PaginatedPullFileResponse, error)
+	// Poll schedules an immediate poll of a source
+	Poll(name string) error
+	// Run polls the configured sources on their intervals until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// pullService implements PullService interface
+type pullService struct {
+	repo       repository.PullRepository
+	jobs       repository.UploadJobRepository
+	historical HistoricalService
+	cfg        config.PullConfig
+
+	mu   sync.Mutex
+	next map[string]time.Time // next poll of each source
+	wake chan struct{}
+}
+
+// NewPullService creates a new pull service ingesting files through historical
+func NewPullService(repo repository.PullRepository, jobs repository.UploadJobRepository, historical HistoricalService, cfg config.PullConfig) PullService {
+	return &pullService{
+		repo:       repo,
+		jobs:       jobs,
+		historical: historical,
+		cfg:        cfg,
+		next:       make(map[string]time.Time, len(cfg.Sources)),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// GetSources reports the configured sources with their polling state
+func (s *pullService) GetSources(ctx context.Context) ([]response.PullSourceStatus, error) {
+	states, err := s.repo.FindStates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull sources: %w", err)
+	}
+	byName := make(map[string]model.PullSourceState, len(states))
+	for _, state := range states {
+		byName[state.Name] = state
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]response.PullSourceStatus, len(s.cfg.Sources))
+	for i, src := range s.cfg.Sources {
+		state, ok := byName[src.Name]
+		if !ok {
+			state = model.PullSourceState{Name: src.Name}
+		}
+		result[i] = response.PullSourceStatus{
+			PullSourceState: state,
+			Protocol:        src.Protocol,
+			Host:            src.Host,
+			Directory:       src.Directory,
+			Pattern:         src.Pattern,
+			ArchiveDir:      src.ArchiveDir,
+			Interval:        src.Interval,
+			Tenant:          src.Tenant,
+		}
+		if next, ok := s.next[src.Name]; ok {
+			result[i].NextPollAt = &next
+		}
+	}
+	return result, nil
+}
+
+// GetFiles lists the files pulled from a source, newest first
+func (s *pullService) GetFiles(ctx context.Context, name string, req *request.GetPullFilesRequest) (*response.PaginatedPullFileResponse, error) {
+	if _, ok := s.source(name); !ok {
+		return nil, apperror.NotFound("Pull source not found").Wrap(fmt.Errorf("%w: %q", ErrUnknownPullSource, name))
+	}
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"status": req.Status,
+	}
+
+	files, total, err := s.repo.FindFiles(ctx, name, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pulled files: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedPullFileResponse{
+		Data: files,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// Poll moves the next poll of a source to now and wakes the worker
+func (s *pullService) Poll(name string) error {
+	if _, ok := s.source(name); !ok {
+		return apperror.NotFound("Pull source not found").Wrap(fmt.Errorf("%w: %q", ErrUnknownPullSource, name))
+	}
+
+	s.mu.Lock()
+	s.next[name] = time.Now()
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run polls each source when due, picking up the schedule where the previous
+// process left it. Sources are polled one at a time; only one API instance
+// should run pull sources.
+func (s *pullService) Run(ctx context.Context) {
+	log := logger.GetGlobalLogger()
+
+	states := make(map[string]*model.PullSourceState, len(s.cfg.Sources))
+	stored, err := s.repo.FindStates(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load pull source states")
+	}
+	for i := range stored {
+		states[stored[i].Name] = &stored[i]
+	}
+
+	s.mu.Lock()
+	for _, src := range s.cfg.Sources {
+		next := time.Now()
+		if state, ok := states[src.Name]; ok && state.LastPollAt != nil {
+			next = state.LastPollAt.Add(s.interval(src))
+		} else {
+			states[src.Name] = &model.PullSourceState{Name: src.Name}
+		}
+		s.next[src.Name] = next
+	}
+	s.mu.Unlock()
+
+	for {
+		for _, src := range s.cfg.Sources {
+			if ctx.Err() != nil {
+				return
+			}
+			s.mu.Lock()
+			due := !s.next[src.Name].After(time.Now())
+			s.mu.Unlock()
+			if !due {
+				continue
+			}
+
+			s.poll(ctx, src, states[src.Name])
+
+			s.mu.Lock()
+			s.next[src.Name] = time.Now().Add(s.interval(src))
+			s.mu.Unlock()
+		}
+
+		timer := time.NewTimer(s.untilNextPoll())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		}
+	}
+}
+
+// poll fetches the new files of a source and records the outcome in its state
+func (s *pullService) poll(ctx context.Context, src config.PullSourceConfig, state *model.PullSourceState) {
+	log := logger.GetGlobalLogger()
+
+	now := time.Now()
+	state.LastPollAt = &now
+	err := s.pollSource(ctx, src, state)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		state.LastError = err.Error()
+		log.Error().Err(err).Str("source", src.Name).Msg("Pull source poll failed")
+	} else {
+		state.LastError = ""
+		state.LastSuccessAt = &now
+	}
+
+	if err := s.repo.SaveState(ctx, state); err != nil {
+		log.Error().Err(err).Str("source", src.Name).Msg("Failed to save pull source state")
+	}
+}
+
+// pollSource lists the source directory and ingests the matching files not
+// pulled before, oldest first. Failures of a single file are recorded with the
+// file; connection failures end the poll and are returned.
+func (s *pullService) pollSource(ctx context.Context, src config.PullSourceConfig, state *model.PullSourceState) error {
+	client, err := remote.Dial(ctx, src, s.timeout())
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	entries, err := client.List(src.Directory)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", src.Directory, err)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].ModTime.Equal(entries[j].ModTime) {
+			return entries[i].ModTime.Before(entries[j].ModTime)
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	pattern := src.Pattern
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	archiveReady := false
+	for _, entry := range entries {
+		if entry.IsDir {
+			continue
+		}
+		if matched, _ := path.Match(pattern, entry.Name); !matched {
+			continue
+		}
+		if entry.ModTime.IsZero() {
+			entry.ModTime = time.Unix(0, 0).UTC()
+		}
+
+		pulled, err := s.repo.HasFile(ctx, src.Name, entry.Name, entry.Size, entry.ModTime)
+		if err != nil {
+			return err
+		}
+		if pulled {
+			continue
+		}
+
+		file, err := s.pullFile(ctx, client, src, entry)
+		if err != nil {
+			return err
+		}
+
+		if file.Status == model.PullFileStatusIngested && src.ArchiveDir != "" {
+			if !archiveReady {
+				if err := client.Mkdir(src.ArchiveDir); err != nil {
+					return fmt.Errorf("failed to create archive directory %s: %w", src.ArchiveDir, err)
+				}
+				archiveReady = true
+			}
+			target := path.Join(src.ArchiveDir, time.Now().UTC().Format("20060102T150405")+"_"+entry.Name)
+			if err := client.Rename(path.Join(src.Directory, entry.Name), target); err != nil {
+				file.Error = fmt.Sprintf("ingested but not archived: %v", err)
+			} else {
+				file.ArchivedTo = target
+			}
+		}
+
+		if err := s.repo.CreateFile(ctx, file); err != nil {
+			return err
+		}
+		if file.Status == model.PullFileStatusIngested {
+			state.FilesIngested++
+			state.RowsIngested += int64(file.RowCount)
+		} else {
+			state.FilesFailed++
+		}
+	}
+	return nil
+}
+
+// pullFile downloads a file to the spool directory and ingests it through the
+// CSV upload pipeline with checkpoints. A file whose ingest was interrupted is
+// not downloaded again and resumes from its checkpoint. Only download failures
+// are returned; a rejected file is reported as failed.
+func (s *pullService) pullFile(ctx context.Context, client remote.Client, src config.PullSourceConfig, entry remote.FileInfo) (*model.PullFile, error) {
+	spoolPath := filepath.Join(s.cfg.SpoolDir, src.Name, filepath.Base(entry.Name))
+
+	info := UploadInfo{
+		Filename:  entry.Name,
+		Tenant:    src.Tenant,
+		APIKey:    src.Name,
+		Resumable: true,
+	}
+	interrupted, err := s.interruptedUpload(ctx, src, entry)
+	if err != nil {
+		return nil, err
+	}
+	if interrupted != nil && interrupted.FileSize == entry.Size && spooled(spoolPath, entry.Size) {
+		info.ResumeJobID = interrupted.ID
+		logger.GetGlobalLogger().Info().Str("source", src.Name).Str("file", entry.Name).
+			Uint64("job_id", interrupted.ID).Int64("offset", interrupted.ProcessedBytes).Msg("Resuming interrupted pull file")
+	} else {
+		if interrupted != nil {
+			s.abandonUpload(ctx, interrupted)
+		}
+		if err := s.download(client, path.Join(src.Directory, entry.Name), spoolPath); err != nil {
+			return nil, err
+		}
+	}
+
+	spool, err := os.Open(spoolPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open download file: %w", err)
+	}
+	defer spool.Close()
+	if stat, err := spool.Stat(); err == nil {
+		info.FileSize = stat.Size()
+	}
+
+	file := &model.PullFile{
+		Source:     src.Name,
+		Filename:   entry.Name,
+		Size:       entry.Size,
+		ModifiedAt: entry.ModTime,
+		Status:     model.PullFileStatusIngested,
+	}
+
+	result, err := s.historical.UploadCSV(ctx, spool, info)
+	if ctx.Err() != nil {
+		// Keep the download for the ingest to resume after a restart
+		return nil, ctx.Err()
+	}
+	_ = os.Remove(spoolPath)
+	if err != nil {
+		file.Status = model.PullFileStatusFailed
+		file.Error = err.Error()
+		var malformedErr *MalformedFileError
+		if errors.As(err, &malformedErr) {
+			file.UploadJobID = &malformedErr.JobID
+		}
+		return file, nil
+	}
+
+	file.UploadJobID = &result.JobID
+	file.RowCount = result.SuccessCount
+	return file, nil
+}
+
+// download copies a remote file to the spool directory, replacing a previous download
+func (s *pullService) download(client remote.Client, remotePath, spoolPath string) error {
+	if err := os.MkdirAll(filepath.Dir(spoolPath), 0o750); err != nil {
+		return fmt.Errorf("failed to create spool directory: %w", err)
+	}
+	spool, err := os.Create(spoolPath)
+	if err != nil {
+		return fmt.Errorf("failed to create download file: %w", err)
+	}
+	defer spool.Close()
+
+	reader, err := client.Open(remotePath)
+	if err != nil {
+		_ = os.Remove(spoolPath)
+		return fmt.Errorf("failed to open %s: %w", remotePath, err)
+	}
+	_, err = io.Copy(spool, reader)
+	if closeErr := reader.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(spoolPath)
+		return fmt.Errorf("failed to download %s: %w", remotePath, err)
+	}
+	return nil
+}
+
+// interruptedUpload returns the upload job of a file left processing by a
+// previous process, nil when there is none. Sources are polled one at a time,
+// so no upload of the source is running.
+func (s *pullService) interruptedUpload(ctx context.Context, src config.PullSourceConfig, entry remote.FileInfo) (*model.UploadJob, error) {
+	jobs, _, err := s.jobs.FindAll(ctx, map[string]interface{}{
+		"status":   model.UploadStatusProcessing,
+		"api_key":  src.Name,
+		"filename": entry.Name,
+	}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// abandonUpload fails an interrupted upload that cannot be resumed because
+// the file changed or its download is gone; the file is ingested afresh
+func (s *pullService) abandonUpload(ctx context.Context, job *model.UploadJob) {
+	finishedAt := time.Now()
+	job.Status = model.UploadStatusFailed
+	job.Message = "interrupted and not resumable, ingested again from the start"
+	job.FinishedAt = &finishedAt
+	if err := s.jobs.Update(ctx, job); err != nil {
+		logger.GetGlobalLogger().Error().Err(err).Uint64("job_id", job.ID).Msg("Failed to update upload job")
+	}
+}
+
+// spooled reports whether a complete download of a file is in the spool directory
+func spooled(spoolPath string, size int64) bool {
+	stat, err := os.Stat(spoolPath)
+	return err == nil && stat.Size() == size
+}
+
+// untilNextPoll returns the wait before the earliest scheduled poll
+func (s *pullService) untilNextPoll() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := time.Hour
+	for _, next := range s.next {
+		wait = min(wait, time.Until(next))
+	}
+	return max(wait, time.Second)
+}
+
+// interval returns the polling interval of a source, at least a minute
+func (s *pullService) interval(src config.PullSourceConfig) time.Duration {
+	return time.Duration(max(src.Interval, 60)) * time.Second
+}
+
+// timeout returns the bound of connection attempts and remote operations
+func (s *pullService) timeout() time.Duration {
+	return time.Duration(max(s.cfg.Timeout, 1)) * time.Second
+}
+
+// source returns the configuration of a source by name
+func (s *pullService) source(name string) (config.PullSourceConfig, bool) {
+	for _, src := range s.cfg.Sources {
+		if src.Name == name {
+			return src, true
+		}
+	}
+	return config.PullSourceConfig{}, false
+}
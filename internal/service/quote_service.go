@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/csvparser"
+	"github.com/go-historical-data/pkg/i18n"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// RowErrorCrossedQuote rejects a quote whose ask is below its bid
+const RowErrorCrossedQuote = "crossed_quote"
+
+// QuoteService defines the interface for bid/ask quote operations
+type QuoteService interface {
+	ValidateUpload(info UploadInfo) error
+	UploadCSV(ctx context.Context, reader io.Reader, info UploadInfo) (*response.QuoteUploadResponse, error)
+	GetQuotes(ctx context.Context, req *request.GetQuotesRequest) (*response.PaginatedQuoteResponse, error)
+}
+
+// quoteService implements QuoteService interface
+type quoteService struct {
+	repo     repository.QuoteRepository
+	sources  repository.SourceRepository
+	resolver SymbolResolver
+	cfg      config.IngestionConfig
+}
+
+// NewQuoteService creates a new quote service instance. Uploads use the
+// configured ingestion batch size and file size limit.
+func NewQuoteService(repo repository.QuoteRepository, sources repository.SourceRepository, resolver SymbolResolver, cfg config.IngestionConfig) QuoteService {
+	return &quoteService{
+		repo:     repo,
+		sources:  sources,
+		resolver: resolver,
+		cfg:      cfg,
+	}
+}
+
+// ValidateUpload checks the file size against the configured limit
+func (s *quoteService) ValidateUpload(info UploadInfo) error {
+	if s.cfg.MaxFileSize > 0 && info.FileSize > s.cfg.MaxFileSize {
+		return &FileTooLargeError{Size: info.FileSize, Limit: s.cfg.MaxFileSize}
+	}
+	return nil
+}
+
+// UploadCSV stores the quotes of a symbol,date,bid,ask file. Invalid rows are
+// reported and skipped; a quote replaces the stored one of the same day.
+func (s *quoteService) UploadCSV(ctx context.Context, reader io.Reader, info UploadInfo) (*response.QuoteUploadResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "QuoteService.UploadCSV")
+	defer span.End()
+
+	parser := csvparser.NewQuoteParser(reader)
+	parser.SetLanguage(i18n.FromContext(ctx))
+	if err := parser.ParseHeader(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid CSV header")
+		return nil, &request.ValidationError{Field: "file", Message: fmt.Sprintf("invalid CSV header: %v", err)}
+	}
+
+	// Every quote of this upload points back at the file it came from
+	source := &model.Source{
+		Kind:   model.SourceKindUpload,
+		Name:   info.Filename,
+		Tenant: info.Tenant,
+	}
+	if err := s.sources.Create(ctx, source); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create source")
+		return nil, err
+	}
+
+	batchSize := max(s.cfg.BatchSize, 1)
+	result := &response.QuoteUploadResponse{SourceID: source.ID}
+	var rowErrors []response.CSVRowError
+	var symbols []string
+	batch := make([]model.Quote, 0, batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.repo.BulkUpsert(ctx, batch, batchSize); err != nil {
+			result.FailedCount += len(batch)
+			rowErrors = append(rowErrors, response.CSVRowError{Code: RowErrorBatchInsert, Message: err.Error()})
+		} else {
+			result.SuccessCount += len(batch)
+			for i := range batch {
+				if !slices.Contains(symbols, batch[i].Symbol) {
+					symbols = append(symbols, batch[i].Symbol)
+				}
+			}
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		row, err := parser.ParseRow()
+		if err == io.EOF {
+			break
+		}
+		result.TotalRows++
+		if err != nil {
+			result.FailedCount++
+			rowErrors = append(rowErrors, parseRowError(err, parser.GetCurrentLine()))
+			continue
+		}
+
+		quote := model.Quote{
+			Symbol:   row.Symbol,
+			Date:     row.Date,
+			Bid:      row.Bid,
+			Ask:      row.Ask,
+			SourceID: &source.ID,
+		}
+		if err := validateQuote(&quote); err != nil {
+			result.FailedCount++
+			rowErrors = append(rowErrors, quoteRowError(err, &quote, parser.GetCurrentLine()))
+			continue
+		}
+
+		batch = append(batch, quote)
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	// Limit the listed errors to avoid huge responses
+	result.OmittedErrors = max(len(rowErrors)-maxReportedErrors, 0)
+	result.Errors = rowErrors[:len(rowErrors)-result.OmittedErrors]
+	result.Symbols = symbols
+
+	result.Message = "CSV file processed successfully"
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("CSV file processed with %d errors", result.FailedCount)
+	}
+
+	span.SetAttributes(
+		attribute.Int("total_rows", result.TotalRows),
+		attribute.Int("success_count", result.SuccessCount),
+		attribute.Int("failed_count", result.FailedCount),
+	)
+	span.SetStatus(codes.Ok, result.Message)
+
+	return result, nil
+}
+
+// GetQuotes lists quotes matching the request filters, newest first
+func (s *quoteService) GetQuotes(ctx context.Context, req *request.GetQuotesRequest) (*response.PaginatedQuoteResponse, error) {
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Former names of renamed symbols query the current symbol
+	symbol, err := s.resolver.Resolve(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := map[string]interface{}{
+		"symbol":     symbol,
+		"start_date": req.StartDate,
+		"end_date":   req.EndDate,
+	}
+
+	quotes, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotes: %w", err)
+	}
+
+	data := make([]response.QuoteResponse, len(quotes))
+	for i := range quotes {
+		data[i] = toQuoteResponse(&quotes[i])
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedQuoteResponse{
+		Data: data,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// validateQuote validates the business rules of a quote: positive prices, an
+// ask at or above the bid and a date that is not in the future
+func validateQuote(quote *model.Quote) error {
+	if !quote.Bid.IsPositive() {
+		return &BarValidationError{Field: "bid", Code: RowErrorNonPositivePrice, Message: "bid must be positive"}
+	}
+	if !quote.Ask.IsPositive() {
+		return &BarValidationError{Field: "ask", Code: RowErrorNonPositivePrice, Message: "ask must be positive"}
+	}
+	if quote.Ask.LessThan(quote.Bid) {
+		return &BarValidationError{
+			Field:   "ask",
+			Code:    RowErrorCrossedQuote,
+			Message: fmt.Sprintf("ask (%s) must be greater than or equal to bid (%s)", quote.Ask, quote.Bid),
+		}
+	}
+	if quote.Date.After(latestToday(time.Now())) {
+		return &BarValidationError{
+			Field:   "date",
+			Code:    RowErrorFutureDate,
+			Message: fmt.Sprintf("date (%s) cannot be in the future", quote.Date.Format("2006-01-02")),
+		}
+	}
+	return nil
+}
+
+// quoteRowError describes a quote rejected by validateQuote
+func quoteRowError(err error, quote *model.Quote, line int) response.CSVRowError {
+	rowErr := response.CSVRowError{Line: line, Message: err.Error()}
+	var quoteErr *BarValidationError
+	if !errors.As(err, &quoteErr) {
+		return rowErr
+	}
+	rowErr.Field = quoteErr.Field
+	rowErr.Code = quoteErr.Code
+	switch quoteErr.Field {
+	case "bid":
+		rowErr.RawValue = quote.Bid.String()
+	case "ask":
+		rowErr.RawValue = quote.Ask.String()
+	case "date":
+		rowErr.RawValue = quote.Date.Format("2006-01-02")
+	}
+	return rowErr
+}
+
+// toQuoteResponse converts model to response DTO
+func toQuoteResponse(quote *model.Quote) response.QuoteResponse {
+	return response.QuoteResponse{
+		ID:       quote.ID,
+		Symbol:   quote.Symbol,
+		Date:     quote.Date.Format("2006-01-02"),
+		Bid:      quote.Bid,
+		Ask:      quote.Ask,
+		Mid:      quote.Mid(),
+		Spread:   quote.Spread(),
+		SourceID: quote.SourceID,
+	}
+}
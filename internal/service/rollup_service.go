@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/analytics"
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// rollupPeriods are the periods maintained as rollups
+var rollupPeriods = []string{repository.RollupWeekly, repository.RollupMonthly}
+
+// RollupService defines the interface for maintaining the weekly and monthly rollups
+type RollupService interface {
+	// Enqueue schedules the refresh of the periods touched by newly ingested bars
+	Enqueue(event events.BarsIngested)
+	// Rebuild schedules the refresh of the whole history of the requested
+	// symbols, or of every symbol when none is given
+	Rebuild(ctx context.Context, req *request.RebuildRollupsRequest) (*response.RebuildRollupsResponse, error)
+	// Run refreshes enqueued rollups until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// rollupService implements RollupService interface
+type rollupService struct {
+	repo       repository.RollupRepository
+	historical repository.HistoricalRepository
+	bus        events.Bus
+
+	mu      sync.Mutex
+	pending map[string]rollupRange // ingested date range per symbol awaiting refresh
+	wake    chan struct{}
+}
+
+// rollupRange is a date range of ingested bars of one symbol; a zero range
+// covers the whole history
+type rollupRange struct {
+	start, end time.Time
+}
+
+// whole reports whether the range covers the whole history
+func (r rollupRange) whole() bool {
+	return r.start.IsZero() && r.end.IsZero()
+}
+
+// NewRollupService creates a new rollup service instance
+func NewRollupService(repo repository.RollupRepository, historical repository.HistoricalRepository, bus events.Bus) RollupService {
+	return &rollupService{
+		repo:       repo,
+		historical: historical,
+		bus:        bus,
+		pending:    make(map[string]rollupRange),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Enqueue merges the ingested range into the pending refreshes and wakes the
+// worker. Ingestion never blocks on the refresh.
+func (s *rollupService) Enqueue(event events.BarsIngested) {
+	s.mu.Lock()
+	for _, symbol := range event.Symbols {
+		r, ok := s.pending[symbol]
+		switch {
+		case !ok:
+			r = rollupRange{start: event.StartDate, end: event.EndDate}
+		case r.whole():
+		default:
+			if event.StartDate.Before(r.start) {
+				r.start = event.StartDate
+			}
+			if event.EndDate.After(r.end) {
+				r.end = event.EndDate
+			}
+		}
+		s.pending[symbol] = r
+	}
+	s.mu.Unlock()
+
+	s.signal()
+}
+
+// Rebuild queues a refresh of the whole history of the symbols
+func (s *rollupService) Rebuild(ctx context.Context, req *request.RebuildRollupsRequest) (*response.RebuildRollupsResponse, error) {
+	req.Normalize()
+
+	symbols := req.Symbols
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = s.historical.ListSymbols(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list symbols: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	for _, symbol := range symbols {
+		s.pending[symbol] = rollupRange{}
+	}
+	s.mu.Unlock()
+
+	s.signal()
+	return &response.RebuildRollupsResponse{
+		Symbols: symbols,
+		Queued:  len(symbols),
+	}, nil
+}
+
+// signal wakes the worker without blocking
+func (s *rollupService) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run refreshes the rollups of pending symbols each time new bars are enqueued
+func (s *rollupService) Run(ctx context.Context) {
+	log := logger.GetGlobalLogger()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		}
+
+		s.mu.Lock()
+		pending := s.pending
+		s.pending = make(map[string]rollupRange)
+		s.mu.Unlock()
+
+		for symbol, r := range pending {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := s.refresh(ctx, symbol, r); err != nil {
+				log.Error().Err(err).Str("symbol", symbol).Msg("Rollup refresh failed")
+			}
+		}
+	}
+}
+
+// refresh recomputes every period of the symbol touched by the range
+func (s *rollupService) refresh(ctx context.Context, symbol string, r rollupRange) error {
+	for _, period := range rollupPeriods {
+		var from, until time.Time
+		if !r.whole() {
+			from = analytics.PeriodStart(r.start, period)
+			until = analytics.NextPeriodStart(analytics.PeriodStart(r.end, period), period)
+		}
+		if _, err := s.repo.Refresh(ctx, symbol, period, from, until); err != nil {
+			return err
+		}
+	}
+
+	// Aggregates cached before the refresh are stale now
+	s.bus.Publish(ctx, events.RollupsRefreshed{
+		Symbol:     symbol,
+		OccurredAt: time.Now(),
+	})
+	return nil
+}
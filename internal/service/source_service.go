@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+)
+
+// SourceService defines the interface for looking up data provenance
+type SourceService interface {
+	GetSource(ctx context.Context, id uint64) (*model.Source, error)
+	GetSources(ctx context.Context, req *request.GetSourcesRequest) (*response.PaginatedSourceResponse, error)
+}
+
+// sourceService implements SourceService interface
+type sourceService struct {
+	repo repository.SourceRepository
+}
+
+// NewSourceService creates a new source service instance
+func NewSourceService(repo repository.SourceRepository) SourceService {
+	return &sourceService{
+		repo: repo,
+	}
+}
+
+// GetSource retrieves a source by ID, returning nil when not found
+func (s *sourceService) GetSource(ctx context.Context, id uint64) (*model.Source, error) {
+	source, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source: %w", err)
+	}
+	return source, nil
+}
+
+// GetSources lists sources matching the request filters, newest first
+func (s *sourceService) GetSources(ctx context.Context, req *request.GetSourcesRequest) (*response.PaginatedSourceResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"kind":   req.Kind,
+		"name":   req.Name,
+		"tenant": req.Tenant,
+	}
+
+	sources, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sources: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedSourceResponse{
+		Data: sources,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-historical-data/internal/repository"
+)
+
+// SymbolResolver maps the former names of renamed symbols to their current symbol
+type SymbolResolver interface {
+	Resolve(ctx context.Context, symbol string) (string, error)
+	ResolveAll(ctx context.Context, symbols []string) ([]string, error)
+	ResolveMap(ctx context.Context, symbols []string) (map[string]string, error)
+}
+
+// symbolResolver implements SymbolResolver interface
+type symbolResolver struct {
+	repo repository.SymbolRepository
+}
+
+// NewSymbolResolver creates a new symbol resolver instance
+func NewSymbolResolver(repo repository.SymbolRepository) SymbolResolver {
+	return &symbolResolver{
+		repo: repo,
+	}
+}
+
+// Resolve returns the current symbol of a former name, or symbol itself when
+// it was never renamed
+func (r *symbolResolver) Resolve(ctx context.Context, symbol string) (string, error) {
+	if symbol == "" {
+		return symbol, nil
+	}
+	current, err := r.repo.ResolveAlias(ctx, symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve symbol %s: %w", symbol, err)
+	}
+	if current == "" {
+		return symbol, nil
+	}
+	return current, nil
+}
+
+// ResolveAll resolves every symbol of a list, keeping the first occurrence of
+// symbols that resolve to the same current symbol
+func (r *symbolResolver) ResolveAll(ctx context.Context, symbols []string) ([]string, error) {
+	resolved := make([]string, 0, len(symbols))
+	seen := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		current, err := r.Resolve(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[current]; ok {
+			continue
+		}
+		seen[current] = struct{}{}
+		resolved = append(resolved, current)
+	}
+	return resolved, nil
+}
+
+// ResolveMap maps every symbol of a list to its current symbol, looking the
+// former names up in bulk
+func (r *symbolResolver) ResolveMap(ctx context.Context, symbols []string) (map[string]string, error) {
+	aliases, err := r.repo.ResolveAliases(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		resolved[symbol] = symbol
+		if current, ok := aliases[symbol]; ok {
+			resolved[symbol] = current
+		}
+	}
+	return resolved, nil
+}
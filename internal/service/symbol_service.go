@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/apperror"
+)
+
+// ErrSymbolCollision is returned by RenameSymbol when both symbols have bars on
+// the same dates and the request asked to fail on conflict
+var ErrSymbolCollision = errors.New("symbol collision")
+
+// SymbolService defines the interface for symbol metadata
+type SymbolService interface {
+	GetSymbol(ctx context.Context, symbol string) (*model.Symbol, error)
+	GetSymbols(ctx context.Context, req *request.GetSymbolsRequest) (*response.PaginatedSymbolResponse, error)
+	UpsertSymbol(ctx context.Context, symbol string, req *request.UpsertSymbolRequest) (*model.Symbol, error)
+	RenameSymbol(ctx context.Context, req *request.RenameSymbolRequest) (*response.RenameSymbolResponse, error)
+}
+
+// symbolService implements SymbolService interface
+type symbolService struct {
+	repo      repository.SymbolRepository
+	summaries repository.SymbolSummaryRepository
+	bus       events.Bus
+}
+
+// NewSymbolService creates a new symbol service instance
+func NewSymbolService(repo repository.SymbolRepository, summaries repository.SymbolSummaryRepository, bus events.Bus) SymbolService {
+	return &symbolService{
+		repo:      repo,
+		summaries: summaries,
+		bus:       bus,
+	}
+}
+
+// GetSymbol retrieves the metadata of a symbol with its former names and the
+// coverage of its bars, returning nil when not found. A former name retrieves the current symbol.
+func (s *symbolService) GetSymbol(ctx context.Context, symbol string) (*model.Symbol, error) {
+	symbol = strings.ToUpper(symbol)
+	current, err := s.repo.ResolveAlias(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol: %w", err)
+	}
+	if current != "" {
+		symbol = current
+	}
+
+	data, err := s.repo.FindBySymbol(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	if data.FormerNames, err = s.repo.FindAliases(ctx, symbol); err != nil {
+		return nil, fmt.Errorf("failed to get symbol: %w", err)
+	}
+	tags, err := s.repo.FindTags(ctx, []string{symbol})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol: %w", err)
+	}
+	data.Tags = tags[symbol]
+	if data.Summary, err = s.summaries.FindBySymbol(ctx, symbol); err != nil {
+		return nil, fmt.Errorf("failed to get symbol: %w", err)
+	}
+	return data, nil
+}
+
+// GetSymbols lists symbols matching the request filters with the coverage of
+// their bars, ordered by symbol
+func (s *symbolService) GetSymbols(ctx context.Context, req *request.GetSymbolsRequest) (*response.PaginatedSymbolResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"currency": req.Currency,
+		"exchange": req.Exchange,
+		"tag":      req.Tag,
+	}
+
+	symbols, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbols: %w", err)
+	}
+	if err := s.attachSummaries(ctx, symbols); err != nil {
+		return nil, fmt.Errorf("failed to get symbols: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedSymbolResponse{
+		Data: symbols,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// attachSummaries sets the tags of each symbol and the summary of each
+// symbol that has bars
+func (s *symbolService) attachSummaries(ctx context.Context, symbols []model.Symbol) error {
+	names := make([]string, len(symbols))
+	for i := range symbols {
+		names[i] = symbols[i].Symbol
+	}
+
+	tags, err := s.repo.FindTags(ctx, names)
+	if err != nil {
+		return err
+	}
+	for i := range symbols {
+		symbols[i].Tags = tags[symbols[i].Symbol]
+	}
+
+	summaries, err := s.summaries.FindBySymbols(ctx, names)
+	if err != nil {
+		return err
+	}
+	bySymbol := make(map[string]*model.SymbolSummary, len(summaries))
+	for i := range summaries {
+		bySymbol[summaries[i].Symbol] = &summaries[i]
+	}
+	for i := range symbols {
+		symbols[i].Summary = bySymbol[symbols[i].Symbol]
+	}
+	return nil
+}
+
+// UpsertSymbol creates or replaces the metadata of a symbol, and its tags
+// when the request carries them
+func (s *symbolService) UpsertSymbol(ctx context.Context, symbol string, req *request.UpsertSymbolRequest) (*model.Symbol, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	data := &model.Symbol{
+		Symbol:   strings.ToUpper(symbol),
+		Name:     req.Name,
+		Exchange: req.Exchange,
+		Currency: req.Currency,
+	}
+	if err := s.repo.Upsert(ctx, data); err != nil {
+		return nil, fmt.Errorf("failed to update symbol: %w", err)
+	}
+	if req.Tags != nil {
+		if err := s.repo.SetTags(ctx, data.Symbol, *req.Tags); err != nil {
+			return nil, fmt.Errorf("failed to update symbol: %w", err)
+		}
+	}
+	tags, err := s.repo.FindTags(ctx, []string{data.Symbol})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update symbol: %w", err)
+	}
+	data.Tags = tags[data.Symbol]
+
+	// Converted responses depend on the currency and tag filters on the
+	// tags, so subscribers drop cached reads
+	s.bus.Publish(ctx, events.SymbolUpdated{
+		Symbol:     data.Symbol,
+		Currency:   data.Currency,
+		OccurredAt: time.Now(),
+	})
+
+	return data, nil
+}
+
+// RenameSymbol moves every row of a symbol to a new symbol and records the old
+// one as a former name. With the fail strategy a collision returns
+// ErrSymbolCollision and nothing changes.
+func (s *symbolService) RenameSymbol(ctx context.Context, req *request.RenameSymbolRequest) (*response.RenameSymbolResponse, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Rename(ctx, req.From, req.To, req.OnConflict)
+	if err != nil {
+		if errors.Is(err, repository.ErrRenameCollision) {
+			message := fmt.Sprintf("%s: %d dates of %s already exist under %s", ErrSymbolCollision, result.Collisions, req.From, req.To)
+			return nil, apperror.Conflict(message, nil).Wrap(ErrSymbolCollision)
+		}
+		return nil, err
+	}
+
+	formerNames, err := s.repo.FindAliases(ctx, req.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get former names: %w", err)
+	}
+
+	// Reads of both symbols changed, so subscribers drop cached reads
+	now := time.Now()
+	s.bus.Publish(ctx, events.SymbolRenamed{
+		From:          req.From,
+		To:            req.To,
+		MovedRows:     result.MovedRows,
+		DiscardedRows: result.DiscardedRows,
+		OccurredAt:    now,
+	})
+	// The old symbol is retired; it only resolves as a former name of the new one
+	s.bus.Publish(ctx, events.SymbolDelisted{
+		Symbol:     req.From,
+		Successor:  req.To,
+		DelistedAt: now,
+		OccurredAt: now,
+	})
+
+	return &response.RenameSymbolResponse{
+		From:            req.From,
+		To:              req.To,
+		OnConflict:      req.OnConflict,
+		Collisions:      result.Collisions,
+		MovedRows:       result.MovedRows,
+		DiscardedRows:   result.DiscardedRows,
+		MovedActions:    result.MovedActions,
+		MovedAlertRules: result.MovedAlertRules,
+		MetadataMoved:   result.MetadataMoved,
+		FormerNames:     formerNames,
+	}, nil
+}
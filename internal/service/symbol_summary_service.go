@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/middleware"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// summaryPageSize is the page size used when loading every summary
+const summaryPageSize = 1000
+
+// SymbolSummaryService defines the interface for the per-symbol summaries
+// behind the catalog, freshness metrics and latest quotes
+type SymbolSummaryService interface {
+	// Enqueue schedules the refresh of the summaries of the symbols
+	Enqueue(symbols ...string)
+	GetSummary(ctx context.Context, symbol string) (*model.SymbolSummary, error)
+	GetCatalog(ctx context.Context, req *request.GetCatalogRequest) (*response.PaginatedCatalogResponse, error)
+	// Run refreshes enqueued summaries until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+// symbolSummaryService implements SymbolSummaryService interface
+type symbolSummaryService struct {
+	repo     repository.SymbolSummaryRepository
+	resolver SymbolResolver
+
+	mu      sync.Mutex
+	pending map[string]struct{} // symbols awaiting refresh
+	wake    chan struct{}
+}
+
+// NewSymbolSummaryService creates a new symbol summary service instance
+func NewSymbolSummaryService(repo repository.SymbolSummaryRepository, resolver SymbolResolver) SymbolSummaryService {
+	return &symbolSummaryService{
+		repo:     repo,
+		resolver: resolver,
+		pending:  make(map[string]struct{}),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Enqueue adds the symbols to the pending refreshes and wakes the worker.
+// Ingestion never blocks on the refresh.
+func (s *symbolSummaryService) Enqueue(symbols ...string) {
+	s.mu.Lock()
+	for _, symbol := range symbols {
+		s.pending[symbol] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// GetSummary retrieves the coverage and latest close of a symbol, returning nil
+// when it has no bars. A former name retrieves the current symbol.
+func (s *symbolSummaryService) GetSummary(ctx context.Context, symbol string) (*model.SymbolSummary, error) {
+	symbol, err := s.resolver.Resolve(ctx, strings.ToUpper(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol summary: %w", err)
+	}
+
+	summary, err := s.repo.FindBySymbol(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol summary: %w", err)
+	}
+	return summary, nil
+}
+
+// GetCatalog lists the summaries of every symbol with bars, ordered by symbol
+func (s *symbolSummaryService) GetCatalog(ctx context.Context, req *request.GetCatalogRequest) (*response.PaginatedCatalogResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"tag": req.Tag,
+	}
+	if req.StaleDays > 0 {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		filters["stale_before"] = today.AddDate(0, 0, -req.StaleDays)
+	}
+
+	summaries, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedCatalogResponse{
+		Data: summaries,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// Run publishes the freshness of every symbol, then refreshes the summaries of
+// pending symbols each time new bars are enqueued
+func (s *symbolSummaryService) Run(ctx context.Context) {
+	log := logger.GetGlobalLogger()
+
+	if err := s.recordFreshness(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to load symbol summaries")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		}
+
+		s.mu.Lock()
+		pending := s.pending
+		s.pending = make(map[string]struct{})
+		s.mu.Unlock()
+
+		for symbol := range pending {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := s.refresh(ctx, symbol); err != nil {
+				log.Error().Err(err).Str("symbol", symbol).Msg("Symbol summary refresh failed")
+			}
+		}
+	}
+}
+
+// refresh recomputes the summary of a symbol and its freshness metric
+func (s *symbolSummaryService) refresh(ctx context.Context, symbol string) error {
+	if err := s.repo.Refresh(ctx, symbol); err != nil {
+		return err
+	}
+
+	summary, err := s.repo.FindBySymbol(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if summary == nil {
+		middleware.ClearSymbolFreshness(symbol)
+		return nil
+	}
+	middleware.RecordSymbolFreshness(symbol, summary.LastDate)
+	return nil
+}
+
+// recordFreshness publishes the freshness metric of every summarized symbol
+func (s *symbolSummaryService) recordFreshness(ctx context.Context) error {
+	for offset := 0; ; offset += summaryPageSize {
+		summaries, _, err := s.repo.FindAll(ctx, nil, summaryPageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, summary := range summaries {
+			middleware.RecordSymbolFreshness(summary.Symbol, summary.LastDate)
+		}
+		if len(summaries) < summaryPageSize {
+			return nil
+		}
+	}
+}
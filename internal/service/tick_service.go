@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/tickcodec"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// RowErrorFutureTimestamp rejects a tick stamped later than the clock skew allows
+const RowErrorFutureTimestamp = "future_timestamp"
+
+// maxTickClockSkew is how far ahead of the server clock a tick may be stamped
+const maxTickClockSkew = time.Minute
+
+// TickService defines the interface for tick ingestion and queries
+type TickService interface {
+	Ingest(ctx context.Context, decoder tickcodec.Decoder) (*response.TickIngestResponse, error)
+	GetTicks(ctx context.Context, req *request.GetTicksRequest) (*response.TickListResponse, error)
+	GetBars(ctx context.Context, symbol string, req *request.TickBarsRequest) (*response.TickBarsResponse, error)
+}
+
+// tickService implements TickService interface
+type tickService struct {
+	repo      repository.TickRepository
+	symbols   repository.SymbolRepository
+	resolver  SymbolResolver
+	calendars map[string]*sessionCalendar // by lower-cased exchange
+	cfg       config.TicksConfig
+}
+
+// NewTickService creates a new tick service instance
+func NewTickService(repo repository.TickRepository, symbols repository.SymbolRepository, resolver SymbolResolver, cfg config.TicksConfig) TickService {
+	return &tickService{
+		repo:      repo,
+		symbols:   symbols,
+		resolver:  resolver,
+		calendars: newSessionCalendars(cfg.Exchanges),
+		cfg:       cfg,
+	}
+}
+
+// calendarOf returns the session calendar of the exchange a symbol is
+// listed on, nil when the symbol or its exchange has none
+func (s *tickService) calendarOf(ctx context.Context, symbol string) (*sessionCalendar, error) {
+	if len(s.calendars) == 0 {
+		return nil, nil
+	}
+	metadata, err := s.symbols.FindBySymbol(ctx, symbol)
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+	return s.calendars[strings.ToLower(metadata.Exchange)], nil
+}
+
+// Ingest stores the ticks read from decoder in batches, tagged with the
+// trading session of their symbol's exchange. Undecodable and invalid
+// records are reported and skipped; a read or symbol lookup error ends the
+// ingestion, keeping the batches already stored.
+func (s *tickService) Ingest(ctx context.Context, decoder tickcodec.Decoder) (*response.TickIngestResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "TickService.Ingest")
+	defer span.End()
+
+	batchSize := max(s.cfg.BatchSize, 1)
+	result := &response.TickIngestResponse{}
+	var rowErrors []response.CSVRowError
+	var symbols []string
+	batch := make([]model.Tick, 0, batchSize)
+	calendars := make(map[string]*sessionCalendar)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.repo.BulkCreate(ctx, batch, batchSize); err != nil {
+			result.FailedCount += len(batch)
+			rowErrors = append(rowErrors, response.CSVRowError{Code: RowErrorBatchInsert, Message: err.Error()})
+		} else {
+			result.SuccessCount += len(batch)
+			for i := range batch {
+				if !slices.Contains(symbols, batch[i].Symbol) {
+					symbols = append(symbols, batch[i].Symbol)
+				}
+			}
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		tick, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var decodeErr *tickcodec.DecodeError
+			if !errors.As(err, &decodeErr) {
+				flush()
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "failed to read ticks")
+				return nil, fmt.Errorf("failed to read ticks: %w", err)
+			}
+			result.TotalRecords++
+			result.FailedCount++
+			rowErrors = append(rowErrors, response.CSVRowError{
+				Line:     decodeErr.Record,
+				Field:    decodeErr.Field,
+				RawValue: decodeErr.Value,
+				Code:     decodeErr.Code,
+				Message:  decodeErr.Message,
+			})
+			continue
+		}
+		result.TotalRecords++
+
+		if tick.Timestamp.After(time.Now().Add(maxTickClockSkew)) {
+			result.FailedCount++
+			rowErrors = append(rowErrors, response.CSVRowError{
+				Line:     decoder.Record(),
+				Field:    "ts",
+				RawValue: tick.Timestamp.Format(time.RFC3339Nano),
+				Code:     RowErrorFutureTimestamp,
+				Message:  "timestamp cannot be in the future",
+			})
+			continue
+		}
+
+		calendar, ok := calendars[tick.Symbol]
+		if !ok {
+			if calendar, err = s.calendarOf(ctx, tick.Symbol); err != nil {
+				flush()
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "symbol lookup failed")
+				return nil, fmt.Errorf("failed to look up the exchange of %s: %w", tick.Symbol, err)
+			}
+			calendars[tick.Symbol] = calendar
+		}
+		var session string
+		if calendar != nil {
+			session = calendar.session(tick.Timestamp)
+		}
+
+		batch = append(batch, model.Tick{
+			Symbol:    tick.Symbol,
+			Timestamp: tick.Timestamp,
+			Price:     tick.Price,
+			Size:      tick.Size,
+			Side:      tick.Side,
+			Session:   session,
+		})
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	// Limit the listed errors to avoid huge responses
+	result.OmittedErrors = max(len(rowErrors)-maxReportedErrors, 0)
+	result.Errors = rowErrors[:len(rowErrors)-result.OmittedErrors]
+	result.Symbols = symbols
+
+	result.Message = "Ticks processed successfully"
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("Ticks processed with %d errors", result.FailedCount)
+	}
+
+	span.SetAttributes(
+		attribute.Int("total_records", result.TotalRecords),
+		attribute.Int("success_count", result.SuccessCount),
+		attribute.Int("failed_count", result.FailedCount),
+	)
+	span.SetStatus(codes.Ok, result.Message)
+
+	return result, nil
+}
+
+// GetTicks lists a page of the ticks of a symbol in time order
+func (s *tickService) GetTicks(ctx context.Context, req *request.GetTicksRequest) (*response.TickListResponse, error) {
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var after *repository.TickCursor
+	if req.Cursor != "" {
+		cursor, err := decodeTickCursor(req.Cursor)
+		if err != nil {
+			return nil, &request.ValidationError{Field: "cursor", Message: "invalid cursor"}
+		}
+		after = cursor
+	}
+
+	symbol, err := s.resolver.Resolve(ctx, strings.ToUpper(req.Symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	ticks, err := s.repo.FindRange(ctx, symbol, req.From.UTC(), req.To.UTC(), req.Sessions(), after, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticks: %w", err)
+	}
+
+	result := &response.TickListResponse{Data: ticks}
+	if len(ticks) == req.Limit {
+		last := ticks[len(ticks)-1]
+		result.NextCursor = encodeTickCursor(last.Timestamp, last.ID)
+	}
+	return result, nil
+}
+
+// GetBars builds the bars of a symbol from its ticks
+func (s *tickService) GetBars(ctx context.Context, symbol string, req *request.TickBarsRequest) (*response.TickBarsResponse, error) {
+	req.SetDefaults(time.Now())
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	interval, _ := req.GetInterval()
+	from, to := req.From.UTC(), req.To.UTC()
+
+	if s.cfg.MaxBars > 0 && to.Sub(from)/interval > time.Duration(s.cfg.MaxBars) {
+		return nil, &request.ValidationError{
+			Field:   "interval",
+			Message: fmt.Sprintf("the range spans more than %d intervals, use a larger interval or a shorter range", s.cfg.MaxBars),
+		}
+	}
+
+	symbol, err := s.resolver.Resolve(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := req.Location()
+	bars, err := s.repo.Aggregate(ctx, symbol, from, to, req.Sessions(), interval, loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build bars: %w", err)
+	}
+
+	result := &response.TickBarsResponse{
+		Symbol:   symbol,
+		Interval: req.Interval,
+		TZ:       req.TZ,
+		From:     from.In(loc),
+		To:       to.In(loc),
+		Bars:     make([]response.TickBar, len(bars)),
+	}
+	for i, bar := range bars {
+		result.Bars[i] = response.TickBar{
+			Start:  bar.Start.In(loc),
+			Open:   bar.Open,
+			High:   bar.High,
+			Low:    bar.Low,
+			Close:  bar.Close,
+			Volume: bar.Volume,
+			Trades: bar.Trades,
+		}
+	}
+	return result, nil
+}
+
+// encodeTickCursor renders the position of a tick as <unix micros>.<id>
+func encodeTickCursor(ts time.Time, id uint64) string {
+	return strconv.FormatInt(ts.UnixMicro(), 10) + "." + strconv.FormatUint(id, 10)
+}
+
+// decodeTickCursor parses a cursor rendered by encodeTickCursor
+func decodeTickCursor(cursor string) (*repository.TickCursor, error) {
+	micros, id, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, fmt.Errorf("malformed cursor %q", cursor)
+	}
+	us, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.TickCursor{Timestamp: time.UnixMicro(us).UTC(), ID: n}, nil
+}
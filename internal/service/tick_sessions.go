@@ -0,0 +1,79 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+)
+
+// Trading sessions ticks are tagged with
+const (
+	SessionPre     = "pre"
+	SessionRegular = "regular"
+	SessionPost    = "post"
+)
+
+// sessionCalendar tells the trading session of a time at one exchange
+type sessionCalendar struct {
+	loc       *time.Location
+	preOpen   time.Duration // offsets from local midnight
+	open      time.Duration
+	close     time.Duration
+	postClose time.Duration
+	holidays  map[string]bool // YYYY-MM-DD
+}
+
+// newSessionCalendars builds the calendars of the configured exchanges,
+// keyed by lower-cased name. Entries the startup validation rejects are
+// skipped.
+func newSessionCalendars(exchanges map[string]config.ExchangeCalendarConfig) map[string]*sessionCalendar {
+	calendars := make(map[string]*sessionCalendar, len(exchanges))
+	for name, exchange := range exchanges {
+		loc, err := time.LoadLocation(exchange.Timezone)
+		if err != nil {
+			continue
+		}
+		preOpen, open, close, postClose, err := exchange.SessionBounds()
+		if err != nil {
+			continue
+		}
+		holidays := make(map[string]bool, len(exchange.Holidays))
+		for _, holiday := range exchange.Holidays {
+			holidays[holiday] = true
+		}
+		calendars[strings.ToLower(name)] = &sessionCalendar{
+			loc:       loc,
+			preOpen:   preOpen,
+			open:      open,
+			close:     close,
+			postClose: postClose,
+			holidays:  holidays,
+		}
+	}
+	return calendars
+}
+
+// session returns the session ts falls in, empty outside the sessions and
+// on weekends and holidays. The bounds are local wall clock times, so they
+// follow the exchange's DST changes.
+func (c *sessionCalendar) session(ts time.Time) string {
+	local := ts.In(c.loc)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday || c.holidays[local.Format("2006-01-02")] {
+		return ""
+	}
+	sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second + time.Duration(local.Nanosecond())
+	switch {
+	case sinceMidnight < c.preOpen:
+		return ""
+	case sinceMidnight < c.open:
+		return SessionPre
+	case sinceMidnight < c.close:
+		return SessionRegular
+	case sinceMidnight < c.postClose:
+		return SessionPost
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/events"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/csvparser"
+	"github.com/go-historical-data/pkg/i18n"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// TimeSeriesService defines the interface for generic time series operations
+type TimeSeriesService interface {
+	ValidateUpload(info UploadInfo) error
+	UploadCSV(ctx context.Context, reader io.Reader, info UploadInfo) (*response.TimeSeriesUploadResponse, error)
+	GetSeries(ctx context.Context, req *request.GetTimeSeriesRequest) (*response.PaginatedTimeSeriesResponse, error)
+}
+
+// timeSeriesService implements TimeSeriesService interface
+type timeSeriesService struct {
+	repo    repository.TimeSeriesRepository
+	sources repository.SourceRepository
+	bus     events.Bus
+	cfg     config.IngestionConfig
+}
+
+// NewTimeSeriesService creates a new time series service instance. Uploads
+// use the configured ingestion batch size and file size limit and publish
+// SeriesIngested on bus.
+func NewTimeSeriesService(repo repository.TimeSeriesRepository, sources repository.SourceRepository, bus events.Bus, cfg config.IngestionConfig) TimeSeriesService {
+	return &timeSeriesService{
+		repo:    repo,
+		sources: sources,
+		bus:     bus,
+		cfg:     cfg,
+	}
+}
+
+// ValidateUpload checks the file size against the configured limit
+func (s *timeSeriesService) ValidateUpload(info UploadInfo) error {
+	if s.cfg.MaxFileSize > 0 && info.FileSize > s.cfg.MaxFileSize {
+		return &FileTooLargeError{Size: info.FileSize, Limit: s.cfg.MaxFileSize}
+	}
+	return nil
+}
+
+// UploadCSV stores the observations of a series_id,timestamp,value file.
+// Invalid rows are reported and skipped; an observation replaces the stored
+// one of the same series and timestamp.
+func (s *timeSeriesService) UploadCSV(ctx context.Context, reader io.Reader, info UploadInfo) (*response.TimeSeriesUploadResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "TimeSeriesService.UploadCSV")
+	defer span.End()
+
+	parser := csvparser.NewSeriesParser(reader)
+	parser.SetLanguage(i18n.FromContext(ctx))
+	if err := parser.ParseHeader(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid CSV header")
+		return nil, &request.ValidationError{Field: "file", Message: fmt.Sprintf("invalid CSV header: %v", err)}
+	}
+
+	// Every observation of this upload points back at the file it came from
+	source := &model.Source{
+		Kind:   model.SourceKindUpload,
+		Name:   info.Filename,
+		Tenant: info.Tenant,
+	}
+	if err := s.sources.Create(ctx, source); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create source")
+		return nil, err
+	}
+
+	batchSize := max(s.cfg.BatchSize, 1)
+	result := &response.TimeSeriesUploadResponse{SourceID: source.ID}
+	var rowErrors []response.CSVRowError
+	var series []string
+	batch := make([]model.TimeSeries, 0, batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.repo.BulkUpsert(ctx, batch, batchSize); err != nil {
+			result.FailedCount += len(batch)
+			rowErrors = append(rowErrors, response.CSVRowError{Code: RowErrorBatchInsert, Message: err.Error()})
+		} else {
+			result.SuccessCount += len(batch)
+			for i := range batch {
+				if !slices.Contains(series, batch[i].SeriesID) {
+					series = append(series, batch[i].SeriesID)
+				}
+			}
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		row, err := parser.ParseRow()
+		if err == io.EOF {
+			break
+		}
+		result.TotalRows++
+		if err != nil {
+			result.FailedCount++
+			rowErrors = append(rowErrors, parseRowError(err, parser.GetCurrentLine()))
+			continue
+		}
+
+		batch = append(batch, model.TimeSeries{
+			SeriesID:  row.SeriesID,
+			Timestamp: row.Timestamp,
+			Value:     row.Value,
+			Fields:    row.Fields,
+			SourceID:  &source.ID,
+		})
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	if result.SuccessCount > 0 {
+		s.bus.Publish(ctx, events.SeriesIngested{
+			Series:     series,
+			Count:      result.SuccessCount,
+			OccurredAt: time.Now(),
+		})
+	}
+
+	// Limit the listed errors to avoid huge responses
+	result.OmittedErrors = max(len(rowErrors)-maxReportedErrors, 0)
+	result.Errors = rowErrors[:len(rowErrors)-result.OmittedErrors]
+	result.Series = series
+
+	result.Message = "CSV file processed successfully"
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("CSV file processed with %d errors", result.FailedCount)
+	}
+
+	span.SetAttributes(
+		attribute.Int("total_rows", result.TotalRows),
+		attribute.Int("success_count", result.SuccessCount),
+		attribute.Int("failed_count", result.FailedCount),
+	)
+	span.SetStatus(codes.Ok, result.Message)
+
+	return result, nil
+}
+
+// GetSeries lists the observations of a series in timestamp order
+func (s *timeSeriesService) GetSeries(ctx context.Context, req *request.GetTimeSeriesRequest) (*response.PaginatedTimeSeriesResponse, error) {
+	req.SetDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	filters := map[string]interface{}{
+		"series_id": req.SeriesID,
+		"start":     req.Start.UTC(),
+		"end":       req.End.UTC(),
+	}
+
+	points, total, err := s.repo.FindAll(ctx, filters, req.SortDir, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time series: %w", err)
+	}
+
+	data := make([]response.TimeSeriesResponse, len(points))
+	for i := range points {
+		data[i] = toTimeSeriesResponse(&points[i])
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedTimeSeriesResponse{
+		Data: data,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// toTimeSeriesResponse converts model to response DTO
+func toTimeSeriesResponse(point *model.TimeSeries) response.TimeSeriesResponse {
+	return response.TimeSeriesResponse{
+		ID:        point.ID,
+		SeriesID:  point.SeriesID,
+		Timestamp: point.Timestamp.UTC(),
+		Value:     point.Value,
+		Fields:    point.Fields,
+		SourceID:  point.SourceID,
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+)
+
+// UploadJobService defines the interface for querying upload job status
+type UploadJobService interface {
+	GetUploadJob(ctx context.Context, id uint64) (*model.UploadJob, error)
+	GetUploadJobs(ctx context.Context, tenant string, req *request.GetUploadJobsRequest) (*response.PaginatedUploadJobResponse, error)
+}
+
+// uploadJobService implements UploadJobService interface
+type uploadJobService struct {
+	repo repository.UploadJobRepository
+}
+
+// NewUploadJobService creates a new upload job service instance
+func NewUploadJobService(repo repository.UploadJobRepository) UploadJobService {
+	return &uploadJobService{
+		repo: repo,
+	}
+}
+
+// GetUploadJob retrieves an upload job by ID, returning nil when not found
+func (s *uploadJobService) GetUploadJob(ctx context.Context, id uint64) (*model.UploadJob, error) {
+	job, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload job: %w", err)
+	}
+	return job, nil
+}
+
+// GetUploadJobs lists upload jobs of a tenant, newest first. An empty tenant
+// lists jobs of all tenants.
+func (s *uploadJobService) GetUploadJobs(ctx context.Context, tenant string, req *request.GetUploadJobsRequest) (*response.PaginatedUploadJobResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"tenant": tenant,
+		"status": req.Status,
+	}
+
+	jobs, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload jobs: %w", err)
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedUploadJobResponse{
+		Data: jobs,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
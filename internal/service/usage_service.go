@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-historical-data/pkg/logger"
+)
+
+// UsageService defines the interface for usage metering and quota enforcement
+type UsageService interface {
+	// RecordUsage accumulates usage in memory until the next flush
+	RecordUsage(tenant, apiKey string, rowsRead, rowsIngested, bytesIn, bytesOut int64)
+	// CheckIngestQuota returns a *QuotaExceededError if ingesting rows would exceed the monthly quota
+	CheckIngestQuota(ctx context.Context, tenant string, rows int64) error
+	GetUsage(ctx context.Context, tenant string, req *request.GetUsageRequest) (*response.UsageResponse, error)
+	// Run flushes accumulated usage periodically until ctx is cancelled, then flushes once more
+	Run(ctx context.Context)
+	Flush(ctx context.Context) error
+}
+
+// QuotaExceededError is returned when an operation would exceed the tenant quota
+type QuotaExceededError struct {
+	Tenant    string
+	Quota     int64
+	Used      int64
+	Requested int64
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("monthly row quota exceeded for tenant %s: %d used + %d requested > %d allowed",
+		e.Tenant, e.Used, e.Requested, e.Quota)
+}
+
+// AppError answers the error with 429 and the quota figures
+func (e *QuotaExceededError) AppError() *apperror.Error {
+	return apperror.QuotaExceeded("Ingestion would exceed the monthly row quota", map[string]int64{
+		"quota":     e.Quota,
+		"used":      e.Used,
+		"requested": e.Requested,
+	})
+}
+
+// usageKey identifies an in-memory usage accumulator
+type usageKey struct {
+	tenant string
+	apiKey string
+	day    time.Time
+}
+
+// usageService implements UsageService interface
+type usageService struct {
+	repo    repository.UsageRepository
+	cfg     config.UsageConfig
+	mu      sync.Mutex
+	pending map[usageKey]*model.UsageRecord
+	// inFlight holds the rows ingested of batches Flush is writing, still
+	// counted by the quota check until the write commits
+	inFlight map[usageKey]int64
+}
+
+// NewUsageService creates a new usage service instance
+func NewUsageService(repo repository.UsageRepository, cfg config.UsageConfig) UsageService {
+	return &usageService{
+		repo:     repo,
+		cfg:      cfg,
+		pending:  make(map[usageKey]*model.UsageRecord),
+		inFlight: make(map[usageKey]int64),
+	}
+}
+
+// RecordUsage accumulates usage in memory until the next flush
+func (s *usageService) RecordUsage(tenant, apiKey string, rowsRead, rowsIngested, bytesIn, bytesOut int64) {
+	if !s.cfg.Enabled {
+		return
+	}
+
+	key := usageKey{tenant: tenant, apiKey: apiKey, day: today()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.pending[key]
+	if !ok {
+		record = &model.UsageRecord{Tenant: tenant, APIKey: apiKey, Day: key.day}
+		s.pending[key] = record
+	}
+	record.RowsRead += rowsRead
+	record.RowsIngested += rowsIngested
+	record.BytesIn += bytesIn
+	record.BytesOut += bytesOut
+}
+
+// CheckIngestQuota verifies that ingesting rows keeps the tenant within its monthly quota
+func (s *usageService) CheckIngestQuota(ctx context.Context, tenant string, rows int64) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	quota := s.quotaFor(tenant)
+	if quota <= 0 {
+		return nil
+	}
+
+	used, err := s.monthlyRowsIngested(ctx, tenant)
+	if err != nil {
+		return err
+	}
+
+	if used+rows > quota {
+		return &QuotaExceededError{
+			Tenant:    tenant,
+			Quota:     quota,
+			Used:      used,
+			Requested: rows,
+		}
+	}
+	return nil
+}
+
+// GetUsage returns daily and aggregated usage for a tenant
+func (s *usageService) GetUsage(ctx context.Context, tenant string, req *request.GetUsageRequest) (*response.UsageResponse, error) {
+	req.SetDefaults(time.Now())
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Persist pending counters first so the response reflects the latest usage
+	if err := s.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.FindByTenant(ctx, tenant, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	result := &response.UsageResponse{
+		Tenant:    tenant,
+		StartDate: req.StartDate.Format("2006-01-02"),
+		EndDate:   req.EndDate.Format("2006-01-02"),
+		Daily:     make([]response.UsageDaily, len(records)),
+	}
+
+	for i, r := range records {
+		result.Daily[i] = response.UsageDaily{
+			Day:    r.Day.Format("2006-01-02"),
+			APIKey: r.APIKey,
+			UsageTotals: response.UsageTotals{
+				RowsIngested: r.RowsIngested,
+				RowsRead:     r.RowsRead,
+				BytesIn:      r.BytesIn,
+				BytesOut:     r.BytesOut,
+			},
+		}
+		result.Totals.RowsIngested += r.RowsIngested
+		result.Totals.RowsRead += r.RowsRead
+		result.Totals.BytesIn += r.BytesIn
+		result.Totals.BytesOut += r.BytesOut
+	}
+
+	monthly, err := s.monthlyRowsIngested(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	result.Quota = response.UsageQuota{
+		MonthlyRowQuota:     s.quotaFor(tenant),
+		MonthlyRowsIngested: monthly,
+	}
+	if result.Quota.MonthlyRowQuota > 0 {
+		result.Quota.Remaining = max(result.Quota.MonthlyRowQuota-monthly, 0)
+	}
+
+	return result, nil
+}
+
+// Run flushes accumulated usage periodically until ctx is cancelled
+func (s *usageService) Run(ctx context.Context) {
+	interval := time.Duration(s.cfg.FlushInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Final flush on a fresh context so shutdown does not lose counters
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := s.Flush(flushCtx); err != nil {
+				logger.GetGlobalLogger().Error().Err(err).Msg("Failed to flush usage on shutdown")
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				logger.GetGlobalLogger().Error().Err(err).Msg("Failed to flush usage")
+			}
+		}
+	}
+}
+
+// Flush persists accumulated usage; failed records are merged back for the next attempt
+func (s *usageService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	batch := s.pending
+	s.pending = make(map[usageKey]*model.UsageRecord)
+	for key, r := range batch {
+		s.inFlight[key] += r.RowsIngested
+	}
+	s.mu.Unlock()
+
+	records := make([]model.UsageRecord, 0, len(batch))
+	for _, r := range batch {
+		records = append(records, *r)
+	}
+
+	err := s.repo.Increment(ctx, records)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, r := range batch {
+		if s.inFlight[key] -= r.RowsIngested; s.inFlight[key] == 0 {
+			delete(s.inFlight, key)
+		}
+	}
+	if err != nil {
+		for key, r := range batch {
+			if existing, ok := s.pending[key]; ok {
+				existing.RowsRead += r.RowsRead
+				existing.RowsIngested += r.RowsIngested
+				existing.BytesIn += r.BytesIn
+				existing.BytesOut += r.BytesOut
+			} else {
+				s.pending[key] = r
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// monthlyRowsIngested returns stored plus pending and in-flight rows ingested
+// in the current month. The counts in memory are read before the stored ones,
+// so a batch committing in between is counted twice rather than not at all.
+func (s *usageService) monthlyRowsIngested(ctx context.Context, tenant string) (int64, error) {
+	now := today()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var used int64
+	s.mu.Lock()
+	for key, r := range s.pending {
+		if key.tenant == tenant && !key.day.Before(monthStart) {
+			used += r.RowsIngested
+		}
+	}
+	for key, rows := range s.inFlight {
+		if key.tenant == tenant && !key.day.Before(monthStart) {
+			used += rows
+		}
+	}
+	s.mu.Unlock()
+
+	stored, err := s.repo.SumRowsIngested(ctx, tenant, monthStart, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get monthly usage: %w", err)
+	}
+	return used + stored, nil
+}
+
+// quotaFor returns the monthly row quota of a tenant
+func (s *usageService) quotaFor(tenant string) int64 {
+	if quota, ok := s.cfg.TenantRowQuotas[tenant]; ok {
+		return quota
+	}
+	return s.cfg.DefaultMonthlyRowQuota
+}
+
+// today returns the current UTC day at midnight
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
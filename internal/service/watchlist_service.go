@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-historical-data/internal/dto/request"
+	"github.com/go-historical-data/internal/dto/response"
+	"github.com/go-historical-data/internal/model"
+	"github.com/go-historical-data/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// WatchlistService defines the interface for watchlists
+type WatchlistService interface {
+	CreateWatchlist(ctx context.Context, tenant, apiKey string, req *request.WatchlistRequest) (*response.WatchlistResponse, error)
+	GetWatchlists(ctx context.Context, tenant, apiKey string, req *request.GetWatchlistsRequest) (*response.PaginatedWatchlistResponse, error)
+	GetWatchlist(ctx context.Context, tenant, apiKey string, id uint64) (*response.WatchlistResponse, error)
+	UpdateWatchlist(ctx context.Context, tenant, apiKey string, id uint64, req *request.WatchlistRequest) (*response.WatchlistResponse, error)
+	DeleteWatchlist(ctx context.Context, tenant, apiKey string, id uint64) (*response.WatchlistResponse, error)
+	GetWatchlistData(ctx context.Context, tenant, apiKey string, id uint64) (*response.WatchlistDataResponse, error)
+}
+
+// watchlistService implements WatchlistService interface
+type watchlistService struct {
+	repo       repository.WatchlistRepository
+	historical repository.HistoricalRepository
+}
+
+// NewWatchlistService creates a new watchlist service instance
+func NewWatchlistService(repo repository.WatchlistRepository, historical repository.HistoricalRepository) WatchlistService {
+	return &watchlistService{
+		repo:       repo,
+		historical: historical,
+	}
+}
+
+// CreateWatchlist creates a watchlist owned by the caller's API key
+func (s *watchlistService) CreateWatchlist(ctx context.Context, tenant, apiKey string, req *request.WatchlistRequest) (*response.WatchlistResponse, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	watchlist := &model.Watchlist{
+		Tenant:  tenant,
+		APIKey:  apiKey,
+		Name:    req.Name,
+		Symbols: strings.Join(req.Symbols, ","),
+	}
+	if err := s.repo.Create(ctx, watchlist); err != nil {
+		return nil, err
+	}
+
+	result := toWatchlistResponse(watchlist)
+	return &result, nil
+}
+
+// GetWatchlists lists the watchlists of the caller's API key, ordered by name
+func (s *watchlistService) GetWatchlists(ctx context.Context, tenant, apiKey string, req *request.GetWatchlistsRequest) (*response.PaginatedWatchlistResponse, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"tenant":  tenant,
+		"api_key": apiKey,
+	}
+
+	watchlists, total, err := s.repo.FindAll(ctx, filters, req.Limit, req.GetOffset())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlists: %w", err)
+	}
+
+	data := make([]response.WatchlistResponse, len(watchlists))
+	for i := range watchlists {
+		data[i] = toWatchlistResponse(&watchlists[i])
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit > 0 {
+		totalPages++
+	}
+
+	return &response.PaginatedWatchlistResponse{
+		Data: data,
+		Pagination: response.PaginationMeta{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// GetWatchlist retrieves a watchlist of the caller's API key, returning nil when not found
+func (s *watchlistService) GetWatchlist(ctx context.Context, tenant, apiKey string, id uint64) (*response.WatchlistResponse, error) {
+	watchlist, err := s.find(ctx, tenant, apiKey, id)
+	if err != nil || watchlist == nil {
+		return nil, err
+	}
+
+	result := toWatchlistResponse(watchlist)
+	return &result, nil
+}
+
+// UpdateWatchlist replaces the name and symbols of a watchlist, returning nil when not found
+func (s *watchlistService) UpdateWatchlist(ctx context.Context, tenant, apiKey string, id uint64, req *request.WatchlistRequest) (*response.WatchlistResponse, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	watchlist, err := s.find(ctx, tenant, apiKey, id)
+	if err != nil || watchlist == nil {
+		return nil, err
+	}
+
+	watchlist.Name = req.Name
+	watchlist.Symbols = strings.Join(req.Symbols, ",")
+	if err := s.repo.Update(ctx, watchlist); err != nil {
+		return nil, err
+	}
+
+	result := toWatchlistResponse(watchlist)
+	return &result, nil
+}
+
+// DeleteWatchlist deletes a watchlist, returning nil when not found
+func (s *watchlistService) DeleteWatchlist(ctx context.Context, tenant, apiKey string, id uint64) (*response.WatchlistResponse, error) {
+	watchlist, err := s.find(ctx, tenant, apiKey, id)
+	if err != nil || watchlist == nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+
+	result := toWatchlistResponse(watchlist)
+	return &result, nil
+}
+
+// GetWatchlistData returns the latest bar of every symbol of a watchlist with
+// its change from the previous bar, in list order. It returns nil when the
+// watchlist does not exist.
+func (s *watchlistService) GetWatchlistData(ctx context.Context, tenant, apiKey string, id uint64) (*response.WatchlistDataResponse, error) {
+	tracer := otel.Tracer("historical-service")
+	ctx, span := tracer.Start(ctx, "WatchlistService.GetWatchlistData")
+	defer span.End()
+
+	watchlist, err := s.find(ctx, tenant, apiKey, id)
+	if err != nil || watchlist == nil {
+		return nil, err
+	}
+
+	symbols := watchlist.SymbolList()
+	span.SetAttributes(
+		attribute.Int64("watchlist_id", int64(id)),
+		attribute.Int("symbol_count", len(symbols)),
+	)
+
+	// The latest two bars of each symbol, newest first
+	rows, err := s.historical.FindLatestBySymbols(ctx, symbols, 2)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "database query failed")
+		return nil, fmt.Errorf("failed to get watchlist data: %w", err)
+	}
+	bars := make(map[string][]model.HistoricalData, len(symbols))
+	for _, row := range rows {
+		bars[row.Symbol] = append(bars[row.Symbol], row)
+	}
+
+	result := &response.WatchlistDataResponse{
+		ID:      watchlist.ID,
+		Name:    watchlist.Name,
+		Symbols: make([]response.WatchlistQuote, len(symbols)),
+	}
+	for i, symbol := range symbols {
+		quote := response.WatchlistQuote{Symbol: symbol}
+		if latest := bars[symbol]; len(latest) > 0 {
+			bar := toHistoricalDataResponse(&latest[0])
+			quote.Latest = &bar
+			if len(latest) > 1 {
+				prev := latest[1].Close
+				change := latest[0].Close.Sub(prev)
+				quote.PreviousDate = latest[1].Date.Format("2006-01-02")
+				quote.PreviousClose = &prev
+				quote.Change = &change
+				if !prev.IsZero() {
+					changePct := change.Div(prev).InexactFloat64() * 100
+					quote.ChangePct = &changePct
+				}
+			}
+		}
+		result.Symbols[i] = quote
+	}
+
+	return result, nil
+}
+
+// find retrieves a watchlist owned by the caller, returning nil when not found
+func (s *watchlistService) find(ctx context.Context, tenant, apiKey string, id uint64) (*model.Watchlist, error) {
+	watchlist, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+	if watchlist == nil || watchlist.Tenant != tenant || watchlist.APIKey != apiKey {
+		return nil, nil
+	}
+	return watchlist, nil
+}
+
+// toWatchlistResponse converts model to response DTO
+func toWatchlistResponse(watchlist *model.Watchlist) response.WatchlistResponse {
+	return response.WatchlistResponse{
+		ID:        watchlist.ID,
+		Name:      watchlist.Name,
+		Symbols:   watchlist.SymbolList(),
+		CreatedAt: watchlist.CreatedAt,
+		UpdatedAt: watchlist.UpdatedAt,
+	}
+}
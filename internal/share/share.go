@@ -0,0 +1,93 @@
+package share
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DataPath is the public route answering the data query of a share link
+const DataPath = "/shared/data"
+
+// ErrInvalidSignature is returned for share links that were tampered with or expired
+var ErrInvalidSignature = errors.New("invalid or expired share link")
+
+// Query parameters added to a shared query
+const (
+	issuerParam    = "issuer"
+	expiresParam   = "expires"
+	signatureParam = "signature"
+)
+
+// LinkParams lists the query parameters a link adds to the shared query
+var LinkParams = []string{issuerParam, expiresParam, signatureParam}
+
+// unsignedParams may be changed by the holder of a link without invalidating
+// it: the page of a shared query and the signature itself
+var unsignedParams = map[string]bool{"page": true, signatureParam: true}
+
+// Signer mints and verifies share links: the URL of a read-only data query
+// carrying the name of the API key that issued it, an expiry and an HMAC of
+// both and of every query parameter, so none can be changed or added.
+type Signer struct {
+	publicURL  string
+	signingKey []byte
+}
+
+// NewSigner creates a signer of links built on publicURL
+func NewSigner(publicURL, signingKey string) (*Signer, error) {
+	if signingKey == "" {
+		return nil, fmt.Errorf("a signing key is required for share links")
+	}
+	return &Signer{
+		publicURL:  strings.TrimRight(publicURL, "/"),
+		signingKey: []byte(signingKey),
+	}, nil
+}
+
+// URL returns a link answering query on behalf of the issuing API key until expires
+func (s *Signer) URL(query url.Values, issuer string, expires time.Time) string {
+	signed := url.Values{}
+	for name, values := range query {
+		if !unsignedParams[name] {
+			signed[name] = values
+		}
+	}
+	signed.Set(issuerParam, issuer)
+	signed.Set(expiresParam, strconv.FormatInt(expires.Unix(), 10))
+	signed.Set(signatureParam, s.sign(signed))
+	return s.publicURL + DataPath + "?" + signed.Encode()
+}
+
+// Verify checks the signature and expiry of the query of a link and returns
+// the name of the API key that issued it. ErrInvalidSignature is returned for
+// tampered or expired links.
+func (s *Signer) Verify(query url.Values) (string, error) {
+	exp, err := strconv.ParseInt(query.Get(expiresParam), 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return "", ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(query.Get(signatureParam)), []byte(s.sign(query))) {
+		return "", ErrInvalidSignature
+	}
+	return query.Get(issuerParam), nil
+}
+
+// sign returns the hex HMAC-SHA256 of the signed parameters, sorted by name
+func (s *Signer) sign(query url.Values) string {
+	signed := url.Values{}
+	for name, values := range query {
+		if !unsignedParams[name] {
+			signed[name] = values
+		}
+	}
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(signed.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
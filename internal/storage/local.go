@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalDownloadPath is the route prefix serving artifacts of a LocalStore
+const LocalDownloadPath = "/downloads/"
+
+// LocalStore keeps artifacts in a directory. Its download links point at
+// LocalDownloadPath of the API itself and carry an HMAC of the key and expiry,
+// checked by Open.
+type LocalStore struct {
+	dir        string
+	publicURL  string
+	signingKey []byte
+}
+
+// NewLocalStore creates a store writing artifacts below dir, creating it when
+// missing. Links are built on publicURL and signed with signingKey.
+func NewLocalStore(dir, publicURL, signingKey string) (*LocalStore, error) {
+	if signingKey == "" {
+		return nil, fmt.Errorf("a signing key is required for local export storage")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return &LocalStore{
+		dir:        dir,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+		signingKey: []byte(signingKey),
+	}, nil
+}
+
+// Put writes the artifact to a temporary file renamed into place, so a
+// download never sees a partial artifact
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to store artifact: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to store artifact: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to store artifact: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store artifact: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the artifact file
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete artifact: %w", err)
+	}
+	return nil
+}
+
+// URL returns a signed link to the download route
+func (s *LocalStore) URL(key string, expires time.Time) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	query := url.Values{
+		"expires":   {exp},
+		"signature": {s.sign(key, exp)},
+	}
+	return s.publicURL + LocalDownloadPath + key + "?" + query.Encode(), nil
+}
+
+// Open verifies a download link and returns the path of its artifact.
+// ErrInvalidSignature is returned for tampered or expired links.
+func (s *LocalStore) Open(key, expires, signature string) (string, error) {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return "", ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return "", ErrInvalidSignature
+	}
+	return s.path(key)
+}
+
+// sign returns the hex HMAC-SHA256 of the key and expiry
+func (s *LocalStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a key to a file below the store directory, rejecting keys that
+// would escape it
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", fmt.Errorf("invalid artifact key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
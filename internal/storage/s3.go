@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+)
+
+// s3MaxPresignExpiry is the longest validity S3 accepts for presigned links
+const s3MaxPresignExpiry = 7 * 24 * time.Hour
+
+// s3UnsignedPayload skips hashing the body; uploads are sent over TLS
+const s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Store keeps artifacts in an S3 bucket, or any store speaking the S3 API,
+// using path-style addressing. Requests and download links are signed with
+// AWS Signature Version 4.
+type S3Store struct {
+	client   *http.Client
+	endpoint *url.URL
+	bucket   string
+	region   string
+	prefix   string
+	keyID    string
+	secret   string
+}
+
+// NewS3Store creates a store for the configured bucket
+func NewS3Store(cfg config.S3Config, timeout time.Duration) (*S3Store, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, fmt.Errorf("s3 export storage needs a bucket and region")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 export storage needs an access key")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", endpoint)
+	}
+
+	return &S3Store{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: u,
+		bucket:   cfg.Bucket,
+		region:   cfg.Region,
+		prefix:   cfg.Prefix,
+		keyID:    cfg.AccessKeyID,
+		secret:   cfg.SecretAccessKey,
+	}, nil
+}
+
+// Put uploads the artifact with a single PUT
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	return s.do(req, "upload")
+}
+
+// Delete removes the object; S3 answers 204 for missing objects too
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, "delete")
+}
+
+// URL returns a presigned GET link
+func (s *S3Store) URL(key string, expires time.Time) (string, error) {
+	now := time.Now().UTC()
+	ttl := expires.Sub(now).Round(time.Second)
+	if ttl <= 0 {
+		return "", fmt.Errorf("download link of %q would already be expired", key)
+	}
+	ttl = min(ttl, s3MaxPresignExpiry)
+
+	u, err := url.Parse(s.objectURL(key))
+	if err != nil {
+		return "", err
+	}
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.keyID + "/" + scope},
+		"X-Amz-Date":          {amzDate},
+		"X-Amz-Expires":       {strconv.Itoa(int(ttl.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	canonical := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+	query.Set("X-Amz-Signature", s.signature(now, amzDate, scope, canonical))
+
+	u.RawQuery = canonicalQuery(query)
+	return u.String(), nil
+}
+
+// do signs and sends a request, treating any non-2xx status as an error
+func (s *S3Store) do(req *http.Request, action string) error {
+	s.signRequest(req, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 %s failed: %w", action, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("s3 %s responded with status %d: %s", action, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// signRequest adds the Authorization header of a request with an unsigned payload
+func (s *S3Store) signRequest(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": s3UnsignedPayload,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signed = append(signed, "content-type")
+		values["content-type"] = contentType
+	}
+	sort.Strings(signed)
+
+	var headers strings.Builder
+	for _, name := range signed {
+		headers.WriteString(name + ":" + strings.TrimSpace(values[name]) + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		headers.String(),
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.keyID, scope, signedHeaders, s.signature(now, amzDate, scope, canonical)))
+}
+
+// signature derives the signing key of the day and signs the canonical request
+func (s *S3Store) signature(now time.Time, amzDate, scope, canonical string) string {
+	digest := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secret), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// scope returns the credential scope of the day
+func (s *S3Store) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// objectURL returns the path-style URL of the object of key
+func (s *S3Store) objectURL(key string) string {
+	segments := strings.Split(s.prefix+key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return s.endpoint.String() + "/" + uriEncode(s.bucket) + "/" + strings.Join(segments, "/")
+}
+
+// canonicalQuery encodes query parameters sorted by name, as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes every byte but the unreserved characters of RFC 3986
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrInvalidSignature is returned for download links that were tampered with or expired
+var ErrInvalidSignature = errors.New("invalid or expired download signature")
+
+// Store keeps export artifacts and hands out time-limited download links
+type Store interface {
+	// Put stores size bytes read from r under key, replacing any artifact of
+	// the same key
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Delete removes the artifact of key; a missing artifact is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns a link downloading the artifact of key until expires
+	URL(key string, expires time.Time) (string, error)
+}
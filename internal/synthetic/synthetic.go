@@ -0,0 +1,174 @@
+// Package synthetic generates random-walk daily bars for load tests, demos and
+// local development without licensed market data
+package synthetic
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/go-historical-data/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// Columns is the upload CSV layout written by WriteCSV
+var Columns = []string{"symbol", "date", "open", "high", "low", "close", "volume"}
+
+// Options selects the bars a Generator produces
+type Options struct {
+	Symbols []string
+	Start   time.Time // first calendar date, inclusive
+	End     time.Time // last calendar date, inclusive
+	Seed    int64     // the same seed produces the same bars
+
+	// Daily drift and volatility of the close-to-close log returns; zero
+	// values use 0.0003 and 0.02
+	Drift      float64
+	Volatility float64
+}
+
+// Generator walks the symbols one by one, producing a bar for every weekday
+// of the range in date order
+type Generator struct {
+	opts   Options
+	rng    *rand.Rand
+	symbol int
+	date   time.Time
+	close  float64
+	volume float64
+}
+
+// NewGenerator creates a generator of the bars selected by opts
+func NewGenerator(opts Options) *Generator {
+	if opts.Drift == 0 {
+		opts.Drift = 0.0003
+	}
+	if opts.Volatility == 0 {
+		opts.Volatility = 0.02
+	}
+	opts.Start = truncateDay(opts.Start)
+	opts.End = truncateDay(opts.End)
+
+	g := &Generator{opts: opts, rng: rand.New(rand.NewSource(opts.Seed))}
+	g.startSymbol()
+	return g
+}
+
+// Rows returns the number of bars the generator produces in total
+func (g *Generator) Rows() int64 {
+	var days int64
+	for d := g.opts.Start; !d.After(g.opts.End); d = d.AddDate(0, 0, 1) {
+		if isWeekday(d) {
+			days++
+		}
+	}
+	return days * int64(len(g.opts.Symbols))
+}
+
+// Next returns the next bar, or false once every symbol is done
+func (g *Generator) Next() (model.HistoricalData, bool) {
+	for g.symbol < len(g.opts.Symbols) {
+		if g.date.After(g.opts.End) {
+			g.symbol++
+			g.startSymbol()
+			continue
+		}
+		date := g.date
+		g.date = g.date.AddDate(0, 0, 1)
+		if !isWeekday(date) {
+			continue
+		}
+		return g.bar(date), true
+	}
+	return model.HistoricalData{}, false
+}
+
+// startSymbol resets the walk for the current symbol with a random starting
+// price between 10 and 500 and volume between 100K and 10M
+func (g *Generator) startSymbol() {
+	g.date = g.opts.Start
+	g.close = 10 + g.rng.Float64()*490
+	g.volume = math.Exp(math.Log(1e5) + g.rng.Float64()*math.Log(100))
+}
+
+// bar moves the walk by one day. The open gaps from the previous close, the
+// high and low wick past the body and the volume rises with the size of the move.
+func (g *Generator) bar(date time.Time) model.HistoricalData {
+	vol := g.opts.Volatility
+	open := g.close * math.Exp(g.rng.NormFloat64()*vol/4)
+	ret := g.opts.Drift + g.rng.NormFloat64()*vol
+	closePrice := open * math.Exp(ret)
+	high := math.Max(open, closePrice) * (1 + math.Abs(g.rng.NormFloat64())*vol/2)
+	low := math.Min(open, closePrice) * (1 - math.Min(math.Abs(g.rng.NormFloat64())*vol/2, 0.5))
+	volume := g.volume * math.Exp(g.rng.NormFloat64()*0.3) * (1 + math.Abs(ret)/vol)
+	g.close = closePrice
+
+	return model.HistoricalData{
+		Symbol: g.opts.Symbols[g.symbol],
+		Date:   date,
+		Open:   price(open),
+		High:   price(high),
+		Low:    price(low),
+		Close:  price(closePrice),
+		Volume: uint64(volume),
+	}
+}
+
+// price rounds a generated price to cents, never below one cent
+func price(v float64) decimal.Decimal {
+	return decimal.NewFromFloat(math.Max(v, 0.01)).Round(2)
+}
+
+// WriteCSV writes every bar of g to w with the upload CSV columns and returns
+// the number of rows written
+func WriteCSV(w io.Writer, g *Generator) (int64, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	record := make([]string, len(Columns))
+	for {
+		bar, ok := g.Next()
+		if !ok {
+			break
+		}
+		record[0] = bar.Symbol
+		record[1] = bar.Date.Format("2006-01-02")
+		record[2] = bar.Open.String()
+		record[3] = bar.High.String()
+		record[4] = bar.Low.String()
+		record[5] = bar.Close.String()
+		record[6] = strconv.FormatUint(bar.Volume, 10)
+		if err := cw.Write(record); err != nil {
+			return rows, err
+		}
+		rows++
+	}
+	cw.Flush()
+	return rows, cw.Error()
+}
+
+// Symbols returns n symbol names, SYN0001 onwards
+func Symbols(n int) []string {
+	symbols := make([]string, n)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("SYN%04d", i+1)
+	}
+	return symbols
+}
+
+// truncateDay returns the UTC midnight of the calendar date of t
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// isWeekday reports whether markets trade on the date
+func isWeekday(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
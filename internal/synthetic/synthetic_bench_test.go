@@ -0,0 +1,17 @@
+package synthetic_test
+
+import (
+	"testing"
+
+	"github.com/go-historical-data/internal/bench"
+)
+
+// BenchmarkWriteCSV measures generating bars and writing them as an upload CSV
+func BenchmarkWriteCSV(b *testing.B) {
+	fixture, err := bench.NewFixture(bench.DefaultOptions())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	fixture.Generate(b)
+}
@@ -0,0 +1,149 @@
+// Package apperror defines the error services return for failures with an
+// HTTP meaning. The error handler answers an *Error with its status, code,
+// message and details; any other error is a 500.
+package apperror
+
+import (
+	"errors"
+
+	"github.com/go-historical-data/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Error is a failure the client can act on
+type Error struct {
+	Status  int         // HTTP status answered
+	Code    string      // stable error code, one of the response.ErrCode constants
+	Message string      // sent to the client
+	Details interface{} // sent to the client when set
+	Err     error       // cause, logged but never sent
+}
+
+// Error returns the message sent to the client
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the cause, so errors.Is still matches the sentinel an
+// Error was made from
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns a copy of the error caused by err
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// Coder is implemented by the typed errors of the services that carry their
+// own fields, returning the Error they are answered with
+type Coder interface {
+	AppError() *Error
+}
+
+// From returns the Error err is or carries, reporting false for any other error
+func From(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.AppError(), true
+	}
+	return nil, false
+}
+
+// Localizer is implemented by errors whose messages are translated,
+// returning the Error they are answered with in a language
+type Localizer interface {
+	Localize(lang string) *Error
+}
+
+// FromLocalized is From with the messages of a Localizer in lang
+func FromLocalized(err error, lang string) (*Error, bool) {
+	var localizer Localizer
+	if errors.As(err, &localizer) {
+		return localizer.Localize(lang), true
+	}
+	return From(err)
+}
+
+// New creates an error answered with status and code
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// BadRequest creates a 400 error for malformed input
+func BadRequest(message string, details interface{}) *Error {
+	return &Error{Status: fiber.StatusBadRequest, Code: response.ErrCodeBadRequest, Message: message, Details: details}
+}
+
+// Unauthorized creates a 401 error
+func Unauthorized(message string) *Error {
+	return New(fiber.StatusUnauthorized, response.ErrCodeUnauthorized, message)
+}
+
+// Forbidden creates a 403 error
+func Forbidden(message string) *Error {
+	return New(fiber.StatusForbidden, response.ErrCodeForbidden, message)
+}
+
+// NotFound creates a 404 error
+func NotFound(message string) *Error {
+	return New(fiber.StatusNotFound, response.ErrCodeNotFound, message)
+}
+
+// Conflict creates a 409 error for a request conflicting with stored data
+func Conflict(message string, details interface{}) *Error {
+	return &Error{Status: fiber.StatusConflict, Code: response.ErrCodeConflict, Message: message, Details: details}
+}
+
+// Validation creates a 422 error for well-formed input breaking a rule
+func Validation(message string, details interface{}) *Error {
+	return &Error{Status: fiber.StatusUnprocessableEntity, Code: response.ErrCodeValidation, Message: message, Details: details}
+}
+
+// MalformedFile creates a 422 error for an upload aborted because too many
+// rows failed
+func MalformedFile(message string, details interface{}) *Error {
+	return &Error{Status: fiber.StatusUnprocessableEntity, Code: response.ErrCodeMalformedFile, Message: message, Details: details}
+}
+
+// PayloadTooLarge creates a 413 error
+func PayloadTooLarge(message string) *Error {
+	return New(fiber.StatusRequestEntityTooLarge, response.ErrCodePayloadTooLarge, message)
+}
+
+// UnsupportedMediaType creates a 415 error
+func UnsupportedMediaType(message string) *Error {
+	return New(fiber.StatusUnsupportedMediaType, response.ErrCodeUnsupportedMedia, message)
+}
+
+// TooManyRequests creates a 429 error for a rate limited client
+func TooManyRequests(message string) *Error {
+	return New(fiber.StatusTooManyRequests, response.ErrCodeTooManyRequests, message)
+}
+
+// QuotaExceeded creates a 429 error for an exhausted quota
+func QuotaExceeded(message string, details interface{}) *Error {
+	return &Error{Status: fiber.StatusTooManyRequests, Code: response.ErrCodeQuotaExceeded, Message: message, Details: details}
+}
+
+// Internal creates a 500 error
+func Internal(message string) *Error {
+	return New(fiber.StatusInternalServerError, response.ErrCodeInternalServer, message)
+}
+
+// Unavailable creates a 503 error for a dependency failing; retrying later
+// may succeed
+func Unavailable(message string) *Error {
+	return New(fiber.StatusServiceUnavailable, response.ErrCodeServiceUnavailable, message)
+}
+
+// Timeout creates a 504 error for a request that ran past its deadline
+func Timeout(message string) *Error {
+	return New(fiber.StatusGatewayTimeout, response.ErrCodeTimeout, message)
+}
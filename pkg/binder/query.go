@@ -0,0 +1,125 @@
+// Package binder binds request parameters into request DTOs on top of the
+// fiber parsers, optionally rejecting parameters no field reads.
+package binder
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/go-historical-data/pkg/apperror"
+)
+
+// Locals keys of the binding options of a request
+const (
+	strictKey  = "binder_strict"
+	allowedKey = "binder_allowed"
+)
+
+// FieldError names a rejected query parameter and the reason
+type FieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// Strict makes Query reject, for the rest of the request, query parameters
+// no field of the DTO reads and values of its time fields ParseTime rejects
+func Strict(c *fiber.Ctx) {
+	c.Locals(strictKey, true)
+}
+
+// Allow lets strict binding accept query parameters read by the handler chain
+// outside the DTO, such as the signature of a share link
+func Allow(c *fiber.Ctx, names ...string) {
+	allowed, _ := c.Locals(allowedKey).([]string)
+	c.Locals(allowedKey, append(allowed, names...))
+}
+
+// Query binds the query string into out, a pointer to a struct with query
+// tags, parsing time fields with ParseTime. Extra names the parameters the
+// handler reads itself. In strict mode unknown parameters and malformed times
+// are answered 400 listing them all, before anything is bound.
+func Query(c *fiber.Ctx, out interface{}, extra ...string) error {
+	if strict, _ := c.Locals(strictKey).(bool); strict {
+		allowed, _ := c.Locals(allowedKey).([]string)
+		if problems := checkQuery(c, out, append(allowed, extra...)); len(problems) > 0 {
+			return apperror.BadRequest("Invalid query parameters", problems)
+		}
+	}
+	if err := c.QueryParser(out); err != nil {
+		return apperror.BadRequest("Invalid query parameters", err.Error())
+	}
+	return nil
+}
+
+// timeType is the type of time fields
+var timeType = reflect.TypeOf(time.Time{})
+
+// checkQuery lists the query parameters of the request that are neither read
+// by a field of out nor allowed, and those of time fields that do not parse
+func checkQuery(c *fiber.Ctx, out interface{}, allowed []string) []FieldError {
+	fields := make(map[string]reflect.Type)
+	collectFields(reflect.TypeOf(out), fields)
+	for _, name := range allowed {
+		if _, ok := fields[name]; !ok {
+			fields[name] = nil
+		}
+	}
+
+	var problems []FieldError
+	seen := make(map[string]bool)
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		name := strings.ToLower(strings.TrimSuffix(string(key), "[]"))
+		if seen[name] {
+			return
+		}
+		fieldType, ok := fields[name]
+		switch {
+		case !ok:
+			seen[name] = true
+			problems = append(problems, FieldError{Field: name, Error: "unknown parameter"})
+		case fieldType == timeType && len(value) > 0:
+			if _, err := ParseTime(string(value), time.Now()); err != nil {
+				seen[name] = true
+				problems = append(problems, FieldError{Field: name, Error: "malformed time, expected " + timeFormats})
+			}
+		}
+	})
+	return problems
+}
+
+// collectFields maps the query names of the fields of t, a struct or a
+// pointer to one, to their types, descending into embedded structs. Fields
+// without a query tag are read under their lowercased name.
+func collectFields(t reflect.Type, fields map[string]reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(field.Tag.Get("query"), ",")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" {
+			collectFields(field.Type, fields)
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		fields[strings.ToLower(tag)] = fieldType
+	}
+}
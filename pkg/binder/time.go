@@ -0,0 +1,122 @@
+package binder
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// timeFormats describes the values ParseTime accepts, for error messages
+const timeFormats = "YYYY-MM-DD, RFC 3339, epoch seconds, today, yesterday, now or an offset such as -30d"
+
+// minEpochDigits is the fewest digits read as epoch seconds (1973-03-03 on),
+// so a compact date such as 20240102 or a bare year is rejected rather than
+// read as a time in 1970
+const minEpochDigits = 9
+
+// RegisterTimeParser makes the time fields of every fiber parser, query, body,
+// header and cookie alike, read the values of ParseTime instead of RFC 3339
+// alone. The decoder is process-wide, so it is registered once at startup.
+func RegisterTimeParser() {
+	fiber.SetParserDecoder(fiber.ParserConfig{
+		IgnoreUnknownKeys: true,
+		ZeroEmpty:         true,
+		ParserType: []fiber.ParserType{{
+			Customtype: time.Time{},
+			Converter:  convertTime,
+		}},
+	})
+}
+
+// convertTime is the fiber converter of time fields, an invalid value for a
+// time that does not parse
+func convertTime(value string) reflect.Value {
+	if value == "" {
+		return reflect.ValueOf(time.Time{})
+	}
+	t, err := ParseTime(value, time.Now())
+	if err != nil {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(t)
+}
+
+// ParseTime parses the value of a time parameter:
+//   - a date, 2024-01-02, as midnight UTC
+//   - an RFC 3339 time, 2024-01-02T15:04:05Z
+//   - Unix epoch seconds of at least 9 digits, 1704153600
+//   - today, yesterday or now, relative to now
+//   - a signed offset from today in days, weeks, months or years, -30d, -2w, -6m or -1y
+//
+// Every time is returned in UTC.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch strings.ToLower(value) {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	if isEpoch(value) {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC(), nil
+		}
+	}
+	if t, ok := parseOffset(value, today); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected %s", value, timeFormats)
+}
+
+// isEpoch reports whether value is a run of at least minEpochDigits digits
+func isEpoch(value string) bool {
+	if len(value) < minEpochDigits {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseOffset parses a signed offset from today such as -30d or +1w
+func parseOffset(value string, today time.Time) (time.Time, bool) {
+	if len(value) < 3 || (value[0] != '-' && value[0] != '+') {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	if value[0] == '-' {
+		n = -n
+	}
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "d":
+		return today.AddDate(0, 0, n), true
+	case "w":
+		return today.AddDate(0, 0, 7*n), true
+	case "m":
+		return today.AddDate(0, n, 0), true
+	case "y":
+		return today.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
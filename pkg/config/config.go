@@ -5,17 +5,36 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Database DatabaseConfig `mapstructure:"database"`
-	API      APIConfig      `mapstructure:"api"`
-	Logging  LoggingConfig  `mapstructure:"logging"`
-	CORS     CORSConfig     `mapstructure:"cors"`
-	Tracing  TracingConfig  `mapstructure:"tracing"`
+	App           AppConfig           `mapstructure:"app"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	API           APIConfig           `mapstructure:"api"`
+	Logging       LoggingConfig       `mapstructure:"logging"`
+	CORS          CORSConfig          `mapstructure:"cors"`
+	Tracing       TracingConfig       `mapstructure:"tracing"`
+	Metrics       MetricsConfig       `mapstructure:"metrics"`
+	Cache         CacheConfig         `mapstructure:"cache"`
+	Auth          AuthConfig          `mapstructure:"auth"`
+	Usage         UsageConfig         `mapstructure:"usage"`
+	Security      SecurityConfig      `mapstructure:"security"`
+	Server        ServerConfig        `mapstructure:"server"`
+	TLS           TLSConfig           `mapstructure:"tls"`
+	Fetcher       FetcherConfig       `mapstructure:"fetcher"`
+	Backfill      BackfillConfig      `mapstructure:"backfill"`
+	Ingestion     IngestionConfig     `mapstructure:"ingestion"`
+	Analytics     AnalyticsConfig     `mapstructure:"analytics"`
+	Alerts        AlertsConfig        `mapstructure:"alerts"`
+	Ticks         TicksConfig         `mapstructure:"ticks"`
+	Exports       ExportsConfig       `mapstructure:"exports"`
+	Shares        SharesConfig        `mapstructure:"shares"`
+	Pull          PullConfig          `mapstructure:"pull"`
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+	Features      FeaturesConfig      `mapstructure:"features"`
 }
 
 type AppConfig struct {
@@ -26,20 +45,106 @@ type AppConfig struct {
 }
 
 type DatabaseConfig struct {
-	Host            string `mapstructure:"host"`
-	Port            int    `mapstructure:"port"`
-	Name            string `mapstructure:"name"`
-	User            string `mapstructure:"user"`
-	Password        string `mapstructure:"password"`
-	MaxOpenConns    int    `mapstructure:"max_open_conns"`
-	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"`
+	Driver             string `mapstructure:"driver"`      // mysql, or sqlite to run self-contained without a MySQL server
+	SQLitePath         string `mapstructure:"sqlite_path"` // database file of the sqlite driver; :memory: keeps the data in memory until exit
+	Host               string `mapstructure:"host"`
+	Port               int    `mapstructure:"port"`
+	Name               string `mapstructure:"name"`
+	User               string `mapstructure:"user"`
+	Password           string `mapstructure:"password"`
+	MaxOpenConns       int    `mapstructure:"max_open_conns"`
+	MaxIdleConns       int    `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime    int    `mapstructure:"conn_max_lifetime"`
+	AutoMigrate        bool   `mapstructure:"auto_migrate"`         // apply pending migrations on startup
+	CountCacheTTL      int    `mapstructure:"count_cache_ttl"`      // seconds an exact list count is reused for the same filters; 0 disables
+	SlowQueryThreshold int    `mapstructure:"slow_query_threshold"` // milliseconds after which a statement logs a warning; 0 disables
+
+	Explain      ExplainConfig      `mapstructure:"explain"`
+	Connect      ConnectConfig      `mapstructure:"connect"`
+	Pool         PoolConfig         `mapstructure:"pool"`
+	Resilience   ResilienceConfig   `mapstructure:"resilience"`
+	Partitioning PartitioningConfig `mapstructure:"partitioning"`
+}
+
+type ExplainConfig struct {
+	Enabled    bool    `mapstructure:"enabled"`     // run EXPLAIN on slow selects and log their plan, a debugging aid for staging
+	SampleRate float64 `mapstructure:"sample_rate"` // share of the slow selects explained, between 0 and 1
+}
+
+type ConnectConfig struct {
+	MaxWait     int `mapstructure:"max_wait"`      // seconds the startup retries connecting before giving up; 0 fails on the first error
+	BaseDelayMs int `mapstructure:"base_delay_ms"` // delay after the first failure, doubled after each further one
+	MaxDelayMs  int `mapstructure:"max_delay_ms"`  // cap of the delay between attempts
+}
+
+type PartitioningConfig struct {
+	Enabled         bool `mapstructure:"enabled"`          // run the partition maintenance job
+	Interval        int  `mapstructure:"interval"`         // minutes between maintenance runs
+	FutureMonths    int  `mapstructure:"future_months"`    // monthly partitions created ahead of the current month
+	RetentionMonths int  `mapstructure:"retention_months"` // drop months older than this, with their rows; 0 keeps everything
+}
+
+type PoolConfig struct {
+	WarmConnections int `mapstructure:"warm_connections"` // connections opened and pinged before serving, and kept open; 0 disables
+	PingInterval    int `mapstructure:"ping_interval"`    // seconds between pings of the idle connections; 0 disables
+}
+
+type ResilienceConfig struct {
+	BreakerEnabled      bool `mapstructure:"breaker_enabled"`
+	FailureThreshold    int  `mapstructure:"failure_threshold"`      // consecutive failures before opening
+	OpenTimeout         int  `mapstructure:"open_timeout"`           // seconds before probing (half-open)
+	HalfOpenMaxRequests int  `mapstructure:"half_open_max_requests"` // probes allowed while half-open
+	RetryMaxAttempts    int  `mapstructure:"retry_max_attempts"`     // total attempts for transient errors
+	RetryBaseDelayMs    int  `mapstructure:"retry_base_delay_ms"`
+	RetryMaxDelayMs     int  `mapstructure:"retry_max_delay_ms"`
 }
 
 type APIConfig struct {
-	RateLimit       int `mapstructure:"rate_limit"`
-	RequestTimeout  int `mapstructure:"request_timeout"`
-	ShutdownTimeout int `mapstructure:"shutdown_timeout"`
+	RateLimit       int               `mapstructure:"rate_limit"`
+	ShutdownTimeout int               `mapstructure:"shutdown_timeout"`
+	Versioning      VersioningConfig  `mapstructure:"versioning"`
+	BodyLimits      BodyLimitsConfig  `mapstructure:"body_limits"`
+	Timeouts        TimeoutsConfig    `mapstructure:"timeouts"`
+	Compression     CompressionConfig `mapstructure:"compression"`
+	Errors          ErrorsConfig      `mapstructure:"errors"`
+	StrictQuery     bool              `mapstructure:"strict_query"` // reject unknown query parameters and malformed times with 400
+}
+
+// ErrorsConfig selects the format of error responses
+type ErrorsConfig struct {
+	Format          string `mapstructure:"format"`            // envelope or problem (RFC 7807); requests accepting application/problem+json always get problem documents
+	ProblemTypeBase string `mapstructure:"problem_type_base"` // URI prefix of problem types, followed by the error code; empty sends about:blank
+}
+
+// CompressionConfig negotiates the encoding of response bodies with the
+// Accept-Encoding of each request
+type CompressionConfig struct {
+	Enabled          bool     `mapstructure:"enabled"`
+	Level            string   `mapstructure:"level"`              // fastest, default or best, mapped onto each encoding's own scale
+	MinBytes         int      `mapstructure:"min_bytes"`          // smaller bodies are sent as is; streamed bodies are always compressed
+	Encodings        []string `mapstructure:"encodings"`          // zstd, br, gzip or deflate, by preference among those a client accepts equally
+	SkipContentTypes []string `mapstructure:"skip_content_types"` // media types, or prefixes ending in "/", sent as is because they are already compressed
+}
+
+type TimeoutsConfig struct {
+	Default int `mapstructure:"default"` // seconds a request may run before its queries are cancelled, 0 disables
+	Upload  int `mapstructure:"upload"`  // seconds, applies to CSV upload routes
+}
+
+type BodyLimitsConfig struct {
+	Default int64 `mapstructure:"default"` // bytes, applies to every route without an override
+	Upload  int64 `mapstructure:"upload"`  // bytes, applies to CSV upload routes
+}
+
+// Max returns the largest configured limit, used as the server-wide ceiling
+func (b BodyLimitsConfig) Max() int64 {
+	return max(b.Default, b.Upload)
+}
+
+type VersioningConfig struct {
+	V1Deprecated      bool   `mapstructure:"v1_deprecated"`
+	V1DeprecationDate string `mapstructure:"v1_deprecation_date"` // YYYY-MM-DD
+	V1SunsetDate      string `mapstructure:"v1_sunset_date"`      // YYYY-MM-DD
 }
 
 type LoggingConfig struct {
@@ -48,6 +153,16 @@ type LoggingConfig struct {
 	LogstashHost   string `mapstructure:"logstash_host"`
 	LogstashPort   int    `mapstructure:"logstash_port"`
 	EnableLogstash bool   `mapstructure:"enable_logstash"`
+
+	Payloads PayloadLoggingConfig `mapstructure:"payloads"`
+}
+
+// PayloadLoggingConfig logs request and response bodies at debug level, for
+// debugging environments only
+type PayloadLoggingConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	MaxBytes     int      `mapstructure:"max_bytes"`     // bodies are truncated past this size; larger request bodies are not read
+	RedactFields []string `mapstructure:"redact_fields"` // headers, query parameters and JSON fields masked besides credentials and the API key header
 }
 
 type CORSConfig struct {
@@ -64,6 +179,353 @@ type TracingConfig struct {
 	SamplingRate   float64 `mapstructure:"sampling_rate"`
 }
 
+type MetricsConfig struct {
+	PathLabels bool     `mapstructure:"path_labels"` // label HTTP metrics by route template; false merges every path into one series
+	Paths      []string `mapstructure:"paths"`       // route templates labelled, others count as "other"; empty labels every registered route
+}
+
+type CacheConfig struct {
+	Enabled    bool           `mapstructure:"enabled"`
+	DefaultTTL int            `mapstructure:"default_ttl"` // seconds
+	MaxBytes   uint           `mapstructure:"max_bytes"`
+	RouteTTLs  map[string]int `mapstructure:"route_ttls"` // seconds, keyed by route name
+}
+
+// TTL returns the cache TTL for the named route, falling back to the default TTL
+func (c CacheConfig) TTL(route string) time.Duration {
+	if ttl, ok := c.RouteTTLs[route]; ok {
+		return time.Duration(ttl) * time.Second
+	}
+	return time.Duration(c.DefaultTTL) * time.Second
+}
+
+type AuthConfig struct {
+	Enabled       bool           `mapstructure:"enabled"`
+	Header        string         `mapstructure:"header"`
+	AnonymousRole string         `mapstructure:"anonymous_role"` // role assigned when auth is disabled
+	APIKeys       []APIKeyConfig `mapstructure:"api_keys"`
+}
+
+type APIKeyConfig struct {
+	Key       string `mapstructure:"key"`
+	Name      string `mapstructure:"name"`
+	Tenant    string `mapstructure:"tenant"`
+	Role      string `mapstructure:"role"`       // admin or user
+	DelayDays int    `mapstructure:"delay_days"` // entitles the key to bars dated this many days ago and older, e.g. 1 for T-1; 0 for every bar
+}
+
+type UsageConfig struct {
+	Enabled                bool             `mapstructure:"enabled"`
+	FlushInterval          int              `mapstructure:"flush_interval"` // seconds
+	DefaultMonthlyRowQuota int64            `mapstructure:"default_monthly_row_quota"`
+	TenantRowQuotas        map[string]int64 `mapstructure:"tenant_row_quotas"` // 0 means unlimited
+}
+
+type SecurityConfig struct {
+	HeadersEnabled        bool   `mapstructure:"headers_enabled"`
+	HSTSMaxAge            int    `mapstructure:"hsts_max_age"` // seconds, sent on HTTPS requests only
+	HSTSPreload           bool   `mapstructure:"hsts_preload"`
+	ContentSecurityPolicy string `mapstructure:"content_security_policy"`
+	// TrustedProxies lists the CIDRs of the load balancers whose
+	// X-Forwarded-For is believed; requests from other peers are taken as is
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// IPAllowlists restricts route groups (api, admin or upload) to client
+	// CIDRs; a group without an entry is reachable from anywhere
+	IPAllowlists map[string][]string `mapstructure:"ip_allowlists"`
+}
+
+// ServerConfig tunes the HTTP listener and the connections it keeps open
+type ServerConfig struct {
+	ReadTimeout      int  `mapstructure:"read_timeout"`      // seconds to read a request, 0 = none
+	WriteTimeout     int  `mapstructure:"write_timeout"`     // seconds to write a response, 0 = none
+	IdleTimeout      int  `mapstructure:"idle_timeout"`      // seconds a keep-alive connection waits for its next request, 0 = read_timeout
+	DisableKeepalive bool `mapstructure:"disable_keepalive"` // close every connection after its response
+	Concurrency      int  `mapstructure:"concurrency"`       // connections served at once over HTTP/1.1, 0 = 262144
+	Prefork          bool `mapstructure:"prefork"`           // one process per CPU sharing the port (SO_REUSEPORT), plain HTTP only
+
+	HTTP2 HTTP2Config `mapstructure:"http2"`
+}
+
+// HTTP2Config offers HTTP/2 to TLS clients through ALPN, next to HTTP/1.1
+type HTTP2Config struct {
+	Enabled              bool `mapstructure:"enabled"`                // needs tls.enabled
+	MaxConcurrentStreams int  `mapstructure:"max_concurrent_streams"` // per connection, 0 = 250
+}
+
+type TLSConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	CertFile     string `mapstructure:"cert_file"`
+	KeyFile      string `mapstructure:"key_file"`
+	ClientCAFile string `mapstructure:"client_ca_file"`
+	ClientAuth   string `mapstructure:"client_auth"` // none, request, verify_if_given, require
+	MinVersion   string `mapstructure:"min_version"` // 1.2 or 1.3
+}
+
+type IngestionConfig struct {
+	BatchSize          int   `mapstructure:"batch_size"`           // rows per insert statement
+	MaxParallelBatches int   `mapstructure:"max_parallel_batches"` // batches inserted concurrently per upload
+	MaxFileSize        int64 `mapstructure:"max_file_size"`        // bytes, 0 means unlimited (bounded by api.body_limits.upload)
+	MaxErrors          int   `mapstructure:"max_errors"`           // failed rows before an upload aborts, 0 means unlimited
+
+	MaxErrorRate     float64 `mapstructure:"max_error_rate"`      // percent of failed rows before an upload aborts, 0 disables
+	ErrorRateMinRows int     `mapstructure:"error_rate_min_rows"` // rows read before max_error_rate applies
+
+	Transforms []SourceTransforms `mapstructure:"transforms"` // per-source row transforms
+
+	CaptureAttributes bool `mapstructure:"capture_attributes"` // keep unmapped CSV columns as bar attributes
+
+	Staged        bool  `mapstructure:"staged"`          // land every upload in the staging table, validate it with SQL and merge it in one statement
+	StagedMinSize int64 `mapstructure:"staged_min_size"` // bytes from which uploads are staged, 0 stages only the uploads asking for it
+
+	UnknownSymbols string `mapstructure:"unknown_symbols"` // register (metadata stubs), reject or allow uploaded symbols without metadata or bars
+	StubCurrency   string `mapstructure:"stub_currency"`   // currency of the registered stubs until their metadata is set, default USD
+
+	Signing SigningConfig `mapstructure:"signing"` // HMAC signatures required of machine pushes
+
+	Session WriteSessionConfig `mapstructure:"session"` // MySQL session of the transactions writing bars
+}
+
+// WriteSessionConfig tunes the MySQL transactions writing bars, so backfills
+// and live ingestion overlapping on the same symbols neither stall each other
+// for the server's lock wait timeout nor take the gap locks of repeatable read
+type WriteSessionConfig struct {
+	IsolationLevel  string `mapstructure:"isolation_level"`   // read_uncommitted, read_committed, repeatable_read or serializable; empty keeps the server default
+	LockWaitTimeout int    `mapstructure:"lock_wait_timeout"` // seconds a write waits for a row lock (innodb_lock_wait_timeout), 0 keeps the server default
+}
+
+// SigningConfig requires the pushes of the listed sources to the upload routes
+// to carry an HMAC signature of their body, timestamp and nonce
+type SigningConfig struct {
+	MaxSkew int             `mapstructure:"max_skew"` // seconds a signature timestamp may be off the server clock
+	Sources []SigningSource `mapstructure:"sources"`
+}
+
+// SigningSource is a vendor pushing with an API key and signing with a shared secret
+type SigningSource struct {
+	Name      string `mapstructure:"name"`       // identifies the source in logs and metrics
+	APIKey    string `mapstructure:"api_key"`    // name of the API key the source pushes with; its uploads must be signed
+	SecretEnv string `mapstructure:"secret_env"` // environment variable holding the shared secret
+}
+
+// Secret returns the shared secret of the source from its environment variable
+func (c SigningSource) Secret() string {
+	if c.SecretEnv == "" {
+		return ""
+	}
+	return os.Getenv(c.SecretEnv)
+}
+
+// SourceTransforms names the transforms applied to the rows of one source
+type SourceTransforms struct {
+	Source string   `mapstructure:"source"` // backfill provider or uploaded file name
+	Apply  []string `mapstructure:"apply"`  // transform names, applied in order
+}
+
+// TransformsFor returns the transforms configured for a source
+func (c IngestionConfig) TransformsFor(source string) []string {
+	for _, t := range c.Transforms {
+		if t.Source == source {
+			return t.Apply
+		}
+	}
+	return nil
+}
+
+type FetcherConfig struct {
+	Timeout  int    `mapstructure:"timeout"`   // seconds per provider request
+	StooqURL string `mapstructure:"stooq_url"` // empty disables the stooq provider
+	FileDir  string `mapstructure:"file_dir"`  // directory of <SYMBOL>.csv files, empty disables the file provider
+
+	FREDURL    string `mapstructure:"fred_url"`
+	FREDAPIKey string `mapstructure:"fred_api_key"` // empty disables the fred provider
+
+	Crypto []CryptoExchangeConfig `mapstructure:"crypto"` // kline providers, one per exchange
+}
+
+type CryptoExchangeConfig struct {
+	Exchange          string               `mapstructure:"exchange"` // binance or coinbase, also the provider name
+	BaseURL           string               `mapstructure:"base_url"`
+	Interval          string               `mapstructure:"interval"`            // kline interval rolled up into daily bars, e.g. 1h; default 1d
+	RequestsPerSecond float64              `mapstructure:"requests_per_second"` // keep under the exchange's public rate limit
+	Symbols           []CryptoSymbolConfig `mapstructure:"symbols"`             // the only symbols the provider fetches
+}
+
+type CryptoSymbolConfig struct {
+	Symbol string `mapstructure:"symbol"` // stored symbol, e.g. BTCUSD
+	Pair   string `mapstructure:"pair"`   // exchange pair, e.g. BTCUSDT on binance or BTC-USD on coinbase
+}
+
+type BackfillConfig struct {
+	Enabled          bool `mapstructure:"enabled"`
+	ChunkDays        int  `mapstructure:"chunk_days"`          // days of history fetched per chunk
+	MaxConcurrency   int  `mapstructure:"max_concurrency"`     // chunks fetched in parallel
+	MaxSymbols       int  `mapstructure:"max_symbols"`         // symbols accepted per backfill
+	MaxAttempts      int  `mapstructure:"max_attempts"`        // attempts per chunk before it fails
+	PollInterval     int  `mapstructure:"poll_interval"`       // seconds between checks for pending backfills
+	RetryBaseDelayMs int  `mapstructure:"retry_base_delay_ms"` // delay before the second attempt, doubled per attempt
+}
+
+type AnalyticsConfig struct {
+	RiskFreeRate float64              `mapstructure:"risk_free_rate"` // annual percent used by Sharpe ratios
+	Rollups      RollupsConfig        `mapstructure:"rollups"`
+	Cache        AnalyticsCacheConfig `mapstructure:"cache"`
+}
+
+// AnalyticsCacheConfig configures the cache of computed analytics results,
+// invalidated per symbol as its bars are written
+type AnalyticsCacheConfig struct {
+	Enabled    bool        `mapstructure:"enabled"`
+	Backend    string      `mapstructure:"backend"`     // memory or redis
+	TTL        int         `mapstructure:"ttl"`         // seconds a result is served
+	MaxEntries int         `mapstructure:"max_entries"` // results kept by the memory backend; 0 for no bound
+	MaxBytes   int64       `mapstructure:"max_bytes"`   // bytes kept by the memory backend; 0 for no bound
+	Redis      RedisConfig `mapstructure:"redis"`
+}
+
+type RedisConfig struct {
+	Addr      string `mapstructure:"addr"` // host:port
+	Password  string `mapstructure:"password"`
+	DB        int    `mapstructure:"db"`
+	KeyPrefix string `mapstructure:"key_prefix"` // prepended to every key, so instances of several deployments can share a server
+	PoolSize  int    `mapstructure:"pool_size"`  // idle connections kept open
+	Timeout   int    `mapstructure:"timeout"`    // milliseconds per command
+}
+
+type RollupsConfig struct {
+	Enabled bool `mapstructure:"enabled"` // maintain weekly and monthly rollups on ingest and serve aggregates from them
+}
+
+type AlertsConfig struct {
+	Enabled        bool       `mapstructure:"enabled"`
+	WebhookTimeout int        `mapstructure:"webhook_timeout"` // seconds per webhook delivery
+	SMTP           SMTPConfig `mapstructure:"smtp"`
+}
+
+type TicksConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	BatchSize       int      `mapstructure:"batch_size"`       // ticks per insert statement
+	MaxBars         int      `mapstructure:"max_bars"`         // bars a single aggregation may return
+	CachedIntervals []string `mapstructure:"cached_intervals"` // bar intervals kept in the response cache, e.g. 1m
+	// Exchanges holds the trading sessions ticks are tagged with, keyed by
+	// the exchange of the symbol metadata (case-insensitive)
+	Exchanges map[string]ExchangeCalendarConfig `mapstructure:"exchanges"`
+}
+
+// ExchangeCalendarConfig holds the trading sessions of an exchange in its
+// local time (HH:MM). Weekends and holidays have no session.
+type ExchangeCalendarConfig struct {
+	Timezone  string   `mapstructure:"timezone"`   // IANA zone, e.g. America/New_York
+	PreOpen   string   `mapstructure:"pre_open"`   // start of the pre-market session
+	Open      string   `mapstructure:"open"`       // start of the regular session
+	Close     string   `mapstructure:"close"`      // end of the regular session
+	PostClose string   `mapstructure:"post_close"` // end of the post-market session
+	Holidays  []string `mapstructure:"holidays"`   // YYYY-MM-DD
+}
+
+// SessionBounds returns the session bounds as offsets from local midnight
+func (c ExchangeCalendarConfig) SessionBounds() (preOpen, open, close, postClose time.Duration, err error) {
+	var bounds [4]time.Duration
+	for i, value := range []string{c.PreOpen, c.Open, c.Close, c.PostClose} {
+		t, err := time.Parse("15:04", value)
+		if err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("invalid time %q: expected HH:MM", value)
+		}
+		bounds[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return bounds[0], bounds[1], bounds[2], bounds[3], nil
+}
+
+type ExportsConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	Storage      string   `mapstructure:"storage"`       // where artifacts are kept: local or s3
+	LocalDir     string   `mapstructure:"local_dir"`     // artifact directory of local storage
+	PublicURL    string   `mapstructure:"public_url"`    // base URL of local download links, e.g. https://api.example.com
+	SigningKey   string   `mapstructure:"signing_key"`   // signs local download links
+	URLTTL       int      `mapstructure:"url_ttl"`       // seconds a download link stays valid
+	Retention    int      `mapstructure:"retention"`     // hours an artifact is kept after the export completes
+	MaxSymbols   int      `mapstructure:"max_symbols"`   // symbols accepted per export
+	PollInterval int      `mapstructure:"poll_interval"` // seconds between checks for pending exports and expired artifacts
+	S3           S3Config `mapstructure:"s3"`
+}
+
+// SharesConfig configures the signed links answering a data query without an API key
+type SharesConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	PublicURL  string `mapstructure:"public_url"`  // base URL of share links, e.g. https://api.example.com
+	SigningKey string `mapstructure:"signing_key"` // signs share links; rotating it revokes every link
+	DefaultTTL int    `mapstructure:"default_ttl"` // seconds a link stays valid when the request sets no ttl
+	MaxTTL     int    `mapstructure:"max_ttl"`     // longest ttl a link may be minted with, in seconds
+}
+
+type S3Config struct {
+	Bucket          string `mapstructure:"bucket"`
+	Region          string `mapstructure:"region"`
+	Endpoint        string `mapstructure:"endpoint"` // defaults to https://s3.<region>.amazonaws.com; set for S3-compatible stores
+	Prefix          string `mapstructure:"prefix"`   // prepended to artifact keys
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+}
+
+type PullConfig struct {
+	Enabled  bool               `mapstructure:"enabled"`
+	Timeout  int                `mapstructure:"timeout"`   // seconds per connection attempt and remote operation
+	SpoolDir string             `mapstructure:"spool_dir"` // downloads are kept here until ingested, so an interrupted ingest resumes
+	Sources  []PullSourceConfig `mapstructure:"sources"`
+}
+
+type PullSourceConfig struct {
+	Name           string `mapstructure:"name"`     // identifies the source in status reports
+	Protocol       string `mapstructure:"protocol"` // sftp or ftp
+	Host           string `mapstructure:"host"`
+	Port           int    `mapstructure:"port"` // defaults to 22 for sftp and 21 for ftp
+	Username       string `mapstructure:"username"`
+	PasswordEnv    string `mapstructure:"password_env"`     // environment variable holding the password
+	PrivateKeyFile string `mapstructure:"private_key_file"` // sftp only
+	HostKey        string `mapstructure:"host_key"`         // sftp only: the server key as in known_hosts, e.g. "ssh-ed25519 AAAA..."
+	Directory      string `mapstructure:"directory"`        // remote directory polled for files
+	Pattern        string `mapstructure:"pattern"`          // file name glob, e.g. *.csv; empty matches every file
+	ArchiveDir     string `mapstructure:"archive_dir"`      // ingested files are moved here; empty leaves them in place
+	Interval       int    `mapstructure:"interval"`         // seconds between polls
+	Tenant         string `mapstructure:"tenant"`           // tenant the ingested uploads are recorded for
+}
+
+// Password returns the password of the source from its environment variable
+func (c PullSourceConfig) Password() string {
+	if c.PasswordEnv == "" {
+		return ""
+	}
+	return os.Getenv(c.PasswordEnv)
+}
+
+type FeaturesConfig struct {
+	Flags           map[string]bool `mapstructure:"flags"`            // overridden by FEATURE_<NAME>=true|false
+	RemoteURL       string          `mapstructure:"remote_url"`       // optional JSON object of flags overriding the ones above
+	RefreshInterval int             `mapstructure:"refresh_interval"` // seconds between polls of remote_url
+}
+
+type NotificationsConfig struct {
+	Enabled            bool                 `mapstructure:"enabled"`
+	Email              []string             `mapstructure:"email"`                // recipients; delivery uses alerts.smtp
+	SlackWebhookURL    string               `mapstructure:"slack_webhook_url"`    // Slack incoming webhook
+	Timeout            int                  `mapstructure:"timeout"`              // seconds per delivery
+	ErrorRateThreshold float64              `mapstructure:"error_rate_threshold"` // share of failed rows or chunks that notifies, 0 disables
+	FreshnessInterval  int                  `mapstructure:"freshness_interval"`   // seconds between freshness checks
+	Freshness          []FreshnessSLAConfig `mapstructure:"freshness"`
+}
+
+type FreshnessSLAConfig struct {
+	Symbol string `mapstructure:"symbol"`
+	MaxAge int    `mapstructure:"max_age"` // hours allowed between the date of the latest bar and now
+}
+
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"` // email delivery is disabled without a host
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
 // Load loads configuration from file and environment variables
 func Load() (*Config, error) {
 	env := getEnv("APP_ENV", "dev")
@@ -80,6 +542,17 @@ func Load() (*Config, error) {
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	return read()
+}
+
+// Reload reads the configuration file chosen by Load again, with the same
+// environment overrides. Callers decide which of the settings to apply.
+func Reload() (*Config, error) {
+	return read()
+}
+
+// read reads and unmarshals the configuration file
+func read() (*Config, error) {
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -94,6 +567,9 @@ func Load() (*Config, error) {
 	// Override with environment variables if present
 	overrideFromEnv(&config)
 
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
 
@@ -103,6 +579,12 @@ func overrideFromEnv(cfg *Config) {
 			cfg.App.Port = port
 		}
 	}
+	if val := os.Getenv("DB_DRIVER"); val != "" {
+		cfg.Database.Driver = val
+	}
+	if val := os.Getenv("DB_SQLITE_PATH"); val != "" {
+		cfg.Database.SQLitePath = val
+	}
 	if val := os.Getenv("DB_HOST"); val != "" {
 		cfg.Database.Host = val
 	}
@@ -120,6 +602,17 @@ func overrideFromEnv(cfg *Config) {
 	if val := os.Getenv("DB_PASSWORD"); val != "" {
 		cfg.Database.Password = val
 	}
+	if val := os.Getenv("DB_CONNECT_MAX_WAIT"); val != "" {
+		if wait, err := strconv.Atoi(val); err == nil {
+			cfg.Database.Connect.MaxWait = wait
+		}
+	}
+	if val := os.Getenv("DB_EXPLAIN_ENABLED"); val != "" {
+		cfg.Database.Explain.Enabled = val == "true"
+	}
+	if val := os.Getenv("AUTO_MIGRATE"); val != "" {
+		cfg.Database.AutoMigrate = val == "true"
+	}
 	if val := os.Getenv("LOG_LEVEL"); val != "" {
 		cfg.Logging.Level = val
 	}
@@ -134,12 +627,245 @@ func overrideFromEnv(cfg *Config) {
 	if val := os.Getenv("ENABLE_LOGSTASH"); val != "" {
 		cfg.Logging.EnableLogstash = val == "true"
 	}
+	if val := os.Getenv("LOG_PAYLOADS"); val != "" {
+		cfg.Logging.Payloads.Enabled = val == "true"
+	}
 	if val := os.Getenv("TRACING_ENABLED"); val != "" {
 		cfg.Tracing.Enabled = val == "true"
 	}
 	if val := os.Getenv("JAEGER_ENDPOINT"); val != "" {
 		cfg.Tracing.JaegerEndpoint = val
 	}
+	if val := os.Getenv("CACHE_ENABLED"); val != "" {
+		cfg.Cache.Enabled = val == "true"
+	}
+	if val := os.Getenv("AUTH_ENABLED"); val != "" {
+		cfg.Auth.Enabled = val == "true"
+	}
+	if val := os.Getenv("AUTH_API_KEYS"); val != "" {
+		cfg.Auth.APIKeys = parseAPIKeys(val)
+	}
+	if val := os.Getenv("TLS_ENABLED"); val != "" {
+		cfg.TLS.Enabled = val == "true"
+	}
+	if val := os.Getenv("TLS_CERT_FILE"); val != "" {
+		cfg.TLS.CertFile = val
+	}
+	if val := os.Getenv("TLS_KEY_FILE"); val != "" {
+		cfg.TLS.KeyFile = val
+	}
+	if val := os.Getenv("TLS_CLIENT_CA_FILE"); val != "" {
+		cfg.TLS.ClientCAFile = val
+	}
+	if val := os.Getenv("USAGE_ENABLED"); val != "" {
+		cfg.Usage.Enabled = val == "true"
+	}
+	if val := os.Getenv("INGEST_BATCH_SIZE"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			cfg.Ingestion.BatchSize = n
+		}
+	}
+	if val := os.Getenv("INGEST_MAX_PARALLEL_BATCHES"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			cfg.Ingestion.MaxParallelBatches = n
+		}
+	}
+	if val := os.Getenv("INGEST_MAX_FILE_SIZE"); val != "" {
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			cfg.Ingestion.MaxFileSize = n
+		}
+	}
+	if val := os.Getenv("INGEST_MAX_ERRORS"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			cfg.Ingestion.MaxErrors = n
+		}
+	}
+	if val := os.Getenv("INGEST_MAX_ERROR_RATE"); val != "" {
+		if rate, err := strconv.ParseFloat(val, 64); err == nil {
+			cfg.Ingestion.MaxErrorRate = rate
+		}
+	}
+	if val := os.Getenv("INGEST_CAPTURE_ATTRIBUTES"); val != "" {
+		cfg.Ingestion.CaptureAttributes = val == "true"
+	}
+	if val := os.Getenv("INGEST_STAGED"); val != "" {
+		cfg.Ingestion.Staged = val == "true"
+	}
+	if val := os.Getenv("INGEST_ISOLATION_LEVEL"); val != "" {
+		cfg.Ingestion.Session.IsolationLevel = val
+	}
+	if val := os.Getenv("INGEST_LOCK_WAIT_TIMEOUT"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			cfg.Ingestion.Session.LockWaitTimeout = n
+		}
+	}
+	if val := os.Getenv("INGEST_UNKNOWN_SYMBOLS"); val != "" {
+		cfg.Ingestion.UnknownSymbols = val
+	}
+	if val := os.Getenv("BACKFILL_ENABLED"); val != "" {
+		cfg.Backfill.Enabled = val == "true"
+	}
+	if val := os.Getenv("BACKFILL_MAX_CONCURRENCY"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			cfg.Backfill.MaxConcurrency = n
+		}
+	}
+	if val := os.Getenv("FETCHER_STOOQ_URL"); val != "" {
+		cfg.Fetcher.StooqURL = val
+	}
+	if val := os.Getenv("FETCHER_FILE_DIR"); val != "" {
+		cfg.Fetcher.FileDir = val
+	}
+	if val := os.Getenv("FETCHER_FRED_API_KEY"); val != "" {
+		cfg.Fetcher.FREDAPIKey = val
+	}
+	if val := os.Getenv("ANALYTICS_RISK_FREE_RATE"); val != "" {
+		if rate, err := strconv.ParseFloat(val, 64); err == nil {
+			cfg.Analytics.RiskFreeRate = rate
+		}
+	}
+	if val := os.Getenv("ROLLUPS_ENABLED"); val != "" {
+		cfg.Analytics.Rollups.Enabled = val == "true"
+	}
+	if val := os.Getenv("ANALYTICS_CACHE_ENABLED"); val != "" {
+		cfg.Analytics.Cache.Enabled = val == "true"
+	}
+	if val := os.Getenv("ANALYTICS_CACHE_BACKEND"); val != "" {
+		cfg.Analytics.Cache.Backend = val
+	}
+	if val := os.Getenv("REDIS_ADDR"); val != "" {
+		cfg.Analytics.Cache.Redis.Addr = val
+	}
+	if val := os.Getenv("REDIS_PASSWORD"); val != "" {
+		cfg.Analytics.Cache.Redis.Password = val
+	}
+	if val := os.Getenv("ALERTS_ENABLED"); val != "" {
+		cfg.Alerts.Enabled = val == "true"
+	}
+	if val := os.Getenv("SMTP_HOST"); val != "" {
+		cfg.Alerts.SMTP.Host = val
+	}
+	if val := os.Getenv("SMTP_PORT"); val != "" {
+		if port, err := strconv.Atoi(val); err == nil {
+			cfg.Alerts.SMTP.Port = port
+		}
+	}
+	if val := os.Getenv("SMTP_USERNAME"); val != "" {
+		cfg.Alerts.SMTP.Username = val
+	}
+	if val := os.Getenv("SMTP_PASSWORD"); val != "" {
+		cfg.Alerts.SMTP.Password = val
+	}
+	if val := os.Getenv("SMTP_FROM"); val != "" {
+		cfg.Alerts.SMTP.From = val
+	}
+	if val := os.Getenv("TICKS_ENABLED"); val != "" {
+		cfg.Ticks.Enabled = val == "true"
+	}
+	if val := os.Getenv("EXPORTS_ENABLED"); val != "" {
+		cfg.Exports.Enabled = val == "true"
+	}
+	if val := os.Getenv("HTTP2_ENABLED"); val != "" {
+		cfg.Server.HTTP2.Enabled = val == "true"
+	}
+	if val := os.Getenv("SERVER_PREFORK"); val != "" {
+		cfg.Server.Prefork = val == "true"
+	}
+	if val := os.Getenv("API_ERROR_FORMAT"); val != "" {
+		cfg.API.Errors.Format = val
+	}
+	if val := os.Getenv("API_STRICT_QUERY"); val != "" {
+		cfg.API.StrictQuery = val == "true"
+	}
+	if val := os.Getenv("TRUSTED_PROXIES"); val != "" {
+		cfg.Security.TrustedProxies = strings.Split(val, ",")
+	}
+	if val := os.Getenv("ADMIN_ALLOWED_CIDRS"); val != "" {
+		setIPAllowlist(cfg, "admin", val)
+	}
+	if val := os.Getenv("UPLOAD_ALLOWED_CIDRS"); val != "" {
+		setIPAllowlist(cfg, "upload", val)
+	}
+	if val := os.Getenv("EXPORTS_STORAGE"); val != "" {
+		cfg.Exports.Storage = val
+	}
+	if val := os.Getenv("EXPORTS_PUBLIC_URL"); val != "" {
+		cfg.Exports.PublicURL = val
+	}
+	if val := os.Getenv("EXPORTS_SIGNING_KEY"); val != "" {
+		cfg.Exports.SigningKey = val
+	}
+	if val := os.Getenv("SHARES_SIGNING_KEY"); val != "" {
+		cfg.Shares.SigningKey = val
+	}
+	if val := os.Getenv("EXPORTS_S3_BUCKET"); val != "" {
+		cfg.Exports.S3.Bucket = val
+	}
+	if val := os.Getenv("EXPORTS_S3_ACCESS_KEY_ID"); val != "" {
+		cfg.Exports.S3.AccessKeyID = val
+	}
+	if val := os.Getenv("EXPORTS_S3_SECRET_ACCESS_KEY"); val != "" {
+		cfg.Exports.S3.SecretAccessKey = val
+	}
+	if val := os.Getenv("PULL_ENABLED"); val != "" {
+		cfg.Pull.Enabled = val == "true"
+	}
+	if val := os.Getenv("NOTIFICATIONS_ENABLED"); val != "" {
+		cfg.Notifications.Enabled = val == "true"
+	}
+	if val := os.Getenv("NOTIFICATIONS_EMAIL"); val != "" {
+		cfg.Notifications.Email = strings.Split(val, ",")
+	}
+	if val := os.Getenv("SLACK_WEBHOOK_URL"); val != "" {
+		cfg.Notifications.SlackWebhookURL = val
+	}
+	if val := os.Getenv("FEATURES_REMOTE_URL"); val != "" {
+		cfg.Features.RemoteURL = val
+	}
+	for _, env := range os.Environ() {
+		name, val, _ := strings.Cut(env, "=")
+		if flag, ok := strings.CutPrefix(name, "FEATURE_"); ok && flag != "" {
+			if cfg.Features.Flags == nil {
+				cfg.Features.Flags = make(map[string]bool)
+			}
+			cfg.Features.Flags[strings.ToLower(flag)] = val == "true"
+		}
+	}
+}
+
+// setIPAllowlist replaces the allowlist of a route group with a comma separated list of CIDRs
+func setIPAllowlist(cfg *Config, group, val string) {
+	if cfg.Security.IPAllowlists == nil {
+		cfg.Security.IPAllowlists = make(map[string][]string)
+	}
+	cfg.Security.IPAllowlists[group] = strings.Split(val, ",")
+}
+
+// parseAPIKeys parses API keys in the form "key:name:tenant:role;key:name:tenant:role",
+// each optionally followed by ":delay_days"
+func parseAPIKeys(val string) []APIKeyConfig {
+	keys := make([]APIKeyConfig, 0)
+	for _, entry := range strings.Split(val, ";") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if (len(parts) != 4 && len(parts) != 5) || parts[0] == "" {
+			continue
+		}
+		key := APIKeyConfig{
+			Key:    parts[0],
+			Name:   parts[1],
+			Tenant: parts[2],
+			Role:   parts[3],
+		}
+		if len(parts) == 5 {
+			delay, err := strconv.Atoi(parts[4])
+			if err != nil {
+				continue
+			}
+			key.DelayDays = delay
+		}
+		keys = append(keys, key)
+	}
+	return keys
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,335 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+// Error implements the error interface, one problem per line
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// problems collects the problems of a configuration
+type problems []string
+
+// check records a problem unless ok holds
+func (p *problems) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		*p = append(*p, fmt.Sprintf(format, args...))
+	}
+}
+
+// Validate checks the required settings, ranges and mutually exclusive
+// options of every section, and reports all problems at once
+func (c *Config) Validate() error {
+	var p problems
+
+	p.check(validPort(c.App.Port), "app.port must be between 1 and 65535, got %d", c.App.Port)
+
+	p.check(oneOf(c.Database.Driver, "", "mysql", "sqlite"), "database.driver must be mysql or sqlite, got %q", c.Database.Driver)
+	if c.Database.Driver == "sqlite" {
+		p.check(c.Database.SQLitePath != "", "database.sqlite_path is required with the sqlite driver (DB_SQLITE_PATH)")
+	} else {
+		p.check(c.Database.Host != "", "database.host is required (DB_HOST)")
+		p.check(validPort(c.Database.Port), "database.port must be between 1 and 65535, got %d", c.Database.Port)
+		p.check(c.Database.Name != "", "database.name is required (DB_NAME)")
+		p.check(c.Database.User != "", "database.user is required (DB_USER)")
+	}
+	p.check(c.Database.MaxOpenConns >= 0, "database.max_open_conns must not be negative")
+	p.check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns must not be negative")
+	p.check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns (%d) must not exceed database.max_open_conns (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	p.check(c.Database.SlowQueryThreshold >= 0, "database.slow_query_threshold must not be negative")
+	if c.Database.Explain.Enabled {
+		p.check(c.Database.SlowQueryThreshold > 0, "database.explain.enabled needs a positive database.slow_query_threshold, the statements explained are the slow ones")
+		p.check(c.Database.Explain.SampleRate > 0 && c.Database.Explain.SampleRate <= 1,
+			"database.explain.sample_rate must be above 0 and at most 1, got %g", c.Database.Explain.SampleRate)
+	}
+	p.check(c.Database.Connect.MaxWait >= 0, "database.connect.max_wait must not be negative")
+	if c.Database.Connect.MaxWait > 0 {
+		p.check(c.Database.Connect.BaseDelayMs > 0, "database.connect.base_delay_ms must be positive")
+		p.check(c.Database.Connect.MaxDelayMs >= c.Database.Connect.BaseDelayMs,
+			"database.connect.max_delay_ms (%d) must not be below database.connect.base_delay_ms (%d)", c.Database.Connect.MaxDelayMs, c.Database.Connect.BaseDelayMs)
+	}
+	p.check(c.Database.Pool.WarmConnections >= 0, "database.pool.warm_connections must not be negative")
+	p.check(c.Database.Pool.WarmConnections <= c.Database.MaxIdleConns,
+		"database.pool.warm_connections (%d) must not exceed database.max_idle_conns (%d), or the pool closes them", c.Database.Pool.WarmConnections, c.Database.MaxIdleConns)
+	p.check(c.Database.Pool.PingInterval >= 0, "database.pool.ping_interval must not be negative")
+	p.check(c.Database.Resilience.RetryMaxAttempts >= 0, "database.resilience.retry_max_attempts must not be negative")
+	if c.Database.Partitioning.Enabled {
+		p.check(c.Database.Partitioning.Interval > 0, "database.partitioning.interval must be positive")
+		p.check(c.Database.Partitioning.FutureMonths >= 0, "database.partitioning.future_months must not be negative")
+	}
+
+	p.check(c.API.RateLimit >= 0, "api.rate_limit must not be negative")
+	p.check(c.API.ShutdownTimeout > 0, "api.shutdown_timeout must be positive")
+	p.check(c.API.BodyLimits.Default > 0, "api.body_limits.default must be positive")
+	p.check(c.API.BodyLimits.Upload > 0, "api.body_limits.upload must be positive")
+	p.check(c.API.Timeouts.Default >= 0, "api.timeouts.default must not be negative")
+	p.check(c.API.Timeouts.Upload >= 0, "api.timeouts.upload must not be negative")
+	if c.API.Compression.Enabled {
+		p.check(oneOf(c.API.Compression.Level, "fastest", "default", "best"),
+			"api.compression.level must be fastest, default or best, got %q", c.API.Compression.Level)
+		p.check(c.API.Compression.MinBytes >= 0, "api.compression.min_bytes must not be negative")
+		p.check(len(c.API.Compression.Encodings) > 0, "api.compression.encodings needs at least one encoding")
+		for i, encoding := range c.API.Compression.Encodings {
+			p.check(oneOf(encoding, "zstd", "br", "gzip", "deflate"),
+				"api.compression.encodings[%d] must be zstd, br, gzip or deflate, got %q", i, encoding)
+		}
+	}
+	p.check(oneOf(c.API.Errors.Format, "", "envelope", "problem"),
+		"api.errors.format must be envelope or problem, got %q", c.API.Errors.Format)
+	deprecation, deprecationOK := validDate(&p, "api.versioning.v1_deprecation_date", c.API.Versioning.V1DeprecationDate)
+	sunset, sunsetOK := validDate(&p, "api.versioning.v1_sunset_date", c.API.Versioning.V1SunsetDate)
+	if deprecationOK && sunsetOK {
+		p.check(sunset.After(deprecation), "api.versioning.v1_sunset_date must be after v1_deprecation_date")
+	}
+
+	p.check(oneOf(strings.ToLower(c.Logging.Level), "debug", "info", "warn", "warning", "error", "fatal", "panic"),
+		"logging.level must be one of debug, info, warn, error, fatal or panic, got %q", c.Logging.Level)
+	p.check(oneOf(c.Logging.Format, "json", "console"), "logging.format must be json or console, got %q", c.Logging.Format)
+	if c.Logging.EnableLogstash {
+		p.check(c.Logging.LogstashHost != "", "logging.logstash_host is required when enable_logstash is set")
+		p.check(validPort(c.Logging.LogstashPort), "logging.logstash_port must be between 1 and 65535, got %d", c.Logging.LogstashPort)
+	}
+	if c.Logging.Payloads.Enabled {
+		p.check(c.Logging.Payloads.MaxBytes > 0, "logging.payloads.max_bytes must be positive")
+	}
+
+	if c.Tracing.Enabled {
+		p.check(c.Tracing.JaegerEndpoint != "", "tracing.jaeger_endpoint is required when tracing is enabled")
+		p.check(c.Tracing.SamplingRate >= 0 && c.Tracing.SamplingRate <= 1, "tracing.sampling_rate must be between 0 and 1, got %g", c.Tracing.SamplingRate)
+	}
+
+	p.check(c.Cache.DefaultTTL >= 0, "cache.default_ttl must not be negative")
+
+	p.check(oneOf(c.Auth.AnonymousRole, "", "admin", "user"), "auth.anonymous_role must be admin or user, got %q", c.Auth.AnonymousRole)
+	seenKeys := make(map[string]bool, len(c.Auth.APIKeys))
+	for i, key := range c.Auth.APIKeys {
+		p.check(key.Key != "", "auth.api_keys[%d].key is required", i)
+		p.check(oneOf(key.Role, "admin", "user"), "auth.api_keys[%d].role must be admin or user, got %q", i, key.Role)
+		p.check(key.DelayDays >= 0, "auth.api_keys[%d].delay_days must not be negative", i)
+		p.check(key.Key == "" || !seenKeys[key.Key], "auth.api_keys[%d] repeats the key of %q", i, key.Name)
+		seenKeys[key.Key] = true
+	}
+	if c.Auth.Enabled {
+		p.check(c.Auth.Header != "", "auth.header is required when auth is enabled")
+		p.check(len(c.Auth.APIKeys) > 0, "auth.api_keys (AUTH_API_KEYS) is required when auth is enabled")
+	}
+
+	for i, cidr := range c.Security.TrustedProxies {
+		p.check(validCIDR(cidr), "security.trusted_proxies[%d] must be an IP address or CIDR, got %q", i, cidr)
+	}
+	for group, cidrs := range c.Security.IPAllowlists {
+		p.check(oneOf(group, "api", "admin", "upload"), "security.ip_allowlists has unknown route group %q; use api, admin or upload", group)
+		for i, cidr := range cidrs {
+			p.check(validCIDR(cidr), "security.ip_allowlists.%s[%d] must be an IP address or CIDR, got %q", group, i, cidr)
+		}
+	}
+
+	p.check(c.Server.ReadTimeout >= 0, "server.read_timeout must not be negative")
+	p.check(c.Server.WriteTimeout >= 0, "server.write_timeout must not be negative")
+	p.check(c.Server.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	p.check(c.Server.Concurrency >= 0, "server.concurrency must not be negative")
+	p.check(c.Server.HTTP2.MaxConcurrentStreams >= 0, "server.http2.max_concurrent_streams must not be negative")
+	p.check(!c.Server.HTTP2.Enabled || c.TLS.Enabled, "server.http2.enabled needs tls.enabled")
+	p.check(!c.Server.Prefork || !c.TLS.Enabled, "server.prefork is not supported with tls.enabled")
+	p.check(!c.Server.Prefork || c.Database.Driver != "sqlite" || c.Database.SQLitePath != ":memory:",
+		"server.prefork needs a database.sqlite_path file: every process would get its own in-memory database")
+
+	p.check(oneOf(c.TLS.ClientAuth, "", "none", "request", "verify_if_given", "require"),
+		"tls.client_auth must be none, request, verify_if_given or require, got %q", c.TLS.ClientAuth)
+	p.check(oneOf(c.TLS.MinVersion, "", "1.2", "1.3"), "tls.min_version must be 1.2 or 1.3, got %q", c.TLS.MinVersion)
+	if c.TLS.Enabled {
+		p.check(c.TLS.CertFile != "" && c.TLS.KeyFile != "", "tls.cert_file and tls.key_file are required when TLS is enabled")
+		p.check(c.TLS.ClientCAFile != "" || !oneOf(c.TLS.ClientAuth, "verify_if_given", "require"),
+			"tls.client_ca_file is required when client_auth is %q", c.TLS.ClientAuth)
+	} else {
+		p.check(oneOf(c.TLS.ClientAuth, "", "none"), "tls.client_auth needs tls.enabled")
+	}
+
+	p.check(c.Ingestion.BatchSize > 0, "ingestion.batch_size must be positive")
+	p.check(c.Ingestion.MaxParallelBatches > 0, "ingestion.max_parallel_batches must be positive")
+	p.check(c.Ingestion.MaxFileSize >= 0, "ingestion.max_file_size must not be negative")
+	p.check(c.Ingestion.MaxErrors >= 0, "ingestion.max_errors must not be negative")
+	p.check(c.Ingestion.StagedMinSize >= 0, "ingestion.staged_min_size must not be negative")
+	p.check(c.Ingestion.MaxErrorRate >= 0 && c.Ingestion.MaxErrorRate <= 100, "ingestion.max_error_rate must be a percent between 0 and 100, got %g", c.Ingestion.MaxErrorRate)
+	p.check(oneOf(c.Ingestion.UnknownSymbols, "", "register", "reject", "allow"),
+		"ingestion.unknown_symbols must be register, reject or allow, got %q", c.Ingestion.UnknownSymbols)
+	p.check(c.Ingestion.StubCurrency == "" || isCurrencyCode(c.Ingestion.StubCurrency),
+		"ingestion.stub_currency must be an ISO 4217 code such as USD, got %q", c.Ingestion.StubCurrency)
+	p.check(oneOf(c.Ingestion.Session.IsolationLevel, "", "read_uncommitted", "read_committed", "repeatable_read", "serializable"),
+		"ingestion.session.isolation_level must be read_uncommitted, read_committed, repeatable_read or serializable, got %q", c.Ingestion.Session.IsolationLevel)
+	p.check(c.Ingestion.Session.LockWaitTimeout >= 0, "ingestion.session.lock_wait_timeout must not be negative")
+	if len(c.Ingestion.Signing.Sources) > 0 {
+		p.check(c.Ingestion.Signing.MaxSkew > 0, "ingestion.signing.max_skew must be positive")
+	}
+	signedKeys := make(map[string]bool, len(c.Ingestion.Signing.Sources))
+	for i, src := range c.Ingestion.Signing.Sources {
+		p.check(src.Name != "", "ingestion.signing.sources[%d].name is required", i)
+		p.check(src.APIKey != "", "ingestion.signing.sources[%d].api_key is required", i)
+		p.check(src.APIKey == "" || !signedKeys[src.APIKey], "ingestion.signing.sources[%d] repeats the api_key %q", i, src.APIKey)
+		signedKeys[src.APIKey] = true
+		p.check(src.Secret() != "", "ingestion.signing.sources[%d] (%s) has no secret; set secret_env to a non-empty variable", i, src.Name)
+	}
+
+	p.check(c.Fetcher.Timeout > 0, "fetcher.timeout must be positive")
+	for i, exchange := range c.Fetcher.Crypto {
+		p.check(exchange.RequestsPerSecond >= 0, "fetcher.crypto[%d].requests_per_second must not be negative", i)
+		p.check(len(exchange.Symbols) > 0, "fetcher.crypto[%d] (%s) needs at least one symbol", i, exchange.Exchange)
+	}
+
+	if c.Backfill.Enabled {
+		p.check(c.Backfill.ChunkDays > 0, "backfill.chunk_days must be positive")
+		p.check(c.Backfill.MaxConcurrency > 0, "backfill.max_concurrency must be positive")
+		p.check(c.Backfill.MaxSymbols > 0, "backfill.max_symbols must be positive")
+		p.check(c.Backfill.MaxAttempts > 0, "backfill.max_attempts must be positive")
+		p.check(c.Backfill.PollInterval > 0, "backfill.poll_interval must be positive")
+	}
+
+	if c.Ticks.Enabled {
+		p.check(c.Ticks.BatchSize > 0, "ticks.batch_size must be positive")
+		p.check(c.Ticks.MaxBars > 0, "ticks.max_bars must be positive")
+		for name, exchange := range c.Ticks.Exchanges {
+			_, err := time.LoadLocation(exchange.Timezone)
+			p.check(exchange.Timezone != "" && err == nil, "ticks.exchanges.%s.timezone must be an IANA zone", name)
+			preOpen, open, close, postClose, err := exchange.SessionBounds()
+			p.check(err == nil, "ticks.exchanges.%s session times must be HH:MM", name)
+			p.check(err != nil || (preOpen <= open && open < close && close <= postClose),
+				"ticks.exchanges.%s must satisfy pre_open <= open < close <= post_close", name)
+			for _, holiday := range exchange.Holidays {
+				_, err := time.Parse("2006-01-02", holiday)
+				p.check(err == nil, "ticks.exchanges.%s.holidays must be YYYY-MM-DD dates, got %q", name, holiday)
+			}
+		}
+	}
+
+	if c.Analytics.Cache.Enabled {
+		switch c.Analytics.Cache.Backend {
+		case "", "memory":
+			p.check(c.Analytics.Cache.MaxEntries >= 0, "analytics.cache.max_entries must not be negative")
+			p.check(c.Analytics.Cache.MaxBytes >= 0, "analytics.cache.max_bytes must not be negative")
+		case "redis":
+			p.check(c.Analytics.Cache.Redis.Addr != "", "analytics.cache.redis.addr (REDIS_ADDR) is required for the redis backend")
+			p.check(c.Analytics.Cache.Redis.DB >= 0, "analytics.cache.redis.db must not be negative")
+		default:
+			p.check(false, "analytics.cache.backend must be memory or redis, got %q", c.Analytics.Cache.Backend)
+		}
+		p.check(c.Analytics.Cache.TTL > 0, "analytics.cache.ttl must be positive")
+	}
+
+	if c.Exports.Enabled {
+		switch c.Exports.Storage {
+		case "", "local":
+			p.check(c.Exports.LocalDir != "", "exports.local_dir is required for local storage")
+			p.check(c.Exports.SigningKey != "", "exports.signing_key (EXPORTS_SIGNING_KEY) is required for local storage")
+		case "s3":
+			p.check(c.Exports.S3.Bucket != "" && c.Exports.S3.Region != "", "exports.s3.bucket and exports.s3.region are required for s3 storage")
+			p.check(c.Exports.S3.AccessKeyID != "" && c.Exports.S3.SecretAccessKey != "", "exports.s3 access keys are required for s3 storage")
+		default:
+			p.check(false, "exports.storage must be local or s3, got %q", c.Exports.Storage)
+		}
+		p.check(c.Exports.URLTTL > 0, "exports.url_ttl must be positive")
+		p.check(c.Exports.MaxSymbols > 0, "exports.max_symbols must be positive")
+		p.check(c.Exports.PollInterval > 0, "exports.poll_interval must be positive")
+	}
+
+	if c.Shares.Enabled {
+		p.check(c.Shares.SigningKey != "", "shares.signing_key (SHARES_SIGNING_KEY) is required when shares are enabled")
+		p.check(c.Shares.DefaultTTL > 0, "shares.default_ttl must be positive")
+		p.check(c.Shares.MaxTTL >= c.Shares.DefaultTTL, "shares.max_ttl must not be below shares.default_ttl")
+	}
+
+	if c.Pull.Enabled {
+		p.check(c.Pull.SpoolDir != "", "pull.spool_dir is required when pull sources are enabled")
+	}
+	seenSources := make(map[string]bool, len(c.Pull.Sources))
+	for i, src := range c.Pull.Sources {
+		p.check(src.Name != "", "pull.sources[%d].name is required", i)
+		p.check(src.Name == "" || !seenSources[src.Name], "pull.sources[%d] repeats the name %q", i, src.Name)
+		seenSources[src.Name] = true
+		p.check(src.Host != "", "pull.sources[%d].host is required", i)
+		p.check(src.Port == 0 || validPort(src.Port), "pull.sources[%d].port must be between 1 and 65535, got %d", i, src.Port)
+		switch src.Protocol {
+		case "sftp":
+			p.check(src.HostKey != "", "pull.sources[%d] (%s) requires a host_key for sftp", i, src.Name)
+		case "ftp":
+			p.check(src.HostKey == "" && src.PrivateKeyFile == "", "pull.sources[%d] (%s): host_key and private_key_file only apply to sftp", i, src.Name)
+		default:
+			p.check(false, "pull.sources[%d].protocol must be sftp or ftp, got %q", i, src.Protocol)
+		}
+	}
+
+	if c.Notifications.Enabled {
+		p.check(len(c.Notifications.Email) == 0 || c.Alerts.SMTP.Host != "", "notifications.email needs alerts.smtp.host")
+		p.check(c.Notifications.ErrorRateThreshold >= 0 && c.Notifications.ErrorRateThreshold <= 1,
+			"notifications.error_rate_threshold must be a share between 0 and 1, got %g", c.Notifications.ErrorRateThreshold)
+		for i, sla := range c.Notifications.Freshness {
+			p.check(sla.Symbol != "", "notifications.freshness[%d].symbol is required", i)
+			p.check(sla.MaxAge > 0, "notifications.freshness[%d].max_age must be positive", i)
+		}
+	}
+
+	p.check(c.Features.RefreshInterval >= 0, "features.refresh_interval must not be negative")
+
+	if len(p) > 0 {
+		return &ValidationError{Problems: p}
+	}
+	return nil
+}
+
+// validPort reports whether port is a TCP port number
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+// validDate parses an optional YYYY-MM-DD setting, recording a problem when malformed
+func validDate(p *problems, key, value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	date, err := time.Parse("2006-01-02", value)
+	p.check(err == nil, "%s must be a YYYY-MM-DD date, got %q", key, value)
+	return date, err == nil
+}
+
+// validCIDR reports whether value is an IP address or a CIDR
+func validCIDR(value string) bool {
+	value = strings.TrimSpace(value)
+	if _, err := netip.ParsePrefix(value); err == nil {
+		return true
+	}
+	_, err := netip.ParseAddr(value)
+	return err == nil
+}
+
+// isCurrencyCode reports whether code has the shape of an ISO 4217 code
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// oneOf reports whether value is one of the allowed values
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
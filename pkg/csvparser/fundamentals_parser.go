@@ -0,0 +1,197 @@
+package csvparser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CodeInvalidPeriod is the parse error code of a fiscal period that is neither
+// a quarter nor a fiscal year
+const CodeInvalidPeriod = "invalid_period"
+
+// FundamentalRow represents a single row of a fundamentals CSV. Figures are
+// null when the file has no such column or the cell is empty.
+type FundamentalRow struct {
+	Symbol            string
+	Period            string
+	PeriodEnd         time.Time
+	EPS               decimal.NullDecimal
+	Revenue           decimal.NullDecimal
+	NetIncome         decimal.NullDecimal
+	SharesOutstanding *uint64
+}
+
+// fundamentalHeaders lists the columns every fundamentals file must have
+var fundamentalHeaders = []string{"symbol", "period", "period_end"}
+
+// fiscalPeriodPattern matches 2024Q1, 2024-Q1, Q1 2024, 2024FY and FY2024
+var fiscalPeriodPattern = regexp.MustCompile(`^(?:(\d{4})[ -]?(Q[1-4]|FY)|(Q[1-4]|FY)[ -]?(\d{4}))$`)
+
+// NormalizeFiscalPeriod returns a fiscal period as 2024Q1 for a quarter or
+// 2024FY for a fiscal year
+func NormalizeFiscalPeriod(s string) (string, error) {
+	m := fiscalPeriodPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return "", fmt.Errorf("invalid fiscal period %q", s)
+	}
+	if m[1] != "" {
+		return m[1] + m[2], nil
+	}
+	return m[4] + m[3], nil
+}
+
+// FundamentalsParser handles CSV parsing for fundamentals: a symbol, a fiscal
+// period, its end date and the optional eps, revenue, net_income and
+// shares_outstanding columns. Dates follow the rules of Parser.
+type FundamentalsParser struct {
+	base *Parser
+}
+
+// NewFundamentalsParser creates a new fundamentals CSV parser
+func NewFundamentalsParser(r io.Reader) *FundamentalsParser {
+	return &FundamentalsParser{base: NewParser(r)}
+}
+
+// ParseHeader reads and validates the CSV header
+func (p *FundamentalsParser) ParseHeader() error {
+	header, err := p.base.reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+
+	p.base.currentLine++
+	p.base.headers = make([]string, len(header))
+	p.base.headerIndexes = make(map[string]int)
+
+	for i, h := range header {
+		normalized := strings.ToLower(strings.TrimSpace(h))
+		p.base.headers[i] = normalized
+		p.base.headerIndexes[normalized] = i
+	}
+
+	for _, required := range fundamentalHeaders {
+		if _, exists := p.base.headerIndexes[required]; !exists {
+			return errors.New(p.base.message(msgMissingHeader, required))
+		}
+	}
+
+	return nil
+}
+
+// ParseRow reads and parses a single row
+func (p *FundamentalsParser) ParseRow() (*FundamentalRow, error) {
+	record, err := p.base.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		p.base.currentLine++
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			return nil, &ParseError{
+				Line:    p.base.currentLine,
+				Code:    CodeMalformedRow,
+				Message: p.base.malformedMessage(csvErr),
+			}
+		}
+		return nil, err
+	}
+
+	p.base.currentLine++
+
+	row := &FundamentalRow{}
+
+	// Symbol
+	symbolIdx := p.base.headerIndexes["symbol"]
+	row.Symbol = strings.TrimSpace(strings.ToUpper(record[symbolIdx]))
+	if row.Symbol == "" {
+		return nil, &ParseError{
+			Line:    p.base.currentLine,
+			Field:   "symbol",
+			Value:   record[symbolIdx],
+			Code:    CodeMissingSymbol,
+			Message: p.base.message(msgMissingSymbol),
+		}
+	}
+
+	// Period
+	periodIdx := p.base.headerIndexes["period"]
+	row.Period, err = NormalizeFiscalPeriod(record[periodIdx])
+	if err != nil {
+		return nil, &ParseError{
+			Line:    p.base.currentLine,
+			Field:   "period",
+			Value:   record[periodIdx],
+			Code:    CodeInvalidPeriod,
+			Message: p.base.message(msgInvalidPeriod),
+		}
+	}
+
+	// Period end
+	endIdx := p.base.headerIndexes["period_end"]
+	endStr := strings.TrimSpace(record[endIdx])
+	row.PeriodEnd, err = p.base.parseDate(endStr)
+	if err != nil {
+		return nil, &ParseError{
+			Line:    p.base.currentLine,
+			Field:   "period_end",
+			Value:   endStr,
+			Code:    CodeInvalidDate,
+			Message: p.base.message(msgInvalidDate, strings.Join(p.base.supportedFormats, ", ")),
+		}
+	}
+
+	// Optional figures; EPS and net income may be negative
+	for _, figure := range []struct {
+		name  string
+		value *decimal.NullDecimal
+	}{{"eps", &row.EPS}, {"revenue", &row.Revenue}, {"net_income", &row.NetIncome}} {
+		idx, exists := p.base.headerIndexes[figure.name]
+		if !exists || strings.TrimSpace(record[idx]) == "" {
+			continue
+		}
+		value, err := decimal.NewFromString(strings.ReplaceAll(strings.TrimSpace(record[idx]), ",", ""))
+		if err != nil {
+			return nil, &ParseError{
+				Line:    p.base.currentLine,
+				Field:   figure.name,
+				Value:   record[idx],
+				Code:    CodeInvalidValue,
+				Message: p.base.message(msgInvalidNumber),
+			}
+		}
+		*figure.value = decimal.NewNullDecimal(value)
+	}
+
+	if idx, exists := p.base.headerIndexes["shares_outstanding"]; exists {
+		row.SharesOutstanding, err = p.base.parseOptionalUint(record[idx])
+		if err != nil {
+			return nil, &ParseError{
+				Line:    p.base.currentLine,
+				Field:   "shares_outstanding",
+				Value:   record[idx],
+				Code:    CodeInvalidValue,
+				Message: p.base.message(msgNegativeCount),
+			}
+		}
+	}
+
+	return row, nil
+}
+
+// SetLanguage makes the parser write the messages of its errors in lang
+func (p *FundamentalsParser) SetLanguage(lang string) {
+	p.base.SetLanguage(lang)
+}
+
+// GetCurrentLine returns the current line number being processed
+func (p *FundamentalsParser) GetCurrentLine() int {
+	return p.base.currentLine
+}
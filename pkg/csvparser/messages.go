@@ -0,0 +1,88 @@
+package csvparser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+
+	"github.com/go-historical-data/pkg/i18n"
+)
+
+// Keys of the parse error messages
+const (
+	msgMissingHeader    = "missing_header"
+	msgMissingSymbol    = "missing_symbol"
+	msgInvalidDate      = "invalid_date"
+	msgInvalidPrice     = "invalid_price"
+	msgInvalidCount     = "invalid_count"
+	msgInvalidNumber    = "invalid_number"
+	msgNegativeCount    = "negative_count"
+	msgInvalidPeriod    = "invalid_period"
+	msgInvalidSeriesID  = "invalid_series_id"
+	msgInvalidTimestamp = "invalid_timestamp"
+	msgFieldCount       = "field_count"
+	msgBareQuote        = "bare_quote"
+	msgQuote            = "quote"
+)
+
+// messages is the catalog of parse error messages by language and key
+var messages = map[string]map[string]string{
+	i18n.English: {
+		msgMissingHeader:    "missing required header: %s",
+		msgMissingSymbol:    "symbol cannot be empty",
+		msgInvalidDate:      "invalid date format, supported formats: %s",
+		msgInvalidPrice:     "must be a valid number with at most %d decimal places",
+		msgInvalidCount:     "must be a valid non-negative integer",
+		msgInvalidNumber:    "must be a valid number",
+		msgNegativeCount:    "must be a non-negative integer",
+		msgInvalidPeriod:    "must be a quarter such as 2024Q1 or a fiscal year such as 2024FY",
+		msgInvalidSeriesID:  "series ID must have 1 to %d characters",
+		msgInvalidTimestamp: "invalid timestamp, supported formats: RFC 3339, %s",
+		msgFieldCount:       "wrong number of fields",
+		msgBareQuote:        `bare " in non-quoted-field`,
+		msgQuote:            `extraneous or missing " in quoted-field`,
+	},
+	i18n.Vietnamese: {
+		msgMissingHeader:    "thiếu cột bắt buộc: %s",
+		msgMissingSymbol:    "mã chứng khoán không được để trống",
+		msgInvalidDate:      "ngày không đúng định dạng, các định dạng được hỗ trợ: %s",
+		msgInvalidPrice:     "phải là số hợp lệ với tối đa %d chữ số thập phân",
+		msgInvalidCount:     "phải là số nguyên không âm hợp lệ",
+		msgInvalidNumber:    "phải là số hợp lệ",
+		msgNegativeCount:    "phải là số nguyên không âm",
+		msgInvalidPeriod:    "phải là một quý như 2024Q1 hoặc một năm tài chính như 2024FY",
+		msgInvalidSeriesID:  "mã chuỗi phải có từ 1 đến %d ký tự",
+		msgInvalidTimestamp: "thời điểm không hợp lệ, các định dạng được hỗ trợ: RFC 3339, %s",
+		msgFieldCount:       "sai số lượng cột",
+		msgBareQuote:        `dấu " nằm trong ô không được đặt trong ngoặc kép`,
+		msgQuote:            `thừa hoặc thiếu dấu " trong ô đặt trong ngoặc kép`,
+	},
+}
+
+// SetLanguage makes the parser write the messages of its errors in lang,
+// English by default or for an unsupported language
+func (p *Parser) SetLanguage(lang string) {
+	p.lang = lang
+}
+
+// message renders the message of key in the language of the parser
+func (p *Parser) message(key string, args ...interface{}) string {
+	format, ok := messages[p.lang][key]
+	if !ok {
+		format = messages[i18n.English][key]
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// malformedMessage renders the reason a record is not well-formed CSV
+func (p *Parser) malformedMessage(err *csv.ParseError) string {
+	switch {
+	case errors.Is(err.Err, csv.ErrFieldCount):
+		return p.message(msgFieldCount)
+	case errors.Is(err.Err, csv.ErrBareQuote):
+		return p.message(msgBareQuote)
+	case errors.Is(err.Err, csv.ErrQuote):
+		return p.message(msgQuote)
+	}
+	return err.Err.Error()
+}
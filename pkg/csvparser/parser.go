@@ -2,33 +2,74 @@ package csvparser
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+// PriceScale is the number of decimal places prices are stored with
+const PriceScale = 8
+
 // HistoricalDataRow represents a single row from CSV
 type HistoricalDataRow struct {
 	Symbol string
 	Date   time.Time
-	Open   float64
-	High   float64
-	Low    float64
-	Close  float64
+	Open   decimal.Decimal
+	High   decimal.Decimal
+	Low    decimal.Decimal
+	Close  decimal.Decimal
 	Volume uint64
+
+	// OpenInterest and NumberOfTrades are nil when the file has no such
+	// column or the cell is empty
+	OpenInterest   *uint64
+	NumberOfTrades *uint64
+
+	// Attributes holds the values of unmapped columns by header name; nil
+	// unless the parser captures them (see CaptureUnmapped)
+	Attributes map[string]string
 }
 
+// requiredHeaders lists the columns every file must have
+var requiredHeaders = []string{"symbol", "date", "open", "high", "low", "close", "volume"}
+
+// Accepted header names of the optional columns, preferred name first
+var (
+	openInterestHeaders   = []string{"open_interest", "openinterest", "oi"}
+	numberOfTradesHeaders = []string{"number_of_trades", "trades", "trade_count"}
+)
+
+// Stable codes of parse errors, for clients to act on without matching messages
+const (
+	CodeMalformedRow  = "malformed_row" // not a well-formed CSV record, e.g. a wrong number of fields
+	CodeMissingSymbol = "missing_symbol"
+	CodeInvalidDate   = "invalid_date"
+	CodeInvalidPrice  = "invalid_price"
+	CodeInvalidVolume = "invalid_volume"
+
+	CodeInvalidOpenInterest   = "invalid_open_interest"
+	CodeInvalidNumberOfTrades = "invalid_number_of_trades"
+)
+
 // ParseError represents a parsing error with line number
 type ParseError struct {
 	Line    int
-	Field   string
+	Field   string // empty for CodeMalformedRow
 	Value   string
+	Code    string
 	Message string
 }
 
 func (e *ParseError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
 	return fmt.Sprintf("line %d, field '%s', value '%s': %s", e.Line, e.Field, e.Value, e.Message)
 }
 
@@ -39,17 +80,21 @@ type Parser struct {
 	headerIndexes    map[string]int
 	currentLine      int
 	supportedFormats []string
+	captureUnmapped  bool
+	unmapped         []int // indexes of the columns no field is parsed from
+	openInterestIdx  int   // -1 when the file has no open interest column
+	tradesIdx        int   // -1 when the file has no number of trades column
+	baseOffset       int64 // byte offset the reader started at, see ResumeAt
+	lang             string
 }
 
 // NewParser creates a new CSV parser
 func NewParser(r io.Reader) *Parser {
-	csvReader := csv.NewReader(r)
-	csvReader.TrimLeadingSpace = true
-	csvReader.ReuseRecord = true // Memory optimization
-
 	return &Parser{
-		reader:      csvReader,
-		currentLine: 0,
+		reader:          newCSVReader(r),
+		currentLine:     0,
+		openInterestIdx: -1,
+		tradesIdx:       -1,
 		supportedFormats: []string{
 			"2006-01-02",
 			"01/02/2006",
@@ -60,6 +105,20 @@ func NewParser(r io.Reader) *Parser {
 	}
 }
 
+// CaptureUnmapped makes ParseRow keep the non-empty values of columns other
+// than the required ones in HistoricalDataRow.Attributes instead of dropping them
+func (p *Parser) CaptureUnmapped() {
+	p.captureUnmapped = true
+}
+
+// newCSVReader creates the CSV reader of a parser
+func newCSVReader(r io.Reader) *csv.Reader {
+	csvReader := csv.NewReader(r)
+	csvReader.TrimLeadingSpace = true
+	csvReader.ReuseRecord = true // Memory optimization
+	return csvReader
+}
+
 // ParseHeader reads and validates the CSV header
 func (p *Parser) ParseHeader() error {
 	header, err := p.reader.Read()
@@ -79,10 +138,22 @@ func (p *Parser) ParseHeader() error {
 	}
 
 	// Validate required headers
-	requiredHeaders := []string{"symbol", "date", "open", "high", "low", "close", "volume"}
 	for _, required := range requiredHeaders {
 		if _, exists := p.headerIndexes[required]; !exists {
-			return fmt.Errorf("missing required header: %s", required)
+			return errors.New(p.message(msgMissingHeader, required))
+		}
+	}
+
+	p.openInterestIdx = p.optionalIndex(openInterestHeaders)
+	p.tradesIdx = p.optionalIndex(numberOfTradesHeaders)
+
+	// Remember the remaining columns; unnamed ones are skipped and a repeated
+	// name resolves to its last column, as for the required headers
+	p.unmapped = nil
+	for i, h := range p.headers {
+		if h != "" && p.headerIndexes[h] == i && !slices.Contains(requiredHeaders, h) &&
+			i != p.openInterestIdx && i != p.tradesIdx {
+			p.unmapped = append(p.unmapped, i)
 		}
 	}
 
@@ -93,6 +164,18 @@ func (p *Parser) ParseHeader() error {
 func (p *Parser) ParseRow() (*HistoricalDataRow, error) {
 	record, err := p.reader.Read()
 	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		p.currentLine++
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			return nil, &ParseError{
+				Line:    p.currentLine,
+				Code:    CodeMalformedRow,
+				Message: p.malformedMessage(csvErr),
+			}
+		}
 		return nil, err
 	}
 
@@ -109,7 +192,8 @@ func (p *Parser) ParseRow() (*HistoricalDataRow, error) {
 			Line:    p.currentLine,
 			Field:   "symbol",
 			Value:   record[symbolIdx],
-			Message: "symbol cannot be empty",
+			Code:    CodeMissingSymbol,
+			Message: p.message(msgMissingSymbol),
 		}
 	}
 
@@ -122,55 +206,60 @@ func (p *Parser) ParseRow() (*HistoricalDataRow, error) {
 			Line:    p.currentLine,
 			Field:   "date",
 			Value:   dateStr,
-			Message: fmt.Sprintf("invalid date format, supported formats: %s", strings.Join(p.supportedFormats, ", ")),
+			Code:    CodeInvalidDate,
+			Message: p.message(msgInvalidDate, strings.Join(p.supportedFormats, ", ")),
 		}
 	}
 
 	// Open
 	openIdx := p.headerIndexes["open"]
-	row.Open, err = p.parseFloat(record[openIdx])
+	row.Open, err = p.parsePrice(record[openIdx])
 	if err != nil {
 		return nil, &ParseError{
 			Line:    p.currentLine,
 			Field:   "open",
 			Value:   record[openIdx],
-			Message: "must be a valid number",
+			Code:    CodeInvalidPrice,
+			Message: p.message(msgInvalidPrice, PriceScale),
 		}
 	}
 
 	// High
 	highIdx := p.headerIndexes["high"]
-	row.High, err = p.parseFloat(record[highIdx])
+	row.High, err = p.parsePrice(record[highIdx])
 	if err != nil {
 		return nil, &ParseError{
 			Line:    p.currentLine,
 			Field:   "high",
 			Value:   record[highIdx],
-			Message: "must be a valid number",
+			Code:    CodeInvalidPrice,
+			Message: p.message(msgInvalidPrice, PriceScale),
 		}
 	}
 
 	// Low
 	lowIdx := p.headerIndexes["low"]
-	row.Low, err = p.parseFloat(record[lowIdx])
+	row.Low, err = p.parsePrice(record[lowIdx])
 	if err != nil {
 		return nil, &ParseError{
 			Line:    p.currentLine,
 			Field:   "low",
 			Value:   record[lowIdx],
-			Message: "must be a valid number",
+			Code:    CodeInvalidPrice,
+			Message: p.message(msgInvalidPrice, PriceScale),
 		}
 	}
 
 	// Close
 	closeIdx := p.headerIndexes["close"]
-	row.Close, err = p.parseFloat(record[closeIdx])
+	row.Close, err = p.parsePrice(record[closeIdx])
 	if err != nil {
 		return nil, &ParseError{
 			Line:    p.currentLine,
 			Field:   "close",
 			Value:   record[closeIdx],
-			Message: "must be a valid number",
+			Code:    CodeInvalidPrice,
+			Message: p.message(msgInvalidPrice, PriceScale),
 		}
 	}
 
@@ -182,7 +271,45 @@ func (p *Parser) ParseRow() (*HistoricalDataRow, error) {
 			Line:    p.currentLine,
 			Field:   "volume",
 			Value:   record[volumeIdx],
-			Message: "must be a valid non-negative integer",
+			Code:    CodeInvalidVolume,
+			Message: p.message(msgInvalidCount),
+		}
+	}
+
+	// Open interest and number of trades, when present
+	if p.openInterestIdx >= 0 {
+		row.OpenInterest, err = p.parseOptionalUint(record[p.openInterestIdx])
+		if err != nil {
+			return nil, &ParseError{
+				Line:    p.currentLine,
+				Field:   "open_interest",
+				Value:   record[p.openInterestIdx],
+				Code:    CodeInvalidOpenInterest,
+				Message: p.message(msgInvalidCount),
+			}
+		}
+	}
+	if p.tradesIdx >= 0 {
+		row.NumberOfTrades, err = p.parseOptionalUint(record[p.tradesIdx])
+		if err != nil {
+			return nil, &ParseError{
+				Line:    p.currentLine,
+				Field:   "number_of_trades",
+				Value:   record[p.tradesIdx],
+				Code:    CodeInvalidNumberOfTrades,
+				Message: p.message(msgInvalidCount),
+			}
+		}
+	}
+
+	if p.captureUnmapped {
+		for _, i := range p.unmapped {
+			if value := strings.TrimSpace(record[i]); value != "" {
+				if row.Attributes == nil {
+					row.Attributes = make(map[string]string, len(p.unmapped))
+				}
+				row.Attributes[p.headers[i]] = value
+			}
 		}
 	}
 
@@ -192,7 +319,7 @@ func (p *Parser) ParseRow() (*HistoricalDataRow, error) {
 // ParseAll reads all rows from the CSV
 func (p *Parser) ParseAll() ([]HistoricalDataRow, []error) {
 	rows := make([]HistoricalDataRow, 0)
-	errors := make([]error, 0)
+	errs := make([]error, 0)
 
 	for {
 		row, err := p.ParseRow()
@@ -200,14 +327,28 @@ func (p *Parser) ParseAll() ([]HistoricalDataRow, []error) {
 			if err == io.EOF {
 				break
 			}
-			errors = append(errors, err)
+			errs = append(errs, err)
 			continue
 		}
 
 		rows = append(rows, *row)
 	}
 
-	return rows, errors
+	return rows, errs
+}
+
+// Offset returns the byte offset of the input after the last record read
+func (p *Parser) Offset() int64 {
+	return p.baseOffset + p.reader.InputOffset()
+}
+
+// ResumeAt continues parsing from r, which reads the input from a record
+// boundary returned by Offset, with the record count of that boundary. The
+// header must have been parsed from the start of the input first.
+func (p *Parser) ResumeAt(r io.Reader, offset int64, line int) {
+	p.reader = newCSVReader(r)
+	p.baseOffset = offset
+	p.currentLine = line
 }
 
 // GetCurrentLine returns the current line number being processed
@@ -225,29 +366,55 @@ func (p *Parser) parseDate(dateStr string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("unable to parse date")
 }
 
-// parseFloat parses a float64 value
-func (p *Parser) parseFloat(s string) (float64, error) {
+// parsePrice parses a price exactly, rejecting digits beyond PriceScale that
+// storage would round away
+func (p *Parser) parsePrice(s string) (decimal.Decimal, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
-		return 0, fmt.Errorf("empty value")
+		return decimal.Zero, fmt.Errorf("empty value")
 	}
 
 	// Remove common currency symbols and commas
 	s = strings.ReplaceAll(s, ",", "")
 	s = strings.ReplaceAll(s, "$", "")
 
-	val, err := strconv.ParseFloat(s, 64)
+	val, err := decimal.NewFromString(s)
 	if err != nil {
-		return 0, err
+		return decimal.Zero, err
 	}
 
-	if val < 0 {
-		return 0, fmt.Errorf("negative value not allowed")
+	if val.IsNegative() {
+		return decimal.Zero, fmt.Errorf("negative value not allowed")
+	}
+	if !val.Equal(val.Truncate(PriceScale)) {
+		return decimal.Zero, fmt.Errorf("more than %d decimal places", PriceScale)
 	}
 
 	return val, nil
 }
 
+// parseOptionalUint parses an optional count, nil for an empty cell
+func (p *Parser) parseOptionalUint(s string) (*uint64, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	val, err := p.parseUint(s)
+	if err != nil {
+		return nil, err
+	}
+	return &val, nil
+}
+
+// optionalIndex returns the index of the first of names present in the header, or -1
+func (p *Parser) optionalIndex(names []string) int {
+	for _, name := range names {
+		if i, exists := p.headerIndexes[name]; exists {
+			return i
+		}
+	}
+	return -1
+}
+
 // parseUint parses a uint64 value
 func (p *Parser) parseUint(s string) (uint64, error) {
 	s = strings.TrimSpace(s)
@@ -265,3 +432,43 @@ func (p *Parser) parseUint(s string) (uint64, error) {
 
 	return val, nil
 }
+
+// CountRows counts the data rows (excluding the header) in a CSV stream by
+// counting line breaks. Quoted fields containing newlines are over-counted, so
+// the result is an upper bound suitable for quota pre-checks.
+func CountRows(r io.Reader) (int64, error) {
+	buf := make([]byte, 64*1024)
+	var lines int64
+	var last byte
+	var seen bool
+
+	for {
+		n, err := r.Read(buf)
+		for _, b := range buf[:n] {
+			if b == '\n' {
+				lines++
+			}
+		}
+		if n > 0 {
+			last = buf[n-1]
+			seen = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Count a trailing line without a final newline
+	if seen && last != '\n' {
+		lines++
+	}
+
+	// Exclude the header line
+	if lines > 0 {
+		lines--
+	}
+	return lines, nil
+}
@@ -0,0 +1,17 @@
+package csvparser_test
+
+import (
+	"testing"
+
+	"github.com/go-historical-data/internal/bench"
+)
+
+// BenchmarkParser measures decoding an upload CSV, row by row
+func BenchmarkParser(b *testing.B) {
+	fixture, err := bench.NewFixture(bench.DefaultOptions())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	fixture.Decode(b)
+}
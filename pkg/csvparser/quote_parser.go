@@ -0,0 +1,141 @@
+package csvparser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// QuoteRow represents a single row of a bid/ask quotes CSV
+type QuoteRow struct {
+	Symbol string
+	Date   time.Time
+	Bid    decimal.Decimal
+	Ask    decimal.Decimal
+}
+
+// quoteHeaders lists the columns every quotes file must have
+var quoteHeaders = []string{"symbol", "date", "bid", "ask"}
+
+// QuoteParser handles CSV parsing for bid/ask quotes. Dates, prices and
+// errors follow the rules of Parser.
+type QuoteParser struct {
+	base *Parser
+}
+
+// NewQuoteParser creates a new quotes CSV parser
+func NewQuoteParser(r io.Reader) *QuoteParser {
+	return &QuoteParser{base: NewParser(r)}
+}
+
+// ParseHeader reads and validates the CSV header
+func (p *QuoteParser) ParseHeader() error {
+	header, err := p.base.reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+
+	p.base.currentLine++
+	p.base.headers = make([]string, len(header))
+	p.base.headerIndexes = make(map[string]int)
+
+	for i, h := range header {
+		normalized := strings.ToLower(strings.TrimSpace(h))
+		p.base.headers[i] = normalized
+		p.base.headerIndexes[normalized] = i
+	}
+
+	for _, required := range quoteHeaders {
+		if _, exists := p.base.headerIndexes[required]; !exists {
+			return errors.New(p.base.message(msgMissingHeader, required))
+		}
+	}
+
+	return nil
+}
+
+// ParseRow reads and parses a single row
+func (p *QuoteParser) ParseRow() (*QuoteRow, error) {
+	record, err := p.base.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		p.base.currentLine++
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			return nil, &ParseError{
+				Line:    p.base.currentLine,
+				Code:    CodeMalformedRow,
+				Message: p.base.malformedMessage(csvErr),
+			}
+		}
+		return nil, err
+	}
+
+	p.base.currentLine++
+
+	row := &QuoteRow{}
+
+	// Symbol
+	symbolIdx := p.base.headerIndexes["symbol"]
+	row.Symbol = strings.TrimSpace(strings.ToUpper(record[symbolIdx]))
+	if row.Symbol == "" {
+		return nil, &ParseError{
+			Line:    p.base.currentLine,
+			Field:   "symbol",
+			Value:   record[symbolIdx],
+			Code:    CodeMissingSymbol,
+			Message: p.base.message(msgMissingSymbol),
+		}
+	}
+
+	// Date
+	dateIdx := p.base.headerIndexes["date"]
+	dateStr := strings.TrimSpace(record[dateIdx])
+	row.Date, err = p.base.parseDate(dateStr)
+	if err != nil {
+		return nil, &ParseError{
+			Line:    p.base.currentLine,
+			Field:   "date",
+			Value:   dateStr,
+			Code:    CodeInvalidDate,
+			Message: p.base.message(msgInvalidDate, strings.Join(p.base.supportedFormats, ", ")),
+		}
+	}
+
+	// Bid and ask
+	for _, field := range []struct {
+		name  string
+		value *decimal.Decimal
+	}{{"bid", &row.Bid}, {"ask", &row.Ask}} {
+		idx := p.base.headerIndexes[field.name]
+		*field.value, err = p.base.parsePrice(record[idx])
+		if err != nil {
+			return nil, &ParseError{
+				Line:    p.base.currentLine,
+				Field:   field.name,
+				Value:   record[idx],
+				Code:    CodeInvalidPrice,
+				Message: p.base.message(msgInvalidPrice, PriceScale),
+			}
+		}
+	}
+
+	return row, nil
+}
+
+// SetLanguage makes the parser write the messages of its errors in lang
+func (p *QuoteParser) SetLanguage(lang string) {
+	p.base.SetLanguage(lang)
+}
+
+// GetCurrentLine returns the current line number being processed
+func (p *QuoteParser) GetCurrentLine() int {
+	return p.base.currentLine
+}
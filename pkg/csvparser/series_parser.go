@@ -0,0 +1,184 @@
+package csvparser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SeriesValueScale is the number of decimal places series values are stored with
+const SeriesValueScale = 10
+
+// MaxSeriesIDLength is the longest accepted series ID
+const MaxSeriesIDLength = 64
+
+// Stable codes of series parse errors, in addition to CodeMalformedRow
+const (
+	CodeMissingSeriesID  = "missing_series_id"
+	CodeInvalidTimestamp = "invalid_timestamp"
+	CodeInvalidValue     = "invalid_value"
+)
+
+// SeriesRow represents a single row of a generic time series CSV
+type SeriesRow struct {
+	SeriesID  string
+	Timestamp time.Time
+	Value     decimal.Decimal
+
+	// Fields holds the non-empty values of the other columns by header name;
+	// nil when there are none
+	Fields map[string]string
+}
+
+// Accepted header names of the series columns, preferred name first
+var (
+	seriesIDHeaders  = []string{"series_id", "series", "symbol"}
+	timestampHeaders = []string{"timestamp", "ts", "date"}
+)
+
+// SeriesParser handles CSV parsing for generic time series: a series ID, a
+// timestamp, a value and any number of extra columns kept as fields.
+// Timestamps are RFC 3339 or one of the date formats of Parser.
+type SeriesParser struct {
+	base         *Parser
+	seriesIdx    int
+	timestampIdx int
+	valueIdx     int
+}
+
+// NewSeriesParser creates a new time series CSV parser
+func NewSeriesParser(r io.Reader) *SeriesParser {
+	return &SeriesParser{base: NewParser(r)}
+}
+
+// ParseHeader reads and validates the CSV header
+func (p *SeriesParser) ParseHeader() error {
+	header, err := p.base.reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+
+	p.base.currentLine++
+	p.base.headers = make([]string, len(header))
+	p.base.headerIndexes = make(map[string]int)
+
+	for i, h := range header {
+		normalized := strings.ToLower(strings.TrimSpace(h))
+		p.base.headers[i] = normalized
+		p.base.headerIndexes[normalized] = i
+	}
+
+	if p.seriesIdx = p.base.optionalIndex(seriesIDHeaders); p.seriesIdx < 0 {
+		return errors.New(p.base.message(msgMissingHeader, seriesIDHeaders[0]))
+	}
+	if p.timestampIdx = p.base.optionalIndex(timestampHeaders); p.timestampIdx < 0 {
+		return errors.New(p.base.message(msgMissingHeader, timestampHeaders[0]))
+	}
+	var ok bool
+	if p.valueIdx, ok = p.base.headerIndexes["value"]; !ok {
+		return errors.New(p.base.message(msgMissingHeader, "value"))
+	}
+
+	return nil
+}
+
+// ParseRow reads and parses a single row
+func (p *SeriesParser) ParseRow() (*SeriesRow, error) {
+	record, err := p.base.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		p.base.currentLine++
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			return nil, &ParseError{
+				Line:    p.base.currentLine,
+				Code:    CodeMalformedRow,
+				Message: p.base.malformedMessage(csvErr),
+			}
+		}
+		return nil, err
+	}
+
+	p.base.currentLine++
+
+	row := &SeriesRow{}
+
+	// Series ID
+	row.SeriesID = strings.ToUpper(strings.TrimSpace(record[p.seriesIdx]))
+	if row.SeriesID == "" || len(row.SeriesID) > MaxSeriesIDLength {
+		return nil, &ParseError{
+			Line:    p.base.currentLine,
+			Field:   "series_id",
+			Value:   record[p.seriesIdx],
+			Code:    CodeMissingSeriesID,
+			Message: p.base.message(msgInvalidSeriesID, MaxSeriesIDLength),
+		}
+	}
+
+	// Timestamp
+	tsStr := strings.TrimSpace(record[p.timestampIdx])
+	row.Timestamp, err = p.parseTimestamp(tsStr)
+	if err != nil {
+		return nil, &ParseError{
+			Line:    p.base.currentLine,
+			Field:   "timestamp",
+			Value:   tsStr,
+			Code:    CodeInvalidTimestamp,
+			Message: p.base.message(msgInvalidTimestamp, strings.Join(p.base.supportedFormats, ", ")),
+		}
+	}
+
+	// Value, which unlike a price may be zero or negative
+	valueStr := strings.TrimSpace(record[p.valueIdx])
+	row.Value, err = decimal.NewFromString(valueStr)
+	if err != nil || !row.Value.Equal(row.Value.Truncate(SeriesValueScale)) {
+		return nil, &ParseError{
+			Line:    p.base.currentLine,
+			Field:   "value",
+			Value:   valueStr,
+			Code:    CodeInvalidValue,
+			Message: p.base.message(msgInvalidPrice, SeriesValueScale),
+		}
+	}
+
+	// Every other column is an optional field
+	for i, value := range record {
+		if i == p.seriesIdx || i == p.timestampIdx || i == p.valueIdx || i >= len(p.base.headers) {
+			continue
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		if row.Fields == nil {
+			row.Fields = make(map[string]string)
+		}
+		row.Fields[p.base.headers[i]] = value
+	}
+
+	return row, nil
+}
+
+// SetLanguage makes the parser write the messages of its errors in lang
+func (p *SeriesParser) SetLanguage(lang string) {
+	p.base.SetLanguage(lang)
+}
+
+// GetCurrentLine returns the current line number being processed
+func (p *SeriesParser) GetCurrentLine() int {
+	return p.base.currentLine
+}
+
+// parseTimestamp parses an RFC 3339 timestamp, held in UTC, or a calendar date
+func (p *SeriesParser) parseTimestamp(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), nil
+	}
+	return p.base.parseDate(s)
+}
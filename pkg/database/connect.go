@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewConnection connects to the database of the configured driver
+func NewConnection(cfg config.DatabaseConfig, logLevel logger.LogLevel) (*gorm.DB, error) {
+	if cfg.Driver == "sqlite" {
+		return NewSQLiteConnection(cfg, logLevel)
+	}
+	return NewMySQLConnection(cfg, logLevel)
+}
+
+// ConnectWithRetry connects like NewConnection, retrying failed attempts with
+// exponential backoff for up to cfg.Connect.MaxWait seconds, so the API can
+// start before the database accepts connections. onRetry, when set, is called
+// before each wait with the number of failed attempts, the delay and the error.
+func ConnectWithRetry(ctx context.Context, cfg config.DatabaseConfig, logLevel logger.LogLevel, onRetry func(attempt int, delay time.Duration, err error)) (*gorm.DB, error) {
+	deadline := time.Now().Add(time.Duration(cfg.Connect.MaxWait) * time.Second)
+	delay := time.Duration(max(cfg.Connect.BaseDelayMs, 1)) * time.Millisecond
+	ceiling := time.Duration(max(cfg.Connect.MaxDelayMs, cfg.Connect.BaseDelayMs, 1)) * time.Millisecond
+
+	for attempt := 1; ; attempt++ {
+		db, err := NewConnection(cfg, logLevel)
+		if err == nil {
+			return db, nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if attempt > 1 {
+				return nil, fmt.Errorf("gave up after %d attempts in %ds: %w", attempt, cfg.Connect.MaxWait, err)
+			}
+			return nil, err
+		}
+
+		wait := min(delay, remaining)
+		if onRetry != nil {
+			onRetry(attempt, wait, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %v", ctx.Err(), err)
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, ceiling)
+	}
+}
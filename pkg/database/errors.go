@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Typed errors of database calls. ClassifyError wraps a driver error with
+// one of them, so callers match the outcome with errors.Is instead of
+// parsing messages, while the driver error stays in the chain.
+var (
+	// ErrDuplicate is a write conflicting with a stored unique key
+	ErrDuplicate = errors.New("duplicate key")
+	// ErrConstraint is a write violating a foreign key, NOT NULL or CHECK
+	// constraint, or a value the column cannot hold
+	ErrConstraint = errors.New("constraint violation")
+	// ErrUnavailable is a call the database could not serve: the connection
+	// was lost, the circuit breaker is open, or a deadlock or lock wait
+	// timeout outlasted the retries. Retrying later may succeed.
+	ErrUnavailable = errors.New("database unavailable")
+)
+
+// MySQL error numbers classified by ClassifyError
+const (
+	mysqlErrDuplicate         = 1062
+	mysqlErrDuplicateKeyName  = 1586
+	mysqlErrRowIsReferenced   = 1451
+	mysqlErrNoReferencedRow   = 1452
+	mysqlErrBadNull           = 1048
+	mysqlErrNoDefault         = 1364
+	mysqlErrDataTooLong       = 1406
+	mysqlErrOutOfRange        = 1264
+	mysqlErrCheckConstraint   = 3819
+	mysqlErrTooManyConnection = 1040
+	mysqlErrServerShutdown    = 1053
+)
+
+// SQLite primary result codes classified by ClassifyError and IsUnavailable
+const (
+	sqliteBusy                 = 5
+	sqliteLocked               = 6
+	sqliteIOErr                = 10
+	sqliteCorrupt              = 11
+	sqliteFull                 = 13
+	sqliteCantOpen             = 14
+	sqliteConstraint           = 19
+	sqliteNotADB               = 26
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// ClassifyError wraps err with the typed error it matches, or returns it
+// unchanged. An error already classified is returned as is.
+func ClassifyError(err error) error {
+	if err == nil || errors.Is(err, ErrDuplicate) || errors.Is(err, ErrConstraint) || errors.Is(err, ErrUnavailable) {
+		return err
+	}
+	if typed := classify(err); typed != nil {
+		return fmt.Errorf("%w: %w", typed, err)
+	}
+	return err
+}
+
+// classify returns the typed error matching err, nil for none
+func classify(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlErrDuplicate, mysqlErrDuplicateKeyName:
+			return ErrDuplicate
+		case mysqlErrRowIsReferenced, mysqlErrNoReferencedRow, mysqlErrBadNull, mysqlErrNoDefault,
+			mysqlErrDataTooLong, mysqlErrOutOfRange, mysqlErrCheckConstraint:
+			return ErrConstraint
+		case mysqlErrDeadlock, mysqlErrLockWaitTimeout, mysqlErrTooManyConnection, mysqlErrServerShutdown:
+			return ErrUnavailable
+		}
+		return nil
+	}
+
+	var sqliteErr *gosqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch code := sqliteErr.Code(); {
+		case code == sqliteConstraintUnique || code == sqliteConstraintPrimaryKey ||
+			code&0xff == sqliteConstraint && strings.Contains(sqliteErr.Error(), "UNIQUE constraint failed"):
+			return ErrDuplicate
+		case code&0xff == sqliteConstraint:
+			return ErrConstraint
+		case code&0xff == sqliteBusy || code&0xff == sqliteLocked:
+			return ErrUnavailable
+		}
+		return nil
+	}
+
+	// A request running out of time says nothing about the database, though
+	// its error is a net.Error
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	var netErr net.Error
+	if errors.Is(err, ErrCircuitOpen) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.As(err, &netErr) {
+		return ErrUnavailable
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// explainTimeout bounds reading the plan of a statement
+const explainTimeout = 5 * time.Second
+
+// Plan is the query plan of a statement
+type Plan struct {
+	Rows      []map[string]string // rows of EXPLAIN, or EXPLAIN QUERY PLAN on SQLite, by column
+	FullScans []string            // tables read in full
+}
+
+// Explainer reads the plan of a sample of slow selects, to catch a query that
+// lost its index before it reaches production. Plans are read in the
+// background, one at a time; a slow select arriving meanwhile is skipped, so
+// a burst of slow queries doesn't add to the load causing it.
+type Explainer struct {
+	db     *sql.DB
+	sqlite bool
+	rate   float64
+	busy   atomic.Bool
+	onPlan func(ctx context.Context, query SlowQuery, plan Plan, err error)
+}
+
+// NewExplainer creates an explainer of a share rate of the slow selects of db,
+// reporting their plans to onPlan
+func NewExplainer(db *gorm.DB, rate float64, onPlan func(ctx context.Context, query SlowQuery, plan Plan, err error)) (*Explainer, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	return &Explainer{db: sqlDB, sqlite: IsSQLite(db), rate: rate, onPlan: onPlan}, nil
+}
+
+// Explain reads the plan of query if it is a sampled, successful select
+func (e *Explainer) Explain(ctx context.Context, query SlowQuery) {
+	if query.Operation != "select" || query.Err != nil || rand.Float64() >= e.rate {
+		return
+	}
+	if !e.busy.CompareAndSwap(false, true) {
+		return
+	}
+
+	// The plan outlives the request, keeping its trace
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), explainTimeout)
+	go func() {
+		defer cancel()
+		defer e.busy.Store(false)
+		plan, err := e.plan(ctx, query)
+		e.onPlan(ctx, query, plan, err)
+	}()
+}
+
+// plan runs EXPLAIN on the connection pool directly, bypassing the callbacks
+// that would report the EXPLAIN itself
+func (e *Explainer) plan(ctx context.Context, query SlowQuery) (Plan, error) {
+	statement := "EXPLAIN " + query.SQL
+	if e.sqlite {
+		statement = "EXPLAIN QUERY PLAN " + query.SQL
+	}
+	rows, err := e.db.QueryContext(ctx, statement, query.vars...)
+	if err != nil {
+		return Plan{}, fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return Plan{}, fmt.Errorf("failed to explain query: %w", err)
+	}
+	var plan Plan
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return Plan{}, fmt.Errorf("failed to read query plan: %w", err)
+		}
+		row := make(map[string]string, len(columns))
+		for i, column := range columns {
+			row[column] = values[i].String
+		}
+		plan.Rows = append(plan.Rows, row)
+		if table, ok := e.fullScan(row); ok {
+			plan.FullScans = append(plan.FullScans, table)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return Plan{}, fmt.Errorf("failed to read query plan: %w", err)
+	}
+	return plan, nil
+}
+
+// fullScan reports the table a plan row reads in full. MySQL marks such an
+// access type ALL; SQLite details it as SCAN without an index. Derived tables
+// are read in full by design and not reported.
+func (e *Explainer) fullScan(row map[string]string) (string, bool) {
+	if e.sqlite {
+		// SCAN historical_data, or SCAN TABLE historical_data before 3.36
+		fields := strings.Fields(strings.Replace(row["detail"], "SCAN TABLE ", "SCAN ", 1))
+		if len(fields) != 2 || fields[0] != "SCAN" || strings.HasPrefix(fields[1], "(") {
+			return "", false
+		}
+		return fields[1], true
+	}
+	table := row["table"]
+	if row["type"] != "ALL" || strings.HasPrefix(table, "<") {
+		return "", false
+	}
+	return table, true
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// IndexSpec describes an index a query path relies on
+type IndexSpec struct {
+	Table   string
+	Name    string
+	Columns []string
+	Unique  bool
+	Reason  string // what breaks or slows down without it
+}
+
+// String formats the spec for logs
+func (s IndexSpec) String() string {
+	kind := "INDEX"
+	if s.Unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("%s %s ON %s (%s)", kind, s.Name, s.Table, strings.Join(s.Columns, ", "))
+}
+
+// MissingIndexes returns the specs no index of the current schema satisfies.
+// An index satisfies a spec when it starts with the spec's columns in order
+// (and is unique when the spec is), whatever its name.
+func MissingIndexes(ctx context.Context, db *gorm.DB, specs []IndexSpec) ([]IndexSpec, error) {
+	var rows []struct {
+		TableName  string
+		IndexName  string
+		NonUnique  int
+		ColumnName string
+	}
+	err := db.WithContext(ctx).Raw(`SELECT TABLE_NAME AS table_name, INDEX_NAME AS index_name, NON_UNIQUE AS non_unique, COLUMN_NAME AS column_name
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE()
+		ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX`).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index statistics: %w", err)
+	}
+
+	type index struct {
+		unique  bool
+		columns []string
+	}
+	indexes := make(map[string]map[string]*index)
+	for _, row := range rows {
+		if indexes[row.TableName] == nil {
+			indexes[row.TableName] = make(map[string]*index)
+		}
+		idx := indexes[row.TableName][row.IndexName]
+		if idx == nil {
+			idx = &index{unique: row.NonUnique == 0}
+			indexes[row.TableName][row.IndexName] = idx
+		}
+		idx.columns = append(idx.columns, strings.ToLower(row.ColumnName))
+	}
+
+	var missing []IndexSpec
+	for _, spec := range specs {
+		found := false
+		for _, idx := range indexes[spec.Table] {
+			if spec.Unique && !idx.unique {
+				continue
+			}
+			if len(idx.columns) >= len(spec.Columns) && slices.Equal(idx.columns[:len(spec.Columns)], spec.Columns) {
+				// A unique spec needs exactly its columns to be unique
+				if spec.Unique && len(idx.columns) != len(spec.Columns) {
+					continue
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, spec)
+		}
+	}
+	return missing, nil
+}
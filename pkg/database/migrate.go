@@ -0,0 +1,144 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/go-historical-data/database/migrations"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/golang-migrate/migrate/v4"
+	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// MigrationStatus describes the schema version of a database
+type MigrationStatus struct {
+	Current uint `json:"current"`
+	Latest  uint `json:"latest"`
+	Dirty   bool `json:"dirty"`
+}
+
+// Pending reports whether migrations remain to be applied
+func (s *MigrationStatus) Pending() bool {
+	return s.Current < s.Latest
+}
+
+// Migrator applies the embedded versioned migrations
+type Migrator struct {
+	m      *migrate.Migrate
+	db     *sql.DB
+	latest uint
+}
+
+// NewMigrator creates a migrator for the embedded migrations against the configured database
+func NewMigrator(cfg config.DatabaseConfig) (*Migrator, error) {
+	return NewMigratorFromFS(cfg, migrations.FS)
+}
+
+// NewMigratorFromFS creates a migrator for the migrations contained in fsys
+func NewMigratorFromFS(cfg config.DatabaseConfig, fsys fs.FS) (*Migrator, error) {
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	latest, err := latestVersion(src)
+	if err != nil {
+		return nil, err
+	}
+
+	// Migration files may contain several statements
+	db, err := sql.Open("mysql", DSN(cfg)+"&multiStatements=true")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration connection: %w", err)
+	}
+
+	driver, err := migratemysql.WithInstance(db, &migratemysql.Config{})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, cfg.Name, driver)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	return &Migrator{m: m, db: db, latest: latest}, nil
+}
+
+// Up applies all pending migrations
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return nil
+}
+
+// Down rolls back the given number of migrations
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive")
+	}
+	if err := m.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	return nil
+}
+
+// Force sets the schema version without running migrations, clearing the dirty flag
+func (m *Migrator) Force(version int) error {
+	if err := m.m.Force(version); err != nil {
+		return fmt.Errorf("failed to force version: %w", err)
+	}
+	return nil
+}
+
+// Status returns the current and latest schema versions
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	status := &MigrationStatus{Latest: m.latest}
+
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	status.Current = version
+	status.Dirty = dirty
+
+	return status, nil
+}
+
+// Close releases the migrator connections
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+	return dbErr
+}
+
+// latestVersion walks the migration source to find the highest version
+func latestVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
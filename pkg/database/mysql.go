@@ -2,6 +2,8 @@ package database
 
 import (
 	"fmt"
+	"log"
+	"os"
 	"time"
 
 	"github.com/go-historical-data/pkg/config"
@@ -12,16 +14,13 @@ import (
 
 // NewMySQLConnection creates a new MySQL connection with GORM
 func NewMySQLConnection(cfg config.DatabaseConfig, logLevel logger.LogLevel) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Name,
-	)
-
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
+	// Slow statements are reported by RegisterSlowQueryLog, without the bound
+	// values the default logger prints
+	db, err := gorm.Open(mysql.Open(DSN(cfg)), &gorm.Config{
+		Logger: logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+			LogLevel: logLevel,
+			Colorful: true,
+		}),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -46,6 +45,20 @@ func NewMySQLConnection(cfg config.DatabaseConfig, logLevel logger.LogLevel) (*g
 	return db, nil
 }
 
+// DSN builds the MySQL data source name for the configured database. Both the
+// driver and the session use UTC, so DATE columns round-trip as UTC midnight
+// of the same calendar date and timestamps are stored in UTC whatever the
+// zone of the host or of the MySQL server.
+func DSN(cfg config.DatabaseConfig) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=UTC&time_zone=%%27%%2B00%%3A00%%27",
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Name,
+	)
+}
+
 // GetLogLevel converts string to GORM log level
 func GetLogLevel(level string) logger.LogLevel {
 	switch level {
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+	"gorm.io/gorm"
+)
+
+// pingTimeout bounds a warm-up or ping round, including opening connections
+const pingTimeout = 10 * time.Second
+
+// PoolWarmer opens connections ahead of the first requests and pings the idle
+// ones periodically, so requests neither wait for connections to be
+// established nor fail on connections the server dropped (e.g. on failover)
+type PoolWarmer struct {
+	db     *sql.DB
+	cfg    config.PoolConfig
+	onPing func(alive, dropped int, err error)
+}
+
+// NewPoolWarmer creates a warmer of the pool of db. onPing, when set, is called
+// after every round with the connections that answered, those that failed and
+// the last failure.
+func NewPoolWarmer(db *gorm.DB, cfg config.PoolConfig, onPing func(alive, dropped int, err error)) (*PoolWarmer, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+	return &PoolWarmer{db: sqlDB, cfg: cfg, onPing: onPing}, nil
+}
+
+// Warm pings every idle connection and opens new ones until the pool holds
+// cfg.WarmConnections. The connections are held together, so none is pinged
+// twice; those failing the ping are discarded by the pool.
+func (w *PoolWarmer) Warm(ctx context.Context) (alive, dropped int, err error) {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	stats := w.db.Stats()
+	n := stats.Idle + max(w.cfg.WarmConnections-stats.OpenConnections, 0)
+	if n == 0 {
+		return 0, 0, nil
+	}
+
+	var mu sync.Mutex
+	var acquired, wg sync.WaitGroup
+	release := make(chan struct{})
+	for i := 0; i < n; i++ {
+		acquired.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, connErr := w.db.Conn(ctx)
+			if connErr == nil {
+				connErr = conn.PingContext(ctx)
+			}
+			acquired.Done()
+
+			mu.Lock()
+			if connErr == nil {
+				alive++
+			} else {
+				dropped++
+				err = connErr
+			}
+			mu.Unlock()
+
+			if conn != nil {
+				// Returned to the pool once every connection is held;
+				// a failed ping already marked it bad
+				<-release
+				conn.Close()
+			}
+		}()
+	}
+	acquired.Wait()
+	close(release)
+	wg.Wait()
+
+	if w.onPing != nil {
+		w.onPing(alive, dropped, err)
+	}
+	return alive, dropped, err
+}
+
+// Run pings the pool every cfg.PingInterval seconds until ctx is cancelled
+func (w *PoolWarmer) Run(ctx context.Context) {
+	if w.cfg.PingInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(w.cfg.PingInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Warm(ctx)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/go-historical-data/pkg/config"
+	"github.com/go-sql-driver/mysql"
+	"github.com/sony/gobreaker"
+	"gorm.io/gorm"
+)
+
+// MySQL error numbers treated as transient
+const (
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrDeadlock        = 1213
+)
+
+// ErrCircuitOpen is returned when the database circuit breaker rejects a call
+var ErrCircuitOpen = errors.New("database circuit breaker is open")
+
+// Resilience wraps database calls with a circuit breaker and bounded retries with jitter
+type Resilience struct {
+	breaker *gobreaker.CircuitBreaker
+	cfg     config.ResilienceConfig
+	onRetry func(reason string)
+}
+
+// NewResilience creates a resilience wrapper. onStateChange is invoked with the
+// new breaker state ("closed", "half-open", "open") on every transition, and
+// onRetry with the RetryReason of every failed attempt that is retried.
+func NewResilience(cfg config.ResilienceConfig, onStateChange func(state string), onRetry func(reason string)) *Resilience {
+	r := &Resilience{cfg: cfg, onRetry: onRetry}
+
+	if cfg.BreakerEnabled {
+		threshold := uint32(max(cfg.FailureThreshold, 1))
+		r.breaker = gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:        "mysql",
+			MaxRequests: uint32(max(cfg.HalfOpenMaxRequests, 1)),
+			Timeout:     time.Duration(cfg.OpenTimeout) * time.Second,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= threshold
+			},
+			// Only infrastructure failures count against the breaker
+			IsSuccessful: func(err error) bool {
+				return err == nil || !IsUnavailable(err)
+			},
+			OnStateChange: func(_ string, _ gobreaker.State, to gobreaker.State) {
+				if onStateChange != nil {
+					onStateChange(to.String())
+				}
+			},
+		})
+	}
+
+	return r
+}
+
+// Do executes fn through the circuit breaker, retrying transient errors
+// (deadlocks, lock wait timeouts, bad connections) with exponential backoff and full jitter.
+// The error returned is classified by ClassifyError.
+func (r *Resilience) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r == nil {
+		return ClassifyError(fn(ctx))
+	}
+
+	attempts := max(r.cfg.RetryMaxAttempts, 1)
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = r.execute(ctx, fn)
+		if err == nil || !IsTransient(err) || attempt == attempts {
+			return ClassifyError(err)
+		}
+		if r.onRetry != nil {
+			r.onRetry(RetryReason(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ClassifyError(err)
+		case <-time.After(r.backoff(attempt)):
+		}
+	}
+	return ClassifyError(err)
+}
+
+// State returns the breaker state ("closed", "half-open", "open"), or "disabled"
+func (r *Resilience) State() string {
+	if r == nil || r.breaker == nil {
+		return "disabled"
+	}
+	return r.breaker.State().String()
+}
+
+// execute runs a single attempt through the breaker
+func (r *Resilience) execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.breaker == nil {
+		return fn(ctx)
+	}
+
+	_, err := r.breaker.Execute(func() (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return fmt.Errorf("%w: %v", ErrCircuitOpen, err)
+	}
+	return err
+}
+
+// backoff returns a full-jitter delay for the given attempt
+func (r *Resilience) backoff(attempt int) time.Duration {
+	base := time.Duration(max(r.cfg.RetryBaseDelayMs, 1)) * time.Millisecond
+	ceiling := time.Duration(max(r.cfg.RetryMaxDelayMs, r.cfg.RetryBaseDelayMs, 1)) * time.Millisecond
+
+	delay := base << (attempt - 1)
+	if delay <= 0 || delay > ceiling {
+		delay = ceiling
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1) // #nosec G404 -- jitter does not need crypto randomness
+}
+
+// IsTransient reports whether an error is safe to retry: the server rolled the
+// statement back (deadlock, lock wait timeout) or it was never sent (bad
+// connection). The driver only returns driver.ErrBadConn before writing the
+// statement; mysql.ErrInvalidConn may come after the server received it, so
+// retrying could apply a write such as a counter increment twice.
+func IsTransient(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+	}
+	return errors.Is(err, driver.ErrBadConn)
+}
+
+// RetryReason names the transient error an attempt failed with: "deadlock",
+// "lock_wait_timeout" or "bad_connection"
+func RetryReason(err error) string {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if mysqlErr.Number == mysqlErrDeadlock {
+			return "deadlock"
+		}
+		return "lock_wait_timeout"
+	}
+	return "bad_connection"
+}
+
+// IsUnavailable reports whether an error indicates the database itself is failing,
+// as opposed to a query-level outcome such as a missing record or a constraint violation.
+// Cancelled and timed out requests say nothing about the database.
+func IsUnavailable(err error) bool {
+	if err == nil ||
+		errors.Is(err, gorm.ErrRecordNotFound) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		// Statement-level errors (syntax, constraints, deadlocks) mean the server is answering
+		return false
+	}
+	var sqliteErr *gosqlite.Error
+	if errors.As(err, &sqliteErr) {
+		// Likewise, unless the database file itself cannot be read or written
+		switch sqliteErr.Code() & 0xff {
+		case sqliteIOErr, sqliteCorrupt, sqliteFull, sqliteCantOpen, sqliteNotADB:
+			return true
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-historical-data/pkg/config"
+	"gorm.io/gorm"
+)
+
+// Isolation levels of config.WriteSessionConfig
+var isolationLevels = map[string]sql.IsolationLevel{
+	"":                 sql.LevelDefault,
+	"read_uncommitted": sql.LevelReadUncommitted,
+	"read_committed":   sql.LevelReadCommitted,
+	"repeatable_read":  sql.LevelRepeatableRead,
+	"serializable":     sql.LevelSerializable,
+}
+
+// WriteSession runs the transactions of bulk writes with the configured
+// isolation level and InnoDB lock wait timeout. Both are MySQL settings; on
+// SQLite the transactions keep the defaults.
+type WriteSession struct {
+	cfg config.WriteSessionConfig
+}
+
+// NewWriteSession creates a session applying cfg to every write transaction
+func NewWriteSession(cfg config.WriteSessionConfig) WriteSession {
+	return WriteSession{cfg: cfg}
+}
+
+// Transaction runs fn in a transaction of db. The lock wait timeout is set on
+// the connection of the transaction and restored to the server default before
+// the connection returns to the pool, so reads never inherit it.
+func (s WriteSession) Transaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if IsSQLite(db) {
+		return db.WithContext(ctx).Transaction(fn)
+	}
+
+	opts := &sql.TxOptions{Isolation: isolationLevels[s.cfg.IsolationLevel]}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if s.cfg.LockWaitTimeout > 0 {
+			if err := tx.Exec(fmt.Sprintf("SET SESSION innodb_lock_wait_timeout = %d", s.cfg.LockWaitTimeout)).Error; err != nil {
+				return err
+			}
+			defer tx.Exec("SET SESSION innodb_lock_wait_timeout = DEFAULT")
+		}
+		return fn(tx)
+	}, opts)
+}
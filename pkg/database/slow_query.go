@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// slowQueryStartKey holds the start time of a statement in its gorm instance
+const slowQueryStartKey = "slow_query:start"
+
+// SlowQuery describes a statement that ran longer than the slow query threshold
+type SlowQuery struct {
+	Operation string // select, insert, update, delete or raw
+	Table     string
+	SQL       string // with placeholders; bound values are never reported
+	Duration  time.Duration
+	Rows      int64 // rows returned or affected
+	Err       error
+
+	vars []interface{} // bound values, for an Explainer only
+}
+
+// RegisterSlowQueryLog reports every statement of db running longer than
+// threshold to onSlow, once it completes
+func RegisterSlowQueryLog(db *gorm.DB, threshold time.Duration, onSlow func(ctx context.Context, query SlowQuery)) error {
+	start := func(tx *gorm.DB) {
+		tx.InstanceSet(slowQueryStartKey, time.Now())
+	}
+	finish := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			value, ok := tx.InstanceGet(slowQueryStartKey)
+			if !ok {
+				return
+			}
+			duration := time.Since(value.(time.Time))
+			if duration < threshold {
+				return
+			}
+			onSlow(tx.Statement.Context, SlowQuery{
+				Operation: operation,
+				Table:     tx.Statement.Table,
+				SQL:       tx.Statement.SQL.String(),
+				Duration:  duration,
+				Rows:      tx.Statement.RowsAffected,
+				Err:       tx.Error,
+				vars:      tx.Statement.Vars,
+			})
+		}
+	}
+
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Query().Before("gorm:query").Register("slow_query:start", start),
+		callbacks.Query().After("gorm:query").Register("slow_query:finish", finish("select")),
+		callbacks.Row().Before("gorm:row").Register("slow_query:start", start),
+		callbacks.Row().After("gorm:row").Register("slow_query:finish", finish("select")),
+		callbacks.Create().Before("gorm:create").Register("slow_query:start", start),
+		callbacks.Create().After("gorm:create").Register("slow_query:finish", finish("insert")),
+		callbacks.Update().Before("gorm:update").Register("slow_query:start", start),
+		callbacks.Update().After("gorm:update").Register("slow_query:finish", finish("update")),
+		callbacks.Delete().Before("gorm:delete").Register("slow_query:start", start),
+		callbacks.Delete().After("gorm:delete").Register("slow_query:finish", finish("delete")),
+		callbacks.Raw().Before("gorm:raw").Register("slow_query:start", start),
+		callbacks.Raw().After("gorm:raw").Register("slow_query:finish", finish("raw")),
+	)
+}
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"time"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"github.com/go-historical-data/pkg/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SQLiteMemory is the sqlite_path keeping the database in memory
+const SQLiteMemory = ":memory:"
+
+// sqliteDriver is the name of the SQLite driver reading times back from
+// expressions, see timeRows
+const sqliteDriver = "sqlite-historical"
+
+// sqliteTimeFormat is the text the SQLite driver stores times as
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+func init() {
+	sql.Register(sqliteDriver, timeDriver{&gosqlite.Driver{}})
+}
+
+// NewSQLiteConnection opens the SQLite database at cfg.SQLitePath with GORM.
+// The driver is pure Go, so the API runs self-contained for demos and tests.
+// An in-memory database is shared by the connections of the pool and lives as
+// long as one of them, so the pool never closes its last idle connection.
+func NewSQLiteConnection(cfg config.DatabaseConfig, logLevel logger.LogLevel) (*gorm.DB, error) {
+	db, err := gorm.Open(&sqlite.Dialector{DriverName: sqliteDriver, DSN: SQLiteDSN(cfg)}, &gorm.Config{
+		Logger: logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+			LogLevel: logLevel,
+			Colorful: true,
+		}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	if cfg.SQLitePath == SQLiteMemory {
+		sqlDB.SetMaxIdleConns(max(cfg.MaxIdleConns, 1))
+	} else {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	}
+
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// SQLiteDSN builds the data source name of the configured SQLite database.
+// Writers wait for each other instead of failing with SQLITE_BUSY, and file
+// databases use the write-ahead log so reads don't block on writes.
+func SQLiteDSN(cfg config.DatabaseConfig) string {
+	if cfg.SQLitePath == SQLiteMemory {
+		// memdb shares the database between the connections of the process
+		return "file:/historical_data?vfs=memdb&_pragma=busy_timeout(10000)"
+	}
+	return fmt.Sprintf("file:%s?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)", cfg.SQLitePath)
+}
+
+// IsSQLite reports whether db runs on SQLite rather than MySQL
+func IsSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == "sqlite"
+}
+
+// Inserted returns the SQL referencing the value an upsert tried to insert
+// into column, for the assignments of an ON CONFLICT clause
+func Inserted(db *gorm.DB, column string) string {
+	if IsSQLite(db) {
+		return "excluded." + column
+	}
+	return fmt.Sprintf("VALUES(%s)", column)
+}
+
+// timeDriver wraps the SQLite driver so that queries read the times of
+// expressions back as time.Time. SQLite stores times as text, and the driver
+// only parses the columns declared as dates or times, which an aggregate such
+// as MIN(date) is not.
+type timeDriver struct {
+	driver.Driver
+}
+
+// Open implements driver.Driver
+func (d timeDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return timeConn{conn}, nil
+}
+
+// timeConn is a SQLite connection returning timeRows. The driver implements
+// every context interface, so they are asserted without fallbacks.
+type timeConn struct {
+	driver.Conn
+}
+
+// Ping implements driver.Pinger
+func (c timeConn) Ping(ctx context.Context) error {
+	return c.Conn.(driver.Pinger).Ping(ctx)
+}
+
+// BeginTx implements driver.ConnBeginTx
+func (c timeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+// PrepareContext implements driver.ConnPrepareContext
+func (c timeConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+}
+
+// ExecContext implements driver.ExecerContext
+func (c timeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+// QueryContext implements driver.QueryerContext
+func (c timeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return timeRows{rows}, nil
+}
+
+// timeRows parses the text values of undeclared columns that hold a time
+type timeRows struct {
+	driver.Rows
+}
+
+// Next implements driver.Rows
+func (r timeRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return err
+	}
+	for i, value := range dest {
+		if s, ok := value.(string); ok && len(s) >= len("2006-01-02 15:04:05-07:00") && r.ColumnTypeDatabaseTypeName(i) == "" {
+			if t, err := time.Parse(sqliteTimeFormat, s); err == nil {
+				dest[i] = t
+			}
+		}
+	}
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName implements driver.RowsColumnTypeDatabaseTypeName
+func (r timeRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.Rows.(driver.RowsColumnTypeDatabaseTypeName).ColumnTypeDatabaseTypeName(index)
+}
+
+// ColumnTypeScanType implements driver.RowsColumnTypeScanType
+func (r timeRows) ColumnTypeScanType(index int) reflect.Type {
+	return r.Rows.(driver.RowsColumnTypeScanType).ColumnTypeScanType(index)
+}
+
+// ColumnTypeNullable implements driver.RowsColumnTypeNullable
+func (r timeRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return r.Rows.(driver.RowsColumnTypeNullable).ColumnTypeNullable(index)
+}
+
+// ColumnTypeLength implements driver.RowsColumnTypeLength
+func (r timeRows) ColumnTypeLength(index int) (length int64, ok bool) {
+	return r.Rows.(driver.RowsColumnTypeLength).ColumnTypeLength(index)
+}
+
+// ColumnTypePrecisionScale implements driver.RowsColumnTypePrecisionScale
+func (r timeRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	return r.Rows.(driver.RowsColumnTypePrecisionScale).ColumnTypePrecisionScale(index)
+}
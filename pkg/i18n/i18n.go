@@ -0,0 +1,63 @@
+// Package i18n negotiates the language of user-facing messages. The messages
+// themselves live in catalogs next to the code producing them.
+package i18n
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Supported languages
+const (
+	English    = "en"
+	Vietnamese = "vi"
+)
+
+// Default is the language of requests naming none of the supported ones
+const Default = English
+
+// Supported lists the languages messages are translated to
+var Supported = []string{English, Vietnamese}
+
+// Negotiate returns the supported language an Accept-Language header
+// prefers, matching on the primary subtag so vi-VN selects vi. Ties go to the
+// earliest listed; a header naming no supported language selects Default.
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(entry, ";")
+		tag := strings.ToLower(strings.TrimSpace(params[0]))
+		primary, _, _ := strings.Cut(tag, "-")
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > bestQ && slices.Contains(Supported, primary) {
+			best, bestQ = primary, q
+		}
+	}
+	return best
+}
+
+type contextKey struct{}
+
+// WithLanguage returns a context carrying the language of the request
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language carried by ctx, or Default
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok {
+		return lang
+	}
+	return Default
+}
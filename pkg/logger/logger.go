@@ -82,6 +82,11 @@ func parseLogLevel(level string) zerolog.Level {
 	}
 }
 
+// SetLevel changes the level of every logger at runtime
+func SetLevel(level string) {
+	zerolog.SetGlobalLevel(parseLogLevel(level))
+}
+
 // WithContext returns a new logger with context fields
 func (l *Logger) WithContext(fields map[string]interface{}) *Logger {
 	ctx := l.Logger.With()
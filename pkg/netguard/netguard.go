@@ -0,0 +1,28 @@
+// Package netguard tells public addresses from internal ones, for outbound
+// connections to targets chosen by API clients such as alert webhooks.
+package netguard
+
+import "net/netip"
+
+// IsPublicAddr reports whether addr is a public unicast address, excluding
+// loopback, private (RFC 1918, fc00::/7), link-local (169.254.0.0/16, which
+// holds cloud metadata endpoints) and unspecified addresses
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// nonPublicPrefixes are the ranges IsGlobalUnicast admits that are not
+// reachable on the internet
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network", dialled as the local host
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT (RFC 6598)
+}
@@ -6,8 +6,10 @@ import (
 
 // ErrorResponse represents a standardized error response
 type ErrorResponse struct {
-	Success bool        `json:"success"`
-	Error   ErrorDetail `json:"error"`
+	Success   bool        `json:"success"`
+	RequestID string      `json:"request_id,omitempty"`
+	TraceID   string      `json:"trace_id,omitempty"`
+	Error     ErrorDetail `json:"error"`
 }
 
 // ErrorDetail contains error details
@@ -27,109 +29,99 @@ const (
 	ErrCodeValidation         = "VALIDATION_ERROR"
 	ErrCodeInternalServer     = "INTERNAL_SERVER_ERROR"
 	ErrCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	ErrCodeTimeout            = "TIMEOUT"
 	ErrCodeTooManyRequests    = "TOO_MANY_REQUESTS"
 	ErrCodeDatabaseError      = "DATABASE_ERROR"
 	ErrCodeCacheError         = "CACHE_ERROR"
+	ErrCodeQuotaExceeded      = "QUOTA_EXCEEDED"
+	ErrCodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	ErrCodeMalformedFile      = "MALFORMED_FILE"
+	ErrCodeUnsupportedMedia   = "UNSUPPORTED_MEDIA_TYPE"
 )
 
-// BadRequest sends a 400 Bad Request error response
-func BadRequest(c *fiber.Ctx, message string, details interface{}) error {
-	return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-		Success: false,
+// Error sends an error response with the given status and code
+func Error(c *fiber.Ctx, status int, code, message string, details interface{}) error {
+	requestID, traceID := requestIDs(c)
+	return c.Status(status).JSON(ErrorResponse{
+		Success:   false,
+		RequestID: requestID,
+		TraceID:   traceID,
 		Error: ErrorDetail{
-			Code:    ErrCodeBadRequest,
+			Code:    code,
 			Message: message,
 			Details: details,
 		},
 	})
 }
 
+// BadRequest sends a 400 Bad Request error response
+func BadRequest(c *fiber.Ctx, message string, details interface{}) error {
+	return Error(c, fiber.StatusBadRequest, ErrCodeBadRequest, message, details)
+}
+
 // Unauthorized sends a 401 Unauthorized error response
 func Unauthorized(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    ErrCodeUnauthorized,
-			Message: message,
-		},
-	})
+	return Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, message, nil)
 }
 
 // Forbidden sends a 403 Forbidden error response
 func Forbidden(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    ErrCodeForbidden,
-			Message: message,
-		},
-	})
+	return Error(c, fiber.StatusForbidden, ErrCodeForbidden, message, nil)
 }
 
 // NotFound sends a 404 Not Found error response
 func NotFound(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    ErrCodeNotFound,
-			Message: message,
-		},
-	})
+	return Error(c, fiber.StatusNotFound, ErrCodeNotFound, message, nil)
 }
 
 // Conflict sends a 409 Conflict error response
 func Conflict(c *fiber.Ctx, message string, details interface{}) error {
-	return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    ErrCodeConflict,
-			Message: message,
-			Details: details,
-		},
-	})
+	return Error(c, fiber.StatusConflict, ErrCodeConflict, message, details)
 }
 
 // ValidationError sends a 422 Unprocessable Entity error response
 func ValidationError(c *fiber.Ctx, message string, details interface{}) error {
-	return c.Status(fiber.StatusUnprocessableEntity).JSON(ErrorResponse{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    ErrCodeValidation,
-			Message: message,
-			Details: details,
-		},
-	})
+	return Error(c, fiber.StatusUnprocessableEntity, ErrCodeValidation, message, details)
+}
+
+// PayloadTooLarge sends a 413 Request Entity Too Large error response
+func PayloadTooLarge(c *fiber.Ctx, message string) error {
+	return Error(c, fiber.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, message, nil)
 }
 
 // InternalServerError sends a 500 Internal Server Error response
 func InternalServerError(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    ErrCodeInternalServer,
-			Message: message,
-		},
-	})
+	return Error(c, fiber.StatusInternalServerError, ErrCodeInternalServer, message, nil)
 }
 
 // ServiceUnavailable sends a 503 Service Unavailable error response
 func ServiceUnavailable(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusServiceUnavailable).JSON(ErrorResponse{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    ErrCodeServiceUnavailable,
-			Message: message,
-		},
-	})
+	return Error(c, fiber.StatusServiceUnavailable, ErrCodeServiceUnavailable, message, nil)
+}
+
+// GatewayTimeout sends a 504 Gateway Timeout error response for requests
+// that ran past their deadline
+func GatewayTimeout(c *fiber.Ctx, message string) error {
+	return Error(c, fiber.StatusGatewayTimeout, ErrCodeTimeout, message, nil)
 }
 
 // TooManyRequests sends a 429 Too Many Requests error response
 func TooManyRequests(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusTooManyRequests).JSON(ErrorResponse{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    ErrCodeTooManyRequests,
-			Message: message,
-		},
-	})
+	return Error(c, fiber.StatusTooManyRequests, ErrCodeTooManyRequests, message, nil)
+}
+
+// QuotaExceeded sends a 429 Too Many Requests error response for exhausted quotas
+func QuotaExceeded(c *fiber.Ctx, message string, details interface{}) error {
+	return Error(c, fiber.StatusTooManyRequests, ErrCodeQuotaExceeded, message, details)
+}
+
+// MalformedFile sends a 422 Unprocessable Entity error response for uploads
+// aborted because too many rows failed
+func MalformedFile(c *fiber.Ctx, message string, details interface{}) error {
+	return Error(c, fiber.StatusUnprocessableEntity, ErrCodeMalformedFile, message, details)
+}
+
+// UnsupportedMediaType sends a 415 Unsupported Media Type error response
+func UnsupportedMediaType(c *fiber.Ctx, message string) error {
+	return Error(c, fiber.StatusUnsupportedMediaType, ErrCodeUnsupportedMedia, message, nil)
 }
@@ -0,0 +1,70 @@
+package response
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// ContentTypeProblem is the media type of RFC 7807 problem documents
+const ContentTypeProblem = "application/problem+json"
+
+// Problem is an RFC 7807 problem document, carrying the code and details of
+// the error envelope as extension members
+type Problem struct {
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Status    int         `json:"status"`
+	Detail    string      `json:"detail,omitempty"`
+	Instance  string      `json:"instance,omitempty"`
+	Code      string      `json:"code"`
+	Details   interface{} `json:"details,omitempty"`
+	TraceID   string      `json:"trace_id,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// NewProblem builds the problem document of an error. The type is typeBase
+// followed by the code in lower kebab case, or about:blank without a base,
+// and the title is the reason phrase of the status.
+func NewProblem(typeBase string, status int, code, message string, details interface{}) Problem {
+	problemType := "about:blank"
+	if typeBase != "" {
+		problemType = typeBase + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+	}
+	return Problem{
+		Type:    problemType,
+		Title:   utils.StatusMessage(status),
+		Status:  status,
+		Detail:  message,
+		Code:    code,
+		Details: details,
+	}
+}
+
+// ProblemError sends a problem document with its status
+func ProblemError(c *fiber.Ctx, problem Problem) error {
+	return c.Status(problem.Status).JSON(problem, ContentTypeProblem)
+}
+
+// AcceptsProblem reports whether the Accept header of the request names
+// problem documents with a non-zero quality
+func AcceptsProblem(c *fiber.Ctx) bool {
+	for _, accepted := range strings.Split(c.Get(fiber.HeaderAccept), ",") {
+		params := strings.Split(accepted, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), ContentTypeProblem) {
+			continue
+		}
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
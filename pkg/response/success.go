@@ -1,19 +1,39 @@
 package response
 
 import (
+	"bytes"
+	"encoding/json"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 // SuccessResponse represents a standardized success response
 type SuccessResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
+	Success   bool        `json:"success"`
+	RequestID string      `json:"request_id,omitempty"`
+	TraceID   string      `json:"trace_id,omitempty"`
+	Message   string      `json:"message,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Respond sends body with the given status, stamped with the request and
+// trace IDs of the request
+func Respond(c *fiber.Ctx, status int, body SuccessResponse) error {
+	body.RequestID, body.TraceID = requestIDs(c)
+	return c.Status(status).JSON(body)
+}
+
+// requestIDs returns the request ID and, with tracing on, the trace ID the
+// middleware stored in the locals of the request
+func requestIDs(c *fiber.Ctx) (requestID, traceID string) {
+	requestID, _ = c.Locals("request_id").(string)
+	traceID, _ = c.Locals("trace_id").(string)
+	return requestID, traceID
 }
 
 // Success sends a success response with data
 func Success(c *fiber.Ctx, data interface{}) error {
-	return c.Status(fiber.StatusOK).JSON(SuccessResponse{
+	return Respond(c, fiber.StatusOK, SuccessResponse{
 		Success: true,
 		Data:    data,
 	})
@@ -21,7 +41,7 @@ func Success(c *fiber.Ctx, data interface{}) error {
 
 // SuccessWithMessage sends a success response with message and data
 func SuccessWithMessage(c *fiber.Ctx, message string, data interface{}) error {
-	return c.Status(fiber.StatusOK).JSON(SuccessResponse{
+	return Respond(c, fiber.StatusOK, SuccessResponse{
 		Success: true,
 		Message: message,
 		Data:    data,
@@ -30,7 +50,7 @@ func SuccessWithMessage(c *fiber.Ctx, message string, data interface{}) error {
 
 // Created sends a 201 Created response with data
 func Created(c *fiber.Ctx, data interface{}) error {
-	return c.Status(fiber.StatusCreated).JSON(SuccessResponse{
+	return Respond(c, fiber.StatusCreated, SuccessResponse{
 		Success: true,
 		Message: "Resource created successfully",
 		Data:    data,
@@ -41,3 +61,54 @@ func Created(c *fiber.Ctx, data interface{}) error {
 func NoContent(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// Restamp replaces the request and trace IDs at the head of an envelope
+// replayed from a cache with those of the current request. Bodies other than
+// envelopes are left as they are.
+func Restamp(c *fiber.Ctx) {
+	body := c.Response().Body()
+	var head string
+	for _, prefix := range []string{`{"success":true`, `{"success":false`} {
+		if bytes.HasPrefix(body, []byte(prefix)) {
+			head = prefix
+		}
+	}
+	if head == "" {
+		return
+	}
+	rest := body[len(head):]
+	for _, field := range []string{`,"request_id":`, `,"trace_id":`} {
+		if bytes.HasPrefix(rest, []byte(field)) {
+			rest = skipJSONString(rest[len(field):])
+		}
+	}
+
+	requestID, traceID := requestIDs(c)
+	var stamped bytes.Buffer
+	stamped.WriteString(head)
+	for _, id := range []struct{ field, value string }{{"request_id", requestID}, {"trace_id", traceID}} {
+		if id.value != "" {
+			value, _ := json.Marshal(id.value)
+			stamped.WriteString(`,"` + id.field + `":`)
+			stamped.Write(value)
+		}
+	}
+	stamped.Write(rest)
+	c.Response().SetBodyRaw(stamped.Bytes())
+}
+
+// skipJSONString returns what follows the JSON string at the start of b
+func skipJSONString(b []byte) []byte {
+	if len(b) == 0 || b[0] != '"' {
+		return b
+	}
+	for i := 1; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '"':
+			return b[i+1:]
+		}
+	}
+	return b
+}
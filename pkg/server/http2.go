@@ -0,0 +1,143 @@
+package server
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-historical-data/pkg/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http2"
+)
+
+// NewHTTP2Server builds a net/http server offering HTTP/2 and HTTP/1.1 to TLS
+// clients and passing every request to the Fiber app, whose fasthttp server
+// only speaks HTTP/1.1. Request and response bodies are streamed, so uploads
+// and exports are never buffered whole. bodyLimit caps request bodies like
+// fiber.Config.BodyLimit does on the HTTP/1.1 listener.
+func NewHTTP2Server(app *fiber.App, cfg config.ServerConfig, tlsCfg *tls.Config, bodyLimit int64) (*http.Server, error) {
+	srv := &http.Server{
+		Handler:           &fiberHandler{handler: app.Handler(), bodyLimit: bodyLimit},
+		TLSConfig:         tlsCfg.Clone(),
+		ReadHeaderTimeout: time.Duration(cfg.ReadTimeout) * time.Second,
+		ReadTimeout:       time.Duration(cfg.ReadTimeout) * time.Second,
+		WriteTimeout:      time.Duration(cfg.WriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(cfg.IdleTimeout) * time.Second,
+	}
+	srv.SetKeepAlivesEnabled(!cfg.DisableKeepalive)
+
+	// Adds h2 to the ALPN protocols of the TLS configuration
+	if err := http2.ConfigureServer(srv, &http2.Server{
+		MaxConcurrentStreams: uint32(cfg.HTTP2.MaxConcurrentStreams),
+	}); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// fiberHandler serves net/http requests with a fasthttp request handler
+type fiberHandler struct {
+	handler   fasthttp.RequestHandler
+	bodyLimit int64
+}
+
+// ServeHTTP implements http.Handler
+func (h *fiberHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var ctx fasthttp.RequestCtx
+	ctx.Init2(newBridgeConn(r), nil, false)
+	defer ctx.Request.Reset()
+	defer ctx.Response.Reset()
+
+	req := &ctx.Request
+	req.Header.SetMethod(r.Method)
+	req.SetRequestURI(r.RequestURI)
+	req.Header.SetHost(r.Host)
+	for key, values := range r.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		body := io.Reader(r.Body)
+		if h.bodyLimit > 0 {
+			body = http.MaxBytesReader(w, r.Body, h.bodyLimit)
+		}
+		req.SetBodyStream(body, int(r.ContentLength))
+	}
+
+	h.handler(&ctx)
+
+	resp := &ctx.Response
+	resp.Header.VisitAll(func(key, value []byte) {
+		switch string(key) {
+		// Connection-specific headers are not allowed in HTTP/2
+		case fiber.HeaderConnection, fiber.HeaderTransferEncoding, fiber.HeaderKeepAlive:
+			return
+		}
+		w.Header().Add(string(key), string(value))
+	})
+	w.WriteHeader(resp.StatusCode())
+
+	if !resp.IsBodyStream() {
+		_, _ = w.Write(resp.Body())
+		return
+	}
+	// Streamed bodies are flushed as they are produced, like on the
+	// HTTP/1.1 listener
+	_, _ = io.Copy(&flushWriter{w: w, rc: http.NewResponseController(w)}, resp.BodyStream())
+}
+
+// flushWriter flushes the response after every write
+type flushWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+// Write implements io.Writer
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, f.rc.Flush()
+}
+
+// bridgeConn gives a bridged request the addresses and TLS state of its
+// connection, so Fiber reports the client IP and the https scheme. It is never
+// read from or written to.
+type bridgeConn struct {
+	net.Conn
+	local, remote net.Addr
+	state         tls.ConnectionState
+}
+
+// newBridgeConn describes the connection of a net/http request
+func newBridgeConn(r *http.Request) *bridgeConn {
+	conn := &bridgeConn{local: &net.TCPAddr{}, remote: &net.TCPAddr{}}
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		conn.local = addr
+	}
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		conn.remote = addr
+	}
+	if r.TLS != nil {
+		conn.state = *r.TLS
+	}
+	return conn
+}
+
+// LocalAddr implements net.Conn
+func (c *bridgeConn) LocalAddr() net.Addr { return c.local }
+
+// RemoteAddr implements net.Conn
+func (c *bridgeConn) RemoteAddr() net.Addr { return c.remote }
+
+// Handshake completes the TLS handshake, already done by net/http
+func (c *bridgeConn) Handshake() error { return nil }
+
+// ConnectionState returns the TLS state of the connection, client
+// certificates included
+func (c *bridgeConn) ConnectionState() tls.ConnectionState { return c.state }
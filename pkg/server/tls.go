@@ -0,0 +1,79 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/go-historical-data/pkg/config"
+)
+
+// NewTLSConfig builds a TLS configuration from the server certificate, key and
+// optional client CA used for mutual TLS
+func NewTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	minVersion, err := parseTLSVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   minVersion,
+	}
+
+	clientAuth, err := parseClientAuth(cfg.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg.ClientAuth = clientAuth
+
+	if cfg.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificates found in client CA file %s", cfg.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+	} else if clientAuth >= tls.VerifyClientCertIfGiven {
+		return nil, fmt.Errorf("client_ca_file is required when client_auth is %q", cfg.ClientAuth)
+	}
+
+	return tlsCfg, nil
+}
+
+// parseTLSVersion converts "1.2"/"1.3" into a crypto/tls version constant
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS min_version %q (supported: 1.2, 1.3)", version)
+	}
+}
+
+// parseClientAuth converts the configured client certificate policy
+func parseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch mode {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS client_auth %q (supported: none, request, verify_if_given, require)", mode)
+	}
+}
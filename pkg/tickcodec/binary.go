@@ -0,0 +1,107 @@
+package tickcodec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Binary records are laid out big-endian as:
+//
+//	uint8   symbol length n (1-20)
+//	[n]byte symbol
+//	int64   timestamp, microseconds since the epoch
+//	int64   price × 10^8
+//	int64   size × 10^8
+//	uint8   side: 0 unknown, 1 buy, 2 sell
+//
+// A truncated record ends the stream, as the following records can't be
+// located anymore.
+
+// binaryDecoder decodes binary tick records
+type binaryDecoder struct {
+	r      *bufio.Reader
+	record int
+	done   bool
+}
+
+// NewBinaryDecoder creates a decoder of binary tick records
+func NewBinaryDecoder(r io.Reader) Decoder {
+	return &binaryDecoder{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// Decode implements Decoder
+func (d *binaryDecoder) Decode() (*Tick, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+
+	n, err := d.r.ReadByte()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	d.record++
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, int(n)+25)
+	if _, err := io.ReadFull(d.r, buf); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			d.done = true
+			return nil, &DecodeError{Record: d.record, Code: CodeMalformedRecord, Message: "truncated record"}
+		}
+		return nil, err
+	}
+
+	fields := buf[n:]
+	tick := &Tick{
+		Symbol:    strings.ToUpper(string(buf[:n])),
+		Timestamp: time.UnixMicro(int64(binary.BigEndian.Uint64(fields[0:8]))).UTC(),
+		Price:     decimal.New(int64(binary.BigEndian.Uint64(fields[8:16])), -PriceScale),
+		Size:      decimal.New(int64(binary.BigEndian.Uint64(fields[16:24])), -PriceScale),
+	}
+	switch fields[24] {
+	case 0:
+	case 1:
+		tick.Side = SideBuy
+	case 2:
+		tick.Side = SideSell
+	default:
+		return nil, &DecodeError{Record: d.record, Field: "side", Value: fmt.Sprint(fields[24]), Code: CodeInvalidSide,
+			Message: "must be 0 (unknown), 1 (buy) or 2 (sell)"}
+	}
+	if err := check(tick, d.record); err != nil {
+		return nil, err
+	}
+	return tick, nil
+}
+
+// Record implements Decoder
+func (d *binaryDecoder) Record() int {
+	return d.record
+}
+
+// AppendBinary appends the binary record of t to buf, for clients and tools
+// producing binary uploads
+func AppendBinary(buf []byte, t *Tick) []byte {
+	buf = append(buf, byte(len(t.Symbol)))
+	buf = append(buf, t.Symbol...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(t.Timestamp.UnixMicro()))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(t.Price.Shift(PriceScale).IntPart()))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(t.Size.Shift(PriceScale).IntPart()))
+	var side byte
+	switch t.Side {
+	case SideBuy:
+		side = 1
+	case SideSell:
+		side = 2
+	}
+	return append(buf, side)
+}
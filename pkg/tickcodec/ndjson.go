@@ -0,0 +1,118 @@
+package tickcodec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxLineSize bounds a single NDJSON line
+const maxLineSize = 64 * 1024
+
+// ndjsonTick is one line of NDJSON input, e.g.
+// {"symbol":"BTCUSD","ts":"2024-01-02T15:04:05.123456Z","price":"42000.5","size":"0.25","side":"buy"}.
+// ts is RFC 3339 or integer microseconds since the epoch; price and size are
+// JSON numbers or strings.
+type ndjsonTick struct {
+	Symbol string          `json:"symbol"`
+	TS     json.RawMessage `json:"ts"`
+	Price  json.RawMessage `json:"price"`
+	Size   json.RawMessage `json:"size"`
+	Side   string          `json:"side"`
+}
+
+// ndjsonDecoder decodes newline-delimited JSON ticks
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewNDJSONDecoder creates a decoder of newline-delimited JSON ticks. Blank
+// lines are skipped.
+func NewNDJSONDecoder(r io.Reader) Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+	return &ndjsonDecoder{scanner: scanner}
+}
+
+// Decode implements Decoder
+func (d *ndjsonDecoder) Decode() (*Tick, error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var raw ndjsonTick
+		if err := json.Unmarshal(line, &raw); err != nil {
+			return nil, &DecodeError{Record: d.line, Code: CodeMalformedRecord, Message: err.Error()}
+		}
+
+		tick := &Tick{
+			Symbol: strings.ToUpper(strings.TrimSpace(raw.Symbol)),
+			Side:   strings.ToLower(strings.TrimSpace(raw.Side)),
+		}
+		var err error
+		if tick.Timestamp, err = parseTimestamp(raw.TS); err != nil {
+			return nil, &DecodeError{Record: d.line, Field: "ts", Value: string(raw.TS), Code: CodeInvalidTimestamp,
+				Message: "must be an RFC 3339 timestamp or microseconds since the epoch"}
+		}
+		if tick.Price, err = parseDecimal(raw.Price); err != nil {
+			return nil, &DecodeError{Record: d.line, Field: "price", Value: string(raw.Price), Code: CodeInvalidPrice,
+				Message: "must be a valid number"}
+		}
+		if len(raw.Size) > 0 {
+			if tick.Size, err = parseDecimal(raw.Size); err != nil {
+				return nil, &DecodeError{Record: d.line, Field: "size", Value: string(raw.Size), Code: CodeInvalidSize,
+					Message: "must be a valid number"}
+			}
+		}
+		if err := check(tick, d.line); err != nil {
+			return nil, err
+		}
+		return tick, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return nil, &DecodeError{Record: d.line + 1, Code: CodeMalformedRecord, Message: "line too long"}
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Record implements Decoder
+func (d *ndjsonDecoder) Record() int {
+	return d.line
+}
+
+// parseTimestamp parses an RFC 3339 string or integer epoch microseconds
+func parseTimestamp(raw json.RawMessage) (time.Time, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.UTC().Truncate(time.Microsecond), nil
+	}
+	micros, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMicro(micros).UTC(), nil
+}
+
+// parseDecimal parses a JSON number or string
+func parseDecimal(raw json.RawMessage) (decimal.Decimal, error) {
+	var d decimal.Decimal
+	err := d.UnmarshalJSON(raw)
+	return d, err
+}
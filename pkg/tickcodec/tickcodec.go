@@ -0,0 +1,94 @@
+// Package tickcodec decodes the trade ticks accepted by the tick ingestion
+// endpoint, as newline-delimited JSON or as fixed-layout binary records.
+package tickcodec
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Content types of the supported encodings
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeBinary = "application/octet-stream"
+)
+
+// PriceScale is the number of decimal places prices and sizes are stored with
+const PriceScale = 8
+
+// MaxSymbolLength is the longest symbol a tick may carry
+const MaxSymbolLength = 20
+
+// Sides of the aggressor of a trade; empty when the feed doesn't report it
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// Tick is a decoded trade
+type Tick struct {
+	Symbol    string
+	Timestamp time.Time // UTC, microsecond precision
+	Price     decimal.Decimal
+	Size      decimal.Decimal
+	Side      string
+}
+
+// Stable codes of decode errors, for clients to act on without matching messages
+const (
+	CodeMalformedRecord  = "malformed_record"
+	CodeMissingSymbol    = "missing_symbol"
+	CodeInvalidTimestamp = "invalid_timestamp"
+	CodeInvalidPrice     = "invalid_price"
+	CodeInvalidSize      = "invalid_size"
+	CodeInvalidSide      = "invalid_side"
+)
+
+// DecodeError describes a record that could not be decoded. Record is the
+// 1-based line of NDJSON input or index of a binary record.
+type DecodeError struct {
+	Record  int
+	Field   string // empty for CodeMalformedRecord
+	Value   string
+	Code    string
+	Message string
+}
+
+func (e *DecodeError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("record %d: %s", e.Record, e.Message)
+	}
+	return fmt.Sprintf("record %d, field '%s', value '%s': %s", e.Record, e.Field, e.Value, e.Message)
+}
+
+// Decoder reads ticks one at a time. Decode returns io.EOF at the end of the
+// input and a *DecodeError for a record that is skipped; other errors end
+// the stream.
+type Decoder interface {
+	Decode() (*Tick, error)
+	// Record returns the number of the record last decoded
+	Record() int
+}
+
+// check validates the fields shared by every encoding
+func check(t *Tick, record int) error {
+	if t.Symbol == "" || len(t.Symbol) > MaxSymbolLength {
+		return &DecodeError{Record: record, Field: "symbol", Value: t.Symbol, Code: CodeMissingSymbol,
+			Message: fmt.Sprintf("symbol must have 1 to %d characters", MaxSymbolLength)}
+	}
+	if !t.Price.IsPositive() || t.Price.Exponent() < -PriceScale {
+		return &DecodeError{Record: record, Field: "price", Value: t.Price.String(), Code: CodeInvalidPrice,
+			Message: "must be a positive number with at most 8 decimal places"}
+	}
+	if t.Size.IsNegative() || t.Size.Exponent() < -PriceScale {
+		return &DecodeError{Record: record, Field: "size", Value: t.Size.String(), Code: CodeInvalidSize,
+			Message: "must be a non-negative number with at most 8 decimal places"}
+	}
+	if t.Side != "" && t.Side != SideBuy && t.Side != SideSell {
+		return &DecodeError{Record: record, Field: "side", Value: t.Side, Code: CodeInvalidSide,
+			Message: "must be buy, sell or empty"}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MaxTickerLength is the longest accepted ticker, the width of the symbol columns
+const MaxTickerLength = 20
+
+// tickerPattern matches tickers such as AAPL, BRK.B, BTC-USD, EURUSD=X and
+// ^GSPC, in either case: letters, digits and . - = ^ / after a letter, digit or ^
+var tickerPattern = regexp.MustCompile(`^[A-Za-z0-9^][A-Za-z0-9.=^/-]*$`)
+
+// ISODateLayout is the layout of ISO 8601 calendar dates
+const ISODateLayout = "2006-01-02"
+
+// Intervals lists the bar intervals, and RollupIntervals those rolled up
+// from daily bars
+var (
+	Intervals       = []string{"daily", "weekly", "monthly"}
+	RollupIntervals = []string{"weekly", "monthly"}
+)
+
+// IsTicker reports whether s is a well-formed ticker of at most MaxTickerLength characters
+func IsTicker(s string) bool {
+	return len(s) <= MaxTickerLength && tickerPattern.MatchString(s)
+}
+
+// IsISODate reports whether s is an ISO 8601 calendar date, YYYY-MM-DD
+func IsISODate(s string) bool {
+	_, err := time.Parse(ISODateLayout, s)
+	return err == nil
+}
+
+// registerCustom registers the validation tags of the domain:
+//   - ticker: a symbol, see IsTicker
+//   - isodate: a date string, see IsISODate
+//   - interval: one of Intervals
+//   - rollup_interval: one of RollupIntervals
+func registerCustom(v *validator.Validate) {
+	v.RegisterValidation("ticker", func(fl validator.FieldLevel) bool {
+		return IsTicker(fl.Field().String())
+	})
+	v.RegisterValidation("isodate", func(fl validator.FieldLevel) bool {
+		return IsISODate(fl.Field().String())
+	})
+	v.RegisterValidation("interval", func(fl validator.FieldLevel) bool {
+		return slices.Contains(Intervals, fl.Field().String())
+	})
+	v.RegisterValidation("rollup_interval", func(fl validator.FieldLevel) bool {
+		return slices.Contains(RollupIntervals, fl.Field().String())
+	})
+}
@@ -0,0 +1,121 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-historical-data/pkg/i18n"
+)
+
+// messages is the catalog of validation messages by language and key. A key
+// is a validation tag, suffixed with the kind of value for tags whose meaning
+// depends on it. Formats take the field name as %[1]s and the tag parameter
+// as %[2]s.
+var messages = map[string]map[string]string{
+	i18n.English: {
+		"failed":          "Validation failed",
+		"required":        "field '%[1]s' is required",
+		"min.string":      "field '%[1]s' must be at least %[2]s characters long",
+		"min.items":       "field '%[1]s' must contain at least %[2]s items",
+		"min.number":      "field '%[1]s' must be at least %[2]s",
+		"max.string":      "field '%[1]s' must be at most %[2]s characters long",
+		"max.items":       "field '%[1]s' must contain at most %[2]s items",
+		"max.number":      "field '%[1]s' must be at most %[2]s",
+		"len.string":      "field '%[1]s' must be exactly %[2]s characters long",
+		"len.items":       "field '%[1]s' must contain exactly %[2]s items",
+		"len.number":      "field '%[1]s' must equal %[2]s",
+		"gt":              "field '%[1]s' must be greater than %[2]s",
+		"gte":             "field '%[1]s' must be greater than or equal to %[2]s",
+		"lt":              "field '%[1]s' must be less than %[2]s",
+		"lte":             "field '%[1]s' must be less than or equal to %[2]s",
+		"oneof":           "field '%[1]s' must be one of: %[2]s",
+		"alpha":           "field '%[1]s' must contain letters only",
+		"numeric":         "field '%[1]s' must be numeric",
+		"timezone":        "field '%[1]s' must be an IANA time zone such as America/New_York",
+		"datetime":        "field '%[1]s' must be a date in the layout %[2]s",
+		"ticker":          "field '%[1]s' must be a ticker of up to 20 letters, digits or . - = ^ /",
+		"isodate":         "field '%[1]s' must be a date in the format YYYY-MM-DD",
+		"interval":        "field '%[1]s' must be one of: daily, weekly, monthly",
+		"rollup_interval": "field '%[1]s' must be one of: weekly, monthly",
+		"default":         "field '%[1]s' failed validation on '%[3]s' tag",
+	},
+	i18n.Vietnamese: {
+		"failed":          "Dữ liệu không hợp lệ",
+		"required":        "trường '%[1]s' là bắt buộc",
+		"min.string":      "trường '%[1]s' phải có ít nhất %[2]s ký tự",
+		"min.items":       "trường '%[1]s' phải có ít nhất %[2]s phần tử",
+		"min.number":      "trường '%[1]s' phải lớn hơn hoặc bằng %[2]s",
+		"max.string":      "trường '%[1]s' không được dài quá %[2]s ký tự",
+		"max.items":       "trường '%[1]s' không được có quá %[2]s phần tử",
+		"max.number":      "trường '%[1]s' phải nhỏ hơn hoặc bằng %[2]s",
+		"len.string":      "trường '%[1]s' phải có đúng %[2]s ký tự",
+		"len.items":       "trường '%[1]s' phải có đúng %[2]s phần tử",
+		"len.number":      "trường '%[1]s' phải bằng %[2]s",
+		"gt":              "trường '%[1]s' phải lớn hơn %[2]s",
+		"gte":             "trường '%[1]s' phải lớn hơn hoặc bằng %[2]s",
+		"lt":              "trường '%[1]s' phải nhỏ hơn %[2]s",
+		"lte":             "trường '%[1]s' phải nhỏ hơn hoặc bằng %[2]s",
+		"oneof":           "trường '%[1]s' phải là một trong các giá trị: %[2]s",
+		"alpha":           "trường '%[1]s' chỉ được chứa chữ cái",
+		"numeric":         "trường '%[1]s' phải là số",
+		"timezone":        "trường '%[1]s' phải là múi giờ IANA, ví dụ Asia/Ho_Chi_Minh",
+		"datetime":        "trường '%[1]s' phải là ngày theo định dạng %[2]s",
+		"ticker":          "trường '%[1]s' phải là mã chứng khoán dài tối đa 20 ký tự gồm chữ, số hoặc . - = ^ /",
+		"isodate":         "trường '%[1]s' phải là ngày theo định dạng YYYY-MM-DD",
+		"interval":        "trường '%[1]s' phải là một trong các giá trị: daily, weekly, monthly",
+		"rollup_interval": "trường '%[1]s' phải là một trong các giá trị: weekly, monthly",
+		"default":         "trường '%[1]s' không thỏa mãn quy tắc '%[3]s'",
+	},
+}
+
+// fieldError is a failed validation of one field
+type fieldError struct {
+	Field string
+	Tag   string
+	Param string
+	Kind  reflect.Kind
+}
+
+// message renders the failure in lang, falling back to English
+func (e fieldError) message(lang string) string {
+	param := e.Param
+	if e.Tag == "oneof" {
+		param = strings.Join(strings.Fields(param), ", ")
+	}
+	return fmt.Sprintf(lookup(lang, e.key()), e.Field, param, e.Tag)
+}
+
+// key returns the catalog key of the failure
+func (e fieldError) key() string {
+	switch e.Tag {
+	case "min", "max", "len":
+		switch e.Kind {
+		case reflect.String:
+			return e.Tag + ".string"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return e.Tag + ".items"
+		}
+		return e.Tag + ".number"
+	case "gt", "gte", "lt", "lte":
+		// Without a parameter, times are compared with the current time
+		if e.Param == "" {
+			return "default"
+		}
+	}
+	return e.Tag
+}
+
+// lookup returns the message of key in lang, falling back to English and to
+// the generic message of an uncatalogued tag
+func lookup(lang, key string) string {
+	for _, catalog := range []map[string]string{messages[lang], messages[i18n.English]} {
+		if format, ok := catalog[key]; ok {
+			return format
+		}
+	}
+	if format, ok := messages[lang]["default"]; ok {
+		return format
+	}
+	return messages[i18n.English]["default"]
+}
@@ -1,10 +1,12 @@
 package validator
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/go-historical-data/pkg/apperror"
+	"github.com/go-historical-data/pkg/i18n"
 )
 
 // Validator wraps the validator instance
@@ -16,8 +18,7 @@ type Validator struct {
 func New() *Validator {
 	v := validator.New()
 
-	// Register custom validators here if needed
-	// v.RegisterValidation("custom_tag", customValidationFunc)
+	registerCustom(v)
 
 	return &Validator{
 		validate: v,
@@ -27,24 +28,42 @@ func New() *Validator {
 // Validate validates a struct
 func (v *Validator) Validate(data interface{}) error {
 	if err := v.validate.Struct(data); err != nil {
-		return v.formatValidationErrors(err)
+		return v.formatValidationErrors(err, "")
 	}
 	return nil
 }
 
-// formatValidationErrors formats validation errors into a readable format
-func (v *Validator) formatValidationErrors(err error) error {
+// ValidateVar validates a single value, such as a path parameter, against
+// the tags, naming it field in the errors
+func (v *Validator) ValidateVar(field string, value interface{}, tags string) error {
+	if err := v.validate.Var(value, tags); err != nil {
+		return v.formatValidationErrors(err, field)
+	}
+	return nil
+}
+
+// formatValidationErrors formats validation errors into a readable format,
+// naming the failed fields field when it is set
+func (v *Validator) formatValidationErrors(err error, field string) error {
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		var errors []string
+		fields := make([]fieldError, 0, len(validationErrors))
 		for _, e := range validationErrors {
-			errors = append(errors, fmt.Sprintf("field '%s' failed validation on '%s' tag",
-				v.formatFieldName(e.Field()), e.Tag()))
-		}
-		return &ValidationError{
-			Errors: errors,
+			name := field
+			if name == "" {
+				name = v.formatFieldName(e.Field())
+			}
+			fields = append(fields, fieldError{
+				Field: name,
+				Tag:   e.Tag(),
+				Param: e.Param(),
+				Kind:  e.Kind(),
+			})
 		}
+		validationErr := &ValidationError{fields: fields}
+		validationErr.Errors = validationErr.messages(i18n.English)
+		return validationErr
 	}
-	return err
+	return apperror.BadRequest("Validation failed", err.Error())
 }
 
 // formatFieldName converts field name to snake_case
@@ -61,13 +80,34 @@ func (v *Validator) formatFieldName(field string) string {
 
 // ValidationError represents validation errors
 type ValidationError struct {
-	Errors []string
+	Errors []string // in English
+
+	fields []fieldError
 }
 
 func (e *ValidationError) Error() string {
 	return strings.Join(e.Errors, "; ")
 }
 
+// AppError answers the error with 422 listing the failed fields
+func (e *ValidationError) AppError() *apperror.Error {
+	return e.Localize(i18n.English)
+}
+
+// Localize answers the error with 422 listing the failed fields in lang
+func (e *ValidationError) Localize(lang string) *apperror.Error {
+	return apperror.Validation(lookup(lang, "failed"), e.messages(lang))
+}
+
+// messages renders the failed fields in lang
+func (e *ValidationError) messages(lang string) []string {
+	rendered := make([]string, 0, len(e.fields))
+	for _, field := range e.fields {
+		rendered = append(rendered, field.message(lang))
+	}
+	return rendered
+}
+
 // GetErrors returns the list of validation errors
 func (e *ValidationError) GetErrors() []string {
 	return e.Errors